@@ -0,0 +1,52 @@
+package announcement
+
+import (
+	"net/http"
+
+	"github.com/yonasadiel/helios"
+)
+
+var errAnnouncementChangeNotAuthorized = helios.ErrorAPI{
+	StatusCode: http.StatusForbidden,
+	Code:       "not_authorized_post_announcement",
+	Message:    "User is not authorized to post announcement",
+}
+
+var errVenueNotFound = helios.ErrorAPI{
+	StatusCode: http.StatusNotFound,
+	Code:       "venue_not_found",
+	Message:    "No venue with given ID",
+}
+
+var errClarificationNotAuthorized = helios.ErrorAPI{
+	StatusCode: http.StatusForbidden,
+	Code:       "not_authorized_ask_clarification",
+	Message:    "Only participant can ask for clarification",
+}
+
+var errClarificationAnswerNotAuthorized = helios.ErrorAPI{
+	StatusCode: http.StatusForbidden,
+	Code:       "not_authorized_answer_clarification",
+	Message:    "User is not authorized to answer clarification",
+}
+
+var errClarificationNotFound = helios.ErrorAPI{
+	StatusCode: http.StatusNotFound,
+	Code:       "clarification_not_found",
+	Message:    "No clarification with given ID",
+}
+
+var errClarificationAlreadyAnswered = helios.ErrorAPI{
+	StatusCode: http.StatusBadRequest,
+	Code:       "clarification_already_answered",
+	Message:    "Clarification has already been answered",
+}
+
+const (
+	// actions and target types of the changes recorded on the audit log
+	auditActionAnnouncementCreate  = "announcement.create"
+	auditActionClarificationCreate = "clarification.create"
+	auditActionClarificationAnswer = "clarification.answer"
+	auditTargetAnnouncement        = "announcement"
+	auditTargetClarification       = "clarification"
+)
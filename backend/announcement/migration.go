@@ -0,0 +1,53 @@
+package announcement
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/yonasadiel/charon/backend/migration"
+)
+
+// Migrations is the schema migrations of announcement models
+var Migrations = []migration.Migration{
+	{
+		Version: 2026101903,
+		Name:    "create announcement tables",
+		Up: func(db *gorm.DB) error {
+			return migration.CreateTables(db, initialTables)
+		},
+		Down: func(db *gorm.DB) error {
+			return migration.DropTables(db, initialTables)
+		},
+	},
+}
+
+// initialTables is the announcement tables created by the first migration. The
+// tables are kept apart from the models, so the migration doesn't change with
+// them.
+var initialTables = []migration.Table{
+	{Name: "announcements", Model: &struct {
+		ID        uint `gorm:"primary_key"`
+		EventID   uint
+		VenueID   uint
+		AuthorID  uint
+		Content   string `gorm:"type:text"`
+		CreatedAt time.Time
+		UpdatedAt time.Time
+		DeletedAt *time.Time
+	}{}},
+	{Name: "clarifications", Model: &struct {
+		ID             uint `gorm:"primary_key"`
+		EventID        uint
+		VenueID        uint
+		ParticipantID  uint
+		Question       string `gorm:"type:text"`
+		Answer         string `gorm:"type:text"`
+		AnsweredByID   uint
+		AnsweredAt     time.Time
+		AnnouncementID uint
+		CreatedAt      time.Time
+		UpdatedAt      time.Time
+		DeletedAt      *time.Time
+	}{}},
+}
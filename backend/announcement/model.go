@@ -0,0 +1,67 @@
+package announcement
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/yonasadiel/helios"
+
+	"github.com/yonasadiel/charon/backend/auth"
+	"github.com/yonasadiel/charon/backend/exam"
+)
+
+// Announcement is a message from proctor or organizer to the participants
+// of an event. VenueID is zero if the announcement is for all venues.
+type Announcement struct {
+	ID       uint `gorm:"primary_key"`
+	EventID  uint
+	VenueID  uint
+	AuthorID uint
+	Content  string `gorm:"type:text"`
+
+	Event  *exam.Event `gorm:"foreignkey:EventID;association_autoupdate:false"`
+	Author *auth.User  `gorm:"foreignkey:AuthorID;association_autoupdate:false"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+// Clarification is a question from a participant about the exam. It is only
+// visible to the participant and the proctors of the venue. AnsweredAt is zero
+// if it is not answered yet. AnnouncementID is not zero if the answer is
+// broadcasted as an announcement.
+type Clarification struct {
+	ID             uint `gorm:"primary_key"`
+	EventID        uint
+	VenueID        uint
+	ParticipantID  uint
+	Question       string `gorm:"type:text"`
+	Answer         string `gorm:"type:text"`
+	AnsweredByID   uint
+	AnsweredAt     time.Time
+	AnnouncementID uint
+
+	Event       *exam.Event `gorm:"foreignkey:EventID;association_autoupdate:false"`
+	Participant *auth.User  `gorm:"foreignkey:ParticipantID;association_autoupdate:false"`
+	AnsweredBy  *auth.User  `gorm:"foreignkey:AnsweredByID;association_autoupdate:false"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+func init() {
+	helios.App.RegisterModel(Announcement{})
+	helios.App.RegisterModel(Clarification{})
+	exam.RegisterEventDeleteHook(deleteEventAnnouncements)
+}
+
+// deleteEventAnnouncements deletes the clarifications and announcements of the
+// event that is being deleted
+func deleteEventAnnouncements(tx *gorm.DB, event exam.Event) error {
+	if err := tx.Where("event_id = ?", event.ID).Delete(Clarification{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("event_id = ?", event.ID).Delete(Announcement{}).Error
+}
@@ -0,0 +1,19 @@
+package announcement
+
+import (
+	"net/http"
+
+	"github.com/yonasadiel/charon/backend/openapi"
+)
+
+var loggedInSecurity = []string{openapi.SecuritySession}
+
+// Operations describes the views of announcement for the OpenAPI document
+var Operations = openapi.Operations{
+	"GET /exam/{eventSlug}/announcement/":                            {ID: "AnnouncementList", Summary: "List the announcements of the event", Security: loggedInSecurity, Response: []AnnouncementData{}},
+	"POST /exam/{eventSlug}/announcement/":                           {ID: "AnnouncementCreate", Summary: "Post an announcement on the event", Security: loggedInSecurity, Request: AnnouncementData{}, StatusCode: http.StatusCreated, Response: AnnouncementData{}},
+	"GET /exam/{eventSlug}/announcement/after/{announcementID}/":     {ID: "AnnouncementListAfter", Summary: "List the announcements after the announcement", Security: loggedInSecurity, Response: []AnnouncementData{}},
+	"GET /exam/{eventSlug}/clarification/":                           {ID: "ClarificationList", Summary: "List the clarifications of the event", Security: loggedInSecurity, Response: []ClarificationData{}},
+	"POST /exam/{eventSlug}/clarification/":                          {ID: "ClarificationCreate", Summary: "Ask a clarification on the event", Security: loggedInSecurity, Request: ClarificationData{}, StatusCode: http.StatusCreated, Response: ClarificationData{}},
+	"POST /exam/{eventSlug}/clarification/{clarificationID}/answer/": {ID: "ClarificationAnswer", Summary: "Answer the clarification, and broadcast it as an announcement", Security: loggedInSecurity, Request: ClarificationAnswerRequest{}, Response: ClarificationData{}},
+}
@@ -0,0 +1,122 @@
+package announcement
+
+import (
+	"time"
+
+	"github.com/yonasadiel/helios"
+)
+
+// AnnouncementData is JSON representation of announcement.
+// VenueID is zero if the announcement is for all venues.
+type AnnouncementData struct {
+	ID         uint   `json:"id"`
+	VenueID    uint   `json:"venueId"`
+	AuthorName string `json:"authorName"`
+	Content    string `json:"content"`
+	CreatedAt  string `json:"createdAt"`
+}
+
+// ClarificationData is JSON representation of clarification.
+// Answer and AnsweredAt are empty if it is not answered yet.
+type ClarificationData struct {
+	ID            uint   `json:"id"`
+	UserUsername  string `json:"userUsername"`
+	Question      string `json:"question"`
+	Answer        string `json:"answer"`
+	AnsweredAt    string `json:"answeredAt"`
+	IsBroadcasted bool   `json:"isBroadcasted"`
+}
+
+// ClarificationAnswerRequest is JSON representation of request when
+// answering a clarification. If Broadcast is true, the answer is also
+// posted as an announcement.
+type ClarificationAnswerRequest struct {
+	Answer    string `json:"answer"`
+	Broadcast bool   `json:"broadcast"`
+}
+
+// SerializeAnnouncement converts Announcement object announcement to JSON of announcement
+func SerializeAnnouncement(announcement Announcement) AnnouncementData {
+	var authorName string
+	if announcement.Author != nil {
+		authorName = announcement.Author.Name
+	}
+	announcementData := AnnouncementData{
+		ID:         announcement.ID,
+		VenueID:    announcement.VenueID,
+		AuthorName: authorName,
+		Content:    announcement.Content,
+		CreatedAt:  announcement.CreatedAt.Local().Format(time.RFC3339),
+	}
+	return announcementData
+}
+
+// DeserializeAnnouncement converts JSON of announcement to Announcement object
+func DeserializeAnnouncement(announcementData AnnouncementData, announcement *Announcement) helios.Error {
+	var err helios.ErrorForm = helios.NewErrorForm()
+	announcement.ID = announcementData.ID
+	announcement.VenueID = announcementData.VenueID
+	announcement.Content = announcementData.Content
+
+	if announcement.Content == "" {
+		err.FieldError["content"] = helios.ErrorFormFieldAtomic{"Content can't be empty"}
+	}
+
+	if err.IsError() {
+		return err
+	}
+	return nil
+}
+
+// SerializeClarification converts Clarification object clarification to JSON of clarification
+func SerializeClarification(clarification Clarification) ClarificationData {
+	var userUsername, answeredAt string
+	if clarification.Participant != nil {
+		userUsername = clarification.Participant.Username
+	}
+	if !clarification.AnsweredAt.IsZero() {
+		answeredAt = clarification.AnsweredAt.Local().Format(time.RFC3339)
+	}
+	clarificationData := ClarificationData{
+		ID:            clarification.ID,
+		UserUsername:  userUsername,
+		Question:      clarification.Question,
+		Answer:        clarification.Answer,
+		AnsweredAt:    answeredAt,
+		IsBroadcasted: clarification.AnnouncementID != 0,
+	}
+	return clarificationData
+}
+
+// DeserializeClarification converts JSON of clarification to Clarification object.
+// Only the question is taken, the answer is submitted with ClarificationAnswerRequest.
+func DeserializeClarification(clarificationData ClarificationData, clarification *Clarification) helios.Error {
+	var err helios.ErrorForm = helios.NewErrorForm()
+	clarification.ID = clarificationData.ID
+	clarification.Question = clarificationData.Question
+
+	if clarification.Question == "" {
+		err.FieldError["question"] = helios.ErrorFormFieldAtomic{"Question can't be empty"}
+	}
+
+	if err.IsError() {
+		return err
+	}
+	return nil
+}
+
+// DeserializeClarificationAnswerRequest validates the request of answering clarification
+func DeserializeClarificationAnswerRequest(clarificationAnswerRequest ClarificationAnswerRequest, answer *string, broadcast *bool) helios.Error {
+	var err helios.ErrorForm = helios.NewErrorForm()
+	*answer = clarificationAnswerRequest.Answer
+	*broadcast = clarificationAnswerRequest.Broadcast
+
+	if *answer == "" {
+		err.FieldError["answer"] = helios.ErrorFormFieldAtomic{"Answer can't be empty"}
+	}
+
+	if err.IsError() {
+		return err
+	}
+	return nil
+}
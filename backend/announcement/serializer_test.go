@@ -0,0 +1,163 @@
+package announcement
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yonasadiel/charon/backend/auth"
+	"github.com/yonasadiel/helios"
+)
+
+func TestSerializeAnnouncement(t *testing.T) {
+	var createdAt time.Time = time.Date(2020, 4, 1, 10, 0, 0, 0, time.Local)
+	var announcement Announcement = AnnouncementFactory(Announcement{
+		ID:        3,
+		VenueID:   2,
+		Content:   "announcement content",
+		Author:    &auth.User{Name: "Author Name"},
+		CreatedAt: createdAt,
+	})
+	var expectedJSON string = `{"id":3,"venueId":2,"authorName":"Author Name","content":"announcement content","createdAt":"` + createdAt.Format(time.RFC3339) + `"}`
+	var serialized AnnouncementData = SerializeAnnouncement(announcement)
+	var serializedJSON []byte
+	var errMarshalling error
+	serializedJSON, errMarshalling = json.Marshal(serialized)
+	assert.Nil(t, errMarshalling)
+	assert.Equal(t, expectedJSON, string(serializedJSON))
+}
+
+func TestDeserializeAnnouncement(t *testing.T) {
+	type deserializeAnnouncementTestCase struct {
+		announcementDataJSON string
+		expectedAnnouncement Announcement
+		expectedError        string
+	}
+	testCases := []deserializeAnnouncementTestCase{{
+		announcementDataJSON: `{"venueId":2,"content":"abc"}`,
+		expectedAnnouncement: Announcement{VenueID: 2, Content: "abc"},
+	}, {
+		announcementDataJSON: `{}`,
+		expectedError:        `{"code":"form_error","message":{"_error":[],"content":["Content can't be empty"]}}`,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test DeserializeAnnouncement testcase: %d", i)
+		var announcement Announcement
+		var announcementData AnnouncementData
+		var errUnmarshalling error
+		var errDeserialization helios.Error
+		errUnmarshalling = json.Unmarshal([]byte(testCase.announcementDataJSON), &announcementData)
+		errDeserialization = DeserializeAnnouncement(announcementData, &announcement)
+		assert.Nil(t, errUnmarshalling)
+		if testCase.expectedError == "" {
+			assert.Nil(t, errDeserialization)
+			assert.Equal(t, testCase.expectedAnnouncement.VenueID, announcement.VenueID)
+			assert.Equal(t, testCase.expectedAnnouncement.Content, announcement.Content)
+		} else if assert.NotNil(t, errDeserialization) {
+			errJSON, _ := json.Marshal(errDeserialization.GetMessage())
+			assert.Equal(t, testCase.expectedError, string(errJSON))
+		}
+	}
+}
+
+func TestSerializeClarification(t *testing.T) {
+	var answeredAt time.Time = time.Date(2020, 4, 1, 10, 0, 0, 0, time.Local)
+	type serializeClarificationTestCase struct {
+		clarification Clarification
+		expectedJSON  string
+	}
+	testCases := []serializeClarificationTestCase{{
+		clarification: ClarificationFactory(Clarification{
+			ID:          4,
+			Question:    "question",
+			Participant: &auth.User{Username: "participant"},
+		}),
+		expectedJSON: `{"id":4,"userUsername":"participant","question":"question","answer":"","answeredAt":"","isBroadcasted":false}`,
+	}, {
+		clarification: ClarificationFactory(Clarification{
+			ID:             5,
+			Question:       "question",
+			Answer:         "answer",
+			AnsweredAt:     answeredAt,
+			AnnouncementID: 3,
+			Participant:    &auth.User{Username: "participant"},
+		}),
+		expectedJSON: `{"id":5,"userUsername":"participant","question":"question","answer":"answer","answeredAt":"` + answeredAt.Format(time.RFC3339) + `","isBroadcasted":true}`,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test SerializeClarification testcase: %d", i)
+		serializedJSON, errMarshalling := json.Marshal(SerializeClarification(testCase.clarification))
+		assert.Nil(t, errMarshalling)
+		assert.Equal(t, testCase.expectedJSON, string(serializedJSON))
+	}
+}
+
+func TestDeserializeClarification(t *testing.T) {
+	type deserializeClarificationTestCase struct {
+		clarificationDataJSON string
+		expectedQuestion      string
+		expectedError         string
+	}
+	testCases := []deserializeClarificationTestCase{{
+		clarificationDataJSON: `{"question":"abc","answer":"def"}`,
+		expectedQuestion:      "abc",
+	}, {
+		clarificationDataJSON: `{}`,
+		expectedError:         `{"code":"form_error","message":{"_error":[],"question":["Question can't be empty"]}}`,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test DeserializeClarification testcase: %d", i)
+		var clarification Clarification
+		var clarificationData ClarificationData
+		var errUnmarshalling error
+		var errDeserialization helios.Error
+		errUnmarshalling = json.Unmarshal([]byte(testCase.clarificationDataJSON), &clarificationData)
+		errDeserialization = DeserializeClarification(clarificationData, &clarification)
+		assert.Nil(t, errUnmarshalling)
+		if testCase.expectedError == "" {
+			assert.Nil(t, errDeserialization)
+			assert.Equal(t, testCase.expectedQuestion, clarification.Question)
+			assert.Equal(t, "", clarification.Answer)
+		} else if assert.NotNil(t, errDeserialization) {
+			errJSON, _ := json.Marshal(errDeserialization.GetMessage())
+			assert.Equal(t, testCase.expectedError, string(errJSON))
+		}
+	}
+}
+
+func TestDeserializeClarificationAnswerRequest(t *testing.T) {
+	type deserializeClarificationAnswerRequestTestCase struct {
+		requestJSON       string
+		expectedAnswer    string
+		expectedBroadcast bool
+		expectedError     string
+	}
+	testCases := []deserializeClarificationAnswerRequestTestCase{{
+		requestJSON:       `{"answer":"abc","broadcast":true}`,
+		expectedAnswer:    "abc",
+		expectedBroadcast: true,
+	}, {
+		requestJSON:   `{"broadcast":true}`,
+		expectedError: `{"code":"form_error","message":{"_error":[],"answer":["Answer can't be empty"]}}`,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test DeserializeClarificationAnswerRequest testcase: %d", i)
+		var answer string
+		var broadcast bool
+		var clarificationAnswerRequest ClarificationAnswerRequest
+		var errUnmarshalling error
+		var errDeserialization helios.Error
+		errUnmarshalling = json.Unmarshal([]byte(testCase.requestJSON), &clarificationAnswerRequest)
+		errDeserialization = DeserializeClarificationAnswerRequest(clarificationAnswerRequest, &answer, &broadcast)
+		assert.Nil(t, errUnmarshalling)
+		if testCase.expectedError == "" {
+			assert.Nil(t, errDeserialization)
+			assert.Equal(t, testCase.expectedAnswer, answer)
+			assert.Equal(t, testCase.expectedBroadcast, broadcast)
+		} else if assert.NotNil(t, errDeserialization) {
+			errJSON, _ := json.Marshal(errDeserialization.GetMessage())
+			assert.Equal(t, testCase.expectedError, string(errJSON))
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package announcement
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yonasadiel/helios"
+
+	"github.com/yonasadiel/charon/backend/auth"
+	"github.com/yonasadiel/charon/backend/exam"
+	"github.com/yonasadiel/charon/backend/logging"
+)
+
+// getVenueIDOfUser returns the venue id of user's participation on the event.
+// It returns zero if the user doesn't participate on the event.
+func getVenueIDOfUser(user auth.User, event exam.Event) (uint, helios.Error) {
+	var participation exam.Participation
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("user_id = ?", user.ID).Where("event_id = ?", event.ID).First(&participation)); errDB != nil {
+		return 0, errDB
+	}
+	return participation.VenueID, nil
+}
+
+// containsVenueID returns true if venueID is one of venueIDs
+func containsVenueID(venueIDs []uint, venueID uint) bool {
+	for _, id := range venueIDs {
+		if id == venueID {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAllAnnouncementOfUserAndEvent returns the announcements of the event with
+// id greater than afterID, so the client can fetch only the new announcements.
+// User permitted to post on all venues sees all announcements, while the others
+// only see the announcements for all venues, for the venues they are permitted
+// to post on, and for their own venue.
+func GetAllAnnouncementOfUserAndEvent(user auth.User, eventSlug string, afterID uint) ([]Announcement, helios.Error) {
+	var event exam.Event
+	var announcements []Announcement
+	var errGetEvent helios.Error
+	event, errGetEvent = exam.GetEventOfUser(user, eventSlug)
+	if errGetEvent != nil {
+		return nil, errGetEvent
+	}
+
+	query := helios.DB.
+		Preload("Author").
+		Where("event_id = ?", event.ID).
+		Where("id > ?", afterID)
+	var allVenues bool
+	var venueIDs []uint
+	allVenues, venueIDs = auth.GetVenueIDsOfPermission(user, auth.ActionAnnouncementPost, event.ID)
+	if !allVenues {
+		venueID, errVenue := getVenueIDOfUser(user, event)
+		if errVenue != nil {
+			return nil, errVenue
+		}
+		venueIDs = append(venueIDs, venueID)
+		query = query.Where("venue_id = 0 or venue_id in (?)", venueIDs)
+	}
+	if errDB := logging.CheckDB(user.RequestID, query.Order("id asc").Find(&announcements)); errDB != nil {
+		return nil, errDB
+	}
+	return announcements, nil
+}
+
+// CreateAnnouncement posts an announcement to the event. User permitted to post
+// on all venues can post to all venues or to a specific venue, while the others
+// can only post to the venues they are permitted to.
+func CreateAnnouncement(user auth.User, eventSlug string, announcement *Announcement) helios.Error {
+	var event exam.Event
+	var errGetEvent helios.Error
+	event, errGetEvent = exam.GetEventOfUser(user, eventSlug)
+	if errGetEvent != nil {
+		return errGetEvent
+	}
+
+	var allVenues bool
+	var venueIDs []uint
+	allVenues, venueIDs = auth.GetVenueIDsOfPermission(user, auth.ActionAnnouncementPost, event.ID)
+	if !allVenues && !containsVenueID(venueIDs, announcement.VenueID) {
+		return errAnnouncementChangeNotAuthorized
+	}
+
+	if allVenues && announcement.VenueID != 0 {
+		var venue exam.Venue
+		if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("id = ?", announcement.VenueID).First(&venue)); errDB != nil {
+			return errDB
+		}
+		if venue.ID == 0 {
+			return errVenueNotFound
+		}
+	}
+
+	announcement.ID = 0
+	announcement.EventID = event.ID
+	announcement.AuthorID = user.ID
+	tx := helios.DB.Begin()
+	errDB := logging.CheckDB(user.RequestID, tx.Create(announcement))
+	if errDB == nil {
+		errDB = auth.RecordAuditLog(tx, user, auditActionAnnouncementCreate, auditTargetAnnouncement, announcement.ID, nil, *announcement)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	if errDB = logging.CheckDB(user.RequestID, tx.Commit()); errDB != nil {
+		return errDB
+	}
+	announcement.Event = &event
+	announcement.Author = &user
+	return nil
+}
+
+// GetAllClarificationOfUserAndEvent returns the clarifications of the event
+// from the venues that the user is permitted to answer. User without the
+// permission only sees their own clarifications.
+func GetAllClarificationOfUserAndEvent(user auth.User, eventSlug string) ([]Clarification, helios.Error) {
+	var event exam.Event
+	var clarifications []Clarification
+	var errGetEvent helios.Error
+	event, errGetEvent = exam.GetEventOfUser(user, eventSlug)
+	if errGetEvent != nil {
+		return nil, errGetEvent
+	}
+
+	query := helios.DB.
+		Preload("Participant").
+		Where("event_id = ?", event.ID)
+	var allVenues bool
+	var venueIDs []uint
+	allVenues, venueIDs = auth.GetVenueIDsOfPermission(user, auth.ActionClarificationAnswer, event.ID)
+	if !allVenues && len(venueIDs) == 0 {
+		query = query.Where("participant_id = ?", user.ID)
+	} else if !allVenues {
+		query = query.Where("venue_id in (?)", venueIDs)
+	}
+	if errDB := logging.CheckDB(user.RequestID, query.Order("id asc").Find(&clarifications)); errDB != nil {
+		return nil, errDB
+	}
+	return clarifications, nil
+}
+
+// CreateClarification submits a clarification request from participant.
+func CreateClarification(user auth.User, eventSlug string, clarification *Clarification) helios.Error {
+	if !auth.Can(user, auth.ActionClarificationSubmit, auth.Resource{}) {
+		return errClarificationNotAuthorized
+	}
+
+	var event exam.Event
+	var errGetEvent helios.Error
+	event, errGetEvent = exam.GetEventOfUser(user, eventSlug)
+	if errGetEvent != nil {
+		return errGetEvent
+	}
+
+	var venueID uint
+	var errVenue helios.Error
+	if venueID, errVenue = getVenueIDOfUser(user, event); errVenue != nil {
+		return errVenue
+	}
+	clarification.ID = 0
+	clarification.EventID = event.ID
+	clarification.VenueID = venueID
+	clarification.ParticipantID = user.ID
+	clarification.Answer = ""
+	clarification.AnsweredByID = 0
+	clarification.AnsweredAt = time.Time{}
+	clarification.AnnouncementID = 0
+	tx := helios.DB.Begin()
+	errDB := logging.CheckDB(user.RequestID, tx.Create(clarification))
+	if errDB == nil {
+		errDB = auth.RecordAuditLog(tx, user, auditActionClarificationCreate, auditTargetClarification, clarification.ID, nil, *clarification)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	if errDB = logging.CheckDB(user.RequestID, tx.Commit()); errDB != nil {
+		return errDB
+	}
+	clarification.Participant = &user
+	return nil
+}
+
+// AnswerClarification answers the clarification with given id. User can only
+// answer the clarifications from the venues they are permitted to. If broadcast
+// is true, the question and the answer are also posted as an announcement to
+// all venues, or only to the venue of the clarification if the user is not
+// permitted on all venues.
+func AnswerClarification(user auth.User, eventSlug string, clarificationID uint, answer string, broadcast bool) (*Clarification, helios.Error) {
+	var event exam.Event
+	var clarification Clarification
+	var errGetEvent helios.Error
+	event, errGetEvent = exam.GetEventOfUser(user, eventSlug)
+	if errGetEvent != nil {
+		return nil, errGetEvent
+	}
+
+	var allVenues bool
+	var venueIDs []uint
+	allVenues, venueIDs = auth.GetVenueIDsOfPermission(user, auth.ActionClarificationAnswer, event.ID)
+	if !allVenues && len(venueIDs) == 0 {
+		return nil, errClarificationAnswerNotAuthorized
+	}
+
+	query := helios.DB.
+		Preload("Participant").
+		Where("id = ?", clarificationID).
+		Where("event_id = ?", event.ID)
+	if !allVenues {
+		query = query.Where("venue_id in (?)", venueIDs)
+	}
+	if errDB := logging.CheckDB(user.RequestID, query.First(&clarification)); errDB != nil {
+		return nil, errDB
+	}
+	if clarification.ID == 0 {
+		return nil, errClarificationNotFound
+	}
+	if !clarification.AnsweredAt.IsZero() {
+		return nil, errClarificationAlreadyAnswered
+	}
+
+	tx := helios.DB.Begin()
+	var errDB helios.Error
+	if broadcast {
+		var announcement Announcement = Announcement{
+			EventID:  event.ID,
+			AuthorID: user.ID,
+			Content:  fmt.Sprintf("%s\n\n%s", clarification.Question, answer),
+		}
+		if !allVenues {
+			announcement.VenueID = clarification.VenueID
+		}
+		errDB = logging.CheckDB(user.RequestID, tx.Create(&announcement))
+		if errDB == nil {
+			errDB = auth.RecordAuditLog(tx, user, auditActionAnnouncementCreate, auditTargetAnnouncement, announcement.ID, nil, announcement)
+		}
+		clarification.AnnouncementID = announcement.ID
+	}
+	clarification.Answer = answer
+	clarification.AnsweredByID = user.ID
+	clarification.AnsweredAt = time.Now()
+	if errDB == nil {
+		errDB = logging.CheckDB(user.RequestID, tx.Model(&clarification).Updates(map[string]interface{}{
+			"answer":          clarification.Answer,
+			"answered_by_id":  clarification.AnsweredByID,
+			"answered_at":     clarification.AnsweredAt,
+			"announcement_id": clarification.AnnouncementID,
+		}))
+	}
+	if errDB == nil {
+		errDB = auth.RecordAuditLog(tx, user, auditActionClarificationAnswer, auditTargetClarification, clarification.ID, nil, map[string]interface{}{
+			"Answer":         clarification.Answer,
+			"AnnouncementID": clarification.AnnouncementID,
+		})
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return nil, errDB
+	}
+	if errDB = logging.CheckDB(user.RequestID, tx.Commit()); errDB != nil {
+		return nil, errDB
+	}
+	clarification.AnsweredBy = &user
+	return &clarification, nil
+}
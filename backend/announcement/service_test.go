@@ -0,0 +1,349 @@
+package announcement
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yonasadiel/charon/backend/auth"
+	"github.com/yonasadiel/charon/backend/exam"
+	"github.com/yonasadiel/helios"
+)
+
+func TestGetAllAnnouncementOfUserAndEvent(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var event exam.Event = exam.EventFactorySaved(exam.Event{})
+	var venue1 exam.Venue = exam.VenueFactorySaved(exam.Venue{})
+	var venue2 exam.Venue = exam.VenueFactorySaved(exam.Venue{})
+	var participation1 exam.Participation = exam.ParticipationFactorySaved(exam.Participation{Event: &event, Venue: &venue1})
+	var local2 auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	exam.ParticipationFactorySaved(exam.Participation{Event: &event, Venue: &venue2, User: &local2})
+	var organizer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	exam.EventRoleFactorySaved(organizer, event, nil, auth.EventRoleAuthor)
+	var announcementAll Announcement = AnnouncementFactorySaved(Announcement{Event: &event})
+	AnnouncementFactorySaved(Announcement{Event: &event, VenueID: venue1.ID})
+	AnnouncementFactorySaved(Announcement{Event: &event, VenueID: venue2.ID})
+	AnnouncementFactorySaved(Announcement{})
+
+	type getAllAnnouncementOfUserAndEventTestCase struct {
+		user                       auth.User
+		eventSlug                  string
+		afterID                    uint
+		expectedStatusCode         int
+		expectedAnnouncementsCount int
+	}
+	testCases := []getAllAnnouncementOfUserAndEventTestCase{{
+		user:                       organizer,
+		eventSlug:                  event.Slug,
+		expectedAnnouncementsCount: 3,
+	}, {
+		user:                       organizer,
+		eventSlug:                  event.Slug,
+		afterID:                    announcementAll.ID,
+		expectedAnnouncementsCount: 2,
+	}, {
+		user:                       *participation1.User,
+		eventSlug:                  event.Slug,
+		expectedAnnouncementsCount: 2,
+	}, {
+		user:                       local2,
+		eventSlug:                  event.Slug,
+		expectedAnnouncementsCount: 2,
+	}, {
+		user:                       *participation1.User,
+		eventSlug:                  "def",
+		expectedStatusCode:         http.StatusNotFound,
+		expectedAnnouncementsCount: 0,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test GetAllAnnouncementOfUserAndEvent testcase: %d", i)
+		announcements, err := GetAllAnnouncementOfUserAndEvent(testCase.user, testCase.eventSlug, testCase.afterID)
+		if testCase.expectedStatusCode == 0 {
+			assert.Nil(t, err)
+		} else if assert.NotNil(t, err) {
+			assert.Equal(t, testCase.expectedStatusCode, err.GetStatusCode())
+		}
+		assert.Equal(t, testCase.expectedAnnouncementsCount, len(announcements))
+	}
+}
+
+func TestCreateAnnouncement(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var event exam.Event = exam.EventFactorySaved(exam.Event{})
+	var venue1 exam.Venue = exam.VenueFactorySaved(exam.Venue{})
+	var venue2 exam.Venue = exam.VenueFactorySaved(exam.Venue{})
+	var participation exam.Participation = exam.ParticipationFactorySaved(exam.Participation{Event: &event, Venue: &venue1})
+	var local auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	exam.ParticipationFactorySaved(exam.Participation{Event: &event, Venue: &venue1, User: &local})
+	var organizer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	exam.EventRoleFactorySaved(organizer, event, nil, auth.EventRoleAuthor)
+
+	type createAnnouncementTestCase struct {
+		user            auth.User
+		eventSlug       string
+		announcement    Announcement
+		expectedError   helios.Error
+		expectedVenueID uint
+	}
+	testCases := []createAnnouncementTestCase{{
+		user:            organizer,
+		eventSlug:       event.Slug,
+		announcement:    Announcement{Content: "abc"},
+		expectedVenueID: 0,
+	}, {
+		user:            organizer,
+		eventSlug:       event.Slug,
+		announcement:    Announcement{Content: "abc", VenueID: venue2.ID},
+		expectedVenueID: venue2.ID,
+	}, {
+		user:          organizer,
+		eventSlug:     event.Slug,
+		announcement:  Announcement{Content: "abc", VenueID: venue2.ID + 100},
+		expectedError: errVenueNotFound,
+	}, {
+		user:            local,
+		eventSlug:       event.Slug,
+		announcement:    Announcement{Content: "abc", VenueID: venue1.ID},
+		expectedVenueID: venue1.ID,
+	}, {
+		user:          local,
+		eventSlug:     event.Slug,
+		announcement:  Announcement{Content: "abc", VenueID: venue2.ID},
+		expectedError: errAnnouncementChangeNotAuthorized,
+	}, {
+		user:          local,
+		eventSlug:     event.Slug,
+		announcement:  Announcement{Content: "abc"},
+		expectedError: errAnnouncementChangeNotAuthorized,
+	}, {
+		user:          *participation.User,
+		eventSlug:     event.Slug,
+		announcement:  Announcement{Content: "abc"},
+		expectedError: errAnnouncementChangeNotAuthorized,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test CreateAnnouncement testcase: %d", i)
+		err := CreateAnnouncement(testCase.user, testCase.eventSlug, &testCase.announcement)
+		assert.Equal(t, testCase.expectedError, err)
+		if testCase.expectedError == nil {
+			var announcementSaved Announcement
+			helios.DB.Where("id = ?", testCase.announcement.ID).First(&announcementSaved)
+			assert.NotEqual(t, uint(0), announcementSaved.ID)
+			assert.Equal(t, event.ID, announcementSaved.EventID)
+			assert.Equal(t, testCase.user.ID, announcementSaved.AuthorID)
+			assert.Equal(t, testCase.expectedVenueID, announcementSaved.VenueID)
+			assertAuditLogRecorded(t, testCase.user, auditActionAnnouncementCreate, announcementSaved.ID)
+		}
+	}
+}
+
+func TestGetAllClarificationOfUserAndEvent(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var event exam.Event = exam.EventFactorySaved(exam.Event{})
+	var venue1 exam.Venue = exam.VenueFactorySaved(exam.Venue{})
+	var venue2 exam.Venue = exam.VenueFactorySaved(exam.Venue{})
+	var participation1 exam.Participation = exam.ParticipationFactorySaved(exam.Participation{Event: &event, Venue: &venue1})
+	var participation2 exam.Participation = exam.ParticipationFactorySaved(exam.Participation{Event: &event, Venue: &venue2})
+	var local1 auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	exam.ParticipationFactorySaved(exam.Participation{Event: &event, Venue: &venue1, User: &local1})
+	var organizer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	exam.EventRoleFactorySaved(organizer, event, nil, auth.EventRoleAuthor)
+	ClarificationFactorySaved(Clarification{Event: &event, VenueID: venue1.ID, Participant: participation1.User})
+	ClarificationFactorySaved(Clarification{Event: &event, VenueID: venue1.ID, Participant: participation1.User})
+	ClarificationFactorySaved(Clarification{Event: &event, VenueID: venue2.ID, Participant: participation2.User})
+
+	type getAllClarificationOfUserAndEventTestCase struct {
+		user                        auth.User
+		eventSlug                   string
+		expectedStatusCode          int
+		expectedClarificationsCount int
+	}
+	testCases := []getAllClarificationOfUserAndEventTestCase{{
+		user:                        organizer,
+		eventSlug:                   event.Slug,
+		expectedClarificationsCount: 3,
+	}, {
+		user:                        local1,
+		eventSlug:                   event.Slug,
+		expectedClarificationsCount: 2,
+	}, {
+		user:                        *participation2.User,
+		eventSlug:                   event.Slug,
+		expectedClarificationsCount: 1,
+	}, {
+		user:                        *participation2.User,
+		eventSlug:                   "def",
+		expectedStatusCode:          http.StatusNotFound,
+		expectedClarificationsCount: 0,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test GetAllClarificationOfUserAndEvent testcase: %d", i)
+		clarifications, err := GetAllClarificationOfUserAndEvent(testCase.user, testCase.eventSlug)
+		if testCase.expectedStatusCode == 0 {
+			assert.Nil(t, err)
+		} else if assert.NotNil(t, err) {
+			assert.Equal(t, testCase.expectedStatusCode, err.GetStatusCode())
+		}
+		assert.Equal(t, testCase.expectedClarificationsCount, len(clarifications))
+	}
+}
+
+func TestCreateClarification(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var event exam.Event = exam.EventFactorySaved(exam.Event{})
+	var venue exam.Venue = exam.VenueFactorySaved(exam.Venue{})
+	var participation exam.Participation = exam.ParticipationFactorySaved(exam.Participation{Event: &event, Venue: &venue})
+	var local auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	exam.ParticipationFactorySaved(exam.Participation{Event: &event, Venue: &venue, User: &local})
+
+	type createClarificationTestCase struct {
+		user          auth.User
+		eventSlug     string
+		clarification Clarification
+		expectedError helios.Error
+	}
+	testCases := []createClarificationTestCase{{
+		user:          *participation.User,
+		eventSlug:     event.Slug,
+		clarification: Clarification{Question: "abc", Answer: "def", AnnouncementID: 10},
+	}, {
+		user:          local,
+		eventSlug:     event.Slug,
+		clarification: Clarification{Question: "abc"},
+		expectedError: errClarificationNotAuthorized,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test CreateClarification testcase: %d", i)
+		err := CreateClarification(testCase.user, testCase.eventSlug, &testCase.clarification)
+		assert.Equal(t, testCase.expectedError, err)
+		if testCase.expectedError == nil {
+			var clarificationSaved Clarification
+			helios.DB.Where("id = ?", testCase.clarification.ID).First(&clarificationSaved)
+			assert.Equal(t, event.ID, clarificationSaved.EventID)
+			assert.Equal(t, venue.ID, clarificationSaved.VenueID)
+			assert.Equal(t, testCase.user.ID, clarificationSaved.ParticipantID)
+			assert.Equal(t, "", clarificationSaved.Answer)
+			assert.Equal(t, uint(0), clarificationSaved.AnnouncementID)
+			assertAuditLogRecorded(t, testCase.user, auditActionClarificationCreate, clarificationSaved.ID)
+		}
+	}
+}
+
+func TestAnswerClarification(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var event exam.Event = exam.EventFactorySaved(exam.Event{})
+	var venue1 exam.Venue = exam.VenueFactorySaved(exam.Venue{})
+	var venue2 exam.Venue = exam.VenueFactorySaved(exam.Venue{})
+	var participation1 exam.Participation = exam.ParticipationFactorySaved(exam.Participation{Event: &event, Venue: &venue1})
+	var participation2 exam.Participation = exam.ParticipationFactorySaved(exam.Participation{Event: &event, Venue: &venue2})
+	var local1 auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	exam.ParticipationFactorySaved(exam.Participation{Event: &event, Venue: &venue1, User: &local1})
+	var organizer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	exam.EventRoleFactorySaved(organizer, event, nil, auth.EventRoleAuthor)
+	var clarification1 Clarification = ClarificationFactorySaved(Clarification{Event: &event, VenueID: venue1.ID, Participant: participation1.User})
+	var clarification2 Clarification = ClarificationFactorySaved(Clarification{Event: &event, VenueID: venue2.ID, Participant: participation2.User})
+	var clarification3 Clarification = ClarificationFactorySaved(Clarification{Event: &event, VenueID: venue2.ID, Participant: participation2.User})
+
+	type answerClarificationTestCase struct {
+		user                        auth.User
+		clarificationID             uint
+		broadcast                   bool
+		expectedError               helios.Error
+		expectedAnnouncementVenueID uint
+	}
+	testCases := []answerClarificationTestCase{{
+		user:            *participation1.User,
+		clarificationID: clarification1.ID,
+		expectedError:   errClarificationAnswerNotAuthorized,
+	}, {
+		user:            local1,
+		clarificationID: clarification2.ID,
+		expectedError:   errClarificationNotFound,
+	}, {
+		user:                        local1,
+		clarificationID:             clarification1.ID,
+		broadcast:                   true,
+		expectedAnnouncementVenueID: venue1.ID,
+	}, {
+		user:            organizer,
+		clarificationID: clarification1.ID,
+		expectedError:   errClarificationAlreadyAnswered,
+	}, {
+		user:            organizer,
+		clarificationID: clarification2.ID,
+	}, {
+		user:                        organizer,
+		clarificationID:             clarification3.ID,
+		broadcast:                   true,
+		expectedAnnouncementVenueID: 0,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test AnswerClarification testcase: %d", i)
+		clarification, err := AnswerClarification(testCase.user, event.Slug, testCase.clarificationID, "the answer", testCase.broadcast)
+		assert.Equal(t, testCase.expectedError, err)
+		if testCase.expectedError == nil {
+			var clarificationSaved Clarification
+			helios.DB.Where("id = ?", testCase.clarificationID).First(&clarificationSaved)
+			assert.Equal(t, "the answer", clarificationSaved.Answer)
+			assert.Equal(t, testCase.user.ID, clarificationSaved.AnsweredByID)
+			assert.False(t, clarificationSaved.AnsweredAt.IsZero())
+			assert.Equal(t, clarification.AnnouncementID, clarificationSaved.AnnouncementID)
+			assertAuditLogRecorded(t, testCase.user, auditActionClarificationAnswer, clarificationSaved.ID)
+			if testCase.broadcast {
+				var announcement Announcement
+				helios.DB.Where("id = ?", clarificationSaved.AnnouncementID).First(&announcement)
+				assert.NotEqual(t, uint(0), announcement.ID)
+				assert.Equal(t, testCase.expectedAnnouncementVenueID, announcement.VenueID)
+				assertAuditLogRecorded(t, testCase.user, auditActionAnnouncementCreate, announcement.ID)
+			} else {
+				assert.Equal(t, uint(0), clarificationSaved.AnnouncementID)
+			}
+		}
+	}
+}
+
+func TestDeleteEventAnnouncements(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var event1 exam.Event = exam.EventFactorySaved(exam.Event{})
+	var event2 exam.Event = exam.EventFactorySaved(exam.Event{})
+	helios.DB.Model(&event1).Update("decrypted_at", time.Time{})
+	var organizer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	exam.EventRoleFactorySaved(organizer, event1, nil, auth.EventRoleAuthor)
+	for _, event := range []exam.Event{event1, event2} {
+		var eventOfData exam.Event = event
+		AnnouncementFactorySaved(Announcement{Event: &eventOfData})
+		ClarificationFactorySaved(Clarification{Event: &eventOfData})
+	}
+
+	_, err := exam.DeleteEvent(organizer, event1.Slug, false)
+	assert.Nil(t, err)
+	for _, event := range []exam.Event{event1, event2} {
+		var announcementCount, clarificationCount int
+		helios.DB.Model(&Announcement{}).Where("event_id = ?", event.ID).Count(&announcementCount)
+		helios.DB.Model(&Clarification{}).Where("event_id = ?", event.ID).Count(&clarificationCount)
+		if event.ID == event1.ID {
+			assert.Equal(t, 0, announcementCount, "Announcements of deleted event should be deleted")
+			assert.Equal(t, 0, clarificationCount, "Clarifications of deleted event should be deleted")
+		} else {
+			assert.Equal(t, 1, announcementCount, "Announcements of other event should not be deleted")
+			assert.Equal(t, 1, clarificationCount, "Clarifications of other event should not be deleted")
+		}
+	}
+}
+
+func assertAuditLogRecorded(t *testing.T, actor auth.User, action string, targetID uint) {
+	var auditLogCount int
+	helios.DB.Model(auth.AuditLog{}).
+		Where("actor_id = ?", actor.ID).
+		Where("action = ?", action).
+		Where("target_id = ?", targetID).
+		Count(&auditLogCount)
+	assert.Equal(t, 1, auditLogCount, "Audit log of %s should be recorded", action)
+}
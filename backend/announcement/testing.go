@@ -0,0 +1,85 @@
+package announcement
+
+import (
+	"fmt"
+
+	"github.com/yonasadiel/helios"
+
+	"github.com/yonasadiel/charon/backend/auth"
+	"github.com/yonasadiel/charon/backend/exam"
+)
+
+var announcementSeq uint = 0
+var clarificationSeq uint = 0
+
+// AnnouncementFactory creates an announcement for testing. The given argument will be
+// completed if the attribute is empty.
+func AnnouncementFactory(announcement Announcement) Announcement {
+	announcementSeq = announcementSeq + 1
+	if announcement.Content == "" {
+		announcement.Content = fmt.Sprintf("Announcement content #%d", announcementSeq)
+	}
+	if announcement.Event == nil && announcement.EventID == 0 {
+		event := exam.EventFactory(exam.Event{})
+		announcement.Event = &event
+	}
+	if announcement.Author == nil && announcement.AuthorID == 0 {
+		author := auth.UserFactory(auth.User{Role: auth.UserRoleOrganizer})
+		announcement.Author = &author
+	}
+	return announcement
+}
+
+// AnnouncementFactorySaved do exactly like AnnouncementFactory but the result
+// will be saved to database
+func AnnouncementFactorySaved(announcement Announcement) Announcement {
+	if announcement.ID == 0 {
+		announcement = AnnouncementFactory(announcement)
+		var event exam.Event = exam.EventFactorySaved(*announcement.Event)
+		var author auth.User = auth.UserFactorySaved(*announcement.Author)
+		announcement.EventID = event.ID
+		announcement.AuthorID = author.ID
+		announcement.Event = nil
+		announcement.Author = nil
+		helios.DB.Create(&announcement)
+		announcement.Event = &event
+		announcement.Author = &author
+	}
+	return announcement
+}
+
+// ClarificationFactory creates a clarification for testing. The given argument will be
+// completed if the attribute is empty.
+func ClarificationFactory(clarification Clarification) Clarification {
+	clarificationSeq = clarificationSeq + 1
+	if clarification.Question == "" {
+		clarification.Question = fmt.Sprintf("Clarification question #%d", clarificationSeq)
+	}
+	if clarification.Event == nil && clarification.EventID == 0 {
+		event := exam.EventFactory(exam.Event{})
+		clarification.Event = &event
+	}
+	if clarification.Participant == nil && clarification.ParticipantID == 0 {
+		participant := auth.UserFactory(auth.User{Role: auth.UserRoleParticipant})
+		clarification.Participant = &participant
+	}
+	return clarification
+}
+
+// ClarificationFactorySaved do exactly like ClarificationFactory but the result
+// will be saved to database
+func ClarificationFactorySaved(clarification Clarification) Clarification {
+	if clarification.ID == 0 {
+		clarification = ClarificationFactory(clarification)
+		var event exam.Event = exam.EventFactorySaved(*clarification.Event)
+		var participant auth.User = auth.UserFactorySaved(*clarification.Participant)
+		clarification.EventID = event.ID
+		clarification.ParticipantID = participant.ID
+		clarification.Event = nil
+		clarification.Participant = nil
+		helios.DB.Create(&clarification)
+		clarification.Event = &event
+		clarification.Participant = &participant
+	}
+	return clarification
+}
@@ -0,0 +1,168 @@
+package announcement
+
+import (
+	"net/http"
+
+	"github.com/yonasadiel/helios"
+
+	"github.com/yonasadiel/charon/backend/auth"
+)
+
+// AnnouncementListView sends list of announcements of the event. If announcementID
+// is given in URL, only the announcements after it will be sent.
+func AnnouncementListView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var eventSlug string = req.GetURLParam("eventSlug")
+	var afterID uint
+	if req.GetURLParam("announcementID") != "" {
+		var errParseAnnouncementID error
+		afterID, errParseAnnouncementID = req.GetURLParamUint("announcementID")
+		if errParseAnnouncementID != nil {
+			afterID = 0
+		}
+	}
+
+	var announcements []Announcement
+	var err helios.Error
+	announcements, err = GetAllAnnouncementOfUserAndEvent(user, eventSlug, afterID)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+
+	serializedAnnouncements := make([]AnnouncementData, 0)
+	for _, announcement := range announcements {
+		serializedAnnouncements = append(serializedAnnouncements, SerializeAnnouncement(announcement))
+	}
+	req.SendJSON(serializedAnnouncements, http.StatusOK)
+}
+
+// AnnouncementCreateView posts an announcement to the event
+func AnnouncementCreateView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var eventSlug string = req.GetURLParam("eventSlug")
+	var announcementData AnnouncementData
+	var announcement Announcement
+	var err helios.Error
+	err = req.DeserializeRequestData(&announcementData)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	err = DeserializeAnnouncement(announcementData, &announcement)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+
+	err = CreateAnnouncement(user, eventSlug, &announcement)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeAnnouncement(announcement), http.StatusCreated)
+}
+
+// ClarificationListView sends list of clarifications of the event
+func ClarificationListView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var eventSlug string = req.GetURLParam("eventSlug")
+	var clarifications []Clarification
+	var err helios.Error
+	clarifications, err = GetAllClarificationOfUserAndEvent(user, eventSlug)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+
+	serializedClarifications := make([]ClarificationData, 0)
+	for _, clarification := range clarifications {
+		serializedClarifications = append(serializedClarifications, SerializeClarification(clarification))
+	}
+	req.SendJSON(serializedClarifications, http.StatusOK)
+}
+
+// ClarificationCreateView submits a clarification request from participant
+func ClarificationCreateView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var eventSlug string = req.GetURLParam("eventSlug")
+	var clarificationData ClarificationData
+	var clarification Clarification
+	var err helios.Error
+	err = req.DeserializeRequestData(&clarificationData)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	err = DeserializeClarification(clarificationData, &clarification)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+
+	err = CreateClarification(user, eventSlug, &clarification)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeClarification(clarification), http.StatusCreated)
+}
+
+// ClarificationAnswerView answers a clarification, optionally broadcasting it
+func ClarificationAnswerView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var eventSlug string = req.GetURLParam("eventSlug")
+	clarificationID, errParseClarificationID := req.GetURLParamUint("clarificationID")
+	if errParseClarificationID != nil {
+		req.SendJSON(errClarificationNotFound.GetMessage(), errClarificationNotFound.GetStatusCode())
+		return
+	}
+
+	var clarificationAnswerRequest ClarificationAnswerRequest
+	var clarification *Clarification
+	var answer string
+	var broadcast bool
+	var err helios.Error
+	err = req.DeserializeRequestData(&clarificationAnswerRequest)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	err = DeserializeClarificationAnswerRequest(clarificationAnswerRequest, &answer, &broadcast)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+
+	clarification, err = AnswerClarification(user, eventSlug, clarificationID, answer, broadcast)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeClarification(*clarification), http.StatusOK)
+}
@@ -0,0 +1,339 @@
+package announcement
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yonasadiel/charon/backend/auth"
+	"github.com/yonasadiel/charon/backend/exam"
+	"github.com/yonasadiel/helios"
+)
+
+func TestAnnouncementListView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var event exam.Event = exam.EventFactorySaved(exam.Event{})
+	var participation exam.Participation = exam.ParticipationFactorySaved(exam.Participation{Event: &event})
+	var announcement1 Announcement = AnnouncementFactorySaved(Announcement{Event: &event})
+	AnnouncementFactorySaved(Announcement{Event: &event})
+
+	type announcementListViewTestCase struct {
+		user                       interface{}
+		eventSlug                  string
+		announcementID             string
+		expectedStatusCode         int
+		expectedErrorCode          string
+		expectedAnnouncementsCount int
+	}
+	testCases := []announcementListViewTestCase{{
+		user:                       *participation.User,
+		eventSlug:                  event.Slug,
+		expectedStatusCode:         http.StatusOK,
+		expectedAnnouncementsCount: 2,
+	}, {
+		user:                       *participation.User,
+		eventSlug:                  event.Slug,
+		announcementID:             strconv.Itoa(int(announcement1.ID)),
+		expectedStatusCode:         http.StatusOK,
+		expectedAnnouncementsCount: 1,
+	}, {
+		user:               *participation.User,
+		eventSlug:          "def",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  "event_not_found",
+	}, {
+		user:               "bad_user",
+		eventSlug:          event.Slug,
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedErrorCode:  helios.ErrInternalServerError.Code,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test AnnouncementListView testcase: %d", i)
+		var req helios.MockRequest = helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["eventSlug"] = testCase.eventSlug
+		req.URLParam["announcementID"] = testCase.announcementID
+
+		AnnouncementListView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		} else {
+			var announcementsData []AnnouncementData
+			json.Unmarshal(req.JSONResponse, &announcementsData)
+			assert.Equal(t, testCase.expectedAnnouncementsCount, len(announcementsData))
+		}
+	}
+}
+
+func TestAnnouncementCreateView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var event exam.Event = exam.EventFactorySaved(exam.Event{})
+	var participation exam.Participation = exam.ParticipationFactorySaved(exam.Participation{Event: &event})
+	var organizer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	exam.EventRoleFactorySaved(organizer, event, nil, auth.EventRoleAuthor)
+
+	type announcementCreateViewTestCase struct {
+		user                       interface{}
+		requestData                string
+		expectedStatusCode         int
+		expectedErrorCode          string
+		expectedAnnouncementsCount int
+	}
+	testCases := []announcementCreateViewTestCase{{
+		user:                       organizer,
+		requestData:                `{"content":"abc"}`,
+		expectedStatusCode:         http.StatusCreated,
+		expectedAnnouncementsCount: 1,
+	}, {
+		user:                       *participation.User,
+		requestData:                `{"content":"abc"}`,
+		expectedStatusCode:         http.StatusForbidden,
+		expectedErrorCode:          errAnnouncementChangeNotAuthorized.Code,
+		expectedAnnouncementsCount: 1,
+	}, {
+		user:                       organizer,
+		requestData:                `{"content":""}`,
+		expectedStatusCode:         http.StatusBadRequest,
+		expectedErrorCode:          "form_error",
+		expectedAnnouncementsCount: 1,
+	}, {
+		user:                       organizer,
+		requestData:                `bad_request_data`,
+		expectedStatusCode:         http.StatusBadRequest,
+		expectedErrorCode:          helios.ErrJSONParseFailed.Code,
+		expectedAnnouncementsCount: 1,
+	}, {
+		user:                       "bad_user",
+		requestData:                `{"content":"abc"}`,
+		expectedStatusCode:         http.StatusInternalServerError,
+		expectedErrorCode:          helios.ErrInternalServerError.Code,
+		expectedAnnouncementsCount: 1,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test AnnouncementCreateView testcase: %d", i)
+		var announcementsCount int
+		var req helios.MockRequest = helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["eventSlug"] = event.Slug
+		req.RequestData = testCase.requestData
+
+		AnnouncementCreateView(&req)
+
+		helios.DB.Model(Announcement{}).Count(&announcementsCount)
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		assert.Equal(t, testCase.expectedAnnouncementsCount, announcementsCount)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestClarificationListView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var event exam.Event = exam.EventFactorySaved(exam.Event{})
+	var participation exam.Participation = exam.ParticipationFactorySaved(exam.Participation{Event: &event})
+	var organizer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	exam.EventRoleFactorySaved(organizer, event, nil, auth.EventRoleAuthor)
+	ClarificationFactorySaved(Clarification{Event: &event, VenueID: participation.VenueID, Participant: participation.User})
+	ClarificationFactorySaved(Clarification{Event: &event})
+
+	type clarificationListViewTestCase struct {
+		user                        interface{}
+		eventSlug                   string
+		expectedStatusCode          int
+		expectedErrorCode           string
+		expectedClarificationsCount int
+	}
+	testCases := []clarificationListViewTestCase{{
+		user:                        *participation.User,
+		eventSlug:                   event.Slug,
+		expectedStatusCode:          http.StatusOK,
+		expectedClarificationsCount: 1,
+	}, {
+		user:                        organizer,
+		eventSlug:                   event.Slug,
+		expectedStatusCode:          http.StatusOK,
+		expectedClarificationsCount: 2,
+	}, {
+		user:               *participation.User,
+		eventSlug:          "def",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  "event_not_found",
+	}, {
+		user:               "bad_user",
+		eventSlug:          event.Slug,
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedErrorCode:  helios.ErrInternalServerError.Code,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test ClarificationListView testcase: %d", i)
+		var req helios.MockRequest = helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["eventSlug"] = testCase.eventSlug
+
+		ClarificationListView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		} else {
+			var clarificationsData []ClarificationData
+			json.Unmarshal(req.JSONResponse, &clarificationsData)
+			assert.Equal(t, testCase.expectedClarificationsCount, len(clarificationsData))
+		}
+	}
+}
+
+func TestClarificationCreateView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var event exam.Event = exam.EventFactorySaved(exam.Event{})
+	var participation exam.Participation = exam.ParticipationFactorySaved(exam.Participation{Event: &event})
+
+	type clarificationCreateViewTestCase struct {
+		user                        interface{}
+		requestData                 string
+		expectedStatusCode          int
+		expectedErrorCode           string
+		expectedClarificationsCount int
+	}
+	testCases := []clarificationCreateViewTestCase{{
+		user:                        *participation.User,
+		requestData:                 `{"question":"abc"}`,
+		expectedStatusCode:          http.StatusCreated,
+		expectedClarificationsCount: 1,
+	}, {
+		user:                        auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		requestData:                 `{"question":"abc"}`,
+		expectedStatusCode:          http.StatusForbidden,
+		expectedErrorCode:           errClarificationNotAuthorized.Code,
+		expectedClarificationsCount: 1,
+	}, {
+		user:                        *participation.User,
+		requestData:                 `{"question":""}`,
+		expectedStatusCode:          http.StatusBadRequest,
+		expectedErrorCode:           "form_error",
+		expectedClarificationsCount: 1,
+	}, {
+		user:                        *participation.User,
+		requestData:                 `bad_request_data`,
+		expectedStatusCode:          http.StatusBadRequest,
+		expectedErrorCode:           helios.ErrJSONParseFailed.Code,
+		expectedClarificationsCount: 1,
+	}, {
+		user:                        "bad_user",
+		requestData:                 `{"question":"abc"}`,
+		expectedStatusCode:          http.StatusInternalServerError,
+		expectedErrorCode:           helios.ErrInternalServerError.Code,
+		expectedClarificationsCount: 1,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test ClarificationCreateView testcase: %d", i)
+		var clarificationsCount int
+		var req helios.MockRequest = helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["eventSlug"] = event.Slug
+		req.RequestData = testCase.requestData
+
+		ClarificationCreateView(&req)
+
+		helios.DB.Model(Clarification{}).Count(&clarificationsCount)
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		assert.Equal(t, testCase.expectedClarificationsCount, clarificationsCount)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestClarificationAnswerView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var event exam.Event = exam.EventFactorySaved(exam.Event{})
+	var participation exam.Participation = exam.ParticipationFactorySaved(exam.Participation{Event: &event})
+	var clarification Clarification = ClarificationFactorySaved(Clarification{Event: &event, VenueID: participation.VenueID, Participant: participation.User})
+	var organizer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	exam.EventRoleFactorySaved(organizer, event, nil, auth.EventRoleAuthor)
+
+	type clarificationAnswerViewTestCase struct {
+		user               interface{}
+		clarificationID    string
+		requestData        string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []clarificationAnswerViewTestCase{{
+		user:               *participation.User,
+		clarificationID:    strconv.Itoa(int(clarification.ID)),
+		requestData:        `{"answer":"abc"}`,
+		expectedStatusCode: http.StatusForbidden,
+		expectedErrorCode:  errClarificationAnswerNotAuthorized.Code,
+	}, {
+		user:               organizer,
+		clarificationID:    "bad_clarification_id",
+		requestData:        `{"answer":"abc"}`,
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errClarificationNotFound.Code,
+	}, {
+		user:               organizer,
+		clarificationID:    strconv.Itoa(int(clarification.ID)),
+		requestData:        `{"answer":""}`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  "form_error",
+	}, {
+		user:               organizer,
+		clarificationID:    strconv.Itoa(int(clarification.ID)),
+		requestData:        `bad_request_data`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  helios.ErrJSONParseFailed.Code,
+	}, {
+		user:               organizer,
+		clarificationID:    strconv.Itoa(int(clarification.ID)),
+		requestData:        `{"answer":"abc","broadcast":true}`,
+		expectedStatusCode: http.StatusOK,
+	}, {
+		user:               organizer,
+		clarificationID:    strconv.Itoa(int(clarification.ID)),
+		requestData:        `{"answer":"abc"}`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  errClarificationAlreadyAnswered.Code,
+	}, {
+		user:               "bad_user",
+		clarificationID:    strconv.Itoa(int(clarification.ID)),
+		requestData:        `{"answer":"abc"}`,
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedErrorCode:  helios.ErrInternalServerError.Code,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test ClarificationAnswerView testcase: %d", i)
+		var req helios.MockRequest = helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["eventSlug"] = event.Slug
+		req.URLParam["clarificationID"] = testCase.clarificationID
+		req.RequestData = testCase.requestData
+
+		ClarificationAnswerView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
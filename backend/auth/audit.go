@@ -0,0 +1,213 @@
+package auth
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/yonasadiel/helios"
+
+	"github.com/yonasadiel/charon/backend/logging"
+)
+
+// auditIgnoredFields are the fields of the models that are not recorded
+// because they change on every save
+var auditIgnoredFields = map[string]bool{
+	"CreatedAt": true,
+	"UpdatedAt": true,
+	"DeletedAt": true,
+}
+
+// auditRedactedFields are the fields of the models that hold secrets.
+// The change is recorded, but the values are replaced.
+var auditRedactedFields = map[string]bool{
+	"Password":       true,
+	"TOTPSecret":     true,
+	"Token":          true,
+	"TokenHashed":    true,
+	"SimKey":         true,
+	"PrvKey":         true,
+	"KeyPlain":       true,
+	"KeyHashedOnce":  true,
+	"KeyHashedTwice": true,
+	"SecretShareY":   true,
+}
+
+const auditRedactedValue = "[redacted]"
+
+// AuditLogFilter is the filter of audit log query. The empty fields are
+// not filtered. BeforeID is the ID of the last entry of the previous page.
+type AuditLogFilter struct {
+	ActorUsername string
+	Action        string
+	TargetType    string
+	TargetID      uint
+	From          time.Time
+	To            time.Time
+	BeforeID      uint
+}
+
+// AuditLogVerification is the result of verifying the hash chain of the
+// audit log. BrokenID is the first entry that doesn't match its hash or
+// the previous entry, zero if the chain is intact. LastHash is the hash
+// of the last intact entry, it can be kept elsewhere to detect removal
+// of the latest entries.
+type AuditLogVerification struct {
+	Checked  int
+	BrokenID uint
+	LastHash string
+}
+
+// RecordAuditLog appends the change made by the actor on the target to the
+// audit log. before and after are the target before and after the change,
+// nil on creation and deletion. Only the changed fields are recorded, the
+// associations are skipped, and the secrets are redacted. The entry is appended
+// on tx, the transaction that makes the change, so the change is rolled back if
+// it can't be recorded. The transactions that append at the same time wait for
+// each other, see lockAuditLogHead, and PrevHash is unique, so two entries are
+// never chained to the same previous entry.
+func RecordAuditLog(tx *gorm.DB, actor User, action string, targetType string, targetID uint, before interface{}, after interface{}) helios.Error {
+	var auditLog AuditLog = AuditLog{
+		ActorID:       actor.ID,
+		ActorUsername: actor.Username,
+		IPAddress:     actor.IPAddress,
+		Action:        action,
+		TargetType:    targetType,
+		TargetID:      targetID,
+		// the time is hashed in seconds, so it is kept the same after stored
+		CreatedAt: time.Now().Truncate(time.Second),
+	}
+	auditLog.Before, auditLog.After = auditDiff(before, after)
+
+	var lastAuditLog AuditLog
+	if errDB := logging.CheckDB(actor.RequestID, lockAuditLogHead(tx, &lastAuditLog)); errDB != nil {
+		return errDB
+	}
+	auditLog.PrevHash = lastAuditLog.Hash
+	auditLog.Hash = hashAuditLog(auditLog)
+	return logging.CheckDB(actor.RequestID, tx.Create(&auditLog))
+}
+
+// auditLogLockKey is the key of the postgres advisory lock of the audit log
+const auditLogLockKey = 7246001
+
+// lockAuditLogHead reads the last entry of the audit log after locking the log
+// until tx ends, so the other transactions wait to chain after the entry of tx.
+// Postgres takes the advisory lock of the transaction, and mysql locks the last
+// entry and the gap after it. On sqlite3 the write transactions are begun
+// immediate by config.OpenDatabase, so tx already holds the database lock.
+func lockAuditLogHead(tx *gorm.DB, lastAuditLog *AuditLog) *gorm.DB {
+	switch tx.Dialect().GetName() {
+	case "postgres":
+		if locked := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditLogLockKey); locked.Error != nil {
+			return locked
+		}
+	case "mysql":
+		return tx.Set("gorm:query_option", "FOR UPDATE").Last(lastAuditLog)
+	}
+	return tx.Last(lastAuditLog)
+}
+
+// hashAuditLog hashes the content of the entry together with the hash
+// of the previous entry
+func hashAuditLog(auditLog AuditLog) string {
+	content, _ := json.Marshal([]interface{}{
+		auditLog.PrevHash,
+		auditLog.ActorID,
+		auditLog.ActorUsername,
+		auditLog.IPAddress,
+		auditLog.Action,
+		auditLog.TargetType,
+		auditLog.TargetID,
+		auditLog.Before,
+		auditLog.After,
+		auditLog.CreatedAt.UTC().Format(time.RFC3339),
+	})
+	return hashToken(string(content))
+}
+
+// auditDiff returns JSON of the fields that are different between before
+// and after. If one of them is nil, all fields of the other are returned.
+func auditDiff(before interface{}, after interface{}) (string, string) {
+	var beforeFields map[string]interface{} = auditFields(before)
+	var afterFields map[string]interface{} = auditFields(after)
+	var beforeChanged map[string]interface{} = make(map[string]interface{})
+	var afterChanged map[string]interface{} = make(map[string]interface{})
+	for field, value := range beforeFields {
+		if afterValue, ok := afterFields[field]; !ok || !reflect.DeepEqual(value, afterValue) {
+			beforeChanged[field] = redactAuditField(field, value)
+		}
+	}
+	for field, value := range afterFields {
+		if beforeValue, ok := beforeFields[field]; !ok || !reflect.DeepEqual(value, beforeValue) {
+			afterChanged[field] = redactAuditField(field, value)
+		}
+	}
+	return encodeAuditFields(beforeChanged), encodeAuditFields(afterChanged)
+}
+
+// auditFields converts the model to map of its fields, without the ignored
+// fields and the associations. It returns nil if the model is nil.
+func auditFields(model interface{}) map[string]interface{} {
+	if model == nil {
+		return nil
+	}
+	var fields map[string]interface{}
+	encoded, errEncode := json.Marshal(model)
+	if errEncode != nil || json.Unmarshal(encoded, &fields) != nil {
+		return nil
+	}
+	for field, value := range fields {
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			delete(fields, field)
+		default:
+			if auditIgnoredFields[field] {
+				delete(fields, field)
+			}
+		}
+	}
+	return fields
+}
+
+func redactAuditField(field string, value interface{}) interface{} {
+	if auditRedactedFields[field] && value != nil && value != "" {
+		return auditRedactedValue
+	}
+	return value
+}
+
+func encodeAuditFields(fields map[string]interface{}) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	encoded, _ := json.Marshal(fields)
+	return string(encoded)
+}
+
+// apply adds the conditions of the filter to the query
+func (filter AuditLogFilter) apply(query *gorm.DB) *gorm.DB {
+	if filter.ActorUsername != "" {
+		query = query.Where("actor_username = ?", filter.ActorUsername)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != 0 {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at <= ?", filter.To)
+	}
+	if filter.BeforeID != 0 {
+		query = query.Where("id < ?", filter.BeforeID)
+	}
+	return query
+}
@@ -0,0 +1,146 @@
+package auth
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yonasadiel/helios"
+
+	"github.com/yonasadiel/charon/backend/config"
+)
+
+func TestAuditDiff(t *testing.T) {
+	type auditDiffTestCase struct {
+		before         interface{}
+		after          interface{}
+		expectedBefore string
+		expectedAfter  string
+	}
+	testCases := []auditDiffTestCase{{
+		before:         nil,
+		after:          User{ID: 1, Name: "Alice", Username: "alice", Role: UserRoleLocal},
+		expectedBefore: "",
+		expectedAfter:  `{"ID":1,"MustChangePassword":false,"Name":"Alice","Role":20,"TOTPEnabled":false,"TOTPLastCounter":0,"TOTPSecret":"","Username":"alice","Password":""}`,
+	}, {
+		before:         User{ID: 1, Name: "Alice", Username: "alice", Password: "hash1"},
+		after:          User{ID: 1, Name: "Bob", Username: "alice", Password: "hash2"},
+		expectedBefore: `{"Name":"Alice","Password":"[redacted]"}`,
+		expectedAfter:  `{"Name":"Bob","Password":"[redacted]"}`,
+	}, {
+		before:         User{ID: 1, Name: "Alice"},
+		after:          User{ID: 1, Name: "Alice"},
+		expectedBefore: "",
+		expectedAfter:  "",
+	}, {
+		before:         Session{ID: 2, Token: "token", IPAddress: "7.1.1.1", User: &User{ID: 1}},
+		after:          nil,
+		expectedBefore: `{"ID":2,"IPAddress":"7.1.1.1","LastSeenAt":"0001-01-01T00:00:00Z","Token":"[redacted]","TwoFactorPending":false,"UserID":0}`,
+		expectedAfter:  "",
+	}, {
+		before:         map[string]interface{}{"RoomID": 0, "SeatNumber": 0},
+		after:          map[string]interface{}{"RoomID": 3, "SeatNumber": 0},
+		expectedBefore: `{"RoomID":0}`,
+		expectedAfter:  `{"RoomID":3}`,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test AuditDiff testcase: %d", i)
+		before, after := auditDiff(testCase.before, testCase.after)
+		if testCase.expectedBefore == "" {
+			assert.Equal(t, "", before)
+		} else {
+			assert.JSONEq(t, testCase.expectedBefore, before)
+		}
+		if testCase.expectedAfter == "" {
+			assert.Equal(t, "", after)
+		} else {
+			assert.JSONEq(t, testCase.expectedAfter, after)
+		}
+	}
+}
+
+func TestRecordAuditLog(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var actor User = UserFactorySaved(User{Role: UserRoleAdmin})
+	actor.IPAddress = "7.1.1.1"
+	RecordAuditLog(helios.DB, actor, "user.update", "user", 5, User{ID: 5, Name: "Alice"}, User{ID: 5, Name: "Bob"})
+	RecordAuditLog(helios.DB, actor, "user.unlock", "user", 5, nil, nil)
+
+	var auditLogs []AuditLog
+	helios.DB.Order("id asc").Find(&auditLogs)
+	assert.Equal(t, 2, len(auditLogs))
+	assert.Equal(t, actor.ID, auditLogs[0].ActorID)
+	assert.Equal(t, actor.Username, auditLogs[0].ActorUsername)
+	assert.Equal(t, "7.1.1.1", auditLogs[0].IPAddress)
+	assert.Equal(t, "user.update", auditLogs[0].Action)
+	assert.Equal(t, "user", auditLogs[0].TargetType)
+	assert.Equal(t, uint(5), auditLogs[0].TargetID)
+	assert.Equal(t, `{"Name":"Alice"}`, auditLogs[0].Before)
+	assert.Equal(t, `{"Name":"Bob"}`, auditLogs[0].After)
+	assert.Equal(t, "", auditLogs[0].PrevHash)
+	assert.Equal(t, hashAuditLog(auditLogs[0]), auditLogs[0].Hash)
+	assert.Equal(t, auditLogs[0].Hash, auditLogs[1].PrevHash, "Entry should be chained to the previous entry")
+	assert.Equal(t, hashAuditLog(auditLogs[1]), auditLogs[1].Hash)
+	assert.Equal(t, "", auditLogs[1].Before)
+	assert.Equal(t, "", auditLogs[1].After)
+
+	var count int
+	tx := helios.DB.Begin()
+	assert.Nil(t, RecordAuditLog(tx, actor, "user.unlock", "user", 6, nil, nil))
+	tx.Rollback()
+	helios.DB.Model(AuditLog{}).Count(&count)
+	assert.Equal(t, 2, count, "Entry should be rolled back with the change")
+
+	var errDB error = helios.DB.Create(&AuditLog{PrevHash: auditLogs[0].Hash, Hash: "forked"}).Error
+	assert.NotNil(t, errDB, "Two entries should not be chained to the same entry")
+}
+
+func TestRecordAuditLogConcurrently(t *testing.T) {
+	// the in-memory database of the tests is not shared between connections
+	dir, err := ioutil.TempDir("", "charon-audit")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	db, err := config.OpenDatabase("sqlite3", filepath.Join(dir, "audit.sqlite3"))
+	assert.Nil(t, err)
+	defer db.Close()
+	assert.Nil(t, db.AutoMigrate(&AuditLog{}).Error)
+
+	const appendCount = 20
+	var actor User = User{ID: 1, Username: "admin"}
+	var wg sync.WaitGroup
+	var errs chan helios.Error = make(chan helios.Error, appendCount)
+	for i := 0; i < appendCount; i++ {
+		wg.Add(1)
+		go func(targetID uint) {
+			defer wg.Done()
+			tx := db.Begin()
+			if errDB := RecordAuditLog(tx, actor, "user.unlock", "user", targetID, nil, nil); errDB != nil {
+				tx.Rollback()
+				errs <- errDB
+				return
+			}
+			if errCommit := tx.Commit().Error; errCommit != nil {
+				errs <- helios.ErrInternalServerError
+			}
+		}(uint(i))
+	}
+	wg.Wait()
+	close(errs)
+	for errAppend := range errs {
+		assert.Nil(t, errAppend, "Concurrent entries should wait for each other")
+	}
+
+	var auditLogs []AuditLog
+	db.Order("id asc").Find(&auditLogs)
+	assert.Equal(t, appendCount, len(auditLogs))
+	var prevHash string
+	for _, auditLog := range auditLogs {
+		assert.Equal(t, prevHash, auditLog.PrevHash, "Entry should be chained to the previous entry")
+		prevHash = auditLog.Hash
+	}
+}
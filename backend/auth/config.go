@@ -0,0 +1,258 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+	"unicode"
+)
+
+// SessionPolicy is the rule of concurrent sessions of a role
+type SessionPolicy struct {
+	// MaxSessions is the maximum number of active sessions at the same
+	// time. Zero means unlimited.
+	MaxSessions int
+	// RevokeOldest revokes the oldest session when the limit is reached.
+	// Otherwise, the new login is rejected until one of the sessions
+	// is revoked.
+	RevokeOldest bool
+}
+
+// PasswordHashCost is the bcrypt cost of hashing the user passwords
+var PasswordHashCost int = 10
+
+// SessionMaxAge is the duration of session since it is created until it
+// expires, regardless of the activity. Zero means never expires.
+var SessionMaxAge time.Duration = 12 * time.Hour
+
+// SessionIdleTimeout is the duration of session without any request
+// until it expires. Zero means never expires.
+var SessionIdleTimeout time.Duration = 2 * time.Hour
+
+// SessionPolicies is the session policy of each role. Participants are
+// limited to one device, so the proctor has to revoke the old session
+// before the participant can move to another computer.
+var SessionPolicies = map[uint]SessionPolicy{
+	UserRoleAdmin:       {MaxSessions: 0},
+	UserRoleOrganizer:   {MaxSessions: 0},
+	UserRoleLocal:       {MaxSessions: 3, RevokeOldest: true},
+	UserRoleParticipant: {MaxSessions: 1, RevokeOldest: false},
+}
+
+// APITokenMaxAge is the duration of API token since it is issued until it
+// expires. Zero means never expires.
+var APITokenMaxAge time.Duration = 180 * 24 * time.Hour
+
+// TwoFactorRequiredRoles is the roles that have to log in with TOTP code.
+// The user of the roles has to enroll before doing anything else.
+var TwoFactorRequiredRoles = map[uint]bool{
+	UserRoleAdmin:       true,
+	UserRoleOrganizer:   true,
+	UserRoleLocal:       false,
+	UserRoleParticipant: false,
+}
+
+// TwoFactorLoginTimeout is the duration of the session waiting for TOTP
+// code since the password is verified until it expires
+var TwoFactorLoginTimeout time.Duration = 5 * time.Minute
+
+// TOTPIssuer is the issuer shown on the authenticator app
+var TOTPIssuer string = "Charon"
+
+// LoginThrottlePolicy is the rule of failed login attempts. After FreeAttempts
+// failures, the next attempt has to wait BackoffBase, doubled on every failure.
+// After MaxAttempts failures, the login is locked for LockoutDuration or until
+// unlocked by the proctor. Only failures within Window are counted.
+type LoginThrottlePolicy struct {
+	FreeAttempts    int
+	BackoffBase     time.Duration
+	MaxAttempts     int
+	LockoutDuration time.Duration
+	Window          time.Duration
+}
+
+// LoginThrottleByUsername is the throttle policy of failed login attempts
+// to the same username
+var LoginThrottleByUsername = LoginThrottlePolicy{
+	FreeAttempts:    3,
+	BackoffBase:     2 * time.Second,
+	MaxAttempts:     10,
+	LockoutDuration: 15 * time.Minute,
+	Window:          time.Hour,
+}
+
+// LoginThrottleByIP is the throttle policy of failed login attempts from
+// the same IP address. It is looser than LoginThrottleByUsername because
+// some computers may share the same address behind NAT.
+var LoginThrottleByIP = LoginThrottlePolicy{
+	FreeAttempts:    10,
+	BackoffBase:     time.Second,
+	MaxAttempts:     50,
+	LockoutDuration: 15 * time.Minute,
+	Window:          time.Hour,
+}
+
+// PasswordPolicy is the rule of password chosen by the user
+type PasswordPolicy struct {
+	MinLength     int
+	RequireLetter bool
+	RequireDigit  bool
+	RequireSymbol bool
+}
+
+// UserPasswordPolicy is the policy of password set by the user. Generated
+// passwords of participants are not checked against it.
+var UserPasswordPolicy = PasswordPolicy{
+	MinLength:     8,
+	RequireLetter: true,
+	RequireDigit:  true,
+	RequireSymbol: false,
+}
+
+// PasswordResetTokenMaxAge is the duration of password reset token since
+// it is issued until it expires
+var PasswordResetTokenMaxAge time.Duration = 24 * time.Hour
+
+// Validate returns the rules of the policy that are not satisfied by
+// the password, empty if the password is valid
+func (policy PasswordPolicy) Validate(password string) []string {
+	var hasLetter, hasDigit, hasSymbol bool
+	for _, c := range password {
+		switch {
+		case unicode.IsLetter(c):
+			hasLetter = true
+		case unicode.IsDigit(c):
+			hasDigit = true
+		default:
+			hasSymbol = true
+		}
+	}
+	var violations []string = make([]string, 0)
+	if len([]rune(password)) < policy.MinLength {
+		violations = append(violations, fmt.Sprintf("Password should be at least %d characters", policy.MinLength))
+	}
+	if policy.RequireLetter && !hasLetter {
+		violations = append(violations, "Password should contain a letter")
+	}
+	if policy.RequireDigit && !hasDigit {
+		violations = append(violations, "Password should contain a digit")
+	}
+	if policy.RequireSymbol && !hasSymbol {
+		violations = append(violations, "Password should contain a symbol")
+	}
+	return violations
+}
+
+// ConfigureFromEnv overrides the default configuration with environment
+// variables. Sessions are configured by SESSION_MAX_AGE and SESSION_IDLE_TIMEOUT
+// (Go duration, e.g. "12h"), and SESSION_LIMIT_ADMIN, SESSION_LIMIT_ORGANIZER,
+// SESSION_LIMIT_LOCAL, SESSION_LIMIT_PARTICIPANT (number of sessions). Login
+// throttling per username is configured by LOGIN_MAX_ATTEMPTS and
+// LOGIN_LOCKOUT_DURATION. Password policy is configured by PASSWORD_MIN_LENGTH,
+// PASSWORD_REQUIRE_LETTER, PASSWORD_REQUIRE_DIGIT, PASSWORD_REQUIRE_SYMBOL
+// (true or false), and PASSWORD_RESET_TOKEN_MAX_AGE. Two-factor authentication
+// is configured by TWO_FACTOR_REQUIRED_ADMIN, TWO_FACTOR_REQUIRED_ORGANIZER,
+// TWO_FACTOR_REQUIRED_LOCAL, TWO_FACTOR_REQUIRED_PARTICIPANT (true or false),
+// TWO_FACTOR_LOGIN_TIMEOUT, and TOTP_ISSUER. API tokens are configured by
+// API_TOKEN_MAX_AGE.
+func ConfigureFromEnv() error {
+	var err error
+	if LoginThrottleByUsername.MaxAttempts, err = intFromEnv("LOGIN_MAX_ATTEMPTS", LoginThrottleByUsername.MaxAttempts); err != nil {
+		return err
+	}
+	if LoginThrottleByUsername.LockoutDuration, err = durationFromEnv("LOGIN_LOCKOUT_DURATION", LoginThrottleByUsername.LockoutDuration); err != nil {
+		return err
+	}
+	if UserPasswordPolicy.MinLength, err = intFromEnv("PASSWORD_MIN_LENGTH", UserPasswordPolicy.MinLength); err != nil {
+		return err
+	}
+	if UserPasswordPolicy.RequireLetter, err = boolFromEnv("PASSWORD_REQUIRE_LETTER", UserPasswordPolicy.RequireLetter); err != nil {
+		return err
+	}
+	if UserPasswordPolicy.RequireDigit, err = boolFromEnv("PASSWORD_REQUIRE_DIGIT", UserPasswordPolicy.RequireDigit); err != nil {
+		return err
+	}
+	if UserPasswordPolicy.RequireSymbol, err = boolFromEnv("PASSWORD_REQUIRE_SYMBOL", UserPasswordPolicy.RequireSymbol); err != nil {
+		return err
+	}
+	if PasswordResetTokenMaxAge, err = durationFromEnv("PASSWORD_RESET_TOKEN_MAX_AGE", PasswordResetTokenMaxAge); err != nil {
+		return err
+	}
+	if SessionMaxAge, err = durationFromEnv("SESSION_MAX_AGE", SessionMaxAge); err != nil {
+		return err
+	}
+	if SessionIdleTimeout, err = durationFromEnv("SESSION_IDLE_TIMEOUT", SessionIdleTimeout); err != nil {
+		return err
+	}
+	roleEnvs := map[uint]string{
+		UserRoleAdmin:       "SESSION_LIMIT_ADMIN",
+		UserRoleOrganizer:   "SESSION_LIMIT_ORGANIZER",
+		UserRoleLocal:       "SESSION_LIMIT_LOCAL",
+		UserRoleParticipant: "SESSION_LIMIT_PARTICIPANT",
+	}
+	for role, env := range roleEnvs {
+		policy := SessionPolicies[role]
+		if policy.MaxSessions, err = intFromEnv(env, policy.MaxSessions); err != nil {
+			return err
+		}
+		SessionPolicies[role] = policy
+	}
+	twoFactorEnvs := map[uint]string{
+		UserRoleAdmin:       "TWO_FACTOR_REQUIRED_ADMIN",
+		UserRoleOrganizer:   "TWO_FACTOR_REQUIRED_ORGANIZER",
+		UserRoleLocal:       "TWO_FACTOR_REQUIRED_LOCAL",
+		UserRoleParticipant: "TWO_FACTOR_REQUIRED_PARTICIPANT",
+	}
+	for role, env := range twoFactorEnvs {
+		if TwoFactorRequiredRoles[role], err = boolFromEnv(env, TwoFactorRequiredRoles[role]); err != nil {
+			return err
+		}
+	}
+	if TwoFactorLoginTimeout, err = durationFromEnv("TWO_FACTOR_LOGIN_TIMEOUT", TwoFactorLoginTimeout); err != nil {
+		return err
+	}
+	if APITokenMaxAge, err = durationFromEnv("API_TOKEN_MAX_AGE", APITokenMaxAge); err != nil {
+		return err
+	}
+	if issuer := os.Getenv("TOTP_ISSUER"); issuer != "" {
+		TOTPIssuer = issuer
+	}
+	return nil
+}
+
+func intFromEnv(env string, defaultValue int) (int, error) {
+	value := os.Getenv(env)
+	if value == "" {
+		return defaultValue, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 0 {
+		return defaultValue, fmt.Errorf("%s should be a non-negative number, got %q", env, value)
+	}
+	return number, nil
+}
+
+func durationFromEnv(env string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(env)
+	if value == "" {
+		return defaultValue, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return defaultValue, fmt.Errorf("%s should be a non-negative duration, got %q", env, value)
+	}
+	return duration, nil
+}
+
+func boolFromEnv(env string, defaultValue bool) (bool, error) {
+	value := os.Getenv(env)
+	if value == "" {
+		return defaultValue, nil
+	}
+	flag, err := strconv.ParseBool(value)
+	if err != nil {
+		return defaultValue, fmt.Errorf("%s should be true or false, got %q", env, value)
+	}
+	return flag, nil
+}
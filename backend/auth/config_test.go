@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigureFromEnv(t *testing.T) {
+	var defaultMaxAge time.Duration = SessionMaxAge
+	var defaultIdleTimeout time.Duration = SessionIdleTimeout
+	var defaultParticipantPolicy SessionPolicy = SessionPolicies[UserRoleParticipant]
+	var defaultLoginThrottle LoginThrottlePolicy = LoginThrottleByUsername
+	var defaultPasswordPolicy PasswordPolicy = UserPasswordPolicy
+	var defaultTwoFactorLocal bool = TwoFactorRequiredRoles[UserRoleLocal]
+	defer func() {
+		TwoFactorRequiredRoles[UserRoleLocal] = defaultTwoFactorLocal
+		os.Unsetenv("TWO_FACTOR_REQUIRED_LOCAL")
+		UserPasswordPolicy = defaultPasswordPolicy
+		os.Unsetenv("PASSWORD_MIN_LENGTH")
+		os.Unsetenv("PASSWORD_REQUIRE_SYMBOL")
+		LoginThrottleByUsername = defaultLoginThrottle
+		os.Unsetenv("LOGIN_MAX_ATTEMPTS")
+		SessionMaxAge = defaultMaxAge
+		SessionIdleTimeout = defaultIdleTimeout
+		SessionPolicies[UserRoleParticipant] = defaultParticipantPolicy
+		os.Unsetenv("SESSION_MAX_AGE")
+		os.Unsetenv("SESSION_IDLE_TIMEOUT")
+		os.Unsetenv("SESSION_LIMIT_PARTICIPANT")
+	}()
+
+	os.Setenv("SESSION_MAX_AGE", "6h")
+	os.Setenv("SESSION_IDLE_TIMEOUT", "30m")
+	os.Setenv("SESSION_LIMIT_PARTICIPANT", "2")
+	os.Setenv("LOGIN_MAX_ATTEMPTS", "5")
+	os.Setenv("PASSWORD_MIN_LENGTH", "12")
+	os.Setenv("PASSWORD_REQUIRE_SYMBOL", "true")
+	os.Setenv("TWO_FACTOR_REQUIRED_LOCAL", "true")
+	assert.Nil(t, ConfigureFromEnv())
+	assert.True(t, TwoFactorRequiredRoles[UserRoleLocal])
+	assert.True(t, TwoFactorRequiredRoles[UserRoleAdmin])
+	assert.Equal(t, 12, UserPasswordPolicy.MinLength)
+	assert.True(t, UserPasswordPolicy.RequireSymbol)
+	assert.Equal(t, defaultPasswordPolicy.RequireDigit, UserPasswordPolicy.RequireDigit)
+	assert.Equal(t, 5, LoginThrottleByUsername.MaxAttempts)
+	assert.Equal(t, defaultLoginThrottle.LockoutDuration, LoginThrottleByUsername.LockoutDuration)
+	assert.Equal(t, 6*time.Hour, SessionMaxAge)
+	assert.Equal(t, 30*time.Minute, SessionIdleTimeout)
+	assert.Equal(t, 2, SessionPolicies[UserRoleParticipant].MaxSessions)
+	assert.Equal(t, defaultParticipantPolicy.RevokeOldest, SessionPolicies[UserRoleParticipant].RevokeOldest)
+
+	os.Setenv("SESSION_IDLE_TIMEOUT", "abc")
+	assert.NotNil(t, ConfigureFromEnv())
+	os.Setenv("SESSION_IDLE_TIMEOUT", "30m")
+	os.Setenv("SESSION_LIMIT_PARTICIPANT", "-1")
+	assert.NotNil(t, ConfigureFromEnv())
+	os.Setenv("SESSION_LIMIT_PARTICIPANT", "2")
+	os.Setenv("PASSWORD_REQUIRE_SYMBOL", "maybe")
+	assert.NotNil(t, ConfigureFromEnv())
+}
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	var policy PasswordPolicy = PasswordPolicy{MinLength: 8, RequireLetter: true, RequireDigit: true, RequireSymbol: true}
+	type passwordPolicyValidateTestCase struct {
+		password           string
+		expectedViolations []string
+	}
+	testCases := []passwordPolicyValidateTestCase{{
+		password:           "abc1234!",
+		expectedViolations: []string{},
+	}, {
+		password:           "ab1!",
+		expectedViolations: []string{"Password should be at least 8 characters"},
+	}, {
+		password:           "12345678",
+		expectedViolations: []string{"Password should contain a letter", "Password should contain a symbol"},
+	}, {
+		password:           "",
+		expectedViolations: []string{"Password should be at least 8 characters", "Password should contain a letter", "Password should contain a digit", "Password should contain a symbol"},
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test PasswordPolicyValidate testcase: %d", i)
+		assert.Equal(t, testCase.expectedViolations, policy.Validate(testCase.password))
+	}
+}
@@ -2,17 +2,21 @@ package auth
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/yonasadiel/helios"
 )
 
 const (
-	hashCost = 10
-
 	// UserTokenSessionKey is the key of session data that store user id
 	UserTokenSessionKey = "user"
 	// UserContextKey is the key of context data that store user object
 	UserContextKey = "user"
+	// SessionContextKey is the key of context data that store session object
+	SessionContextKey = "session"
+	// APITokenContextKey is the key of context data that store API token object,
+	// only set if the request is authenticated by API token
+	APITokenContextKey = "api_token"
 
 	// UserRoleAdmin is the administrator of the website
 	UserRoleAdmin = 40
@@ -23,11 +27,105 @@ const (
 	// UserRoleParticipant is the one that taking the exam
 	UserRoleParticipant = 10
 
-	userTokenLength  = 16 // length of the token
-	userTokenBytes   = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	userTokenIdxBits = 6                       // 6 bits to represent a userToken index
-	userTokenIdxMask = 1<<userTokenIdxBits - 1 // All 1-bits, as many as userTokenIdxBits
-	userTokenIdxMax  = 63 / userTokenIdxBits   // # of userToken indices fitting in 63 bits
+	// EventRoleAuthor is the one that writes the questions and manages the event
+	EventRoleAuthor = "author"
+	// EventRoleReviewer is the one that reviews the questions before the event starts
+	EventRoleReviewer = "reviewer"
+	// EventRoleGrader is the one that grades the answers
+	EventRoleGrader = "grader"
+	// EventRoleProctor is the one that supervises the participants on the venue
+	EventRoleProctor = "proctor"
+	// EventRoleVenueManager is the one that manages the participants and seats of the venue
+	EventRoleVenueManager = "venue_manager"
+
+	userTokenLength = 64 // length of the token, encoded from 48 random bytes
+
+	// LoginResultSuccess is the result of login attempt with correct credential
+	LoginResultSuccess = "success"
+	// LoginResultFailed is the result of login attempt with wrong credential
+	LoginResultFailed = "failed"
+	// LoginResultBlocked is the result of login attempt that is rejected
+	// because of too many failed attempts
+	LoginResultBlocked = "blocked"
+	// LoginResultRejected is the result of login attempt with correct credential
+	// but is rejected by the session policy
+	LoginResultRejected = "rejected"
+
+	// loginAttemptListLimit is the maximum number of login attempts returned
+	loginAttemptListLimit = 200
+
+	// LoginResultTwoFactorPending is the result of login attempt with correct
+	// password, waiting for the second factor
+	LoginResultTwoFactorPending = "two_factor_pending"
+
+	// totpPeriod is the time step of TOTP code
+	totpPeriod = 30 * time.Second
+	// totpDigits is the number of digits of TOTP code
+	totpDigits = 6
+	// totpSkew is the number of time steps before and after the current
+	// step that are accepted, to allow clock drift of the device
+	totpSkew = 1
+	// totpSecretLength is the number of random bytes of TOTP secret
+	totpSecretLength = 20
+	// recoveryCodeCount is the number of recovery codes generated at once
+	recoveryCodeCount = 10
+	// recoveryCodeLength is the number of characters of recovery code,
+	// excluding the separator
+	recoveryCodeLength = 10
+
+	// APITokenUsageAccepted is the result of request with API token on
+	// the event within its scopes
+	APITokenUsageAccepted = "accepted"
+	// APITokenUsageOutOfScope is the result of request with API token on
+	// the event outside its scopes
+	APITokenUsageOutOfScope = "out_of_scope"
+	// APITokenUsageInvalid is the result of request with unknown API token,
+	// or the token of user that is not local
+	APITokenUsageInvalid = "invalid"
+	// APITokenUsageExpired is the result of request with expired API token
+	APITokenUsageExpired = "expired"
+	// APITokenUsageRevoked is the result of request with revoked API token
+	APITokenUsageRevoked = "revoked"
+
+	// apiTokenPrefixLength is the length of the token prefix that is kept
+	// to recognize the token
+	apiTokenPrefixLength = 8
+	// apiTokenUsageListLimit is the maximum number of API token usages returned
+	apiTokenUsageListLimit = 200
+
+	// auditLogListLimit is the maximum number of audit log entries returned on a page
+	auditLogListLimit = 100
+	// auditLogVerifyBatchSize is the number of audit log entries loaded at
+	// once on verifying the hash chain
+	auditLogVerifyBatchSize = 500
+
+	// actions and target types of the changes recorded on the audit log
+	auditActionUserCreate             = "user.create"
+	auditActionUserUpdate             = "user.update"
+	auditActionUserUnlock             = "user.unlock"
+	auditActionIPUnlock               = "ip.unlock"
+	auditActionPasswordChange         = "password.change"
+	auditActionPasswordResetIssue     = "password.reset_issue"
+	auditActionPasswordReset          = "password.reset"
+	auditActionTwoFactorEnroll        = "two_factor.enroll"
+	auditActionTwoFactorEnable        = "two_factor.enable"
+	auditActionTwoFactorDisable       = "two_factor.disable"
+	auditActionTwoFactorReset         = "two_factor.reset"
+	auditActionRecoveryCodeRegenerate = "recovery_code.regenerate"
+	auditActionSessionLogin           = "session.login"
+	auditActionSessionVerify          = "session.verify"
+	auditActionSessionLogout          = "session.logout"
+	auditActionSessionRevoke          = "session.revoke"
+	auditActionAPITokenIssue          = "api_token.issue"
+	auditActionAPITokenRevoke         = "api_token.revoke"
+	auditTargetUser                   = "user"
+	auditTargetSession                = "session"
+	auditTargetAPIToken               = "api_token"
+	auditTargetIPAddress              = "ip_address"
+
+	// sessionLastSeenInterval is the minimum interval between two updates
+	// of session's last seen time, to avoid writing on every request
+	sessionLastSeenInterval = time.Minute
 )
 
 var errWrongUsernamePassword = helios.ErrorForm{
@@ -35,11 +133,62 @@ var errWrongUsernamePassword = helios.ErrorForm{
 	NonFieldError: helios.ErrorFormFieldAtomic{"Wrong username / password"},
 }
 
-var errSessionLocked = helios.ErrorForm{
-	Code:          "session_locked",
+var errSessionLimitReached = helios.ErrorForm{
+	Code:          "session_limit_reached",
 	NonFieldError: helios.ErrorFormFieldAtomic{"You have already login from other device"},
 }
 
+var errWrongPassword = helios.ErrorForm{
+	Code:          "wrong_password",
+	NonFieldError: helios.ErrorFormFieldAtomic{"Wrong password"},
+}
+
+var errPasswordNotChanged = helios.ErrorForm{
+	Code:          "password_not_changed",
+	NonFieldError: helios.ErrorFormFieldAtomic{"New password should be different from the old password"},
+}
+
+var errWrongTwoFactorCode = helios.ErrorForm{
+	Code:          "wrong_two_factor_code",
+	NonFieldError: helios.ErrorFormFieldAtomic{"Wrong authentication code"},
+}
+
+var errLoginThrottled = helios.ErrorAPI{
+	StatusCode: http.StatusTooManyRequests,
+	Code:       "login_throttled",
+	Message:    "Too many failed login attempts, try again in %d seconds",
+}
+
+var errLoginLocked = helios.ErrorAPI{
+	StatusCode: http.StatusTooManyRequests,
+	Code:       "login_locked",
+	Message:    "Too many failed login attempts, the account is locked. Ask the proctor to unlock it",
+}
+
+var errLoginAttemptAccessNotAuthorized = helios.ErrorAPI{
+	StatusCode: http.StatusForbidden,
+	Code:       "login_attempt_forbidden",
+	Message:    "User role doesn't have permission to access login attempts",
+}
+
+var errUserChangeNotAuthorized = helios.ErrorAPI{
+	StatusCode: http.StatusForbidden,
+	Code:       "not_authorized_edit_user",
+	Message:    "User role doesn't have permission to manage users",
+}
+
+var errSessionExpired = helios.ErrorAPI{
+	StatusCode: http.StatusUnauthorized,
+	Code:       "session_expired",
+	Message:    "Your session has expired, please log in again",
+}
+
+var errSessionNotFound = helios.ErrorAPI{
+	StatusCode: http.StatusNotFound,
+	Code:       "session_not_found",
+	Message:    "No session with given ID",
+}
+
 var errUnauthorized = helios.ErrorAPI{
 	StatusCode: http.StatusUnauthorized,
 	Code:       "unauthorized",
@@ -57,3 +206,87 @@ var errUserNotFound = helios.ErrorAPI{
 	Code:       "user_not_found",
 	Message:    "User not found",
 }
+
+var errPasswordChangeRequired = helios.ErrorAPI{
+	StatusCode: http.StatusForbidden,
+	Code:       "password_change_required",
+	Message:    "You have to change your password first",
+}
+
+var errPasswordResetTokenInvalid = helios.ErrorAPI{
+	StatusCode: http.StatusBadRequest,
+	Code:       "password_reset_token_invalid",
+	Message:    "The reset token is invalid, has been used, or has expired",
+}
+
+var errTwoFactorRequired = helios.ErrorAPI{
+	StatusCode: http.StatusUnauthorized,
+	Code:       "two_factor_required",
+	Message:    "You need to enter the authentication code first",
+}
+
+var errTwoFactorEnrollmentRequired = helios.ErrorAPI{
+	StatusCode: http.StatusForbidden,
+	Code:       "two_factor_enrollment_required",
+	Message:    "You have to set up two-factor authentication first",
+}
+
+var errTwoFactorNotPending = helios.ErrorAPI{
+	StatusCode: http.StatusBadRequest,
+	Code:       "two_factor_not_pending",
+	Message:    "The session doesn't need authentication code",
+}
+
+var errTwoFactorAlreadyEnabled = helios.ErrorAPI{
+	StatusCode: http.StatusBadRequest,
+	Code:       "two_factor_already_enabled",
+	Message:    "Two-factor authentication is already enabled",
+}
+
+var errTwoFactorNotEnrolled = helios.ErrorAPI{
+	StatusCode: http.StatusBadRequest,
+	Code:       "two_factor_not_enrolled",
+	Message:    "Two-factor authentication is not yet enrolled",
+}
+
+var errTwoFactorNotEnabled = helios.ErrorAPI{
+	StatusCode: http.StatusBadRequest,
+	Code:       "two_factor_not_enabled",
+	Message:    "Two-factor authentication is not enabled",
+}
+
+var errTwoFactorRequiredByPolicy = helios.ErrorAPI{
+	StatusCode: http.StatusForbidden,
+	Code:       "two_factor_required_by_policy",
+	Message:    "Two-factor authentication can't be disabled for your role",
+}
+
+var errAPITokenInvalid = helios.ErrorAPI{
+	StatusCode: http.StatusUnauthorized,
+	Code:       "api_token_invalid",
+	Message:    "The API token is invalid, has been revoked, or has expired",
+}
+
+var errAPITokenOutOfScope = helios.ErrorAPI{
+	StatusCode: http.StatusForbidden,
+	Code:       "api_token_out_of_scope",
+	Message:    "The API token is not allowed on this event",
+}
+
+var errAPITokenIssueNotAuthorized = helios.ErrorAPI{
+	StatusCode: http.StatusForbidden,
+	Code:       "not_authorized_issue_api_token",
+	Message:    "User role doesn't have permission to issue API tokens",
+}
+
+var errAPITokenNotFound = helios.ErrorAPI{
+	StatusCode: http.StatusNotFound,
+	Code:       "api_token_not_found",
+	Message:    "No API token with given ID",
+}
+
+var errAuditLogAccessNotAuthorized = helios.ErrorAPI{
+	StatusCode: http.StatusForbidden,
+	Code:       "audit_log_forbidden",
+	Message:    "User role doesn't have permission to access audit log",
+}
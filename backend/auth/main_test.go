@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/yonasadiel/charon/backend/config"
+)
+
+func TestMain(m *testing.M) {
+	teardown, err := config.SetupTestDatabase()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	var code int = m.Run()
+	teardown()
+	os.Exit(code)
+}
@@ -1,15 +1,70 @@
 package auth
 
 import (
+	"strings"
+	"time"
+
 	"github.com/yonasadiel/helios"
+
+	"github.com/yonasadiel/charon/backend/logging"
+)
+
+// loginStage is how far the session has gone through the login. The
+// stages are ordered, a handler accepts the sessions on its stage or later.
+type loginStage int
+
+const (
+	// loginStageTwoFactor is the session waiting for the TOTP code
+	loginStageTwoFactor loginStage = iota
+	// loginStageAccountSetup is the session of user that has to change the
+	// password or enroll two-factor authentication
+	loginStageAccountSetup
+	// loginStageComplete is the session that can access everything
+	loginStageComplete
 )
 
+// getLoginStage returns the stage of the session, and the error that
+// prevents the session from reaching the next stage
+func getLoginStage(session Session) (loginStage, helios.Error) {
+	if session.TwoFactorPending {
+		return loginStageTwoFactor, errTwoFactorRequired
+	}
+	if session.User.MustChangePassword {
+		return loginStageAccountSetup, errPasswordChangeRequired
+	}
+	if TwoFactorRequiredRoles[session.User.Role] && !session.User.TOTPEnabled {
+		return loginStageAccountSetup, errTwoFactorEnrollmentRequired
+	}
+	return loginStageComplete, nil
+}
+
 // LoggedInMiddleware check whether user is authenticated or not
-// and send errUnauthorized when user is not logged in.
+// and send errUnauthorized when user is not logged in. Expired
+// session is removed and errSessionExpired is sent. Session waiting
+// for TOTP code gets errTwoFactorRequired, and user that must change
+// the password or enroll two-factor authentication gets the error of it.
 func LoggedInMiddleware(f helios.HTTPHandler) helios.HTTPHandler {
+	return loggedIn(f, loginStageComplete)
+}
+
+// AccountSetupMiddleware is LoggedInMiddleware that also lets the user
+// that must change the password or enroll two-factor authentication
+// through. Used on changing password and enrolling two-factor.
+func AccountSetupMiddleware(f helios.HTTPHandler) helios.HTTPHandler {
+	return loggedIn(f, loginStageAccountSetup)
+}
+
+// LoginPendingMiddleware is LoggedInMiddleware that also lets the session
+// waiting for TOTP code through. Used on verifying the code and logging out.
+func LoginPendingMiddleware(f helios.HTTPHandler) helios.HTTPHandler {
+	return loggedIn(f, loginStageTwoFactor)
+}
+
+func loggedIn(f helios.HTTPHandler, minStage loginStage) helios.HTTPHandler {
 	return func(req helios.Request) {
 		var userToken string
 		var userSession Session
+		var now time.Time = time.Now()
 
 		userToken, _ = req.GetSessionData(UserTokenSessionKey).(string)
 
@@ -18,18 +73,127 @@ func LoggedInMiddleware(f helios.HTTPHandler) helios.HTTPHandler {
 			return
 		}
 
-		helios.DB.
+		var requestID string = req.GetHeader(logging.RequestIDHeader)
+		errDB := logging.CheckDB(requestID, helios.DB.
 			Where("token = ?", userToken).
 			Where("ip_address = ?", req.ClientIP()).
 			Preload("User").
-			First(&userSession)
+			First(&userSession))
+		if errDB != nil {
+			req.SendJSON(errDB.GetMessage(), errDB.GetStatusCode())
+			return
+		}
 
 		if userSession.ID == 0 {
 			req.SendJSON(errUnauthorized.GetMessage(), errUnauthorized.GetStatusCode())
 			return
 		}
 
+		if userSession.IsExpired(now) {
+			logging.CheckDB(requestID, helios.DB.Delete(&userSession))
+			req.SendJSON(errSessionExpired.GetMessage(), errSessionExpired.GetStatusCode())
+			return
+		}
+
+		if now.Sub(userSession.LastSeenAt) > sessionLastSeenInterval {
+			userSession.LastSeenAt = now
+			logging.CheckDB(requestID, helios.DB.Model(&userSession).UpdateColumn("last_seen_at", now))
+		}
+
+		if stage, errStage := getLoginStage(userSession); stage < minStage {
+			req.SendJSON(errStage.GetMessage(), errStage.GetStatusCode())
+			return
+		}
+
+		userSession.User.IPAddress = req.ClientIP()
+		userSession.User.RequestID = requestID
+		logging.SetUserID(requestID, userSession.User.ID)
 		req.SetContextData(UserContextKey, *userSession.User)
+		req.SetContextData(SessionContextKey, userSession)
 		f(req)
 	}
 }
+
+// APITokenMiddleware accepts the request with API token on the Authorization
+// header ("Bearer <token>"), so the local server can call the central server
+// without cookie session. The token is only accepted on the event of eventSlug
+// URL param, resolved to its ID by eventIDOfSlug, if the event is within
+// the scopes of the token. The requests are logged as APITokenUsage, including
+// the ones with unknown, revoked or expired token, so the attempts of a leaked
+// or stale token can be traced by its prefix. Request without the header falls
+// back to LoggedInMiddleware for the browsers.
+func APITokenMiddleware(eventIDOfSlug func(eventSlug string) uint) helios.Middleware {
+	return func(f helios.HTTPHandler) helios.HTTPHandler {
+		var loggedInHandler helios.HTTPHandler = LoggedInMiddleware(f)
+		return func(req helios.Request) {
+			var authorization string = req.GetHeader("Authorization")
+			var apiToken APIToken
+			var now time.Time = time.Now()
+			var requestID string = req.GetHeader(logging.RequestIDHeader)
+
+			if authorization == "" {
+				loggedInHandler(req)
+				return
+			}
+
+			if !strings.HasPrefix(authorization, "Bearer ") {
+				req.SendJSON(errAPITokenInvalid.GetMessage(), errAPITokenInvalid.GetStatusCode())
+				return
+			}
+
+			var token string = strings.TrimSpace(strings.TrimPrefix(authorization, "Bearer "))
+			errDB := logging.CheckDB(requestID, helios.DB.
+				Where("token_hashed = ?", hashToken(token)).
+				Preload("User").
+				Preload("Scopes").
+				First(&apiToken))
+			if errDB != nil {
+				req.SendJSON(errDB.GetMessage(), errDB.GetStatusCode())
+				return
+			}
+
+			var eventID uint = eventIDOfSlug(req.GetURLParam("eventSlug"))
+			var usage APITokenUsage = APITokenUsage{
+				APITokenID: apiToken.ID,
+				Prefix:     apiToken.Prefix,
+				EventID:    eventID,
+				IPAddress:  req.ClientIP(),
+				Result:     APITokenUsageAccepted,
+			}
+			if apiToken.ID == 0 {
+				usage.Prefix = tokenPrefix(token)
+				usage.Result = APITokenUsageInvalid
+			} else if apiToken.RevokedAt != nil {
+				usage.Result = APITokenUsageRevoked
+			} else if apiToken.IsExpired(now) {
+				usage.Result = APITokenUsageExpired
+			} else if apiToken.User == nil || !apiToken.User.IsLocal() {
+				usage.Result = APITokenUsageInvalid
+			}
+			if usage.Result != APITokenUsageAccepted {
+				logging.CheckDB(requestID, helios.DB.Create(&usage))
+				req.SendJSON(errAPITokenInvalid.GetMessage(), errAPITokenInvalid.GetStatusCode())
+				return
+			}
+
+			if eventID == 0 || !apiToken.HasScope(eventID) {
+				usage.Result = APITokenUsageOutOfScope
+				logging.CheckDB(requestID, helios.DB.Create(&usage))
+				req.SendJSON(errAPITokenOutOfScope.GetMessage(), errAPITokenOutOfScope.GetStatusCode())
+				return
+			}
+			if errDB := logging.CheckDB(requestID, helios.DB.Create(&usage)); errDB != nil {
+				req.SendJSON(errDB.GetMessage(), errDB.GetStatusCode())
+				return
+			}
+			logging.CheckDB(requestID, helios.DB.Model(&apiToken).UpdateColumn("last_used_at", now))
+
+			apiToken.User.IPAddress = usage.IPAddress
+			apiToken.User.RequestID = requestID
+			logging.SetUserID(requestID, apiToken.User.ID)
+			req.SetContextData(UserContextKey, *apiToken.User)
+			req.SetContextData(APITokenContextKey, apiToken)
+			f(req)
+		}
+	}
+}
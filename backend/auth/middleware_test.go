@@ -1,8 +1,10 @@
 package auth
 
 import (
+	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/yonasadiel/helios"
@@ -18,6 +20,7 @@ func TestLoggedInMiddleware(t *testing.T) {
 	}
 	var wrappedHandler helios.HTTPHandler = LoggedInMiddleware(blankHandler)
 	helios.DB.Create(&Session{Token: token, UserID: user.ID, IPAddress: "7.1.1.1"})
+	helios.DB.Create(&Session{Token: "expired_token", UserID: user.ID, IPAddress: "7.1.1.1", CreatedAt: time.Now().Add(-SessionMaxAge - time.Minute)})
 	type loggedInMiddlewareTestCase struct {
 		sessionToken       string
 		expectedStatusCode int
@@ -32,6 +35,10 @@ func TestLoggedInMiddleware(t *testing.T) {
 		sessionToken:       token,
 		expectedStatusCode: errUnauthorized.StatusCode,
 		remoteAddr:         "7.1.1.2",
+	}, {
+		sessionToken:       "expired_token",
+		expectedStatusCode: errSessionExpired.StatusCode,
+		remoteAddr:         "7.1.1.1",
 	}, {
 		sessionToken:       token,
 		expectedStatusCode: http.StatusOK,
@@ -51,6 +58,215 @@ func TestLoggedInMiddleware(t *testing.T) {
 			userReturned, successCoversion := req.GetContextData(UserTokenSessionKey).(User)
 			assert.True(t, successCoversion, "Failed to convert user in context data to user object")
 			assert.Equal(t, user.ID, userReturned.ID, "User object should be on the context data")
+			assert.Equal(t, testCase.remoteAddr, userReturned.IPAddress, "IP address should be set to be recorded on audit log")
+			sessionReturned, successCoversion := req.GetContextData(SessionContextKey).(Session)
+			assert.True(t, successCoversion, "Failed to convert session in context data to session object")
+			assert.Equal(t, token, sessionReturned.Token, "Session object should be on the context data")
+			assert.False(t, sessionReturned.LastSeenAt.IsZero(), "Last seen time should be updated")
+		}
+	}
+}
+
+func TestLoginStageMiddleware(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userMustChangePassword User = UserFactorySaved(User{MustChangePassword: true})
+	var userAdmin User = UserFactorySaved(User{Role: UserRoleAdmin})
+	var userAdminEnrolled User = UserFactorySaved(User{Role: UserRoleAdmin, TOTPEnabled: true})
+	var blankHandler = func(req helios.Request) {
+		req.SendJSON("OK", http.StatusOK)
+	}
+	helios.DB.Create(&Session{Token: "token_password", UserID: userMustChangePassword.ID, IPAddress: "7.1.1.1"})
+	helios.DB.Create(&Session{Token: "token_enroll", UserID: userAdmin.ID, IPAddress: "7.1.1.1"})
+	helios.DB.Create(&Session{Token: "token_pending", UserID: userAdminEnrolled.ID, IPAddress: "7.1.1.1", TwoFactorPending: true})
+	helios.DB.Create(&Session{Token: "token_complete", UserID: userAdminEnrolled.ID, IPAddress: "7.1.1.1"})
+	type loginStageMiddlewareTestCase struct {
+		middleware         helios.Middleware
+		sessionToken       string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []loginStageMiddlewareTestCase{{
+		middleware:         LoggedInMiddleware,
+		sessionToken:       "token_password",
+		expectedStatusCode: errPasswordChangeRequired.StatusCode,
+		expectedErrorCode:  errPasswordChangeRequired.Code,
+	}, {
+		middleware:         AccountSetupMiddleware,
+		sessionToken:       "token_password",
+		expectedStatusCode: http.StatusOK,
+	}, {
+		middleware:         AccountSetupMiddleware,
+		sessionToken:       "unknown_token",
+		expectedStatusCode: errUnauthorized.StatusCode,
+		expectedErrorCode:  errUnauthorized.Code,
+	}, {
+		middleware:         LoggedInMiddleware,
+		sessionToken:       "token_enroll",
+		expectedStatusCode: errTwoFactorEnrollmentRequired.StatusCode,
+		expectedErrorCode:  errTwoFactorEnrollmentRequired.Code,
+	}, {
+		middleware:         AccountSetupMiddleware,
+		sessionToken:       "token_enroll",
+		expectedStatusCode: http.StatusOK,
+	}, {
+		middleware:         AccountSetupMiddleware,
+		sessionToken:       "token_pending",
+		expectedStatusCode: errTwoFactorRequired.StatusCode,
+		expectedErrorCode:  errTwoFactorRequired.Code,
+	}, {
+		middleware:         LoginPendingMiddleware,
+		sessionToken:       "token_pending",
+		expectedStatusCode: http.StatusOK,
+	}, {
+		middleware:         LoggedInMiddleware,
+		sessionToken:       "token_complete",
+		expectedStatusCode: http.StatusOK,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test LoginStageMiddleware testcase: %d", i)
+		var req helios.MockRequest
+
+		req = helios.NewMockRequest()
+		req.SetSessionData(UserTokenSessionKey, testCase.sessionToken)
+		req.RemoteAddr = "7.1.1.1"
+		testCase.middleware(blankHandler)(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestAPITokenMiddleware(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userLocal User = UserFactorySaved(User{Role: UserRoleLocal})
+	var userParticipant User = UserFactorySaved(User{Role: UserRoleParticipant})
+	var expiredAt time.Time = time.Now().Add(-time.Minute)
+	var apiToken APIToken = APIToken{UserID: userLocal.ID, Prefix: "token1", TokenHashed: hashToken("token1"), Scopes: []APITokenScope{{EventID: 1}}}
+	var apiTokenExpired APIToken = APIToken{UserID: userLocal.ID, Prefix: "expired", TokenHashed: hashToken("expired"), ExpiresAt: &expiredAt, Scopes: []APITokenScope{{EventID: 1}}}
+	var apiTokenRevoked APIToken = APIToken{UserID: userLocal.ID, Prefix: "revoked", TokenHashed: hashToken("revoked"), RevokedAt: &expiredAt, Scopes: []APITokenScope{{EventID: 1}}}
+	var apiTokenParticipant APIToken = APIToken{UserID: userParticipant.ID, Prefix: "particip", TokenHashed: hashToken("participant"), Scopes: []APITokenScope{{EventID: 1}}}
+	helios.DB.Create(&apiToken)
+	helios.DB.Create(&apiTokenExpired)
+	helios.DB.Create(&apiTokenRevoked)
+	helios.DB.Create(&apiTokenParticipant)
+	helios.DB.Create(&Session{Token: "session1", UserID: userLocal.ID, IPAddress: "7.1.1.1"})
+	var eventIDOfSlug = func(eventSlug string) uint {
+		return map[string]uint{"event-1": 1, "event-2": 2}[eventSlug]
+	}
+	var blankHandler = func(req helios.Request) {
+		req.SendJSON("OK", http.StatusOK)
+	}
+	var wrappedHandler helios.HTTPHandler = APITokenMiddleware(eventIDOfSlug)(blankHandler)
+	type apiTokenMiddlewareTestCase struct {
+		authorization           string
+		sessionToken            string
+		eventSlug               string
+		expectedStatusCode      int
+		expectedErrorCode       string
+		expectedUsageResult     string
+		expectedUsageAPITokenID uint
+		expectedUsagePrefix     string
+	}
+	testCases := []apiTokenMiddlewareTestCase{{
+		authorization:       "Bearer random-token",
+		eventSlug:           "event-1",
+		expectedStatusCode:  errAPITokenInvalid.StatusCode,
+		expectedErrorCode:   errAPITokenInvalid.Code,
+		expectedUsageResult: APITokenUsageInvalid,
+		expectedUsagePrefix: "random-t",
+	}, {
+		authorization:      "token1",
+		eventSlug:          "event-1",
+		expectedStatusCode: errAPITokenInvalid.StatusCode,
+		expectedErrorCode:  errAPITokenInvalid.Code,
+	}, {
+		authorization:           "Bearer expired",
+		eventSlug:               "event-1",
+		expectedStatusCode:      errAPITokenInvalid.StatusCode,
+		expectedErrorCode:       errAPITokenInvalid.Code,
+		expectedUsageResult:     APITokenUsageExpired,
+		expectedUsageAPITokenID: apiTokenExpired.ID,
+		expectedUsagePrefix:     "expired",
+	}, {
+		authorization:           "Bearer revoked",
+		eventSlug:               "event-1",
+		expectedStatusCode:      errAPITokenInvalid.StatusCode,
+		expectedErrorCode:       errAPITokenInvalid.Code,
+		expectedUsageResult:     APITokenUsageRevoked,
+		expectedUsageAPITokenID: apiTokenRevoked.ID,
+		expectedUsagePrefix:     "revoked",
+	}, {
+		authorization:           "Bearer participant",
+		eventSlug:               "event-1",
+		expectedStatusCode:      errAPITokenInvalid.StatusCode,
+		expectedErrorCode:       errAPITokenInvalid.Code,
+		expectedUsageResult:     APITokenUsageInvalid,
+		expectedUsageAPITokenID: apiTokenParticipant.ID,
+		expectedUsagePrefix:     "particip",
+	}, {
+		authorization:           "Bearer token1",
+		eventSlug:               "event-2",
+		expectedStatusCode:      errAPITokenOutOfScope.StatusCode,
+		expectedErrorCode:       errAPITokenOutOfScope.Code,
+		expectedUsageResult:     APITokenUsageOutOfScope,
+		expectedUsageAPITokenID: apiToken.ID,
+		expectedUsagePrefix:     "token1",
+	}, {
+		authorization:           "Bearer token1",
+		eventSlug:               "event-1",
+		expectedStatusCode:      http.StatusOK,
+		expectedUsageResult:     APITokenUsageAccepted,
+		expectedUsageAPITokenID: apiToken.ID,
+		expectedUsagePrefix:     "token1",
+	}, {
+		sessionToken:       "session1",
+		eventSlug:          "event-2",
+		expectedStatusCode: http.StatusOK,
+	}, {
+		eventSlug:          "event-1",
+		expectedStatusCode: errUnauthorized.StatusCode,
+		expectedErrorCode:  errUnauthorized.Code,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test APITokenMiddleware testcase: %d", i)
+		var req helios.MockRequest
+		var usageCountBefore, usageCountAfter int
+
+		helios.DB.Model(APITokenUsage{}).Count(&usageCountBefore)
+		req = helios.NewMockRequest()
+		req.RequestHeader["authorization"] = testCase.authorization
+		req.SetSessionData(UserTokenSessionKey, testCase.sessionToken)
+		req.URLParam["eventSlug"] = testCase.eventSlug
+		req.RemoteAddr = "7.1.1.1"
+		wrappedHandler(&req)
+		helios.DB.Model(APITokenUsage{}).Count(&usageCountAfter)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+		if testCase.expectedUsageResult != "" {
+			var usage APITokenUsage
+			helios.DB.Order("id desc").First(&usage)
+			assert.Equal(t, usageCountBefore+1, usageCountAfter)
+			assert.Equal(t, testCase.expectedUsageAPITokenID, usage.APITokenID)
+			assert.Equal(t, testCase.expectedUsagePrefix, usage.Prefix)
+			assert.Equal(t, testCase.expectedUsageResult, usage.Result)
+		} else {
+			assert.Equal(t, usageCountBefore, usageCountAfter)
+		}
+		if testCase.expectedStatusCode == http.StatusOK {
+			userReturned, _ := req.GetContextData(UserContextKey).(User)
+			assert.Equal(t, userLocal.ID, userReturned.ID)
+			assert.Equal(t, "7.1.1.1", userReturned.IPAddress)
 		}
 	}
 }
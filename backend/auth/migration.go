@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/yonasadiel/charon/backend/migration"
+)
+
+// Migrations is the schema migrations of auth models
+var Migrations = []migration.Migration{
+	{
+		Version: 2026101901,
+		Name:    "create auth tables",
+		Up: func(db *gorm.DB) error {
+			return migration.CreateTables(db, initialTables)
+		},
+		Down: func(db *gorm.DB) error {
+			return migration.DropTables(db, initialTables)
+		},
+	},
+	{
+		Version: 2026101906,
+		Name:    "add prefix to api token usages",
+		Up: func(db *gorm.DB) error {
+			// the column exists if the tables are created by the first migration
+			// of the earlier servers, which migrated the models of their version
+			if db.Dialect().HasColumn("api_token_usages", "prefix") {
+				return nil
+			}
+			if err := db.Exec("ALTER TABLE api_token_usages ADD COLUMN prefix varchar(8)").Error; err != nil {
+				return err
+			}
+			return db.Table("api_token_usages").AddIndex("idx_api_token_usages_prefix", "prefix").Error
+		},
+		Down: func(db *gorm.DB) error {
+			if err := db.Table("api_token_usages").RemoveIndex("idx_api_token_usages_prefix").Error; err != nil {
+				return err
+			}
+			return db.Exec("ALTER TABLE api_token_usages DROP COLUMN prefix").Error
+		},
+	},
+	{
+		Version: 2026101907,
+		Name:    "add unique index on audit log previous hash",
+		Up: func(db *gorm.DB) error {
+			if db.Dialect().HasIndex("audit_logs", "uix_audit_logs_prev_hash") {
+				return nil
+			}
+			return db.Table("audit_logs").AddUniqueIndex("uix_audit_logs_prev_hash", "prev_hash").Error
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Table("audit_logs").RemoveIndex("uix_audit_logs_prev_hash").Error
+		},
+	},
+	{
+		Version: 2026101909,
+		Name:    "widen ip address columns for ipv6",
+		Up: func(db *gorm.DB) error {
+			return modifyIPAddressColumns(db, "varchar(45)")
+		},
+		Down: func(db *gorm.DB) error {
+			return modifyIPAddressColumns(db, "varchar(20)")
+		},
+	},
+}
+
+// initialTables is the auth tables created by the first migration. The tables
+// are kept apart from the models, so the migration doesn't change with them.
+var initialTables = []migration.Table{
+	{Name: "users", Model: &struct {
+		ID                 uint   `gorm:"primary_key"`
+		Name               string `gorm:"size:256"`
+		Username           string `gorm:"size:256; unique"`
+		Password           string `gorm:"size:256"`
+		Role               uint
+		MustChangePassword bool   `gorm:"default:false"`
+		TOTPSecret         string `gorm:"column:totp_secret;size:64"`
+		TOTPEnabled        bool   `gorm:"column:totp_enabled;default:false"`
+		TOTPLastCounter    int64  `gorm:"column:totp_last_counter"`
+		CreatedAt          time.Time
+		UpdatedAt          time.Time
+		DeletedAt          *time.Time
+	}{}},
+	{Name: "sessions", Model: &struct {
+		ID               uint `gorm:"primary_key"`
+		UserID           uint
+		Token            string `gorm:"size:64;unique"`
+		IPAddress        string `gorm:"size:20"`
+		LastSeenAt       time.Time
+		TwoFactorPending bool `gorm:"default:false"`
+		CreatedAt        time.Time
+		UpdatedAt        time.Time
+		DeletedAt        *time.Time
+	}{}},
+	{Name: "login_attempts", Model: &struct {
+		ID        uint   `gorm:"primary_key"`
+		Username  string `gorm:"size:256;index"`
+		IPAddress string `gorm:"size:20;index"`
+		Result    string `gorm:"size:16"`
+		Cleared   bool   `gorm:"default:false"`
+		CreatedAt time.Time
+		UpdatedAt time.Time
+		DeletedAt *time.Time
+	}{}},
+	{Name: "event_roles", Model: &struct {
+		ID        uint `gorm:"primary_key"`
+		UserID    uint `gorm:"index"`
+		EventID   uint `gorm:"index"`
+		VenueID   uint
+		Role      string `gorm:"size:16"`
+		CreatedAt time.Time
+		UpdatedAt time.Time
+		DeletedAt *time.Time
+	}{}},
+	{Name: "password_reset_tokens", Model: &struct {
+		ID          uint   `gorm:"primary_key"`
+		UserID      uint   `gorm:"index"`
+		TokenHashed string `gorm:"size:64;unique"`
+		ExpiresAt   time.Time
+		UsedAt      *time.Time
+		CreatedAt   time.Time
+		UpdatedAt   time.Time
+		DeletedAt   *time.Time
+	}{}},
+	{Name: "recovery_codes", Model: &struct {
+		ID         uint   `gorm:"primary_key"`
+		UserID     uint   `gorm:"index"`
+		CodeHashed string `gorm:"size:64"`
+		UsedAt     *time.Time
+		CreatedAt  time.Time
+		UpdatedAt  time.Time
+		DeletedAt  *time.Time
+	}{}},
+	{Name: "api_tokens", Model: &struct {
+		ID          uint   `gorm:"primary_key"`
+		UserID      uint   `gorm:"index"`
+		Name        string `gorm:"size:64"`
+		Prefix      string `gorm:"size:8"`
+		TokenHashed string `gorm:"size:64;unique"`
+		ExpiresAt   *time.Time
+		LastUsedAt  *time.Time
+		RevokedAt   *time.Time
+		CreatedAt   time.Time
+		UpdatedAt   time.Time
+		DeletedAt   *time.Time
+	}{}},
+	{Name: "api_token_scopes", Model: &struct {
+		ID         uint `gorm:"primary_key"`
+		APITokenID uint `gorm:"index"`
+		EventID    uint
+		CreatedAt  time.Time
+		UpdatedAt  time.Time
+		DeletedAt  *time.Time
+	}{}},
+	{Name: "api_token_usages", Model: &struct {
+		ID         uint   `gorm:"primary_key"`
+		APITokenID uint   `gorm:"index"`
+		EventID    uint   `gorm:"index"`
+		IPAddress  string `gorm:"size:20"`
+		Result     string `gorm:"size:16"`
+		CreatedAt  time.Time
+		UpdatedAt  time.Time
+		DeletedAt  *time.Time
+	}{}},
+	{Name: "audit_logs", Model: &struct {
+		ID            uint   `gorm:"primary_key"`
+		ActorID       uint   `gorm:"index"`
+		ActorUsername string `gorm:"size:256"`
+		IPAddress     string `gorm:"size:20"`
+		Action        string `gorm:"size:64;index"`
+		TargetType    string `gorm:"size:32;index"`
+		TargetID      uint
+		Before        string `gorm:"type:text"`
+		After         string `gorm:"type:text"`
+		PrevHash      string `gorm:"size:64"`
+		Hash          string `gorm:"size:64;unique"`
+		CreatedAt     time.Time
+	}{}},
+}
+
+// ipAddressTables is the tables that have ip_address column
+var ipAddressTables = []string{"sessions", "login_attempts", "api_token_usages", "audit_logs"}
+
+// modifyIPAddressColumns changes the type of ip_address columns. It does
+// nothing on sqlite3, because sqlite3 can't alter the column and doesn't
+// enforce the length of varchar anyway.
+func modifyIPAddressColumns(db *gorm.DB, typ string) error {
+	if db.Dialect().GetName() == "sqlite3" {
+		return nil
+	}
+	for _, table := range ipAddressTables {
+		if err := db.Dialect().ModifyColumn(table, "ip_address", typ); err != nil {
+			return err
+		}
+	}
+	return nil
+}
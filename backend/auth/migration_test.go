@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yonasadiel/helios"
+
+	"github.com/yonasadiel/charon/backend/migration"
+)
+
+func TestModifyIPAddressColumns(t *testing.T) {
+	helios.App.BeforeTest()
+
+	// the tables are created by the models, so the migrations adopt them, and
+	// the last migration is reverted and applied again to widen the columns
+	assert.Nil(t, migration.Up(helios.DB, Migrations))
+	assert.Nil(t, migration.Down(helios.DB, Migrations))
+	assert.Nil(t, migration.Up(helios.DB, Migrations))
+	assert.Nil(t, migration.Check(helios.DB, Migrations))
+
+	var ipAddress string = "0000:0000:0000:0000:0000:ffff:192.168.100.228"
+	var user User = UserFactorySaved(User{})
+	var session Session = Session{UserID: user.ID, Token: "token", IPAddress: ipAddress}
+	var loginAttempt LoginAttempt = LoginAttempt{Username: user.Username, IPAddress: ipAddress}
+	var apiTokenUsage APITokenUsage = APITokenUsage{IPAddress: ipAddress}
+	var auditLog AuditLog = AuditLog{IPAddress: ipAddress, Hash: "hash"}
+	for _, model := range []interface{}{&session, &loginAttempt, &apiTokenUsage, &auditLog} {
+		assert.Nil(t, helios.DB.Create(model).Error, "IPv6 address should fit the column of %T", model)
+	}
+	var sessionSaved Session
+	var loginAttemptSaved LoginAttempt
+	var apiTokenUsageSaved APITokenUsage
+	var auditLogSaved AuditLog
+	helios.DB.First(&sessionSaved, session.ID)
+	helios.DB.First(&loginAttemptSaved, loginAttempt.ID)
+	helios.DB.First(&apiTokenUsageSaved, apiTokenUsage.ID)
+	helios.DB.First(&auditLogSaved, auditLog.ID)
+	assert.Equal(t, ipAddress, sessionSaved.IPAddress)
+	assert.Equal(t, ipAddress, loginAttemptSaved.IPAddress)
+	assert.Equal(t, ipAddress, apiTokenUsageSaved.IPAddress)
+	assert.Equal(t, ipAddress, auditLogSaved.IPAddress)
+}
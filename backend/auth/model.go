@@ -11,25 +11,44 @@ import (
 // - "participant": user that taking the exam.
 // - "admin": administrator of applicaton.
 // - "organizer": writer of problems, etc.
+// MustChangePassword is true if the password is given by other user,
+// so the user has to change it before doing anything else.
+// TOTPSecret is set on two-factor enrollment, but it is only checked on
+// login after the user confirms it with a code and TOTPEnabled is true.
+// TOTPLastCounter is the time step of the last accepted code, so the same
+// code can't be used twice. IPAddress is not stored, it is the address of
+// the request made by the user, set by the middlewares to be recorded on
+// the audit log. RequestID is not stored either, it is the ID of the request
+// to be logged with the failures of the request.
 type User struct {
-	ID            uint   `gorm:"primary_key"`
-	Name          string `gorm:"size:256"`
-	Username      string `gorm:"size:256; unique"`
-	Password      string `gorm:"size:256"`
-	Role          uint   // default to participant
-	SessionLocked bool   `gorm:"default:false"`
+	ID                 uint   `gorm:"primary_key"`
+	Name               string `gorm:"size:256"`
+	Username           string `gorm:"size:256; unique"`
+	Password           string `gorm:"size:256"`
+	Role               uint   // default to participant
+	MustChangePassword bool   `gorm:"default:false"`
+	TOTPSecret         string `gorm:"column:totp_secret;size:64"`
+	TOTPEnabled        bool   `gorm:"column:totp_enabled;default:false"`
+	TOTPLastCounter    int64  `gorm:"column:totp_last_counter"`
+	IPAddress          string `gorm:"-" json:"-"`
+	RequestID          string `gorm:"-" json:"-"`
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt *time.Time
 }
 
-// Session of user logged in
+// Session of user logged in. LastSeenAt is the time of the last
+// request made with the session, zero if it is never used.
+// TwoFactorPending is true if the password is verified, but the session
+// is waiting for the TOTP code.
 type Session struct {
-	ID        uint `gorm:"primary_key"`
-	UserID    uint
-	Token     string `gorm:"size:20;unique"`
-	IPAddress string `gorm:"size:20"`
+	ID               uint `gorm:"primary_key"`
+	UserID           uint
+	Token            string `gorm:"size:64;unique"`
+	IPAddress        string `gorm:"size:45"`
+	LastSeenAt       time.Time
+	TwoFactorPending bool `gorm:"default:false"`
 
 	User *User `gorm:"foreignkey:user_id"`
 
@@ -38,9 +57,191 @@ type Session struct {
 	DeletedAt *time.Time
 }
 
+// LoginAttempt is a record of login, used to throttle brute-force attack
+// and to be monitored by the proctor. Cleared is true if the failed attempt
+// is no longer counted, because the user has logged in successfully or the
+// proctor has unlocked the user.
+type LoginAttempt struct {
+	ID        uint   `gorm:"primary_key"`
+	Username  string `gorm:"size:256;index"`
+	IPAddress string `gorm:"size:45;index"`
+	Result    string `gorm:"size:16"`
+	Cleared   bool   `gorm:"default:false"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+// EventRole is an assignment of user to a role on an event. The role
+// grants the actions in EventRolePermissions on the event. VenueID limits
+// the assignment to a venue, zero means all venues of the event. The event
+// is referred by its ID only, because auth doesn't know about exam.
+type EventRole struct {
+	ID      uint `gorm:"primary_key"`
+	UserID  uint `gorm:"index"`
+	EventID uint `gorm:"index"`
+	VenueID uint
+	Role    string `gorm:"size:16"`
+
+	User *User `gorm:"foreignkey:UserID;association_autoupdate:false"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+// PasswordResetToken is a one-time token to reset the password of a user,
+// issued by the admin. Only the hash of the token is stored. UsedAt is
+// nil until the token is used.
+type PasswordResetToken struct {
+	ID          uint   `gorm:"primary_key"`
+	UserID      uint   `gorm:"index"`
+	TokenHashed string `gorm:"size:64;unique"`
+	ExpiresAt   time.Time
+	UsedAt      *time.Time
+
+	User *User `gorm:"foreignkey:UserID;association_autoupdate:false"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+// RecoveryCode is a one-time code to log in when the authenticator app is
+// not available. Only the hash of the code is stored. UsedAt is nil until
+// the code is used.
+type RecoveryCode struct {
+	ID         uint   `gorm:"primary_key"`
+	UserID     uint   `gorm:"index"`
+	CodeHashed string `gorm:"size:64"`
+	UsedAt     *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+// APIToken is a long-lived token for the local server to call the sync
+// endpoints of the central server without cookie session, so it is not
+// bound to an IP address. The token is only accepted on the events in
+// Scopes. Only the hash of the token is stored, Prefix is kept so the
+// user can recognize the token. ExpiresAt is nil if it never expires.
+type APIToken struct {
+	ID          uint   `gorm:"primary_key"`
+	UserID      uint   `gorm:"index"`
+	Name        string `gorm:"size:64"`
+	Prefix      string `gorm:"size:8"`
+	TokenHashed string `gorm:"size:64;unique"`
+	ExpiresAt   *time.Time
+	LastUsedAt  *time.Time
+	RevokedAt   *time.Time
+
+	User   *User           `gorm:"foreignkey:UserID;association_autoupdate:false"`
+	Scopes []APITokenScope `gorm:"foreignkey:APITokenID"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+// APITokenScope is an event that the API token is accepted on. The event
+// is referred by its ID only, because auth doesn't know about exam.
+type APITokenScope struct {
+	ID         uint `gorm:"primary_key"`
+	APITokenID uint `gorm:"index"`
+	EventID    uint
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+// APITokenUsage is a record of request made with API token. Result is
+// accepted, out of scope if the event is not in the scopes, or the reason
+// the token is rejected. APITokenID is zero if the token is unknown, the
+// Prefix of the presented token is kept instead.
+type APITokenUsage struct {
+	ID         uint   `gorm:"primary_key"`
+	APITokenID uint   `gorm:"index"`
+	Prefix     string `gorm:"size:8;index"`
+	EventID    uint   `gorm:"index"`
+	IPAddress  string `gorm:"size:45"`
+	Result     string `gorm:"size:16"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+// AuditLog is an entry of the append-only log of changes made by users.
+// Before and After are JSON of the changed fields of the target, empty on
+// creation and deletion respectively. Hash is computed from the entry and
+// PrevHash, the hash of the previous entry, so changing or removing an
+// entry breaks the chain from that entry onwards. The entry is never
+// updated nor deleted, so it has no UpdatedAt and DeletedAt.
+type AuditLog struct {
+	ID            uint   `gorm:"primary_key"`
+	ActorID       uint   `gorm:"index"`
+	ActorUsername string `gorm:"size:256"`
+	IPAddress     string `gorm:"size:45"`
+	Action        string `gorm:"size:64;index"`
+	TargetType    string `gorm:"size:32;index"`
+	TargetID      uint
+	Before        string `gorm:"type:text"`
+	After         string `gorm:"type:text"`
+	PrevHash      string `gorm:"size:64;unique_index"`
+	Hash          string `gorm:"size:64;unique"`
+
+	CreatedAt time.Time
+}
+
 func init() {
 	helios.App.RegisterModel(User{})
 	helios.App.RegisterModel(Session{})
+	helios.App.RegisterModel(LoginAttempt{})
+	helios.App.RegisterModel(EventRole{})
+	helios.App.RegisterModel(PasswordResetToken{})
+	helios.App.RegisterModel(RecoveryCode{})
+	helios.App.RegisterModel(APIToken{})
+	helios.App.RegisterModel(APITokenScope{})
+	helios.App.RegisterModel(APITokenUsage{})
+	helios.App.RegisterModel(AuditLog{})
+}
+
+// IsExpired returns true if the session has passed SessionMaxAge since
+// it is created, or has not been used for SessionIdleTimeout. Session
+// waiting for TOTP code expires after TwoFactorLoginTimeout.
+func (session *Session) IsExpired(now time.Time) bool {
+	if session.TwoFactorPending && now.Sub(session.CreatedAt) > TwoFactorLoginTimeout {
+		return true
+	}
+	var lastSeenAt time.Time = session.LastSeenAt
+	if lastSeenAt.IsZero() {
+		lastSeenAt = session.CreatedAt
+	}
+	if SessionMaxAge > 0 && now.Sub(session.CreatedAt) > SessionMaxAge {
+		return true
+	}
+	if SessionIdleTimeout > 0 && now.Sub(lastSeenAt) > SessionIdleTimeout {
+		return true
+	}
+	return false
+}
+
+// IsExpired returns true if the API token has passed its expiry time
+func (apiToken *APIToken) IsExpired(now time.Time) bool {
+	return apiToken.ExpiresAt != nil && now.After(*apiToken.ExpiresAt)
+}
+
+// HasScope returns true if the API token is accepted on the event
+func (apiToken *APIToken) HasScope(eventID uint) bool {
+	for _, scope := range apiToken.Scopes {
+		if scope.EventID == eventID {
+			return true
+		}
+	}
+	return false
 }
 
 // IsAdmin returns true if the user is local
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -45,3 +46,34 @@ func TestUserType(t *testing.T) {
 	assert.False(t, user.IsOrganizer(), "user should be participant")
 	assert.False(t, user.IsAdmin(), "user should be participant")
 }
+
+func TestSessionIsExpired(t *testing.T) {
+	var now time.Time = time.Now()
+	type sessionIsExpiredTestCase struct {
+		session         Session
+		expectedExpired bool
+	}
+	testCases := []sessionIsExpiredTestCase{{
+		session:         Session{CreatedAt: now, LastSeenAt: now},
+		expectedExpired: false,
+	}, {
+		session:         Session{CreatedAt: now.Add(-SessionIdleTimeout / 2)},
+		expectedExpired: false,
+	}, {
+		session:         Session{CreatedAt: now.Add(-SessionIdleTimeout - time.Minute)},
+		expectedExpired: true,
+	}, {
+		session:         Session{CreatedAt: now.Add(-SessionIdleTimeout - time.Minute), LastSeenAt: now},
+		expectedExpired: false,
+	}, {
+		session:         Session{CreatedAt: now.Add(-SessionMaxAge - time.Minute), LastSeenAt: now},
+		expectedExpired: true,
+	}, {
+		session:         Session{CreatedAt: now.Add(-TwoFactorLoginTimeout - time.Minute), LastSeenAt: now, TwoFactorPending: true},
+		expectedExpired: true,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test SessionIsExpired testcase: %d", i)
+		assert.Equal(t, testCase.expectedExpired, testCase.session.IsExpired(now))
+	}
+}
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/yonasadiel/charon/backend/openapi"
+)
+
+var loggedInSecurity = []string{openapi.SecuritySession}
+
+// Operations describes the views of auth for the OpenAPI document
+var Operations = openapi.Operations{
+	"POST /auth/login/":                     {ID: "Login", Summary: "Log in with the username and the password", Request: LoginRequest{}, Response: UserData{}},
+	"POST /auth/login/verify/":              {ID: "LoginVerify", Summary: "Verify the login with the two-factor or recovery code", Security: loggedInSecurity, Request: TwoFactorCodeRequest{}, Response: UserData{}},
+	"POST /auth/logout/":                    {ID: "Logout", Summary: "Log out of the session", Security: loggedInSecurity},
+	"POST /auth/password/":                  {ID: "PasswordChange", Summary: "Change the password of the user", Security: loggedInSecurity, Request: PasswordChangeRequest{}, Response: openapi.OK},
+	"POST /auth/password/reset/":            {ID: "PasswordReset", Summary: "Reset the password with the reset token", Request: PasswordResetRequest{}, Response: openapi.OK},
+	"POST /auth/two-factor/":                {ID: "TwoFactorEnroll", Summary: "Start enrolling the two-factor authentication", Security: loggedInSecurity, Response: TwoFactorEnrollmentData{}},
+	"DELETE /auth/two-factor/":              {ID: "TwoFactorDisable", Summary: "Disable the two-factor authentication", Security: loggedInSecurity, Request: TwoFactorCodeRequest{}, Response: openapi.OK},
+	"POST /auth/two-factor/confirm/":        {ID: "TwoFactorConfirm", Summary: "Confirm the two-factor enrollment with a code", Security: loggedInSecurity, Request: TwoFactorCodeRequest{}, Response: RecoveryCodesData{}},
+	"POST /auth/two-factor/recovery-codes/": {ID: "RecoveryCodeRegenerate", Summary: "Regenerate the recovery codes", Security: loggedInSecurity, Request: TwoFactorCodeRequest{}, Response: RecoveryCodesData{}},
+
+	"GET /auth/user/":                              {ID: "UserList", Summary: "List the users", Security: loggedInSecurity, Response: []UserData{}},
+	"POST /auth/user/":                             {ID: "UserCreate", Summary: "Create a user", Security: loggedInSecurity, Request: UserWithPasswordData{}, StatusCode: http.StatusCreated, Response: UserData{}},
+	"POST /auth/user/{username}/unlock-login/":     {ID: "UserLoginUnlock", Summary: "Unlock the login of the user after too many failures", Security: loggedInSecurity, Response: openapi.OK},
+	"POST /auth/user/{username}/reset-password/":   {ID: "PasswordResetTokenCreate", Summary: "Create a password reset token of the user", Security: loggedInSecurity, StatusCode: http.StatusCreated, Response: PasswordResetTokenData{}},
+	"POST /auth/user/{username}/reset-two-factor/": {ID: "UserTwoFactorReset", Summary: "Disable the two-factor authentication of the user", Security: loggedInSecurity, Response: openapi.OK},
+	"GET /auth/login-attempt/":                     {ID: "LoginAttemptList", Summary: "List the login attempts", Security: loggedInSecurity, Response: []LoginAttemptData{}},
+	"POST /auth/login-attempt/unlock/":             {ID: "LoginAttemptUnlock", Summary: "Unlock the login from the IP address after too many failures", Security: loggedInSecurity, Request: LoginUnlockRequest{}, Response: openapi.OK},
+	"GET /auth/session/":                           {ID: "SessionList", Summary: "List the sessions of the user", Security: loggedInSecurity, Response: []SessionData{}},
+	"DELETE /auth/session/{sessionID}/":            {ID: "SessionRevoke", Summary: "Revoke the session of the user", Security: loggedInSecurity, Response: openapi.OK},
+
+	"POST /auth/audit-log/":        {ID: "AuditLogQuery", Summary: "Query the audit logs", Security: loggedInSecurity, Request: AuditLogQueryRequest{}, Response: []AuditLogData{}},
+	"POST /auth/audit-log/export/": {ID: "AuditLogExport", Summary: "Export the audit logs as CSV", Security: loggedInSecurity, Request: AuditLogQueryRequest{}, Response: AuditLogExportData{}},
+	"GET /auth/audit-log/verify/":  {ID: "AuditLogVerify", Summary: "Verify the hash chain of the audit logs", Security: loggedInSecurity, Response: AuditLogVerificationData{}},
+
+	"GET /auth/api-token/":                    {ID: "APITokenList", Summary: "List the API tokens of the user", Security: loggedInSecurity, Response: []APITokenData{}},
+	"POST /auth/api-token/":                   {ID: "APITokenCreate", Summary: "Create an API token, the token is only sent once", Security: loggedInSecurity, Request: APITokenData{}, StatusCode: http.StatusCreated, Response: APITokenData{}},
+	"DELETE /auth/api-token/{apiTokenID}/":    {ID: "APITokenRevoke", Summary: "Revoke the API token", Security: loggedInSecurity, Response: APITokenData{}},
+	"GET /auth/api-token/{apiTokenID}/usage/": {ID: "APITokenUsageList", Summary: "List the usages of the API token", Security: loggedInSecurity, Response: []APITokenUsageData{}},
+}
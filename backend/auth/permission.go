@@ -0,0 +1,288 @@
+package auth
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/yonasadiel/helios"
+
+	"github.com/yonasadiel/charon/backend/logging"
+)
+
+// Action is an operation on a resource that is checked by Can
+type Action string
+
+// Actions that are checked by Can. Actions on event are granted either
+// by the role of the user, or by the role assignment on the event.
+const (
+	ActionUserManage         Action = "user.manage"
+	ActionLoginAttemptManage Action = "login_attempt.manage"
+	ActionVenueManage        Action = "venue.manage"
+	ActionEventCreate        Action = "event.create"
+	ActionEventSynchronize   Action = "event.synchronize"
+	ActionEventForceEdit     Action = "event.force_edit"
+	ActionAPITokenIssue      Action = "api_token.issue"
+	ActionAuditLogView       Action = "audit_log.view"
+
+	ActionUserManageAdmin       Action = "user.manage.admin"
+	ActionUserManageOrganizer   Action = "user.manage.organizer"
+	ActionUserManageLocal       Action = "user.manage.local"
+	ActionUserManageParticipant Action = "user.manage.participant"
+
+	ActionEventView               Action = "event.view"
+	ActionEventEdit               Action = "event.edit"
+	ActionEventRoleManage         Action = "event.role.manage"
+	ActionEventDecrypt            Action = "event.decrypt"
+	ActionEventRun                Action = "event.run"
+	ActionEventGrade              Action = "event.grade"
+	ActionQuestionView            Action = "question.view"
+	ActionQuestionViewBeforeStart Action = "question.view_before_start"
+	ActionQuestionEdit            Action = "question.edit"
+	ActionSubmissionSubmit        Action = "submission.submit"
+	ActionParticipationView       Action = "participation.view"
+	ActionParticipationEdit       Action = "participation.edit"
+	ActionParticipationCheckIn    Action = "participation.check_in"
+	ActionParticipationMonitor    Action = "participation.monitor"
+	ActionAttendanceSynchronize   Action = "attendance.synchronize"
+	ActionSeatAssign              Action = "seat.assign"
+	ActionCredentialCardGenerate  Action = "credential_card.generate"
+	ActionAnnouncementPost        Action = "announcement.post"
+	ActionClarificationSubmit     Action = "clarification.submit"
+	ActionClarificationAnswer     Action = "clarification.answer"
+)
+
+// Resource is the object that the action is done to. EventID is zero
+// if the resource doesn't belong to any event, and VenueID is zero if
+// the resource is not limited to a venue of the event.
+type Resource struct {
+	EventID uint
+	VenueID uint
+}
+
+// RolePermissions is the actions that are granted by the role of the user
+// on all resources. Admin is granted all actions, except personal actions.
+var RolePermissions = map[uint][]Action{
+	UserRoleOrganizer: {
+		ActionUserManage,
+		ActionUserManageLocal,
+		ActionUserManageParticipant,
+		ActionLoginAttemptManage,
+		ActionVenueManage,
+		ActionEventCreate,
+	},
+	UserRoleLocal: {
+		ActionUserManage,
+		ActionUserManageParticipant,
+		ActionLoginAttemptManage,
+		ActionEventCreate,
+		ActionEventSynchronize,
+		ActionAPITokenIssue,
+	},
+	UserRoleParticipant: {
+		ActionSubmissionSubmit,
+		ActionClarificationSubmit,
+	},
+}
+
+// personalActions is the actions that are done by the user on their own
+// participation, such as submitting answers or synchronizing the venue of
+// the local server, so admin is not granted these actions. API tokens are
+// only for the local server to synchronize, so they are personal too.
+var personalActions = []Action{
+	ActionAPITokenIssue,
+	ActionAttendanceSynchronize,
+	ActionEventSynchronize,
+	ActionSubmissionSubmit,
+	ActionClarificationSubmit,
+}
+
+// adminDeniedActions is the actions that are not granted to admin either,
+// so an admin can't take over the account of the other admins
+var adminDeniedActions = []Action{
+	ActionUserManageAdmin,
+}
+
+// userManageActions is the action of managing the users of each role
+var userManageActions = map[uint]Action{
+	UserRoleAdmin:       ActionUserManageAdmin,
+	UserRoleOrganizer:   ActionUserManageOrganizer,
+	UserRoleLocal:       ActionUserManageLocal,
+	UserRoleParticipant: ActionUserManageParticipant,
+}
+
+// EventRolePermissions is the actions that are granted by the role
+// assignment on the event
+var EventRolePermissions = map[string][]Action{
+	EventRoleAuthor: {
+		ActionEventView,
+		ActionEventEdit,
+		ActionEventGrade,
+		ActionEventRoleManage,
+		ActionQuestionView,
+		ActionQuestionViewBeforeStart,
+		ActionQuestionEdit,
+		ActionParticipationView,
+		ActionParticipationEdit,
+		ActionSeatAssign,
+		ActionCredentialCardGenerate,
+		ActionAnnouncementPost,
+		ActionClarificationAnswer,
+	},
+	EventRoleReviewer: {
+		ActionEventView,
+		ActionQuestionView,
+		ActionQuestionViewBeforeStart,
+	},
+	EventRoleGrader: {
+		ActionEventView,
+		ActionEventGrade,
+		ActionQuestionView,
+	},
+	EventRoleProctor: {
+		ActionEventView,
+		ActionEventDecrypt,
+		ActionEventRun,
+		ActionQuestionView,
+		ActionParticipationView,
+		ActionParticipationCheckIn,
+		ActionParticipationMonitor,
+		ActionAttendanceSynchronize,
+		ActionAnnouncementPost,
+		ActionClarificationAnswer,
+	},
+	EventRoleVenueManager: {
+		ActionEventView,
+		ActionParticipationView,
+		ActionParticipationEdit,
+		ActionSeatAssign,
+		ActionCredentialCardGenerate,
+	},
+}
+
+// IsValidEventRole returns true if the role is one of the event roles
+func IsValidEventRole(role string) bool {
+	_, ok := EventRolePermissions[role]
+	return ok
+}
+
+// Can returns true if the user is permitted to do the action on the resource.
+// If the resource is limited to a venue, only the assignments on all venues
+// or on the same venue are considered. Otherwise, assignment on any venue
+// of the event is enough. The action is not permitted if the assignments
+// can't be read.
+func Can(user User, action Action, resource Resource) bool {
+	if isGrantedByRole(user, action) {
+		return true
+	}
+	if resource.EventID == 0 {
+		return false
+	}
+
+	var count int
+	var query = helios.DB.
+		Model(&EventRole{}).
+		Where("user_id = ?", user.ID).
+		Where("event_id = ?", resource.EventID).
+		Where("role in (?)", eventRolesGranting(action))
+	if resource.VenueID != 0 {
+		query = query.Where("(venue_id = 0 or venue_id = ?)", resource.VenueID)
+	}
+	if logging.CheckDB(user.RequestID, query.Count(&count)) != nil {
+		return false
+	}
+	return count > 0
+}
+
+// UserManageAction returns the action of managing the users of the role,
+// such as resetting their password or registering them to an event. The
+// unknown role is treated as admin, so its users can't be managed.
+func UserManageAction(role uint) Action {
+	if action, ok := userManageActions[role]; ok {
+		return action
+	}
+	return ActionUserManageAdmin
+}
+
+// GetManageableUserRoles returns the roles whose users can be managed by
+// the user, to filter the queries of the users
+func GetManageableUserRoles(user User) []uint {
+	var roles []uint = make([]uint, 0)
+	for _, role := range []uint{UserRoleAdmin, UserRoleOrganizer, UserRoleLocal, UserRoleParticipant} {
+		if Can(user, UserManageAction(role), Resource{}) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// GetVenueIDsOfPermission returns the venues of the event that the user is
+// permitted to do the action on. allVenues is true if the user is permitted
+// on all venues of the event, and venueIDs is empty. The user is permitted
+// on no venue if the assignments can't be read.
+func GetVenueIDsOfPermission(user User, action Action, eventID uint) (allVenues bool, venueIDs []uint) {
+	if isGrantedByRole(user, action) {
+		return true, nil
+	}
+
+	var eventRoles []EventRole
+	errDB := logging.CheckDB(user.RequestID, helios.DB.
+		Where("user_id = ?", user.ID).
+		Where("event_id = ?", eventID).
+		Where("role in (?)", eventRolesGranting(action)).
+		Find(&eventRoles))
+	if errDB != nil {
+		return false, nil
+	}
+	for _, eventRole := range eventRoles {
+		if eventRole.VenueID == 0 {
+			return true, nil
+		}
+		venueIDs = append(venueIDs, eventRole.VenueID)
+	}
+	return false, venueIDs
+}
+
+// AssignEventRole assigns the user to the role on the event, if the same
+// assignment doesn't exist yet. db is given so that the assignment can be
+// saved in the transaction of the caller.
+func AssignEventRole(db *gorm.DB, userID uint, eventID uint, venueID uint, role string) error {
+	var eventRole EventRole
+	err := db.
+		Where("user_id = ?", userID).
+		Where("event_id = ?", eventID).
+		Where("venue_id = ?", venueID).
+		Where("role = ?", role).
+		First(&eventRole).Error
+	if err != nil && !gorm.IsRecordNotFoundError(err) {
+		return err
+	}
+	if eventRole.ID != 0 {
+		return nil
+	}
+	eventRole = EventRole{UserID: userID, EventID: eventID, VenueID: venueID, Role: role}
+	return db.Create(&eventRole).Error
+}
+
+func isGrantedByRole(user User, action Action) bool {
+	if user.IsAdmin() && !hasAction(personalActions, action) && !hasAction(adminDeniedActions, action) {
+		return true
+	}
+	return hasAction(RolePermissions[user.Role], action)
+}
+
+func eventRolesGranting(action Action) []string {
+	var roles []string = make([]string, 0)
+	for role, actions := range EventRolePermissions {
+		if hasAction(actions, action) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+func hasAction(actions []Action, action Action) bool {
+	for _, granted := range actions {
+		if granted == action {
+			return true
+		}
+	}
+	return false
+}
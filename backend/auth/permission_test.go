@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yonasadiel/helios"
+)
+
+func TestCan(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userAdmin User = UserFactorySaved(User{Role: UserRoleAdmin})
+	var userOrganizer User = UserFactorySaved(User{Role: UserRoleOrganizer})
+	var userAuthor User = UserFactorySaved(User{Role: UserRoleOrganizer})
+	var userProctor User = UserFactorySaved(User{Role: UserRoleLocal})
+	var userParticipant User = UserFactorySaved(User{Role: UserRoleParticipant})
+	AssignEventRole(helios.DB, userAuthor.ID, 1, 0, EventRoleAuthor)
+	AssignEventRole(helios.DB, userProctor.ID, 1, 5, EventRoleProctor)
+
+	type canTestCase struct {
+		user     User
+		action   Action
+		resource Resource
+		expected bool
+	}
+	testCases := []canTestCase{
+		{user: userAdmin, action: ActionEventEdit, resource: Resource{EventID: 1}, expected: true},
+		{user: userAdmin, action: ActionEventForceEdit, resource: Resource{EventID: 1}, expected: true},
+		{user: userAuthor, action: ActionEventForceEdit, resource: Resource{EventID: 1}, expected: false},
+		{user: userAdmin, action: ActionSubmissionSubmit, resource: Resource{}, expected: false},
+		{user: userAdmin, action: ActionAPITokenIssue, resource: Resource{}, expected: false},
+		{user: userProctor, action: ActionAPITokenIssue, resource: Resource{}, expected: true},
+		{user: userOrganizer, action: ActionEventCreate, resource: Resource{}, expected: true},
+		{user: userOrganizer, action: ActionEventEdit, resource: Resource{EventID: 1}, expected: false},
+		{user: userAuthor, action: ActionEventEdit, resource: Resource{EventID: 1}, expected: true},
+		{user: userAuthor, action: ActionEventEdit, resource: Resource{EventID: 2}, expected: false},
+		{user: userAuthor, action: ActionEventEdit, resource: Resource{}, expected: false},
+		{user: userAuthor, action: ActionSeatAssign, resource: Resource{EventID: 1, VenueID: 7}, expected: true},
+		{user: userAuthor, action: ActionParticipationCheckIn, resource: Resource{EventID: 1, VenueID: 7}, expected: false},
+		{user: userProctor, action: ActionParticipationCheckIn, resource: Resource{EventID: 1, VenueID: 5}, expected: true},
+		{user: userProctor, action: ActionParticipationCheckIn, resource: Resource{EventID: 1, VenueID: 6}, expected: false},
+		{user: userProctor, action: ActionEventView, resource: Resource{EventID: 1}, expected: true},
+		{user: userProctor, action: ActionQuestionEdit, resource: Resource{EventID: 1}, expected: false},
+		{user: userProctor, action: ActionEventRun, resource: Resource{EventID: 1}, expected: true},
+		{user: userAuthor, action: ActionEventRun, resource: Resource{EventID: 1}, expected: false},
+		{user: userParticipant, action: ActionSubmissionSubmit, resource: Resource{EventID: 1}, expected: true},
+		{user: userParticipant, action: ActionEventView, resource: Resource{EventID: 1}, expected: false},
+		{user: userAdmin, action: ActionUserManageOrganizer, resource: Resource{}, expected: true},
+		{user: userAdmin, action: ActionUserManageAdmin, resource: Resource{}, expected: false},
+		{user: userOrganizer, action: ActionUserManageLocal, resource: Resource{}, expected: true},
+		{user: userOrganizer, action: ActionUserManageOrganizer, resource: Resource{}, expected: false},
+		{user: userProctor, action: ActionUserManageParticipant, resource: Resource{}, expected: true},
+		{user: userProctor, action: ActionUserManageLocal, resource: Resource{}, expected: false},
+	}
+	for i, testCase := range testCases {
+		t.Logf("Test Can testcase: %d", i)
+		assert.Equal(t, testCase.expected, Can(testCase.user, testCase.action, testCase.resource))
+	}
+}
+
+func TestGetManageableUserRoles(t *testing.T) {
+	helios.App.BeforeTest()
+
+	type getManageableUserRolesTestCase struct {
+		user          User
+		expectedRoles []uint
+	}
+	testCases := []getManageableUserRolesTestCase{
+		{user: UserFactorySaved(User{Role: UserRoleAdmin}), expectedRoles: []uint{UserRoleOrganizer, UserRoleLocal, UserRoleParticipant}},
+		{user: UserFactorySaved(User{Role: UserRoleOrganizer}), expectedRoles: []uint{UserRoleLocal, UserRoleParticipant}},
+		{user: UserFactorySaved(User{Role: UserRoleLocal}), expectedRoles: []uint{UserRoleParticipant}},
+		{user: UserFactorySaved(User{Role: UserRoleParticipant}), expectedRoles: []uint{}},
+	}
+	for i, testCase := range testCases {
+		t.Logf("Test GetManageableUserRoles testcase: %d", i)
+		assert.Equal(t, testCase.expectedRoles, GetManageableUserRoles(testCase.user))
+	}
+	assert.Equal(t, ActionUserManageAdmin, UserManageAction(0), "Unknown role should not be manageable")
+}
+
+func TestGetVenueIDsOfPermission(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userAuthor User = UserFactorySaved(User{Role: UserRoleOrganizer})
+	var userVenueManager User = UserFactorySaved(User{Role: UserRoleLocal})
+	AssignEventRole(helios.DB, userAuthor.ID, 1, 0, EventRoleAuthor)
+	AssignEventRole(helios.DB, userVenueManager.ID, 1, 3, EventRoleVenueManager)
+	AssignEventRole(helios.DB, userVenueManager.ID, 1, 4, EventRoleVenueManager)
+	AssignEventRole(helios.DB, userVenueManager.ID, 1, 4, EventRoleVenueManager)
+
+	var allVenues bool
+	var venueIDs []uint
+	allVenues, venueIDs = GetVenueIDsOfPermission(userAuthor, ActionParticipationEdit, 1)
+	assert.True(t, allVenues)
+	assert.Empty(t, venueIDs)
+	allVenues, venueIDs = GetVenueIDsOfPermission(userVenueManager, ActionParticipationEdit, 1)
+	assert.False(t, allVenues)
+	assert.Equal(t, []uint{3, 4}, venueIDs, "Same assignment should not be saved twice")
+	allVenues, venueIDs = GetVenueIDsOfPermission(userVenueManager, ActionParticipationEdit, 2)
+	assert.False(t, allVenues)
+	assert.Empty(t, venueIDs)
+}
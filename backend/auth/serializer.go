@@ -1,6 +1,14 @@
 package auth
 
-import "github.com/yonasadiel/helios"
+import (
+	"bytes"
+	"encoding/csv"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/yonasadiel/helios"
+)
 
 // LoginRequest is request for logging in
 type LoginRequest struct {
@@ -8,11 +16,15 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
-// UserData is JSON representation of User.
+// UserData is JSON representation of User. TwoFactorPending is only
+// set on the login response, true if the TOTP code has to be verified.
 type UserData struct {
-	Name     string `json:"name"`
-	Username string `json:"username"`
-	Role     string `json:"role"`
+	Name               string `json:"name"`
+	Username           string `json:"username"`
+	Role               string `json:"role"`
+	MustChangePassword bool   `json:"mustChangePassword,omitempty"`
+	TwoFactorEnabled   bool   `json:"twoFactorEnabled,omitempty"`
+	TwoFactorPending   bool   `json:"twoFactorPending,omitempty"`
 }
 
 // UserWithPasswordData is JSON representation of User according to auth.UserData,
@@ -24,6 +36,137 @@ type UserWithPasswordData struct {
 	Password string `json:"password"`
 }
 
+// PasswordChangeRequest is request for changing the password of the
+// logged in user
+type PasswordChangeRequest struct {
+	OldPassword string `json:"oldPassword"`
+	NewPassword string `json:"newPassword"`
+}
+
+// PasswordResetRequest is request for resetting the password with the
+// token issued by the admin
+type PasswordResetRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"newPassword"`
+}
+
+// PasswordResetTokenData is JSON representation of issued PasswordResetToken.
+// Token is only shown once, when it is issued.
+type PasswordResetTokenData struct {
+	Username  string `json:"username"`
+	Token     string `json:"token"`
+	ExpiresAt string `json:"expiresAt"`
+}
+
+// TwoFactorCodeRequest is request with TOTP code or recovery code
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+// TwoFactorEnrollmentData is the TOTP secret to be added to the
+// authenticator app, by typing the secret or scanning the URI as QR code
+type TwoFactorEnrollmentData struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+// RecoveryCodesData is the recovery codes, only shown once when they are
+// generated
+type RecoveryCodesData struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// APITokenData is JSON representation of APIToken. Token is only shown
+// once, when it is issued. The times are empty if they are not set.
+type APITokenData struct {
+	ID         uint   `json:"id"`
+	Name       string `json:"name"`
+	Prefix     string `json:"prefix"`
+	Token      string `json:"token,omitempty"`
+	EventIDs   []uint `json:"eventIds"`
+	CreatedAt  string `json:"createdAt"`
+	ExpiresAt  string `json:"expiresAt"`
+	LastUsedAt string `json:"lastUsedAt"`
+	RevokedAt  string `json:"revokedAt"`
+}
+
+// APITokenUsageData is JSON representation of APITokenUsage
+type APITokenUsageData struct {
+	ID        uint   `json:"id"`
+	EventID   uint   `json:"eventId"`
+	IPAddress string `json:"ipAddress"`
+	Result    string `json:"result"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// SessionData is JSON representation of Session. IsCurrent is true if
+// it is the session used on the request.
+type SessionData struct {
+	ID         uint   `json:"id"`
+	IPAddress  string `json:"ipAddress"`
+	CreatedAt  string `json:"createdAt"`
+	LastSeenAt string `json:"lastSeenAt"`
+	IsCurrent  bool   `json:"isCurrent"`
+}
+
+// LoginUnlockRequest is request for clearing the failed login attempts
+// from an IP address
+type LoginUnlockRequest struct {
+	IPAddress string `json:"ipAddress"`
+}
+
+// LoginAttemptData is JSON representation of LoginAttempt
+type LoginAttemptData struct {
+	ID        uint   `json:"id"`
+	Username  string `json:"username"`
+	IPAddress string `json:"ipAddress"`
+	Result    string `json:"result"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// AuditLogData is JSON representation of AuditLog. Before and After are
+// JSON of the changed fields, kept as string so the hash can be checked.
+type AuditLogData struct {
+	ID            uint   `json:"id"`
+	ActorID       uint   `json:"actorId"`
+	ActorUsername string `json:"actorUsername"`
+	IPAddress     string `json:"ipAddress"`
+	Action        string `json:"action"`
+	TargetType    string `json:"targetType"`
+	TargetID      uint   `json:"targetId"`
+	Before        string `json:"before"`
+	After         string `json:"after"`
+	PrevHash      string `json:"prevHash"`
+	Hash          string `json:"hash"`
+	CreatedAt     string `json:"createdAt"`
+}
+
+// AuditLogQueryRequest is JSON representation of the filter of audit log.
+// From and To are RFC3339 time. The empty fields are not filtered.
+type AuditLogQueryRequest struct {
+	ActorUsername string `json:"actorUsername"`
+	Action        string `json:"action"`
+	TargetType    string `json:"targetType"`
+	TargetID      uint   `json:"targetId"`
+	From          string `json:"from"`
+	To            string `json:"to"`
+	BeforeID      uint   `json:"beforeId"`
+}
+
+// AuditLogExportData is the exported audit log as CSV, one entry per row
+// after the header
+type AuditLogExportData struct {
+	CSV string `json:"csv"`
+}
+
+// AuditLogVerificationData is JSON representation of AuditLogVerification
+type AuditLogVerificationData struct {
+	Valid    bool   `json:"valid"`
+	Checked  int    `json:"checked"`
+	BrokenID uint   `json:"brokenId"`
+	LastHash string `json:"lastHash"`
+}
+
 // SerializeUser serialize user to UserData
 func SerializeUser(user User) UserData {
 	var role string
@@ -37,9 +180,11 @@ func SerializeUser(user User) UserData {
 		role = "participant"
 	}
 	return UserData{
-		Username: user.Username,
-		Name:     user.Name,
-		Role:     role,
+		Username:           user.Username,
+		Name:               user.Name,
+		Role:               role,
+		MustChangePassword: user.MustChangePassword,
+		TwoFactorEnabled:   user.TOTPEnabled,
 	}
 }
 
@@ -84,8 +229,30 @@ func SerializeUserWithPassword(user User) UserWithPasswordData {
 	}
 }
 
-// DeserializeUserWithPassword deserialize UserWithPasswordData to User
+// DeserializeUserWithPassword deserialize UserWithPasswordData to User.
+// The password is checked against UserPasswordPolicy.
 func DeserializeUserWithPassword(userData UserWithPasswordData, user *User) helios.Error {
+	var err helios.ErrorForm = helios.NewErrorForm()
+	if errUser, ok := DeserializeUser(UserData{
+		Name:     userData.Name,
+		Username: userData.Username,
+		Role:     userData.Role,
+	}, user).(helios.ErrorForm); ok {
+		err = errUser
+	}
+	user.Password = userData.Password
+	if violations := UserPasswordPolicy.Validate(userData.Password); len(violations) > 0 {
+		err.FieldError["password"] = helios.ErrorFormFieldAtomic(violations)
+	}
+	if err.IsError() {
+		return err
+	}
+	return nil
+}
+
+// DeserializeUserWithHashedPassword deserialize UserWithPasswordData to User,
+// with the password that is already hashed. Used for synchronization.
+func DeserializeUserWithHashedPassword(userData UserWithPasswordData, user *User) helios.Error {
 	err := DeserializeUser(UserData{
 		Name:     userData.Name,
 		Username: userData.Username,
@@ -98,7 +265,8 @@ func DeserializeUserWithPassword(userData UserWithPasswordData, user *User) heli
 	return nil
 }
 
-// DeserializeUserWithUnencryptedPassword deserialize UserWithPasswordData to User
+// DeserializeUserWithUnencryptedPassword deserialize UserWithPasswordData to User.
+// The password is hashed, and empty hash means the hashing failed.
 func DeserializeUserWithUnencryptedPassword(userData UserWithPasswordData, user *User) helios.Error {
 	err := DeserializeUser(UserData{
 		Name:     userData.Name,
@@ -109,5 +277,214 @@ func DeserializeUserWithUnencryptedPassword(userData UserWithPasswordData, user
 	if err != nil {
 		return err
 	}
+	if user.Password == "" {
+		return helios.ErrInternalServerError
+	}
 	return nil
 }
+
+// SerializeSession serialize session to SessionData
+func SerializeSession(session Session, isCurrent bool) SessionData {
+	var lastSeenAt string
+	if !session.LastSeenAt.IsZero() {
+		lastSeenAt = session.LastSeenAt.Local().Format(time.RFC3339)
+	}
+	return SessionData{
+		ID:         session.ID,
+		IPAddress:  session.IPAddress,
+		CreatedAt:  session.CreatedAt.Local().Format(time.RFC3339),
+		LastSeenAt: lastSeenAt,
+		IsCurrent:  isCurrent,
+	}
+}
+
+// SerializePasswordResetToken serialize reset token to PasswordResetTokenData
+// with the token in plain
+func SerializePasswordResetToken(resetToken PasswordResetToken, token string) PasswordResetTokenData {
+	var username string
+	if resetToken.User != nil {
+		username = resetToken.User.Username
+	}
+	return PasswordResetTokenData{
+		Username:  username,
+		Token:     token,
+		ExpiresAt: resetToken.ExpiresAt.Local().Format(time.RFC3339),
+	}
+}
+
+// formatOptionalTime formats the time as RFC3339, or empty if it is nil
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Local().Format(time.RFC3339)
+}
+
+// SerializeAPIToken serialize API token to APITokenData. The token in plain
+// is only given when it is issued.
+func SerializeAPIToken(apiToken APIToken, token string) APITokenData {
+	var eventIDs []uint = make([]uint, 0)
+	for _, scope := range apiToken.Scopes {
+		eventIDs = append(eventIDs, scope.EventID)
+	}
+	return APITokenData{
+		ID:         apiToken.ID,
+		Name:       apiToken.Name,
+		Prefix:     apiToken.Prefix,
+		Token:      token,
+		EventIDs:   eventIDs,
+		CreatedAt:  apiToken.CreatedAt.Local().Format(time.RFC3339),
+		ExpiresAt:  formatOptionalTime(apiToken.ExpiresAt),
+		LastUsedAt: formatOptionalTime(apiToken.LastUsedAt),
+		RevokedAt:  formatOptionalTime(apiToken.RevokedAt),
+	}
+}
+
+// DeserializeAPIToken deserialize APITokenData to APIToken. Only the name
+// and the scopes are taken, the rest are set on issuing.
+func DeserializeAPIToken(apiTokenData APITokenData, apiToken *APIToken) helios.Error {
+	var err helios.ErrorForm = helios.NewErrorForm()
+	apiToken.Name = apiTokenData.Name
+	apiToken.Scopes = make([]APITokenScope, 0)
+	if apiToken.Name == "" {
+		err.FieldError["name"] = helios.ErrorFormFieldAtomic{"Name can't be empty"}
+	} else if len(apiToken.Name) > 64 {
+		err.FieldError["name"] = helios.ErrorFormFieldAtomic{"Name can't be longer than 64 characters"}
+	}
+	var seenEventIDs map[uint]bool = make(map[uint]bool)
+	for _, eventID := range apiTokenData.EventIDs {
+		if eventID == 0 || seenEventIDs[eventID] {
+			continue
+		}
+		seenEventIDs[eventID] = true
+		apiToken.Scopes = append(apiToken.Scopes, APITokenScope{EventID: eventID})
+	}
+	if len(apiToken.Scopes) == 0 {
+		err.FieldError["eventIds"] = helios.ErrorFormFieldAtomic{"Token should be allowed on at least one event"}
+	}
+	if err.IsError() {
+		return err
+	}
+	return nil
+}
+
+// SerializeAPITokenUsage serialize API token usage to APITokenUsageData
+func SerializeAPITokenUsage(usage APITokenUsage) APITokenUsageData {
+	return APITokenUsageData{
+		ID:        usage.ID,
+		EventID:   usage.EventID,
+		IPAddress: usage.IPAddress,
+		Result:    usage.Result,
+		CreatedAt: usage.CreatedAt.Local().Format(time.RFC3339),
+	}
+}
+
+// SerializeLoginAttempt serialize login attempt to LoginAttemptData
+func SerializeLoginAttempt(loginAttempt LoginAttempt) LoginAttemptData {
+	return LoginAttemptData{
+		ID:        loginAttempt.ID,
+		Username:  loginAttempt.Username,
+		IPAddress: loginAttempt.IPAddress,
+		Result:    loginAttempt.Result,
+		CreatedAt: loginAttempt.CreatedAt.Local().Format(time.RFC3339),
+	}
+}
+
+// SerializeAuditLog serialize audit log entry to AuditLogData
+func SerializeAuditLog(auditLog AuditLog) AuditLogData {
+	return AuditLogData{
+		ID:            auditLog.ID,
+		ActorID:       auditLog.ActorID,
+		ActorUsername: auditLog.ActorUsername,
+		IPAddress:     auditLog.IPAddress,
+		Action:        auditLog.Action,
+		TargetType:    auditLog.TargetType,
+		TargetID:      auditLog.TargetID,
+		Before:        auditLog.Before,
+		After:         auditLog.After,
+		PrevHash:      auditLog.PrevHash,
+		Hash:          auditLog.Hash,
+		CreatedAt:     auditLog.CreatedAt.Local().Format(time.RFC3339),
+	}
+}
+
+// SerializeAuditLogExport writes the audit log entries as CSV. The time is
+// written in UTC, the same as it is hashed.
+func SerializeAuditLogExport(auditLogs []AuditLog) AuditLogExportData {
+	var buffer bytes.Buffer
+	var writer *csv.Writer = csv.NewWriter(&buffer)
+	writer.Write([]string{"id", "created_at", "actor_id", "actor_username", "ip_address", "action", "target_type", "target_id", "before", "after", "prev_hash", "hash"})
+	for _, auditLog := range auditLogs {
+		writer.Write([]string{
+			strconv.FormatUint(uint64(auditLog.ID), 10),
+			auditLog.CreatedAt.UTC().Format(time.RFC3339),
+			strconv.FormatUint(uint64(auditLog.ActorID), 10),
+			auditLog.ActorUsername,
+			auditLog.IPAddress,
+			auditLog.Action,
+			auditLog.TargetType,
+			strconv.FormatUint(uint64(auditLog.TargetID), 10),
+			auditLog.Before,
+			auditLog.After,
+			auditLog.PrevHash,
+			auditLog.Hash,
+		})
+	}
+	writer.Flush()
+	return AuditLogExportData{CSV: buffer.String()}
+}
+
+// DeserializeAuditLogQuery converts AuditLogQueryRequest to AuditLogFilter
+func DeserializeAuditLogQuery(request AuditLogQueryRequest, filter *AuditLogFilter) helios.Error {
+	var err helios.ErrorForm = helios.NewErrorForm()
+	var errFrom, errTo error
+	filter.ActorUsername = request.ActorUsername
+	filter.Action = request.Action
+	filter.TargetType = request.TargetType
+	filter.TargetID = request.TargetID
+	filter.BeforeID = request.BeforeID
+	filter.From = time.Time{}
+	filter.To = time.Time{}
+	if request.From != "" {
+		filter.From, errFrom = time.Parse(time.RFC3339, request.From)
+		if errFrom != nil {
+			err.FieldError["from"] = helios.ErrorFormFieldAtomic{"Failed to parse time"}
+		}
+	}
+	if request.To != "" {
+		filter.To, errTo = time.Parse(time.RFC3339, request.To)
+		if errTo != nil {
+			err.FieldError["to"] = helios.ErrorFormFieldAtomic{"Failed to parse time"}
+		}
+	}
+	if errFrom == nil && errTo == nil && !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		err.FieldError["to"] = helios.ErrorFormFieldAtomic{"End time should be after start time"}
+	}
+	if err.IsError() {
+		return err
+	}
+	return nil
+}
+
+// DeserializeLoginUnlockRequest validates the IP address of the request,
+// and normalizes it to the form recorded on the login attempts
+func DeserializeLoginUnlockRequest(request LoginUnlockRequest, ipAddress *string) helios.Error {
+	var err helios.ErrorForm = helios.NewErrorForm()
+	var ip net.IP = net.ParseIP(request.IPAddress)
+	if ip == nil {
+		err.FieldError["ipAddress"] = helios.ErrorFormFieldAtomic{"Invalid IP address"}
+		return err
+	}
+	*ipAddress = ip.String()
+	return nil
+}
+
+// SerializeAuditLogVerification serialize AuditLogVerification to AuditLogVerificationData
+func SerializeAuditLogVerification(verification AuditLogVerification) AuditLogVerificationData {
+	return AuditLogVerificationData{
+		Valid:    verification.BrokenID == 0,
+		Checked:  verification.Checked,
+		BrokenID: verification.BrokenID,
+		LastHash: verification.LastHash,
+	}
+}
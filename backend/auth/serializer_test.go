@@ -3,6 +3,7 @@ package auth
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/yonasadiel/helios"
@@ -132,16 +133,19 @@ func TestDeserializeUserWithPassword(t *testing.T) {
 		expectedError string
 	}
 	testCases := []deserializeUserWithPasswordTestCase{{
-		userDataJSON: `{"name":"User 1","username":"user1","role":"participant","password":"abcd"}`,
+		userDataJSON: `{"name":"User 1","username":"user1","role":"participant","password":"abcd1234"}`,
 		expectedUser: User{
 			Name:     "User 1",
 			Username: "user1",
 			Role:     UserRoleParticipant,
-			Password: "abcd",
+			Password: "abcd1234",
 		},
+	}, {
+		userDataJSON:  `{"name":"User 2","username":"user2","role":"participant","password":"abcdefgh"}`,
+		expectedError: `{"code":"form_error","message":{"_error":[],"password":["Password should contain a digit"]}}`,
 	}, {
 		userDataJSON:  `{"name":"User 5","username":"user5","role":"random","password":"abc"}`,
-		expectedError: `{"code":"form_error","message":{"_error":[],"role":["Role should be either admin, organizer, local, or participant"]}}`,
+		expectedError: `{"code":"form_error","message":{"_error":[],"password":["Password should be at least 8 characters","Password should contain a digit"],"role":["Role should be either admin, organizer, local, or participant"]}}`,
 	}}
 	for i, testCase := range testCases {
 		t.Logf("Test DeserializeUserWithPassword testcase: %d", i)
@@ -215,3 +219,133 @@ func TestDeserializeUserWithUnencryptedPassword(t *testing.T) {
 		}
 	}
 }
+
+func TestSerializeLoginAttempt(t *testing.T) {
+	var createdAt time.Time = time.Date(2020, 4, 1, 10, 0, 0, 0, time.Local)
+	var loginAttempt LoginAttempt = LoginAttempt{
+		ID:        3,
+		Username:  "user1",
+		IPAddress: "1.2.3.4",
+		Result:    LoginResultFailed,
+		CreatedAt: createdAt,
+	}
+	var expectedJSON string = `{"id":3,"username":"user1","ipAddress":"1.2.3.4","result":"failed","createdAt":"` + createdAt.Format(time.RFC3339) + `"}`
+	serialized, errMarshalling := json.Marshal(SerializeLoginAttempt(loginAttempt))
+	assert.Nil(t, errMarshalling)
+	assert.Equal(t, expectedJSON, string(serialized))
+}
+
+func TestDeserializeAPIToken(t *testing.T) {
+	type deserializeAPITokenTestCase struct {
+		apiTokenDataJSON string
+		expectedEventIDs []uint
+		expectedError    string
+	}
+	testCases := []deserializeAPITokenTestCase{{
+		apiTokenDataJSON: `{"name":"Local server","eventIds":[1,2,2,0]}`,
+		expectedEventIDs: []uint{1, 2},
+	}, {
+		apiTokenDataJSON: `{"name":"","eventIds":[]}`,
+		expectedError:    `{"code":"form_error","message":{"_error":[],"eventIds":["Token should be allowed on at least one event"],"name":["Name can't be empty"]}}`,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test DeserializeAPIToken testcase: %d", i)
+		var apiToken APIToken
+		var apiTokenData APITokenData
+		var errUnmarshalling error
+		var errDeserialization helios.Error
+		errUnmarshalling = json.Unmarshal([]byte(testCase.apiTokenDataJSON), &apiTokenData)
+		errDeserialization = DeserializeAPIToken(apiTokenData, &apiToken)
+		assert.Nil(t, errUnmarshalling)
+		if testCase.expectedError == "" {
+			assert.Nil(t, errDeserialization)
+			assert.Equal(t, apiTokenData.Name, apiToken.Name)
+			assert.Equal(t, len(testCase.expectedEventIDs), len(apiToken.Scopes))
+			for j, eventID := range testCase.expectedEventIDs {
+				assert.Equal(t, eventID, apiToken.Scopes[j].EventID)
+			}
+		} else {
+			var errDeserializationJSON []byte
+			var errMarshalling error
+			errDeserializationJSON, errMarshalling = json.Marshal(errDeserialization.GetMessage())
+			assert.Nil(t, errMarshalling)
+			assert.Equal(t, testCase.expectedError, string(errDeserializationJSON))
+		}
+	}
+}
+
+func TestSerializeAPIToken(t *testing.T) {
+	var apiToken APIToken = APIToken{
+		ID:        3,
+		Name:      "Local server",
+		Prefix:    "abcdefgh",
+		Scopes:    []APITokenScope{{EventID: 1}},
+		CreatedAt: time.Date(2020, 4, 1, 8, 0, 0, 0, time.UTC),
+	}
+	serialized, errMarshalling := json.Marshal(SerializeAPIToken(apiToken, ""))
+	assert.Nil(t, errMarshalling)
+	assert.Equal(t, `{"id":3,"name":"Local server","prefix":"abcdefgh","eventIds":[1],"createdAt":"2020-04-01T15:00:00+07:00","expiresAt":"","lastUsedAt":"","revokedAt":""}`, string(serialized))
+}
+
+func TestDeserializeAuditLogQuery(t *testing.T) {
+	type deserializeAuditLogQueryTestCase struct {
+		requestJSON    string
+		expectedFilter AuditLogFilter
+		expectedError  string
+	}
+	testCases := []deserializeAuditLogQueryTestCase{{
+		requestJSON:    `{"actorUsername":"admin","action":"event.create","targetType":"event","targetId":3,"beforeId":10}`,
+		expectedFilter: AuditLogFilter{ActorUsername: "admin", Action: "event.create", TargetType: "event", TargetID: 3, BeforeID: 10},
+	}, {
+		requestJSON: `{"from":"2020-04-01T08:00:00Z","to":"2020-04-02T08:00:00Z"}`,
+		expectedFilter: AuditLogFilter{
+			From: time.Date(2020, 4, 1, 8, 0, 0, 0, time.UTC),
+			To:   time.Date(2020, 4, 2, 8, 0, 0, 0, time.UTC),
+		},
+	}, {
+		requestJSON:   `{"from":"yesterday","to":"2020-04-02T08:00:00Z"}`,
+		expectedError: `{"code":"form_error","message":{"_error":[],"from":["Failed to parse time"]}}`,
+	}, {
+		requestJSON:   `{"from":"2020-04-02T08:00:00Z","to":"2020-04-01T08:00:00Z"}`,
+		expectedError: `{"code":"form_error","message":{"_error":[],"to":["End time should be after start time"]}}`,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test DeserializeAuditLogQuery testcase: %d", i)
+		var filter AuditLogFilter
+		var request AuditLogQueryRequest
+		var errUnmarshalling error
+		var errDeserialization helios.Error
+		errUnmarshalling = json.Unmarshal([]byte(testCase.requestJSON), &request)
+		errDeserialization = DeserializeAuditLogQuery(request, &filter)
+		assert.Nil(t, errUnmarshalling)
+		if testCase.expectedError == "" {
+			assert.Nil(t, errDeserialization)
+			assert.Equal(t, testCase.expectedFilter, filter)
+		} else {
+			var errDeserializationJSON []byte
+			var errMarshalling error
+			errDeserializationJSON, errMarshalling = json.Marshal(errDeserialization.GetMessage())
+			assert.Nil(t, errMarshalling)
+			assert.Equal(t, testCase.expectedError, string(errDeserializationJSON))
+		}
+	}
+}
+
+func TestSerializeAuditLogExport(t *testing.T) {
+	var auditLogs []AuditLog = []AuditLog{{
+		ID:            1,
+		ActorID:       2,
+		ActorUsername: "admin",
+		IPAddress:     "7.1.1.1",
+		Action:        "user.update",
+		TargetType:    "user",
+		TargetID:      3,
+		Before:        `{"Name":"Alice"}`,
+		After:         `{"Name":"Bob"}`,
+		Hash:          "abc",
+		CreatedAt:     time.Date(2020, 4, 1, 15, 0, 0, 0, time.FixedZone("WIB", 7*3600)),
+	}}
+	assert.Equal(t, "id,created_at,actor_id,actor_username,ip_address,action,target_type,target_id,before,after,prev_hash,hash\n"+
+		`1,2020-04-01T08:00:00Z,2,admin,7.1.1.1,user.update,user,3,"{""Name"":""Alice""}","{""Name"":""Bob""}",,abc`+"\n",
+		SerializeAuditLogExport(auditLogs).CSV)
+}
@@ -1,21 +1,25 @@
 package auth
 
 import (
-	"math/rand"
-	"strings"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"math"
 	"time"
 
+	"github.com/jinzhu/gorm"
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/yonasadiel/helios"
-)
 
-var src = rand.NewSource(time.Now().UnixNano())
+	"github.com/yonasadiel/charon/backend/logging"
+)
 
 func hashPassword(password string) string {
 	// we ignore error because the failure
 	// usually because of cost error
-	bytes, _ := bcrypt.GenerateFromPassword([]byte(password), hashCost)
+	bytes, _ := bcrypt.GenerateFromPassword([]byte(password), PasswordHashCost)
 	return string(bytes)
 }
 
@@ -25,113 +29,998 @@ func checkPasswordHash(password, hash string) bool {
 }
 
 // generateUserToken generates token of length userTokenLength
-// https://stackoverflow.com/questions/22892120/how-to-generate-a-random-string-of-a-fixed-length-in-go
-func generateUserToken() string {
-	n := userTokenLength
-	sb := strings.Builder{}
-	sb.Grow(n)
-	// A src.Int63() generates 63 random bits, enough for letterIdxMax characters!
-	for i, cache, remain := n-1, src.Int63(), userTokenIdxMax; i >= 0; {
-		if remain == 0 {
-			cache, remain = src.Int63(), userTokenIdxMax
+// from cryptographically secure random bytes
+func generateUserToken() (string, error) {
+	tokenBytes := make([]byte, userTokenLength*3/4)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(tokenBytes), nil
+}
+
+// hashToken hashes the random token to be stored, such as reset token
+// or recovery code. The token is random enough, so it doesn't need the
+// slow bcrypt.
+func hashToken(token string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(token)))
+}
+
+// tokenPrefix returns the prefix of the token that is kept to recognize it
+func tokenPrefix(token string) string {
+	var runes []rune = []rune(token)
+	if len(runes) > apiTokenPrefixLength {
+		runes = runes[:apiTokenPrefixLength]
+	}
+	return string(runes)
+}
+
+// sessionRequestID returns the ID of the request made with the session,
+// set on its user by the middleware
+func sessionRequestID(session Session) string {
+	if session.User == nil {
+		return ""
+	}
+	return session.User.RequestID
+}
+
+// checkPasswordPolicy returns form error on the field if the password
+// doesn't satisfy UserPasswordPolicy
+func checkPasswordPolicy(field string, password string) helios.Error {
+	var violations []string = UserPasswordPolicy.Validate(password)
+	if len(violations) == 0 {
+		return nil
+	}
+	var err helios.ErrorForm = helios.NewErrorForm()
+	err.FieldError[field] = helios.ErrorFormFieldAtomic(violations)
+	return err
+}
+
+// applySessionPolicy removes the expired sessions of the user, then checks
+// the number of active sessions against the policy of user's role. If the
+// limit is reached, the oldest sessions are revoked or the login is rejected.
+func applySessionPolicy(user User) helios.Error {
+	var sessions []Session
+	var now time.Time = time.Now()
+	var activeSessions []Session
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("user_id = ?", user.ID).Order("created_at asc").Find(&sessions)); errDB != nil {
+		return errDB
+	}
+	for _, session := range sessions {
+		if session.IsExpired(now) {
+			if errDB := logging.CheckDB(user.RequestID, helios.DB.Delete(&session)); errDB != nil {
+				return errDB
+			}
+		} else {
+			activeSessions = append(activeSessions, session)
 		}
-		if idx := int(cache & userTokenIdxMask); idx < len(userTokenBytes) {
-			sb.WriteByte(userTokenBytes[idx])
-			i--
+	}
+
+	var policy SessionPolicy = SessionPolicies[user.Role]
+	if policy.MaxSessions == 0 || len(activeSessions) < policy.MaxSessions {
+		return nil
+	}
+	if !policy.RevokeOldest {
+		return errSessionLimitReached
+	}
+	for _, session := range activeSessions[:len(activeSessions)-policy.MaxSessions+1] {
+		if errDB := logging.CheckDB(user.RequestID, helios.DB.Delete(&session)); errDB != nil {
+			return errDB
+		}
+	}
+	return nil
+}
+
+// checkLoginThrottle checks the failed login attempts with given column
+// (username or ip_address) against the policy. It returns errLoginLocked
+// if the attempts exceed the maximum, or errLoginThrottled if the attempt
+// is made before the backoff time.
+func checkLoginThrottle(column string, value string, policy LoginThrottlePolicy, now time.Time) helios.Error {
+	var failedAttempts []LoginAttempt
+	errDB := logging.CheckDB("", helios.DB.
+		Where(column+" = ?", value).
+		Where("result = ?", LoginResultFailed).
+		Where("cleared = ?", false).
+		Where("created_at > ?", now.Add(-policy.Window)).
+		Order("created_at desc").
+		Find(&failedAttempts))
+	if errDB != nil {
+		return errDB
+	}
+
+	var failedCount int = len(failedAttempts)
+	if failedCount == 0 || failedCount < policy.FreeAttempts {
+		return nil
+	}
+
+	var lastFailedAt time.Time = failedAttempts[0].CreatedAt
+	if policy.MaxAttempts > 0 && failedCount >= policy.MaxAttempts {
+		if now.Before(lastFailedAt.Add(policy.LockoutDuration)) {
+			return errLoginLocked
 		}
-		cache >>= userTokenIdxBits
-		remain--
+		return nil
+	}
+
+	var backoff time.Duration = policy.BackoffBase * time.Duration(math.Pow(2, float64(failedCount-policy.FreeAttempts)))
+	if now.Before(lastFailedAt.Add(backoff)) {
+		var errThrottled helios.ErrorAPI = errLoginThrottled
+		errThrottled.Message = fmt.Sprintf(errLoginThrottled.Message, int(math.Ceil(lastFailedAt.Add(backoff).Sub(now).Seconds())))
+		return errThrottled
 	}
+	return nil
+}
 
-	return sb.String()
+// recordLoginAttempt saves the login attempt. If it is successful, the
+// previous failed attempts to the username are cleared.
+func recordLoginAttempt(username string, ip string, result string) helios.Error {
+	errDB := logging.CheckDB("", helios.DB.Create(&LoginAttempt{
+		Username:  username,
+		IPAddress: ip,
+		Result:    result,
+	}))
+	if errDB != nil {
+		return errDB
+	}
+	if result == LoginResultSuccess {
+		return logging.CheckDB("", helios.DB.Model(LoginAttempt{}).
+			Where("username = ?", username).
+			Where("result = ?", LoginResultFailed).
+			Update("cleared", true))
+	}
+	return nil
 }
 
 // Login will try to authenticate user and store the session
 // if it fails, it will give helios.Error, If it success, it will
-// return a new session
+// return a new session. Every attempt is recorded, and too many
+// failed attempts from the same username or IP address will be
+// throttled.
 func Login(username string, password string, ip string) (*Session, helios.Error) {
 	var user User
 	var session Session
-	var token string
+	var now time.Time = time.Now()
 
-	helios.DB.Where("username = ?", username).First(&user)
+	errThrottle := checkLoginThrottle("username", username, LoginThrottleByUsername, now)
+	if errThrottle == nil {
+		errThrottle = checkLoginThrottle("ip_address", ip, LoginThrottleByIP, now)
+	}
+	if errThrottle == helios.ErrInternalServerError {
+		return nil, errThrottle
+	}
+	if errThrottle != nil {
+		if errDB := recordLoginAttempt(username, ip, LoginResultBlocked); errDB != nil {
+			return nil, errDB
+		}
+		return nil, errThrottle
+	}
 
-	if user.ID == 0 {
-		return nil, errWrongUsernamePassword
+	if errDB := logging.CheckDB("", helios.DB.Where("username = ?", username).First(&user)); errDB != nil {
+		return nil, errDB
 	}
 
-	if !checkPasswordHash(password, user.Password) {
+	if user.ID == 0 || !checkPasswordHash(password, user.Password) {
+		if errDB := recordLoginAttempt(username, ip, LoginResultFailed); errDB != nil {
+			return nil, errDB
+		}
 		return nil, errWrongUsernamePassword
 	}
 
-	if user.SessionLocked {
-		return nil, errSessionLocked
+	errSessionPolicy := applySessionPolicy(user)
+	if errSessionPolicy == helios.ErrInternalServerError {
+		return nil, errSessionPolicy
+	}
+	if errSessionPolicy != nil {
+		if errDB := recordLoginAttempt(username, ip, LoginResultRejected); errDB != nil {
+			return nil, errDB
+		}
+		return nil, errSessionPolicy
 	}
 
-	token = generateUserToken()
+	token, errGenerateToken := generateUserToken()
+	if errGenerateToken != nil {
+		return nil, helios.ErrInternalServerError
+	}
 	session = Session{
-		UserID:    user.ID,
-		Token:     token,
-		User:      &user,
-		IPAddress: ip,
+		UserID:           user.ID,
+		Token:            token,
+		User:             &user,
+		IPAddress:        ip,
+		LastSeenAt:       time.Now(),
+		TwoFactorPending: user.TOTPEnabled,
+	}
+	user.IPAddress = ip
+	tx := helios.DB.Begin()
+	errDB := logging.CheckDB("", tx.Create(&session))
+	if errDB == nil {
+		errDB = RecordAuditLog(tx, user, auditActionSessionLogin, auditTargetSession, session.ID, nil, nil)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return nil, errDB
+	}
+	if errDB = logging.CheckDB("", tx.Commit()); errDB != nil {
+		return nil, errDB
+	}
+	var result string = LoginResultSuccess
+	if session.TwoFactorPending {
+		result = LoginResultTwoFactorPending
+	}
+	if errDB := recordLoginAttempt(username, ip, result); errDB != nil {
+		return nil, errDB
 	}
-	helios.DB.Create(&session)
 
 	return &session, nil
 }
 
-// Logout invalidates the session token
-func Logout(user User) {
-	user.SessionLocked = false
-	helios.DB.Save(&user)
-	helios.DB.Where("user_id = ?", user.ID).Delete(&Session{})
+// checkSecondFactor checks the TOTP code or an unused recovery code of
+// the user. The accepted code is consumed, so it can't be used again. The
+// code is consumed by conditional update, so when the same code is sent by
+// concurrent requests, only the request that updates the row accepts it.
+func checkSecondFactor(user *User, code string, now time.Time) (bool, helios.Error) {
+	if counter, ok := verifyTOTP(user.TOTPSecret, code, now, user.TOTPLastCounter); ok {
+		var result *gorm.DB = helios.DB.Model(User{}).
+			Where("id = ?", user.ID).
+			Where("totp_last_counter < ?", counter).
+			UpdateColumn("totp_last_counter", counter)
+		if errDB := logging.CheckDB(user.RequestID, result); errDB != nil {
+			return false, errDB
+		}
+		if result.RowsAffected == 0 {
+			return false, nil
+		}
+		user.TOTPLastCounter = counter
+		return true, nil
+	}
+	var recoveryCode RecoveryCode
+	errDB := logging.CheckDB(user.RequestID, helios.DB.
+		Where("user_id = ?", user.ID).
+		Where("code_hashed = ?", hashRecoveryCode(code)).
+		Where("used_at IS NULL").
+		First(&recoveryCode))
+	if errDB != nil {
+		return false, errDB
+	}
+	if recoveryCode.ID == 0 {
+		return false, nil
+	}
+	var result *gorm.DB = helios.DB.Model(RecoveryCode{}).
+		Where("id = ?", recoveryCode.ID).
+		Where("used_at IS NULL").
+		Update("used_at", now)
+	if errDB = logging.CheckDB(user.RequestID, result); errDB != nil {
+		return false, errDB
+	}
+	return result.RowsAffected > 0, nil
 }
 
-// GetAllUser returns all users with lower role.
-func GetAllUser(user User) []User {
+// verifySecondFactor checks the code of the user with checkSecondFactor,
+// throttled the same way as the password. Wrong code is recorded as
+// failed login attempt.
+func verifySecondFactor(user *User, code string, ip string) helios.Error {
+	var now time.Time = time.Now()
+	errThrottle := checkLoginThrottle("username", user.Username, LoginThrottleByUsername, now)
+	if errThrottle == nil {
+		errThrottle = checkLoginThrottle("ip_address", ip, LoginThrottleByIP, now)
+	}
+	if errThrottle == helios.ErrInternalServerError {
+		return errThrottle
+	}
+	if errThrottle != nil {
+		if errDB := recordLoginAttempt(user.Username, ip, LoginResultBlocked); errDB != nil {
+			return errDB
+		}
+		return errThrottle
+	}
+	accepted, errDB := checkSecondFactor(user, code, now)
+	if errDB != nil {
+		return errDB
+	}
+	if !accepted {
+		if errDB = recordLoginAttempt(user.Username, ip, LoginResultFailed); errDB != nil {
+			return errDB
+		}
+		return errWrongTwoFactorCode
+	}
+	return nil
+}
+
+// replaceRecoveryCodes removes the recovery codes of the user and
+// saves the hash of the new codes
+func replaceRecoveryCodes(tx *gorm.DB, user User, codes []string) helios.Error {
+	if errDB := logging.CheckDB(user.RequestID, tx.Where("user_id = ?", user.ID).Delete(RecoveryCode{})); errDB != nil {
+		return errDB
+	}
+	for _, code := range codes {
+		if errDB := logging.CheckDB(user.RequestID, tx.Create(&RecoveryCode{UserID: user.ID, CodeHashed: hashRecoveryCode(code)})); errDB != nil {
+			return errDB
+		}
+	}
+	return nil
+}
+
+// VerifyLogin completes the login of the session waiting for the second
+// factor, with TOTP code or recovery code
+func VerifyLogin(session Session, code string, ip string) helios.Error {
+	if !session.TwoFactorPending {
+		return errTwoFactorNotPending
+	}
+	var user User
+	var requestID string = sessionRequestID(session)
+	if errDB := logging.CheckDB(requestID, helios.DB.Where("id = ?", session.UserID).First(&user)); errDB != nil {
+		return errDB
+	}
+	if user.ID == 0 {
+		return errUserNotFound
+	}
+	user.RequestID = requestID
+	user.IPAddress = ip
+	if err := verifySecondFactor(&user, code, ip); err != nil {
+		return err
+	}
+	tx := helios.DB.Begin()
+	errDB := logging.CheckDB(requestID, tx.Model(&session).UpdateColumn("two_factor_pending", false))
+	if errDB == nil {
+		errDB = RecordAuditLog(tx, user, auditActionSessionVerify, auditTargetSession, session.ID, nil, nil)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	if errDB = logging.CheckDB(requestID, tx.Commit()); errDB != nil {
+		return errDB
+	}
+	return recordLoginAttempt(user.Username, ip, LoginResultSuccess)
+}
+
+// EnrollTwoFactor generates new TOTP secret of the user. It returns the
+// secret and its provisioning URI to be added to the authenticator app.
+// The secret is not used until it is confirmed by ConfirmTwoFactor.
+func EnrollTwoFactor(user User) (string, string, helios.Error) {
+	if user.TOTPEnabled {
+		return "", "", errTwoFactorAlreadyEnabled
+	}
+	secret, errGenerate := generateTOTPSecret()
+	if errGenerate != nil {
+		return "", "", helios.ErrInternalServerError
+	}
+	tx := helios.DB.Begin()
+	errDB := logging.CheckDB(user.RequestID, tx.Model(&user).UpdateColumn("totp_secret", secret))
+	if errDB == nil {
+		errDB = RecordAuditLog(tx, user, auditActionTwoFactorEnroll, auditTargetUser, user.ID, nil, nil)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return "", "", errDB
+	}
+	if errDB = logging.CheckDB(user.RequestID, tx.Commit()); errDB != nil {
+		return "", "", errDB
+	}
+	return secret, totpProvisioningURI(secret, user.Username), nil
+}
+
+// ConfirmTwoFactor enables the two-factor authentication of the user after
+// checking the code generated from the enrolled secret. It returns the
+// recovery codes in plain, only their hashes are stored.
+func ConfirmTwoFactor(user User, code string) ([]string, helios.Error) {
+	if user.TOTPEnabled {
+		return nil, errTwoFactorAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, errTwoFactorNotEnrolled
+	}
+	counter, ok := verifyTOTP(user.TOTPSecret, code, time.Now(), user.TOTPLastCounter)
+	if !ok {
+		return nil, errWrongTwoFactorCode
+	}
+	codes, errGenerate := generateRecoveryCodes()
+	if errGenerate != nil {
+		return nil, helios.ErrInternalServerError
+	}
+
+	tx := helios.DB.Begin()
+	errDB := logging.CheckDB(user.RequestID, tx.Model(&user).Updates(map[string]interface{}{
+		"totp_enabled":      true,
+		"totp_last_counter": counter,
+	}))
+	if errDB == nil {
+		errDB = replaceRecoveryCodes(tx, user, codes)
+	}
+	if errDB == nil {
+		errDB = RecordAuditLog(tx, user, auditActionTwoFactorEnable, auditTargetUser, user.ID, nil, nil)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return nil, errDB
+	}
+	if errDB = logging.CheckDB(user.RequestID, tx.Commit()); errDB != nil {
+		return nil, errDB
+	}
+	return codes, nil
+}
+
+// DisableTwoFactor disables the two-factor authentication of the user after
+// checking the code. It can't be disabled if it is required for user's role.
+func DisableTwoFactor(user User, code string, ip string) helios.Error {
+	if !user.TOTPEnabled {
+		return errTwoFactorNotEnabled
+	}
+	if TwoFactorRequiredRoles[user.Role] {
+		return errTwoFactorRequiredByPolicy
+	}
+	if err := verifySecondFactor(&user, code, ip); err != nil {
+		return err
+	}
+
+	tx := helios.DB.Begin()
+	errDB := clearTwoFactor(tx, user, user.ID)
+	if errDB == nil {
+		errDB = RecordAuditLog(tx, user, auditActionTwoFactorDisable, auditTargetUser, user.ID, nil, nil)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	return logging.CheckDB(user.RequestID, tx.Commit())
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the user after
+// checking the code. It returns the new codes in plain.
+func RegenerateRecoveryCodes(user User, code string, ip string) ([]string, helios.Error) {
+	if !user.TOTPEnabled {
+		return nil, errTwoFactorNotEnabled
+	}
+	if err := verifySecondFactor(&user, code, ip); err != nil {
+		return nil, err
+	}
+	codes, errGenerate := generateRecoveryCodes()
+	if errGenerate != nil {
+		return nil, helios.ErrInternalServerError
+	}
+
+	tx := helios.DB.Begin()
+	errDB := replaceRecoveryCodes(tx, user, codes)
+	if errDB == nil {
+		errDB = RecordAuditLog(tx, user, auditActionRecoveryCodeRegenerate, auditTargetUser, user.ID, nil, nil)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return nil, errDB
+	}
+	if errDB = logging.CheckDB(user.RequestID, tx.Commit()); errDB != nil {
+		return nil, errDB
+	}
+	return codes, nil
+}
+
+// ResetTwoFactor disables the two-factor authentication of the user with
+// given username, used when the user loses the authenticator app and the
+// recovery codes. Only user permitted to manage the users of the target's
+// role can reset. The sessions of the user are revoked, so the
+// user has to enroll again on the next login if it is required.
+func ResetTwoFactor(user User, username string) helios.Error {
+	if !Can(user, ActionUserManage, Resource{}) {
+		return errUserChangeNotAuthorized
+	}
+	var targetUser User
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("username = ?", username).Where("role in (?)", GetManageableUserRoles(user)).First(&targetUser)); errDB != nil {
+		return errDB
+	}
+	if targetUser.ID == 0 {
+		return errUserNotFound
+	}
+
+	tx := helios.DB.Begin()
+	errDB := clearTwoFactor(tx, user, targetUser.ID)
+	if errDB == nil {
+		errDB = logging.CheckDB(user.RequestID, tx.Where("user_id = ?", targetUser.ID).Delete(Session{}))
+	}
+	if errDB == nil {
+		errDB = RecordAuditLog(tx, user, auditActionTwoFactorReset, auditTargetUser, targetUser.ID, nil, nil)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	return logging.CheckDB(user.RequestID, tx.Commit())
+}
+
+// clearTwoFactor removes the TOTP secret and recovery codes of the user
+// with given id, on the request of the user
+func clearTwoFactor(tx *gorm.DB, user User, userID uint) helios.Error {
+	errDB := logging.CheckDB(user.RequestID, tx.Model(User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"totp_enabled":      false,
+		"totp_secret":       "",
+		"totp_last_counter": 0,
+	}))
+	if errDB != nil {
+		return errDB
+	}
+	return logging.CheckDB(user.RequestID, tx.Where("user_id = ?", userID).Delete(RecoveryCode{}))
+}
+
+// UnlockUserLogin clears the failed login attempts of the user with given
+// username, so the user can log in again without waiting. Only user permitted
+// to manage login attempts can unlock, and only for the users of the roles
+// they are permitted to manage.
+func UnlockUserLogin(user User, username string) helios.Error {
+	if !Can(user, ActionLoginAttemptManage, Resource{}) {
+		return errLoginAttemptAccessNotAuthorized
+	}
+	var targetUser User
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("username = ?", username).Where("role in (?)", GetManageableUserRoles(user)).First(&targetUser)); errDB != nil {
+		return errDB
+	}
+	if targetUser.ID == 0 {
+		return errUserNotFound
+	}
+	tx := helios.DB.Begin()
+	errDB := logging.CheckDB(user.RequestID, tx.Model(LoginAttempt{}).
+		Where("username = ?", targetUser.Username).
+		Where("result = ?", LoginResultFailed).
+		Update("cleared", true))
+	if errDB == nil {
+		errDB = RecordAuditLog(tx, user, auditActionUserUnlock, auditTargetUser, targetUser.ID, nil, nil)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	return logging.CheckDB(user.RequestID, tx.Commit())
+}
+
+// UnlockIPLogin clears the failed login attempts from the IP address, so the
+// users behind it, e.g. the participants of a venue sharing the address, can
+// log in again without waiting. Only user permitted to manage login attempts
+// can unlock.
+func UnlockIPLogin(user User, ipAddress string) helios.Error {
+	if !Can(user, ActionLoginAttemptManage, Resource{}) {
+		return errLoginAttemptAccessNotAuthorized
+	}
+	tx := helios.DB.Begin()
+	errDB := logging.CheckDB(user.RequestID, tx.Model(LoginAttempt{}).
+		Where("ip_address = ?", ipAddress).
+		Where("result = ?", LoginResultFailed).
+		Update("cleared", true))
+	if errDB == nil {
+		errDB = RecordAuditLog(tx, user, auditActionIPUnlock, auditTargetIPAddress, 0, nil, map[string]string{"IPAddress": ipAddress})
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	return logging.CheckDB(user.RequestID, tx.Commit())
+}
+
+// GetAllLoginAttempt returns the latest login attempts, the newest first,
+// to be monitored by the proctor
+func GetAllLoginAttempt(user User) ([]LoginAttempt, helios.Error) {
+	if !Can(user, ActionLoginAttemptManage, Resource{}) {
+		return nil, errLoginAttemptAccessNotAuthorized
+	}
+	var loginAttempts []LoginAttempt
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Order("created_at desc").Order("id desc").Limit(loginAttemptListLimit).Find(&loginAttempts)); errDB != nil {
+		return nil, errDB
+	}
+	return loginAttempts, nil
+}
+
+// Logout invalidates the session token. The other sessions
+// of the user are kept
+func Logout(session Session) helios.Error {
+	var requestID string = sessionRequestID(session)
+	var user User = User{ID: session.UserID, IPAddress: session.IPAddress}
+	if session.User != nil {
+		user = *session.User
+	}
+	tx := helios.DB.Begin()
+	errDB := logging.CheckDB(requestID, tx.Delete(&session))
+	if errDB == nil {
+		errDB = RecordAuditLog(tx, user, auditActionSessionLogout, auditTargetSession, session.ID, nil, nil)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	return logging.CheckDB(requestID, tx.Commit())
+}
+
+// GetAllSessionOfUser returns the active sessions of the user,
+// the newest first
+func GetAllSessionOfUser(user User) ([]Session, helios.Error) {
+	var sessions []Session
+	var activeSessions []Session = make([]Session, 0)
+	var now time.Time = time.Now()
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("user_id = ?", user.ID).Order("created_at desc").Find(&sessions)); errDB != nil {
+		return nil, errDB
+	}
+	for _, session := range sessions {
+		if !session.IsExpired(now) {
+			activeSessions = append(activeSessions, session)
+		}
+	}
+	return activeSessions, nil
+}
+
+// RevokeSession invalidates the session with given id. User can only
+// revoke their own session
+func RevokeSession(user User, sessionID uint) helios.Error {
+	var session Session
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("id = ?", sessionID).Where("user_id = ?", user.ID).First(&session)); errDB != nil {
+		return errDB
+	}
+	if session.ID == 0 {
+		return errSessionNotFound
+	}
+	tx := helios.DB.Begin()
+	errDB := logging.CheckDB(user.RequestID, tx.Delete(&session))
+	if errDB == nil {
+		errDB = RecordAuditLog(tx, user, auditActionSessionRevoke, auditTargetSession, session.ID, session, nil)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	return logging.CheckDB(user.RequestID, tx.Commit())
+}
+
+// GetAllUser returns all users of the roles that the user is permitted to
+// manage. It is empty if the user is not permitted to manage users.
+func GetAllUser(user User) ([]User, helios.Error) {
 	var users []User
-	helios.DB.Where("role < ?", user.Role).Find(&users)
-	return users
+	if !Can(user, ActionUserManage, Resource{}) {
+		return users, nil
+	}
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("role in (?)", GetManageableUserRoles(user)).Find(&users)); errDB != nil {
+		return nil, errDB
+	}
+	return users, nil
 }
 
 // UpsertUser creates or updates a user. It creates if
-// ID = 0, or updates otherwise. The role should be
-// less then invoker's role
-// If it is create, then user.ID will be changed.
+// ID = 0, or updates otherwise. The invoker should be permitted to
+// manage the users of the role, both before and after the update.
+// If it is create, then user.ID will be changed. The created user must
+// change the password on the first login, because it is given by the invoker.
 func UpsertUser(user User, newUser *User) helios.Error {
-	if newUser.Role >= user.Role {
+	if !Can(user, ActionUserManage, Resource{}) {
+		return errUserChangeNotAuthorized
+	}
+	if !Can(user, UserManageAction(newUser.Role), Resource{}) {
 		return errUserRoleTooHigh
 	}
 
-	newUser.Password = hashPassword(newUser.Password)
+	tx := helios.DB.Begin()
+	var errDB helios.Error
 	if newUser.ID == 0 {
-		helios.DB.Create(newUser)
+		newUser.Password = hashPassword(newUser.Password)
+		newUser.MustChangePassword = true
+		errDB = logging.CheckDB(user.RequestID, tx.Create(newUser))
+		if errDB == nil {
+			errDB = RecordAuditLog(tx, user, auditActionUserCreate, auditTargetUser, newUser.ID, nil, *newUser)
+		}
 	} else {
-		helios.DB.Save(newUser)
+		var userBefore, userAfter User
+		errDB = logging.CheckDB(user.RequestID, tx.Where("id = ?", newUser.ID).First(&userBefore))
+		if errDB == nil && userBefore.ID != 0 && !Can(user, UserManageAction(userBefore.Role), Resource{}) {
+			tx.Rollback()
+			return errUserRoleTooHigh
+		}
+		if errDB == nil {
+			errDB = logging.CheckDB(user.RequestID, tx.Omit("password", "must_change_password").Save(newUser))
+		}
+		if errDB == nil {
+			errDB = logging.CheckDB(user.RequestID, tx.Where("id = ?", newUser.ID).First(&userAfter))
+		}
+		if errDB == nil {
+			errDB = RecordAuditLog(tx, user, auditActionUserUpdate, auditTargetUser, newUser.ID, userBefore, userAfter)
+		}
 	}
-	return nil
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	return logging.CheckDB(user.RequestID, tx.Commit())
 }
 
-// LockUserSession locks user so they can't login from new device
-func LockUserSession(user User, username string) helios.Error {
-	var targetUser User
-	helios.DB.Where("username = ?", username).Where("role < ?", user.Role).First(&targetUser)
-	if targetUser.ID == 0 {
+// ChangePassword changes the password of the user of the session after
+// checking the old password. The other sessions of the user are revoked.
+func ChangePassword(session Session, oldPassword string, newPassword string) helios.Error {
+	var user User
+	var requestID string = sessionRequestID(session)
+	if errDB := logging.CheckDB(requestID, helios.DB.Where("id = ?", session.UserID).First(&user)); errDB != nil {
+		return errDB
+	}
+	if user.ID == 0 {
 		return errUserNotFound
 	}
-	targetUser.SessionLocked = true
-	helios.DB.Save(&targetUser)
-	return nil
+	if !checkPasswordHash(oldPassword, user.Password) {
+		return errWrongPassword
+	}
+	if err := checkPasswordPolicy("newPassword", newPassword); err != nil {
+		return err
+	}
+	if checkPasswordHash(newPassword, user.Password) {
+		return errPasswordNotChanged
+	}
+
+	tx := helios.DB.Begin()
+	errDB := logging.CheckDB(requestID, tx.Model(&user).Updates(map[string]interface{}{
+		"password":             hashPassword(newPassword),
+		"must_change_password": false,
+	}))
+	if errDB == nil {
+		errDB = logging.CheckDB(requestID, tx.Where("user_id = ?", user.ID).Where("id <> ?", session.ID).Delete(Session{}))
+	}
+	if errDB == nil {
+		user.IPAddress = session.IPAddress
+		user.RequestID = requestID
+		errDB = RecordAuditLog(tx, user, auditActionPasswordChange, auditTargetUser, user.ID, nil, nil)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	return logging.CheckDB(requestID, tx.Commit())
 }
 
-// UnlockUserSession reverse the lock effect, so the user can login from new device
-func UnlockUserSession(user User, username string) helios.Error {
+// IssuePasswordResetToken issues a one-time token to reset the password of
+// the user with given username. Only user permitted to manage the users of
+// the target's role can issue. The previous tokens of the user are
+// invalidated. The token is returned in plain, only its hash is stored.
+func IssuePasswordResetToken(user User, username string) (string, *PasswordResetToken, helios.Error) {
+	if !Can(user, ActionUserManage, Resource{}) {
+		return "", nil, errUserChangeNotAuthorized
+	}
 	var targetUser User
-	helios.DB.Where("username = ?", username).Where("role < ?", user.Role).First(&targetUser)
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("username = ?", username).Where("role in (?)", GetManageableUserRoles(user)).First(&targetUser)); errDB != nil {
+		return "", nil, errDB
+	}
 	if targetUser.ID == 0 {
-		return errUserNotFound
+		return "", nil, errUserNotFound
+	}
+
+	token, errGenerateToken := generateUserToken()
+	if errGenerateToken != nil {
+		return "", nil, helios.ErrInternalServerError
+	}
+	var resetToken PasswordResetToken = PasswordResetToken{
+		UserID:      targetUser.ID,
+		TokenHashed: hashToken(token),
+		ExpiresAt:   time.Now().Add(PasswordResetTokenMaxAge),
+		User:        &targetUser,
+	}
+	tx := helios.DB.Begin()
+	errDB := logging.CheckDB(user.RequestID, tx.Where("user_id = ?", targetUser.ID).Where("used_at IS NULL").Delete(PasswordResetToken{}))
+	if errDB == nil {
+		errDB = logging.CheckDB(user.RequestID, tx.Create(&resetToken))
+	}
+	if errDB == nil {
+		errDB = RecordAuditLog(tx, user, auditActionPasswordResetIssue, auditTargetUser, targetUser.ID, nil, nil)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return "", nil, errDB
+	}
+	if errDB = logging.CheckDB(user.RequestID, tx.Commit()); errDB != nil {
+		return "", nil, errDB
+	}
+	return token, &resetToken, nil
+}
+
+// ResetPassword sets the password of the user of the reset token. The token
+// can only be used once before it expires. All sessions of the user are
+// revoked and the failed login attempts are cleared, so the user can log in
+// with the new password right away.
+func ResetPassword(token string, newPassword string) helios.Error {
+	var resetToken PasswordResetToken
+	var now time.Time = time.Now()
+	errDB := logging.CheckDB("", helios.DB.
+		Where("token_hashed = ?", hashToken(token)).
+		Where("used_at IS NULL").
+		Preload("User").
+		First(&resetToken))
+	if errDB != nil {
+		return errDB
+	}
+	if resetToken.ID == 0 || resetToken.User == nil || now.After(resetToken.ExpiresAt) {
+		return errPasswordResetTokenInvalid
+	}
+	if err := checkPasswordPolicy("newPassword", newPassword); err != nil {
+		return err
+	}
+
+	var user User = *resetToken.User
+	tx := helios.DB.Begin()
+	for _, result := range []*gorm.DB{
+		tx.Model(&user).Updates(map[string]interface{}{
+			"password":             hashPassword(newPassword),
+			"must_change_password": false,
+		}),
+		tx.Model(&resetToken).Update("used_at", now),
+		tx.Where("user_id = ?", user.ID).Delete(Session{}),
+		tx.Model(LoginAttempt{}).
+			Where("username = ?", user.Username).
+			Where("result = ?", LoginResultFailed).
+			Update("cleared", true),
+	} {
+		if errDB = logging.CheckDB("", result); errDB != nil {
+			tx.Rollback()
+			return errDB
+		}
+	}
+	if errDB = RecordAuditLog(tx, user, auditActionPasswordReset, auditTargetUser, user.ID, nil, nil); errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	return logging.CheckDB("", tx.Commit())
+}
+
+// GetAllAPITokenOfUser returns the API tokens of the user, including the
+// revoked ones, the newest first
+func GetAllAPITokenOfUser(user User) ([]APIToken, helios.Error) {
+	var apiTokens []APIToken
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("user_id = ?", user.ID).Preload("Scopes").Order("created_at desc").Order("id desc").Find(&apiTokens)); errDB != nil {
+		return nil, errDB
+	}
+	return apiTokens, nil
+}
+
+// IssueAPIToken issues API token for the user, to be used by the local
+// server. Only user permitted to issue API tokens can issue, which is the
+// local user. The scopes are not checked against the events of the user,
+// because the token doesn't grant more than the user is permitted. The token
+// is returned in plain, only its hash is stored.
+func IssueAPIToken(user User, apiToken *APIToken) (string, helios.Error) {
+	if !Can(user, ActionAPITokenIssue, Resource{}) {
+		return "", errAPITokenIssueNotAuthorized
+	}
+
+	token, errGenerateToken := generateUserToken()
+	if errGenerateToken != nil {
+		return "", helios.ErrInternalServerError
+	}
+	apiToken.ID = 0
+	apiToken.UserID = user.ID
+	apiToken.Prefix = tokenPrefix(token)
+	apiToken.TokenHashed = hashToken(token)
+	apiToken.ExpiresAt = nil
+	apiToken.LastUsedAt = nil
+	apiToken.RevokedAt = nil
+	if APITokenMaxAge > 0 {
+		var expiresAt time.Time = time.Now().Add(APITokenMaxAge)
+		apiToken.ExpiresAt = &expiresAt
+	}
+	tx := helios.DB.Begin()
+	errDB := logging.CheckDB(user.RequestID, tx.Create(apiToken))
+	if errDB == nil {
+		errDB = RecordAuditLog(tx, user, auditActionAPITokenIssue, auditTargetAPIToken, apiToken.ID, nil, *apiToken)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return "", errDB
+	}
+	if errDB = logging.CheckDB(user.RequestID, tx.Commit()); errDB != nil {
+		return "", errDB
+	}
+	return token, nil
+}
+
+// getAPITokenOfUser returns the API token with given id, that is owned by
+// the user or by the users of the roles that the user is permitted to manage
+func getAPITokenOfUser(user User, apiTokenID uint) (APIToken, helios.Error) {
+	var apiToken APIToken
+	var query *gorm.DB = helios.DB.Where("id = ?", apiTokenID)
+	if Can(user, ActionUserManage, Resource{}) {
+		query = query.Where("user_id = ? or user_id in ?", user.ID, helios.DB.Model(User{}).Select("id").Where("role in (?)", GetManageableUserRoles(user)).SubQuery())
+	} else {
+		query = query.Where("user_id = ?", user.ID)
+	}
+	if errDB := logging.CheckDB(user.RequestID, query.Preload("Scopes").First(&apiToken)); errDB != nil {
+		return apiToken, errDB
+	}
+	if apiToken.ID == 0 {
+		return apiToken, errAPITokenNotFound
+	}
+	return apiToken, nil
+}
+
+// RevokeAPIToken revokes the API token with given id. User can revoke their
+// own token, and user permitted to manage users can revoke the token of the
+// users of the roles they manage, e.g. when the local server is compromised.
+func RevokeAPIToken(user User, apiTokenID uint) (*APIToken, helios.Error) {
+	apiToken, err := getAPITokenOfUser(user, apiTokenID)
+	if err != nil {
+		return nil, err
+	}
+	if apiToken.RevokedAt == nil {
+		var now time.Time = time.Now()
+		apiToken.RevokedAt = &now
+		tx := helios.DB.Begin()
+		errDB := logging.CheckDB(user.RequestID, tx.Model(&apiToken).UpdateColumn("revoked_at", now))
+		if errDB == nil {
+			errDB = RecordAuditLog(tx, user, auditActionAPITokenRevoke, auditTargetAPIToken, apiToken.ID, nil, nil)
+		}
+		if errDB != nil {
+			tx.Rollback()
+			return nil, errDB
+		}
+		if errDB = logging.CheckDB(user.RequestID, tx.Commit()); errDB != nil {
+			return nil, errDB
+		}
+	}
+	return &apiToken, nil
+}
+
+// GetAllAPITokenUsage returns the latest usages of the API token with given
+// id, the newest first. The token is looked up like RevokeAPIToken.
+func GetAllAPITokenUsage(user User, apiTokenID uint) ([]APITokenUsage, helios.Error) {
+	apiToken, err := getAPITokenOfUser(user, apiTokenID)
+	if err != nil {
+		return nil, err
+	}
+	var usages []APITokenUsage
+	errDB := logging.CheckDB(user.RequestID, helios.DB.
+		Where("api_token_id = ?", apiToken.ID).
+		Order("created_at desc").
+		Order("id desc").
+		Limit(apiTokenUsageListLimit).
+		Find(&usages))
+	if errDB != nil {
+		return nil, errDB
+	}
+	return usages, nil
+}
+
+// GetAllAuditLog returns a page of audit log matching the filter, the newest
+// first. Only user permitted to view audit log can access this use case.
+func GetAllAuditLog(user User, filter AuditLogFilter) ([]AuditLog, helios.Error) {
+	if !Can(user, ActionAuditLogView, Resource{}) {
+		return nil, errAuditLogAccessNotAuthorized
+	}
+	var auditLogs []AuditLog
+	if errDB := logging.CheckDB(user.RequestID, filter.apply(helios.DB).Order("id desc").Limit(auditLogListLimit).Find(&auditLogs)); errDB != nil {
+		return nil, errDB
+	}
+	return auditLogs, nil
+}
+
+// ExportAuditLog returns all audit log matching the filter, the oldest first,
+// so the hash chain can be checked outside of the app.
+func ExportAuditLog(user User, filter AuditLogFilter) ([]AuditLog, helios.Error) {
+	if !Can(user, ActionAuditLogView, Resource{}) {
+		return nil, errAuditLogAccessNotAuthorized
+	}
+	var auditLogs []AuditLog
+	if errDB := logging.CheckDB(user.RequestID, filter.apply(helios.DB).Order("id asc").Find(&auditLogs)); errDB != nil {
+		return nil, errDB
+	}
+	return auditLogs, nil
+}
+
+// VerifyAuditLog recomputes the hash chain of the audit log from the first
+// entry, and stops at the first entry that is changed or whose previous
+// entry is removed.
+func VerifyAuditLog(user User) (*AuditLogVerification, helios.Error) {
+	if !Can(user, ActionAuditLogView, Resource{}) {
+		return nil, errAuditLogAccessNotAuthorized
+	}
+	var verification AuditLogVerification
+	var lastID uint
+	for {
+		var auditLogs []AuditLog
+		if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("id > ?", lastID).Order("id asc").Limit(auditLogVerifyBatchSize).Find(&auditLogs)); errDB != nil {
+			return nil, errDB
+		}
+		for _, auditLog := range auditLogs {
+			if auditLog.PrevHash != verification.LastHash || hashAuditLog(auditLog) != auditLog.Hash {
+				verification.BrokenID = auditLog.ID
+				return &verification, nil
+			}
+			verification.Checked++
+			verification.LastHash = auditLog.Hash
+			lastID = auditLog.ID
+		}
+		if len(auditLogs) < auditLogVerifyBatchSize {
+			return &verification, nil
+		}
 	}
-	targetUser.SessionLocked = false
-	helios.DB.Save(&targetUser)
-	return nil
 }
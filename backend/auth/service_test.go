@@ -2,6 +2,7 @@ package auth
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -11,6 +12,10 @@ import (
 func TestLogin(t *testing.T) {
 	helios.App.BeforeTest()
 
+	var userLoggedIn User = UserFactorySaved(User{Username: "user2", Password: "def", Role: UserRoleParticipant})
+	var userExpired User = UserFactorySaved(User{Username: "user5", Password: "def", Role: UserRoleParticipant})
+	helios.DB.Create(&Session{Token: "token-user2", UserID: userLoggedIn.ID, IPAddress: "1.2.3.4"})
+	helios.DB.Create(&Session{Token: "token-user5", UserID: userExpired.ID, IPAddress: "1.2.3.4", CreatedAt: time.Now().Add(-SessionMaxAge - time.Minute)})
 	type loginTestCase struct {
 		user          User
 		username      string
@@ -22,11 +27,17 @@ func TestLogin(t *testing.T) {
 		user:     UserFactorySaved(User{Username: "user1", Password: "def"}),
 		username: "user1",
 		password: "def",
+		ipAddr:   "1.2.3.4",
 	}, {
-		user:          UserFactorySaved(User{Username: "user2", Password: "def", SessionLocked: true}),
+		user:          userLoggedIn,
 		username:      "user2",
 		password:      "def",
-		expectedError: errSessionLocked,
+		expectedError: errSessionLimitReached,
+	}, {
+		user:     userExpired,
+		username: "user5",
+		password: "def",
+		ipAddr:   "0000:0000:0000:0000:0000:ffff:192.168.100.228",
 	}, {
 		user:          UserFactorySaved(User{Username: "user3", Password: "def"}),
 		username:      "def",
@@ -43,7 +54,7 @@ func TestLogin(t *testing.T) {
 		var userSession *Session
 		var userSessionSaved Session
 		var err helios.Error
-		userSession, err = Login(testCase.username, testCase.password, "1.2.3.4")
+		userSession, err = Login(testCase.username, testCase.password, testCase.ipAddr)
 		if testCase.expectedError == nil {
 			helios.DB.Where("token = ?", userSession.Token).First(&userSessionSaved)
 			assert.Nil(t, err)
@@ -52,7 +63,11 @@ func TestLogin(t *testing.T) {
 			assert.Equal(t, testCase.user.ID, userSession.UserID)
 			assert.NotEqual(t, 0, userSessionSaved.ID, "Session not saved on database")
 			assert.Equal(t, testCase.user.ID, userSessionSaved.UserID)
-			assert.Equal(t, "1.2.3.4", userSessionSaved.IPAddress)
+			assert.Equal(t, testCase.ipAddr, userSessionSaved.IPAddress)
+			var auditLog AuditLog
+			helios.DB.Where("action = ?", auditActionSessionLogin).Where("target_id = ?", userSessionSaved.ID).First(&auditLog)
+			assert.Equal(t, testCase.user.ID, auditLog.ActorID)
+			assert.Equal(t, testCase.ipAddr, auditLog.IPAddress)
 		} else {
 			assert.Equal(t, testCase.expectedError, err)
 			assert.Nil(t, userSession)
@@ -60,6 +75,194 @@ func TestLogin(t *testing.T) {
 	}
 }
 
+func TestCheckLoginThrottle(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var now time.Time = time.Now()
+	var policy LoginThrottlePolicy = LoginThrottlePolicy{
+		FreeAttempts:    2,
+		BackoffBase:     10 * time.Second,
+		MaxAttempts:     4,
+		LockoutDuration: time.Minute,
+		Window:          time.Hour,
+	}
+	createFailedAttempts := func(username string, count int, lastFailedAt time.Time, cleared bool) {
+		for i := 0; i < count; i++ {
+			helios.DB.Create(&LoginAttempt{
+				Username:  username,
+				IPAddress: "1.2.3.4",
+				Result:    LoginResultFailed,
+				Cleared:   cleared,
+				CreatedAt: lastFailedAt.Add(time.Duration(-i) * time.Second),
+			})
+		}
+	}
+	createFailedAttempts("free", 1, now, false)
+	createFailedAttempts("backoff", 3, now.Add(-10*time.Second), false)
+	createFailedAttempts("backoff_passed", 3, now.Add(-21*time.Second), false)
+	createFailedAttempts("locked", 4, now.Add(-30*time.Second), false)
+	createFailedAttempts("lock_passed", 4, now.Add(-2*time.Minute), false)
+	createFailedAttempts("cleared", 4, now, true)
+	createFailedAttempts("old", 4, now.Add(-2*time.Hour), false)
+	helios.DB.Create(&LoginAttempt{Username: "blocked", Result: LoginResultBlocked})
+
+	type checkLoginThrottleTestCase struct {
+		username           string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []checkLoginThrottleTestCase{{
+		username: "free",
+	}, {
+		username:           "backoff",
+		expectedStatusCode: errLoginThrottled.StatusCode,
+		expectedErrorCode:  errLoginThrottled.Code,
+	}, {
+		username: "backoff_passed",
+	}, {
+		username:           "locked",
+		expectedStatusCode: errLoginLocked.StatusCode,
+		expectedErrorCode:  errLoginLocked.Code,
+	}, {
+		username: "lock_passed",
+	}, {
+		username: "cleared",
+	}, {
+		username: "old",
+	}, {
+		username: "blocked",
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test CheckLoginThrottle testcase: %d", i)
+		err := checkLoginThrottle("username", testCase.username, policy, now)
+		if testCase.expectedErrorCode == "" {
+			assert.Nil(t, err)
+		} else if assert.NotNil(t, err) {
+			assert.Equal(t, testCase.expectedStatusCode, err.GetStatusCode())
+			assert.Equal(t, testCase.expectedErrorCode, err.GetMessage()["code"])
+		}
+	}
+}
+
+func TestLoginThrottled(t *testing.T) {
+	helios.App.BeforeTest()
+
+	UserFactorySaved(User{Username: "user1", Password: "def"})
+	for i := 0; i < LoginThrottleByUsername.FreeAttempts; i++ {
+		_, err := Login("user1", "wrong", "1.2.3.4")
+		assert.Equal(t, errWrongUsernamePassword, err)
+	}
+	_, err := Login("user1", "def", "1.2.3.4")
+	if assert.NotNil(t, err, "Login should be throttled even with correct password") {
+		assert.Equal(t, errLoginThrottled.Code, err.GetMessage()["code"])
+	}
+
+	var failedCount, blockedCount int
+	helios.DB.Model(LoginAttempt{}).Where("username = ?", "user1").Where("result = ?", LoginResultFailed).Count(&failedCount)
+	helios.DB.Model(LoginAttempt{}).Where("username = ?", "user1").Where("result = ?", LoginResultBlocked).Count(&blockedCount)
+	assert.Equal(t, LoginThrottleByUsername.FreeAttempts, failedCount)
+	assert.Equal(t, 1, blockedCount)
+
+	var userLocal User = UserFactorySaved(User{Role: UserRoleLocal})
+	assert.Nil(t, UnlockUserLogin(userLocal, "user1"))
+	session, err := Login("user1", "def", "1.2.3.4")
+	assert.Nil(t, err, "Login should be allowed after unlocked")
+	assert.NotNil(t, session)
+}
+
+func TestUnlockUserLogin(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user1 User = UserFactorySaved(User{Role: UserRoleParticipant})
+	helios.DB.Create(&LoginAttempt{Username: user1.Username, Result: LoginResultFailed})
+	type unlockUserLoginTestCase struct {
+		user          User
+		username      string
+		expectedError helios.Error
+	}
+	testCases := []unlockUserLoginTestCase{{
+		user:          UserFactorySaved(User{Role: UserRoleParticipant}),
+		username:      user1.Username,
+		expectedError: errLoginAttemptAccessNotAuthorized,
+	}, {
+		user:          UserFactorySaved(User{Role: UserRoleLocal}),
+		username:      "random",
+		expectedError: errUserNotFound,
+	}, {
+		user:     UserFactorySaved(User{Role: UserRoleLocal}),
+		username: user1.Username,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test UnlockUserLogin testcase: %d", i)
+		var unclearedCount int
+		err := UnlockUserLogin(testCase.user, testCase.username)
+		helios.DB.Model(LoginAttempt{}).Where("username = ?", user1.Username).Where("cleared = ?", false).Count(&unclearedCount)
+		assert.Equal(t, testCase.expectedError, err)
+		if testCase.expectedError == nil {
+			assert.Equal(t, 0, unclearedCount)
+		} else {
+			assert.Equal(t, 1, unclearedCount)
+		}
+	}
+}
+
+func TestUnlockIPLogin(t *testing.T) {
+	helios.App.BeforeTest()
+
+	helios.DB.Create(&LoginAttempt{Username: "user1", IPAddress: "10.0.0.5", Result: LoginResultFailed})
+	helios.DB.Create(&LoginAttempt{Username: "user2", IPAddress: "10.0.0.5", Result: LoginResultFailed})
+	helios.DB.Create(&LoginAttempt{Username: "user3", IPAddress: "10.0.0.6", Result: LoginResultFailed})
+	type unlockIPLoginTestCase struct {
+		user                   User
+		ipAddress              string
+		expectedError          helios.Error
+		expectedUnclearedCount int
+	}
+	testCases := []unlockIPLoginTestCase{{
+		user:                   UserFactorySaved(User{Role: UserRoleParticipant}),
+		ipAddress:              "10.0.0.5",
+		expectedError:          errLoginAttemptAccessNotAuthorized,
+		expectedUnclearedCount: 3,
+	}, {
+		user:                   UserFactorySaved(User{Role: UserRoleLocal}),
+		ipAddress:              "10.0.0.5",
+		expectedUnclearedCount: 1,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test UnlockIPLogin testcase: %d", i)
+		var unclearedCount int
+		err := UnlockIPLogin(testCase.user, testCase.ipAddress)
+		helios.DB.Model(LoginAttempt{}).Where("cleared = ?", false).Count(&unclearedCount)
+		assert.Equal(t, testCase.expectedError, err)
+		assert.Equal(t, testCase.expectedUnclearedCount, unclearedCount)
+	}
+}
+
+func TestGetAllLoginAttempt(t *testing.T) {
+	helios.App.BeforeTest()
+
+	helios.DB.Create(&LoginAttempt{Username: "user1", Result: LoginResultFailed})
+	helios.DB.Create(&LoginAttempt{Username: "user1", Result: LoginResultSuccess})
+	type getAllLoginAttemptTestCase struct {
+		user          User
+		expectedError helios.Error
+		expectedCount int
+	}
+	testCases := []getAllLoginAttemptTestCase{{
+		user:          UserFactorySaved(User{Role: UserRoleParticipant}),
+		expectedError: errLoginAttemptAccessNotAuthorized,
+	}, {
+		user:          UserFactorySaved(User{Role: UserRoleLocal}),
+		expectedCount: 2,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test GetAllLoginAttempt testcase: %d", i)
+		loginAttempts, err := GetAllLoginAttempt(testCase.user)
+		assert.Equal(t, testCase.expectedError, err)
+		assert.Equal(t, testCase.expectedCount, len(loginAttempts))
+	}
+}
+
 func TestGetAllUser(t *testing.T) {
 	helios.App.BeforeTest()
 	var userAdmin User = UserFactorySaved(User{Role: UserRoleAdmin})
@@ -85,8 +288,8 @@ func TestGetAllUser(t *testing.T) {
 	}}
 	for i, testCase := range testCases {
 		t.Logf("Test GetAllUser testcase: %d", i)
-		var users []User
-		users = GetAllUser(testCase.user)
+		users, err := GetAllUser(testCase.user)
+		assert.Nil(t, err)
 		assert.Equal(t, testCase.expectedLength, len(users))
 	}
 }
@@ -96,6 +299,7 @@ func TestUpsertUser(t *testing.T) {
 
 	var userParticipant User = UserFactorySaved(User{Role: UserRoleParticipant})
 	var userLocal User = UserFactorySaved(User{Role: UserRoleLocal})
+	var userOrganizer User = UserFactorySaved(User{Role: UserRoleOrganizer})
 
 	type upsertUserTestCase struct {
 		user              User
@@ -108,22 +312,35 @@ func TestUpsertUser(t *testing.T) {
 		user:              userParticipant,
 		newUser:           UserFactory(User{Role: UserRoleParticipant}),
 		password:          "pass1",
+		expectedError:     errUserChangeNotAuthorized,
+		expectedUserCount: 3,
+	}, {
+		user:              userLocal,
+		newUser:           UserFactory(User{Role: UserRoleLocal}),
+		password:          "pass1",
 		expectedError:     errUserRoleTooHigh,
-		expectedUserCount: 2,
+		expectedUserCount: 3,
 	}, {
 		user:              userLocal,
 		newUser:           UserFactory(User{Role: UserRoleParticipant}),
 		password:          "pass2",
-		expectedUserCount: 3,
+		expectedUserCount: 4,
 	}, {
 		user:              userLocal,
-		newUser:           UserFactory(User{ID: userLocal.ID, Name: "abc"}),
+		newUser:           UserFactory(User{ID: userParticipant.ID, Name: "abc", Role: UserRoleParticipant}),
 		password:          "pass3",
-		expectedUserCount: 3,
+		expectedUserCount: 4,
+	}, {
+		user:              userLocal,
+		newUser:           UserFactory(User{ID: userOrganizer.ID, Name: "abc", Role: UserRoleParticipant}),
+		password:          "pass4",
+		expectedError:     errUserRoleTooHigh,
+		expectedUserCount: 4,
 	}}
 	for i, testCase := range testCases {
 		var newUserCount int
 		var newUserSaved User
+		var isCreate bool = testCase.newUser.ID == 0
 		t.Logf("Test UpsertUser testcase: %d", i)
 		testCase.newUser.Password = testCase.password
 		err := UpsertUser(testCase.user, &testCase.newUser)
@@ -133,14 +350,172 @@ func TestUpsertUser(t *testing.T) {
 		if testCase.expectedError == nil {
 			assert.Nil(t, err)
 			assert.Equal(t, testCase.newUser.Name, newUserSaved.Name, "If the newUser has already existed, it should be updated")
-			assert.NotEqual(t, testCase.newUser.Password, testCase.password)
-			assert.True(t, checkPasswordHash(testCase.password, testCase.newUser.Password), "password should be hashed")
+			var auditLog AuditLog
+			helios.DB.Last(&auditLog)
+			assert.Equal(t, testCase.user.ID, auditLog.ActorID)
+			assert.Equal(t, testCase.newUser.ID, auditLog.TargetID)
+			if isCreate {
+				assert.True(t, checkPasswordHash(testCase.password, newUserSaved.Password), "password should be hashed")
+				assert.True(t, newUserSaved.MustChangePassword, "created user should change the password")
+				assert.Equal(t, auditActionUserCreate, auditLog.Action)
+			} else {
+				assert.Equal(t, userParticipant.Password, newUserSaved.Password, "password is not changed on update")
+				assert.Equal(t, auditActionUserUpdate, auditLog.Action)
+				assert.Contains(t, auditLog.After, `"Name":"abc"`)
+				assert.NotContains(t, auditLog.After, "Password", "password is not changed on update")
+			}
 		} else {
 			assert.Equal(t, testCase.expectedError, err)
 		}
 	}
 }
 
+func TestChangePassword(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user User = UserFactorySaved(User{Password: "oldpass1", MustChangePassword: true})
+	var session1 Session = Session{Token: "token1", UserID: user.ID}
+	var session2 Session = Session{Token: "token2", UserID: user.ID}
+	helios.DB.Create(&session1)
+	helios.DB.Create(&session2)
+	type changePasswordTestCase struct {
+		oldPassword   string
+		newPassword   string
+		expectedError string
+	}
+	testCases := []changePasswordTestCase{{
+		oldPassword:   "wrongpass1",
+		newPassword:   "newpass1",
+		expectedError: errWrongPassword.Code,
+	}, {
+		oldPassword:   "oldpass1",
+		newPassword:   "new",
+		expectedError: "form_error",
+	}, {
+		oldPassword:   "oldpass1",
+		newPassword:   "oldpass1",
+		expectedError: errPasswordNotChanged.Code,
+	}, {
+		oldPassword: "oldpass1",
+		newPassword: "newpass1",
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test ChangePassword testcase: %d", i)
+		var userSaved User
+		var sessionCount int
+		err := ChangePassword(session1, testCase.oldPassword, testCase.newPassword)
+		helios.DB.Where("id = ?", user.ID).First(&userSaved)
+		helios.DB.Model(Session{}).Where("user_id = ?", user.ID).Count(&sessionCount)
+		if testCase.expectedError == "" {
+			assert.Nil(t, err)
+			assert.True(t, checkPasswordHash(testCase.newPassword, userSaved.Password))
+			assert.False(t, userSaved.MustChangePassword)
+			assert.Equal(t, 1, sessionCount, "Other sessions should be revoked")
+		} else {
+			assert.NotNil(t, err)
+			assert.Equal(t, testCase.expectedError, err.GetMessage()["code"])
+			assert.Equal(t, user.Password, userSaved.Password)
+			assert.True(t, userSaved.MustChangePassword)
+			assert.Equal(t, 2, sessionCount)
+		}
+	}
+}
+
+func TestIssuePasswordResetToken(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userAdmin User = UserFactorySaved(User{Role: UserRoleAdmin})
+	var userParticipant User = UserFactorySaved(User{Role: UserRoleParticipant})
+	var userLocal User = UserFactorySaved(User{Role: UserRoleLocal})
+	type issuePasswordResetTokenTestCase struct {
+		user          User
+		username      string
+		expectedError helios.Error
+	}
+	testCases := []issuePasswordResetTokenTestCase{{
+		user:          userParticipant,
+		username:      userParticipant.Username,
+		expectedError: errUserChangeNotAuthorized,
+	}, {
+		user:          userLocal,
+		username:      userLocal.Username,
+		expectedError: errUserNotFound,
+	}, {
+		user:     userAdmin,
+		username: userLocal.Username,
+	}, {
+		user:     userAdmin,
+		username: userLocal.Username,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test IssuePasswordResetToken testcase: %d", i)
+		token, resetToken, err := IssuePasswordResetToken(testCase.user, testCase.username)
+		assert.Equal(t, testCase.expectedError, err)
+		if testCase.expectedError == nil {
+			var unusedTokenCount int
+			helios.DB.Model(PasswordResetToken{}).Where("user_id = ?", userLocal.ID).Count(&unusedTokenCount)
+			assert.NotEmpty(t, token)
+			assert.Equal(t, hashToken(token), resetToken.TokenHashed, "Only the hash of token is stored")
+			assert.Equal(t, 1, unusedTokenCount, "Previous tokens should be invalidated")
+		}
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user User = UserFactorySaved(User{Role: UserRoleParticipant, MustChangePassword: true})
+	helios.DB.Create(&Session{Token: "token1", UserID: user.ID})
+	helios.DB.Create(&LoginAttempt{Username: user.Username, Result: LoginResultFailed})
+	helios.DB.Create(&PasswordResetToken{UserID: user.ID, TokenHashed: hashToken("expired"), ExpiresAt: time.Now().Add(-time.Minute)})
+	helios.DB.Create(&PasswordResetToken{UserID: user.ID, TokenHashed: hashToken("valid"), ExpiresAt: time.Now().Add(time.Hour)})
+	type resetPasswordTestCase struct {
+		token         string
+		newPassword   string
+		expectedError string
+	}
+	testCases := []resetPasswordTestCase{{
+		token:         "random",
+		newPassword:   "newpass1",
+		expectedError: errPasswordResetTokenInvalid.Code,
+	}, {
+		token:         "expired",
+		newPassword:   "newpass1",
+		expectedError: errPasswordResetTokenInvalid.Code,
+	}, {
+		token:         "valid",
+		newPassword:   "new",
+		expectedError: "form_error",
+	}, {
+		token:       "valid",
+		newPassword: "newpass1",
+	}, {
+		token:         "valid",
+		newPassword:   "newpass2",
+		expectedError: errPasswordResetTokenInvalid.Code,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test ResetPassword testcase: %d", i)
+		err := ResetPassword(testCase.token, testCase.newPassword)
+		if testCase.expectedError == "" {
+			var userSaved User
+			var sessionCount int
+			var unclearedCount int
+			helios.DB.Where("id = ?", user.ID).First(&userSaved)
+			helios.DB.Model(Session{}).Where("user_id = ?", user.ID).Count(&sessionCount)
+			helios.DB.Model(LoginAttempt{}).Where("username = ?", user.Username).Where("cleared = ?", false).Count(&unclearedCount)
+			assert.Nil(t, err)
+			assert.True(t, checkPasswordHash(testCase.newPassword, userSaved.Password))
+			assert.False(t, userSaved.MustChangePassword)
+			assert.Equal(t, 0, sessionCount, "All sessions should be revoked")
+			assert.Equal(t, 0, unclearedCount, "Failed login attempts should be cleared")
+		} else {
+			assert.NotNil(t, err)
+			assert.Equal(t, testCase.expectedError, err.GetMessage()["code"])
+		}
+	}
+}
+
 func TestHashPassword(t *testing.T) {
 	passwordHashed := hashPassword("charon")
 	assert.NotEmpty(t, passwordHashed, "Hashed Password is empty")
@@ -151,70 +526,594 @@ func TestCheckPasswordHash(t *testing.T) {
 	assert.True(t, check, "Password mismatch")
 }
 
-func TestLockUserSession(t *testing.T) {
-	var user1 User = UserFactorySaved(User{Role: UserRoleParticipant, SessionLocked: false})
-	type lockUserSessionTestCase struct {
+func TestLoginSessionPolicy(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userLocal User = UserFactorySaved(User{Username: "local", Password: "def", Role: UserRoleLocal})
+	var userOrganizer User = UserFactorySaved(User{Username: "organizer", Password: "def", Role: UserRoleOrganizer})
+	var maxLocalSessions int = SessionPolicies[UserRoleLocal].MaxSessions
+	var firstLocalSession *Session
+	for i := 0; i < maxLocalSessions+2; i++ {
+		session, err := Login("local", "def", "1.2.3.4")
+		assert.Nil(t, err)
+		if i == 0 {
+			firstLocalSession = session
+		}
+	}
+	var localSessionCount int
+	var firstLocalSessionSaved Session
+	helios.DB.Model(Session{}).Where("user_id = ?", userLocal.ID).Count(&localSessionCount)
+	helios.DB.Where("id = ?", firstLocalSession.ID).First(&firstLocalSessionSaved)
+	assert.Equal(t, maxLocalSessions, localSessionCount, "Oldest session of local should be revoked")
+	assert.Equal(t, uint(0), firstLocalSessionSaved.ID, "Oldest session of local should be revoked")
+
+	for i := 0; i < 5; i++ {
+		_, err := Login("organizer", "def", "1.2.3.4")
+		assert.Nil(t, err)
+	}
+	var organizerSessionCount int
+	helios.DB.Model(Session{}).Where("user_id = ?", userOrganizer.ID).Count(&organizerSessionCount)
+	assert.Equal(t, 5, organizerSessionCount, "Organizer has unlimited sessions")
+}
+
+func TestLogout(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user User = UserFactorySaved(User{})
+	var session1 Session = Session{Token: "token1", UserID: user.ID}
+	var session2 Session = Session{Token: "token2", UserID: user.ID}
+	helios.DB.Create(&session1)
+	helios.DB.Create(&session2)
+
+	assert.Nil(t, Logout(session1))
+
+	var sessionCount, auditLogCount int
+	helios.DB.Model(Session{}).Where("user_id = ?", user.ID).Count(&sessionCount)
+	assert.Equal(t, 1, sessionCount, "Only the given session should be removed")
+	helios.DB.Model(AuditLog{}).Where("action = ?", auditActionSessionLogout).Where("actor_id = ?", user.ID).Where("target_id = ?", session1.ID).Count(&auditLogCount)
+	assert.Equal(t, 1, auditLogCount)
+}
+
+func TestGetAllSessionOfUser(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user1 User = UserFactorySaved(User{})
+	var user2 User = UserFactorySaved(User{})
+	helios.DB.Create(&Session{Token: "token1", UserID: user1.ID})
+	helios.DB.Create(&Session{Token: "token2", UserID: user1.ID, LastSeenAt: time.Now().Add(-SessionIdleTimeout - time.Minute), CreatedAt: time.Now().Add(-SessionIdleTimeout - time.Minute)})
+	helios.DB.Create(&Session{Token: "token3", UserID: user2.ID})
+
+	sessions1, err := GetAllSessionOfUser(user1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(sessions1), "Expired session should not be returned")
+	sessions2, err := GetAllSessionOfUser(user2)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(sessions2))
+}
+
+func TestRevokeSession(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user1 User = UserFactorySaved(User{})
+	var user2 User = UserFactorySaved(User{})
+	var session1 Session = Session{Token: "token1", UserID: user1.ID}
+	var session2 Session = Session{Token: "token2", UserID: user2.ID}
+	helios.DB.Create(&session1)
+	helios.DB.Create(&session2)
+	type revokeSessionTestCase struct {
 		user          User
-		username      string
+		sessionID     uint
 		expectedError helios.Error
 	}
-	testCases := []lockUserSessionTestCase{{
-		user:          UserFactorySaved(User{Role: UserRoleParticipant}),
-		username:      user1.Username,
-		expectedError: errUserNotFound,
+	testCases := []revokeSessionTestCase{{
+		user:          user1,
+		sessionID:     session2.ID,
+		expectedError: errSessionNotFound,
 	}, {
-		user:     UserFactorySaved(User{Role: UserRoleLocal}),
-		username: user1.Username,
+		user:      user1,
+		sessionID: session1.ID,
 	}, {
-		user:     UserFactorySaved(User{Role: UserRoleLocal}),
-		username: user1.Username,
+		user:          user1,
+		sessionID:     session1.ID,
+		expectedError: errSessionNotFound,
 	}}
 	for i, testCase := range testCases {
-		t.Logf("Test LockUserSession testcase: %d", i)
-		var err helios.Error
-		var userSaved User
-		err = LockUserSession(testCase.user, testCase.username)
-		helios.DB.Where("username = ?", testCase.username).First(&userSaved)
+		t.Logf("Test RevokeSession testcase: %d", i)
+		var err helios.Error = RevokeSession(testCase.user, testCase.sessionID)
+		assert.Equal(t, testCase.expectedError, err)
 		if testCase.expectedError == nil {
+			var sessionSaved Session
+			helios.DB.Where("id = ?", testCase.sessionID).First(&sessionSaved)
+			assert.Equal(t, uint(0), sessionSaved.ID, "Session should be deleted")
+		}
+	}
+}
+
+func TestLoginTwoFactor(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user User = UserFactorySaved(User{Username: "user1", Password: "def", Role: UserRoleAdmin, TOTPSecret: testTOTPSecret, TOTPEnabled: true})
+	session, err := Login("user1", "def", "1.2.3.4")
+	assert.Nil(t, err)
+	assert.True(t, session.TwoFactorPending, "Session should wait for the second factor")
+
+	var pendingCount int
+	helios.DB.Model(LoginAttempt{}).Where("username = ?", user.Username).Where("result = ?", LoginResultTwoFactorPending).Count(&pendingCount)
+	assert.Equal(t, 1, pendingCount)
+}
+
+func TestCheckSecondFactor(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var now time.Time = time.Now()
+	var user User = UserFactorySaved(User{Role: UserRoleAdmin, TOTPSecret: testTOTPSecret, TOTPEnabled: true})
+	helios.DB.Create(&RecoveryCode{UserID: user.ID, CodeHashed: hashRecoveryCode("abcde-12345")})
+	validCode, _ := totpCode(testTOTPSecret, totpCounter(now))
+	type checkSecondFactorTestCase struct {
+		code             string
+		expectedAccepted bool
+	}
+	testCases := []checkSecondFactorTestCase{
+		{code: validCode, expectedAccepted: true},
+		// the concurrent request that has read the user before the code is consumed
+		{code: validCode, expectedAccepted: false},
+		{code: "abcde-12345", expectedAccepted: true},
+		{code: "abcde-12345", expectedAccepted: false},
+	}
+	for i, testCase := range testCases {
+		t.Logf("Test CheckSecondFactor testcase: %d", i)
+		var staleUser User = user
+		accepted, err := checkSecondFactor(&staleUser, testCase.code, now)
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.expectedAccepted, accepted)
+	}
+	var userSaved User
+	helios.DB.Where("id = ?", user.ID).First(&userSaved)
+	assert.Equal(t, totpCounter(now), userSaved.TOTPLastCounter)
+}
+
+func TestVerifyLogin(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var now time.Time = time.Now()
+	var user User = UserFactorySaved(User{Role: UserRoleAdmin, TOTPSecret: testTOTPSecret, TOTPEnabled: true})
+	var pendingSession Session = Session{Token: "token1", UserID: user.ID, TwoFactorPending: true}
+	var completeSession Session = Session{Token: "token2", UserID: user.ID}
+	helios.DB.Create(&pendingSession)
+	helios.DB.Create(&completeSession)
+	helios.DB.Create(&RecoveryCode{UserID: user.ID, CodeHashed: hashRecoveryCode("abcde-12345")})
+	validCode, _ := totpCode(testTOTPSecret, totpCounter(now))
+	type verifyLoginTestCase struct {
+		session         Session
+		code            string
+		expectedError   string
+		expectedPending bool
+	}
+	testCases := []verifyLoginTestCase{{
+		session:       completeSession,
+		code:          validCode,
+		expectedError: errTwoFactorNotPending.Code,
+	}, {
+		session:         pendingSession,
+		code:            "000000",
+		expectedError:   errWrongTwoFactorCode.Code,
+		expectedPending: true,
+	}, {
+		session: pendingSession,
+		code:    validCode,
+	}, {
+		session:         pendingSession,
+		code:            validCode,
+		expectedError:   errWrongTwoFactorCode.Code,
+		expectedPending: true,
+	}, {
+		session: pendingSession,
+		code:    "ABCDE12345",
+	}, {
+		session:         pendingSession,
+		code:            "abcde-12345",
+		expectedError:   errWrongTwoFactorCode.Code,
+		expectedPending: true,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test VerifyLogin testcase: %d", i)
+		var sessionSaved Session
+		var auditLogCountBefore, auditLogCountAfter int
+		helios.DB.Model(&pendingSession).UpdateColumn("two_factor_pending", true)
+		helios.DB.Model(LoginAttempt{}).Update("cleared", true)
+		helios.DB.Model(AuditLog{}).Where("action = ?", auditActionSessionVerify).Count(&auditLogCountBefore)
+		err := VerifyLogin(testCase.session, testCase.code, "1.2.3.4")
+		helios.DB.Where("id = ?", testCase.session.ID).First(&sessionSaved)
+		helios.DB.Model(AuditLog{}).Where("action = ?", auditActionSessionVerify).Count(&auditLogCountAfter)
+		if testCase.expectedError == "" {
 			assert.Nil(t, err)
-			assert.True(t, userSaved.SessionLocked)
+			assert.Equal(t, auditLogCountBefore+1, auditLogCountAfter)
 		} else {
-			assert.False(t, userSaved.SessionLocked)
-			assert.Equal(t, testCase.expectedError, err)
+			assert.NotNil(t, err)
+			assert.Equal(t, auditLogCountBefore, auditLogCountAfter)
+			assert.Equal(t, testCase.expectedError, err.GetMessage()["code"])
+		}
+		assert.Equal(t, testCase.expectedPending, sessionSaved.TwoFactorPending)
+	}
+}
+
+func TestEnrollTwoFactor(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user User = UserFactorySaved(User{Role: UserRoleAdmin})
+	secret, uri, err := EnrollTwoFactor(user)
+	var userSaved User
+	helios.DB.Where("id = ?", user.ID).First(&userSaved)
+	assert.Nil(t, err)
+	assert.Equal(t, secret, userSaved.TOTPSecret)
+	assert.False(t, userSaved.TOTPEnabled, "Two-factor should be enabled after confirmed")
+	assert.Contains(t, uri, secret)
+	var auditLog AuditLog
+	helios.DB.Where("action = ?", auditActionTwoFactorEnroll).First(&auditLog)
+	assert.Equal(t, user.ID, auditLog.TargetID)
+	assert.Equal(t, "", auditLog.After, "Secret should not be recorded")
+
+	_, _, err = EnrollTwoFactor(UserFactorySaved(User{TOTPSecret: testTOTPSecret, TOTPEnabled: true}))
+	assert.Equal(t, errTwoFactorAlreadyEnabled, err)
+}
+
+func TestConfirmTwoFactor(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user User = UserFactorySaved(User{TOTPSecret: testTOTPSecret})
+	validCode, _ := totpCode(testTOTPSecret, totpCounter(time.Now()))
+	type confirmTwoFactorTestCase struct {
+		user          User
+		code          string
+		expectedError helios.Error
+	}
+	testCases := []confirmTwoFactorTestCase{{
+		user:          UserFactorySaved(User{}),
+		code:          validCode,
+		expectedError: errTwoFactorNotEnrolled,
+	}, {
+		user:          UserFactorySaved(User{TOTPSecret: testTOTPSecret, TOTPEnabled: true}),
+		code:          validCode,
+		expectedError: errTwoFactorAlreadyEnabled,
+	}, {
+		user:          user,
+		code:          "000000",
+		expectedError: errWrongTwoFactorCode,
+	}, {
+		user: user,
+		code: validCode,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test ConfirmTwoFactor testcase: %d", i)
+		var userSaved User
+		var savedCodeCount int
+		codes, err := ConfirmTwoFactor(testCase.user, testCase.code)
+		helios.DB.Where("id = ?", testCase.user.ID).First(&userSaved)
+		helios.DB.Model(RecoveryCode{}).Where("user_id = ?", testCase.user.ID).Count(&savedCodeCount)
+		assert.Equal(t, testCase.expectedError, err)
+		if testCase.expectedError == nil {
+			assert.True(t, userSaved.TOTPEnabled)
+			assert.Equal(t, recoveryCodeCount, len(codes))
+			assert.Equal(t, recoveryCodeCount, savedCodeCount)
+		} else {
+			assert.Equal(t, testCase.user.TOTPEnabled, userSaved.TOTPEnabled)
+		}
+	}
+}
+
+func TestDisableTwoFactor(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userLocal User = UserFactorySaved(User{Role: UserRoleLocal, TOTPSecret: testTOTPSecret, TOTPEnabled: true})
+	var userAdmin User = UserFactorySaved(User{Role: UserRoleAdmin, TOTPSecret: testTOTPSecret, TOTPEnabled: true})
+	helios.DB.Create(&RecoveryCode{UserID: userLocal.ID, CodeHashed: hashRecoveryCode("abcde-12345")})
+	validCode, _ := totpCode(testTOTPSecret, totpCounter(time.Now()))
+	type disableTwoFactorTestCase struct {
+		user          User
+		code          string
+		expectedError helios.Error
+	}
+	testCases := []disableTwoFactorTestCase{{
+		user:          UserFactorySaved(User{Role: UserRoleLocal}),
+		code:          validCode,
+		expectedError: errTwoFactorNotEnabled,
+	}, {
+		user:          userAdmin,
+		code:          validCode,
+		expectedError: errTwoFactorRequiredByPolicy,
+	}, {
+		user:          userLocal,
+		code:          "000000",
+		expectedError: errWrongTwoFactorCode,
+	}, {
+		user: userLocal,
+		code: validCode,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test DisableTwoFactor testcase: %d", i)
+		var userSaved User
+		var savedCodeCount int
+		err := DisableTwoFactor(testCase.user, testCase.code, "1.2.3.4")
+		helios.DB.Where("id = ?", testCase.user.ID).First(&userSaved)
+		helios.DB.Model(RecoveryCode{}).Where("user_id = ?", testCase.user.ID).Count(&savedCodeCount)
+		assert.Equal(t, testCase.expectedError, err)
+		if testCase.expectedError == nil {
+			assert.False(t, userSaved.TOTPEnabled)
+			assert.Empty(t, userSaved.TOTPSecret)
+			assert.Equal(t, 0, savedCodeCount)
+		} else {
+			assert.Equal(t, testCase.user.TOTPEnabled, userSaved.TOTPEnabled)
 		}
 	}
 }
 
-func TestUnlockUserSession(t *testing.T) {
-	var user1 User = UserFactorySaved(User{Role: UserRoleParticipant, SessionLocked: true})
-	type lockUserSessionTestCase struct {
+func TestRegenerateRecoveryCodes(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user User = UserFactorySaved(User{Role: UserRoleAdmin, TOTPSecret: testTOTPSecret, TOTPEnabled: true})
+	helios.DB.Create(&RecoveryCode{UserID: user.ID, CodeHashed: hashRecoveryCode("abcde-12345")})
+
+	_, err := RegenerateRecoveryCodes(UserFactorySaved(User{}), "000000", "1.2.3.4")
+	assert.Equal(t, errTwoFactorNotEnabled, err)
+	_, err = RegenerateRecoveryCodes(user, "000000", "1.2.3.4")
+	assert.Equal(t, errWrongTwoFactorCode, err)
+
+	codes, err := RegenerateRecoveryCodes(user, "abcde-12345", "1.2.3.4")
+	var recoveryCodes []RecoveryCode
+	helios.DB.Where("user_id = ?", user.ID).Find(&recoveryCodes)
+	assert.Nil(t, err)
+	assert.Equal(t, recoveryCodeCount, len(codes))
+	assert.Equal(t, recoveryCodeCount, len(recoveryCodes), "Old recovery codes should be removed")
+	assert.Equal(t, hashRecoveryCode(codes[0]), recoveryCodes[0].CodeHashed)
+}
+
+func TestResetTwoFactor(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userAdmin User = UserFactorySaved(User{Role: UserRoleAdmin})
+	var userOrganizer User = UserFactorySaved(User{Role: UserRoleOrganizer, TOTPSecret: testTOTPSecret, TOTPEnabled: true})
+	helios.DB.Create(&Session{Token: "token1", UserID: userOrganizer.ID})
+	type resetTwoFactorTestCase struct {
 		user          User
 		username      string
 		expectedError helios.Error
 	}
-	testCases := []lockUserSessionTestCase{{
+	testCases := []resetTwoFactorTestCase{{
 		user:          UserFactorySaved(User{Role: UserRoleParticipant}),
-		username:      user1.Username,
-		expectedError: errUserNotFound,
+		username:      userOrganizer.Username,
+		expectedError: errUserChangeNotAuthorized,
 	}, {
-		user:     UserFactorySaved(User{Role: UserRoleLocal}),
-		username: user1.Username,
+		user:          userOrganizer,
+		username:      userAdmin.Username,
+		expectedError: errUserNotFound,
 	}, {
-		user:     UserFactorySaved(User{Role: UserRoleLocal}),
-		username: user1.Username,
+		user:     userAdmin,
+		username: userOrganizer.Username,
 	}}
 	for i, testCase := range testCases {
-		t.Logf("Test UnlockUserSession testcase: %d", i)
-		var err helios.Error
+		t.Logf("Test ResetTwoFactor testcase: %d", i)
 		var userSaved User
-		err = UnlockUserSession(testCase.user, testCase.username)
-		helios.DB.Where("username = ?", testCase.username).First(&userSaved)
+		var sessionCount int
+		err := ResetTwoFactor(testCase.user, testCase.username)
+		helios.DB.Where("id = ?", userOrganizer.ID).First(&userSaved)
+		helios.DB.Model(Session{}).Where("user_id = ?", userOrganizer.ID).Count(&sessionCount)
+		assert.Equal(t, testCase.expectedError, err)
+		assert.Equal(t, testCase.expectedError != nil, userSaved.TOTPEnabled)
+		if testCase.expectedError == nil {
+			assert.Equal(t, 0, sessionCount, "Sessions of the user should be revoked")
+		} else {
+			assert.Equal(t, 1, sessionCount)
+		}
+	}
+}
+
+func TestIssueAPIToken(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userLocal User = UserFactorySaved(User{Role: UserRoleLocal})
+	type issueAPITokenTestCase struct {
+		user          User
+		expectedError helios.Error
+	}
+	testCases := []issueAPITokenTestCase{{
+		user:          UserFactorySaved(User{Role: UserRoleAdmin}),
+		expectedError: errAPITokenIssueNotAuthorized,
+	}, {
+		user:          UserFactorySaved(User{Role: UserRoleParticipant}),
+		expectedError: errAPITokenIssueNotAuthorized,
+	}, {
+		user: userLocal,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test IssueAPIToken testcase: %d", i)
+		var apiToken APIToken = APIToken{Name: "Local server", Scopes: []APITokenScope{{EventID: 1}, {EventID: 2}}}
+		token, err := IssueAPIToken(testCase.user, &apiToken)
+		assert.Equal(t, testCase.expectedError, err)
+		if testCase.expectedError == nil {
+			var apiTokenSaved APIToken
+			helios.DB.Where("token_hashed = ?", hashToken(token)).Preload("Scopes").First(&apiTokenSaved)
+			assert.Equal(t, testCase.user.ID, apiTokenSaved.UserID)
+			assert.Equal(t, token[:apiTokenPrefixLength], apiTokenSaved.Prefix)
+			assert.Equal(t, 2, len(apiTokenSaved.Scopes))
+			assert.NotNil(t, apiTokenSaved.ExpiresAt)
+		} else {
+			assert.Empty(t, token)
+		}
+	}
+}
+
+func TestGetAllAPITokenOfUser(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user1 User = UserFactorySaved(User{Role: UserRoleLocal})
+	var user2 User = UserFactorySaved(User{Role: UserRoleLocal})
+	helios.DB.Create(&APIToken{UserID: user1.ID, TokenHashed: hashToken("token1"), Scopes: []APITokenScope{{EventID: 1}}})
+	helios.DB.Create(&APIToken{UserID: user1.ID, TokenHashed: hashToken("token2")})
+	helios.DB.Create(&APIToken{UserID: user2.ID, TokenHashed: hashToken("token3")})
+
+	apiTokens, err := GetAllAPITokenOfUser(user1)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(apiTokens))
+	assert.Equal(t, 1, len(apiTokens[1].Scopes), "Scopes should be loaded")
+}
+
+func TestRevokeAPIToken(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userLocal User = UserFactorySaved(User{Role: UserRoleLocal})
+	var apiToken APIToken = APIToken{UserID: userLocal.ID, TokenHashed: hashToken("token1")}
+	helios.DB.Create(&apiToken)
+	type revokeAPITokenTestCase struct {
+		user          User
+		apiTokenID    uint
+		expectedError helios.Error
+	}
+	testCases := []revokeAPITokenTestCase{{
+		user:          UserFactorySaved(User{Role: UserRoleLocal}),
+		apiTokenID:    apiToken.ID,
+		expectedError: errAPITokenNotFound,
+	}, {
+		user:          userLocal,
+		apiTokenID:    apiToken.ID + 1,
+		expectedError: errAPITokenNotFound,
+	}, {
+		user:       UserFactorySaved(User{Role: UserRoleAdmin}),
+		apiTokenID: apiToken.ID,
+	}, {
+		user:       userLocal,
+		apiTokenID: apiToken.ID,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test RevokeAPIToken testcase: %d", i)
+		var apiTokenSaved APIToken
+		revokedAPIToken, err := RevokeAPIToken(testCase.user, testCase.apiTokenID)
+		helios.DB.Where("id = ?", apiToken.ID).First(&apiTokenSaved)
+		assert.Equal(t, testCase.expectedError, err)
+		if testCase.expectedError == nil {
+			assert.NotNil(t, revokedAPIToken.RevokedAt)
+			assert.NotNil(t, apiTokenSaved.RevokedAt)
+		} else {
+			assert.Nil(t, revokedAPIToken)
+		}
+	}
+}
+
+func TestGetAllAPITokenUsage(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userLocal User = UserFactorySaved(User{Role: UserRoleLocal})
+	var apiToken APIToken = APIToken{UserID: userLocal.ID, TokenHashed: hashToken("token1")}
+	helios.DB.Create(&apiToken)
+	helios.DB.Create(&APITokenUsage{APITokenID: apiToken.ID, EventID: 1, Result: APITokenUsageAccepted})
+	helios.DB.Create(&APITokenUsage{APITokenID: apiToken.ID, EventID: 2, Result: APITokenUsageOutOfScope})
+	helios.DB.Create(&APITokenUsage{APITokenID: apiToken.ID + 1, EventID: 1, Result: APITokenUsageAccepted})
+
+	usages, err := GetAllAPITokenUsage(userLocal, apiToken.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(usages))
+	assert.Equal(t, uint(2), usages[0].EventID, "Newest usage should be the first")
+
+	_, err = GetAllAPITokenUsage(UserFactorySaved(User{Role: UserRoleLocal}), apiToken.ID)
+	assert.Equal(t, errAPITokenNotFound, err)
+}
+
+func TestGetAllAuditLog(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userAdmin User = UserFactorySaved(User{Role: UserRoleAdmin})
+	var userOrganizer User = UserFactorySaved(User{Role: UserRoleOrganizer})
+	RecordAuditLog(helios.DB, userAdmin, auditActionUserCreate, auditTargetUser, 1, nil, nil)
+	RecordAuditLog(helios.DB, userOrganizer, auditActionUserCreate, auditTargetUser, 2, nil, nil)
+	RecordAuditLog(helios.DB, userOrganizer, auditActionUserUpdate, auditTargetUser, 2, nil, nil)
+
+	type getAllAuditLogTestCase struct {
+		user            User
+		filter          AuditLogFilter
+		expectedError   helios.Error
+		expectedTargets []uint
+	}
+	testCases := []getAllAuditLogTestCase{{
+		user:          userOrganizer,
+		expectedError: errAuditLogAccessNotAuthorized,
+	}, {
+		user:            userAdmin,
+		expectedTargets: []uint{2, 2, 1},
+	}, {
+		user:            userAdmin,
+		filter:          AuditLogFilter{ActorUsername: userOrganizer.Username, Action: auditActionUserCreate},
+		expectedTargets: []uint{2},
+	}, {
+		user:            userAdmin,
+		filter:          AuditLogFilter{TargetType: auditTargetUser, TargetID: 1},
+		expectedTargets: []uint{1},
+	}, {
+		user:            userAdmin,
+		filter:          AuditLogFilter{From: time.Now().Add(time.Hour)},
+		expectedTargets: []uint{},
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test GetAllAuditLog testcase: %d", i)
+		auditLogs, err := GetAllAuditLog(testCase.user, testCase.filter)
 		if testCase.expectedError == nil {
 			assert.Nil(t, err)
-			assert.False(t, userSaved.SessionLocked)
+			var targets []uint = make([]uint, 0)
+			for _, auditLog := range auditLogs {
+				targets = append(targets, auditLog.TargetID)
+			}
+			assert.Equal(t, testCase.expectedTargets, targets)
 		} else {
-			assert.True(t, userSaved.SessionLocked)
 			assert.Equal(t, testCase.expectedError, err)
 		}
 	}
+
+	var firstPage, secondPage []AuditLog
+	firstPage, _ = GetAllAuditLog(userAdmin, AuditLogFilter{})
+	secondPage, _ = GetAllAuditLog(userAdmin, AuditLogFilter{BeforeID: firstPage[1].ID})
+	assert.Equal(t, 1, len(secondPage), "Only the entries before the given ID should be returned")
+	assert.Equal(t, firstPage[2].ID, secondPage[0].ID)
+}
+
+func TestExportAuditLog(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userAdmin User = UserFactorySaved(User{Role: UserRoleAdmin})
+	var userOrganizer User = UserFactorySaved(User{Role: UserRoleOrganizer})
+	RecordAuditLog(helios.DB, userAdmin, auditActionUserCreate, auditTargetUser, 1, nil, nil)
+	RecordAuditLog(helios.DB, userAdmin, auditActionUserCreate, auditTargetUser, 2, nil, nil)
+
+	auditLogs, err := ExportAuditLog(userAdmin, AuditLogFilter{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(auditLogs))
+	assert.Equal(t, uint(1), auditLogs[0].TargetID, "Exported audit log should be ordered from the oldest")
+
+	_, err = ExportAuditLog(userOrganizer, AuditLogFilter{})
+	assert.Equal(t, errAuditLogAccessNotAuthorized, err)
+}
+
+func TestVerifyAuditLog(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userAdmin User = UserFactorySaved(User{Role: UserRoleAdmin})
+	var userOrganizer User = UserFactorySaved(User{Role: UserRoleOrganizer})
+	for i := 1; i <= 3; i++ {
+		RecordAuditLog(helios.DB, userAdmin, auditActionUserCreate, auditTargetUser, uint(i), nil, User{ID: uint(i)})
+	}
+	var auditLogs []AuditLog
+	helios.DB.Order("id asc").Find(&auditLogs)
+
+	verification, err := VerifyAuditLog(userAdmin)
+	assert.Nil(t, err)
+	assert.Equal(t, AuditLogVerification{Checked: 3, LastHash: auditLogs[2].Hash}, *verification)
+
+	_, err = VerifyAuditLog(userOrganizer)
+	assert.Equal(t, errAuditLogAccessNotAuthorized, err)
+
+	helios.DB.Model(&auditLogs[1]).UpdateColumn("target_id", 4)
+	verification, _ = VerifyAuditLog(userAdmin)
+	assert.Equal(t, AuditLogVerification{Checked: 1, BrokenID: auditLogs[1].ID, LastHash: auditLogs[0].Hash}, *verification, "Changed entry should break the chain")
+
+	helios.DB.Model(&auditLogs[1]).UpdateColumn("target_id", 2)
+	helios.DB.Delete(&auditLogs[0])
+	verification, _ = VerifyAuditLog(userAdmin)
+	assert.Equal(t, AuditLogVerification{Checked: 0, BrokenID: auditLogs[1].ID}, *verification, "Removed entry should break the chain")
 }
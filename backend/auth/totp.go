@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// generateTOTPSecret generates base32 encoded secret of totpSecretLength
+// random bytes, to be added to the authenticator app
+func generateTOTPSecret() (string, error) {
+	secretBytes := make([]byte, totpSecretLength)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(secretBytes), nil
+}
+
+// totpCounter returns the time step of the time according to RFC 6238
+func totpCounter(now time.Time) int64 {
+	return now.Unix() / int64(totpPeriod/time.Second)
+}
+
+// totpCode returns the HOTP code of the secret on the counter
+// according to RFC 4226, using SHA-1 as most authenticator apps do
+func totpCode(secret string, counter int64) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+	var message [8]byte
+	binary.BigEndian.PutUint64(message[:], uint64(counter))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message[:])
+	var sum []byte = mac.Sum(nil)
+	var offset byte = sum[len(sum)-1] & 0x0f
+	var truncated uint32 = binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	var modulo uint32 = 1
+	for i := 0; i < totpDigits; i++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", totpDigits, truncated%modulo), nil
+}
+
+// verifyTOTP checks the code against the time steps around now, allowing
+// totpSkew steps of clock drift. Code of the step at or before lastCounter
+// is rejected, so a code can't be used twice. It returns the step of the
+// matching code.
+func verifyTOTP(secret string, code string, now time.Time, lastCounter int64) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != totpDigits {
+		return 0, false
+	}
+	var current int64 = totpCounter(now)
+	for counter := current - totpSkew; counter <= current+totpSkew; counter++ {
+		if counter <= lastCounter {
+			continue
+		}
+		expected, err := totpCode(secret, counter)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return counter, true
+		}
+	}
+	return 0, false
+}
+
+// totpProvisioningURI returns the otpauth URI of the secret, usually shown
+// as QR code to be scanned by the authenticator app
+func totpProvisioningURI(secret string, username string) string {
+	var query url.Values = url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", TOTPIssuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprintf("%d", totpDigits))
+	query.Set("period", fmt.Sprintf("%d", int(totpPeriod/time.Second)))
+	var label string = url.PathEscape(TOTPIssuer + ":" + username)
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, query.Encode())
+}
+
+// generateRecoveryCodes generates recoveryCodeCount one-time codes, formatted
+// as two groups of lowercase letters and digits, e.g. "abcde-12345"
+func generateRecoveryCodes() ([]string, error) {
+	var codes []string = make([]string, 0)
+	for i := 0; i < recoveryCodeCount; i++ {
+		codeBytes := make([]byte, recoveryCodeLength*5/8)
+		if _, err := rand.Read(codeBytes); err != nil {
+			return nil, err
+		}
+		var code string = strings.ToLower(totpEncoding.EncodeToString(codeBytes))
+		codes = append(codes, code[:len(code)/2]+"-"+code[len(code)/2:])
+	}
+	return codes, nil
+}
+
+// hashRecoveryCode hashes the recovery code to be stored, ignoring
+// the separator and letter case typed by the user
+func hashRecoveryCode(code string) string {
+	var normalized string = strings.ToLower(strings.Replace(strings.TrimSpace(code), "-", "", -1))
+	return hashToken(normalized)
+}
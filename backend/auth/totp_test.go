@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testTOTPSecret is the secret of RFC 6238 test vectors, "12345678901234567890"
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	type totpCodeTestCase struct {
+		unixTime     int64
+		expectedCode string
+	}
+	testCases := []totpCodeTestCase{{
+		unixTime:     59,
+		expectedCode: "287082",
+	}, {
+		unixTime:     1111111109,
+		expectedCode: "081804",
+	}, {
+		unixTime:     1234567890,
+		expectedCode: "005924",
+	}, {
+		unixTime:     20000000000,
+		expectedCode: "353130",
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test TOTPCode testcase: %d", i)
+		code, err := totpCode(testTOTPSecret, totpCounter(time.Unix(testCase.unixTime, 0)))
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.expectedCode, code)
+	}
+
+	_, err := totpCode("not base32!", 1)
+	assert.NotNil(t, err)
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	var now time.Time = time.Unix(1111111109, 0)
+	var counter int64 = totpCounter(now)
+	type verifyTOTPTestCase struct {
+		code            string
+		lastCounter     int64
+		expectedCounter int64
+		expectedValid   bool
+	}
+	testCases := []verifyTOTPTestCase{{
+		code:            "081804",
+		expectedCounter: counter,
+		expectedValid:   true,
+	}, {
+		code:            " 081804 ",
+		expectedCounter: counter,
+		expectedValid:   true,
+	}, {
+		code:          "081804",
+		lastCounter:   counter,
+		expectedValid: false,
+	}, {
+		code:          "000000",
+		expectedValid: false,
+	}, {
+		code:          "0818",
+		expectedValid: false,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test VerifyTOTP testcase: %d", i)
+		matchedCounter, valid := verifyTOTP(testTOTPSecret, testCase.code, now, testCase.lastCounter)
+		assert.Equal(t, testCase.expectedValid, valid)
+		assert.Equal(t, testCase.expectedCounter, matchedCounter)
+	}
+
+	previousCode, _ := totpCode(testTOTPSecret, counter-1)
+	matchedCounter, valid := verifyTOTP(testTOTPSecret, previousCode, now, 0)
+	assert.True(t, valid, "Code of previous time step should be accepted for clock drift")
+	assert.Equal(t, counter-1, matchedCounter)
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri, err := url.Parse(totpProvisioningURI(testTOTPSecret, "user 1"))
+	assert.Nil(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/"+TOTPIssuer+":user 1", uri.Path)
+	assert.Equal(t, testTOTPSecret, uri.Query().Get("secret"))
+	assert.Equal(t, TOTPIssuer, uri.Query().Get("issuer"))
+	assert.Equal(t, "6", uri.Query().Get("digits"))
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, err := generateRecoveryCodes()
+	assert.Nil(t, err)
+	assert.Equal(t, recoveryCodeCount, len(codes))
+	for _, code := range codes {
+		assert.Equal(t, recoveryCodeLength+1, len(code))
+		assert.Equal(t, hashRecoveryCode(code), hashRecoveryCode(strings.ToUpper(strings.Replace(code, "-", "", -1))))
+	}
+}
@@ -17,14 +17,42 @@ func LoginView(req helios.Request) {
 	} else {
 		req.SetSessionData(UserTokenSessionKey, userSession.Token)
 		req.SaveSession()
-		req.SendJSON(SerializeUser(*userSession.User), http.StatusOK)
+		var userData UserData = SerializeUser(*userSession.User)
+		userData.TwoFactorPending = userSession.TwoFactorPending
+		req.SendJSON(userData, http.StatusOK)
 	}
 }
 
+// LoginVerifyView completes the login with TOTP code or recovery code
+func LoginVerifyView(req helios.Request) {
+	session, ok := req.GetContextData(SessionContextKey).(Session)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var codeRequest TwoFactorCodeRequest
+	var err helios.Error
+	err = req.DeserializeRequestData(&codeRequest)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	err = VerifyLogin(session, codeRequest.Code, req.ClientIP())
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeUser(*session.User), http.StatusOK)
+}
+
 // LogoutView clear user session data
 func LogoutView(req helios.Request) {
-	var user User = req.GetContextData(UserTokenSessionKey).(User)
-	Logout(user)
+	var session Session = req.GetContextData(SessionContextKey).(Session)
+	if err := Logout(session); err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
 	req.SetSessionData(UserTokenSessionKey, "")
 	req.SendJSON(nil, http.StatusOK)
 }
@@ -32,7 +60,11 @@ func LogoutView(req helios.Request) {
 // UserListView returns all the users
 func UserListView(req helios.Request) {
 	var user User = req.GetContextData(UserTokenSessionKey).(User)
-	var users []User = GetAllUser(user)
+	users, err := GetAllUser(user)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
 
 	serializedUsers := make([]UserData, 0)
 	for _, user := range users {
@@ -63,12 +95,60 @@ func UserCreateView(req helios.Request) {
 		return
 	}
 	newUser.ID = 0
-	UpsertUser(user, &newUser)
+	err = UpsertUser(user, &newUser)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
 	req.SendJSON(SerializeUser(newUser), http.StatusCreated)
 }
 
-// UserSessionLockView locks the session of user
-func UserSessionLockView(req helios.Request) {
+// SessionListView returns the active sessions of the user
+func SessionListView(req helios.Request) {
+	user, ok := req.GetContextData(UserContextKey).(User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+	currentSession, _ := req.GetContextData(SessionContextKey).(Session)
+
+	sessions, err := GetAllSessionOfUser(user)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	serializedSessions := make([]SessionData, 0)
+	for _, session := range sessions {
+		serializedSessions = append(serializedSessions, SerializeSession(session, session.ID == currentSession.ID))
+	}
+	req.SendJSON(serializedSessions, http.StatusOK)
+}
+
+// SessionRevokeView revokes a session of the user, logging out the device
+// that uses the session
+func SessionRevokeView(req helios.Request) {
+	user, ok := req.GetContextData(UserContextKey).(User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	sessionID, errParseSessionID := req.GetURLParamUint("sessionID")
+	if errParseSessionID != nil {
+		req.SendJSON(errSessionNotFound.GetMessage(), errSessionNotFound.GetStatusCode())
+		return
+	}
+
+	var err helios.Error = RevokeSession(user, sessionID)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON("OK", http.StatusOK)
+}
+
+// UserLoginUnlockView clears the failed login attempts of a user
+func UserLoginUnlockView(req helios.Request) {
 	user, ok := req.GetContextData(UserContextKey).(User)
 	if !ok {
 		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
@@ -76,7 +156,177 @@ func UserSessionLockView(req helios.Request) {
 	}
 
 	var username string = req.GetURLParam("username")
-	var err helios.Error = LockUserSession(user, username)
+	var err helios.Error = UnlockUserLogin(user, username)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON("OK", http.StatusOK)
+}
+
+// LoginAttemptUnlockView clears the failed login attempts from an IP address
+func LoginAttemptUnlockView(req helios.Request) {
+	user, ok := req.GetContextData(UserContextKey).(User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var unlockRequest LoginUnlockRequest
+	var ipAddress string
+	var err helios.Error
+	err = req.DeserializeRequestData(&unlockRequest)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	err = DeserializeLoginUnlockRequest(unlockRequest, &ipAddress)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	err = UnlockIPLogin(user, ipAddress)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON("OK", http.StatusOK)
+}
+
+// LoginAttemptListView returns the latest login attempts
+func LoginAttemptListView(req helios.Request) {
+	user, ok := req.GetContextData(UserContextKey).(User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	loginAttempts, err := GetAllLoginAttempt(user)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+
+	serializedLoginAttempts := make([]LoginAttemptData, 0)
+	for _, loginAttempt := range loginAttempts {
+		serializedLoginAttempts = append(serializedLoginAttempts, SerializeLoginAttempt(loginAttempt))
+	}
+	req.SendJSON(serializedLoginAttempts, http.StatusOK)
+}
+
+// PasswordChangeView changes the password of the logged in user
+func PasswordChangeView(req helios.Request) {
+	session, ok := req.GetContextData(SessionContextKey).(Session)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var passwordChangeRequest PasswordChangeRequest
+	var err helios.Error
+	err = req.DeserializeRequestData(&passwordChangeRequest)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	err = ChangePassword(session, passwordChangeRequest.OldPassword, passwordChangeRequest.NewPassword)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON("OK", http.StatusOK)
+}
+
+// PasswordResetTokenCreateView issues a token to reset the password of a user
+func PasswordResetTokenCreateView(req helios.Request) {
+	user, ok := req.GetContextData(UserContextKey).(User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var username string = req.GetURLParam("username")
+	token, resetToken, err := IssuePasswordResetToken(user, username)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializePasswordResetToken(*resetToken, token), http.StatusCreated)
+}
+
+// PasswordResetView resets the password with the token issued by the admin
+func PasswordResetView(req helios.Request) {
+	var passwordResetRequest PasswordResetRequest
+	var err helios.Error
+	err = req.DeserializeRequestData(&passwordResetRequest)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	err = ResetPassword(passwordResetRequest.Token, passwordResetRequest.NewPassword)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON("OK", http.StatusOK)
+}
+
+// TwoFactorEnrollView generates new TOTP secret of the user
+func TwoFactorEnrollView(req helios.Request) {
+	user, ok := req.GetContextData(UserContextKey).(User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	secret, provisioningURI, err := EnrollTwoFactor(user)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(TwoFactorEnrollmentData{Secret: secret, ProvisioningURI: provisioningURI}, http.StatusOK)
+}
+
+// TwoFactorConfirmView enables two-factor authentication of the user
+// and returns the recovery codes
+func TwoFactorConfirmView(req helios.Request) {
+	user, ok := req.GetContextData(UserContextKey).(User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var codeRequest TwoFactorCodeRequest
+	var err helios.Error
+	err = req.DeserializeRequestData(&codeRequest)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	codes, err := ConfirmTwoFactor(user, codeRequest.Code)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(RecoveryCodesData{RecoveryCodes: codes}, http.StatusOK)
+}
+
+// TwoFactorDisableView disables two-factor authentication of the user
+func TwoFactorDisableView(req helios.Request) {
+	user, ok := req.GetContextData(UserContextKey).(User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var codeRequest TwoFactorCodeRequest
+	var err helios.Error
+	err = req.DeserializeRequestData(&codeRequest)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	err = DisableTwoFactor(user, codeRequest.Code, req.ClientIP())
 	if err != nil {
 		req.SendJSON(err.GetMessage(), err.GetStatusCode())
 		return
@@ -84,8 +334,31 @@ func UserSessionLockView(req helios.Request) {
 	req.SendJSON("OK", http.StatusOK)
 }
 
-// UserSessionUnlockView locks the session of user
-func UserSessionUnlockView(req helios.Request) {
+// RecoveryCodeRegenerateView replaces the recovery codes of the user
+func RecoveryCodeRegenerateView(req helios.Request) {
+	user, ok := req.GetContextData(UserContextKey).(User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var codeRequest TwoFactorCodeRequest
+	var err helios.Error
+	err = req.DeserializeRequestData(&codeRequest)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	codes, err := RegenerateRecoveryCodes(user, codeRequest.Code, req.ClientIP())
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(RecoveryCodesData{RecoveryCodes: codes}, http.StatusOK)
+}
+
+// UserTwoFactorResetView disables two-factor authentication of a user
+func UserTwoFactorResetView(req helios.Request) {
 	user, ok := req.GetContextData(UserContextKey).(User)
 	if !ok {
 		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
@@ -93,10 +366,188 @@ func UserSessionUnlockView(req helios.Request) {
 	}
 
 	var username string = req.GetURLParam("username")
-	var err helios.Error = UnlockUserSession(user, username)
+	var err helios.Error = ResetTwoFactor(user, username)
 	if err != nil {
 		req.SendJSON(err.GetMessage(), err.GetStatusCode())
 		return
 	}
 	req.SendJSON("OK", http.StatusOK)
 }
+
+// APITokenListView returns the API tokens of the user
+func APITokenListView(req helios.Request) {
+	user, ok := req.GetContextData(UserContextKey).(User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	apiTokens, err := GetAllAPITokenOfUser(user)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	serializedAPITokens := make([]APITokenData, 0)
+	for _, apiToken := range apiTokens {
+		serializedAPITokens = append(serializedAPITokens, SerializeAPIToken(apiToken, ""))
+	}
+	req.SendJSON(serializedAPITokens, http.StatusOK)
+}
+
+// APITokenCreateView issues an API token for the user
+func APITokenCreateView(req helios.Request) {
+	user, ok := req.GetContextData(UserContextKey).(User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var apiTokenData APITokenData
+	var apiToken APIToken
+	var err helios.Error
+	err = req.DeserializeRequestData(&apiTokenData)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	err = DeserializeAPIToken(apiTokenData, &apiToken)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	token, err := IssueAPIToken(user, &apiToken)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeAPIToken(apiToken, token), http.StatusCreated)
+}
+
+// APITokenRevokeView revokes an API token
+func APITokenRevokeView(req helios.Request) {
+	user, ok := req.GetContextData(UserContextKey).(User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	apiTokenID, errParseAPITokenID := req.GetURLParamUint("apiTokenID")
+	if errParseAPITokenID != nil {
+		req.SendJSON(errAPITokenNotFound.GetMessage(), errAPITokenNotFound.GetStatusCode())
+		return
+	}
+
+	apiToken, err := RevokeAPIToken(user, apiTokenID)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeAPIToken(*apiToken, ""), http.StatusOK)
+}
+
+// APITokenUsageListView returns the latest usages of an API token
+func APITokenUsageListView(req helios.Request) {
+	user, ok := req.GetContextData(UserContextKey).(User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	apiTokenID, errParseAPITokenID := req.GetURLParamUint("apiTokenID")
+	if errParseAPITokenID != nil {
+		req.SendJSON(errAPITokenNotFound.GetMessage(), errAPITokenNotFound.GetStatusCode())
+		return
+	}
+
+	usages, err := GetAllAPITokenUsage(user, apiTokenID)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+
+	serializedUsages := make([]APITokenUsageData, 0)
+	for _, usage := range usages {
+		serializedUsages = append(serializedUsages, SerializeAPITokenUsage(usage))
+	}
+	req.SendJSON(serializedUsages, http.StatusOK)
+}
+
+// AuditLogQueryView returns a page of audit log matching the filter
+func AuditLogQueryView(req helios.Request) {
+	user, ok := req.GetContextData(UserContextKey).(User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var queryRequest AuditLogQueryRequest
+	var filter AuditLogFilter
+	var err helios.Error
+	err = req.DeserializeRequestData(&queryRequest)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	err = DeserializeAuditLogQuery(queryRequest, &filter)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+
+	auditLogs, err := GetAllAuditLog(user, filter)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	serializedAuditLogs := make([]AuditLogData, 0)
+	for _, auditLog := range auditLogs {
+		serializedAuditLogs = append(serializedAuditLogs, SerializeAuditLog(auditLog))
+	}
+	req.SendJSON(serializedAuditLogs, http.StatusOK)
+}
+
+// AuditLogExportView returns all audit log matching the filter as CSV
+func AuditLogExportView(req helios.Request) {
+	user, ok := req.GetContextData(UserContextKey).(User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var queryRequest AuditLogQueryRequest
+	var filter AuditLogFilter
+	var err helios.Error
+	err = req.DeserializeRequestData(&queryRequest)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	err = DeserializeAuditLogQuery(queryRequest, &filter)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+
+	auditLogs, err := ExportAuditLog(user, filter)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeAuditLogExport(auditLogs), http.StatusOK)
+}
+
+// AuditLogVerifyView checks the hash chain of the audit log
+func AuditLogVerifyView(req helios.Request) {
+	user, ok := req.GetContextData(UserContextKey).(User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	verification, err := VerifyAuditLog(user)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeAuditLogVerification(*verification), http.StatusOK)
+}
@@ -3,7 +3,9 @@ package auth
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -85,6 +87,7 @@ func TestLogoutView(t *testing.T) {
 	sessionData[UserTokenSessionKey] = token
 	contextData := make(map[string]interface{})
 	contextData[UserContextKey] = user
+	contextData[SessionContextKey] = session
 
 	req := helios.MockRequest{
 		RequestData: nil,
@@ -128,9 +131,21 @@ func TestUserCreateView(t *testing.T) {
 	}
 	testCases := []userCreateTestCase{{
 		user:               userLocal,
-		requestData:        `{"id":2,"name":"User 1","username":"user1","role":"participant"}`,
+		requestData:        `{"id":2,"name":"User 1","username":"user1","role":"participant","password":"password1"}`,
 		expectedStatusCode: http.StatusCreated,
 		expectedUserCount:  2,
+	}, {
+		user:               userLocal,
+		requestData:        `{"name":"User 2","username":"user2","role":"participant","password":"pass"}`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  "form_error",
+		expectedUserCount:  2,
+	}, {
+		user:               userLocal,
+		requestData:        `{"name":"User 3","username":"user3","role":"local","password":"password3"}`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  errUserRoleTooHigh.Code,
+		expectedUserCount:  2,
 	}, {
 		user:               userLocal,
 		requestData:        `{"name":""}`,
@@ -171,17 +186,95 @@ func TestUserCreateView(t *testing.T) {
 	}
 }
 
-func TestUserSessionLockView(t *testing.T) {
+func TestSessionListView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user User = UserFactorySaved(User{})
+	var session1 Session = Session{Token: "token1", UserID: user.ID}
+	var session2 Session = Session{Token: "token2", UserID: user.ID}
+	helios.DB.Create(&session1)
+	helios.DB.Create(&session2)
+
+	var req helios.MockRequest = helios.NewMockRequest()
+	req.SetContextData(UserContextKey, user)
+	req.SetContextData(SessionContextKey, session1)
+	SessionListView(&req)
+
+	var sessionsData []SessionData
+	json.Unmarshal(req.JSONResponse, &sessionsData)
+	assert.Equal(t, http.StatusOK, req.StatusCode)
+	assert.Equal(t, 2, len(sessionsData))
+	for _, sessionData := range sessionsData {
+		assert.Equal(t, sessionData.ID == session1.ID, sessionData.IsCurrent)
+	}
+
+	req = helios.NewMockRequest()
+	req.SetContextData(UserContextKey, "bad_user")
+	SessionListView(&req)
+	assert.Equal(t, http.StatusInternalServerError, req.StatusCode)
+}
+
+func TestSessionRevokeView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user User = UserFactorySaved(User{})
+	var session Session = Session{Token: "token1", UserID: user.ID}
+	helios.DB.Create(&session)
+	type sessionRevokeViewTestCase struct {
+		user               interface{}
+		sessionID          string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []sessionRevokeViewTestCase{{
+		user:               UserFactorySaved(User{}),
+		sessionID:          strconv.Itoa(int(session.ID)),
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errSessionNotFound.Code,
+	}, {
+		user:               user,
+		sessionID:          "bad_session_id",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errSessionNotFound.Code,
+	}, {
+		user:               "bad_user",
+		sessionID:          strconv.Itoa(int(session.ID)),
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedErrorCode:  helios.ErrInternalServerError.Code,
+	}, {
+		user:               user,
+		sessionID:          strconv.Itoa(int(session.ID)),
+		expectedStatusCode: http.StatusOK,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test SessionRevokeView testcase: %d", i)
+		var req helios.MockRequest
+		req = helios.NewMockRequest()
+		req.SetContextData(UserContextKey, testCase.user)
+		req.URLParam["sessionID"] = testCase.sessionID
+
+		SessionRevokeView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestUserLoginUnlockView(t *testing.T) {
 	helios.App.BeforeTest()
 
 	var user1 User = UserFactorySaved(User{Role: UserRoleParticipant})
-	type userCreateTestCase struct {
+	type userLoginUnlockViewTestCase struct {
 		user               interface{}
 		username           string
 		expectedStatusCode int
 		expectedErrorCode  string
 	}
-	testCases := []userCreateTestCase{{
+	testCases := []userLoginUnlockViewTestCase{{
 		user:               UserFactorySaved(User{Role: UserRoleLocal}),
 		username:           user1.Username,
 		expectedStatusCode: http.StatusOK,
@@ -197,13 +290,13 @@ func TestUserSessionLockView(t *testing.T) {
 		expectedErrorCode:  helios.ErrInternalServerError.Code,
 	}}
 	for i, testCase := range testCases {
-		t.Logf("Test UserSessionLockView testcase: %d", i)
+		t.Logf("Test UserLoginUnlockView testcase: %d", i)
 		var req helios.MockRequest
 		req = helios.NewMockRequest()
 		req.SetContextData(UserContextKey, testCase.user)
 		req.URLParam["username"] = testCase.username
 
-		UserSessionLockView(&req)
+		UserLoginUnlockView(&req)
 
 		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
 		if testCase.expectedErrorCode != "" {
@@ -214,22 +307,162 @@ func TestUserSessionLockView(t *testing.T) {
 	}
 }
 
-func TestUserSessionUnlockView(t *testing.T) {
+func TestLoginAttemptUnlockView(t *testing.T) {
 	helios.App.BeforeTest()
 
-	var user1 User = UserFactorySaved(User{Role: UserRoleParticipant})
-	type userCreateTestCase struct {
+	type loginAttemptUnlockViewTestCase struct {
 		user               interface{}
-		username           string
+		requestData        string
 		expectedStatusCode int
 		expectedErrorCode  string
 	}
-	testCases := []userCreateTestCase{{
+	testCases := []loginAttemptUnlockViewTestCase{{
 		user:               UserFactorySaved(User{Role: UserRoleLocal}),
-		username:           user1.Username,
+		requestData:        `{"ipAddress":"10.0.0.5"}`,
 		expectedStatusCode: http.StatusOK,
 	}, {
 		user:               UserFactorySaved(User{Role: UserRoleLocal}),
+		requestData:        `{"ipAddress":"10.0.0"}`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  "form_error",
+	}, {
+		user:               UserFactorySaved(User{Role: UserRoleParticipant}),
+		requestData:        `{"ipAddress":"10.0.0.5"}`,
+		expectedStatusCode: http.StatusForbidden,
+		expectedErrorCode:  errLoginAttemptAccessNotAuthorized.Code,
+	}, {
+		user:               UserFactorySaved(User{Role: UserRoleLocal}),
+		requestData:        "bad_format",
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  helios.ErrJSONParseFailed.Code,
+	}, {
+		user:               "bad_user",
+		requestData:        `{"ipAddress":"10.0.0.5"}`,
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedErrorCode:  helios.ErrInternalServerError.Code,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test LoginAttemptUnlockView testcase: %d", i)
+		var req helios.MockRequest
+		req = helios.NewMockRequest()
+		req.SetContextData(UserContextKey, testCase.user)
+		req.RequestData = testCase.requestData
+
+		LoginAttemptUnlockView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestLoginAttemptListView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	helios.DB.Create(&LoginAttempt{Username: "user1", Result: LoginResultFailed})
+	type loginAttemptListViewTestCase struct {
+		user               interface{}
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []loginAttemptListViewTestCase{{
+		user:               UserFactorySaved(User{Role: UserRoleLocal}),
+		expectedStatusCode: http.StatusOK,
+	}, {
+		user:               UserFactorySaved(User{Role: UserRoleParticipant}),
+		expectedStatusCode: http.StatusForbidden,
+		expectedErrorCode:  errLoginAttemptAccessNotAuthorized.Code,
+	}, {
+		user:               "bad_user",
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedErrorCode:  helios.ErrInternalServerError.Code,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test LoginAttemptListView testcase: %d", i)
+		var req helios.MockRequest
+		req = helios.NewMockRequest()
+		req.SetContextData(UserContextKey, testCase.user)
+
+		LoginAttemptListView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestPasswordChangeView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user User = UserFactorySaved(User{Password: "oldpass1", MustChangePassword: true})
+	var session Session = Session{Token: "token1", UserID: user.ID}
+	helios.DB.Create(&session)
+	type passwordChangeViewTestCase struct {
+		session            interface{}
+		requestData        string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []passwordChangeViewTestCase{{
+		session:            "bad_session",
+		requestData:        `{"oldPassword":"oldpass1","newPassword":"newpass1"}`,
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedErrorCode:  helios.ErrInternalServerError.Code,
+	}, {
+		session:            session,
+		requestData:        `bad_request_data`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  helios.ErrJSONParseFailed.Code,
+	}, {
+		session:            session,
+		requestData:        `{"oldPassword":"wrongpass1","newPassword":"newpass1"}`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  errWrongPassword.Code,
+	}, {
+		session:            session,
+		requestData:        `{"oldPassword":"oldpass1","newPassword":"newpass1"}`,
+		expectedStatusCode: http.StatusOK,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test PasswordChangeView testcase: %d", i)
+		var req helios.MockRequest
+		req = helios.NewMockRequest()
+		req.SetContextData(SessionContextKey, testCase.session)
+		req.RequestData = testCase.requestData
+
+		PasswordChangeView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestPasswordResetTokenCreateView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user1 User = UserFactorySaved(User{Role: UserRoleParticipant})
+	type passwordResetTokenCreateViewTestCase struct {
+		user               interface{}
+		username           string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []passwordResetTokenCreateViewTestCase{{
+		user:               UserFactorySaved(User{Role: UserRoleAdmin}),
+		username:           user1.Username,
+		expectedStatusCode: http.StatusCreated,
+	}, {
+		user:               UserFactorySaved(User{Role: UserRoleAdmin}),
 		username:           "random",
 		expectedStatusCode: http.StatusNotFound,
 		expectedErrorCode:  errUserNotFound.Code,
@@ -240,13 +473,108 @@ func TestUserSessionUnlockView(t *testing.T) {
 		expectedErrorCode:  helios.ErrInternalServerError.Code,
 	}}
 	for i, testCase := range testCases {
-		t.Logf("Test UserSessionUnlockView testcase: %d", i)
+		t.Logf("Test PasswordResetTokenCreateView testcase: %d", i)
 		var req helios.MockRequest
 		req = helios.NewMockRequest()
 		req.SetContextData(UserContextKey, testCase.user)
 		req.URLParam["username"] = testCase.username
 
-		UserSessionUnlockView(&req)
+		PasswordResetTokenCreateView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		} else {
+			var tokenData PasswordResetTokenData
+			json.Unmarshal(req.JSONResponse, &tokenData)
+			assert.Equal(t, user1.Username, tokenData.Username)
+			assert.NotEmpty(t, tokenData.Token)
+		}
+	}
+}
+
+func TestPasswordResetView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user User = UserFactorySaved(User{})
+	helios.DB.Create(&PasswordResetToken{UserID: user.ID, TokenHashed: hashToken("valid"), ExpiresAt: time.Now().Add(time.Hour)})
+	type passwordResetViewTestCase struct {
+		requestData        string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []passwordResetViewTestCase{{
+		requestData:        `bad_request_data`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  helios.ErrJSONParseFailed.Code,
+	}, {
+		requestData:        `{"token":"random","newPassword":"newpass1"}`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  errPasswordResetTokenInvalid.Code,
+	}, {
+		requestData:        `{"token":"valid","newPassword":"newpass1"}`,
+		expectedStatusCode: http.StatusOK,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test PasswordResetView testcase: %d", i)
+		var req helios.MockRequest
+		req = helios.NewMockRequest()
+		req.RequestData = testCase.requestData
+
+		PasswordResetView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestLoginVerifyView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user User = UserFactorySaved(User{Role: UserRoleAdmin, TOTPSecret: testTOTPSecret, TOTPEnabled: true})
+	var session Session = Session{Token: "token1", UserID: user.ID, TwoFactorPending: true, User: &user}
+	helios.DB.Create(&session)
+	validCode, _ := totpCode(testTOTPSecret, totpCounter(time.Now()))
+	type loginVerifyViewTestCase struct {
+		session            interface{}
+		requestData        string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []loginVerifyViewTestCase{{
+		session:            "bad_session",
+		requestData:        `{"code":"000000"}`,
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedErrorCode:  helios.ErrInternalServerError.Code,
+	}, {
+		session:            session,
+		requestData:        `bad_request_data`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  helios.ErrJSONParseFailed.Code,
+	}, {
+		session:            session,
+		requestData:        `{"code":"000000"}`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  errWrongTwoFactorCode.Code,
+	}, {
+		session:            session,
+		requestData:        `{"code":"` + validCode + `"}`,
+		expectedStatusCode: http.StatusOK,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test LoginVerifyView testcase: %d", i)
+		var req helios.MockRequest
+		req = helios.NewMockRequest()
+		req.SetContextData(SessionContextKey, testCase.session)
+		req.RequestData = testCase.requestData
+
+		LoginVerifyView(&req)
 
 		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
 		if testCase.expectedErrorCode != "" {
@@ -256,3 +584,569 @@ func TestUserSessionUnlockView(t *testing.T) {
 		}
 	}
 }
+
+func TestTwoFactorEnrollView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var req helios.MockRequest
+	var enrollmentData TwoFactorEnrollmentData
+	req = helios.NewMockRequest()
+	req.SetContextData(UserContextKey, UserFactorySaved(User{Role: UserRoleAdmin}))
+	TwoFactorEnrollView(&req)
+	json.Unmarshal(req.JSONResponse, &enrollmentData)
+	assert.Equal(t, http.StatusOK, req.StatusCode)
+	assert.NotEmpty(t, enrollmentData.Secret)
+	assert.Contains(t, enrollmentData.ProvisioningURI, enrollmentData.Secret)
+
+	req = helios.NewMockRequest()
+	req.SetContextData(UserContextKey, UserFactorySaved(User{TOTPSecret: testTOTPSecret, TOTPEnabled: true}))
+	TwoFactorEnrollView(&req)
+	assert.Equal(t, http.StatusBadRequest, req.StatusCode)
+
+	req = helios.NewMockRequest()
+	req.SetContextData(UserContextKey, "bad_user")
+	TwoFactorEnrollView(&req)
+	assert.Equal(t, http.StatusInternalServerError, req.StatusCode)
+}
+
+func TestTwoFactorConfirmView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user User = UserFactorySaved(User{Role: UserRoleAdmin, TOTPSecret: testTOTPSecret})
+	validCode, _ := totpCode(testTOTPSecret, totpCounter(time.Now()))
+	type twoFactorConfirmViewTestCase struct {
+		user               interface{}
+		requestData        string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []twoFactorConfirmViewTestCase{{
+		user:               "bad_user",
+		requestData:        `{"code":"000000"}`,
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedErrorCode:  helios.ErrInternalServerError.Code,
+	}, {
+		user:               user,
+		requestData:        `bad_request_data`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  helios.ErrJSONParseFailed.Code,
+	}, {
+		user:               user,
+		requestData:        `{"code":"000000"}`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  errWrongTwoFactorCode.Code,
+	}, {
+		user:               user,
+		requestData:        `{"code":"` + validCode + `"}`,
+		expectedStatusCode: http.StatusOK,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test TwoFactorConfirmView testcase: %d", i)
+		var req helios.MockRequest
+		req = helios.NewMockRequest()
+		req.SetContextData(UserContextKey, testCase.user)
+		req.RequestData = testCase.requestData
+
+		TwoFactorConfirmView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		} else {
+			var recoveryCodesData RecoveryCodesData
+			json.Unmarshal(req.JSONResponse, &recoveryCodesData)
+			assert.Equal(t, recoveryCodeCount, len(recoveryCodesData.RecoveryCodes))
+		}
+	}
+}
+
+func TestTwoFactorDisableView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user User = UserFactorySaved(User{Role: UserRoleLocal, TOTPSecret: testTOTPSecret, TOTPEnabled: true})
+	validCode, _ := totpCode(testTOTPSecret, totpCounter(time.Now()))
+	type twoFactorDisableViewTestCase struct {
+		user               interface{}
+		requestData        string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []twoFactorDisableViewTestCase{{
+		user:               "bad_user",
+		requestData:        `{"code":"000000"}`,
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedErrorCode:  helios.ErrInternalServerError.Code,
+	}, {
+		user:               user,
+		requestData:        `bad_request_data`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  helios.ErrJSONParseFailed.Code,
+	}, {
+		user:               UserFactorySaved(User{Role: UserRoleAdmin, TOTPSecret: testTOTPSecret, TOTPEnabled: true}),
+		requestData:        `{"code":"` + validCode + `"}`,
+		expectedStatusCode: http.StatusForbidden,
+		expectedErrorCode:  errTwoFactorRequiredByPolicy.Code,
+	}, {
+		user:               user,
+		requestData:        `{"code":"` + validCode + `"}`,
+		expectedStatusCode: http.StatusOK,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test TwoFactorDisableView testcase: %d", i)
+		var req helios.MockRequest
+		req = helios.NewMockRequest()
+		req.SetContextData(UserContextKey, testCase.user)
+		req.RequestData = testCase.requestData
+
+		TwoFactorDisableView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestRecoveryCodeRegenerateView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user User = UserFactorySaved(User{Role: UserRoleAdmin, TOTPSecret: testTOTPSecret, TOTPEnabled: true})
+	validCode, _ := totpCode(testTOTPSecret, totpCounter(time.Now()))
+	type recoveryCodeRegenerateViewTestCase struct {
+		user               interface{}
+		requestData        string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []recoveryCodeRegenerateViewTestCase{{
+		user:               "bad_user",
+		requestData:        `{"code":"000000"}`,
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedErrorCode:  helios.ErrInternalServerError.Code,
+	}, {
+		user:               user,
+		requestData:        `bad_request_data`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  helios.ErrJSONParseFailed.Code,
+	}, {
+		user:               user,
+		requestData:        `{"code":"000000"}`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  errWrongTwoFactorCode.Code,
+	}, {
+		user:               user,
+		requestData:        `{"code":"` + validCode + `"}`,
+		expectedStatusCode: http.StatusOK,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test RecoveryCodeRegenerateView testcase: %d", i)
+		var req helios.MockRequest
+		req = helios.NewMockRequest()
+		req.SetContextData(UserContextKey, testCase.user)
+		req.RequestData = testCase.requestData
+
+		RecoveryCodeRegenerateView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		} else {
+			var recoveryCodesData RecoveryCodesData
+			json.Unmarshal(req.JSONResponse, &recoveryCodesData)
+			assert.Equal(t, recoveryCodeCount, len(recoveryCodesData.RecoveryCodes))
+		}
+	}
+}
+
+func TestUserTwoFactorResetView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user1 User = UserFactorySaved(User{Role: UserRoleOrganizer, TOTPSecret: testTOTPSecret, TOTPEnabled: true})
+	type userTwoFactorResetViewTestCase struct {
+		user               interface{}
+		username           string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []userTwoFactorResetViewTestCase{{
+		user:               UserFactorySaved(User{Role: UserRoleAdmin}),
+		username:           user1.Username,
+		expectedStatusCode: http.StatusOK,
+	}, {
+		user:               UserFactorySaved(User{Role: UserRoleAdmin}),
+		username:           "random",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errUserNotFound.Code,
+	}, {
+		user:               "bad_user",
+		username:           user1.Username,
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedErrorCode:  helios.ErrInternalServerError.Code,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test UserTwoFactorResetView testcase: %d", i)
+		var req helios.MockRequest
+		req = helios.NewMockRequest()
+		req.SetContextData(UserContextKey, testCase.user)
+		req.URLParam["username"] = testCase.username
+
+		UserTwoFactorResetView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestAPITokenListView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user User = UserFactorySaved(User{Role: UserRoleLocal})
+	helios.DB.Create(&APIToken{UserID: user.ID, TokenHashed: hashToken("token1"), Scopes: []APITokenScope{{EventID: 1}}})
+	helios.DB.Create(&APIToken{UserID: user.ID + 1, TokenHashed: hashToken("token2")})
+
+	var req helios.MockRequest
+	var apiTokensData []APITokenData
+	req = helios.NewMockRequest()
+	req.SetContextData(UserContextKey, user)
+	APITokenListView(&req)
+	json.Unmarshal(req.JSONResponse, &apiTokensData)
+	assert.Equal(t, http.StatusOK, req.StatusCode)
+	assert.Equal(t, 1, len(apiTokensData))
+	assert.Empty(t, apiTokensData[0].Token, "Token should not be shown after issued")
+	assert.Equal(t, []uint{1}, apiTokensData[0].EventIDs)
+
+	req = helios.NewMockRequest()
+	req.SetContextData(UserContextKey, "bad_user")
+	APITokenListView(&req)
+	assert.Equal(t, http.StatusInternalServerError, req.StatusCode)
+}
+
+func TestAPITokenCreateView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userLocal User = UserFactorySaved(User{Role: UserRoleLocal})
+	type apiTokenCreateViewTestCase struct {
+		user               interface{}
+		requestData        string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []apiTokenCreateViewTestCase{{
+		user:               userLocal,
+		requestData:        `{"name":"Local server","eventIds":[1]}`,
+		expectedStatusCode: http.StatusCreated,
+	}, {
+		user:               userLocal,
+		requestData:        `{"name":"Local server","eventIds":[]}`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  "form_error",
+	}, {
+		user:               UserFactorySaved(User{Role: UserRoleOrganizer}),
+		requestData:        `{"name":"Local server","eventIds":[1]}`,
+		expectedStatusCode: http.StatusForbidden,
+		expectedErrorCode:  errAPITokenIssueNotAuthorized.Code,
+	}, {
+		user:               userLocal,
+		requestData:        `bad_request_data`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  helios.ErrJSONParseFailed.Code,
+	}, {
+		user:               "bad_user",
+		requestData:        `{"name":"Local server","eventIds":[1]}`,
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedErrorCode:  helios.ErrInternalServerError.Code,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test APITokenCreateView testcase: %d", i)
+		var req helios.MockRequest
+		req = helios.NewMockRequest()
+		req.SetContextData(UserContextKey, testCase.user)
+		req.RequestData = testCase.requestData
+
+		APITokenCreateView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		} else {
+			var apiTokenData APITokenData
+			json.Unmarshal(req.JSONResponse, &apiTokenData)
+			assert.Equal(t, userTokenLength, len(apiTokenData.Token))
+			assert.Equal(t, apiTokenData.Token[:apiTokenPrefixLength], apiTokenData.Prefix)
+		}
+	}
+}
+
+func TestAPITokenRevokeView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user User = UserFactorySaved(User{Role: UserRoleLocal})
+	var apiToken APIToken = APIToken{UserID: user.ID, TokenHashed: hashToken("token1")}
+	helios.DB.Create(&apiToken)
+	type apiTokenRevokeViewTestCase struct {
+		user               interface{}
+		apiTokenID         string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []apiTokenRevokeViewTestCase{{
+		user:               UserFactorySaved(User{Role: UserRoleLocal}),
+		apiTokenID:         strconv.Itoa(int(apiToken.ID)),
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errAPITokenNotFound.Code,
+	}, {
+		user:               user,
+		apiTokenID:         "bad_api_token_id",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errAPITokenNotFound.Code,
+	}, {
+		user:               "bad_user",
+		apiTokenID:         strconv.Itoa(int(apiToken.ID)),
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedErrorCode:  helios.ErrInternalServerError.Code,
+	}, {
+		user:               user,
+		apiTokenID:         strconv.Itoa(int(apiToken.ID)),
+		expectedStatusCode: http.StatusOK,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test APITokenRevokeView testcase: %d", i)
+		var req helios.MockRequest
+		req = helios.NewMockRequest()
+		req.SetContextData(UserContextKey, testCase.user)
+		req.URLParam["apiTokenID"] = testCase.apiTokenID
+
+		APITokenRevokeView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		} else {
+			var apiTokenData APITokenData
+			json.Unmarshal(req.JSONResponse, &apiTokenData)
+			assert.NotEmpty(t, apiTokenData.RevokedAt)
+		}
+	}
+}
+
+func TestAPITokenUsageListView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user User = UserFactorySaved(User{Role: UserRoleLocal})
+	var apiToken APIToken = APIToken{UserID: user.ID, TokenHashed: hashToken("token1")}
+	helios.DB.Create(&apiToken)
+	helios.DB.Create(&APITokenUsage{APITokenID: apiToken.ID, EventID: 1, IPAddress: "7.1.1.1", Result: APITokenUsageAccepted})
+	type apiTokenUsageListViewTestCase struct {
+		user               interface{}
+		apiTokenID         string
+		expectedStatusCode int
+		expectedErrorCode  string
+		expectedLength     int
+	}
+	testCases := []apiTokenUsageListViewTestCase{{
+		user:               user,
+		apiTokenID:         strconv.Itoa(int(apiToken.ID)),
+		expectedStatusCode: http.StatusOK,
+		expectedLength:     1,
+	}, {
+		user:               UserFactorySaved(User{Role: UserRoleLocal}),
+		apiTokenID:         strconv.Itoa(int(apiToken.ID)),
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errAPITokenNotFound.Code,
+	}, {
+		user:               user,
+		apiTokenID:         "bad_api_token_id",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errAPITokenNotFound.Code,
+	}, {
+		user:               "bad_user",
+		apiTokenID:         strconv.Itoa(int(apiToken.ID)),
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedErrorCode:  helios.ErrInternalServerError.Code,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test APITokenUsageListView testcase: %d", i)
+		var req helios.MockRequest
+		req = helios.NewMockRequest()
+		req.SetContextData(UserContextKey, testCase.user)
+		req.URLParam["apiTokenID"] = testCase.apiTokenID
+
+		APITokenUsageListView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		} else {
+			var usagesData []APITokenUsageData
+			json.Unmarshal(req.JSONResponse, &usagesData)
+			assert.Equal(t, testCase.expectedLength, len(usagesData))
+		}
+	}
+}
+
+func TestAuditLogQueryView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userAdmin User = UserFactorySaved(User{Role: UserRoleAdmin})
+	RecordAuditLog(helios.DB, userAdmin, auditActionUserCreate, auditTargetUser, 1, nil, nil)
+	RecordAuditLog(helios.DB, userAdmin, auditActionUserUpdate, auditTargetUser, 1, nil, nil)
+	type auditLogQueryViewTestCase struct {
+		user               interface{}
+		requestData        string
+		expectedStatusCode int
+		expectedErrorCode  string
+		expectedLength     int
+	}
+	testCases := []auditLogQueryViewTestCase{{
+		user:               userAdmin,
+		requestData:        `{}`,
+		expectedStatusCode: http.StatusOK,
+		expectedLength:     2,
+	}, {
+		user:               userAdmin,
+		requestData:        `{"action":"user.update"}`,
+		expectedStatusCode: http.StatusOK,
+		expectedLength:     1,
+	}, {
+		user:               userAdmin,
+		requestData:        `{"from":"yesterday"}`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  "form_error",
+	}, {
+		user:               UserFactorySaved(User{Role: UserRoleOrganizer}),
+		requestData:        `{}`,
+		expectedStatusCode: http.StatusForbidden,
+		expectedErrorCode:  errAuditLogAccessNotAuthorized.Code,
+	}, {
+		user:               userAdmin,
+		requestData:        `bad_request_data`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  helios.ErrJSONParseFailed.Code,
+	}, {
+		user:               "bad_user",
+		requestData:        `{}`,
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedErrorCode:  helios.ErrInternalServerError.Code,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test AuditLogQueryView testcase: %d", i)
+		var req helios.MockRequest
+		req = helios.NewMockRequest()
+		req.SetContextData(UserContextKey, testCase.user)
+		req.RequestData = testCase.requestData
+
+		AuditLogQueryView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		} else {
+			var auditLogsData []AuditLogData
+			json.Unmarshal(req.JSONResponse, &auditLogsData)
+			assert.Equal(t, testCase.expectedLength, len(auditLogsData))
+		}
+	}
+}
+
+func TestAuditLogExportView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userAdmin User = UserFactorySaved(User{Role: UserRoleAdmin})
+	RecordAuditLog(helios.DB, userAdmin, auditActionUserCreate, auditTargetUser, 1, nil, nil)
+	type auditLogExportViewTestCase struct {
+		user               interface{}
+		requestData        string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []auditLogExportViewTestCase{{
+		user:               userAdmin,
+		requestData:        `{}`,
+		expectedStatusCode: http.StatusOK,
+	}, {
+		user:               userAdmin,
+		requestData:        `{"to":"tomorrow"}`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  "form_error",
+	}, {
+		user:               UserFactorySaved(User{Role: UserRoleOrganizer}),
+		requestData:        `{}`,
+		expectedStatusCode: http.StatusForbidden,
+		expectedErrorCode:  errAuditLogAccessNotAuthorized.Code,
+	}, {
+		user:               userAdmin,
+		requestData:        `bad_request_data`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  helios.ErrJSONParseFailed.Code,
+	}, {
+		user:               "bad_user",
+		requestData:        `{}`,
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedErrorCode:  helios.ErrInternalServerError.Code,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test AuditLogExportView testcase: %d", i)
+		var req helios.MockRequest
+		req = helios.NewMockRequest()
+		req.SetContextData(UserContextKey, testCase.user)
+		req.RequestData = testCase.requestData
+
+		AuditLogExportView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		} else {
+			var exportData AuditLogExportData
+			json.Unmarshal(req.JSONResponse, &exportData)
+			assert.Contains(t, exportData.CSV, auditActionUserCreate)
+		}
+	}
+}
+
+func TestAuditLogVerifyView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userAdmin User = UserFactorySaved(User{Role: UserRoleAdmin})
+	RecordAuditLog(helios.DB, userAdmin, auditActionUserCreate, auditTargetUser, 1, nil, nil)
+
+	var req helios.MockRequest
+	var verificationData AuditLogVerificationData
+	req = helios.NewMockRequest()
+	req.SetContextData(UserContextKey, userAdmin)
+	AuditLogVerifyView(&req)
+	json.Unmarshal(req.JSONResponse, &verificationData)
+	assert.Equal(t, http.StatusOK, req.StatusCode)
+	assert.True(t, verificationData.Valid)
+	assert.Equal(t, 1, verificationData.Checked)
+
+	req = helios.NewMockRequest()
+	req.SetContextData(UserContextKey, UserFactorySaved(User{Role: UserRoleOrganizer}))
+	AuditLogVerifyView(&req)
+	assert.Equal(t, http.StatusForbidden, req.StatusCode)
+
+	req = helios.NewMockRequest()
+	req.SetContextData(UserContextKey, "bad_user")
+	AuditLogVerifyView(&req)
+	assert.Equal(t, http.StatusInternalServerError, req.StatusCode)
+}
@@ -0,0 +1,360 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/yonasadiel/charon/backend/auth"
+	"github.com/yonasadiel/charon/backend/exam"
+	"github.com/yonasadiel/charon/backend/migration"
+)
+
+// snapshotMagic is the first line of every snapshot file
+const snapshotMagic = "CHARON-BACKUP 1"
+
+// Header is the metadata of a snapshot, written as JSON in the second line of
+// the snapshot file. The database follows the header, encrypted if EventSlug
+// is set. Driver is the database the snapshot is taken from, the database
+// file for sqlite3, or the SQL script for postgres and mysql. The snapshots
+// without driver are taken from sqlite3.
+type Header struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Driver    string    `json:"driver,omitempty"`
+	// Checksum is the hex SHA-256 of the plain database
+	Checksum string `json:"checksum"`
+	// EventSlug is the event whose public key encrypts the snapshot. The
+	// database is encrypted by AES-256-GCM with a random key, and the random
+	// key is encrypted by RSA-OAEP with the event public key, so the snapshot
+	// can only be restored with the event private key.
+	EventSlug    string `json:"eventSlug,omitempty"`
+	EncryptedKey string `json:"encryptedKey,omitempty"`
+	Nonce        string `json:"nonce,omitempty"`
+}
+
+// LostAnswerReport is a participant whose answers might be lost, because the
+// event was running after the snapshot was created. LastAnsweredAt is the
+// time of the last answer in the snapshot, zero if there is none.
+type LostAnswerReport struct {
+	EventSlug      string
+	Username       string
+	LastAnsweredAt time.Time
+}
+
+// Create writes a consistent snapshot of the database to the path. The
+// postgres and mysql databases are dumped by pg_dump and mysqldump, connected
+// with the DSN. If event is not nil, the snapshot is encrypted with the event
+// public key. The file is written atomically, so a failed backup never leaves
+// a broken snapshot behind.
+func Create(db *gorm.DB, driver string, dsn string, event *exam.Event, path string) error {
+	database, err := dumpDatabase(db, driver, dsn, filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+
+	var checksum [sha256.Size]byte = sha256.Sum256(database)
+	var header Header = Header{CreatedAt: time.Now(), Driver: driver, Checksum: hex.EncodeToString(checksum[:])}
+	if event != nil {
+		database, err = encryptDatabase(&header, event, database)
+		if err != nil {
+			return err
+		}
+	}
+	headerJSON, _ := json.Marshal(header)
+
+	var snapshot bytes.Buffer
+	snapshot.WriteString(snapshotMagic + "\n")
+	snapshot.Write(headerJSON)
+	snapshot.WriteString("\n")
+	snapshot.Write(database)
+	var tmpPath string = path + ".tmp"
+	if err = ioutil.WriteFile(tmpPath, snapshot.Bytes(), 0600); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write backup file: %v", err)
+	}
+	if err = os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write backup file: %v", err)
+	}
+	return nil
+}
+
+// Read reads and verifies the snapshot, and returns its header and the plain
+// database. The private key is required if the snapshot is encrypted.
+func Read(path string, privateKey *rsa.PrivateKey) (Header, []byte, error) {
+	var header Header
+	file, err := os.Open(path)
+	if err != nil {
+		return header, nil, fmt.Errorf("failed to open snapshot: %v", err)
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	magic, err := reader.ReadString('\n')
+	if err != nil || strings.TrimSpace(magic) != snapshotMagic {
+		return header, nil, fmt.Errorf("%s is not a charon snapshot", path)
+	}
+	headerJSON, err := reader.ReadBytes('\n')
+	if err != nil || json.Unmarshal(headerJSON, &header) != nil {
+		return header, nil, fmt.Errorf("snapshot header is corrupted")
+	}
+	database, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return header, nil, fmt.Errorf("failed to read snapshot: %v", err)
+	}
+	if header.EventSlug != "" {
+		if privateKey == nil {
+			return header, nil, fmt.Errorf("snapshot is encrypted with the key of event %s, its private key is required", header.EventSlug)
+		}
+		database, err = decryptDatabase(header, privateKey, database)
+		if err != nil {
+			return header, nil, err
+		}
+	}
+	var checksum [sha256.Size]byte = sha256.Sum256(database)
+	if hex.EncodeToString(checksum[:]) != header.Checksum {
+		return header, nil, fmt.Errorf("snapshot checksum mismatch, the file is corrupted")
+	}
+	return header, database, nil
+}
+
+// Restore verifies the snapshot and replaces the database of the DSN with it,
+// see restoreFile and restoreServer. The snapshot has to be taken from the
+// same driver. It returns the snapshot header and the participants whose
+// answers might be lost since the snapshot was created.
+func Restore(path string, driver string, dsn string, privateKey *rsa.PrivateKey, migrations []migration.Migration) (Header, []LostAnswerReport, error) {
+	header, database, err := Read(path, privateKey)
+	if err != nil {
+		return header, nil, err
+	}
+	var snapshotDriver string = header.Driver
+	if snapshotDriver == "" {
+		snapshotDriver = "sqlite3"
+	}
+	if snapshotDriver != driver {
+		return header, nil, fmt.Errorf("snapshot is taken from %s database, it can't be restored to %s database", snapshotDriver, driver)
+	}
+	var reports []LostAnswerReport
+	if driver == "sqlite3" {
+		reports, err = restoreFile(header, database, dsn, migrations)
+	} else {
+		reports, err = restoreServer(header, database, driver, dsn, path, migrations)
+	}
+	return header, reports, err
+}
+
+// restoreFile replaces the sqlite3 database file with the snapshot. The
+// restored database has to pass the integrity check and be on the schema of
+// the migrations, otherwise the database file is left untouched. The replaced
+// database file is kept with .before-restore suffix.
+func restoreFile(header Header, database []byte, databaseFile string, migrations []migration.Migration) ([]LostAnswerReport, error) {
+	var restoringFile string = databaseFile + ".restoring"
+	if err := ioutil.WriteFile(restoringFile, database, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write restored database: %v", err)
+	}
+	defer os.Remove(restoringFile)
+
+	db, err := gorm.Open("sqlite3", restoringFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open restored database: %v", err)
+	}
+	var integrity string
+	if err = db.Raw("PRAGMA integrity_check").Row().Scan(&integrity); err != nil || integrity != "ok" {
+		db.Close()
+		return nil, fmt.Errorf("restored database fails integrity check: %s %v", integrity, err)
+	}
+	reports, err := checkRestored(db, header, migrations)
+	db.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = os.Stat(databaseFile); err == nil {
+		if err = os.Rename(databaseFile, databaseFile+".before-restore"); err != nil {
+			return nil, fmt.Errorf("failed to keep the current database: %v", err)
+		}
+	}
+	if err = os.Rename(restoringFile, databaseFile); err != nil {
+		return nil, fmt.Errorf("failed to replace the database: %v", err)
+	}
+	return reports, nil
+}
+
+// restoreServer loads the snapshot to the postgres or mysql database with
+// psql or mysql. The current database is dumped next to the snapshot with
+// .before-restore suffix first, and it is loaded back if the snapshot can't
+// be loaded or the restored database is not on the schema of the migrations.
+func restoreServer(header Header, dump []byte, driver string, dsn string, path string, migrations []migration.Migration) ([]LostAnswerReport, error) {
+	current, err := dumpDatabase(nil, driver, dsn, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to keep the current database: %v", err)
+	}
+	var currentPath string = path + ".before-restore"
+	if err = ioutil.WriteFile(currentPath, current, 0600); err != nil {
+		return nil, fmt.Errorf("failed to keep the current database: %v", err)
+	}
+	if err = loadDatabase(driver, dsn, dump); err != nil {
+		return nil, loadBack(driver, dsn, current, currentPath, fmt.Errorf("failed to load the snapshot: %v", err))
+	}
+	db, err := gorm.Open(driver, dsn)
+	if err != nil {
+		return nil, loadBack(driver, dsn, current, currentPath, fmt.Errorf("failed to open restored database: %v", err))
+	}
+	reports, err := checkRestored(db, header, migrations)
+	db.Close()
+	if err != nil {
+		return nil, loadBack(driver, dsn, current, currentPath, err)
+	}
+	return reports, nil
+}
+
+// loadBack loads the dump of the database taken before the restore, and
+// returns the error of the restore
+func loadBack(driver string, dsn string, current []byte, currentPath string, errRestore error) error {
+	if err := loadDatabase(driver, dsn, current); err != nil {
+		return fmt.Errorf("%v, and loading back the current database failed, it is kept in %s: %v", errRestore, currentPath, err)
+	}
+	return fmt.Errorf("%v, the current database is loaded back", errRestore)
+}
+
+// checkRestored returns error if the restored database is not on the schema
+// of the migrations, otherwise the participants whose answers might be lost
+func checkRestored(db *gorm.DB, header Header, migrations []migration.Migration) ([]LostAnswerReport, error) {
+	if err := migration.Check(db, migrations); err != nil {
+		return nil, fmt.Errorf("restored database can't be used: %v", err)
+	}
+	return reportLostAnswers(db, header.CreatedAt, time.Now())
+}
+
+// ParsePrivateKey parses the event private key, base64 of PKCS #1 like
+// the one stored in the central database
+func ParsePrivateKey(encoded string) (*rsa.PrivateKey, error) {
+	privateKeyMarshalled, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("event private key should be base64 encoded")
+	}
+	privateKey, err := x509.ParsePKCS1PrivateKey(privateKeyMarshalled)
+	if err != nil {
+		return nil, fmt.Errorf("invalid event private key: %v", err)
+	}
+	return privateKey, nil
+}
+
+// reportLostAnswers returns the participants of the events that were running
+// between the snapshot and now, ordered by event and username
+func reportLostAnswers(db *gorm.DB, snapshotAt time.Time, now time.Time) ([]LostAnswerReport, error) {
+	var events []exam.Event
+	err := db.
+		Where("state = ? or (starts_at <= ? and ends_at >= ?)", exam.EventStateRunning, now, snapshotAt).
+		Order("slug asc").
+		Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events of restored database: %v", err)
+	}
+	var reports []LostAnswerReport = make([]LostAnswerReport, 0)
+	for _, event := range events {
+		var participations []exam.Participation
+		err = db.Preload("User").
+			Select("participations.*").
+			Joins("inner join users on participations.user_id = users.id").
+			Where("participations.event_id = ?", event.ID).
+			Where("users.role = ?", auth.UserRoleParticipant).
+			Order("users.username asc").
+			Find(&participations).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to read participations of restored database: %v", err)
+		}
+		for _, participation := range participations {
+			var userQuestion exam.UserQuestion
+			err = db.Where("participation_id = ? and answer <> ?", participation.ID, "").Order("updated_at desc").First(&userQuestion).Error
+			if err != nil && !gorm.IsRecordNotFoundError(err) {
+				return nil, fmt.Errorf("failed to read answers of restored database: %v", err)
+			}
+			reports = append(reports, LostAnswerReport{
+				EventSlug:      event.Slug,
+				Username:       participation.User.Username,
+				LastAnsweredAt: userQuestion.UpdatedAt,
+			})
+		}
+	}
+	return reports, nil
+}
+
+func encryptDatabase(header *Header, event *exam.Event, database []byte) ([]byte, error) {
+	publicKeyMarshalled, err := base64.StdEncoding.DecodeString(event.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("event %s has no valid public key", event.Slug)
+	}
+	publicKey, err := x509.ParsePKCS1PublicKey(publicKeyMarshalled)
+	if err != nil {
+		return nil, fmt.Errorf("event %s has no valid public key", event.Slug)
+	}
+	var key []byte = make([]byte, 32)
+	var nonce []byte = make([]byte, 12)
+	if _, err = io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	encryptedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, publicKey, key, []byte(snapshotMagic))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt snapshot key: %v", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	header.EventSlug = event.Slug
+	header.EncryptedKey = base64.StdEncoding.EncodeToString(encryptedKey)
+	header.Nonce = base64.StdEncoding.EncodeToString(nonce)
+	return gcm.Seal(nil, nonce, database, []byte(header.Checksum)), nil
+}
+
+func decryptDatabase(header Header, privateKey *rsa.PrivateKey, encrypted []byte) ([]byte, error) {
+	encryptedKey, errKey := base64.StdEncoding.DecodeString(header.EncryptedKey)
+	nonce, errNonce := base64.StdEncoding.DecodeString(header.Nonce)
+	if errKey != nil || errNonce != nil {
+		return nil, fmt.Errorf("snapshot header is corrupted")
+	}
+	key, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, privateKey, encryptedKey, []byte(snapshotMagic))
+	if err != nil {
+		return nil, fmt.Errorf("the private key is not the key of event %s", header.EventSlug)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("snapshot header is corrupted")
+	}
+	database, err := gcm.Open(nil, nonce, encrypted, []byte(header.Checksum))
+	if err != nil {
+		return nil, fmt.Errorf("snapshot is corrupted, decryption failed")
+	}
+	return database, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
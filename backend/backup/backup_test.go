@@ -0,0 +1,222 @@
+package backup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/yonasadiel/helios"
+
+	"github.com/yonasadiel/charon/backend/announcement"
+	"github.com/yonasadiel/charon/backend/auth"
+	"github.com/yonasadiel/charon/backend/config"
+	"github.com/yonasadiel/charon/backend/exam"
+	"github.com/yonasadiel/charon/backend/migration"
+)
+
+var testMigrations = migration.Collect(auth.Migrations, exam.Migrations, announcement.Migrations)
+
+func TestCreateAndRestore(t *testing.T) {
+	helios.App.BeforeTest()
+	assert.Nil(t, migration.Up(helios.DB, testMigrations))
+	dir, err := ioutil.TempDir("", "charon-backup")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	var now time.Time = time.Now()
+	var eventRunning exam.Event = exam.EventFactorySaved(exam.Event{State: exam.EventStateRunning, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)})
+	var eventFinished exam.Event = exam.EventFactorySaved(exam.Event{State: exam.EventStateGraded, StartsAt: now.Add(-48 * time.Hour), EndsAt: now.Add(-47 * time.Hour)})
+	var userQuestion exam.UserQuestion = exam.UserQuestionFactorySaved(exam.UserQuestion{
+		Participation: &exam.Participation{Event: &eventRunning, EventID: eventRunning.ID},
+	})
+	exam.ParticipationFactorySaved(exam.Participation{Event: &eventRunning, EventID: eventRunning.ID})
+	exam.ParticipationFactorySaved(exam.Participation{Event: &eventFinished, EventID: eventFinished.ID})
+
+	privateKey, err := ParsePrivateKey(eventRunning.PrvKey)
+	assert.Nil(t, err)
+	otherPrivateKey, err := ParsePrivateKey(eventFinished.PrvKey)
+	assert.Nil(t, err)
+	var plainPath string = filepath.Join(dir, "plain.backup")
+	var encryptedPath string = filepath.Join(dir, "encrypted.backup")
+	assert.NotNil(t, Create(helios.DB, "oracle", "", nil, plainPath))
+	assert.Nil(t, Create(helios.DB, "sqlite3", "", nil, plainPath))
+	assert.Nil(t, Create(helios.DB, "sqlite3", "", &eventRunning, encryptedPath))
+
+	header, _, err := Read(plainPath, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "", header.EventSlug)
+	assert.Equal(t, "sqlite3", header.Driver)
+	_, _, err = Read(encryptedPath, nil)
+	assert.NotNil(t, err)
+	_, _, err = Read(encryptedPath, otherPrivateKey)
+	assert.NotNil(t, err)
+	header, _, err = Read(encryptedPath, privateKey)
+	assert.Nil(t, err)
+	assert.Equal(t, eventRunning.Slug, header.EventSlug)
+
+	// corrupted snapshot is rejected and the database is untouched
+	var databaseFile string = filepath.Join(dir, "local.sqlite3")
+	assert.Nil(t, ioutil.WriteFile(databaseFile, []byte("current"), 0600))
+	snapshot, _ := ioutil.ReadFile(plainPath)
+	snapshot[len(snapshot)-1] ^= 0xff
+	var corruptedPath string = filepath.Join(dir, "corrupted.backup")
+	assert.Nil(t, ioutil.WriteFile(corruptedPath, snapshot, 0600))
+	_, _, err = Restore(corruptedPath, "sqlite3", databaseFile, nil, testMigrations)
+	assert.NotNil(t, err)
+	_, _, err = Restore(plainPath, "postgres", "host=localhost dbname=charon", nil, testMigrations)
+	assert.NotNil(t, err, "Snapshot of sqlite3 database should not be restored to postgres database")
+	current, _ := ioutil.ReadFile(databaseFile)
+	assert.Equal(t, "current", string(current))
+
+	_, reports, err := Restore(encryptedPath, "sqlite3", databaseFile, privateKey, testMigrations)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(reports))
+	for _, report := range reports {
+		assert.Equal(t, eventRunning.Slug, report.EventSlug)
+		if report.Username == userQuestion.Participation.User.Username {
+			assert.False(t, report.LastAnsweredAt.IsZero())
+		} else {
+			assert.True(t, report.LastAnsweredAt.IsZero())
+		}
+	}
+	current, _ = ioutil.ReadFile(databaseFile + ".before-restore")
+	assert.Equal(t, "current", string(current))
+	restoredDB, err := gorm.Open("sqlite3", databaseFile)
+	assert.Nil(t, err)
+	defer restoredDB.Close()
+	var restoredUserQuestion exam.UserQuestion
+	restoredDB.Where("id = ?", userQuestion.ID).First(&restoredUserQuestion)
+	assert.Equal(t, userQuestion.Answer, restoredUserQuestion.Answer)
+}
+
+func TestCreateScheduled(t *testing.T) {
+	helios.App.BeforeTest()
+	dir, err := ioutil.TempDir("", "charon-backup")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	var event exam.Event = exam.EventFactorySaved(exam.Event{})
+
+	_, err = CreateScheduled(helios.DB, "sqlite3", "", dir, 2, "unknown-event")
+	assert.NotNil(t, err)
+	for i := 0; i < 3; i++ {
+		path, err := CreateScheduled(helios.DB, "sqlite3", "", dir, 2, event.Slug)
+		assert.Nil(t, err)
+		_, err = os.Stat(path)
+		assert.Nil(t, err)
+		time.Sleep(time.Millisecond)
+	}
+	paths, _ := filepath.Glob(filepath.Join(dir, snapshotFilePattern))
+	assert.Equal(t, 2, len(paths))
+}
+
+func TestSchedule(t *testing.T) {
+	helios.App.BeforeTest()
+	dir, err := ioutil.TempDir("", "charon-backup")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	stop := Schedule(helios.DB, "sqlite3", "", dir, 10*time.Millisecond, 0, "")
+	var paths []string
+	for i := 0; i < 100 && len(paths) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+		paths, _ = filepath.Glob(filepath.Join(dir, snapshotFilePattern))
+	}
+	assert.NotEqual(t, 0, len(paths))
+	stop()
+
+	// no snapshot is created after the stop function returns
+	paths, _ = filepath.Glob(filepath.Join(dir, snapshotFilePattern))
+	time.Sleep(30 * time.Millisecond)
+	pathsAfterStop, _ := filepath.Glob(filepath.Join(dir, snapshotFilePattern))
+	assert.Equal(t, paths, pathsAfterStop)
+}
+
+func TestCreateAndRestoreServer(t *testing.T) {
+	var driver string = os.Getenv("CHARON_TEST_DB_DRIVER")
+	var dsn string = os.Getenv("CHARON_TEST_DB_DSN")
+	if (driver != "postgres" && driver != "mysql") || dsn == "" {
+		t.Skip("CHARON_TEST_DB_DRIVER and CHARON_TEST_DB_DSN of postgres or mysql database are required")
+	}
+	db, err := config.OpenDatabase(driver, dsn)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer db.Close()
+	assert.Nil(t, migration.Up(db, testMigrations))
+	dir, err := ioutil.TempDir("", "charon-backup")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	var event exam.Event = exam.Event{Slug: "backup-server", Title: "Before"}
+	db.Unscoped().Where("slug = ?", event.Slug).Delete(exam.Event{})
+	assert.Nil(t, db.Create(&event).Error)
+	var path string = filepath.Join(dir, "server.backup")
+	assert.Nil(t, Create(db, driver, dsn, nil, path))
+	assert.Nil(t, db.Model(&event).Update("title", "After").Error)
+
+	header, _, err := Restore(path, driver, dsn, nil, testMigrations)
+	assert.Nil(t, err)
+	assert.Equal(t, driver, header.Driver)
+	var restoredEvent exam.Event
+	db.Where("id = ?", event.ID).First(&restoredEvent)
+	assert.Equal(t, "Before", restoredEvent.Title)
+	_, err = os.Stat(path + ".before-restore")
+	assert.Nil(t, err, "Database before the restore should be kept")
+}
+
+func TestMysqlToolArgs(t *testing.T) {
+	type mysqlToolArgsTestCase struct {
+		dsn           string
+		expectedArgs  []string
+		expectedEnv   []string
+		expectedError bool
+	}
+	testCases := []mysqlToolArgsTestCase{
+		mysqlToolArgsTestCase{
+			dsn:          "charon:secret@tcp(db.example.com:3307)/charon?parseTime=true",
+			expectedArgs: []string{"--user=charon", "--protocol=TCP", "--host=db.example.com", "--port=3307", "--single-transaction", "charon"},
+			expectedEnv:  []string{"MYSQL_PWD=secret"},
+		},
+		mysqlToolArgsTestCase{
+			dsn:          "root@unix(/tmp/mysqld.sock)/charon_test?parseTime=true",
+			expectedArgs: []string{"--user=root", "--protocol=SOCKET", "--socket=/tmp/mysqld.sock", "--single-transaction", "charon_test"},
+		},
+		mysqlToolArgsTestCase{dsn: "charon@tcp(db.example.com/charon", expectedError: true},
+	}
+	for i, testCase := range testCases {
+		t.Logf("Test MysqlToolArgs testcase: %d", i)
+		args, env, err := mysqlToolArgs(testCase.dsn, "--single-transaction")
+		if testCase.expectedError {
+			assert.NotNil(t, err)
+		} else {
+			assert.Nil(t, err)
+			assert.Equal(t, testCase.expectedArgs, args)
+			assert.Equal(t, testCase.expectedEnv, env)
+		}
+	}
+}
+
+func TestReportLostAnswers(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	assert.Nil(t, err)
+	defer db.Close()
+	db.AutoMigrate(&auth.User{}, &exam.Event{}, &exam.Participation{})
+	var now time.Time = time.Now()
+	var user auth.User = auth.User{Username: "participant", Role: auth.UserRoleParticipant}
+	var event exam.Event = exam.Event{Slug: "running", State: exam.EventStateRunning}
+	db.Create(&user)
+	db.Create(&event)
+	db.Create(&exam.Participation{EventID: event.ID, UserID: user.ID})
+
+	_, err = reportLostAnswers(db, now.Add(-time.Hour), now)
+	assert.NotNil(t, err, "Failed read of the answers should not be reported as no answer")
+
+	db.AutoMigrate(&exam.UserQuestion{})
+	reports, err := reportLostAnswers(db, now.Add(-time.Hour), now)
+	assert.Nil(t, err)
+	assert.Equal(t, []LostAnswerReport{{EventSlug: "running", Username: "participant"}}, reports)
+}
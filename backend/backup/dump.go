@@ -0,0 +1,114 @@
+package backup
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+)
+
+// dumpDatabase returns the dump of the database, the database file for
+// sqlite3, or the SQL script of pg_dump or mysqldump for postgres and mysql.
+// The sqlite3 snapshot is taken into a temporary file in dir.
+func dumpDatabase(db *gorm.DB, driver string, dsn string, dir string) ([]byte, error) {
+	switch driver {
+	case "sqlite3":
+		databaseFile, err := ioutil.TempFile(dir, ".charon-backup-*.sqlite3")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create backup file: %v", err)
+		}
+		databaseFile.Close()
+		os.Remove(databaseFile.Name()) // VACUUM INTO refuses to overwrite
+		defer os.Remove(databaseFile.Name())
+		if err = db.Exec("VACUUM INTO ?", databaseFile.Name()).Error; err != nil {
+			return nil, fmt.Errorf("failed to take database snapshot: %v", err)
+		}
+		database, err := ioutil.ReadFile(databaseFile.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read database snapshot: %v", err)
+		}
+		return database, nil
+	case "postgres":
+		// the dump is taken in one transaction, and drops the tables before
+		// creating them, so it replaces the tables when it is loaded
+		return runDatabaseTool(nil, nil, "pg_dump", "--dbname="+dsn, "--clean", "--if-exists", "--no-owner", "--no-privileges")
+	case "mysql":
+		args, env, err := mysqlToolArgs(dsn, "--single-transaction", "--add-drop-table")
+		if err != nil {
+			return nil, err
+		}
+		return runDatabaseTool(nil, env, "mysqldump", args...)
+	}
+	return nil, fmt.Errorf("backup doesn't support %s database", driver)
+}
+
+// loadDatabase applies the dump of pg_dump or mysqldump to the database. The
+// postgres dump is applied in one transaction, so the database is untouched
+// if it fails. The mysql dump can't, because mysql commits on every table
+// creation.
+func loadDatabase(driver string, dsn string, dump []byte) error {
+	var err error
+	switch driver {
+	case "postgres":
+		_, err = runDatabaseTool(dump, nil, "psql", "--dbname="+dsn, "--single-transaction", "--set=ON_ERROR_STOP=1", "--quiet")
+	case "mysql":
+		args, env, errArgs := mysqlToolArgs(dsn)
+		if errArgs != nil {
+			return errArgs
+		}
+		_, err = runDatabaseTool(dump, env, "mysql", args...)
+	default:
+		err = fmt.Errorf("restore doesn't support %s database", driver)
+	}
+	return err
+}
+
+// mysqlToolArgs returns the arguments of mysql and mysqldump to connect to the
+// database of the DSN with the options, and their environment. The password is
+// given by the environment, so it is not shown in the process list.
+func mysqlToolArgs(dsn string, options ...string) ([]string, []string, error) {
+	config, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid mysql database DSN: %v", err)
+	}
+	var args []string = []string{"--user=" + config.User}
+	if config.Net == "unix" {
+		args = append(args, "--protocol=SOCKET", "--socket="+config.Addr)
+	} else {
+		host, port, err := net.SplitHostPort(config.Addr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid mysql database address %q: %v", config.Addr, err)
+		}
+		args = append(args, "--protocol=TCP", "--host="+host, "--port="+port)
+	}
+	var env []string
+	if config.Passwd != "" {
+		env = []string{"MYSQL_PWD=" + config.Passwd}
+	}
+	args = append(args, options...)
+	return append(args, config.DBName), env, nil
+}
+
+// runDatabaseTool runs the command with input as its standard input, and
+// returns its standard output. The standard error is returned in the error.
+func runDatabaseTool(input []byte, env []string, name string, args ...string) ([]byte, error) {
+	if _, err := exec.LookPath(name); err != nil {
+		return nil, fmt.Errorf("%s is required to back up and restore the database: %v", name, err)
+	}
+	var stdout, stderr bytes.Buffer
+	command := exec.Command(name, args...)
+	command.Env = append(os.Environ(), env...)
+	command.Stdin = bytes.NewReader(input)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	if err := command.Run(); err != nil {
+		return nil, fmt.Errorf("%s failed: %v: %s", filepath.Base(name), err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,86 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/yonasadiel/charon/backend/exam"
+	"github.com/yonasadiel/charon/backend/logging"
+)
+
+// snapshotFilePattern is the name of the scheduled snapshot files, sorted
+// by the creation time
+const snapshotFilePattern = "charon-*.backup"
+
+// Schedule creates a snapshot in the directory every interval until the
+// returned stop function is called. If eventSlug is not empty, the snapshots
+// are encrypted with the key of the event. Only the newest keep snapshots
+// are kept, zero keeps all. The failures are logged, so a broken backup disk
+// doesn't stop the exam. The stop function waits for the snapshot being
+// created, so the database can be closed after it returns.
+func Schedule(db *gorm.DB, driver string, dsn string, dir string, interval time.Duration, keep int, eventSlug string) (stop func()) {
+	var done chan struct{} = make(chan struct{})
+	var stopped chan struct{} = make(chan struct{})
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				ticker.Stop()
+				return
+			case <-ticker.C:
+				if path, err := CreateScheduled(db, driver, dsn, dir, keep, eventSlug); err != nil {
+					logging.Error("scheduled backup failed", logging.Fields{"error": err})
+				} else {
+					logging.Info("scheduled backup written", logging.Fields{"path": path})
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// FindEvent returns the event for encrypting the backup, nil if the slug is empty
+func FindEvent(db *gorm.DB, eventSlug string) (*exam.Event, error) {
+	if eventSlug == "" {
+		return nil, nil
+	}
+	var event exam.Event
+	if err := db.Where("slug = ?", eventSlug).First(&event).Error; err != nil && !gorm.IsRecordNotFoundError(err) {
+		return nil, fmt.Errorf("failed to read event %s for encrypting the backup: %v", eventSlug, err)
+	}
+	if event.ID == 0 {
+		return nil, fmt.Errorf("event %s for encrypting the backup is not found", eventSlug)
+	}
+	return &event, nil
+}
+
+// CreateScheduled creates a snapshot in the directory and removes the old
+// snapshots, keeping the newest keep snapshots. It returns the snapshot path.
+func CreateScheduled(db *gorm.DB, driver string, dsn string, dir string, keep int, eventSlug string) (string, error) {
+	event, err := FindEvent(db, eventSlug)
+	if err != nil {
+		return "", err
+	}
+	var path string = filepath.Join(dir, "charon-"+time.Now().UTC().Format("20060102T150405.000")+".backup")
+	if err = Create(db, driver, dsn, event, path); err != nil {
+		return "", err
+	}
+	if keep > 0 {
+		paths, _ := filepath.Glob(filepath.Join(dir, snapshotFilePattern))
+		sort.Strings(paths)
+		for i := 0; i < len(paths)-keep; i++ {
+			os.Remove(paths[i])
+		}
+	}
+	return path, nil
+}
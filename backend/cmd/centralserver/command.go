@@ -0,0 +1,86 @@
+package main
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/yonasadiel/helios"
+
+	"github.com/yonasadiel/charon/backend/backup"
+	"github.com/yonasadiel/charon/backend/config"
+	"github.com/yonasadiel/charon/backend/migration"
+)
+
+// runCommand runs the subcommand given after the flags instead of the server
+func runCommand(cfg config.Config, migrations []migration.Migration) error {
+	switch cfg.Args[0] {
+	case "migrate":
+		return migration.RunCommand(helios.DB, migrations, cfg.Args[1:], os.Stdout)
+	case "backup":
+		return runBackupCommand(cfg)
+	case "restore":
+		return runRestoreCommand(cfg, migrations)
+	}
+	return fmt.Errorf("unknown command %q, the commands are migrate, backup and restore", cfg.Args[0])
+}
+
+// runBackupCommand writes a snapshot of the database to the file, encrypted
+// with the key of the backup event if it is configured
+func runBackupCommand(cfg config.Config) error {
+	if len(cfg.Args) != 2 {
+		return fmt.Errorf("usage: backup <snapshot file>")
+	}
+	event, err := backup.FindEvent(helios.DB, cfg.BackupEvent)
+	if err != nil {
+		return err
+	}
+	err = backup.Create(helios.DB, cfg.DatabaseDriver, cfg.DatabaseDSN, event, cfg.Args[1])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Backup written to %s\n", cfg.Args[1])
+	return nil
+}
+
+// runRestoreCommand replaces the database with the snapshot, and prints the
+// participants whose answers might be lost. The event private key is required
+// for the encrypted snapshot.
+func runRestoreCommand(cfg config.Config, migrations []migration.Migration) error {
+	if len(cfg.Args) != 2 && len(cfg.Args) != 3 {
+		return fmt.Errorf("usage: restore <snapshot file> [<event private key file>]")
+	}
+	var privateKey *rsa.PrivateKey
+	if len(cfg.Args) == 3 {
+		encoded, err := ioutil.ReadFile(cfg.Args[2])
+		if err != nil {
+			return fmt.Errorf("failed to read event private key: %v", err)
+		}
+		privateKey, err = backup.ParsePrivateKey(string(encoded))
+		if err != nil {
+			return err
+		}
+	}
+
+	helios.App.CloseDB()
+	header, reports, err := backup.Restore(cfg.Args[1], cfg.DatabaseDriver, cfg.DatabaseDSN, privateKey, migrations)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Database is restored from the snapshot of %s\n", header.CreatedAt.Format(time.RFC3339))
+	if len(reports) == 0 {
+		fmt.Println("No event was running after the snapshot, no answer is lost.")
+		return nil
+	}
+	fmt.Printf("Answers of %d participant(s) after the snapshot might be lost:\n", len(reports))
+	for _, report := range reports {
+		var lastAnsweredAt string = "no answer"
+		if !report.LastAnsweredAt.IsZero() {
+			lastAnsweredAt = "last answer at " + report.LastAnsweredAt.Format(time.RFC3339)
+		}
+		fmt.Printf("  %s %s (%s)\n", report.EventSlug, report.Username, lastAnsweredAt)
+	}
+	return nil
+}
@@ -1,29 +1,71 @@
 package main
 
 import (
-	"fmt"
 	"log"
-	"net/http"
+	"os"
 
-	"github.com/jinzhu/gorm"
-	"github.com/joho/godotenv"
 	"github.com/yonasadiel/helios"
+
+	"github.com/yonasadiel/charon/backend/announcement"
+	"github.com/yonasadiel/charon/backend/auth"
+	"github.com/yonasadiel/charon/backend/config"
+	"github.com/yonasadiel/charon/backend/exam"
+	"github.com/yonasadiel/charon/backend/health"
+	"github.com/yonasadiel/charon/backend/logging"
+	"github.com/yonasadiel/charon/backend/migration"
+	"github.com/yonasadiel/charon/backend/server"
 )
 
 func main() {
-	err := godotenv.Load()
+	cfg, err := config.Load(config.Default(":8200", "central.sqlite3"), os.Args[1:])
 	if err != nil {
-		panic("Error loading env")
+		log.Fatal(err)
 	}
+	err = auth.ConfigureFromEnv()
+	if err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+	if err = logging.SetLevel(cfg.LogLevel); err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+	auth.PasswordHashCost = cfg.PasswordHashCost
+	exam.EventKeyBits = cfg.EventKeyBits
 
-	helios.App.Initialize()
-	helios.DB, err = gorm.Open("sqlite3", "central.sqlite3")
-
-	defer helios.App.CloseDB()
+	err = helios.App.Initialize()
+	if err != nil {
+		log.Fatalf("failed to initialize app: %v", err)
+	}
+	helios.App.CloseDB()
+	helios.DB, err = config.OpenDatabase(cfg.DatabaseDriver, cfg.DatabaseDSN)
+	if err != nil {
+		log.Fatal(err)
+	}
+	helios.DB.SetLogger(logging.GormLogger{})
+	helios.DB.LogMode(cfg.LogLevel == logging.LevelDebug)
 
-	helios.App.Migrate()
+	var migrations = migration.Collect(auth.Migrations, exam.Migrations, announcement.Migrations)
+	if len(cfg.Args) > 0 {
+		err = runCommand(cfg, migrations)
+		helios.App.CloseDB()
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	err = migration.Check(helios.DB, migrations)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	r := CreateRouter()
-	fmt.Println("Starting server on port 8200...")
-	log.Fatal(http.ListenAndServe(":8200", r))
+	var readyChecks []health.Check = []health.Check{
+		health.DatabaseCheck(),
+		health.MigrationCheck(migrations),
+		health.Check{Name: "keys", Run: exam.CheckEventKeys},
+	}
+	srv := server.New(cfg, CreateRouter(cfg.AllowedOrigins, readyChecks))
+	srv.AddJob("database", helios.App.CloseDB)
+	err = srv.Run(server.NotifyShutdown())
+	if err != nil {
+		log.Fatal(err)
+	}
 }
@@ -6,19 +6,28 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/yonasadiel/helios"
 
+	"github.com/yonasadiel/charon/backend/announcement"
 	"github.com/yonasadiel/charon/backend/auth"
 	"github.com/yonasadiel/charon/backend/exam"
+	"github.com/yonasadiel/charon/backend/health"
+	"github.com/yonasadiel/charon/backend/logging"
+	"github.com/yonasadiel/charon/backend/metrics"
+	"github.com/yonasadiel/charon/backend/openapi"
+	"github.com/yonasadiel/charon/backend/webapp"
 )
 
-// CreateRouter returns the router
-func CreateRouter() (router *mux.Router) {
+// CreateRouter returns the router that accepts cross-origin requests from
+// the allowed origins. The server is ready if all of the ready checks pass.
+// The API is served under webapp.APIPrefix, and the other paths serve the
+// frontend if it is embedded. The OpenAPI document of the routes is served
+// on openapi.Path.
+func CreateRouter(allowedOrigins []string, readyChecks []health.Check) (router *mux.Router) {
 	router = mux.NewRouter()
-	allowedOrigins := []string{"*"}
 
 	headerMiddleware := func(f helios.HTTPHandler) helios.HTTPHandler {
 		return func(req helios.Request) {
 			req.SetHeader("Access-Control-Max-Age", "86400")
-			req.SetHeader("Access-Control-Allow-Headers", "Content-Type")
+			req.SetHeader("Access-Control-Allow-Headers", "Content-Type, Authorization")
 			req.SetHeader("Access-Control-Allow-Credentials", "true")
 			f(req)
 		}
@@ -26,49 +35,145 @@ func CreateRouter() (router *mux.Router) {
 
 	basicMiddlewares := []helios.Middleware{helios.CreateCORSMiddleware(allowedOrigins), headerMiddleware}
 	loggedInMiddlewares := []helios.Middleware{helios.CreateCORSMiddleware(allowedOrigins), headerMiddleware, auth.LoggedInMiddleware}
+	accountSetupMiddlewares := []helios.Middleware{helios.CreateCORSMiddleware(allowedOrigins), headerMiddleware, auth.AccountSetupMiddleware}
+	apiTokenMiddlewares := []helios.Middleware{helios.CreateCORSMiddleware(allowedOrigins), headerMiddleware, auth.APITokenMiddleware(exam.GetEventIDBySlug)}
+	loginPendingMiddlewares := []helios.Middleware{helios.CreateCORSMiddleware(allowedOrigins), headerMiddleware, auth.LoginPendingMiddleware}
+
+	var operations openapi.Operations = openapi.Merge(health.Operations, metrics.Operations, openapi.DocumentOperations,
+		openapi.Prefix(webapp.APIPrefix, auth.Operations, exam.Operations, announcement.Operations))
 
 	optionHandler := func(req helios.Request) {
 		// do nothing
 	}
 
-	router.HandleFunc("/auth/login/", helios.WithMiddleware(auth.LoginView, basicMiddlewares)).Methods(http.MethodPost)
-	router.HandleFunc("/auth/login/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
-	router.HandleFunc("/auth/logout/", helios.WithMiddleware(auth.LogoutView, loggedInMiddlewares)).Methods(http.MethodPost)
-	router.HandleFunc("/auth/logout/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
-	router.HandleFunc("/auth/user/", helios.WithMiddleware(auth.UserListView, loggedInMiddlewares)).Methods(http.MethodGet)
-	router.HandleFunc("/auth/user/", helios.WithMiddleware(auth.UserCreateView, loggedInMiddlewares)).Methods(http.MethodPost)
-	router.HandleFunc("/auth/user/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
-
-	router.HandleFunc("/exam/venue/", helios.WithMiddleware(exam.VenueListView, loggedInMiddlewares)).Methods(http.MethodGet)
-	router.HandleFunc("/exam/venue/", helios.WithMiddleware(exam.VenueCreateView, loggedInMiddlewares)).Methods(http.MethodPost)
-	router.HandleFunc("/exam/venue/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
-	router.HandleFunc("/exam/venue/{venueID}/", helios.WithMiddleware(exam.VenueDeleteView, loggedInMiddlewares)).Methods(http.MethodDelete)
-	router.HandleFunc("/exam/venue/{venueID}/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
-	router.HandleFunc("/exam/", helios.WithMiddleware(exam.EventListView, loggedInMiddlewares)).Methods(http.MethodGet)
-	router.HandleFunc("/exam/", helios.WithMiddleware(exam.EventCreateView, loggedInMiddlewares)).Methods(http.MethodPost)
-	router.HandleFunc("/exam/{eventSlug}/participation/", helios.WithMiddleware(exam.ParticipationListView, loggedInMiddlewares)).Methods(http.MethodGet)
-	router.HandleFunc("/exam/{eventSlug}/participation/", helios.WithMiddleware(exam.ParticipationCreateView, loggedInMiddlewares)).Methods(http.MethodPost)
-	router.HandleFunc("/exam/{eventSlug}/participation/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
-	router.HandleFunc("/exam/{eventSlug}/verify/", helios.WithMiddleware(exam.ParticipationVerifyView, loggedInMiddlewares)).Methods(http.MethodPost)
-	router.HandleFunc("/exam/{eventSlug}/verify/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
-	router.HandleFunc("/exam/{eventSlug}/participation/{participationID}/", helios.WithMiddleware(exam.ParticipationDeleteView, loggedInMiddlewares)).Methods(http.MethodDelete)
-	router.HandleFunc("/exam/{eventSlug}/participation/{participationID}/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
-	router.HandleFunc("/exam/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
-	router.HandleFunc("/exam/{eventSlug}/sync/", helios.WithMiddleware(exam.GetSynchronizationDataView, loggedInMiddlewares)).Methods(http.MethodGet)
-	router.HandleFunc("/exam/{eventSlug}/sync/", helios.WithMiddleware(exam.PutSynchronizationDataView, loggedInMiddlewares)).Methods(http.MethodPost)
-	router.HandleFunc("/exam/{eventSlug}/sync/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
-	router.HandleFunc("/exam/{eventSlug}/decrypt/", helios.WithMiddleware(exam.DecryptEventDataView, loggedInMiddlewares)).Methods(http.MethodPost)
-	router.HandleFunc("/exam/{eventSlug}/decrypt/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
-	router.HandleFunc("/exam/{eventSlug}/question/", helios.WithMiddleware(exam.QuestionListView, loggedInMiddlewares)).Methods(http.MethodGet)
-	router.HandleFunc("/exam/{eventSlug}/question/", helios.WithMiddleware(exam.QuestionCreateView, loggedInMiddlewares)).Methods(http.MethodPost)
-	router.HandleFunc("/exam/{eventSlug}/question/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
-	router.HandleFunc("/exam/{eventSlug}/question/{questionNumber}/", helios.WithMiddleware(exam.QuestionDetailView, loggedInMiddlewares)).Methods(http.MethodGet)
-	router.HandleFunc("/exam/{eventSlug}/question/{questionNumber}/", helios.WithMiddleware(exam.QuestionDeleteView, loggedInMiddlewares)).Methods(http.MethodDelete)
-	router.HandleFunc("/exam/{eventSlug}/question/{questionNumber}/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
-	router.HandleFunc("/exam/{eventSlug}/question/{questionNumber}/submit/", helios.WithMiddleware(exam.SubmissionCreateView, loggedInMiddlewares)).Methods(http.MethodPost)
-	router.HandleFunc("/exam/{eventSlug}/question/{questionNumber}/submit/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
-
-	router.Use(mux.CORSMethodMiddleware(router))
+	router.HandleFunc("/healthz", helios.WithMiddleware(health.LiveView, nil)).Methods(http.MethodGet)
+	router.HandleFunc("/readyz", helios.WithMiddleware(health.CreateReadyView(readyChecks), nil)).Methods(http.MethodGet)
+	router.HandleFunc("/metrics", metrics.Handler).Methods(http.MethodGet)
+	router.HandleFunc(openapi.Path, openapi.Handler(router, openapi.Info{Title: "Charon Central Server API", Version: "0.1.0"}, operations)).Methods(http.MethodGet)
+
+	// the API is under a prefix, so the other paths serve the frontend
+	api := router.PathPrefix(webapp.APIPrefix).Subrouter()
+	api.HandleFunc("/auth/login/", helios.WithMiddleware(auth.LoginView, basicMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/auth/login/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/auth/login/verify/", helios.WithMiddleware(auth.LoginVerifyView, loginPendingMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/auth/login/verify/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/auth/logout/", helios.WithMiddleware(auth.LogoutView, loginPendingMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/auth/logout/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/auth/password/", helios.WithMiddleware(auth.PasswordChangeView, accountSetupMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/auth/password/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/auth/password/reset/", helios.WithMiddleware(auth.PasswordResetView, basicMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/auth/password/reset/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/auth/two-factor/", helios.WithMiddleware(auth.TwoFactorEnrollView, accountSetupMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/auth/two-factor/", helios.WithMiddleware(auth.TwoFactorDisableView, loggedInMiddlewares)).Methods(http.MethodDelete)
+	api.HandleFunc("/auth/two-factor/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/auth/two-factor/confirm/", helios.WithMiddleware(auth.TwoFactorConfirmView, accountSetupMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/auth/two-factor/confirm/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/auth/two-factor/recovery-codes/", helios.WithMiddleware(auth.RecoveryCodeRegenerateView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/auth/two-factor/recovery-codes/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/auth/user/", helios.WithMiddleware(auth.UserListView, loggedInMiddlewares)).Methods(http.MethodGet)
+	api.HandleFunc("/auth/user/", helios.WithMiddleware(auth.UserCreateView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/auth/user/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/auth/user/{username}/unlock-login/", helios.WithMiddleware(auth.UserLoginUnlockView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/auth/user/{username}/unlock-login/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/auth/user/{username}/reset-password/", helios.WithMiddleware(auth.PasswordResetTokenCreateView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/auth/user/{username}/reset-password/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/auth/user/{username}/reset-two-factor/", helios.WithMiddleware(auth.UserTwoFactorResetView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/auth/user/{username}/reset-two-factor/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/auth/login-attempt/", helios.WithMiddleware(auth.LoginAttemptListView, loggedInMiddlewares)).Methods(http.MethodGet)
+	api.HandleFunc("/auth/login-attempt/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/auth/login-attempt/unlock/", helios.WithMiddleware(auth.LoginAttemptUnlockView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/auth/login-attempt/unlock/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/auth/audit-log/", helios.WithMiddleware(auth.AuditLogQueryView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/auth/audit-log/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/auth/audit-log/export/", helios.WithMiddleware(auth.AuditLogExportView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/auth/audit-log/export/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/auth/audit-log/verify/", helios.WithMiddleware(auth.AuditLogVerifyView, loggedInMiddlewares)).Methods(http.MethodGet)
+	api.HandleFunc("/auth/audit-log/verify/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/auth/session/", helios.WithMiddleware(auth.SessionListView, loggedInMiddlewares)).Methods(http.MethodGet)
+	api.HandleFunc("/auth/session/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/auth/session/{sessionID}/", helios.WithMiddleware(auth.SessionRevokeView, loggedInMiddlewares)).Methods(http.MethodDelete)
+	api.HandleFunc("/auth/session/{sessionID}/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+
+	api.HandleFunc("/auth/api-token/", helios.WithMiddleware(auth.APITokenListView, loggedInMiddlewares)).Methods(http.MethodGet)
+	api.HandleFunc("/auth/api-token/", helios.WithMiddleware(auth.APITokenCreateView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/auth/api-token/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/auth/api-token/{apiTokenID}/", helios.WithMiddleware(auth.APITokenRevokeView, loggedInMiddlewares)).Methods(http.MethodDelete)
+	api.HandleFunc("/auth/api-token/{apiTokenID}/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/auth/api-token/{apiTokenID}/usage/", helios.WithMiddleware(auth.APITokenUsageListView, loggedInMiddlewares)).Methods(http.MethodGet)
+	api.HandleFunc("/auth/api-token/{apiTokenID}/usage/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+
+	api.HandleFunc("/exam/venue/", helios.WithMiddleware(exam.VenueListView, loggedInMiddlewares)).Methods(http.MethodGet)
+	api.HandleFunc("/exam/venue/", helios.WithMiddleware(exam.VenueCreateView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/exam/venue/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/exam/venue/{venueID}/", helios.WithMiddleware(exam.VenueDeleteView, loggedInMiddlewares)).Methods(http.MethodDelete)
+	api.HandleFunc("/exam/venue/{venueID}/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/exam/venue/{venueID}/room/", helios.WithMiddleware(exam.RoomListView, loggedInMiddlewares)).Methods(http.MethodGet)
+	api.HandleFunc("/exam/venue/{venueID}/room/", helios.WithMiddleware(exam.RoomCreateView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/exam/venue/{venueID}/room/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/exam/venue/{venueID}/room/{roomID}/", helios.WithMiddleware(exam.RoomUpdateView, loggedInMiddlewares)).Methods(http.MethodPut)
+	api.HandleFunc("/exam/venue/{venueID}/room/{roomID}/", helios.WithMiddleware(exam.RoomDeleteView, loggedInMiddlewares)).Methods(http.MethodDelete)
+	api.HandleFunc("/exam/venue/{venueID}/room/{roomID}/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/exam/", helios.WithMiddleware(exam.EventListView, loggedInMiddlewares)).Methods(http.MethodGet)
+	api.HandleFunc("/exam/", helios.WithMiddleware(exam.EventCreateView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/exam/{eventSlug}/", helios.WithMiddleware(exam.EventDetailView, loggedInMiddlewares)).Methods(http.MethodGet)
+	api.HandleFunc("/exam/{eventSlug}/", helios.WithMiddleware(exam.EventUpdateView, loggedInMiddlewares)).Methods(http.MethodPut)
+	api.HandleFunc("/exam/{eventSlug}/", helios.WithMiddleware(exam.EventDeleteView, loggedInMiddlewares)).Methods(http.MethodDelete)
+	api.HandleFunc("/exam/{eventSlug}/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/exam/{eventSlug}/role/", helios.WithMiddleware(exam.EventRoleListView, loggedInMiddlewares)).Methods(http.MethodGet)
+	api.HandleFunc("/exam/{eventSlug}/role/", helios.WithMiddleware(exam.EventRoleCreateView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/exam/{eventSlug}/role/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/exam/{eventSlug}/role/{eventRoleID}/", helios.WithMiddleware(exam.EventRoleDeleteView, loggedInMiddlewares)).Methods(http.MethodDelete)
+	api.HandleFunc("/exam/{eventSlug}/role/{eventRoleID}/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/exam/{eventSlug}/participation/", helios.WithMiddleware(exam.ParticipationListView, loggedInMiddlewares)).Methods(http.MethodGet)
+	api.HandleFunc("/exam/{eventSlug}/participation/", helios.WithMiddleware(exam.ParticipationCreateView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/exam/{eventSlug}/participation/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/exam/{eventSlug}/participation/import/", helios.WithMiddleware(exam.ParticipantImportView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/exam/{eventSlug}/participation/import/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/exam/{eventSlug}/venue/{venueID}/seat/", helios.WithMiddleware(exam.SeatingChartView, loggedInMiddlewares)).Methods(http.MethodGet)
+	api.HandleFunc("/exam/{eventSlug}/venue/{venueID}/seat/", helios.WithMiddleware(exam.SeatAssignView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/exam/{eventSlug}/venue/{venueID}/seat/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/exam/{eventSlug}/venue/{venueID}/seat/print/", helios.WithMiddleware(exam.SeatingChartPrintView, loggedInMiddlewares)).Methods(http.MethodGet)
+	api.HandleFunc("/exam/{eventSlug}/venue/{venueID}/seat/print/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/exam/{eventSlug}/venue/{venueID}/seat/import/", helios.WithMiddleware(exam.SeatImportView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/exam/{eventSlug}/venue/{venueID}/seat/import/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/exam/{eventSlug}/venue/{venueID}/card/", helios.WithMiddleware(exam.CredentialCardView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/exam/{eventSlug}/venue/{venueID}/card/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/exam/{eventSlug}/attendance/", helios.WithMiddleware(exam.PutAttendanceView, apiTokenMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/exam/{eventSlug}/attendance/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/exam/{eventSlug}/verify/", helios.WithMiddleware(exam.ParticipationVerifyView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/exam/{eventSlug}/verify/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/exam/{eventSlug}/participation/{participationID}/", helios.WithMiddleware(exam.ParticipationDeleteView, loggedInMiddlewares)).Methods(http.MethodDelete)
+	api.HandleFunc("/exam/{eventSlug}/participation/{participationID}/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/exam/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/exam/{eventSlug}/sync/", helios.WithMiddleware(exam.GetSynchronizationDataView, apiTokenMiddlewares)).Methods(http.MethodGet)
+	api.HandleFunc("/exam/{eventSlug}/sync/", helios.WithMiddleware(exam.PutSynchronizationDataView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/exam/{eventSlug}/sync/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/exam/{eventSlug}/decrypt/", helios.WithMiddleware(exam.DecryptEventDataView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/exam/{eventSlug}/decrypt/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/exam/{eventSlug}/clone/", helios.WithMiddleware(exam.EventCloneView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/exam/{eventSlug}/clone/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/exam/{eventSlug}/transition/", helios.WithMiddleware(exam.EventTransitionView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/exam/{eventSlug}/transition/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/exam/{eventSlug}/question/", helios.WithMiddleware(exam.QuestionListView, loggedInMiddlewares)).Methods(http.MethodGet)
+	api.HandleFunc("/exam/{eventSlug}/question/", helios.WithMiddleware(exam.QuestionCreateView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/exam/{eventSlug}/question/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/exam/{eventSlug}/question/{questionNumber}/", helios.WithMiddleware(exam.QuestionDetailView, loggedInMiddlewares)).Methods(http.MethodGet)
+	api.HandleFunc("/exam/{eventSlug}/question/{questionNumber}/", helios.WithMiddleware(exam.QuestionDeleteView, loggedInMiddlewares)).Methods(http.MethodDelete)
+	api.HandleFunc("/exam/{eventSlug}/question/{questionNumber}/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/exam/{eventSlug}/question/{questionNumber}/submit/", helios.WithMiddleware(exam.SubmissionCreateView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/exam/{eventSlug}/question/{questionNumber}/submit/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+
+	api.HandleFunc("/exam/{eventSlug}/announcement/", helios.WithMiddleware(announcement.AnnouncementListView, loggedInMiddlewares)).Methods(http.MethodGet)
+	api.HandleFunc("/exam/{eventSlug}/announcement/", helios.WithMiddleware(announcement.AnnouncementCreateView, loggedInMiddlewares)).Methods(http.MethodPost)
+	api.HandleFunc("/exam/{eventSlug}/announcement/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+	api.HandleFunc("/exam/{eventSlug}/announcement/after/{announcementID}/", helios.WithMiddleware(announcement.AnnouncementListView, loggedInMiddlewares)).Methods(http.MethodGet)
+	api.HandleFunc("/exam/{eventSlug}/announcement/after/{announcementID}/", helios.WithMiddleware(optionHandler, basicMiddlewares)).Methods(http.MethodOptions)
+
+	api.Use(mux.CORSMethodMiddleware(api))
+	router.Use(logging.Middleware)
+	router.Use(metrics.RouteMiddleware)
+
+	webapp.Mount(router, webapp.Assets)
 
 	return router
 }
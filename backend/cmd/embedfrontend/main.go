@@ -0,0 +1,69 @@
+// Command embedfrontend writes the Go source that embeds the compiled
+// frontend into webapp.Assets. It is run by go generate in the webapp
+// package, after the frontend is built:
+//
+//	embedfrontend <frontend build directory> <output file>
+//
+// The output is compiled only with the embedfrontend build tag, so the
+// binaries built without the frontend keep working.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+func main() {
+	if len(os.Args) != 3 {
+		log.Fatalf("usage: %s <frontend build directory> <output file>", os.Args[0])
+	}
+	var buildDir, outputFile string = os.Args[1], os.Args[2]
+	if _, err := os.Stat(filepath.Join(buildDir, "index.html")); err != nil {
+		log.Fatalf("frontend is not built: %v", err)
+	}
+
+	var source bytes.Buffer
+	source.WriteString("// Code generated by embedfrontend. DO NOT EDIT.\n\n")
+	source.WriteString("// +build embedfrontend\n\n")
+	source.WriteString("package webapp\n\n")
+	source.WriteString("import \"time\"\n\n")
+	source.WriteString("func init() {\n")
+	fmt.Fprintf(&source, "\tAssets = newMemoryFileSystem(time.Unix(%d, 0), map[string]string{\n", time.Now().Unix())
+	var count int
+	err := filepath.Walk(buildDir, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		content, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(buildDir, filePath)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&source, "\t\t%s: %s,\n", strconv.Quote("/"+filepath.ToSlash(name)), strconv.Quote(string(content)))
+		count++
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("failed to read frontend: %v", err)
+	}
+	source.WriteString("\t})\n}\n")
+
+	formatted, err := format.Source(source.Bytes())
+	if err != nil {
+		log.Fatalf("failed to format %s: %v", outputFile, err)
+	}
+	if err = ioutil.WriteFile(outputFile, formatted, 0644); err != nil {
+		log.Fatalf("failed to write %s: %v", outputFile, err)
+	}
+	fmt.Printf("%d files of %s are embedded in %s\n", count, buildDir, outputFile)
+}
@@ -3,27 +3,143 @@ package main
 import (
 	"fmt"
 	"log"
-	"net/http"
+	"os"
 
-	"github.com/jinzhu/gorm"
-	"github.com/joho/godotenv"
 	"github.com/yonasadiel/helios"
+
+	"github.com/yonasadiel/charon/backend/announcement"
+	"github.com/yonasadiel/charon/backend/auth"
+	"github.com/yonasadiel/charon/backend/backup"
+	"github.com/yonasadiel/charon/backend/config"
+	"github.com/yonasadiel/charon/backend/exam"
+	"github.com/yonasadiel/charon/backend/health"
+	"github.com/yonasadiel/charon/backend/logging"
+	"github.com/yonasadiel/charon/backend/migration"
+	"github.com/yonasadiel/charon/backend/replication"
+	"github.com/yonasadiel/charon/backend/server"
 )
 
 func main() {
-	err := godotenv.Load()
+	cfg, err := config.Load(config.Default(":8100", "local.sqlite3"), os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = auth.ConfigureFromEnv()
 	if err != nil {
-		panic("Error loading env")
+		log.Fatalf("invalid configuration: %v", err)
+	}
+	if err = logging.SetLevel(cfg.LogLevel); err != nil {
+		log.Fatalf("invalid configuration: %v", err)
 	}
+	auth.PasswordHashCost = cfg.PasswordHashCost
+	exam.EventKeyBits = cfg.EventKeyBits
 
-	helios.App.Initialize()
-	helios.DB, err = gorm.Open("sqlite3", "local.sqlite3")
+	err = helios.App.Initialize()
+	if err != nil {
+		log.Fatalf("failed to initialize app: %v", err)
+	}
+	helios.App.CloseDB()
 
-	defer helios.App.CloseDB()
+	var migrations = migration.Collect(auth.Migrations, exam.Migrations, announcement.Migrations, replication.Migrations)
+	var primary *replication.Client
+	if cfg.ReplicationRole == replication.RoleStandby {
+		primary = replication.NewClient(cfg.ReplicationPrimaryURL, cfg.ReplicationToken)
+		if _, errStat := os.Stat(cfg.DatabaseDSN); os.IsNotExist(errStat) && len(cfg.Args) == 0 {
+			logging.Info("creating standby database from the snapshot of the primary", logging.Fields{"primary": cfg.ReplicationPrimaryURL})
+			err = replication.Bootstrap(primary, cfg.DatabaseDSN, migrations)
+			if err != nil {
+				log.Fatalf("failed to create standby database: %v", err)
+			}
+		}
+	}
+	helios.DB, err = config.OpenDatabase(cfg.DatabaseDriver, cfg.DatabaseDSN)
+	if err != nil {
+		log.Fatal(err)
+	}
+	helios.DB.SetLogger(logging.GormLogger{})
+	helios.DB.LogMode(cfg.LogLevel == logging.LevelDebug)
 
-	helios.App.Migrate()
+	if len(cfg.Args) > 0 {
+		err = runCommand(cfg, migrations)
+		helios.App.CloseDB()
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	err = migration.Check(helios.DB, migrations)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var shutdown <-chan struct{} = server.NotifyShutdown()
+	if cfg.ReplicationRole != "" {
+		err = startReplication(cfg, primary, shutdown)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if isClosed(shutdown) {
+			// shut down while running as standby
+			helios.App.CloseDB()
+			return
+		}
+	}
 
-	r := CreateRouter()
-	fmt.Println("Starting server on port 8100...")
-	log.Fatal(http.ListenAndServe(":8100", r))
+	var readyChecks []health.Check = []health.Check{
+		health.DatabaseCheck(),
+		health.MigrationCheck(migrations),
+		health.Check{Name: "keys", Run: exam.CheckEventKeys},
+	}
+	srv := server.New(cfg, CreateRouter(cfg.AllowedOrigins, readyChecks))
+	srv.AddJob("database", helios.App.CloseDB)
+	if cfg.BackupInterval > 0 {
+		srv.AddJob("backup", backup.Schedule(helios.DB, cfg.DatabaseDriver, cfg.DatabaseDSN, cfg.BackupDir, cfg.BackupInterval, cfg.BackupKeep, cfg.BackupEvent))
+	}
+	err = srv.Run(shutdown)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// isClosed returns true if the channel has been closed
+func isClosed(channel <-chan struct{}) bool {
+	select {
+	case <-channel:
+		return true
+	default:
+		return false
+	}
+}
+
+// startReplication records the writes for the standby if the server is the
+// primary. If the server is the standby, it applies the writes of the primary
+// until it is promoted, then it continues as the primary, or until shutdown
+// is closed.
+func startReplication(cfg config.Config, primary *replication.Client, shutdown <-chan struct{}) error {
+	replication.Token = cfg.ReplicationToken
+	state, err := replication.GetState(helios.DB)
+	if err != nil {
+		return err
+	}
+	if cfg.ReplicationRole == replication.RoleStandby {
+		if state.Role == replication.RolePrimary {
+			return fmt.Errorf("database has been promoted to primary, set REPLICATION_ROLE=primary")
+		}
+		if state.Role != replication.RoleStandby {
+			return fmt.Errorf("database is not created from the primary snapshot, remove %s to create it", cfg.DatabaseDSN)
+		}
+		logging.Info("running as standby", logging.Fields{"primary": cfg.ReplicationPrimaryURL})
+		err = replication.RunStandby(helios.DB, primary, shutdown)
+		if err != nil || isClosed(shutdown) {
+			return err
+		}
+		logging.Info("standby is promoted, serving as primary", nil)
+	} else if state.Role == replication.RoleStandby {
+		return fmt.Errorf("database is a standby, run the promote command before running it as primary")
+	} else if state.Role == "" {
+		err = replication.SetRole(helios.DB, replication.RolePrimary, 0)
+		if err != nil {
+			return err
+		}
+	}
+	return replication.EnableCapture(helios.DB)
 }
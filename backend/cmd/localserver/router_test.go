@@ -0,0 +1,39 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yonasadiel/charon/backend/openapi"
+)
+
+func TestOpenAPI(t *testing.T) {
+	var router *mux.Router = CreateRouter(nil, nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, openapi.Path, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var document openapi.Document
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &document))
+	assert.Equal(t, openapi.Version, document.OpenAPI)
+
+	// every route is described, so add the operation of the new view to
+	// the Operations of its package
+	var operationIDs map[string]string = make(map[string]string)
+	for _, route := range openapi.Routes(router) {
+		var method, path string = strings.ToLower(strings.Split(route, " ")[0]), strings.Split(route, " ")[1]
+		var operation *openapi.OperationObject = document.Paths[path][method]
+		if !assert.NotNil(t, operation, "route %s is missing from the OpenAPI document", route) {
+			continue
+		}
+		assert.NotEmpty(t, operation.OperationID, route)
+		assert.NotContains(t, operationIDs, operation.OperationID, "operation ID of %s is used by %s", route, operationIDs[operation.OperationID])
+		operationIDs[operation.OperationID] = route
+	}
+}
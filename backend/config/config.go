@@ -0,0 +1,370 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Config is the configuration of the server. It is loaded from the defaults,
+// the configuration file, the environment variables and the command line
+// flags, each of them overrides the former.
+type Config struct {
+	// ListenAddress is the host and port the server listens on, e.g. ":8200"
+	ListenAddress string
+	// DatabaseDriver is the name of the database driver, one of
+	// SupportedDatabaseDrivers
+	DatabaseDriver string
+	// DatabaseDSN is the data source name given to the driver, e.g. the file
+	// path for sqlite3, "host=localhost dbname=charon sslmode=disable" for
+	// postgres, or "user:pass@/charon?parseTime=true" for mysql
+	DatabaseDSN string
+	// TLSCertFile and TLSKeyFile are the certificate and private key for
+	// serving HTTPS. Both are empty to serve plain HTTP.
+	TLSCertFile string
+	TLSKeyFile  string
+	// AllowedOrigins is the origins of the frontend that may send requests
+	// with credentials
+	AllowedOrigins []string
+	// TrustedProxies is the IP addresses or CIDRs of the reverse proxies whose
+	// X-Forwarded-For and X-Real-Ip headers are trusted. The headers of the
+	// other requests are ignored, and the client IP is the remote address.
+	TrustedProxies []string
+	// LogLevel is the minimum level of the logs, one of LogLevels
+	LogLevel string
+	// PasswordHashCost is the bcrypt cost of hashing the user passwords
+	PasswordHashCost int
+	// EventKeyBits is the size of RSA key generated for each event, one of
+	// EventKeySizes
+	EventKeyBits int
+	// BackupInterval is the interval of scheduled backup, zero disables it
+	BackupInterval time.Duration
+	// BackupDir is the directory of scheduled backup, preferably on another
+	// disk than the database
+	BackupDir string
+	// BackupEvent is the slug of event whose key encrypts the backup, empty
+	// to write the backup unencrypted
+	BackupEvent string
+	// BackupKeep is the number of newest scheduled backups that are kept,
+	// zero keeps all
+	BackupKeep int
+	// ReplicationRole is the role of local server in the replication, one of
+	// ReplicationRoles, empty to disable the replication
+	ReplicationRole string
+	// ReplicationPrimaryURL is the base URL of the primary, used by standby
+	ReplicationPrimaryURL string
+	// ReplicationToken is the shared secret between primary and standby
+	ReplicationToken string
+	// ShutdownTimeout is the maximum time of waiting the requests in flight
+	// to finish on shutdown, before they are cut off
+	ShutdownTimeout time.Duration
+	// Args is the arguments after the flags, e.g. the subcommand
+	Args []string
+}
+
+// SupportedDatabaseDrivers is the database drivers that can be used
+var SupportedDatabaseDrivers = []string{"sqlite3", "postgres", "mysql"}
+
+// LogLevels is the valid log levels, from the most verbose
+var LogLevels = []string{"debug", "info", "warn", "error"}
+
+// ReplicationRoles is the valid replication roles
+var ReplicationRoles = []string{"primary", "standby"}
+
+// replicationTokenMinLength is the minimum length of the replication token
+const replicationTokenMinLength = 32
+
+// EventKeySizes is the valid sizes of event RSA key in bits
+var EventKeySizes = []int{1024, 2048, 3072, 4096}
+
+// defaultConfigFile is the configuration file that is loaded if exists,
+// when no file is given
+const defaultConfigFile = ".env"
+
+// Default returns the default configuration of the server listening on the
+// address and using the sqlite3 database file
+func Default(listenAddress string, databaseFile string) Config {
+	return Config{
+		ListenAddress:    listenAddress,
+		DatabaseDriver:   "sqlite3",
+		DatabaseDSN:      databaseFile,
+		AllowedOrigins:   []string{"http://localhost:3000"},
+		LogLevel:         "info",
+		PasswordHashCost: 10,
+		EventKeyBits:     1024,
+		ShutdownTimeout:  30 * time.Second,
+	}
+}
+
+// Load returns the configuration from the defaults, overridden by the
+// configuration file, the environment variables and the command line
+// arguments. The configuration file is given by -config flag or CHARON_CONFIG,
+// and it has the same format as .env. If it is not given, .env is loaded if it
+// exists. The values of the file are exported to the environment without
+// overriding the existing variables, so the packages that are configured from
+// the environment read them too. The configuration is validated before returned.
+//
+// The environment variables are LISTEN_ADDRESS, DB_DRIVER, DB_DSN,
+// TLS_CERT_FILE, TLS_KEY_FILE, ALLOWED_ORIGINS (comma separated),
+// TRUSTED_PROXIES (comma separated), LOG_LEVEL,
+// PASSWORD_HASH_COST, EVENT_KEY_BITS, BACKUP_INTERVAL (Go duration, e.g. "5m"),
+// BACKUP_DIR, BACKUP_EVENT, BACKUP_KEEP, REPLICATION_ROLE, REPLICATION_PRIMARY_URL,
+// REPLICATION_TOKEN and SHUTDOWN_TIMEOUT (Go duration). The flags are -listen, -db-driver,
+// -db-dsn, -tls-cert, -tls-key, -allowed-origins and -log-level.
+func Load(defaults Config, args []string) (Config, error) {
+	var configFile, listenAddress, databaseDriver, databaseDSN, tlsCertFile, tlsKeyFile, allowedOrigins, logLevel string
+	var flags *flag.FlagSet = flag.NewFlagSet("server", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	flags.StringVar(&configFile, "config", "", "configuration file in .env format")
+	flags.StringVar(&listenAddress, "listen", "", "host and port to listen on")
+	flags.StringVar(&databaseDriver, "db-driver", "", "database driver")
+	flags.StringVar(&databaseDSN, "db-dsn", "", "database data source name")
+	flags.StringVar(&tlsCertFile, "tls-cert", "", "TLS certificate file")
+	flags.StringVar(&tlsKeyFile, "tls-key", "", "TLS private key file")
+	flags.StringVar(&allowedOrigins, "allowed-origins", "", "comma separated CORS origins")
+	flags.StringVar(&logLevel, "log-level", "", "minimum log level")
+	if err := flags.Parse(args); err != nil {
+		return defaults, fmt.Errorf("invalid command line arguments: %v", err)
+	}
+
+	if configFile == "" {
+		configFile = os.Getenv("CHARON_CONFIG")
+	}
+	if configFile != "" {
+		if err := godotenv.Load(configFile); err != nil {
+			return defaults, fmt.Errorf("failed to load configuration file %s: %v", configFile, err)
+		}
+	} else if _, err := os.Stat(defaultConfigFile); err == nil {
+		if err := godotenv.Load(defaultConfigFile); err != nil {
+			return defaults, fmt.Errorf("failed to load configuration file %s: %v", defaultConfigFile, err)
+		}
+	}
+
+	var config Config = defaults
+	var errs []string
+	stringFromEnv(&config.ListenAddress, "LISTEN_ADDRESS")
+	stringFromEnv(&config.DatabaseDriver, "DB_DRIVER")
+	stringFromEnv(&config.DatabaseDSN, "DB_DSN")
+	stringFromEnv(&config.TLSCertFile, "TLS_CERT_FILE")
+	stringFromEnv(&config.TLSKeyFile, "TLS_KEY_FILE")
+	stringFromEnv(&config.LogLevel, "LOG_LEVEL")
+	if origins := os.Getenv("ALLOWED_ORIGINS"); origins != "" {
+		config.AllowedOrigins = splitList(origins)
+	}
+	if proxies := os.Getenv("TRUSTED_PROXIES"); proxies != "" {
+		config.TrustedProxies = splitList(proxies)
+	}
+	if err := intFromEnv(&config.PasswordHashCost, "PASSWORD_HASH_COST"); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if err := intFromEnv(&config.EventKeyBits, "EVENT_KEY_BITS"); err != nil {
+		errs = append(errs, err.Error())
+	}
+	stringFromEnv(&config.BackupDir, "BACKUP_DIR")
+	stringFromEnv(&config.BackupEvent, "BACKUP_EVENT")
+	if err := durationFromEnv(&config.BackupInterval, "BACKUP_INTERVAL"); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if err := intFromEnv(&config.BackupKeep, "BACKUP_KEEP"); err != nil {
+		errs = append(errs, err.Error())
+	}
+	stringFromEnv(&config.ReplicationRole, "REPLICATION_ROLE")
+	stringFromEnv(&config.ReplicationPrimaryURL, "REPLICATION_PRIMARY_URL")
+	stringFromEnv(&config.ReplicationToken, "REPLICATION_TOKEN")
+	if err := durationFromEnv(&config.ShutdownTimeout, "SHUTDOWN_TIMEOUT"); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "listen":
+			config.ListenAddress = listenAddress
+		case "db-driver":
+			config.DatabaseDriver = databaseDriver
+		case "db-dsn":
+			config.DatabaseDSN = databaseDSN
+		case "tls-cert":
+			config.TLSCertFile = tlsCertFile
+		case "tls-key":
+			config.TLSKeyFile = tlsKeyFile
+		case "allowed-origins":
+			config.AllowedOrigins = splitList(allowedOrigins)
+		case "log-level":
+			config.LogLevel = logLevel
+		}
+	})
+
+	if flags.NArg() > 0 {
+		config.Args = flags.Args()
+	}
+
+	errs = append(errs, config.validate()...)
+	if len(errs) > 0 {
+		return config, fmt.Errorf("invalid configuration:\n  %s", strings.Join(errs, "\n  "))
+	}
+	return config, nil
+}
+
+// validate returns the problems of the configuration, empty if it is valid
+func (config Config) validate() []string {
+	var errs []string = make([]string, 0)
+	if _, port, err := net.SplitHostPort(config.ListenAddress); err != nil {
+		errs = append(errs, fmt.Sprintf("listen address should be host:port, got %q", config.ListenAddress))
+	} else if number, err := strconv.Atoi(port); err != nil || number < 0 || number > 65535 {
+		errs = append(errs, fmt.Sprintf("listen port should be a number between 0 and 65535, got %q", port))
+	}
+	if err := validateDatabase(config.DatabaseDriver, config.DatabaseDSN); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if (config.TLSCertFile == "") != (config.TLSKeyFile == "") {
+		errs = append(errs, "TLS certificate and key should be given together")
+	} else if config.TLSCertFile != "" {
+		for _, file := range []string{config.TLSCertFile, config.TLSKeyFile} {
+			if _, err := os.Stat(file); err != nil {
+				errs = append(errs, fmt.Sprintf("TLS file %s can't be read: %v", file, err))
+			}
+		}
+	}
+	if len(config.AllowedOrigins) == 0 {
+		errs = append(errs, "allowed origins can't be empty")
+	}
+	for _, origin := range config.AllowedOrigins {
+		if origin == "*" {
+			errs = append(errs, "allowed origins can't be *, because the requests are sent with credentials")
+			continue
+		}
+		originURL, err := url.Parse(origin)
+		if err != nil || (originURL.Scheme != "http" && originURL.Scheme != "https") || originURL.Host == "" || (originURL.Path != "" && originURL.Path != "/") {
+			errs = append(errs, fmt.Sprintf("allowed origin should be scheme://host[:port], got %q", origin))
+		}
+	}
+	if _, err := ParseTrustedProxies(config.TrustedProxies); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if !containsString(LogLevels, config.LogLevel) {
+		errs = append(errs, fmt.Sprintf("log level should be one of %s, got %q", strings.Join(LogLevels, ", "), config.LogLevel))
+	}
+	if config.PasswordHashCost < bcrypt.MinCost || config.PasswordHashCost > bcrypt.MaxCost {
+		errs = append(errs, fmt.Sprintf("password hash cost should be between %d and %d, got %d", bcrypt.MinCost, bcrypt.MaxCost, config.PasswordHashCost))
+	}
+	var isValidKeySize bool = false
+	for _, size := range EventKeySizes {
+		isValidKeySize = isValidKeySize || size == config.EventKeyBits
+	}
+	if !isValidKeySize {
+		errs = append(errs, fmt.Sprintf("event key bits should be one of %v, got %d", EventKeySizes, config.EventKeyBits))
+	}
+	if config.BackupInterval < 0 {
+		errs = append(errs, fmt.Sprintf("backup interval can't be negative, got %s", config.BackupInterval))
+	} else if config.BackupInterval > 0 {
+		if info, err := os.Stat(config.BackupDir); config.BackupDir == "" || err != nil || !info.IsDir() {
+			errs = append(errs, fmt.Sprintf("backup directory should be an existing directory, got %q", config.BackupDir))
+		}
+	}
+	if config.BackupKeep < 0 {
+		errs = append(errs, fmt.Sprintf("backup keep can't be negative, got %d", config.BackupKeep))
+	}
+	if config.ReplicationRole != "" {
+		if !containsString(ReplicationRoles, config.ReplicationRole) {
+			errs = append(errs, fmt.Sprintf("replication role should be one of %s, got %q", strings.Join(ReplicationRoles, ", "), config.ReplicationRole))
+		}
+		if config.DatabaseDriver != "sqlite3" {
+			errs = append(errs, "replication only supports sqlite3 database")
+		}
+		if len(config.ReplicationToken) < replicationTokenMinLength {
+			errs = append(errs, fmt.Sprintf("replication token should be at least %d characters", replicationTokenMinLength))
+		}
+		if config.ReplicationRole == "standby" {
+			primaryURL, err := url.Parse(config.ReplicationPrimaryURL)
+			if err != nil || (primaryURL.Scheme != "http" && primaryURL.Scheme != "https") || primaryURL.Host == "" {
+				errs = append(errs, fmt.Sprintf("replication primary URL should be http(s)://host[:port], got %q", config.ReplicationPrimaryURL))
+			}
+		}
+	}
+	if config.ShutdownTimeout <= 0 {
+		errs = append(errs, fmt.Sprintf("shutdown timeout should be positive, got %s", config.ShutdownTimeout))
+	}
+	return errs
+}
+
+// ParseTrustedProxies returns the networks of the trusted proxies. Each of
+// them is an IP address, e.g. "10.0.0.1", or a CIDR, e.g. "10.0.0.0/8".
+func ParseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet = make([]*net.IPNet, 0)
+	for _, proxy := range proxies {
+		if ip := net.ParseIP(proxy); ip != nil {
+			var bits int = 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy should be an IP address or CIDR, got %q", proxy)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+func stringFromEnv(value *string, env string) {
+	if envValue := os.Getenv(env); envValue != "" {
+		*value = envValue
+	}
+}
+
+func intFromEnv(value *int, env string) error {
+	var envValue string = os.Getenv(env)
+	if envValue == "" {
+		return nil
+	}
+	number, err := strconv.Atoi(envValue)
+	if err != nil {
+		return fmt.Errorf("%s should be a number, got %q", env, envValue)
+	}
+	*value = number
+	return nil
+}
+
+func durationFromEnv(value *time.Duration, env string) error {
+	var envValue string = os.Getenv(env)
+	if envValue == "" {
+		return nil
+	}
+	duration, err := time.ParseDuration(envValue)
+	if err != nil {
+		return fmt.Errorf("%s should be a duration, e.g. 5m, got %q", env, envValue)
+	}
+	*value = duration
+	return nil
+}
+
+func splitList(value string) []string {
+	var items []string = make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, strings.TrimSuffix(item, "/"))
+		}
+	}
+	return items
+}
+
+func containsString(items []string, value string) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
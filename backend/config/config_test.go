@@ -0,0 +1,183 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	var envs []string = []string{"CHARON_CONFIG", "LISTEN_ADDRESS", "DB_DRIVER", "DB_DSN", "TLS_CERT_FILE", "TLS_KEY_FILE", "ALLOWED_ORIGINS", "TRUSTED_PROXIES", "LOG_LEVEL", "PASSWORD_HASH_COST", "EVENT_KEY_BITS", "BACKUP_INTERVAL", "BACKUP_DIR", "BACKUP_EVENT", "BACKUP_KEEP", "REPLICATION_ROLE", "REPLICATION_PRIMARY_URL", "REPLICATION_TOKEN", "SHUTDOWN_TIMEOUT"}
+	var unsetEnvs = func() {
+		for _, env := range envs {
+			os.Unsetenv(env)
+		}
+	}
+	defer unsetEnvs()
+	dir, err := ioutil.TempDir("", "charon-config")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	var configFile string = filepath.Join(dir, "server.env")
+	var certFile string = filepath.Join(dir, "cert.pem")
+	assert.Nil(t, ioutil.WriteFile(configFile, []byte("DB_DSN=file.sqlite3\nLOG_LEVEL=debug\nEVENT_KEY_BITS=2048\n"), 0600))
+	assert.Nil(t, ioutil.WriteFile(certFile, []byte("cert"), 0600))
+	var defaults Config = Default(":8200", "central.sqlite3")
+
+	type loadTestCase struct {
+		args           []string
+		env            map[string]string
+		expectedConfig Config
+		expectedError  bool
+	}
+	testCases := []loadTestCase{
+		loadTestCase{expectedConfig: defaults},
+		loadTestCase{
+			args: []string{"-config", configFile, "-log-level", "warn"},
+			env:  map[string]string{"DB_DSN": "env.sqlite3", "ALLOWED_ORIGINS": "https://charon.example.com/, http://localhost:3000"},
+			expectedConfig: Config{
+				ListenAddress:    ":8200",
+				DatabaseDriver:   "sqlite3",
+				DatabaseDSN:      "env.sqlite3",
+				AllowedOrigins:   []string{"https://charon.example.com", "http://localhost:3000"},
+				LogLevel:         "warn",
+				PasswordHashCost: 10,
+				EventKeyBits:     2048,
+				ShutdownTimeout:  30 * time.Second,
+			},
+		},
+		loadTestCase{
+			args: []string{"-db-driver", "postgres", "-db-dsn", "host=localhost dbname=charon sslmode=disable", "migrate", "up"},
+			expectedConfig: Config{
+				ListenAddress:    ":8200",
+				DatabaseDriver:   "postgres",
+				DatabaseDSN:      "host=localhost dbname=charon sslmode=disable",
+				AllowedOrigins:   []string{"http://localhost:3000"},
+				LogLevel:         "info",
+				PasswordHashCost: 10,
+				EventKeyBits:     1024,
+				ShutdownTimeout:  30 * time.Second,
+				Args:             []string{"migrate", "up"},
+			},
+		},
+		loadTestCase{
+			args: []string{"-listen", "127.0.0.1:9000", "-tls-cert", certFile, "-tls-key", certFile},
+			env: map[string]string{
+				"CHARON_CONFIG":           configFile,
+				"PASSWORD_HASH_COST":      "12",
+				"BACKUP_INTERVAL":         "5m",
+				"BACKUP_DIR":              dir,
+				"BACKUP_KEEP":             "3",
+				"REPLICATION_ROLE":        "standby",
+				"REPLICATION_PRIMARY_URL": "http://10.0.0.2:8100",
+				"REPLICATION_TOKEN":       "0123456789abcdef0123456789abcdef",
+				"SHUTDOWN_TIMEOUT":        "1m",
+				"TRUSTED_PROXIES":         "10.0.0.1, 192.168.0.0/16, ::1",
+			},
+			expectedConfig: Config{
+				ListenAddress:         "127.0.0.1:9000",
+				DatabaseDriver:        "sqlite3",
+				DatabaseDSN:           "file.sqlite3",
+				TLSCertFile:           certFile,
+				TLSKeyFile:            certFile,
+				AllowedOrigins:        []string{"http://localhost:3000"},
+				TrustedProxies:        []string{"10.0.0.1", "192.168.0.0/16", "::1"},
+				LogLevel:              "debug",
+				PasswordHashCost:      12,
+				EventKeyBits:          2048,
+				BackupInterval:        5 * time.Minute,
+				BackupDir:             dir,
+				BackupKeep:            3,
+				ReplicationRole:       "standby",
+				ReplicationPrimaryURL: "http://10.0.0.2:8100",
+				ReplicationToken:      "0123456789abcdef0123456789abcdef",
+				ShutdownTimeout:       time.Minute,
+			},
+		},
+		loadTestCase{args: []string{"-config", filepath.Join(dir, "missing.env")}, expectedError: true},
+		loadTestCase{args: []string{"-unknown"}, expectedError: true},
+		loadTestCase{args: []string{"-listen", "8200"}, expectedError: true},
+		loadTestCase{args: []string{"-listen", ":port"}, expectedError: true},
+		loadTestCase{args: []string{"-db-driver", "oracle"}, expectedError: true},
+		loadTestCase{args: []string{"-db-dsn", ""}, expectedError: true},
+		loadTestCase{args: []string{"-db-driver", "mysql", "-db-dsn", "charon:secret@/charon"}, expectedError: true},
+		loadTestCase{args: []string{"-tls-cert", certFile}, expectedError: true},
+		loadTestCase{args: []string{"-tls-cert", certFile, "-tls-key", filepath.Join(dir, "missing.pem")}, expectedError: true},
+		loadTestCase{args: []string{"-allowed-origins", "*"}, expectedError: true},
+		loadTestCase{args: []string{"-allowed-origins", ""}, expectedError: true},
+		loadTestCase{args: []string{"-allowed-origins", "localhost:3000"}, expectedError: true},
+		loadTestCase{env: map[string]string{"TRUSTED_PROXIES": "proxy.local"}, expectedError: true},
+		loadTestCase{args: []string{"-log-level", "verbose"}, expectedError: true},
+		loadTestCase{env: map[string]string{"PASSWORD_HASH_COST": "abc"}, expectedError: true},
+		loadTestCase{env: map[string]string{"PASSWORD_HASH_COST": "64"}, expectedError: true},
+		loadTestCase{env: map[string]string{"EVENT_KEY_BITS": "512"}, expectedError: true},
+		loadTestCase{env: map[string]string{"BACKUP_INTERVAL": "often"}, expectedError: true},
+		loadTestCase{env: map[string]string{"BACKUP_INTERVAL": "5m"}, expectedError: true},
+		loadTestCase{env: map[string]string{"BACKUP_INTERVAL": "5m", "BACKUP_DIR": configFile}, expectedError: true},
+		loadTestCase{env: map[string]string{"BACKUP_KEEP": "-1"}, expectedError: true},
+		loadTestCase{env: map[string]string{"REPLICATION_ROLE": "leader", "REPLICATION_TOKEN": "0123456789abcdef0123456789abcdef"}, expectedError: true},
+		loadTestCase{env: map[string]string{"REPLICATION_ROLE": "primary", "REPLICATION_TOKEN": "secret"}, expectedError: true},
+		loadTestCase{env: map[string]string{"REPLICATION_ROLE": "standby", "REPLICATION_TOKEN": "0123456789abcdef0123456789abcdef"}, expectedError: true},
+		loadTestCase{env: map[string]string{"SHUTDOWN_TIMEOUT": "soon"}, expectedError: true},
+		loadTestCase{env: map[string]string{"SHUTDOWN_TIMEOUT": "0s"}, expectedError: true},
+	}
+
+	for i, testCase := range testCases {
+		t.Logf("Test Load testcase: %d", i)
+		unsetEnvs()
+		for env, value := range testCase.env {
+			os.Setenv(env, value)
+		}
+		config, err := Load(defaults, testCase.args)
+		if testCase.expectedError {
+			assert.NotNil(t, err)
+		} else {
+			assert.Nil(t, err)
+			assert.Equal(t, testCase.expectedConfig, config)
+		}
+	}
+}
+
+func TestSetupTestDatabase(t *testing.T) {
+	var envs []string = []string{"CHARON_TEST_DB_DRIVER", "CHARON_TEST_DB_DSN", "PATH"}
+	var savedEnvs map[string]string = map[string]string{}
+	for _, env := range envs {
+		savedEnvs[env] = os.Getenv(env)
+	}
+	defer func() {
+		for env, value := range savedEnvs {
+			os.Setenv(env, value)
+		}
+	}()
+	dir, err := ioutil.TempDir("", "charon-config")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	type setupTestDatabaseTestCase struct {
+		env           map[string]string
+		expectedError bool
+	}
+	testCases := []setupTestDatabaseTestCase{
+		setupTestDatabaseTestCase{env: map[string]string{"CHARON_TEST_DB_DRIVER": ""}},
+		setupTestDatabaseTestCase{env: map[string]string{"CHARON_TEST_DB_DRIVER": "oracle", "CHARON_TEST_DB_DSN": "dsn"}, expectedError: true},
+		setupTestDatabaseTestCase{env: map[string]string{"CHARON_TEST_DB_DRIVER": "postgres", "CHARON_TEST_DB_DSN": "", "PATH": dir}, expectedError: true},
+		setupTestDatabaseTestCase{env: map[string]string{"CHARON_TEST_DB_DRIVER": "mysql", "CHARON_TEST_DB_DSN": "", "PATH": dir}, expectedError: true},
+	}
+	for i, testCase := range testCases {
+		t.Logf("Test SetupTestDatabase testcase: %d", i)
+		for env, value := range testCase.env {
+			os.Setenv(env, value)
+		}
+		teardown, err := SetupTestDatabase()
+		assert.NotNil(t, teardown)
+		teardown()
+		if testCase.expectedError {
+			assert.NotNil(t, err)
+		} else {
+			assert.Nil(t, err)
+		}
+	}
+}
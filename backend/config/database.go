@@ -0,0 +1,183 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/mysql"    // use mysql dialect
+	_ "github.com/jinzhu/gorm/dialects/postgres" // use postgres dialect
+	_ "github.com/jinzhu/gorm/dialects/sqlite"   // use sqlite dialect
+	"github.com/yonasadiel/helios"
+)
+
+// OpenDatabase opens the database connection with the driver and DSN.
+// The connection is checked, so the error is returned here instead of
+// on the first query. The transactions of sqlite3 are begun immediate, so
+// the concurrent write transactions wait for each other on begin instead of
+// failing with database locked when they upgrade from read to write.
+func OpenDatabase(driver string, dsn string) (*gorm.DB, error) {
+	if err := validateDatabase(driver, dsn); err != nil {
+		return nil, err
+	}
+	if driver == "sqlite3" && !strings.Contains(dsn, "_txlock=") {
+		var separator string = "?"
+		if strings.Contains(dsn, "?") {
+			separator = "&"
+		}
+		dsn = dsn + separator + "_txlock=immediate"
+	}
+	db, err := gorm.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s database: %v", driver, err)
+	}
+	return db, nil
+}
+
+// SetupTestDatabase replaces the in-memory sqlite3 database of the tests with
+// the database given by CHARON_TEST_DB_DRIVER and CHARON_TEST_DB_DSN, so the
+// test suite can be run against every supported dialect. It does nothing if
+// CHARON_TEST_DB_DRIVER is not set. The database is shared between packages,
+// so the packages have to be tested one at a time, i.e. go test -p 1 ./...
+// If the driver is postgres or mysql and the DSN is empty, a throwaway server
+// is started for the package instead, see startTestPostgres and
+// startTestMySQL. The returned function stops the server, it has to be called
+// after the tests.
+func SetupTestDatabase() (func(), error) {
+	var teardown func() = func() {}
+	var driver string = os.Getenv("CHARON_TEST_DB_DRIVER")
+	if driver == "" {
+		return teardown, nil
+	}
+	var dsn string = os.Getenv("CHARON_TEST_DB_DSN")
+	if dsn == "" && (driver == "postgres" || driver == "mysql") {
+		var err error
+		var start func() (string, func(), error) = startTestPostgres
+		if driver == "mysql" {
+			start = startTestMySQL
+		}
+		if dsn, teardown, err = start(); err != nil {
+			return func() {}, err
+		}
+	}
+	db, err := OpenDatabase(driver, dsn)
+	if err != nil {
+		teardown()
+		return func() {}, err
+	}
+	helios.DB = db
+	helios.App.Migrate()
+	return func() {
+		db.Close()
+		teardown()
+	}, nil
+}
+
+// startTestPostgres initializes and starts a postgres cluster on a temporary
+// directory with initdb and pg_ctl of the local postgres installation, so the
+// tests can be run against postgres without a running server or container.
+// The cluster only listens on unix socket inside the directory, so clusters
+// of the packages tested in parallel don't conflict. initdb refuses to run
+// as root, so the tests have to be run as normal user. It returns the DSN of
+// the cluster and the function that stops and removes it.
+func startTestPostgres() (string, func(), error) {
+	for _, command := range []string{"initdb", "pg_ctl"} {
+		if _, err := exec.LookPath(command); err != nil {
+			return "", nil, fmt.Errorf("%s of postgres is required to start the test database, or set CHARON_TEST_DB_DSN: %v", command, err)
+		}
+	}
+	dir, err := ioutil.TempDir("", "charon-test-postgres")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create test database directory: %v", err)
+	}
+	var dataDir string = filepath.Join(dir, "data")
+	var logFile string = filepath.Join(dir, "postgres.log")
+	var remove func() = func() { os.RemoveAll(dir) }
+	if output, err := exec.Command("initdb", "-D", dataDir, "-U", "charon", "-A", "trust", "-E", "UTF8", "--no-sync").CombinedOutput(); err != nil {
+		remove()
+		return "", nil, fmt.Errorf("failed to initialize test database: %v: %s", err, output)
+	}
+	var options string = fmt.Sprintf("-c listen_addresses='' -k %s -F", dir)
+	if output, err := exec.Command("pg_ctl", "-D", dataDir, "-l", logFile, "-o", options, "-w", "start").CombinedOutput(); err != nil {
+		remove()
+		return "", nil, fmt.Errorf("failed to start test database: %v: %s", err, output)
+	}
+	var stop func() = func() {
+		exec.Command("pg_ctl", "-D", dataDir, "-m", "immediate", "-w", "stop").Run()
+		remove()
+	}
+	return fmt.Sprintf("host=%s user=charon dbname=postgres sslmode=disable", dir), stop, nil
+}
+
+// validateDatabase returns the problem of the database driver and DSN, or
+// nil if they are valid
+func validateDatabase(driver string, dsn string) error {
+	if !containsString(SupportedDatabaseDrivers, driver) {
+		return fmt.Errorf("database driver should be one of %s, got %q", strings.Join(SupportedDatabaseDrivers, ", "), driver)
+	}
+	if dsn == "" {
+		return fmt.Errorf("database DSN can't be empty")
+	}
+	if driver == "mysql" && !strings.Contains(dsn, "parseTime=true") {
+		return fmt.Errorf("mysql database DSN should have parseTime=true, so the time columns can be read")
+	}
+	return nil
+}
+
+// startTestMySQL initializes and starts a mysql server on a temporary
+// directory with mysqld of the local mysql installation, the same as
+// startTestPostgres. The server only listens on unix socket inside the
+// directory. It returns the DSN of charon_test database on the server and
+// the function that stops and removes it.
+func startTestMySQL() (string, func(), error) {
+	if _, err := exec.LookPath("mysqld"); err != nil {
+		return "", nil, fmt.Errorf("mysqld is required to start the test database, or set CHARON_TEST_DB_DSN: %v", err)
+	}
+	dir, err := ioutil.TempDir("", "charon-test-mysql")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create test database directory: %v", err)
+	}
+	var dataDir string = filepath.Join(dir, "data")
+	var socket string = filepath.Join(dir, "mysqld.sock")
+	var remove func() = func() { os.RemoveAll(dir) }
+	if output, err := exec.Command("mysqld", "--no-defaults", "--initialize-insecure", "--datadir="+dataDir).CombinedOutput(); err != nil {
+		remove()
+		return "", nil, fmt.Errorf("failed to initialize test database: %v: %s", err, output)
+	}
+	server := exec.Command("mysqld", "--no-defaults", "--datadir="+dataDir, "--socket="+socket, "--skip-networking",
+		"--pid-file="+filepath.Join(dir, "mysqld.pid"), "--log-error="+filepath.Join(dir, "mysqld.log"))
+	if err := server.Start(); err != nil {
+		remove()
+		return "", nil, fmt.Errorf("failed to start test database: %v", err)
+	}
+	var stop func() = func() {
+		server.Process.Kill()
+		server.Wait()
+		remove()
+	}
+	// the server doesn't tell when it is ready, so it is connected until it accepts
+	var deadline time.Time = time.Now().Add(time.Minute)
+	for {
+		db, err := gorm.Open("mysql", fmt.Sprintf("root@unix(%s)/", socket))
+		if err == nil {
+			err = db.Exec("CREATE DATABASE charon_test CHARACTER SET utf8mb4").Error
+			db.Close()
+			if err != nil {
+				stop()
+				return "", nil, fmt.Errorf("failed to create test database: %v", err)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			stop()
+			return "", nil, fmt.Errorf("test database is not ready: %v", err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return fmt.Sprintf("root@unix(%s)/charon_test?parseTime=true&charset=utf8mb4", socket), stop, nil
+}
@@ -0,0 +1,129 @@
+package exam
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/jung-kurt/gofpdf"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// credentialCard is the content printed on a card of a participant
+type credentialCard struct {
+	Name     string
+	Username string
+	Password string
+	Key      string
+}
+
+// renderCredentialCards renders the cards as A4 PDF. Sheet layout prints a card
+// on each page, while card layout prints cut-out cards on a grid.
+func renderCredentialCards(event Event, venue Venue, cards []credentialCard, layout string) ([]byte, error) {
+	var pdf *gofpdf.Fpdf = gofpdf.New("P", "mm", "A4", "")
+	var translate func(string) string = pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle(translate(fmt.Sprintf("%s - %s", event.Title, venue.Name)), false)
+	pdf.SetAutoPageBreak(false, 0)
+
+	var pageWidth, pageHeight float64 = pdf.GetPageSize()
+	var columns, rows int = 1, 1
+	if layout == CredentialCardLayoutCard {
+		columns, rows = credentialCardColumns, credentialCardRows
+	}
+	var cardWidth float64 = pageWidth / float64(columns)
+	var cardHeight float64 = pageHeight / float64(rows)
+	for i, card := range cards {
+		var position int = i % (columns * rows)
+		if position == 0 {
+			pdf.AddPage()
+		}
+		var x float64 = float64(position%columns) * cardWidth
+		var y float64 = float64(position/columns) * cardHeight
+
+		qrPNG, errEncode := qrcode.Encode(card.Key, qrcode.Medium, 256)
+		if errEncode != nil {
+			return nil, errEncode
+		}
+		var imageName string = fmt.Sprintf("qr-%d", i)
+		var imageOptions gofpdf.ImageOptions = gofpdf.ImageOptions{ImageType: "PNG"}
+		pdf.RegisterImageOptionsReader(imageName, imageOptions, bytes.NewReader(qrPNG))
+
+		if layout == CredentialCardLayoutCard {
+			renderCutOutCard(pdf, translate, event, venue, card, imageName, x, y, cardWidth, cardHeight)
+		} else {
+			renderSheet(pdf, translate, event, venue, card, imageName, pageWidth)
+		}
+	}
+	if len(cards) == 0 {
+		pdf.AddPage()
+	}
+
+	var buffer bytes.Buffer
+	if errOutput := pdf.Output(&buffer); errOutput != nil {
+		return nil, errOutput
+	}
+	return buffer.Bytes(), nil
+}
+
+// renderCutOutCard renders a card with dashed border at the given position
+func renderCutOutCard(pdf *gofpdf.Fpdf, translate func(string) string, event Event, venue Venue, card credentialCard, imageName string, x, y, width, height float64) {
+	const margin float64 = 6
+	var qrSize float64 = height - 2*margin - 12
+
+	pdf.SetDashPattern([]float64{2, 2}, 0)
+	pdf.SetLineWidth(0.2)
+	pdf.Rect(x, y, width, height, "D")
+	pdf.SetDashPattern([]float64{}, 0)
+
+	pdf.SetXY(x+margin, y+margin)
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(width-2*margin, 6, translate(event.Title), "", 2, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.CellFormat(width-2*margin, 5, translate(venue.Name), "", 2, "L", false, 0, "")
+
+	var textX float64 = x + margin
+	var textWidth float64 = width - 3*margin - qrSize
+	pdf.SetXY(textX, y+margin+12)
+	renderCredentialField(pdf, translate, "Nama", card.Name, textWidth, 9)
+	renderCredentialField(pdf, translate, "Username", card.Username, textWidth, 9)
+	renderCredentialField(pdf, translate, "Password", card.Password, textWidth, 9)
+	renderCredentialField(pdf, translate, "Kunci", card.Key, textWidth, 7)
+
+	var imageOptions gofpdf.ImageOptions = gofpdf.ImageOptions{ImageType: "PNG"}
+	pdf.ImageOptions(imageName, x+width-margin-qrSize, y+margin+12, qrSize, qrSize, false, imageOptions, 0, "")
+}
+
+// renderSheet renders a full page card of the participant
+func renderSheet(pdf *gofpdf.Fpdf, translate func(string) string, event Event, venue Venue, card credentialCard, imageName string, pageWidth float64) {
+	const margin float64 = 20
+	const qrSize float64 = 80
+
+	pdf.SetXY(margin, margin)
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(pageWidth-2*margin, 10, translate(event.Title), "", 2, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.CellFormat(pageWidth-2*margin, 8, translate(venue.Name), "", 2, "C", false, 0, "")
+
+	pdf.SetXY(margin, margin+30)
+	renderCredentialField(pdf, translate, "Nama", card.Name, pageWidth-2*margin, 14)
+	renderCredentialField(pdf, translate, "Username", card.Username, pageWidth-2*margin, 14)
+	renderCredentialField(pdf, translate, "Password", card.Password, pageWidth-2*margin, 14)
+	renderCredentialField(pdf, translate, "Kunci", card.Key, pageWidth-2*margin, 14)
+
+	var imageOptions gofpdf.ImageOptions = gofpdf.ImageOptions{ImageType: "PNG"}
+	pdf.ImageOptions(imageName, (pageWidth-qrSize)/2, pdf.GetY()+10, qrSize, qrSize, false, imageOptions, 0, "")
+}
+
+// renderCredentialField renders a label and its value below the current position.
+// Empty value is printed as a blank line to be filled by hand.
+func renderCredentialField(pdf *gofpdf.Fpdf, translate func(string) string, label string, value string, width float64, fontSize float64) {
+	var x float64 = pdf.GetX()
+	var lineHeight float64 = fontSize * 0.5
+	if value == "" {
+		value = "____________________"
+	}
+	pdf.SetFont("Helvetica", "", fontSize*0.8)
+	pdf.CellFormat(width, lineHeight, translate(label), "", 2, "L", false, 0, "")
+	pdf.SetFont("Courier", "B", fontSize)
+	pdf.CellFormat(width, lineHeight+1, translate(value), "", 2, "L", false, 0, "")
+	pdf.SetX(x)
+}
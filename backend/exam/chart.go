@@ -0,0 +1,74 @@
+package exam
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// renderSeatingChart renders the seating chart as A4 PDF. Each room is printed
+// on its own page, followed by the participants that don't have any seat yet.
+func renderSeatingChart(event Event, chart SeatingChartData) ([]byte, error) {
+	const margin float64 = 15
+	var pdf *gofpdf.Fpdf = gofpdf.New("P", "mm", "A4", "")
+	var translate func(string) string = pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle(translate(fmt.Sprintf("%s - %s", event.Title, chart.Venue.Name)), false)
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(true, margin)
+
+	var pageWidth, _ float64 = pdf.GetPageSize()
+	var tableWidth float64 = pageWidth - 2*margin
+	for _, room := range chart.Rooms {
+		renderSeatingChartHeader(pdf, translate, event, chart.Venue, room.Room.Name, tableWidth)
+		renderSeatingChartTable(pdf, translate, room.Seats, tableWidth)
+	}
+	if len(chart.Unassigned) > 0 {
+		renderSeatingChartHeader(pdf, translate, event, chart.Venue, "Belum mendapat kursi", tableWidth)
+		renderSeatingChartTable(pdf, translate, chart.Unassigned, tableWidth)
+	}
+	if pdf.PageNo() == 0 {
+		pdf.AddPage()
+	}
+
+	var buffer bytes.Buffer
+	if errOutput := pdf.Output(&buffer); errOutput != nil {
+		return nil, errOutput
+	}
+	return buffer.Bytes(), nil
+}
+
+// renderSeatingChartHeader starts a new page with the event, venue and room name
+func renderSeatingChartHeader(pdf *gofpdf.Fpdf, translate func(string) string, event Event, venue VenueData, title string, width float64) {
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(width, 8, translate(event.Title), "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.CellFormat(width, 6, translate(venue.Name), "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(width, 10, translate(title), "", 1, "C", false, 0, "")
+}
+
+// renderSeatingChartTable renders the seats as a table. Seats without number
+// are printed with a dash, and empty seats are left blank.
+func renderSeatingChartTable(pdf *gofpdf.Fpdf, translate func(string) string, seats []SeatData, width float64) {
+	const numberWidth float64 = 20
+	const usernameWidth float64 = 55
+	const rowHeight float64 = 7
+	var nameWidth float64 = width - numberWidth - usernameWidth
+
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(numberWidth, rowHeight, translate("Kursi"), "1", 0, "C", false, 0, "")
+	pdf.CellFormat(usernameWidth, rowHeight, translate("Username"), "1", 0, "L", false, 0, "")
+	pdf.CellFormat(nameWidth, rowHeight, translate("Nama"), "1", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	for _, seat := range seats {
+		var number string = "-"
+		if seat.Number > 0 {
+			number = fmt.Sprintf("%d", seat.Number)
+		}
+		pdf.CellFormat(numberWidth, rowHeight, number, "1", 0, "C", false, 0, "")
+		pdf.CellFormat(usernameWidth, rowHeight, translate(seat.UserUsername), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(nameWidth, rowHeight, translate(seat.UserName), "1", 1, "L", false, 0, "")
+	}
+}
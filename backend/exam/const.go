@@ -4,12 +4,119 @@ import (
 	"math/big"
 	"net/http"
 
+	"github.com/yonasadiel/charon/backend/auth"
 	"github.com/yonasadiel/helios"
 )
 
+const (
+	// AttendancePresent is the attendance of participant that checked in before the event starts
+	AttendancePresent = "present"
+	// AttendanceLate is the attendance of participant that checked in after the event starts
+	AttendanceLate = "late"
+	// AttendanceNoShow is the attendance of participant that never checked in
+	AttendanceNoShow = "no_show"
+
+	// EventStateDraft is the state of event that is being prepared by the authors
+	EventStateDraft = "draft"
+	// EventStateReview is the state of event whose questions are being reviewed
+	EventStateReview = "review"
+	// EventStatePublished is the state of event whose questions are final and
+	// whose participants are being registered
+	EventStatePublished = "published"
+	// EventStateSynced is the state of event that has been synchronized to local
+	// servers. Questions and participants can't be changed anymore.
+	EventStateSynced = "synced"
+	// EventStateRunning is the state of event that is being held
+	EventStateRunning = "running"
+	// EventStateClosed is the state of event that has ended
+	EventStateClosed = "closed"
+	// EventStateGraded is the state of event whose submissions have been graded
+	EventStateGraded = "graded"
+	// EventStateArchived is the state of event that is kept only for records
+	EventStateArchived = "archived"
+
+	// CredentialCardLayoutSheet prints the credential card of a participant on each page
+	CredentialCardLayoutSheet = "sheet"
+	// CredentialCardLayoutCard prints the credential cards as cut-out cards on a grid
+	CredentialCardLayoutCard = "card"
+
+	// generatedPasswordLength is the length of password generated on participant import
+	generatedPasswordLength = 10
+	// participationKeyLength is the length of generated participation key
+	participationKeyLength = 32
+	// credentialCardColumns and credentialCardRows are the grid size of cut-out cards on a page
+	credentialCardColumns = 2
+	credentialCardRows    = 5
+
+	// actions and target types of the changes recorded on the audit log
+	auditActionVenueCreate            = "venue.create"
+	auditActionVenueUpdate            = "venue.update"
+	auditActionVenueDelete            = "venue.delete"
+	auditActionRoomCreate             = "room.create"
+	auditActionRoomUpdate             = "room.update"
+	auditActionRoomDelete             = "room.delete"
+	auditActionEventCreate            = "event.create"
+	auditActionEventUpdate            = "event.update"
+	auditActionEventClone             = "event.clone"
+	auditActionEventDelete            = "event.delete"
+	auditActionEventDecrypt           = "event.decrypt"
+	auditActionEventTransition        = "event.transition"
+	auditActionEventRoleAssign        = "event_role.assign"
+	auditActionEventRoleRevoke        = "event_role.revoke"
+	auditActionParticipationCreate    = "participation.create"
+	auditActionParticipationUpdate    = "participation.update"
+	auditActionParticipationDelete    = "participation.delete"
+	auditActionParticipationImport    = "participation.import"
+	auditActionParticipationVerify    = "participation.verify"
+	auditActionParticipationCheckIn   = "participation.check_in"
+	auditActionSeatAssign             = "seat.assign"
+	auditActionCredentialCardGenerate = "credential_card.generate"
+	auditActionAttendanceNoShow       = "attendance.no_show"
+	auditActionAttendanceSynchronize  = "attendance.synchronize"
+	auditActionQuestionCreate         = "question.create"
+	auditActionQuestionUpdate         = "question.update"
+	auditActionQuestionDelete         = "question.delete"
+	auditActionSubmissionSubmit       = "submission.submit"
+	auditActionSessionRemove          = "session.remove"
+	auditActionSynchronizationGet     = "synchronization.get"
+	auditActionSynchronizationPut     = "synchronization.put"
+	auditTargetVenue                  = "venue"
+	auditTargetRoom                   = "room"
+	auditTargetEvent                  = "event"
+	auditTargetEventRole              = "event_role"
+	auditTargetParticipation          = "participation"
+	auditTargetQuestion               = "question"
+	auditTargetSubmission             = "submission"
+	auditTargetSession                = "session"
+)
+
+// EventKeyBits is the size of RSA key generated for signing the SimKey of
+// each event
+var EventKeyBits int = 1024
+
 // PRIME is 12th Mersenne prime
 var PRIME *big.Int = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1)) // 2 ** 127 - 1
 
+// eventStateTransitions is the states that an event can be moved to from each
+// state, with the action required to move it. Synced is not listed because it
+// is only reached by synchronization.
+var eventStateTransitions = map[string]map[string]auth.Action{
+	EventStateDraft:     {EventStateReview: auth.ActionEventEdit},
+	EventStateReview:    {EventStateDraft: auth.ActionEventEdit, EventStatePublished: auth.ActionEventEdit},
+	EventStatePublished: {EventStateReview: auth.ActionEventEdit},
+	EventStateSynced:    {EventStateRunning: auth.ActionEventRun},
+	EventStateRunning:   {EventStateClosed: auth.ActionEventRun},
+	EventStateClosed:    {EventStateGraded: auth.ActionEventGrade},
+	EventStateGraded:    {EventStateArchived: auth.ActionEventEdit},
+}
+
+// states of event where the questions, the participants, and the
+// synchronization data can be changed, and where the answers are submitted
+var questionEditableStates = []string{EventStateDraft, EventStateReview}
+var participationEditableStates = []string{EventStateDraft, EventStateReview, EventStatePublished}
+var synchronizableStates = []string{EventStatePublished, EventStateSynced}
+var submittableStates = []string{EventStateRunning}
+
 var errVenueAccessNotAuthorized = helios.ErrorAPI{
 	StatusCode: http.StatusForbidden,
 	Code:       "venue_access_forbidden",
@@ -28,6 +135,54 @@ var errVenueCantDeletedEventExists = helios.ErrorAPI{
 	Message:    "The venue can't be deleted because there is event existed on the venue",
 }
 
+var errRoomNotFound = helios.ErrorAPI{
+	StatusCode: http.StatusNotFound,
+	Code:       "room_not_found",
+	Message:    "No room with given ID in the venue",
+}
+
+var errRoomCantDeletedParticipationExists = helios.ErrorAPI{
+	StatusCode: http.StatusBadRequest,
+	Code:       "room_cant_deleted_participation_exists",
+	Message:    "The room can't be deleted because there is participation seated in the room",
+}
+
+var errRoomCapacityTooSmall = helios.ErrorAPI{
+	StatusCode: http.StatusBadRequest,
+	Code:       "room_capacity_too_small",
+	Message:    "The room capacity is smaller than the seats that are already assigned",
+}
+
+var errRoomFull = helios.ErrorAPI{
+	StatusCode: http.StatusBadRequest,
+	Code:       "room_full",
+	Message:    "All seats in the room are already assigned",
+}
+
+var errSeatNotAvailable = helios.ErrorAPI{
+	StatusCode: http.StatusBadRequest,
+	Code:       "seat_not_available",
+	Message:    "The seat doesn't exist or is already assigned to other participant",
+}
+
+var errVenueCapacityExceeded = helios.ErrorAPI{
+	StatusCode: http.StatusBadRequest,
+	Code:       "venue_capacity_exceeded",
+	Message:    "There are not enough seats in the venue for all participants",
+}
+
+var errSeatAssignmentNotAuthorized = helios.ErrorAPI{
+	StatusCode: http.StatusForbidden,
+	Code:       "not_authorized_assign_seat",
+	Message:    "User is not authorized to assign seats on the venue",
+}
+
+var errCredentialCardNotAuthorized = helios.ErrorAPI{
+	StatusCode: http.StatusForbidden,
+	Code:       "not_authorized_generate_credential_card",
+	Message:    "User is not authorized to generate credential cards of the venue",
+}
+
 var errEventNotFound = helios.ErrorAPI{
 	StatusCode: http.StatusNotFound,
 	Code:       "event_not_found",
@@ -40,6 +195,54 @@ var errEventChangeNotAuthorized = helios.ErrorAPI{
 	Message:    "User is not authorized to make changes on event",
 }
 
+var errEventSlugTaken = helios.ErrorAPI{
+	StatusCode: http.StatusBadRequest,
+	Code:       "event_slug_taken",
+	Message:    "Event with given slug already exists",
+}
+
+var errEventLocked = helios.ErrorAPI{
+	StatusCode: http.StatusBadRequest,
+	Code:       "event_locked",
+	Message:    "The event has been synchronized or decrypted, only admin can force the change",
+}
+
+var errEventStateInvalid = helios.ErrorAPI{
+	StatusCode: http.StatusBadRequest,
+	Code:       "event_state_invalid",
+	Message:    "The operation is not allowed on the current state of the event",
+}
+
+var errEventTransitionInvalid = helios.ErrorAPI{
+	StatusCode: http.StatusBadRequest,
+	Code:       "event_transition_invalid",
+	Message:    "The event can't be moved from the current state to the given state",
+}
+
+var errEventTransitionNotAuthorized = helios.ErrorAPI{
+	StatusCode: http.StatusForbidden,
+	Code:       "not_authorized_transition_event",
+	Message:    "User is not authorized to move the event to the given state",
+}
+
+var errEventRoleManageNotAuthorized = helios.ErrorAPI{
+	StatusCode: http.StatusForbidden,
+	Code:       "not_authorized_manage_event_role",
+	Message:    "User is not authorized to manage roles on event",
+}
+
+var errEventRoleNotFound = helios.ErrorAPI{
+	StatusCode: http.StatusNotFound,
+	Code:       "event_role_not_found",
+	Message:    "No role assignment with given ID on the event",
+}
+
+var errEventRoleNotAssignable = helios.ErrorAPI{
+	StatusCode: http.StatusBadRequest,
+	Code:       "event_role_not_assignable",
+	Message:    "Participant can't be assigned to a role on event",
+}
+
 var errEventIsNotYetStarted = helios.ErrorAPI{
 	StatusCode: http.StatusForbidden,
 	Code:       "event_is_not_yet_started",
@@ -69,6 +272,30 @@ var errParticipationWrongKey = helios.ErrorAPI{
 	Message:    "Wrong participation key",
 }
 
+var errParticipationNotCheckedIn = helios.ErrorAPI{
+	StatusCode: http.StatusForbidden,
+	Code:       "participation_not_checked_in",
+	Message:    "You have to be checked in by the proctor first",
+}
+
+var errParticipationAlreadyCheckedIn = helios.ErrorAPI{
+	StatusCode: http.StatusBadRequest,
+	Code:       "participation_already_checked_in",
+	Message:    "Participant has already been checked in",
+}
+
+var errCheckInNotAuthorized = helios.ErrorAPI{
+	StatusCode: http.StatusForbidden,
+	Code:       "not_authorized_check_in",
+	Message:    "User is not authorized to check in participant",
+}
+
+var errAttendanceAccessNotAuthorized = helios.ErrorAPI{
+	StatusCode: http.StatusForbidden,
+	Code:       "attendance_forbidden",
+	Message:    "User role doesn't have permission to access attendance",
+}
+
 var errParticipationStatusAccessNotAuthorized = helios.ErrorAPI{
 	StatusCode: http.StatusForbidden,
 	Code:       "participation_status_forbidden",
@@ -0,0 +1,94 @@
+package exam
+
+import (
+	"time"
+
+	"github.com/yonasadiel/helios"
+
+	"github.com/yonasadiel/charon/backend/auth"
+	"github.com/yonasadiel/charon/backend/logging"
+	"github.com/yonasadiel/charon/backend/metrics"
+)
+
+const (
+	syncOperationGet = "get"
+	syncOperationPut = "put"
+
+	decryptionResultSuccess = "success"
+	decryptionResultFailure = "failure"
+)
+
+// activeSessionStates are the states of events whose participants are
+// counted in the active sessions metric
+var activeSessionStates = []string{EventStateSynced, EventStateRunning}
+
+var submissionCounter = metrics.NewCounterVec(
+	"charon_submissions_total",
+	"Number of answers submitted by the participants, by event.",
+	"event",
+)
+
+var syncOperationCounter = metrics.NewCounterVec(
+	"charon_sync_operations_total",
+	"Number of successful event synchronizations, by event and operation (get or put).",
+	"event", "operation",
+)
+
+var decryptionCounter = metrics.NewCounterVec(
+	"charon_decryptions_total",
+	"Number of event decryption attempts, by event and result (success or failure).",
+	"event", "result",
+)
+
+func init() {
+	metrics.NewGaugeFunc(
+		"charon_active_sessions",
+		"Number of unexpired sessions of the participants of synced or running events, by event.",
+		[]string{"event"},
+		countActiveSessions,
+	)
+}
+
+// countActiveSessions counts the logged in sessions that are not expired by
+// auth.SessionMaxAge or auth.SessionIdleTimeout of the participants of each
+// synced or running event
+func countActiveSessions() []metrics.Sample {
+	if helios.DB == nil {
+		return nil
+	}
+	var now time.Time = time.Now()
+	var query = helios.DB.
+		Table("sessions").
+		Select("events.slug as event_slug, count(distinct sessions.id) as session_count").
+		Joins("inner join participations on (participations.user_id = sessions.user_id and participations.deleted_at is null)").
+		Joins("inner join events on (events.id = participations.event_id and events.deleted_at is null)").
+		Joins("inner join users on (users.id = sessions.user_id and users.deleted_at is null)").
+		Where("sessions.deleted_at is null").
+		Where("sessions.two_factor_pending = ?", false).
+		Where("users.role = ?", auth.UserRoleParticipant).
+		Where("events.state in (?)", activeSessionStates).
+		Group("events.slug")
+	if auth.SessionMaxAge > 0 {
+		query = query.Where("sessions.created_at >= ?", now.Add(-auth.SessionMaxAge))
+	}
+	if auth.SessionIdleTimeout > 0 {
+		query = query.Where("sessions.last_seen_at >= ?", now.Add(-auth.SessionIdleTimeout))
+	}
+	rows, err := query.Rows()
+	if err != nil {
+		logging.Error("failed to count active sessions", logging.Fields{"error": err})
+		return nil
+	}
+	defer rows.Close()
+	var samples []metrics.Sample
+	for rows.Next() {
+		var eventSlug string
+		var sessionCount int
+		if err = rows.Scan(&eventSlug, &sessionCount); err != nil {
+			logging.Error("failed to count active sessions", logging.Fields{"error": err})
+			return nil
+		}
+		samples = append(samples, metrics.Sample{LabelValues: []string{eventSlug}, Value: float64(sessionCount)})
+	}
+	return samples
+}
@@ -0,0 +1,38 @@
+package exam
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yonasadiel/helios"
+
+	"github.com/yonasadiel/charon/backend/auth"
+	"github.com/yonasadiel/charon/backend/metrics"
+)
+
+func TestCountActiveSessions(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var eventRunning Event = EventFactorySaved(Event{State: EventStateRunning})
+	var eventDraft Event = EventFactorySaved(Event{State: EventStateDraft})
+	var participant1 auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
+	var participant2 auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
+	var participant3 auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
+	var proctor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	ParticipationFactorySaved(Participation{User: &participant1, Event: &eventRunning})
+	ParticipationFactorySaved(Participation{User: &participant2, Event: &eventRunning})
+	ParticipationFactorySaved(Participation{User: &participant3, Event: &eventRunning})
+	ParticipationFactorySaved(Participation{User: &participant1, Event: &eventDraft})
+	ParticipationFactorySaved(Participation{User: &proctor, Event: &eventRunning})
+
+	var now time.Time = time.Now()
+	helios.DB.Create(&auth.Session{UserID: participant1.ID, Token: "session-1", LastSeenAt: now})
+	helios.DB.Create(&auth.Session{UserID: participant1.ID, Token: "session-2", LastSeenAt: now})
+	helios.DB.Create(&auth.Session{UserID: participant2.ID, Token: "session-3", LastSeenAt: now.Add(-auth.SessionIdleTimeout - time.Minute)})
+	helios.DB.Create(&auth.Session{UserID: participant3.ID, Token: "session-4", LastSeenAt: now, TwoFactorPending: true})
+	helios.DB.Create(&auth.Session{UserID: proctor.ID, Token: "session-5", LastSeenAt: now})
+
+	var samples []metrics.Sample = countActiveSessions()
+	assert.Equal(t, []metrics.Sample{{LabelValues: []string{eventRunning.Slug}, Value: 2}}, samples)
+}
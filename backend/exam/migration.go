@@ -0,0 +1,272 @@
+package exam
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/yonasadiel/charon/backend/auth"
+	"github.com/yonasadiel/charon/backend/migration"
+)
+
+// Migrations is the schema migrations of exam models
+var Migrations = []migration.Migration{
+	{
+		Version: 2026101902,
+		Name:    "create exam tables",
+		Up: func(db *gorm.DB) error {
+			return migration.CreateTables(db, initialTables)
+		},
+		Down: func(db *gorm.DB) error {
+			return migration.DropTables(db, initialTables)
+		},
+	},
+	{
+		Version: 2026101905,
+		Name:    "assign event roles of existing events",
+		Up:      assignExistingEventRoles,
+		// the assignments are kept, because they can't be told apart from
+		// the ones that are made after the migration
+		Down: func(db *gorm.DB) error { return nil },
+	},
+	{
+		Version: 2026101908,
+		Name:    "set state of existing events",
+		Up: func(db *gorm.DB) error {
+			return backfillEventStates(db, time.Now())
+		},
+		// the states are kept, they can't be told apart from the transitions
+		// that are made after the migration
+		Down: func(db *gorm.DB) error { return nil },
+	},
+	{
+		Version: 2026101910,
+		Name:    "add unique index on participation seats",
+		Up: func(db *gorm.DB) error {
+			if err := releaseConflictingSeats(db); err != nil {
+				return err
+			}
+			if db.Dialect().HasIndex("participations", "uix_participations_seat") {
+				return nil
+			}
+			return db.Table("participations").AddUniqueIndex("uix_participations_seat", "event_id", "room_id", "seat_number").Error
+		},
+		Down: func(db *gorm.DB) error {
+			err := db.Table("participations").RemoveIndex("uix_participations_seat").Error
+			if err != nil {
+				return err
+			}
+			return db.Exec("UPDATE participations SET room_id = 0 WHERE room_id IS NULL").Error
+		},
+	},
+	{
+		Version: 2026101911,
+		Name:    "free slugs of deleted events",
+		Up:      renameDeletedEventSlugs,
+		// the slugs may have been taken by new events
+		Down: func(db *gorm.DB) error { return nil },
+	},
+}
+
+// initialTables is the exam tables created by the first migration. The tables
+// are kept apart from the models, so the migration doesn't change with them.
+var initialTables = []migration.Table{
+	{Name: "events", Model: &struct {
+		ID                  uint   `gorm:"primary_key"`
+		Slug                string `gorm:"size:100;unique"`
+		Title               string `gorm:"size:256"`
+		Description         string `gorm:"type:text"`
+		SimKey              string `gorm:"size:48"`
+		SimKeySign          string `gorm:"type:text"`
+		PrvKey              string `gorm:"type:text"`
+		PubKey              string `gorm:"type:text"`
+		DecryptedAt         time.Time
+		LastSynchronization time.Time
+		StartsAt            time.Time
+		EndsAt              time.Time
+		State               string `gorm:"size:16;default:'draft'"`
+		CreatedAt           time.Time
+		UpdatedAt           time.Time
+		DeletedAt           *time.Time
+	}{}},
+	{Name: "venues", Model: &struct {
+		ID        uint `gorm:"primary_key"`
+		Name      string
+		CreatedAt time.Time
+		UpdatedAt time.Time
+		DeletedAt *time.Time
+	}{}},
+	{Name: "rooms", Model: &struct {
+		ID        uint `gorm:"primary_key"`
+		VenueID   uint
+		Name      string `gorm:"size:256"`
+		Capacity  uint
+		CreatedAt time.Time
+		UpdatedAt time.Time
+		DeletedAt *time.Time
+	}{}},
+	{Name: "participations", Model: &struct {
+		ID             uint `gorm:"primary_key"`
+		EventID        uint
+		UserID         uint
+		VenueID        uint
+		RoomID         uint
+		SeatNumber     uint
+		KeyPlain       string
+		KeyHashedOnce  string
+		KeyHashedTwice string
+		SecretShareY   string
+		Attendance     string `gorm:"size:16"`
+		CheckedInAt    time.Time
+		IDVerified     bool
+		CreatedAt      time.Time
+		UpdatedAt      time.Time
+		DeletedAt      *time.Time
+	}{}},
+	{Name: "questions", Model: &struct {
+		ID        uint   `gorm:"primary_key"`
+		Content   string `gorm:"type:text"`
+		EventID   uint
+		Choices   string
+		CreatedAt time.Time
+		UpdatedAt time.Time
+		DeletedAt *time.Time
+	}{}},
+	{Name: "user_questions", Model: &struct {
+		ID              uint `gorm:"primary_key"`
+		ParticipationID uint
+		QuestionID      uint
+		Ordering        uint
+		Answer          string `gorm:"type:text"`
+		CreatedAt       time.Time
+		UpdatedAt       time.Time
+		DeletedAt       *time.Time
+	}{}},
+	{Name: "secret_shares", Model: &struct {
+		ID            uint
+		EventID       uint
+		VenueID       uint
+		PolynomCoeffs string `gorm:"type:text"`
+		CreatedAt     time.Time
+		UpdatedAt     time.Time
+		DeletedAt     *time.Time
+	}{}},
+}
+
+// eventStateBackfill is the columns of the events read by backfillEventStates,
+// kept apart from Event so the migration doesn't change with the model
+type eventStateBackfill struct {
+	ID                  uint
+	State               string
+	DecryptedAt         time.Time
+	LastSynchronization time.Time
+	EndsAt              time.Time
+}
+
+// backfillEventStates sets the state of the events that are created before
+// the event states, which are all draft by the column default. The state is
+// derived from the synchronization, the decryption, and the end of the event.
+// The event that has been held is closed rather than graded, because the
+// grading was not recorded, the graders move it to graded.
+func backfillEventStates(db *gorm.DB, now time.Time) error {
+	if !db.Dialect().HasColumn("events", "state") {
+		err := db.Exec("ALTER TABLE events ADD COLUMN state varchar(16) DEFAULT 'draft'").Error
+		if err != nil {
+			return err
+		}
+	}
+	var events []eventStateBackfill
+	err := db.Table("events").
+		Select("id, state, decrypted_at, last_synchronization, ends_at").
+		Where("state = ? OR state IS NULL OR state = ''", EventStateDraft).
+		Scan(&events).Error
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		var state string = EventStateDraft
+		if !event.DecryptedAt.IsZero() || !event.LastSynchronization.IsZero() {
+			state = EventStateSynced
+			if !event.EndsAt.IsZero() && event.EndsAt.Before(now) {
+				state = EventStateClosed
+			} else if !event.DecryptedAt.IsZero() {
+				state = EventStateRunning
+			}
+		}
+		if err = db.Table("events").Where("id = ?", event.ID).UpdateColumn("state", state).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseConflictingSeats prepares the participations for the seat index. The
+// room of unassigned seats becomes null, so they don't conflict on the index,
+// and the seats of deleted participations are released. The seats that are
+// assigned more than once are kept by the first participation only, the
+// others have to be assigned again.
+func releaseConflictingSeats(db *gorm.DB) error {
+	err := db.Exec("UPDATE participations SET room_id = NULL, seat_number = 0 WHERE room_id = 0 OR deleted_at IS NOT NULL").Error
+	if err != nil {
+		return err
+	}
+	// the duplicated seats are selected through a derived table, because mysql
+	// can't select from the table that is updated
+	return db.Exec(`UPDATE participations SET room_id = NULL, seat_number = 0 WHERE id IN (
+		SELECT id FROM (SELECT participations.id FROM participations INNER JOIN participations AS other
+			ON other.event_id = participations.event_id AND other.room_id = participations.room_id
+			AND other.seat_number = participations.seat_number AND other.id < participations.id) AS duplicated)`).Error
+}
+
+// renameDeletedEventSlugs renames the slugs of the events that are deleted
+// before DeleteEvent renames them, so new events can take the slugs
+func renameDeletedEventSlugs(db *gorm.DB) error {
+	var events []Event
+	err := db.Unscoped().Table("events").
+		Select("id, slug").
+		Where("deleted_at IS NOT NULL").
+		Where("slug NOT LIKE ?", "%~deleted-%").
+		Scan(&events).Error
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		if err = db.Table("events").Where("id = ?", event.ID).UpdateColumn("slug", deletedEventSlug(event)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assignExistingEventRoles assigns the roles of the events that are created
+// before the per-event permissions. Events don't record their creator, and
+// every organizer could edit every event, so all organizers become the author
+// of all events. Local users become the proctor and venue manager of the
+// venue they participate on, like assignLocalEventRoles does.
+func assignExistingEventRoles(db *gorm.DB) error {
+	var now time.Time = time.Now()
+	const notAssigned = `NOT EXISTS (SELECT 1 FROM event_roles
+		WHERE event_roles.user_id = users.id AND event_roles.event_id = %s AND event_roles.venue_id = %s
+		AND event_roles.role = ? AND event_roles.deleted_at IS NULL)`
+	err := db.Exec(`INSERT INTO event_roles (user_id, event_id, venue_id, role, created_at, updated_at)
+		SELECT users.id, events.id, 0, ?, ?, ? FROM users CROSS JOIN events
+		WHERE users.role = ? AND users.deleted_at IS NULL AND events.deleted_at IS NULL AND `+
+		fmt.Sprintf(notAssigned, "events.id", "0"),
+		auth.EventRoleAuthor, now, now, auth.UserRoleOrganizer, auth.EventRoleAuthor).Error
+	if err != nil {
+		return err
+	}
+	for _, role := range []string{auth.EventRoleProctor, auth.EventRoleVenueManager} {
+		err = db.Exec(`INSERT INTO event_roles (user_id, event_id, venue_id, role, created_at, updated_at)
+			SELECT users.id, participations.event_id, participations.venue_id, ?, ?, ?
+			FROM participations INNER JOIN users ON participations.user_id = users.id
+			WHERE users.role = ? AND users.deleted_at IS NULL AND participations.deleted_at IS NULL AND `+
+			fmt.Sprintf(notAssigned, "participations.event_id", "participations.venue_id"),
+			role, now, now, auth.UserRoleLocal, role).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
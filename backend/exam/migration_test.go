@@ -0,0 +1,152 @@
+package exam
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yonasadiel/charon/backend/auth"
+	"github.com/yonasadiel/helios"
+)
+
+func TestAssignExistingEventRoles(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var event1 Event = EventFactorySaved(Event{})
+	var event2 Event = EventFactorySaved(Event{})
+	var venue1 Venue = VenueFactorySaved(Venue{})
+	var userOrganizer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	EventRoleFactorySaved(userOrganizer, event1, nil, auth.EventRoleAuthor)
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &userLocal})
+	var participation Participation = ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1})
+
+	type eventRoleCount struct {
+		user    auth.User
+		role    string
+		venueID uint
+		count   int
+	}
+	var expectedCounts []eventRoleCount = []eventRoleCount{
+		{user: userOrganizer, role: auth.EventRoleAuthor, venueID: 0, count: 2},
+		{user: userLocal, role: auth.EventRoleProctor, venueID: venue1.ID, count: 1},
+		{user: userLocal, role: auth.EventRoleVenueManager, venueID: venue1.ID, count: 1},
+		{user: userLocal, role: auth.EventRoleAuthor, venueID: 0, count: 0},
+		{user: *participation.User, role: auth.EventRoleProctor, venueID: venue1.ID, count: 0},
+	}
+	for i := 0; i < 2; i++ {
+		t.Logf("Test AssignExistingEventRoles run: %d", i)
+		assert.Nil(t, assignExistingEventRoles(helios.DB))
+		for _, expected := range expectedCounts {
+			var count int
+			helios.DB.Model(auth.EventRole{}).
+				Where("user_id = ?", expected.user.ID).
+				Where("role = ?", expected.role).
+				Where("venue_id = ?", expected.venueID).
+				Count(&count)
+			assert.Equal(t, expected.count, count, "%s of user %d", expected.role, expected.user.ID)
+		}
+		assert.True(t, auth.Can(userOrganizer, auth.ActionEventEdit, auth.Resource{EventID: event2.ID}))
+	}
+}
+
+func TestBackfillEventStates(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var now time.Time = time.Now()
+	type backfillEventStatesTestCase struct {
+		event         Event
+		expectedState string
+	}
+	testCases := []backfillEventStatesTestCase{{
+		event:         Event{EndsAt: now.Add(-time.Hour)},
+		expectedState: EventStateDraft,
+	}, {
+		event:         Event{LastSynchronization: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)},
+		expectedState: EventStateSynced,
+	}, {
+		event:         Event{DecryptedAt: now.Add(-time.Hour), LastSynchronization: now.Add(-2 * time.Hour), EndsAt: now.Add(time.Hour)},
+		expectedState: EventStateRunning,
+	}, {
+		event:         Event{DecryptedAt: now.Add(-3 * time.Hour), LastSynchronization: now.Add(-4 * time.Hour), EndsAt: now.Add(-time.Hour)},
+		expectedState: EventStateClosed,
+	}, {
+		event:         Event{State: EventStateArchived, DecryptedAt: now.Add(-3 * time.Hour), EndsAt: now.Add(-time.Hour)},
+		expectedState: EventStateArchived,
+	}}
+	for i := range testCases {
+		// the factory fills the zero times, so they are set after saved
+		var decryptedAt, lastSynchronization time.Time = testCases[i].event.DecryptedAt, testCases[i].event.LastSynchronization
+		testCases[i].event = EventFactorySaved(testCases[i].event)
+		helios.DB.Model(&testCases[i].event).UpdateColumns(map[string]interface{}{
+			"decrypted_at":         decryptedAt,
+			"last_synchronization": lastSynchronization,
+		})
+	}
+	assert.Nil(t, backfillEventStates(helios.DB, now))
+	for i, testCase := range testCases {
+		t.Logf("Test BackfillEventStates testcase: %d", i)
+		var eventSaved Event
+		helios.DB.Where("id = ?", testCase.event.ID).First(&eventSaved)
+		assert.Equal(t, testCase.expectedState, eventSaved.State)
+	}
+}
+
+func TestReleaseConflictingSeats(t *testing.T) {
+	helios.App.BeforeTest()
+	// the seats are saved as they were before the seat index
+	assert.Nil(t, helios.DB.Table("participations").RemoveIndex("uix_participations_seat").Error)
+	defer helios.App.Migrate()
+
+	var event Event = EventFactorySaved(Event{})
+	var room Room = RoomFactorySaved(Room{})
+	var participations []Participation = []Participation{
+		ParticipationFactorySaved(Participation{Event: &event, RoomID: &room.ID, SeatNumber: 1}),
+		ParticipationFactorySaved(Participation{Event: &event, RoomID: &room.ID, SeatNumber: 1}),
+		ParticipationFactorySaved(Participation{Event: &event, RoomID: &room.ID, SeatNumber: 2}),
+		ParticipationFactorySaved(Participation{Event: &event, RoomID: &room.ID, SeatNumber: 3}),
+		ParticipationFactorySaved(Participation{Event: &event}),
+	}
+	helios.DB.Model(&participations[3]).UpdateColumn("deleted_at", time.Now())
+	helios.DB.Model(&participations[4]).UpdateColumn("room_id", 0)
+
+	assert.Nil(t, releaseConflictingSeats(helios.DB))
+	var expectedRoomIDs []uint = []uint{room.ID, 0, room.ID, 0, 0}
+	var expectedSeats []uint = []uint{1, 0, 2, 0, 0}
+	for i, participation := range participations {
+		t.Logf("Test ReleaseConflictingSeats participation: %d", i)
+		var participationSaved Participation
+		helios.DB.Unscoped().Where("id = ?", participation.ID).First(&participationSaved)
+		assert.Equal(t, expectedRoomIDs[i], participationSaved.GetRoomID())
+		assert.Equal(t, expectedSeats[i], participationSaved.SeatNumber)
+	}
+	var unassigned int
+	helios.DB.Unscoped().Model(&Participation{}).Where("room_id = 0").Count(&unassigned)
+	assert.Equal(t, 0, unassigned, "Unassigned seat should have no room")
+}
+
+func TestRenameDeletedEventSlugs(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var event Event = EventFactorySaved(Event{Slug: "final-exam"})
+	var eventDeleted Event = EventFactorySaved(Event{Slug: "midterm-exam"})
+	var eventRenamed Event = EventFactorySaved(Event{})
+	helios.DB.Model(&eventDeleted).UpdateColumn("deleted_at", time.Now())
+	var renamedSlug string = deletedEventSlug(eventRenamed)
+	helios.DB.Model(&eventRenamed).UpdateColumns(map[string]interface{}{"slug": renamedSlug, "deleted_at": time.Now()})
+
+	for i := 0; i < 2; i++ {
+		t.Logf("Test RenameDeletedEventSlugs run: %d", i)
+		assert.Nil(t, renameDeletedEventSlugs(helios.DB))
+		var expectedSlugs map[uint]string = map[uint]string{
+			event.ID:        "final-exam",
+			eventDeleted.ID: deletedEventSlug(eventDeleted),
+			eventRenamed.ID: renamedSlug,
+		}
+		for id, expectedSlug := range expectedSlugs {
+			var eventSaved Event
+			helios.DB.Unscoped().Where("id = ?", id).First(&eventSaved)
+			assert.Equal(t, expectedSlug, eventSaved.Slug)
+		}
+	}
+}
@@ -15,13 +15,14 @@ type Event struct {
 	Title               string `gorm:"size:256"`
 	Description         string `gorm:"type:text"`
 	SimKey              string `gorm:"size:48"`
-	SimKeySign          string `gorm:"size:1024"`
-	PrvKey              string `gorm:"size:1024"`
-	PubKey              string `gorm:"size:1024"`
+	SimKeySign          string `gorm:"type:text"`
+	PrvKey              string `gorm:"type:text"`
+	PubKey              string `gorm:"type:text"`
 	DecryptedAt         time.Time
 	LastSynchronization time.Time
 	StartsAt            time.Time
 	EndsAt              time.Time
+	State               string `gorm:"size:16;default:'draft'"`
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -38,21 +39,45 @@ type Venue struct {
 	DeletedAt *time.Time
 }
 
+// Room is a room inside a venue. Capacity is the number of seats
+// in the room, numbered from 1 to Capacity.
+type Room struct {
+	ID       uint `gorm:"primary_key"`
+	VenueID  uint
+	Name     string `gorm:"size:256"`
+	Capacity uint
+
+	Venue *Venue `gorm:"foreignkey:VenueID;association_autoupdate:false"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
 // Participation is many to many indicating an user is participating
-// in a local event.
+// in a local event. RoomID is nil and SeatNumber is zero if the seat is not
+// assigned yet, so the unassigned participations don't conflict on the seat
+// index. The seat of deleted participation is released. Attendance is empty
+// until the participant is checked in by the proctor or recorded as no-show.
 type Participation struct {
 	ID             uint `gorm:"primary_key"`
-	EventID        uint
+	EventID        uint `gorm:"unique_index:uix_participations_seat"`
 	UserID         uint
 	VenueID        uint
+	RoomID         *uint `gorm:"unique_index:uix_participations_seat"`
+	SeatNumber     uint  `gorm:"unique_index:uix_participations_seat"`
 	KeyPlain       string
 	KeyHashedOnce  string
 	KeyHashedTwice string
 	SecretShareY   string
+	Attendance     string `gorm:"size:16"`
+	CheckedInAt    time.Time
+	IDVerified     bool
 
 	Event *Event     `gorm:"foreignkey:EventID;association_autoupdate:false"`
 	User  *auth.User `gorm:"foreignkey:UserID;association_autoupdate:false"`
 	Venue *Venue     `gorm:"foreignkey:VenueID;association_autoupdate:false"`
+	Room  *Room      `gorm:"foreignkey:RoomID;association_autoupdate:false"`
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -111,8 +136,29 @@ type SecretShare struct {
 func init() {
 	helios.App.RegisterModel(Event{})
 	helios.App.RegisterModel(Venue{})
+	helios.App.RegisterModel(Room{})
 	helios.App.RegisterModel(Participation{})
 	helios.App.RegisterModel(Question{})
 	helios.App.RegisterModel(UserQuestion{})
 	helios.App.RegisterModel(SecretShare{})
 }
+
+// GetRoomID returns the ID of the room of the seat, zero if the seat is not assigned
+func (participation *Participation) GetRoomID() uint {
+	if participation.RoomID == nil {
+		return 0
+	}
+	return *participation.RoomID
+}
+
+// SetSeat assigns the seat on the room to the participation. The seat is
+// unassigned if the room ID is zero.
+func (participation *Participation) SetSeat(roomID uint, seatNumber uint) {
+	if roomID == 0 {
+		participation.RoomID = nil
+		participation.SeatNumber = 0
+		return
+	}
+	participation.RoomID = &roomID
+	participation.SeatNumber = seatNumber
+}
@@ -0,0 +1,59 @@
+package exam
+
+import (
+	"net/http"
+
+	"github.com/yonasadiel/charon/backend/openapi"
+)
+
+var loggedInSecurity = []string{openapi.SecuritySession}
+
+// Operations describes the views of exam for the OpenAPI document
+var Operations = openapi.Operations{
+	"GET /exam/venue/":                            {ID: "VenueList", Summary: "List the venues", Security: loggedInSecurity, Response: []VenueData{}},
+	"POST /exam/venue/":                           {ID: "VenueCreate", Summary: "Create a venue", Security: loggedInSecurity, Request: VenueData{}, StatusCode: http.StatusCreated, Response: VenueData{}},
+	"DELETE /exam/venue/{venueID}/":               {ID: "VenueDelete", Summary: "Delete the venue", Security: loggedInSecurity, Response: VenueData{}},
+	"GET /exam/venue/{venueID}/room/":             {ID: "RoomList", Summary: "List the rooms of the venue", Security: loggedInSecurity, Response: []RoomData{}},
+	"POST /exam/venue/{venueID}/room/":            {ID: "RoomCreate", Summary: "Create a room on the venue", Security: loggedInSecurity, Request: RoomData{}, StatusCode: http.StatusCreated, Response: RoomData{}},
+	"PUT /exam/venue/{venueID}/room/{roomID}/":    {ID: "RoomUpdate", Summary: "Update the room of the venue", Security: loggedInSecurity, Request: RoomData{}, Response: RoomData{}},
+	"DELETE /exam/venue/{venueID}/room/{roomID}/": {ID: "RoomDelete", Summary: "Delete the room of the venue", Security: loggedInSecurity, Response: RoomData{}},
+
+	"GET /exam/":                                   {ID: "EventList", Summary: "List the events of the user", Security: loggedInSecurity, Response: []EventData{}},
+	"POST /exam/":                                  {ID: "EventCreate", Summary: "Create an event", Security: loggedInSecurity, Request: EventData{}, StatusCode: http.StatusCreated, Response: EventData{}},
+	"GET /exam/{eventSlug}/":                       {ID: "EventDetail", Summary: "Get the event", Security: loggedInSecurity, Response: EventData{}},
+	"PUT /exam/{eventSlug}/":                       {ID: "EventUpdate", Summary: "Update the event, force is required after it is synchronized or decrypted", Security: loggedInSecurity, Request: EventUpdateRequest{}, Response: EventData{}},
+	"DELETE /exam/{eventSlug}/":                    {ID: "EventDelete", Summary: "Delete the event, force is required after it is synchronized or decrypted", Security: loggedInSecurity, Request: EventDeleteRequest{}, RequestOptional: true, Response: EventData{}},
+	"POST /exam/{eventSlug}/clone/":                {ID: "EventClone", Summary: "Clone the event with a new slug and time", Security: loggedInSecurity, Request: EventCloneRequest{}, StatusCode: http.StatusCreated, Response: EventData{}},
+	"POST /exam/{eventSlug}/transition/":           {ID: "EventTransition", Summary: "Move the event to the state", Security: loggedInSecurity, Request: EventTransitionRequest{}, Response: EventData{}},
+	"POST /exam/{eventSlug}/decrypt/":              {ID: "EventDecrypt", Summary: "Decrypt the questions of the event with its key", Security: loggedInSecurity, Request: DecryptRequest{}, Response: openapi.OK},
+	"GET /exam/{eventSlug}/role/":                  {ID: "EventRoleList", Summary: "List the role assignments on the event", Security: loggedInSecurity, Response: []EventRoleData{}},
+	"POST /exam/{eventSlug}/role/":                 {ID: "EventRoleCreate", Summary: "Assign the user to a role on the event", Security: loggedInSecurity, Request: EventRoleData{}, StatusCode: http.StatusCreated, Response: EventRoleData{}},
+	"DELETE /exam/{eventSlug}/role/{eventRoleID}/": {ID: "EventRoleDelete", Summary: "Revoke the role assignment", Security: loggedInSecurity, Response: EventRoleData{}},
+
+	"GET /exam/{eventSlug}/participation/":                       {ID: "ParticipationList", Summary: "List the participations of the event", Security: loggedInSecurity, Response: []ParticipationData{}},
+	"POST /exam/{eventSlug}/participation/":                      {ID: "ParticipationCreate", Summary: "Add a participant to the event", Security: loggedInSecurity, Request: ParticipationData{}, Response: ParticipationData{}},
+	"POST /exam/{eventSlug}/participation/import/":               {ID: "ParticipantImport", Summary: "Create the participants of the event from CSV", Security: loggedInSecurity, Request: ParticipantImportRequest{}, StatusCode: http.StatusCreated, Response: []ParticipantImportData{}},
+	"DELETE /exam/{eventSlug}/participation/{participationID}/":  {ID: "ParticipationDelete", Summary: "Delete the participation", Security: loggedInSecurity, Response: ParticipationData{}},
+	"POST /exam/{eventSlug}/verify/":                             {ID: "ParticipationVerify", Summary: "Verify the participation key of the participant", Security: loggedInSecurity, Request: VerificationData{}, Response: openapi.OK},
+	"GET /exam/{eventSlug}/participation-status/":                {ID: "ParticipationStatusList", Summary: "List the login status of the participants", Security: loggedInSecurity, Response: []ParticipationStatus{}},
+	"DELETE /exam/{eventSlug}/participation-status/{sessionID}/": {ID: "ParticipationStatusDelete", Summary: "Log the participant out of the session", Security: loggedInSecurity, Response: openapi.OK},
+
+	"GET /exam/{eventSlug}/venue/{venueID}/seat/":         {ID: "SeatingChart", Summary: "Get the seating chart of the venue", Security: loggedInSecurity, Response: SeatingChartData{}},
+	"POST /exam/{eventSlug}/venue/{venueID}/seat/":        {ID: "SeatAssign", Summary: "Assign the seats of the venue automatically", Security: loggedInSecurity, Response: SeatingChartData{}},
+	"GET /exam/{eventSlug}/venue/{venueID}/seat/print/":   {ID: "SeatingChartPrint", Summary: "Render the seating chart of the venue as base64 PDF", Security: loggedInSecurity, Response: SeatingChartPrintData{}},
+	"POST /exam/{eventSlug}/venue/{venueID}/seat/import/": {ID: "SeatImport", Summary: "Assign the seats of the venue from CSV", Security: loggedInSecurity, Request: SeatImportRequest{}, Response: SeatingChartData{}},
+	"POST /exam/{eventSlug}/venue/{venueID}/card/":        {ID: "CredentialCard", Summary: "Generate the credential cards of the venue as base64 PDF", Security: loggedInSecurity, Request: CredentialCardRequest{}, Response: CredentialCardData{}},
+
+	"POST /exam/{eventSlug}/check-in/":           {ID: "CheckIn", Summary: "Check in the participant on the venue", Security: loggedInSecurity, Request: CheckInRequest{}, Response: AttendanceData{}},
+	"POST /exam/{eventSlug}/attendance/no-show/": {ID: "AttendanceNoShow", Summary: "Record the participants that aren't checked in as no-show", Security: loggedInSecurity, Response: openapi.OK},
+	"GET /exam/{eventSlug}/attendance/":          {ID: "AttendanceGet", Summary: "Get the attendance of the venue", Security: loggedInSecurity, Response: AttendanceSynchronizationData{}},
+	"POST /exam/{eventSlug}/attendance/":         {ID: "AttendancePut", Summary: "Save the attendance sent by the local server", Security: []string{openapi.SecuritySession, openapi.SecurityAPIToken}, Request: AttendanceSynchronizationData{}, Response: openapi.OK},
+	"GET /exam/{eventSlug}/sync/":                {ID: "SynchronizationGet", Summary: "Get the synchronization data of the event, the central server accepts the API token", Security: []string{openapi.SecuritySession, openapi.SecurityAPIToken}, Response: SynchronizationData{}},
+	"POST /exam/{eventSlug}/sync/":               {ID: "SynchronizationPut", Summary: "Save the synchronization data of the event", Security: loggedInSecurity, Request: SynchronizationData{}, StatusCode: http.StatusCreated, Response: openapi.OK},
+
+	"GET /exam/{eventSlug}/question/":                          {ID: "QuestionList", Summary: "List the questions of the event", Security: loggedInSecurity, Response: []QuestionData{}},
+	"POST /exam/{eventSlug}/question/":                         {ID: "QuestionCreate", Summary: "Create a question on the event", Security: loggedInSecurity, Request: QuestionData{}, StatusCode: http.StatusCreated, Response: QuestionData{}},
+	"GET /exam/{eventSlug}/question/{questionNumber}/":         {ID: "QuestionDetail", Summary: "Get the question", Security: loggedInSecurity, Response: QuestionData{}},
+	"DELETE /exam/{eventSlug}/question/{questionNumber}/":      {ID: "QuestionDelete", Summary: "Delete the question", Security: loggedInSecurity, Response: QuestionData{}},
+	"POST /exam/{eventSlug}/question/{questionNumber}/submit/": {ID: "SubmissionCreate", Summary: "Submit the answer of the question", Security: loggedInSecurity, Request: SubmitSubmissionRequest{}, StatusCode: http.StatusCreated, Response: QuestionData{}},
+}
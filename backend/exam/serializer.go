@@ -1,6 +1,9 @@
 package exam
 
 import (
+	"encoding/base64"
+	"encoding/csv"
+	"strconv"
 	"strings"
 	"time"
 
@@ -21,6 +24,7 @@ type EventData struct {
 	PubKey              string `json:"pubKey"`
 	IsDecrypted         bool   `json:"isDecrypted"`
 	LastSynchronization string `json:"lastSynchronization"`
+	State               string `json:"state"`
 }
 
 // VenueData is JSON representation of venue.
@@ -29,22 +33,143 @@ type VenueData struct {
 	Name string `json:"name"`
 }
 
+// RoomData is JSON representation of room.
+type RoomData struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Capacity uint   `json:"capacity"`
+}
+
 // ParticipationData is JSON representation of participation.
+// RoomID and SeatNumber are zero if the seat is not assigned.
 type ParticipationData struct {
 	ID           uint   `json:"id"`
 	UserUsername string `json:"userUsername"`
 	VenueID      uint   `json:"venueId"`
+	RoomID       uint   `json:"roomId"`
+	SeatNumber   uint   `json:"seatNumber"`
 	KeyPlain     string `json:"key,omitempty"`
 	KeyTwice     string `json:"keyTwice"`
 }
 
+// EventRoleData is JSON representation of role assignment on event.
+// VenueID is zero if the assignment is on all venues of the event.
+type EventRoleData struct {
+	ID           uint   `json:"id"`
+	UserUsername string `json:"userUsername"`
+	VenueID      uint   `json:"venueId"`
+	Role         string `json:"role"`
+}
+
+// SeatAssignmentData is a seat assignment of a participant, used
+// in importing seat assignment from CSV
+type SeatAssignmentData struct {
+	UserUsername string `json:"userUsername"`
+	RoomName     string `json:"roomName"`
+	SeatNumber   uint   `json:"seatNumber"`
+}
+
+// SeatImportRequest is JSON representation of request for importing
+// seat assignment. CSV contains rows of username, room name, and seat number.
+type SeatImportRequest struct {
+	CSV string `json:"csv"`
+}
+
+// ParticipantImportRequest is JSON representation of request for importing
+// participants. CSV contains rows of name, username, venue name, and optional
+// password. The first row is skipped if it is a header.
+type ParticipantImportRequest struct {
+	CSV string `json:"csv"`
+}
+
+// ParticipantImportData is a participant to be imported. Password is
+// generated if it is empty. On the import result, Key is the generated
+// participation key, and Password is empty if the user has already existed.
+type ParticipantImportData struct {
+	Name      string `json:"name"`
+	Username  string `json:"username"`
+	VenueName string `json:"venueName"`
+	Password  string `json:"password"`
+	Key       string `json:"key"`
+}
+
+// SeatData is a seat in the seating chart. The user fields
+// are empty if nobody sits on the seat.
+type SeatData struct {
+	Number       uint   `json:"number"`
+	UserUsername string `json:"userUsername"`
+	UserName     string `json:"userName"`
+}
+
+// SeatingChartRoomData is the seats of a room in the seating chart
+type SeatingChartRoomData struct {
+	Room  RoomData   `json:"room"`
+	Seats []SeatData `json:"seats"`
+}
+
+// SeatingChartData is JSON representation of seating chart of a venue.
+// Unassigned is the participants that don't have any seat yet.
+type SeatingChartData struct {
+	Venue      VenueData              `json:"venue"`
+	Rooms      []SeatingChartRoomData `json:"rooms"`
+	Unassigned []SeatData             `json:"unassigned"`
+}
+
+// CredentialCardRequest is JSON representation of request for generating
+// credential cards of a venue. Layout is either sheet or card. If ResetPassword
+// is true, new passwords are generated and printed. If ReissueKey is true, new
+// keys are generated for participants whose key has been wiped.
+type CredentialCardRequest struct {
+	Layout        string `json:"layout"`
+	ResetPassword bool   `json:"resetPassword"`
+	ReissueKey    bool   `json:"reissueKey"`
+}
+
+// CredentialCardData is JSON representation of generated credential cards.
+// PDF is base64 encoded, and Skipped is the usernames of participants
+// whose key has been wiped before.
+type CredentialCardData struct {
+	PDF     string   `json:"pdf"`
+	Skipped []string `json:"skipped"`
+}
+
+// SeatingChartPrintData is JSON representation of printable seating chart.
+// PDF is base64 encoded.
+type SeatingChartPrintData struct {
+	PDF string `json:"pdf"`
+}
+
 // ParticipationStatus is status of user participant to be monitored
 type ParticipationStatus struct {
-	UserUsername      string     `json:"userUsername"`
-	IPAddress         string     `json:"ipAddress"`
-	LoginAt           *time.Time `json:"loginAt"`
-	SessionID         uint       `json:"sessionId"`
-	UserSessionLocked bool       `json:"userSessionLocked"`
+	UserUsername string     `json:"userUsername"`
+	IPAddress    string     `json:"ipAddress"`
+	LoginAt      *time.Time `json:"loginAt"`
+	LastSeenAt   *time.Time `json:"lastSeenAt"`
+	SessionID    uint       `json:"sessionId"`
+	Attendance   string     `json:"attendance"`
+}
+
+// CheckInRequest is JSON representation of request when proctor
+// checks in a participant. IDVerified is true if the proctor has
+// checked the identity of the participant.
+type CheckInRequest struct {
+	UserUsername string `json:"userUsername"`
+	IDVerified   bool   `json:"idVerified"`
+}
+
+// AttendanceData is JSON representation of attendance of a participant.
+// CheckedInAt is empty if the participant has not been checked in.
+type AttendanceData struct {
+	UserUsername string `json:"userUsername"`
+	Attendance   string `json:"attendance"`
+	CheckedInAt  string `json:"checkedInAt"`
+	IDVerified   bool   `json:"idVerified"`
+}
+
+// AttendanceSynchronizationData is JSON representation of attendance
+// of all participants on a venue, sent back from local to central
+type AttendanceSynchronizationData struct {
+	Attendances []AttendanceData `json:"attendances"`
 }
 
 // VerificationData used for client submitting hashed once participation key
@@ -72,10 +197,43 @@ type SubmitSubmissionRequest struct {
 type SynchronizationData struct {
 	Event     EventData                   `json:"event"`
 	Venue     VenueData                   `json:"venue"`
+	Rooms     []RoomData                  `json:"rooms"`
 	Questions []QuestionData              `json:"questions"`
 	Users     []auth.UserWithPasswordData `json:"users"`
 	UsersKey  map[string]string           `json:"usersKey"`
 	UsersY    map[string]string           `json:"usersY"`
+	UsersRoom map[string]string           `json:"usersRoom"`
+	UsersSeat map[string]uint             `json:"usersSeat"`
+}
+
+// EventUpdateRequest is JSON representation of updating an event. Force is
+// required to update the event that has been synchronized or decrypted.
+type EventUpdateRequest struct {
+	EventData
+	Force bool `json:"force"`
+}
+
+// EventDeleteRequest is JSON representation of deleting an event. The
+// request data is optional, it is only needed to force the deletion.
+type EventDeleteRequest struct {
+	Force bool `json:"force"`
+}
+
+// EventCloneRequest is JSON representation of cloning an event. The title
+// of the source event is used if the title is empty.
+type EventCloneRequest struct {
+	Slug                    string `json:"slug"`
+	Title                   string `json:"title"`
+	StartsAt                string `json:"startsAt"`
+	EndsAt                  string `json:"endsAt"`
+	IncludeParticipations   bool   `json:"includeParticipations"`
+	IncludeVenueAssignments bool   `json:"includeVenueAssignments"`
+}
+
+// EventTransitionRequest is JSON representation of moving the event
+// to other state
+type EventTransitionRequest struct {
+	State string `json:"state"`
 }
 
 // DecryptRequest is JSON representation of submitting key for
@@ -108,6 +266,35 @@ func DeserializeVenue(venueData VenueData, venue *Venue) helios.Error {
 	return nil
 }
 
+// SerializeRoom converts Room object room to JSON of room
+func SerializeRoom(room Room) RoomData {
+	roomData := RoomData{
+		ID:       room.ID,
+		Name:     room.Name,
+		Capacity: room.Capacity,
+	}
+	return roomData
+}
+
+// DeserializeRoom returns the Room from RoomData
+func DeserializeRoom(roomData RoomData, room *Room) helios.Error {
+	var err helios.ErrorForm = helios.NewErrorForm()
+	room.ID = roomData.ID
+	room.Name = roomData.Name
+	room.Capacity = roomData.Capacity
+
+	if room.Name == "" {
+		err.FieldError["name"] = helios.ErrorFormFieldAtomic{"Name can't be empty"}
+	}
+	if room.Capacity == 0 {
+		err.FieldError["capacity"] = helios.ErrorFormFieldAtomic{"Capacity must be positive"}
+	}
+	if err.IsError() {
+		return err
+	}
+	return nil
+}
+
 // SerializeEvent converts Event object event to JSON of event
 func SerializeEvent(event Event) EventData {
 	var lastSynchronization string
@@ -126,6 +313,7 @@ func SerializeEvent(event Event) EventData {
 		PubKey:              event.PubKey,
 		IsDecrypted:         !event.DecryptedAt.IsZero(),
 		LastSynchronization: lastSynchronization,
+		State:               event.State,
 	}
 	return eventData
 }
@@ -180,6 +368,8 @@ func SerializeParticipation(participation Participation) ParticipationData {
 		ID:           participation.ID,
 		UserUsername: participation.User.Username,
 		VenueID:      participation.Venue.ID,
+		RoomID:       participation.GetRoomID(),
+		SeatNumber:   participation.SeatNumber,
 		KeyTwice:     participation.KeyHashedTwice,
 	}
 	return participationData
@@ -190,10 +380,14 @@ func DeserializeParticipation(participationData ParticipationData, participation
 	var err helios.ErrorForm = helios.NewErrorForm()
 	participation.ID = participationData.ID
 	participation.VenueID = participationData.VenueID
+	participation.SetSeat(participationData.RoomID, participationData.SeatNumber)
 
 	if participation.VenueID == 0 {
 		err.FieldError["venueId"] = helios.ErrorFormFieldAtomic{"Venue can't be empty"}
 	}
+	if participationData.RoomID == 0 && participationData.SeatNumber != 0 {
+		err.FieldError["seatNumber"] = helios.ErrorFormFieldAtomic{"Room must be chosen to assign a seat"}
+	}
 	if participationData.UserUsername == "" {
 		err.FieldError["userUsername"] = helios.ErrorFormFieldAtomic{"Username can't be empty"}
 	}
@@ -204,6 +398,68 @@ func DeserializeParticipation(participationData ParticipationData, participation
 	return nil
 }
 
+// SerializeEventRole converts role assignment to JSON of event role
+func SerializeEventRole(eventRole auth.EventRole) EventRoleData {
+	eventRoleData := EventRoleData{
+		ID:           eventRole.ID,
+		UserUsername: eventRole.User.Username,
+		VenueID:      eventRole.VenueID,
+		Role:         eventRole.Role,
+	}
+	return eventRoleData
+}
+
+// DeserializeEventClone returns the clone Event from EventCloneRequest
+func DeserializeEventClone(cloneRequest EventCloneRequest, event *Event) helios.Error {
+	var err helios.ErrorForm = helios.NewErrorForm()
+	var errStartsAt, errEndsAt error
+	event.Slug = cloneRequest.Slug
+	event.Title = cloneRequest.Title
+	event.StartsAt, errStartsAt = time.Parse(time.RFC3339, cloneRequest.StartsAt)
+	event.EndsAt, errEndsAt = time.Parse(time.RFC3339, cloneRequest.EndsAt)
+
+	if event.Slug == "" {
+		err.FieldError["slug"] = helios.ErrorFormFieldAtomic{"Slug can't be empty"}
+	}
+	if cloneRequest.StartsAt == "" {
+		err.FieldError["startsAt"] = helios.ErrorFormFieldAtomic{"Start time must be provided"}
+	} else if errStartsAt != nil {
+		err.FieldError["startsAt"] = helios.ErrorFormFieldAtomic{"Failed to parse time"}
+	}
+	if cloneRequest.EndsAt == "" {
+		err.FieldError["endsAt"] = helios.ErrorFormFieldAtomic{"End time must be provided"}
+	} else if errEndsAt != nil {
+		err.FieldError["endsAt"] = helios.ErrorFormFieldAtomic{"Failed to parse time"}
+	}
+	if event.EndsAt.Before(event.StartsAt) {
+		err.FieldError["endsAt"] = helios.ErrorFormFieldAtomic{"End time should be after start time"}
+	}
+	if err.IsError() {
+		return err
+	}
+	return nil
+}
+
+// DeserializeEventRole converts JSON of event role to role assignment
+func DeserializeEventRole(eventRoleData EventRoleData, eventRole *auth.EventRole) helios.Error {
+	var err helios.ErrorForm = helios.NewErrorForm()
+	eventRole.ID = eventRoleData.ID
+	eventRole.VenueID = eventRoleData.VenueID
+	eventRole.Role = eventRoleData.Role
+
+	if eventRoleData.UserUsername == "" {
+		err.FieldError["userUsername"] = helios.ErrorFormFieldAtomic{"Username can't be empty"}
+	}
+	if !auth.IsValidEventRole(eventRole.Role) {
+		err.FieldError["role"] = helios.ErrorFormFieldAtomic{"Role should be one of author, reviewer, grader, proctor, or venue_manager"}
+	}
+
+	if err.IsError() {
+		return err
+	}
+	return nil
+}
+
 // DeserializeParticipationWithKey convert JSON like DeserializeParticipation but with key
 // used in creating participation
 func DeserializeParticipationWithKey(participationData ParticipationData, participation *Participation) helios.Error {
@@ -222,6 +478,269 @@ func DeserializeParticipationWithKey(participationData ParticipationData, partic
 	return nil
 }
 
+// SerializeAttendance converts Participation object participation to JSON of attendance
+func SerializeAttendance(participation Participation) AttendanceData {
+	var checkedInAt string
+	if !participation.CheckedInAt.IsZero() {
+		checkedInAt = participation.CheckedInAt.Local().Format(time.RFC3339)
+	}
+	attendanceData := AttendanceData{
+		UserUsername: participation.User.Username,
+		Attendance:   participation.Attendance,
+		CheckedInAt:  checkedInAt,
+		IDVerified:   participation.IDVerified,
+	}
+	return attendanceData
+}
+
+// DeserializeAttendance converts JSON of attendance to Participation object
+// with only the attendance fields filled
+func DeserializeAttendance(attendanceData AttendanceData, participation *Participation) helios.Error {
+	var err helios.ErrorForm = helios.NewErrorForm()
+	var errCheckedInAt error
+	participation.Attendance = attendanceData.Attendance
+	participation.IDVerified = attendanceData.IDVerified
+	participation.CheckedInAt = time.Time{}
+	if attendanceData.CheckedInAt != "" {
+		participation.CheckedInAt, errCheckedInAt = time.Parse(time.RFC3339, attendanceData.CheckedInAt)
+	}
+
+	if attendanceData.UserUsername == "" {
+		err.FieldError["userUsername"] = helios.ErrorFormFieldAtomic{"Username can't be empty"}
+	}
+	switch participation.Attendance {
+	case "", AttendanceNoShow:
+		if attendanceData.CheckedInAt != "" {
+			err.FieldError["checkedInAt"] = helios.ErrorFormFieldAtomic{"Participant without check in can't have check in time"}
+		}
+	case AttendancePresent, AttendanceLate:
+		if attendanceData.CheckedInAt == "" {
+			err.FieldError["checkedInAt"] = helios.ErrorFormFieldAtomic{"Check in time must be provided"}
+		} else if errCheckedInAt != nil {
+			err.FieldError["checkedInAt"] = helios.ErrorFormFieldAtomic{"Failed to parse time"}
+		}
+	default:
+		err.FieldError["attendance"] = helios.ErrorFormFieldAtomic{"Attendance is not valid"}
+	}
+
+	if err.IsError() {
+		return err
+	}
+	return nil
+}
+
+// SerializeAttendanceSynchronizationData converts participations to JSON of attendance synchronization
+func SerializeAttendanceSynchronizationData(participations []Participation) AttendanceSynchronizationData {
+	attendancesData := make([]AttendanceData, 0)
+	for _, participation := range participations {
+		attendancesData = append(attendancesData, SerializeAttendance(participation))
+	}
+	return AttendanceSynchronizationData{Attendances: attendancesData}
+}
+
+// DeserializeAttendanceSynchronizationData converts JSON of attendance synchronization
+// to map of username to Participation object with only the attendance fields filled
+func DeserializeAttendanceSynchronizationData(attendanceSynchronizationData AttendanceSynchronizationData, usersAttendance *map[string]Participation) helios.Error {
+	var err helios.ErrorForm = helios.NewErrorForm()
+	var errAttendances helios.ErrorFormFieldArray = make(helios.ErrorFormFieldArray, 0)
+	*usersAttendance = make(map[string]Participation)
+	for _, attendanceData := range attendanceSynchronizationData.Attendances {
+		var participation Participation
+		var errAttendance helios.Error = DeserializeAttendance(attendanceData, &participation)
+		if errAttendance == nil {
+			(*usersAttendance)[attendanceData.UserUsername] = participation
+			errAttendances = append(errAttendances, helios.ErrorFormFieldNested{})
+		} else {
+			var errAttendanceForm helios.ErrorForm = errAttendance.(helios.ErrorForm)
+			errAttendances = append(errAttendances, errAttendanceForm.FieldError)
+		}
+	}
+	err.FieldError["attendances"] = errAttendances
+
+	if err.IsError() {
+		return err
+	}
+	return nil
+}
+
+// DeserializeSeatImportRequest parses the CSV of seat import request. Each row
+// should consist of username, room name, and seat number.
+func DeserializeSeatImportRequest(seatImportRequest SeatImportRequest, seats *[]SeatAssignmentData) helios.Error {
+	var err helios.ErrorForm = helios.NewErrorForm()
+	var records [][]string
+	var errParse error
+	var reader *csv.Reader = csv.NewReader(strings.NewReader(seatImportRequest.CSV))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, errParse = reader.ReadAll()
+	if errParse != nil {
+		err.FieldError["csv"] = helios.ErrorFormFieldAtomic{"Failed to parse CSV"}
+		return err
+	}
+	if len(records) == 0 {
+		err.FieldError["csv"] = helios.ErrorFormFieldAtomic{"CSV can't be empty"}
+		return err
+	}
+
+	var errRows helios.ErrorFormFieldArray = make(helios.ErrorFormFieldArray, 0)
+	for _, record := range records {
+		var errRow helios.ErrorFormFieldNested = make(helios.ErrorFormFieldNested)
+		var seat SeatAssignmentData
+		if len(record) != 3 {
+			errRow["row"] = helios.ErrorFormFieldAtomic{"Row should consist of username, room name, and seat number"}
+			errRows = append(errRows, errRow)
+			continue
+		}
+		seatNumber, errParseSeat := strconv.ParseUint(strings.TrimSpace(record[2]), 10, 32)
+		seat.UserUsername = strings.TrimSpace(record[0])
+		seat.RoomName = strings.TrimSpace(record[1])
+		seat.SeatNumber = uint(seatNumber)
+		if seat.UserUsername == "" {
+			errRow["userUsername"] = helios.ErrorFormFieldAtomic{"Username can't be empty"}
+		}
+		if seat.RoomName == "" {
+			errRow["roomName"] = helios.ErrorFormFieldAtomic{"Room name can't be empty"}
+		}
+		if errParseSeat != nil {
+			errRow["seatNumber"] = helios.ErrorFormFieldAtomic{"Seat number should be a positive number"}
+		}
+		*seats = append(*seats, seat)
+		errRows = append(errRows, errRow)
+	}
+	err.FieldError["seats"] = errRows
+	if err.IsError() {
+		return err
+	}
+	return nil
+}
+
+// DeserializeParticipantImportRequest parses the CSV of participant import request.
+// Each row should consist of name, username, venue name, and optional password.
+func DeserializeParticipantImportRequest(participantImportRequest ParticipantImportRequest, participants *[]ParticipantImportData) helios.Error {
+	var err helios.ErrorForm = helios.NewErrorForm()
+	var records [][]string
+	var errParse error
+	var reader *csv.Reader = csv.NewReader(strings.NewReader(participantImportRequest.CSV))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, errParse = reader.ReadAll()
+	if errParse != nil {
+		err.FieldError["csv"] = helios.ErrorFormFieldAtomic{"Failed to parse CSV"}
+		return err
+	}
+	if len(records) > 0 && len(records[0]) > 1 && strings.EqualFold(strings.TrimSpace(records[0][1]), "username") {
+		records = records[1:]
+	}
+	if len(records) == 0 {
+		err.FieldError["csv"] = helios.ErrorFormFieldAtomic{"CSV can't be empty"}
+		return err
+	}
+
+	var errRows helios.ErrorFormFieldArray = make(helios.ErrorFormFieldArray, 0)
+	for _, record := range records {
+		var errRow helios.ErrorFormFieldNested = make(helios.ErrorFormFieldNested)
+		var participant ParticipantImportData
+		if len(record) != 3 && len(record) != 4 {
+			errRow["row"] = helios.ErrorFormFieldAtomic{"Row should consist of name, username, venue, and optional password"}
+			errRows = append(errRows, errRow)
+			continue
+		}
+		participant.Name = strings.TrimSpace(record[0])
+		participant.Username = strings.TrimSpace(record[1])
+		participant.VenueName = strings.TrimSpace(record[2])
+		if len(record) == 4 {
+			participant.Password = record[3]
+		}
+		if participant.Name == "" {
+			errRow["name"] = helios.ErrorFormFieldAtomic{"Name can't be empty"}
+		}
+		if participant.Username == "" {
+			errRow["username"] = helios.ErrorFormFieldAtomic{"Username can't be empty"}
+		}
+		if participant.VenueName == "" {
+			errRow["venueName"] = helios.ErrorFormFieldAtomic{"Venue can't be empty"}
+		}
+		*participants = append(*participants, participant)
+		errRows = append(errRows, errRow)
+	}
+	err.FieldError["participants"] = errRows
+	if err.IsError() {
+		return err
+	}
+	return nil
+}
+
+// DeserializeCredentialCardRequest validates the layout of the request.
+// Sheet layout is used if the layout is empty.
+func DeserializeCredentialCardRequest(credentialCardRequest CredentialCardRequest, layout *string) helios.Error {
+	var err helios.ErrorForm = helios.NewErrorForm()
+	*layout = credentialCardRequest.Layout
+	if *layout == "" {
+		*layout = CredentialCardLayoutSheet
+	}
+	if *layout != CredentialCardLayoutSheet && *layout != CredentialCardLayoutCard {
+		err.FieldError["layout"] = helios.ErrorFormFieldAtomic{"Layout should be either sheet or card"}
+	}
+	if err.IsError() {
+		return err
+	}
+	return nil
+}
+
+// SerializeCredentialCard converts the generated PDF and skipped usernames to CredentialCardData
+func SerializeCredentialCard(pdf []byte, skipped []string) CredentialCardData {
+	if skipped == nil {
+		skipped = make([]string, 0)
+	}
+	return CredentialCardData{
+		PDF:     base64.StdEncoding.EncodeToString(pdf),
+		Skipped: skipped,
+	}
+}
+
+// SerializeSeatingChartPrint converts the rendered PDF to SeatingChartPrintData
+func SerializeSeatingChartPrint(pdf []byte) SeatingChartPrintData {
+	return SeatingChartPrintData{PDF: base64.StdEncoding.EncodeToString(pdf)}
+}
+
+// SerializeSeatingChart converts rooms and seated participations into SeatingChartData.
+// All seats of the rooms are listed, including the empty ones.
+func SerializeSeatingChart(venue Venue, rooms []Room, participations []Participation) SeatingChartData {
+	var roomsData []SeatingChartRoomData = make([]SeatingChartRoomData, 0)
+	var unassigned []SeatData = make([]SeatData, 0)
+	var seatIdx map[uint]map[uint]*SeatData = make(map[uint]map[uint]*SeatData)
+	for _, room := range rooms {
+		var seats []SeatData = make([]SeatData, room.Capacity)
+		for i := range seats {
+			seats[i].Number = uint(i + 1)
+		}
+		roomsData = append(roomsData, SeatingChartRoomData{Room: SerializeRoom(room), Seats: seats})
+	}
+	for i, room := range rooms {
+		seatIdx[room.ID] = make(map[uint]*SeatData)
+		for j := range roomsData[i].Seats {
+			seatIdx[room.ID][roomsData[i].Seats[j].Number] = &roomsData[i].Seats[j]
+		}
+	}
+	for _, participation := range participations {
+		var seat *SeatData
+		if seatIdx[participation.GetRoomID()] != nil {
+			seat = seatIdx[participation.GetRoomID()][participation.SeatNumber]
+		}
+		if seat == nil {
+			unassigned = append(unassigned, SeatData{UserUsername: participation.User.Username, UserName: participation.User.Name})
+		} else {
+			seat.UserUsername = participation.User.Username
+			seat.UserName = participation.User.Name
+		}
+	}
+	return SeatingChartData{
+		Venue:      SerializeVenue(venue),
+		Rooms:      roomsData,
+		Unassigned: unassigned,
+	}
+}
+
 // SerializeQuestion converts Question object question to JSON of question
 func SerializeQuestion(question Question) QuestionData {
 	var choicesArr []string = strings.Split(question.Choices, "|")
@@ -263,39 +782,56 @@ func DeserializeQuestion(questionData QuestionData, question *Question) helios.E
 	return nil
 }
 
-// SerializeSynchronizationData converts event, questions, participations, and users
-// into SynchronizationData
-func SerializeSynchronizationData(event Event, venue Venue, questions []Question, users []auth.User, usersKey map[string]string, usersY map[string]string) SynchronizationData {
+// SerializeSynchronizationData converts SynchronizationPayload into SynchronizationData
+func SerializeSynchronizationData(payload SynchronizationPayload) SynchronizationData {
+	var roomsData []RoomData = make([]RoomData, 0)
 	var questionsData []QuestionData = make([]QuestionData, 0)
 	var usersData []auth.UserWithPasswordData = make([]auth.UserWithPasswordData, 0)
-	for _, question := range questions {
+	var usersKey map[string]string = make(map[string]string)
+	var usersY map[string]string = make(map[string]string)
+	var usersRoom map[string]string = make(map[string]string)
+	var usersSeat map[string]uint = make(map[string]uint)
+	for _, room := range payload.Rooms {
+		roomsData = append(roomsData, SerializeRoom(room))
+	}
+	for _, question := range payload.Questions {
 		questionsData = append(questionsData, SerializeQuestion(question))
 	}
-	for _, user := range users {
+	for _, user := range payload.Users {
 		usersData = append(usersData, auth.SerializeUserWithPassword(user))
 	}
+	for username, participation := range payload.Participations {
+		usersKey[username] = participation.KeyHashedTwice
+		usersY[username] = participation.SecretShareY
+		if participation.RoomName != "" {
+			usersRoom[username] = participation.RoomName
+			usersSeat[username] = participation.SeatNumber
+		}
+	}
 	return SynchronizationData{
-		Event:     SerializeEvent(event),
-		Venue:     SerializeVenue(venue),
+		Event:     SerializeEvent(payload.Event),
+		Venue:     SerializeVenue(payload.Venue),
+		Rooms:     roomsData,
 		Questions: questionsData,
 		Users:     usersData,
 		UsersKey:  usersKey,
 		UsersY:    usersY,
+		UsersRoom: usersRoom,
+		UsersSeat: usersSeat,
 	}
 }
 
-// DeserializeSynchronizationData converts event, questions, participations, and users
-// into SynchronizationData
-func DeserializeSynchronizationData(synchronizationData SynchronizationData, event *Event, venue *Venue, questions *[]Question, users *[]auth.User, usersKey *map[string]string, usersY *map[string]string) helios.Error {
+// DeserializeSynchronizationData converts SynchronizationData into SynchronizationPayload
+func DeserializeSynchronizationData(synchronizationData SynchronizationData, payload *SynchronizationPayload) helios.Error {
 	var err helios.ErrorForm = helios.NewErrorForm()
-	var errEvent helios.Error = DeserializeEvent(synchronizationData.Event, event)
+	var errEvent helios.Error = DeserializeEvent(synchronizationData.Event, &payload.Event)
 	if errEvent != nil {
 		var errEventForm helios.ErrorForm = errEvent.(helios.ErrorForm)
 		err.FieldError["event"] = errEventForm.FieldError
 		err.NonFieldError = errEventForm.NonFieldError
 	}
 
-	var errVenue helios.Error = DeserializeVenue(synchronizationData.Venue, venue)
+	var errVenue helios.Error = DeserializeVenue(synchronizationData.Venue, &payload.Venue)
 	if errVenue != nil {
 		var errVenueForm helios.ErrorForm = errVenue.(helios.ErrorForm)
 		err.FieldError["venue"] = errVenueForm.FieldError
@@ -305,12 +841,28 @@ func DeserializeSynchronizationData(synchronizationData SynchronizationData, eve
 		// }
 	}
 
+	var errRooms helios.ErrorFormFieldArray = make(helios.ErrorFormFieldArray, 0)
+	var isRoomExist map[string]bool = make(map[string]bool)
+	for _, roomData := range synchronizationData.Rooms {
+		var room Room
+		var errRoom helios.Error = DeserializeRoom(roomData, &room)
+		if errRoom == nil {
+			payload.Rooms = append(payload.Rooms, room)
+			isRoomExist[room.Name] = true
+			errRooms = append(errRooms, helios.ErrorFormFieldNested{})
+		} else {
+			var errRoomForm helios.ErrorForm = errRoom.(helios.ErrorForm)
+			errRooms = append(errRooms, errRoomForm.FieldError)
+		}
+	}
+	err.FieldError["rooms"] = errRooms
+
 	var errQuestions helios.ErrorFormFieldArray = make(helios.ErrorFormFieldArray, 0)
 	for _, questionData := range synchronizationData.Questions {
 		var question Question
 		var errQuestion helios.Error = DeserializeQuestion(questionData, &question)
 		if errQuestion == nil {
-			*questions = append(*questions, question)
+			payload.Questions = append(payload.Questions, question)
 			errQuestions = append(errQuestions, helios.ErrorFormFieldNested{})
 		} else {
 			var errQuestionForm helios.ErrorForm = errQuestion.(helios.ErrorForm)
@@ -326,9 +878,9 @@ func DeserializeSynchronizationData(synchronizationData SynchronizationData, eve
 	var errUsers helios.ErrorFormFieldArray = make(helios.ErrorFormFieldArray, 0)
 	for _, userData := range synchronizationData.Users {
 		var user auth.User
-		var errUser helios.Error = auth.DeserializeUserWithPassword(userData, &user)
+		var errUser helios.Error = auth.DeserializeUserWithHashedPassword(userData, &user)
 		if errUser == nil {
-			*users = append(*users, user)
+			payload.Users = append(payload.Users, user)
 			errUsers = append(errUsers, helios.ErrorFormFieldNested{})
 		} else {
 			var errUserForm helios.ErrorForm = errUser.(helios.ErrorForm)
@@ -341,15 +893,30 @@ func DeserializeSynchronizationData(synchronizationData SynchronizationData, eve
 	}
 	err.FieldError["users"] = errUsers
 
-	*usersKey = make(map[string]string)
+	payload.Participations = make(map[string]SynchronizedParticipation)
 	for k, v := range synchronizationData.UsersKey {
-		(*usersKey)[k] = v
+		var participation SynchronizedParticipation = payload.Participations[k]
+		participation.KeyHashedTwice = v
+		payload.Participations[k] = participation
 	}
-
-	*usersY = make(map[string]string)
 	for k, v := range synchronizationData.UsersY {
-		(*usersY)[k] = v
+		var participation SynchronizedParticipation = payload.Participations[k]
+		participation.SecretShareY = v
+		payload.Participations[k] = participation
+	}
+
+	var errUsersRoom helios.ErrorFormFieldNested = make(helios.ErrorFormFieldNested)
+	for k, v := range synchronizationData.UsersRoom {
+		if isRoomExist[v] {
+			var participation SynchronizedParticipation = payload.Participations[k]
+			participation.RoomName = v
+			participation.SeatNumber = synchronizationData.UsersSeat[k]
+			payload.Participations[k] = participation
+		} else {
+			errUsersRoom[k] = helios.ErrorFormFieldAtomic{"Room doesn't exist"}
+		}
 	}
+	err.FieldError["usersRoom"] = errUsersRoom
 
 	if err.IsError() {
 		return err
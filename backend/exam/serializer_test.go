@@ -70,6 +70,325 @@ func TestDeserializeVenue(t *testing.T) {
 	}
 }
 
+func TestSerializeRoom(t *testing.T) {
+	var room Room = RoomFactory(Room{ID: 3, Name: "Room A", Capacity: 30})
+	var expectedJSON string = `{"id":3,"name":"Room A","capacity":30}`
+	var serialized RoomData = SerializeRoom(room)
+	var serializedJSON []byte
+	var errMarshalling error
+	serializedJSON, errMarshalling = json.Marshal(serialized)
+	assert.Nil(t, errMarshalling)
+	assert.Equal(t, expectedJSON, string(serializedJSON))
+}
+
+func TestDeserializeRoom(t *testing.T) {
+	type deserializeRoomTestCase struct {
+		roomDataJSON  string
+		expectedRoom  Room
+		expectedError string
+	}
+	testCases := []deserializeRoomTestCase{{
+		roomDataJSON: `{"id":3,"name":"Room A","capacity":30}`,
+		expectedRoom: Room{ID: 3, Name: "Room A", Capacity: 30},
+	}, {
+		roomDataJSON:  `{}`,
+		expectedError: `{"code":"form_error","message":{"_error":[],"capacity":["Capacity must be positive"],"name":["Name can't be empty"]}}`,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test DeserializeRoom testcase: %d", i)
+		var roomData RoomData
+		var room Room
+		var errUnmarshalling error
+		var errDeserialization helios.Error
+		errUnmarshalling = json.Unmarshal([]byte(testCase.roomDataJSON), &roomData)
+		errDeserialization = DeserializeRoom(roomData, &room)
+		assert.Nil(t, errUnmarshalling)
+		if testCase.expectedError == "" {
+			assert.Nil(t, errDeserialization)
+			assert.Equal(t, testCase.expectedRoom, room)
+		} else {
+			var errDeserializationJSON []byte
+			var errMarshalling error
+			errDeserializationJSON, errMarshalling = json.Marshal(errDeserialization.GetMessage())
+			assert.Nil(t, errMarshalling)
+			assert.Equal(t, testCase.expectedError, string(errDeserializationJSON))
+		}
+	}
+}
+
+func TestDeserializeEventRole(t *testing.T) {
+	type deserializeEventRoleTestCase struct {
+		eventRoleDataJSON string
+		expectedEventRole auth.EventRole
+		expectedError     string
+	}
+	testCases := []deserializeEventRoleTestCase{{
+		eventRoleDataJSON: `{"id":3,"userUsername":"abc","venueId":2,"role":"proctor"}`,
+		expectedEventRole: auth.EventRole{ID: 3, VenueID: 2, Role: auth.EventRoleProctor},
+	}, {
+		eventRoleDataJSON: `{"userUsername":"abc","role":"author"}`,
+		expectedEventRole: auth.EventRole{Role: auth.EventRoleAuthor},
+	}, {
+		eventRoleDataJSON: `{"role":"admin"}`,
+		expectedError:     `{"code":"form_error","message":{"_error":[],"role":["Role should be one of author, reviewer, grader, proctor, or venue_manager"],"userUsername":["Username can't be empty"]}}`,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test DeserializeEventRole testcase: %d", i)
+		var eventRoleData EventRoleData
+		var eventRole auth.EventRole
+		var errUnmarshalling error
+		var errDeserialization helios.Error
+		errUnmarshalling = json.Unmarshal([]byte(testCase.eventRoleDataJSON), &eventRoleData)
+		errDeserialization = DeserializeEventRole(eventRoleData, &eventRole)
+		assert.Nil(t, errUnmarshalling)
+		if testCase.expectedError == "" {
+			assert.Nil(t, errDeserialization)
+			assert.Equal(t, testCase.expectedEventRole, eventRole)
+		} else {
+			var errDeserializationJSON []byte
+			var errMarshalling error
+			errDeserializationJSON, errMarshalling = json.Marshal(errDeserialization.GetMessage())
+			assert.Nil(t, errMarshalling)
+			assert.Equal(t, testCase.expectedError, string(errDeserializationJSON))
+		}
+	}
+}
+
+func TestSerializeAttendance(t *testing.T) {
+	type serializeAttendanceTestCase struct {
+		participation Participation
+		expectedJSON  string
+	}
+	var user auth.User = auth.UserFactory(auth.User{Username: "abc"})
+	testCases := []serializeAttendanceTestCase{{
+		participation: ParticipationFactory(Participation{User: &user, Attendance: AttendanceLate, CheckedInAt: time.Date(2020, 8, 12, 2, 30, 10, 0, time.UTC), IDVerified: true}),
+		expectedJSON:  `{"userUsername":"abc","attendance":"late","checkedInAt":"2020-08-12T09:30:10+07:00","idVerified":true}`,
+	}, {
+		participation: ParticipationFactory(Participation{User: &user}),
+		expectedJSON:  `{"userUsername":"abc","attendance":"","checkedInAt":"","idVerified":false}`,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test SerializeAttendance testcase: %d", i)
+		var serializedJSON []byte
+		var errMarshalling error
+		serializedJSON, errMarshalling = json.Marshal(SerializeAttendance(testCase.participation))
+		assert.Nil(t, errMarshalling)
+		assert.Equal(t, testCase.expectedJSON, string(serializedJSON))
+	}
+}
+
+func TestDeserializeAttendanceSynchronizationData(t *testing.T) {
+	type deserializeAttendanceSynchronizationDataTestCase struct {
+		attendanceSynchronizationDataJSON string
+		expectedUsersAttendance           map[string]Participation
+		expectedError                     string
+	}
+	testCases := []deserializeAttendanceSynchronizationDataTestCase{{
+		attendanceSynchronizationDataJSON: `{"attendances":[` +
+			`{"userUsername":"abc","attendance":"present","checkedInAt":"2020-08-12T09:30:10+07:00","idVerified":true},` +
+			`{"userUsername":"def","attendance":"no_show"},` +
+			`{"userUsername":"ghi"}` +
+			`]}`,
+		expectedUsersAttendance: map[string]Participation{
+			"abc": {Attendance: AttendancePresent, CheckedInAt: time.Date(2020, 8, 12, 2, 30, 10, 0, time.UTC), IDVerified: true},
+			"def": {Attendance: AttendanceNoShow},
+			"ghi": {},
+		},
+	}, {
+		attendanceSynchronizationDataJSON: `{"attendances":[` +
+			`{"userUsername":"abc","attendance":"present"},` +
+			`{"attendance":"late","checkedInAt":"abc"},` +
+			`{"userUsername":"def","attendance":"absent"},` +
+			`{"userUsername":"ghi","attendance":"no_show","checkedInAt":"2020-08-12T09:30:10+07:00"},` +
+			`{"userUsername":"jkl","attendance":"late","checkedInAt":"2020-08-12T09:30:10+07:00"}` +
+			`]}`,
+		expectedError: `{"code":"form_error","message":{"_error":[],"attendances":[` +
+			`{"checkedInAt":["Check in time must be provided"]},` +
+			`{"checkedInAt":["Failed to parse time"],"userUsername":["Username can't be empty"]},` +
+			`{"attendance":["Attendance is not valid"]},` +
+			`{"checkedInAt":["Participant without check in can't have check in time"]},` +
+			`{}` +
+			`]}}`,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test DeserializeAttendanceSynchronizationData testcase: %d", i)
+		var attendanceSynchronizationData AttendanceSynchronizationData
+		var usersAttendance map[string]Participation
+		var errUnmarshalling error
+		var errDeserialization helios.Error
+		errUnmarshalling = json.Unmarshal([]byte(testCase.attendanceSynchronizationDataJSON), &attendanceSynchronizationData)
+		errDeserialization = DeserializeAttendanceSynchronizationData(attendanceSynchronizationData, &usersAttendance)
+		assert.Nil(t, errUnmarshalling)
+		if testCase.expectedError == "" {
+			assert.Nil(t, errDeserialization)
+			assert.Equal(t, len(testCase.expectedUsersAttendance), len(usersAttendance))
+			for username, expectedParticipation := range testCase.expectedUsersAttendance {
+				assert.Equal(t, expectedParticipation.Attendance, usersAttendance[username].Attendance)
+				assert.Equal(t, expectedParticipation.IDVerified, usersAttendance[username].IDVerified)
+				assert.True(t, expectedParticipation.CheckedInAt.Equal(usersAttendance[username].CheckedInAt))
+			}
+		} else {
+			var errDeserializationJSON []byte
+			var errMarshalling error
+			errDeserializationJSON, errMarshalling = json.Marshal(errDeserialization.GetMessage())
+			assert.Nil(t, errMarshalling)
+			assert.Equal(t, testCase.expectedError, string(errDeserializationJSON))
+		}
+	}
+}
+
+func TestDeserializeSeatImportRequest(t *testing.T) {
+	type deserializeSeatImportRequestTestCase struct {
+		csv           string
+		expectedSeats []SeatAssignmentData
+		expectedError string
+	}
+	testCases := []deserializeSeatImportRequestTestCase{{
+		csv: "user1,Room A,1\nuser2, Room B ,12\n",
+		expectedSeats: []SeatAssignmentData{
+			{UserUsername: "user1", RoomName: "Room A", SeatNumber: 1},
+			{UserUsername: "user2", RoomName: "Room B", SeatNumber: 12},
+		},
+	}, {
+		csv:           "",
+		expectedError: `{"code":"form_error","message":{"_error":[],"csv":["CSV can't be empty"]}}`,
+	}, {
+		csv:           "user1,\"Room A,1\n",
+		expectedError: `{"code":"form_error","message":{"_error":[],"csv":["Failed to parse CSV"]}}`,
+	}, {
+		csv: "user1,Room A,1\nuser2,Room A\n,,x",
+		expectedError: `{"code":"form_error","message":{"_error":[],"seats":[` +
+			`{},` +
+			`{"row":["Row should consist of username, room name, and seat number"]},` +
+			`{"roomName":["Room name can't be empty"],"seatNumber":["Seat number should be a positive number"],"userUsername":["Username can't be empty"]}` +
+			`]}}`,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test DeserializeSeatImportRequest testcase: %d", i)
+		var seats []SeatAssignmentData
+		var errDeserialization helios.Error
+		errDeserialization = DeserializeSeatImportRequest(SeatImportRequest{CSV: testCase.csv}, &seats)
+		if testCase.expectedError == "" {
+			assert.Nil(t, errDeserialization)
+			assert.Equal(t, testCase.expectedSeats, seats)
+		} else {
+			var errDeserializationJSON []byte
+			var errMarshalling error
+			errDeserializationJSON, errMarshalling = json.Marshal(errDeserialization.GetMessage())
+			assert.Nil(t, errMarshalling)
+			assert.Equal(t, testCase.expectedError, string(errDeserializationJSON))
+		}
+	}
+}
+
+func TestDeserializeParticipantImportRequest(t *testing.T) {
+	type deserializeParticipantImportRequestTestCase struct {
+		csv                  string
+		expectedParticipants []ParticipantImportData
+		expectedError        string
+	}
+	testCases := []deserializeParticipantImportRequestTestCase{{
+		csv: "name,username,venue,password\nUser 1,user1,Venue A\n User 2 , user2 ,Venue B,pass word\n",
+		expectedParticipants: []ParticipantImportData{
+			{Name: "User 1", Username: "user1", VenueName: "Venue A"},
+			{Name: "User 2", Username: "user2", VenueName: "Venue B", Password: "pass word"},
+		},
+	}, {
+		csv:           "name,username,venue\n",
+		expectedError: `{"code":"form_error","message":{"_error":[],"csv":["CSV can't be empty"]}}`,
+	}, {
+		csv:           "User 1,\"user1,Venue A\n",
+		expectedError: `{"code":"form_error","message":{"_error":[],"csv":["Failed to parse CSV"]}}`,
+	}, {
+		csv: "User 1,user1,Venue A\nUser 2,user2\n,,",
+		expectedError: `{"code":"form_error","message":{"_error":[],"participants":[` +
+			`{},` +
+			`{"row":["Row should consist of name, username, venue, and optional password"]},` +
+			`{"name":["Name can't be empty"],"username":["Username can't be empty"],"venueName":["Venue can't be empty"]}` +
+			`]}}`,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test DeserializeParticipantImportRequest testcase: %d", i)
+		var participants []ParticipantImportData
+		var errDeserialization helios.Error
+		errDeserialization = DeserializeParticipantImportRequest(ParticipantImportRequest{CSV: testCase.csv}, &participants)
+		if testCase.expectedError == "" {
+			assert.Nil(t, errDeserialization)
+			assert.Equal(t, testCase.expectedParticipants, participants)
+		} else {
+			var errDeserializationJSON []byte
+			var errMarshalling error
+			errDeserializationJSON, errMarshalling = json.Marshal(errDeserialization.GetMessage())
+			assert.Nil(t, errMarshalling)
+			assert.Equal(t, testCase.expectedError, string(errDeserializationJSON))
+		}
+	}
+}
+
+func TestDeserializeCredentialCardRequest(t *testing.T) {
+	type deserializeCredentialCardRequestTestCase struct {
+		credentialCardRequest CredentialCardRequest
+		expectedLayout        string
+		expectedError         string
+	}
+	testCases := []deserializeCredentialCardRequestTestCase{{
+		credentialCardRequest: CredentialCardRequest{},
+		expectedLayout:        CredentialCardLayoutSheet,
+	}, {
+		credentialCardRequest: CredentialCardRequest{Layout: "card"},
+		expectedLayout:        CredentialCardLayoutCard,
+	}, {
+		credentialCardRequest: CredentialCardRequest{Layout: "poster"},
+		expectedError:         `{"code":"form_error","message":{"_error":[],"layout":["Layout should be either sheet or card"]}}`,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test DeserializeCredentialCardRequest testcase: %d", i)
+		var layout string
+		var errDeserialization helios.Error
+		errDeserialization = DeserializeCredentialCardRequest(testCase.credentialCardRequest, &layout)
+		if testCase.expectedError == "" {
+			assert.Nil(t, errDeserialization)
+			assert.Equal(t, testCase.expectedLayout, layout)
+		} else {
+			var errDeserializationJSON []byte
+			var errMarshalling error
+			errDeserializationJSON, errMarshalling = json.Marshal(errDeserialization.GetMessage())
+			assert.Nil(t, errMarshalling)
+			assert.Equal(t, testCase.expectedError, string(errDeserializationJSON))
+		}
+	}
+}
+
+func TestSerializeSeatingChart(t *testing.T) {
+	var venue Venue = VenueFactory(Venue{ID: 1, Name: "Venue A"})
+	var rooms []Room = []Room{
+		RoomFactory(Room{ID: 2, Name: "Room A", Capacity: 2, Venue: &venue}),
+		RoomFactory(Room{ID: 3, Name: "Room B", Capacity: 1, Venue: &venue}),
+	}
+	var user1 auth.User = auth.UserFactory(auth.User{Username: "user1", Name: "User 1"})
+	var user2 auth.User = auth.UserFactory(auth.User{Username: "user2", Name: "User 2"})
+	var user3 auth.User = auth.UserFactory(auth.User{Username: "user3", Name: "User 3"})
+	var participations []Participation = []Participation{
+		ParticipationFactory(Participation{User: &user1, Venue: &venue, RoomID: &rooms[0].ID, SeatNumber: 2}),
+		ParticipationFactory(Participation{User: &user2, Venue: &venue, RoomID: &rooms[1].ID, SeatNumber: 1}),
+		ParticipationFactory(Participation{User: &user3, Venue: &venue}),
+	}
+	var expectedJSON string = `{` +
+		`"venue":{"id":1,"name":"Venue A"},` +
+		`"rooms":[` +
+		`{"room":{"id":2,"name":"Room A","capacity":2},"seats":[{"number":1,"userUsername":"","userName":""},{"number":2,"userUsername":"user1","userName":"User 1"}]},` +
+		`{"room":{"id":3,"name":"Room B","capacity":1},"seats":[{"number":1,"userUsername":"user2","userName":"User 2"}]}` +
+		`],` +
+		`"unassigned":[{"number":0,"userUsername":"user3","userName":"User 3"}]` +
+		`}`
+	var serialized SeatingChartData = SerializeSeatingChart(venue, rooms, participations)
+	var serializedJSON []byte
+	var errMarshalling error
+	serializedJSON, errMarshalling = json.Marshal(serialized)
+	assert.Nil(t, errMarshalling)
+	assert.Equal(t, expectedJSON, string(serializedJSON))
+}
+
 func TestSerializeEvent(t *testing.T) {
 	var event Event = EventFactory(Event{
 		ID:                  3,
@@ -83,6 +402,7 @@ func TestSerializeEvent(t *testing.T) {
 		PubKey:              "public_key_for_verifying_sim_key",
 		DecryptedAt:         time.Date(2020, 8, 10, 1, 2, 8, 4, time.FixedZone("UTC", 0)),
 		LastSynchronization: time.Date(2020, 8, 10, 1, 2, 3, 4, time.FixedZone("UTC", 0)),
+		State:               EventStateRunning,
 	})
 	var expectedJSON string = `{` +
 		`"id":3,` +
@@ -95,7 +415,8 @@ func TestSerializeEvent(t *testing.T) {
 		`"simKeySign":"sim_key_signature",` +
 		`"pubKey":"public_key_for_verifying_sim_key",` +
 		`"isDecrypted":true,` +
-		`"lastSynchronization":"2020-08-10T08:02:03+07:00"` +
+		`"lastSynchronization":"2020-08-10T08:02:03+07:00",` +
+		`"state":"running"` +
 		`}`
 	var serialized EventData = SerializeEvent(event)
 	var serializedJSON []byte
@@ -182,18 +503,68 @@ func TestDeserializeEvent(t *testing.T) {
 	}
 }
 
+func TestDeserializeEventClone(t *testing.T) {
+	type deserializeEventCloneTestCase struct {
+		cloneRequestJSON string
+		expectedEvent    Event
+		expectedError    string
+	}
+	testCases := []deserializeEventCloneTestCase{{
+		cloneRequestJSON: `{"slug":"math-final-exam-2","title":"Math Final Exam 2","startsAt":"2021-08-12T09:30:10+07:00","endsAt":"2021-08-12T11:30:10+07:00","includeParticipations":true}`,
+		expectedEvent: Event{
+			Slug:     "math-final-exam-2",
+			Title:    "Math Final Exam 2",
+			StartsAt: time.Date(2021, 8, 12, 9, 30, 10, 0, time.FixedZone("Asia/Jakarta", int((7*time.Hour).Seconds()))),
+			EndsAt:   time.Date(2021, 8, 12, 11, 30, 10, 0, time.FixedZone("Asia/Jakarta", int((7*time.Hour).Seconds()))),
+		},
+	}, {
+		// endsAt is before startsAt
+		cloneRequestJSON: `{"slug":"math-final-exam-2","startsAt":"2021-08-12T09:30:10+07:00","endsAt":"2021-08-12T02:30:09Z"}`,
+		expectedError:    `{"code":"form_error","message":{"_error":[],"endsAt":["End time should be after start time"]}}`,
+	}, {
+		// empty fields
+		cloneRequestJSON: `{}`,
+		expectedError:    `{"code":"form_error","message":{"_error":[],"endsAt":["End time must be provided"],"slug":["Slug can't be empty"],"startsAt":["Start time must be provided"]}}`,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test DeserializeEventClone testcase: %d", i)
+		var event Event
+		var cloneRequest EventCloneRequest
+		var errUnmarshalling error = json.Unmarshal([]byte(testCase.cloneRequestJSON), &cloneRequest)
+		var errDeserialization helios.Error = DeserializeEventClone(cloneRequest, &event)
+		assert.Nil(t, errUnmarshalling)
+		if testCase.expectedError == "" {
+			assert.Nil(t, errDeserialization)
+			assert.Equal(t, testCase.expectedEvent.Slug, event.Slug)
+			assert.Equal(t, testCase.expectedEvent.Title, event.Title)
+			assert.True(t, testCase.expectedEvent.StartsAt.Equal(event.StartsAt))
+			assert.True(t, testCase.expectedEvent.EndsAt.Equal(event.EndsAt))
+		} else {
+			var errDeserializationJSON []byte
+			var errMarshalling error
+			errDeserializationJSON, errMarshalling = json.Marshal(errDeserialization.GetMessage())
+			assert.Nil(t, errMarshalling)
+			assert.NotNil(t, errDeserialization)
+			assert.Equal(t, testCase.expectedError, string(errDeserializationJSON))
+		}
+	}
+}
+
 func TestSerializeParticipation(t *testing.T) {
 	var user auth.User = auth.UserFactory(auth.User{Username: "abc"})
 	var venue Venue = VenueFactory(Venue{ID: 5})
+	var roomID uint = 7
 	var participation Participation = ParticipationFactory(Participation{
 		ID:             3,
 		User:           &user,
 		Venue:          &venue,
+		RoomID:         &roomID,
+		SeatNumber:     12,
 		KeyPlain:       "KeyPlain",
 		KeyHashedOnce:  "KeyHashedOnce",
 		KeyHashedTwice: "KeyHashedTwice",
 	})
-	var expectedJSON string = `{"id":3,"userUsername":"abc","venueId":5,"keyTwice":"KeyHashedTwice"}`
+	var expectedJSON string = `{"id":3,"userUsername":"abc","venueId":5,"roomId":7,"seatNumber":12,"keyTwice":"KeyHashedTwice"}`
 	var serialized ParticipationData = SerializeParticipation(participation)
 	var serializedJSON []byte
 	var errMarshalling error
@@ -203,6 +574,7 @@ func TestSerializeParticipation(t *testing.T) {
 }
 
 func TestDeserializeParticipation(t *testing.T) {
+	var roomID uint = 6
 	type deserializeParticipationTestCase struct {
 		participationDataJSON string
 		expectedParticipation Participation
@@ -219,9 +591,19 @@ func TestDeserializeParticipation(t *testing.T) {
 		expectedParticipation: Participation{
 			VenueID: 4,
 		},
+	}, {
+		participationDataJSON: `{"venueId":4,"roomId":6,"seatNumber":8,"userUsername":"abc"}`,
+		expectedParticipation: Participation{
+			VenueID:    4,
+			RoomID:     &roomID,
+			SeatNumber: 8,
+		},
 	}, {
 		participationDataJSON: `{}`,
 		expectedError:         `{"code":"form_error","message":{"_error":[],"userUsername":["Username can't be empty"],"venueId":["Venue can't be empty"]}}`,
+	}, {
+		participationDataJSON: `{"venueId":4,"seatNumber":8,"userUsername":"abc"}`,
+		expectedError:         `{"code":"form_error","message":{"_error":[],"seatNumber":["Room must be chosen to assign a seat"]}}`,
 	}}
 	for i, testCase := range testCases {
 		t.Logf("Test DeserializeParticipation testcase: %d", i)
@@ -238,6 +620,8 @@ func TestDeserializeParticipation(t *testing.T) {
 			assert.Equal(t, testCase.expectedParticipation.VenueID, participation.VenueID)
 			assert.Equal(t, testCase.expectedParticipation.EventID, participation.EventID)
 			assert.Equal(t, testCase.expectedParticipation.UserID, participation.UserID)
+			assert.Equal(t, testCase.expectedParticipation.RoomID, participation.RoomID)
+			assert.Equal(t, testCase.expectedParticipation.SeatNumber, participation.SeatNumber)
 			assert.Nil(t, participation.Event)
 			assert.Nil(t, participation.User)
 			assert.Nil(t, participation.Venue)
@@ -394,78 +778,84 @@ func TestDeserializeQuestion(t *testing.T) {
 
 func TestSerializeSynchronizationData(t *testing.T) {
 	type serializeSynchronizationDataTestCase struct {
-		event        Event
-		venue        Venue
-		questions    []Question
-		users        []auth.User
-		usersKey     map[string]string
-		usersY       map[string]string
+		payload      SynchronizationPayload
 		expectedJSON string
 	}
 	testCases := []serializeSynchronizationDataTestCase{{
-		event: Event{
-			ID:          3,
-			Slug:        "math-final-exam",
-			Title:       "Math Final Exam",
-			Description: "desc",
-			StartsAt:    time.Date(2020, 8, 12, 9, 30, 10, 0, time.FixedZone("Asia/Jakarta", int((7*time.Hour).Seconds()))),
-			EndsAt:      time.Date(2020, 8, 12, 4, 30, 10, 0, time.FixedZone("UTC", 0)),
-		},
-		venue: Venue{
-			ID:   10,
-			Name: "venue1",
-		},
-		questions: []Question{{
-			ID:         2,
-			Content:    "Question Content",
-			Choices:    "a|b|c",
-			UserAnswer: "answer2",
-		}, {}},
-		users: []auth.User{{
-			ID:       4,
-			Username: "def",
-			Role:     auth.UserRoleAdmin,
-			Name:     "abc",
-			Password: "ghi",
-		}},
-		usersKey: map[string]string{
-			"abc": "def",
-			"ghi": "jkl",
-		},
-		usersY: map[string]string{
-			"abc": "123",
-			"ghi": "456",
+		payload: SynchronizationPayload{
+			Event: Event{
+				ID:          3,
+				Slug:        "math-final-exam",
+				Title:       "Math Final Exam",
+				Description: "desc",
+				StartsAt:    time.Date(2020, 8, 12, 9, 30, 10, 0, time.FixedZone("Asia/Jakarta", int((7*time.Hour).Seconds()))),
+				EndsAt:      time.Date(2020, 8, 12, 4, 30, 10, 0, time.FixedZone("UTC", 0)),
+				State:       EventStateSynced,
+			},
+			Venue: Venue{
+				ID:   10,
+				Name: "venue1",
+			},
+			Rooms: []Room{{
+				ID:       2,
+				Name:     "room1",
+				Capacity: 20,
+			}},
+			Questions: []Question{{
+				ID:         2,
+				Content:    "Question Content",
+				Choices:    "a|b|c",
+				UserAnswer: "answer2",
+			}, {}},
+			Users: []auth.User{{
+				ID:       4,
+				Username: "def",
+				Role:     auth.UserRoleAdmin,
+				Name:     "abc",
+				Password: "ghi",
+			}},
+			Participations: map[string]SynchronizedParticipation{
+				"abc": {KeyHashedTwice: "def", SecretShareY: "123", RoomName: "room1", SeatNumber: 3},
+				"ghi": {KeyHashedTwice: "jkl", SecretShareY: "456"},
+			},
 		},
 		expectedJSON: `{` +
 			`"event":{` +
 			`"id":3,"slug":"math-final-exam","title":"Math Final Exam","description":"desc",` +
 			`"startsAt":"2020-08-12T09:30:10+07:00","endsAt":"2020-08-12T11:30:10+07:00",` +
-			`"simKey":"","simKeySign":"","pubKey":"","isDecrypted":false,"lastSynchronization":""` +
+			`"simKey":"","simKeySign":"","pubKey":"","isDecrypted":false,"lastSynchronization":"",` +
+			`"state":"synced"` +
 			`},` +
 			`"venue":{"id":10,"name":"venue1"},` +
+			`"rooms":[{"id":2,"name":"room1","capacity":20}],` +
 			`"questions":[{"number":2,"content":"Question Content","choices":["a","b","c"],"answer":"answer2"},{"number":0,"content":"","choices":[],"answer":""}],` +
 			`"users":[{"name":"abc","username":"def","role":"admin","password":"ghi"}],` +
 			`"usersKey":{"abc":"def","ghi":"jkl"},` +
-			`"usersY":{"abc":"123","ghi":"456"}` +
+			`"usersY":{"abc":"123","ghi":"456"},` +
+			`"usersRoom":{"abc":"room1"},` +
+			`"usersSeat":{"abc":3}` +
 			`}`,
 	}, {
-		event:     Event{},
-		venue:     Venue{},
-		questions: []Question{},
-		users:     []auth.User{},
-		usersKey:  make(map[string]string),
-		usersY:    make(map[string]string),
+		payload: SynchronizationPayload{
+			Rooms:     []Room{},
+			Questions: []Question{},
+			Users:     []auth.User{},
+		},
 		expectedJSON: `{` +
 			`"event":{` +
 			`"id":0,"slug":"","title":"","description":"",` +
 			`"startsAt":"0001-01-01T07:07:12+07:07","endsAt":"0001-01-01T07:07:12+07:07",` +
-			`"simKey":"","simKeySign":"","pubKey":"","isDecrypted":false,"lastSynchronization":""` +
+			`"simKey":"","simKeySign":"","pubKey":"","isDecrypted":false,"lastSynchronization":"",` +
+			`"state":""` +
 			`},` +
 			`"venue":{"id":0,"name":""},` +
+			`"rooms":[],` +
 			`"questions":[],` +
 			`"users":[],` +
 			`"usersKey":{},` +
-			`"usersY":{}` +
+			`"usersY":{},` +
+			`"usersRoom":{},` +
+			`"usersSeat":{}` +
 			`}`,
 	}}
 	for i, testCase := range testCases {
@@ -473,7 +863,7 @@ func TestSerializeSynchronizationData(t *testing.T) {
 		var serialized SynchronizationData
 		var serializedJSON []byte
 		var errMarshalling error
-		serialized = SerializeSynchronizationData(testCase.event, testCase.venue, testCase.questions, testCase.users, testCase.usersKey, testCase.usersY)
+		serialized = SerializeSynchronizationData(testCase.payload)
 		serializedJSON, errMarshalling = json.Marshal(serialized)
 		assert.Nil(t, errMarshalling)
 		assert.Equal(t, testCase.expectedJSON, string(serializedJSON))
@@ -485,20 +875,23 @@ func TestDeserializeSynchronizationData(t *testing.T) {
 		synchronizationDataJSON string
 		expectedEvent           Event
 		expectedVenue           Venue
+		expectedRoomLength      int
 		expectedQuestionLength  int
 		expectedUserLength      int
-		expectedUsersKey        map[string]string
-		expectedUsersY          map[string]string
+		expectedParticipations  map[string]SynchronizedParticipation
 		expectedError           string
 	}
 	testCases := []deserializeQuestionTestCase{{
 		synchronizationDataJSON: `{` +
 			`"event":{"id":3,"slug":"math-final-exam","title":"Math Final Exam","description":"desc","startsAt":"2020-08-12T09:30:10+07:00","endsAt":"2020-08-12T11:30:10+07:00"},` +
 			`"venue":{"id":10,"name":"venue1"},` +
+			`"rooms":[{"id":2,"name":"room1","capacity":20}],` +
 			`"questions":[{"id":2,"content":"Question Content","choices":["a","b","c"],"answer":"answer2"},{"id":0,"content":"a","choices":[],"answer":""}],` +
 			`"users":[{"name":"abc","username":"def","role":"admin"}],` +
 			`"usersKey":{"user1":"key1","user2":"key2"},` +
-			`"usersY":{"user1":"123","user2":"456"}` +
+			`"usersY":{"user1":"123","user2":"456"},` +
+			`"usersRoom":{"user1":"room1"},` +
+			`"usersSeat":{"user1":4}` +
 			`}`,
 		expectedEvent: Event{
 			ID:          3,
@@ -512,15 +905,12 @@ func TestDeserializeSynchronizationData(t *testing.T) {
 			ID:   10,
 			Name: "venue1",
 		},
+		expectedRoomLength:     1,
 		expectedQuestionLength: 2,
 		expectedUserLength:     1,
-		expectedUsersKey: map[string]string{
-			"user1": "key1",
-			"user2": "key2",
-		},
-		expectedUsersY: map[string]string{
-			"user1": "123",
-			"user2": "456",
+		expectedParticipations: map[string]SynchronizedParticipation{
+			"user1": {KeyHashedTwice: "key1", SecretShareY: "123", RoomName: "room1", SeatNumber: 4},
+			"user2": {KeyHashedTwice: "key2", SecretShareY: "456"},
 		},
 	}, {
 		synchronizationDataJSON: `{"event":{"endsAt":"2020-08-12T11:30:10+07:00","startsAt":"2020-08-12T09:30:10+07:00","title":"abc","slug":"abc"},"venue":{"name":"abc"}}`,
@@ -533,50 +923,48 @@ func TestDeserializeSynchronizationData(t *testing.T) {
 		expectedVenue:          Venue{Name: "abc"},
 		expectedQuestionLength: 0,
 		expectedUserLength:     0,
-		expectedUsersKey:       make(map[string]string),
-		expectedUsersY:         make(map[string]string),
+		expectedParticipations: make(map[string]SynchronizedParticipation),
 	}, {
 		synchronizationDataJSON: `{` +
 			`"event":{"endsAt":"2020-08-12T01:30:10+07:00","startsAt":"2020-08-12T09:30:10+07:00","title":"abc","slug":"abc"},` +
 			`"venue":{},` +
+			`"rooms":[{"name":"room1"}],` +
 			`"questions":[{}],` +
-			`"users":[{"name":"abc","role":"admin","username":"abc"},{"role":"admin","username":"abc"}]` +
+			`"users":[{"name":"abc","role":"admin","username":"abc"},{"role":"admin","username":"abc"}],` +
+			`"usersRoom":{"abc":"room2"}` +
 			`}`,
 		expectedError: `{"code":"form_error","message":{` +
 			`"_error":[],` +
 			`"event":{"endsAt":["End time should be after start time"]},` +
 			`"questions":[{"content":["Content can't be empty"]}],` +
+			`"rooms":[{"capacity":["Capacity must be positive"]}],` +
 			`"users":[{},{"name":["Name can't be empty"]}],` +
+			`"usersRoom":{"abc":["Room doesn't exist"]},` +
 			`"venue":{"name":["Name can't be empty"]}` +
 			`}}`,
 	}}
 	for i, testCase := range testCases {
 		t.Logf("Test DeserializeSynchronizationData testcase: %d", i)
 		var synchronizationData SynchronizationData
-		var event Event
-		var venue Venue
-		var questions []Question
-		var users []auth.User
-		var usersKey map[string]string
-		var usersY map[string]string
+		var payload SynchronizationPayload
 		var errUnmarshalling error
 		var errDeserialization helios.Error
 		errUnmarshalling = json.Unmarshal([]byte(testCase.synchronizationDataJSON), &synchronizationData)
-		errDeserialization = DeserializeSynchronizationData(synchronizationData, &event, &venue, &questions, &users, &usersKey, &usersY)
+		errDeserialization = DeserializeSynchronizationData(synchronizationData, &payload)
 		assert.Nil(t, errUnmarshalling)
 		if testCase.expectedError == "" {
 			assert.Nil(t, errDeserialization)
-			assert.Equal(t, testCase.expectedEvent.ID, event.ID)
-			assert.Equal(t, testCase.expectedEvent.Title, event.Title)
-			assert.Equal(t, testCase.expectedVenue.ID, venue.ID)
-			assert.Equal(t, testCase.expectedVenue.Name, venue.Name)
-			assert.Equal(t, testCase.expectedEvent.Description, event.Description)
-			assert.True(t, testCase.expectedEvent.StartsAt.Equal(event.StartsAt))
-			assert.True(t, testCase.expectedEvent.EndsAt.Equal(event.EndsAt))
-			assert.Equal(t, testCase.expectedQuestionLength, len(questions))
-			assert.Equal(t, testCase.expectedUserLength, len(users))
-			assert.Equal(t, testCase.expectedUsersKey, usersKey)
-			assert.Equal(t, testCase.expectedUsersY, usersY)
+			assert.Equal(t, testCase.expectedEvent.ID, payload.Event.ID)
+			assert.Equal(t, testCase.expectedEvent.Title, payload.Event.Title)
+			assert.Equal(t, testCase.expectedVenue.ID, payload.Venue.ID)
+			assert.Equal(t, testCase.expectedVenue.Name, payload.Venue.Name)
+			assert.Equal(t, testCase.expectedEvent.Description, payload.Event.Description)
+			assert.True(t, testCase.expectedEvent.StartsAt.Equal(payload.Event.StartsAt))
+			assert.True(t, testCase.expectedEvent.EndsAt.Equal(payload.Event.EndsAt))
+			assert.Equal(t, testCase.expectedRoomLength, len(payload.Rooms))
+			assert.Equal(t, testCase.expectedQuestionLength, len(payload.Questions))
+			assert.Equal(t, testCase.expectedUserLength, len(payload.Users))
+			assert.Equal(t, testCase.expectedParticipations, payload.Participations)
 		} else {
 			var errDeserializationJSON []byte
 			var errMarshalling error
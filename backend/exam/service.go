@@ -16,102 +16,249 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jinzhu/gorm"
 	"github.com/yonasadiel/charon/backend/auth"
+	"github.com/yonasadiel/charon/backend/logging"
 	"github.com/yonasadiel/helios"
 )
 
 // GetAllVenue returns all venues.
-// Only admin and organizer have permission for this use case.
+// Only user permitted to manage venues can access this use case.
 func GetAllVenue(user auth.User) ([]Venue, helios.Error) {
-	if !user.IsAdmin() && !user.IsOrganizer() {
+	if !auth.Can(user, auth.ActionVenueManage, auth.Resource{}) {
 		return nil, errVenueAccessNotAuthorized
 	}
 
 	var venues []Venue
-	helios.DB.Find(&venues)
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Find(&venues)); errDB != nil {
+		return nil, errDB
+	}
 	return venues, nil
 }
 
 // UpsertVenue creates or updates a venue. It creates if
-// ID = 0, or updates otherwise. Only user with organizer or
-// admin role that can creates / updates venue.
+// ID = 0, or updates otherwise. Only user permitted to
+// manage venues that can creates / updates venue.
 // If it is create, then venue.ID will be changed.
 func UpsertVenue(user auth.User, venue *Venue) helios.Error {
-	if !user.IsAdmin() && !user.IsOrganizer() {
+	if !auth.Can(user, auth.ActionVenueManage, auth.Resource{}) {
 		return errVenueAccessNotAuthorized
 	}
 
+	tx := helios.DB.Begin()
+	var errDB helios.Error
 	if venue.ID == 0 {
-		helios.DB.Create(venue)
+		errDB = logging.CheckDB(user.RequestID, tx.Create(venue))
+		if errDB == nil {
+			errDB = auth.RecordAuditLog(tx, user, auditActionVenueCreate, auditTargetVenue, venue.ID, nil, *venue)
+		}
 	} else {
-		helios.DB.Save(venue)
+		var venueSaved Venue
+		errDB = logging.CheckDB(user.RequestID, tx.Where("id = ?", venue.ID).First(&venueSaved))
+		if errDB == nil {
+			errDB = logging.CheckDB(user.RequestID, tx.Save(venue))
+		}
+		if errDB == nil {
+			errDB = auth.RecordAuditLog(tx, user, auditActionVenueUpdate, auditTargetVenue, venue.ID, venueSaved, *venue)
+		}
 	}
-	return nil
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	return logging.CheckDB(user.RequestID, tx.Commit())
 }
 
 // DeleteVenue deletes a venue with given id
-// and returns the deleted venue. Only organizer and
-// admin that can do deletion. If there is an event
+// and returns the deleted venue. Only user permitted to
+// manage venues that can do deletion. If there is an event
 // organized on the venue, it will fail
 func DeleteVenue(user auth.User, venueID uint) (*Venue, helios.Error) {
-	if !user.IsOrganizer() && !user.IsAdmin() {
+	if !auth.Can(user, auth.ActionVenueManage, auth.Resource{}) {
 		return nil, errVenueAccessNotAuthorized
 	}
 
 	var venue Venue
 	var participationCount int
 
-	helios.DB.Where("id = ?", venueID).First(&venue)
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("id = ?", venueID).First(&venue)); errDB != nil {
+		return nil, errDB
+	}
 	if venue.ID == 0 {
 		return nil, errVenueNotFound
 	}
 
-	helios.DB.Model(&Participation{}).Where("venue_id = ?", venue.ID).Count(&participationCount)
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Model(&Participation{}).Where("venue_id = ?", venue.ID).Count(&participationCount)); errDB != nil {
+		return nil, errDB
+	}
 	if participationCount > 0 {
 		return nil, errVenueCantDeletedEventExists
 	}
 
-	helios.DB.Delete(&venue)
+	tx := helios.DB.Begin()
+	errDB := logging.CheckDB(user.RequestID, tx.Where("venue_id = ?", venue.ID).Delete(Room{}))
+	if errDB == nil {
+		errDB = logging.CheckDB(user.RequestID, tx.Delete(&venue))
+	}
+	if errDB == nil {
+		errDB = auth.RecordAuditLog(tx, user, auditActionVenueDelete, auditTargetVenue, venue.ID, venue, nil)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return nil, errDB
+	}
+	if errDB = logging.CheckDB(user.RequestID, tx.Commit()); errDB != nil {
+		return nil, errDB
+	}
 	return &venue, nil
 }
 
-// GetAllEventOfUser returns all events that is participated by user.
-// If the user is admin or organizer, then return all events that are exist.
-func GetAllEventOfUser(user auth.User) []Event {
-	var events []Event
+// GetAllRoomOfVenue returns all rooms of the venue.
+// Only user permitted to manage venues can access this use case.
+func GetAllRoomOfVenue(user auth.User, venueID uint) ([]Room, helios.Error) {
+	if !auth.Can(user, auth.ActionVenueManage, auth.Resource{}) {
+		return nil, errVenueAccessNotAuthorized
+	}
 
-	if user.IsAdmin() || user.IsOrganizer() {
-		helios.DB.
-			Order("events.starts_at asc").
-			Find(&events)
-	} else { // user is local or participant
-		helios.DB.
-			Select("events.*").
-			Table("events").
-			Joins("inner join participations on participations.event_id = events.id").
-			Where("user_id = ?", user.ID).
-			Order("events.starts_at asc").
-			Find(&events)
+	var venue Venue
+	var rooms []Room
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("id = ?", venueID).First(&venue)); errDB != nil {
+		return nil, errDB
+	}
+	if venue.ID == 0 {
+		return nil, errVenueNotFound
+	}
+
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("venue_id = ?", venue.ID).Order("id asc").Find(&rooms)); errDB != nil {
+		return nil, errDB
+	}
+	return rooms, nil
+}
+
+// UpsertRoom creates or updates a room of the venue. It creates if
+// ID = 0, or updates otherwise. Only user permitted to manage venues
+// that can creates / updates room. The capacity can't be reduced below
+// the highest seat number that is already assigned.
+func UpsertRoom(user auth.User, venueID uint, room *Room) helios.Error {
+	if !auth.Can(user, auth.ActionVenueManage, auth.Resource{}) {
+		return errVenueAccessNotAuthorized
+	}
+
+	var venue Venue
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("id = ?", venueID).First(&venue)); errDB != nil {
+		return errDB
+	}
+	if venue.ID == 0 {
+		return errVenueNotFound
+	}
+
+	room.VenueID = venue.ID
+	room.Venue = &venue
+	if room.ID == 0 {
+		tx := helios.DB.Begin()
+		errDB := logging.CheckDB(user.RequestID, tx.Create(room))
+		if errDB == nil {
+			errDB = auth.RecordAuditLog(tx, user, auditActionRoomCreate, auditTargetRoom, room.ID, nil, *room)
+		}
+		if errDB != nil {
+			tx.Rollback()
+			return errDB
+		}
+		return logging.CheckDB(user.RequestID, tx.Commit())
+	} else {
+		var roomSaved Room
+		var seatedCount int
+		if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("id = ?", room.ID).Where("venue_id = ?", venue.ID).First(&roomSaved)); errDB != nil {
+			return errDB
+		}
+		if roomSaved.ID == 0 {
+			return errRoomNotFound
+		}
+		if errDB := logging.CheckDB(user.RequestID, helios.DB.Model(&Participation{}).Where("room_id = ?", room.ID).Where("seat_number > ?", room.Capacity).Count(&seatedCount)); errDB != nil {
+			return errDB
+		}
+		if seatedCount > 0 {
+			return errRoomCapacityTooSmall
+		}
+		tx := helios.DB.Begin()
+		errDB := logging.CheckDB(user.RequestID, tx.Save(room))
+		if errDB == nil {
+			errDB = auth.RecordAuditLog(tx, user, auditActionRoomUpdate, auditTargetRoom, room.ID, roomSaved, *room)
+		}
+		if errDB != nil {
+			tx.Rollback()
+			return errDB
+		}
+		return logging.CheckDB(user.RequestID, tx.Commit())
+	}
+}
+
+// DeleteRoom deletes a room with given id and returns the deleted room.
+// Only user permitted to manage venues that can do deletion. If there is any
+// participation seated in the room, it will fail
+func DeleteRoom(user auth.User, venueID uint, roomID uint) (*Room, helios.Error) {
+	if !auth.Can(user, auth.ActionVenueManage, auth.Resource{}) {
+		return nil, errVenueAccessNotAuthorized
+	}
+
+	var room Room
+	var participationCount int
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("id = ?", roomID).Where("venue_id = ?", venueID).First(&room)); errDB != nil {
+		return nil, errDB
+	}
+	if room.ID == 0 {
+		return nil, errRoomNotFound
+	}
+
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Model(&Participation{}).Where("room_id = ?", room.ID).Count(&participationCount)); errDB != nil {
+		return nil, errDB
+	}
+	if participationCount > 0 {
+		return nil, errRoomCantDeletedParticipationExists
+	}
+
+	tx := helios.DB.Begin()
+	errDB := logging.CheckDB(user.RequestID, tx.Delete(&room))
+	if errDB == nil {
+		errDB = auth.RecordAuditLog(tx, user, auditActionRoomDelete, auditTargetRoom, room.ID, room, nil)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return nil, errDB
 	}
+	if errDB = logging.CheckDB(user.RequestID, tx.Commit()); errDB != nil {
+		return nil, errDB
+	}
+	return &room, nil
+}
+
+// GetAllEventOfUser returns all events that is participated by user or
+// assigned to the user by event role. If the user is permitted to view
+// all events, then return all events that are exist.
+func GetAllEventOfUser(user auth.User) ([]Event, helios.Error) {
+	var events []Event
 
-	return events
+	var query *gorm.DB = helios.DB
+	if !auth.Can(user, auth.ActionEventView, auth.Resource{}) {
+		query = query.Where("id in ? or id in ?", participatedEventIDs(user), assignedEventIDs(user))
+	}
+	if errDB := logging.CheckDB(user.RequestID, query.Order("events.starts_at asc").Find(&events)); errDB != nil {
+		return nil, errDB
+	}
+	return events, nil
 }
 
-// GetEventOfUser returns the event if exist
-// If the user is local or participant, the permission will be checked.
+// GetEventOfUser returns the event if exist. If the user is not permitted
+// to view all events, the user should participate or be assigned to the event.
 func GetEventOfUser(user auth.User, eventSlug string) (Event, helios.Error) {
 	var event Event
 
-	if user.IsAdmin() || user.IsOrganizer() {
-		helios.DB.Where("slug = ?", eventSlug).First(&event)
-	} else {
-		helios.DB.
-			Table("events").
-			Select("events.*").
-			Joins("inner join participations on participations.event_id = events.id").
-			Where("participations.user_id = ?", user.ID).
-			Where("events.slug = ?", eventSlug).
-			First(&event)
+	var query *gorm.DB = helios.DB.Where("slug = ?", eventSlug)
+	if !auth.Can(user, auth.ActionEventView, auth.Resource{}) {
+		query = query.Where("id in ? or id in ?", participatedEventIDs(user), assignedEventIDs(user))
+	}
+	if errDB := logging.CheckDB(user.RequestID, query.First(&event)); errDB != nil {
+		return event, errDB
 	}
 	if event.ID == 0 {
 		return event, errEventNotFound
@@ -119,138 +266,1610 @@ func GetEventOfUser(user auth.User, eventSlug string) (Event, helios.Error) {
 	return event, nil
 }
 
-// UpsertEvent creates or updates an exam event. It creates if
-// ID = 0, or updates otherwise. Only user with organizer or
-// admin role that can creates / updates event.
-// If it is create, then event.ID will be changed.
-func UpsertEvent(user auth.User, event *Event) helios.Error {
-	if !user.IsOrganizer() && !user.IsAdmin() && !user.IsLocal() {
-		return errEventChangeNotAuthorized
+// GetEventIDBySlug returns the ID of the event with given slug, or zero if
+// there is no such event. It doesn't check the permission of any user, it is
+// used to check the scopes of API tokens, so the token is out of scope if
+// the event can't be read.
+func GetEventIDBySlug(eventSlug string) uint {
+	var event Event
+	logging.CheckDB("", helios.DB.Select("id").Where("slug = ?", eventSlug).First(&event))
+	return event.ID
+}
+
+// participatedEventIDs returns subquery of event ids that the user participates
+func participatedEventIDs(user auth.User) *gorm.SqlExpr {
+	return helios.DB.Model(&Participation{}).Select("event_id").Where("user_id = ?", user.ID).SubQuery()
+}
+
+// assignedEventIDs returns subquery of event ids that the user is assigned to by event role
+func assignedEventIDs(user auth.User) *gorm.SqlExpr {
+	return helios.DB.Model(&auth.EventRole{}).Select("event_id").Where("user_id = ?", user.ID).SubQuery()
+}
+
+// UpsertEvent creates or updates an exam event. It creates if
+// ID = 0, or updates otherwise. The creator is assigned as the
+// author of the event, while local user participates on the event.
+// Updating requires permission to edit the event.
+// If it is create, then event.ID will be changed.
+func UpsertEvent(user auth.User, event *Event) helios.Error {
+	if event.ID == 0 && !auth.Can(user, auth.ActionEventCreate, auth.Resource{}) {
+		return errEventChangeNotAuthorized
+	}
+	if event.ID != 0 && !auth.Can(user, auth.ActionEventEdit, auth.Resource{EventID: event.ID}) {
+		return errEventChangeNotAuthorized
+	}
+
+	if event.ID == 0 {
+		if user.IsAdmin() || user.IsOrganizer() {
+			if errKeys := logging.CheckError(user.RequestID, generateEventKeys(event)); errKeys != nil {
+				return errKeys
+			}
+		}
+		event.State = EventStateDraft
+		tx := helios.DB.Begin()
+		errDB := logging.CheckDB(user.RequestID, tx.Omit("last_synchronization").Create(event))
+		if errDB == nil && user.IsLocal() {
+			errDB = logging.CheckDB(user.RequestID, tx.Create(&Participation{
+				UserID:  user.ID,
+				EventID: event.ID,
+			}))
+		} else if errDB == nil {
+			errDB = logging.CheckError(user.RequestID, auth.AssignEventRole(tx, user.ID, event.ID, 0, auth.EventRoleAuthor))
+		}
+		if errDB == nil {
+			errDB = auth.RecordAuditLog(tx, user, auditActionEventCreate, auditTargetEvent, event.ID, nil, *event)
+		}
+		if errDB != nil {
+			tx.Rollback()
+			return errDB
+		}
+		return logging.CheckDB(user.RequestID, tx.Commit())
+	}
+
+	var eventBefore, eventAfter Event
+	tx := helios.DB.Begin()
+	errDB := logging.CheckDB(user.RequestID, tx.Where("id = ?", event.ID).First(&eventBefore))
+	if errDB == nil {
+		errDB = logging.CheckDB(user.RequestID, tx.Omit("last_synchronization", "sim_key", "sim_key_sign", "prv_key", "pub_key", "decrypted_at", "state").Save(event))
+	}
+	if errDB == nil {
+		errDB = logging.CheckDB(user.RequestID, tx.Where("id = ?", event.ID).First(&eventAfter))
+	}
+	if errDB == nil {
+		errDB = auth.RecordAuditLog(tx, user, auditActionEventUpdate, auditTargetEvent, event.ID, eventBefore, eventAfter)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	return logging.CheckDB(user.RequestID, tx.Commit())
+}
+
+// UpdateEvent updates the event with the given slug. The event that has been
+// synchronized or decrypted is refused, because the copies on local servers
+// would be different, unless it is forced by user permitted to force the edit.
+// The keys, the state and the synchronization time of the event are kept.
+func UpdateEvent(user auth.User, eventSlug string, event *Event, force bool) helios.Error {
+	var eventSaved Event
+	var errGetEvent helios.Error
+	eventSaved, errGetEvent = GetEventOfUser(user, eventSlug)
+	if errGetEvent != nil {
+		return errGetEvent
+	}
+	if !auth.Can(user, auth.ActionEventEdit, auth.Resource{EventID: eventSaved.ID}) {
+		return errEventChangeNotAuthorized
+	}
+	if isEventLocked(eventSaved) && !(force && auth.Can(user, auth.ActionEventForceEdit, auth.Resource{EventID: eventSaved.ID})) {
+		return errEventLocked
+	}
+	if event.Slug != eventSaved.Slug {
+		var slugCount int
+		if errDB := logging.CheckDB(user.RequestID, helios.DB.Unscoped().Model(&Event{}).Where("slug = ?", event.Slug).Count(&slugCount)); errDB != nil {
+			return errDB
+		}
+		if slugCount > 0 {
+			return errEventSlugTaken
+		}
+	}
+
+	event.ID = eventSaved.ID
+	event.CreatedAt = eventSaved.CreatedAt
+	var errUpsert helios.Error = UpsertEvent(user, event)
+	if errUpsert != nil {
+		return errUpsert
+	}
+	return logging.CheckDB(user.RequestID, helios.DB.Where("id = ?", event.ID).First(event))
+}
+
+// EventDeleteHook deletes the records of the event that are kept by the other
+// packages, on the transaction that deletes the event
+type EventDeleteHook func(tx *gorm.DB, event Event) error
+
+var eventDeleteHooks []EventDeleteHook
+
+// RegisterEventDeleteHook registers the hook that is run by DeleteEvent, so the
+// packages that depend on exam, e.g. announcement, don't leave their records of
+// the event behind
+func RegisterEventDeleteHook(hook EventDeleteHook) {
+	eventDeleteHooks = append(eventDeleteHooks, hook)
+}
+
+// DeleteEvent deletes the event with the given slug, together with its
+// questions, answers, participations, secret shares, role assignments, API
+// token scopes and the records of the registered EventDeleteHook, and returns
+// the deleted event. The slug of the deleted event is renamed by deletedEventSlug,
+// so a new event can take the slug. The same as updating, the event that has been synchronized
+// or decrypted can only be deleted if forced by user permitted to force the edit.
+func DeleteEvent(user auth.User, eventSlug string, force bool) (*Event, helios.Error) {
+	var event Event
+	var errGetEvent helios.Error
+	event, errGetEvent = GetEventOfUser(user, eventSlug)
+	if errGetEvent != nil {
+		return nil, errGetEvent
+	}
+	if !auth.Can(user, auth.ActionEventEdit, auth.Resource{EventID: event.ID}) {
+		return nil, errEventChangeNotAuthorized
+	}
+	if isEventLocked(event) && !(force && auth.Can(user, auth.ActionEventForceEdit, auth.Resource{EventID: event.ID})) {
+		return nil, errEventLocked
+	}
+
+	tx := helios.DB.Begin()
+	var errDelete error = tx.
+		Where("question_id in (?)", tx.Table("questions").Select("id").Where("event_id = ?", event.ID).SubQuery()).
+		Or("participation_id in (?)", tx.Table("participations").Select("id").Where("event_id = ?", event.ID).SubQuery()).
+		Delete(UserQuestion{}).Error
+	if errDelete == nil {
+		errDelete = tx.Where("event_id = ?", event.ID).Delete(Question{}).Error
+	}
+	if errDelete == nil {
+		errDelete = deleteParticipations(tx.Where("event_id = ?", event.ID))
+	}
+	if errDelete == nil {
+		errDelete = tx.Where("event_id = ?", event.ID).Delete(SecretShare{}).Error
+	}
+	if errDelete == nil {
+		errDelete = tx.Where("event_id = ?", event.ID).Delete(auth.EventRole{}).Error
+	}
+	if errDelete == nil {
+		errDelete = tx.Where("event_id = ?", event.ID).Delete(auth.APITokenScope{}).Error
+	}
+	for _, hook := range eventDeleteHooks {
+		if errDelete != nil {
+			break
+		}
+		errDelete = hook(tx, event)
+	}
+	if errDelete == nil {
+		errDelete = tx.Model(&Event{}).Where("id = ?", event.ID).UpdateColumn("slug", deletedEventSlug(event)).Error
+	}
+	if errDelete == nil {
+		errDelete = tx.Delete(&event).Error
+	}
+	if errDelete != nil {
+		tx.Rollback()
+		return nil, logging.CheckError(user.RequestID, errDelete)
+	}
+	if errDB := auth.RecordAuditLog(tx, user, auditActionEventDelete, auditTargetEvent, event.ID, event, nil); errDB != nil {
+		tx.Rollback()
+		return nil, errDB
+	}
+	if errDB := logging.CheckDB(user.RequestID, tx.Commit()); errDB != nil {
+		return nil, errDB
+	}
+	return &event, nil
+}
+
+// deletedEventSlug returns the slug that the deleted event is renamed to. The
+// slug column is unique including the deleted events, and the ID keeps the
+// renamed slugs apart.
+func deletedEventSlug(event Event) string {
+	const slugSize = 100 // size of the slug column
+	var suffix string = fmt.Sprintf("~deleted-%d", event.ID)
+	var slug string = event.Slug
+	if len(slug)+len(suffix) > slugSize {
+		slug = slug[:slugSize-len(suffix)]
+	}
+	return slug + suffix
+}
+
+// isEventLocked returns true if the event has been synchronized to local
+// servers or decrypted
+func isEventLocked(event Event) bool {
+	return checkEventState(event, participationEditableStates) != nil ||
+		!event.LastSynchronization.IsZero() ||
+		!event.DecryptedAt.IsZero()
+}
+
+// generateEventKeys generates new SimKey for encrypting the questions, and the
+// key pair for verifying the SimKey on decryption
+func generateEventKeys(event *Event) error {
+	var err error
+	var prvKey *rsa.PrivateKey
+	var simKeySign []byte
+	prvKey, err = rsa.GenerateKey(rand.Reader, EventKeyBits)
+	if err != nil {
+		return err
+	}
+	event.SimKey = generateRandomToken(32)
+	event.PrvKey = base64.StdEncoding.EncodeToString(x509.MarshalPKCS1PrivateKey(prvKey))
+	event.PubKey = base64.StdEncoding.EncodeToString(x509.MarshalPKCS1PublicKey(&prvKey.PublicKey))
+	simKeyHashed := sha256.Sum256([]byte(event.SimKey))
+	simKeySign, err = rsa.SignPSS(rand.Reader, prvKey, crypto.SHA256, simKeyHashed[:], nil)
+	if err != nil {
+		return err
+	}
+	event.SimKeySign = base64.StdEncoding.EncodeToString(simKeySign)
+	return nil
+}
+
+// CheckEventKeys returns error if the key of any event that is not archived
+// can't be loaded. The public key is required to verify the SimKey on
+// decryption, while the private key is only kept by the central server.
+func CheckEventKeys() error {
+	var events []Event
+	err := helios.DB.
+		Select("slug, prv_key, pub_key").
+		Where("state <> ?", EventStateArchived).
+		Find(&events).Error
+	if err != nil {
+		return fmt.Errorf("failed to read events: %v", err)
+	}
+	for _, event := range events {
+		pubKeyMarshalled, err := base64.StdEncoding.DecodeString(event.PubKey)
+		if err == nil {
+			_, err = x509.ParsePKCS1PublicKey(pubKeyMarshalled)
+		}
+		if err != nil {
+			return fmt.Errorf("public key of event %s can't be loaded", event.Slug)
+		}
+		if event.PrvKey == "" {
+			continue
+		}
+		prvKeyMarshalled, err := base64.StdEncoding.DecodeString(event.PrvKey)
+		if err == nil {
+			_, err = x509.ParsePKCS1PrivateKey(prvKeyMarshalled)
+		}
+		if err != nil {
+			return fmt.Errorf("private key of event %s can't be loaded", event.Slug)
+		}
+	}
+	return nil
+}
+
+// CloneEvent creates a copy of the event with the slug, title and time of
+// the clone. The questions and event-wide role assignments are copied, and
+// the participations are copied if includeParticipations is true, each with
+// new participation key. The seats and the role assignments on venues are
+// copied if includeVenueAssignments is true. The clone gets new keys the same
+// way as creating an event, so keys are never shared between events. Requires
+// permission to create events and to edit the source event.
+func CloneEvent(user auth.User, eventSlug string, clone *Event, includeParticipations bool, includeVenueAssignments bool) helios.Error {
+	var source Event
+	var errGetEvent helios.Error
+	source, errGetEvent = GetEventOfUser(user, eventSlug)
+	if errGetEvent != nil {
+		return errGetEvent
+	}
+	if !auth.Can(user, auth.ActionEventCreate, auth.Resource{}) || !auth.Can(user, auth.ActionEventEdit, auth.Resource{EventID: source.ID}) {
+		return errEventChangeNotAuthorized
+	}
+
+	var slugCount int
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Unscoped().Model(&Event{}).Where("slug = ?", clone.Slug).Count(&slugCount)); errDB != nil {
+		return errDB
+	}
+	if slugCount > 0 {
+		return errEventSlugTaken
+	}
+
+	clone.ID = 0
+	clone.Description = source.Description
+	if clone.Title == "" {
+		clone.Title = source.Title
+	}
+	clone.SimKey, clone.SimKeySign, clone.PrvKey, clone.PubKey = "", "", "", ""
+	if user.IsAdmin() || user.IsOrganizer() {
+		if errKeys := logging.CheckError(user.RequestID, generateEventKeys(clone)); errKeys != nil {
+			return errKeys
+		}
+	}
+	clone.DecryptedAt = time.Time{}
+	clone.LastSynchronization = time.Time{}
+	clone.State = EventStateDraft
+
+	var questions []Question
+	var eventRoles []auth.EventRole
+	var participations []Participation
+	var eventRoleQuery *gorm.DB = helios.DB.Where("event_id = ?", source.ID)
+	if !includeVenueAssignments {
+		eventRoleQuery = eventRoleQuery.Where("venue_id = 0")
+	}
+	errDB := logging.CheckDB(user.RequestID, helios.DB.Where("event_id = ?", source.ID).Order("id asc").Find(&questions))
+	if errDB == nil {
+		errDB = logging.CheckDB(user.RequestID, eventRoleQuery.Order("id asc").Find(&eventRoles))
+	}
+	if errDB == nil && includeParticipations {
+		errDB = logging.CheckDB(user.RequestID, helios.DB.Preload("User").Where("event_id = ?", source.ID).Order("id asc").Find(&participations))
+	}
+	if errDB != nil {
+		return errDB
+	}
+
+	tx := helios.DB.Begin()
+	if errDB = logging.CheckDB(user.RequestID, tx.Omit("last_synchronization").Create(clone)); errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	for _, question := range questions {
+		var questionCloned Question = Question{
+			EventID: clone.ID,
+			Content: question.Content,
+			Choices: question.Choices,
+		}
+		if errDB = logging.CheckDB(user.RequestID, tx.Create(&questionCloned)); errDB != nil {
+			tx.Rollback()
+			return errDB
+		}
+	}
+	for _, eventRole := range eventRoles {
+		if errDB = logging.CheckError(user.RequestID, auth.AssignEventRole(tx, eventRole.UserID, clone.ID, eventRole.VenueID, eventRole.Role)); errDB != nil {
+			tx.Rollback()
+			return errDB
+		}
+	}
+	var isParticipating bool = false
+	for _, participation := range participations {
+		var errGenerate error
+		var participationCloned Participation = Participation{
+			EventID: clone.ID,
+			UserID:  participation.UserID,
+			VenueID: participation.VenueID,
+		}
+		if includeVenueAssignments {
+			participationCloned.RoomID = participation.RoomID
+			participationCloned.SeatNumber = participation.SeatNumber
+		}
+		participationCloned.KeyPlain, errGenerate = generateSecureToken(participationKeyLength, tokenBytes)
+		if errDB = logging.CheckError(user.RequestID, errGenerate); errDB != nil {
+			tx.Rollback()
+			return errDB
+		}
+		participationCloned.KeyHashedOnce = fmt.Sprintf("%x", sha256.Sum256([]byte(participationCloned.KeyPlain)))
+		participationCloned.KeyHashedTwice = fmt.Sprintf("%x", sha256.Sum256([]byte(participationCloned.KeyHashedOnce)))
+		errDB = logging.CheckDB(user.RequestID, tx.Create(&participationCloned))
+		if errDB == nil && participation.User != nil && participation.User.IsLocal() {
+			errDB = logging.CheckError(user.RequestID, assignLocalEventRoles(tx, participation.UserID, clone.ID, participation.VenueID))
+		}
+		if errDB != nil {
+			tx.Rollback()
+			return errDB
+		}
+		isParticipating = isParticipating || participation.UserID == user.ID
+	}
+	if user.IsLocal() && !isParticipating {
+		errDB = logging.CheckDB(user.RequestID, tx.Create(&Participation{UserID: user.ID, EventID: clone.ID}))
+	} else if !user.IsLocal() {
+		errDB = logging.CheckError(user.RequestID, auth.AssignEventRole(tx, user.ID, clone.ID, 0, auth.EventRoleAuthor))
+	}
+	if errDB == nil {
+		errDB = auth.RecordAuditLog(tx, user, auditActionEventClone, auditTargetEvent, clone.ID, nil, map[string]interface{}{
+			"SourceEventID":  source.ID,
+			"Slug":           clone.Slug,
+			"Questions":      len(questions),
+			"EventRoles":     len(eventRoles),
+			"Participations": len(participations),
+		})
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	return logging.CheckDB(user.RequestID, tx.Commit())
+}
+
+// TransitionEvent moves the event to the given state. Only the transitions
+// listed in eventStateTransitions are allowed, and each of them requires its
+// own permission on the event.
+func TransitionEvent(user auth.User, eventSlug string, state string) (*Event, helios.Error) {
+	var event Event
+	var errGetEvent helios.Error
+	event, errGetEvent = GetEventOfUser(user, eventSlug)
+	if errGetEvent != nil {
+		return nil, errGetEvent
+	}
+
+	action, ok := eventStateTransitions[event.State][state]
+	if !ok {
+		return nil, errEventTransitionInvalid
+	}
+	if !auth.Can(user, action, auth.Resource{EventID: event.ID}) {
+		return nil, errEventTransitionNotAuthorized
+	}
+
+	var stateBefore string = event.State
+	event.State = state
+	tx := helios.DB.Begin()
+	errDB := logging.CheckDB(user.RequestID, tx.Model(&event).Update("state", state))
+	if errDB == nil {
+		errDB = auth.RecordAuditLog(tx, user, auditActionEventTransition, auditTargetEvent, event.ID, map[string]interface{}{"State": stateBefore}, map[string]interface{}{"State": state})
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return nil, errDB
+	}
+	if errDB = logging.CheckDB(user.RequestID, tx.Commit()); errDB != nil {
+		return nil, errDB
+	}
+	return &event, nil
+}
+
+// checkEventState returns error if the event is not in one of the states
+func checkEventState(event Event, states []string) helios.Error {
+	for _, state := range states {
+		if event.State == state {
+			return nil
+		}
+	}
+	return errEventStateInvalid
+}
+
+// GetAllEventRoleOfEvent returns all role assignments on the event.
+// Only user permitted to manage roles on the event has the permission.
+func GetAllEventRoleOfEvent(user auth.User, eventSlug string) ([]auth.EventRole, helios.Error) {
+	var event Event
+	var eventRoles []auth.EventRole
+	var errGetEvent helios.Error
+	event, errGetEvent = GetEventOfUser(user, eventSlug)
+	if errGetEvent != nil {
+		return nil, errGetEvent
+	}
+	if !auth.Can(user, auth.ActionEventRoleManage, auth.Resource{EventID: event.ID}) {
+		return nil, errEventRoleManageNotAuthorized
+	}
+
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Preload("User").Where("event_id = ?", event.ID).Order("id asc").Find(&eventRoles)); errDB != nil {
+		return nil, errDB
+	}
+	return eventRoles, nil
+}
+
+// AssignEventRole assigns the user with given username to the role on the event.
+// Participant can't be assigned to any role. If the same assignment has already
+// existed, eventRole is filled with the existing one.
+func AssignEventRole(user auth.User, eventSlug string, userUsername string, eventRole *auth.EventRole) helios.Error {
+	var event Event
+	var assignedUser auth.User
+	var errGetEvent helios.Error
+	event, errGetEvent = GetEventOfUser(user, eventSlug)
+	if errGetEvent != nil {
+		return errGetEvent
+	}
+	if !auth.Can(user, auth.ActionEventRoleManage, auth.Resource{EventID: event.ID}) {
+		return errEventRoleManageNotAuthorized
+	}
+
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("username = ?", userUsername).First(&assignedUser)); errDB != nil {
+		return errDB
+	}
+	if assignedUser.ID == 0 {
+		return errUserNotFound
+	} else if assignedUser.IsParticipant() {
+		return errEventRoleNotAssignable
+	}
+	if eventRole.VenueID != 0 {
+		var venue Venue
+		if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("id = ?", eventRole.VenueID).First(&venue)); errDB != nil {
+			return errDB
+		}
+		if venue.ID == 0 {
+			return errVenueNotFound
+		}
+	}
+
+	tx := helios.DB.Begin()
+	errDB := logging.CheckError(user.RequestID, auth.AssignEventRole(tx, assignedUser.ID, event.ID, eventRole.VenueID, eventRole.Role))
+	if errDB == nil {
+		errDB = logging.CheckDB(user.RequestID, tx.
+			Where("user_id = ?", assignedUser.ID).
+			Where("event_id = ?", event.ID).
+			Where("venue_id = ?", eventRole.VenueID).
+			Where("role = ?", eventRole.Role).
+			First(eventRole))
+	}
+	if errDB == nil {
+		eventRole.User = &assignedUser
+		errDB = auth.RecordAuditLog(tx, user, auditActionEventRoleAssign, auditTargetEventRole, eventRole.ID, nil, *eventRole)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	return logging.CheckDB(user.RequestID, tx.Commit())
+}
+
+// RevokeEventRole deletes the role assignment with given id on the event
+// and returns the deleted assignment.
+func RevokeEventRole(user auth.User, eventSlug string, eventRoleID uint) (*auth.EventRole, helios.Error) {
+	var event Event
+	var eventRole auth.EventRole
+	var errGetEvent helios.Error
+	event, errGetEvent = GetEventOfUser(user, eventSlug)
+	if errGetEvent != nil {
+		return nil, errGetEvent
+	}
+	if !auth.Can(user, auth.ActionEventRoleManage, auth.Resource{EventID: event.ID}) {
+		return nil, errEventRoleManageNotAuthorized
+	}
+
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Preload("User").Where("id = ?", eventRoleID).Where("event_id = ?", event.ID).First(&eventRole)); errDB != nil {
+		return nil, errDB
+	}
+	if eventRole.ID == 0 {
+		return nil, errEventRoleNotFound
+	}
+	tx := helios.DB.Begin()
+	errDB := logging.CheckDB(user.RequestID, tx.Delete(&eventRole))
+	if errDB == nil {
+		errDB = auth.RecordAuditLog(tx, user, auditActionEventRoleRevoke, auditTargetEventRole, eventRole.ID, eventRole, nil)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return nil, errDB
+	}
+	if errDB = logging.CheckDB(user.RequestID, tx.Commit()); errDB != nil {
+		return nil, errDB
+	}
+	return &eventRole, nil
+}
+
+// GetAllParticipationOfUserAndEvent returns all participations of the event
+// on the venues that the user is permitted to view, and the participation of
+// the user itself.
+func GetAllParticipationOfUserAndEvent(user auth.User, eventSlug string) ([]Participation, helios.Error) {
+	var event Event
+	var participations []Participation
+	var errGetEvent helios.Error
+
+	event, errGetEvent = GetEventOfUser(user, eventSlug)
+	if errGetEvent != nil {
+		return nil, errGetEvent
+	}
+
+	var allVenues bool
+	var venueIDs []uint
+	allVenues, venueIDs = auth.GetVenueIDsOfPermission(user, auth.ActionParticipationView, event.ID)
+	var query = helios.DB.
+		Table("participations").
+		Joins("inner join users on participations.user_id = users.id").
+		Preload("User").
+		Preload("Venue").
+		Where("event_id = ?", event.ID).
+		Where("(users.role in (?) or users.id = ?)", auth.GetManageableUserRoles(user), user.ID)
+	if !allVenues {
+		query = query.Where("(participations.venue_id in (?) or users.id = ?)", venueIDs, user.ID)
+	}
+	if errDB := logging.CheckDB(user.RequestID, query.Find(&participations)); errDB != nil {
+		return nil, errDB
+	}
+
+	return participations, nil
+}
+
+// UpsertParticipation creates or updates a participation. Only available to user
+// permitted to edit participations on the venue, and only for the users of the
+// roles they are permitted to manage.
+// If the user is not participate to the event, create new participation on the
+// venue. If it has already existed, update the venue. Local user is assigned as
+// the proctor and venue manager of the venue.
+func UpsertParticipation(user auth.User, eventSlug string, userUsername string, participation *Participation) helios.Error {
+	var event Event
+	var participationUser auth.User
+	var venue Venue
+	var participationSaved Participation
+	var errGetEvent helios.Error
+	event, errGetEvent = GetEventOfUser(user, eventSlug)
+	if errGetEvent != nil {
+		return errGetEvent
+	}
+	if errState := checkEventState(event, participationEditableStates); errState != nil {
+		return errState
+	}
+
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("username = ?", userUsername).First(&participationUser)); errDB != nil {
+		return errDB
+	}
+	if participationUser.ID == 0 {
+		return errUserNotFound
+	} else if !auth.Can(user, auth.UserManageAction(participationUser.Role), auth.Resource{}) {
+		return errParticipationChangeNotAuthorized
+	}
+
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("id = ?", participation.VenueID).First(&venue)); errDB != nil {
+		return errDB
+	}
+	if venue.ID == 0 {
+		return errVenueNotFound
+	}
+	if !auth.Can(user, auth.ActionParticipationEdit, auth.Resource{EventID: event.ID, VenueID: venue.ID}) {
+		return errParticipationChangeNotAuthorized
+	}
+
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("user_id = ?", participationUser.ID).Where("event_id = ?", event.ID).First(&participationSaved)); errDB != nil {
+		return errDB
+	}
+	if participationSaved.ID != 0 && !auth.Can(user, auth.ActionParticipationEdit, auth.Resource{EventID: event.ID, VenueID: participationSaved.VenueID}) {
+		return errParticipationChangeNotAuthorized
+	}
+	participation.ID = participationSaved.ID
+	participation.Attendance = participationSaved.Attendance
+	participation.CheckedInAt = participationSaved.CheckedInAt
+	participation.IDVerified = participationSaved.IDVerified
+	participation.User = &participationUser
+	participation.UserID = participationUser.ID
+	participation.EventID = event.ID
+	participation.Event = &event
+	participation.VenueID = venue.ID
+	participation.Venue = &venue
+	participation.KeyHashedOnce = fmt.Sprintf("%x", sha256.Sum256([]byte(participation.KeyPlain)))
+	participation.KeyHashedTwice = fmt.Sprintf("%x", sha256.Sum256([]byte(participation.KeyHashedOnce)))
+	tx := helios.DB.Begin()
+	var errDB helios.Error
+	if participation.RoomID == nil {
+		participation.SeatNumber = 0
+	} else if errSeat := assignSeat(tx, user.RequestID, event, venue, participation); errSeat != nil {
+		tx.Rollback()
+		return errSeat
+	}
+	if participation.ID == 0 {
+		errDB = logging.CheckDB(user.RequestID, tx.Create(&participation))
+	} else {
+		errDB = logging.CheckDB(user.RequestID, tx.Save(&participation))
+	}
+	if errDB == nil && participationUser.IsLocal() {
+		errDB = logging.CheckError(user.RequestID, assignLocalEventRoles(tx, participationUser.ID, event.ID, venue.ID))
+	}
+	if errDB == nil && participationSaved.ID == 0 {
+		errDB = auth.RecordAuditLog(tx, user, auditActionParticipationCreate, auditTargetParticipation, participation.ID, nil, *participation)
+	} else if errDB == nil {
+		errDB = auth.RecordAuditLog(tx, user, auditActionParticipationUpdate, auditTargetParticipation, participation.ID, participationSaved, *participation)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	return logging.CheckDB(user.RequestID, tx.Commit())
+}
+
+// assignLocalEventRoles assigns the local user as the proctor and venue manager
+// of the venue on the event. The assignments on other venue are revoked, because
+// local user can only participate on one venue.
+func assignLocalEventRoles(db *gorm.DB, userID uint, eventID uint, venueID uint) error {
+	var errRevoke error = revokeLocalEventRoles(db, userID, eventID, venueID)
+	if errRevoke != nil {
+		return errRevoke
+	}
+	for _, role := range []string{auth.EventRoleProctor, auth.EventRoleVenueManager} {
+		if errAssign := auth.AssignEventRole(db, userID, eventID, venueID, role); errAssign != nil {
+			return errAssign
+		}
+	}
+	return nil
+}
+
+// revokeLocalEventRoles revokes the proctor and venue manager assignments of
+// the local user on the event, except the assignments on keptVenueID.
+func revokeLocalEventRoles(db *gorm.DB, userID uint, eventID uint, keptVenueID uint) error {
+	return db.
+		Where("user_id = ?", userID).
+		Where("event_id = ?", eventID).
+		Where("venue_id <> ?", keptVenueID).
+		Where("role in (?)", []string{auth.EventRoleProctor, auth.EventRoleVenueManager}).
+		Delete(auth.EventRole{}).Error
+}
+
+// ImportParticipants creates participants and their participations on the event
+// from the given rows. The user is created if the username doesn't exist yet,
+// otherwise the existing user is registered to the event and the password is
+// kept. Password and participation key are generated if they are not given,
+// and are written back to the given rows to be reported to the importer.
+// The given passwords are checked against auth.UserPasswordPolicy. Like the
+// users created by auth.UpsertUser, the created users must change the password
+// on the first login, because it is given by the importer. All rows are
+// validated first, and nothing is saved if there is any invalid row. The error
+// contains the error of each row. The passwords are hashed before the
+// transaction begins, so it isn't held open while bcrypt runs.
+func ImportParticipants(user auth.User, eventSlug string, participants []ParticipantImportData) helios.Error {
+	if !auth.Can(user, auth.ActionUserManageParticipant, auth.Resource{}) {
+		return errParticipationChangeNotAuthorized
+	}
+	var event Event
+	var errGetEvent helios.Error
+	event, errGetEvent = GetEventOfUser(user, eventSlug)
+	if errGetEvent != nil {
+		return errGetEvent
+	}
+	if errState := checkEventState(event, participationEditableStates); errState != nil {
+		return errState
+	}
+	var allVenues bool
+	var permittedVenueIDs []uint
+	var isVenuePermitted map[uint]bool = make(map[uint]bool)
+	allVenues, permittedVenueIDs = auth.GetVenueIDsOfPermission(user, auth.ActionParticipationEdit, event.ID)
+	if !allVenues && len(permittedVenueIDs) == 0 {
+		return errParticipationChangeNotAuthorized
+	}
+	for _, venueID := range permittedVenueIDs {
+		isVenuePermitted[venueID] = true
+	}
+
+	var venues []Venue
+	var venueByName map[string]Venue = make(map[string]Venue)
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Find(&venues)); errDB != nil {
+		return errDB
+	}
+	for _, venue := range venues {
+		venueByName[venue.Name] = venue
+	}
+
+	var usernames []string
+	var existingUsers []auth.User
+	var userByUsername map[string]auth.User = make(map[string]auth.User)
+	var existingUserIDs []uint
+	var isParticipating map[uint]bool = make(map[uint]bool)
+	for _, participant := range participants {
+		usernames = append(usernames, participant.Username)
+	}
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("username in (?)", usernames).Find(&existingUsers)); errDB != nil {
+		return errDB
+	}
+	for _, existingUser := range existingUsers {
+		userByUsername[existingUser.Username] = existingUser
+		existingUserIDs = append(existingUserIDs, existingUser.ID)
+	}
+	var participatingUserIDs []uint
+	errDB := logging.CheckDB(user.RequestID, helios.DB.
+		Model(&Participation{}).
+		Where("event_id = ?", event.ID).
+		Where("user_id in (?)", existingUserIDs).
+		Pluck("user_id", &participatingUserIDs))
+	if errDB != nil {
+		return errDB
+	}
+	for _, userID := range participatingUserIDs {
+		isParticipating[userID] = true
+	}
+
+	var err helios.ErrorForm = helios.NewErrorForm()
+	var errRows helios.ErrorFormFieldArray = make(helios.ErrorFormFieldArray, 0)
+	var isImported map[string]bool = make(map[string]bool)
+	for _, participant := range participants {
+		var errRow helios.ErrorFormFieldNested = make(helios.ErrorFormFieldNested)
+		var existingUser, userExists = userByUsername[participant.Username]
+		if isImported[participant.Username] {
+			errRow["username"] = helios.ErrorFormFieldAtomic{"Username is imported more than once"}
+		} else if userExists && !existingUser.IsParticipant() {
+			errRow["username"] = helios.ErrorFormFieldAtomic{"Username is used by non-participant user"}
+		} else if userExists && isParticipating[existingUser.ID] {
+			errRow["username"] = helios.ErrorFormFieldAtomic{"Participant has already been registered on the event"}
+		}
+		if !userExists {
+			var newUser auth.User
+			if errUser, ok := auth.DeserializeUser(auth.UserData{
+				Name:     participant.Name,
+				Username: participant.Username,
+				Role:     "participant",
+			}, &newUser).(helios.ErrorForm); ok {
+				for field, errField := range errUser.FieldError {
+					if _, exists := errRow[field]; !exists {
+						errRow[field] = errField
+					}
+				}
+			}
+			if participant.Password != "" {
+				if violations := auth.UserPasswordPolicy.Validate(participant.Password); len(violations) > 0 {
+					errRow["password"] = helios.ErrorFormFieldAtomic(violations)
+				}
+			}
+		}
+		if venue, venueExists := venueByName[participant.VenueName]; !venueExists {
+			errRow["venueName"] = helios.ErrorFormFieldAtomic{"Venue doesn't exist"}
+		} else if !allVenues && !isVenuePermitted[venue.ID] {
+			errRow["venueName"] = helios.ErrorFormFieldAtomic{"You are not allowed to add participant on the venue"}
+		}
+		isImported[participant.Username] = true
+		errRows = append(errRows, errRow)
+	}
+	err.FieldError["participants"] = errRows
+	if err.IsError() {
+		return err
+	}
+
+	var participationUsers []auth.User = make([]auth.User, len(participants))
+	for i := range participants {
+		var participant *ParticipantImportData = &participants[i]
+		var participationUser, userExists = userByUsername[participant.Username]
+		var errGenerate error
+		if userExists {
+			participant.Password = ""
+			participationUsers[i] = participationUser
+			continue
+		}
+		if participant.Password == "" {
+			participant.Password, errGenerate = generateSecureToken(generatedPasswordLength, passwordBytes)
+			if errDB = logging.CheckError(user.RequestID, errGenerate); errDB != nil {
+				return errDB
+			}
+		}
+		errUser := auth.DeserializeUserWithUnencryptedPassword(auth.UserWithPasswordData{
+			Name:     participant.Name,
+			Username: participant.Username,
+			Role:     "participant",
+			Password: participant.Password,
+		}, &participationUser)
+		if errUser != nil {
+			if errUserForm, ok := errUser.(helios.ErrorForm); ok {
+				errRows[i] = errUserForm.FieldError
+				err.FieldError["participants"] = errRows
+				return err
+			}
+			return errUser
+		}
+		participationUser.MustChangePassword = true
+		participationUsers[i] = participationUser
+	}
+
+	var importedParticipations []Participation
+	tx := helios.DB.Begin()
+	for i := range participants {
+		var participant *ParticipantImportData = &participants[i]
+		var participationUser *auth.User = &participationUsers[i]
+		var errGenerate error
+		if participationUser.ID == 0 {
+			if errDB = logging.CheckDB(user.RequestID, tx.Create(participationUser)); errDB != nil {
+				tx.Rollback()
+				return errDB
+			}
+		}
+
+		var participation Participation
+		participation.KeyPlain, errGenerate = generateSecureToken(participationKeyLength, tokenBytes)
+		if errDB = logging.CheckError(user.RequestID, errGenerate); errDB != nil {
+			tx.Rollback()
+			return errDB
+		}
+		participation.UserID = participationUser.ID
+		participation.EventID = event.ID
+		participation.VenueID = venueByName[participant.VenueName].ID
+		participation.KeyHashedOnce = fmt.Sprintf("%x", sha256.Sum256([]byte(participation.KeyPlain)))
+		participation.KeyHashedTwice = fmt.Sprintf("%x", sha256.Sum256([]byte(participation.KeyHashedOnce)))
+		if errDB = logging.CheckDB(user.RequestID, tx.Create(&participation)); errDB != nil {
+			tx.Rollback()
+			return errDB
+		}
+		participant.Key = participation.KeyPlain
+		importedParticipations = append(importedParticipations, participation)
+	}
+	for _, participation := range importedParticipations {
+		if errDB = auth.RecordAuditLog(tx, user, auditActionParticipationImport, auditTargetParticipation, participation.ID, nil, participation); errDB != nil {
+			tx.Rollback()
+			return errDB
+		}
+	}
+	return logging.CheckDB(user.RequestID, tx.Commit())
+}
+
+// GenerateCredentialCards renders the credential cards of participants on the venue
+// as PDF, then wipes their plain participation keys. Participants whose key has been
+// wiped are skipped and their usernames are returned, unless reissueKey is true, which
+// generates new keys for them. Password is only printed if resetPassword is true, because
+// the saved password is hashed. Keys can only be reissued and passwords reset before
+// the event is synchronized to the local server. Like the imported participants,
+// the participants whose password is reset must change it on the first login.
+func GenerateCredentialCards(user auth.User, eventSlug string, venueID uint, layout string, resetPassword bool, reissueKey bool) ([]byte, []string, helios.Error) {
+	var event Event
+	var venue Venue
+	var errGetEvent helios.Error
+	event, venue, errGetEvent = getEventAndVenueOfPermission(user, eventSlug, venueID, auth.ActionCredentialCardGenerate, errCredentialCardNotAuthorized)
+	if errGetEvent != nil {
+		return nil, nil, errGetEvent
+	}
+	// new keys and passwords only reach the local server by synchronization
+	if resetPassword || reissueKey {
+		if errState := checkEventState(event, participationEditableStates); errState != nil {
+			return nil, nil, errState
+		}
+	}
+
+	var participations []Participation
+	var errDB helios.Error
+	if participations, errDB = getSeatedParticipations(helios.DB, user.RequestID, event, venue); errDB != nil {
+		return nil, nil, errDB
+	}
+	var cards []credentialCard
+	var skipped []string
+	tx := helios.DB.Begin()
+	for _, participation := range participations {
+		var errGenerate error
+		var card credentialCard = credentialCard{
+			Name:     participation.User.Name,
+			Username: participation.User.Username,
+			Key:      participation.KeyPlain,
+		}
+		if card.Key == "" {
+			if !reissueKey {
+				skipped = append(skipped, card.Username)
+				continue
+			}
+			card.Key, errGenerate = generateSecureToken(participationKeyLength, tokenBytes)
+			if errDB = logging.CheckError(user.RequestID, errGenerate); errDB != nil {
+				tx.Rollback()
+				return nil, nil, errDB
+			}
+			participation.KeyHashedOnce = fmt.Sprintf("%x", sha256.Sum256([]byte(card.Key)))
+			participation.KeyHashedTwice = fmt.Sprintf("%x", sha256.Sum256([]byte(participation.KeyHashedOnce)))
+		}
+		if resetPassword {
+			var participationUser auth.User = *participation.User
+			var userData auth.UserWithPasswordData = auth.SerializeUserWithPassword(participationUser)
+			userData.Password, errGenerate = generateSecureToken(generatedPasswordLength, passwordBytes)
+			if errDB = logging.CheckError(user.RequestID, errGenerate); errDB != nil {
+				tx.Rollback()
+				return nil, nil, errDB
+			}
+			if errDB = auth.DeserializeUserWithUnencryptedPassword(userData, &participationUser); errDB != nil {
+				tx.Rollback()
+				return nil, nil, errDB
+			}
+			if errDB = logging.CheckDB(user.RequestID, tx.Model(&participationUser).Updates(map[string]interface{}{
+				"password":             participationUser.Password,
+				"must_change_password": true,
+			})); errDB != nil {
+				tx.Rollback()
+				return nil, nil, errDB
+			}
+			card.Password = userData.Password
+		}
+		errDB = logging.CheckDB(user.RequestID, tx.
+			Model(&Participation{}).
+			Where("id = ?", participation.ID).
+			Updates(map[string]interface{}{
+				"key_plain":        "",
+				"key_hashed_once":  participation.KeyHashedOnce,
+				"key_hashed_twice": participation.KeyHashedTwice,
+			}))
+		if errDB != nil {
+			tx.Rollback()
+			return nil, nil, errDB
+		}
+		cards = append(cards, card)
+	}
+
+	pdf, errRender := renderCredentialCards(event, venue, cards, layout)
+	if errDB = logging.CheckError(user.RequestID, errRender); errDB != nil {
+		tx.Rollback()
+		return nil, nil, errDB
+	}
+	errDB = auth.RecordAuditLog(tx, user, auditActionCredentialCardGenerate, auditTargetEvent, event.ID, nil, map[string]interface{}{
+		"VenueID":       venue.ID,
+		"Printed":       len(cards),
+		"ResetPassword": resetPassword,
+		"ReissueKey":    reissueKey,
+	})
+	if errDB != nil {
+		tx.Rollback()
+		return nil, nil, errDB
+	}
+	if errDB = logging.CheckDB(user.RequestID, tx.Commit()); errDB != nil {
+		return nil, nil, errDB
+	}
+	return pdf, skipped, nil
+}
+
+// assignSeat checks the capacity of participation's room and whether the seat is
+// still available. If the seat number is zero, the lowest free seat is picked.
+// The room is locked until tx ends, so the seat must be saved in tx.
+func assignSeat(tx *gorm.DB, requestID string, event Event, venue Venue, participation *Participation) helios.Error {
+	var rooms []Room
+	var takenSeats []uint
+	if errDB := logging.CheckDB(requestID, lockRooms(tx.Where("id = ?", participation.GetRoomID()), venue, &rooms)); errDB != nil {
+		return errDB
+	}
+	if len(rooms) == 0 {
+		return errRoomNotFound
+	}
+	var room Room = rooms[0]
+
+	errDB := logging.CheckDB(requestID, tx.
+		Model(&Participation{}).
+		Where("event_id = ?", event.ID).
+		Where("room_id = ?", room.ID).
+		Where("id <> ?", participation.ID).
+		Pluck("seat_number", &takenSeats))
+	if errDB != nil {
+		return errDB
+	}
+	if uint(len(takenSeats)) >= room.Capacity {
+		return errRoomFull
+	}
+
+	var isTaken map[uint]bool = make(map[uint]bool)
+	for _, seat := range takenSeats {
+		isTaken[seat] = true
+	}
+	if participation.SeatNumber == 0 {
+		for seat := uint(1); seat <= room.Capacity; seat++ {
+			if !isTaken[seat] {
+				participation.SeatNumber = seat
+				break
+			}
+		}
+	} else if participation.SeatNumber > room.Capacity || isTaken[participation.SeatNumber] {
+		return errSeatNotAvailable
+	}
+	participation.Room = &room
+	return nil
+}
+
+// lockRooms reads the rooms of the venue ordered by creation, and locks them
+// until tx ends, so the seats on the rooms are assigned by one transaction at
+// a time instead of failing on the seat index when they pick the same seat.
+// On sqlite3 the write transactions are begun immediate by config.OpenDatabase,
+// so tx already holds the database lock.
+func lockRooms(tx *gorm.DB, venue Venue, rooms *[]Room) *gorm.DB {
+	if tx.Dialect().GetName() != "sqlite3" {
+		tx = tx.Set("gorm:query_option", "FOR UPDATE")
+	}
+	return tx.Where("venue_id = ?", venue.ID).Order("id asc").Find(rooms)
+}
+
+// deleteParticipations deletes the participations matched by db and releases
+// their seats, so the seats can be assigned again without conflicting with the
+// deleted participations on the seat index.
+func deleteParticipations(db *gorm.DB) error {
+	err := db.Model(&Participation{}).Updates(map[string]interface{}{
+		"room_id":     nil,
+		"seat_number": 0,
+	}).Error
+	if err != nil {
+		return err
+	}
+	return db.Delete(Participation{}).Error
+}
+
+// getEventAndVenueOfPermission returns the event and the venue if the user is
+// permitted to do the action on the venue of the event. errNotAuthorized is
+// returned otherwise.
+func getEventAndVenueOfPermission(user auth.User, eventSlug string, venueID uint, action auth.Action, errNotAuthorized helios.Error) (Event, Venue, helios.Error) {
+	var event Event
+	var venue Venue
+	var errGetEvent helios.Error
+	event, errGetEvent = GetEventOfUser(user, eventSlug)
+	if errGetEvent != nil {
+		return event, venue, errGetEvent
+	}
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("id = ?", venueID).First(&venue)); errDB != nil {
+		return event, venue, errDB
+	}
+	if venue.ID == 0 {
+		return event, venue, errVenueNotFound
+	}
+	if !auth.Can(user, action, auth.Resource{EventID: event.ID, VenueID: venue.ID}) {
+		return event, venue, errNotAuthorized
+	}
+	return event, venue, nil
+}
+
+// getEventOfSeatAssigner returns the event if the user can manage the seats of the venue.
+func getEventOfSeatAssigner(user auth.User, eventSlug string, venueID uint) (Event, Venue, helios.Error) {
+	return getEventAndVenueOfPermission(user, eventSlug, venueID, auth.ActionSeatAssign, errSeatAssignmentNotAuthorized)
+}
+
+// getSeatedParticipations returns participations of participant on the venue of the event
+// ordered by the username
+func getSeatedParticipations(db *gorm.DB, requestID string, event Event, venue Venue) ([]Participation, helios.Error) {
+	var participations []Participation
+	errDB := logging.CheckDB(requestID, db.
+		Table("participations").
+		Select("participations.*").
+		Joins("inner join users on participations.user_id = users.id").
+		Preload("User").
+		Where("participations.event_id = ?", event.ID).
+		Where("participations.venue_id = ?", venue.ID).
+		Where("users.role = ?", auth.UserRoleParticipant).
+		Where("participations.deleted_at is null").
+		Order("users.username asc").
+		Find(&participations))
+	if errDB != nil {
+		return nil, errDB
+	}
+	return participations, nil
+}
+
+// AssignSeats automatically assigns seats for all participants on the venue that
+// don't have any seat yet. The rooms are filled in order of creation, starting from
+// the lowest seat number. If the seats are not enough, nothing will be assigned.
+func AssignSeats(user auth.User, eventSlug string, venueID uint) helios.Error {
+	var event Event
+	var venue Venue
+	var errGetEvent helios.Error
+	event, venue, errGetEvent = getEventOfSeatAssigner(user, eventSlug, venueID)
+	if errGetEvent != nil {
+		return errGetEvent
+	}
+
+	var rooms []Room
+	var participations []Participation
+	var errDB helios.Error
+	var isTaken map[uint]map[uint]bool = make(map[uint]map[uint]bool)
+	tx := helios.DB.Begin()
+	if errDB = logging.CheckDB(user.RequestID, lockRooms(tx, venue, &rooms)); errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	if participations, errDB = getSeatedParticipations(tx, user.RequestID, event, venue); errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	for _, room := range rooms {
+		isTaken[room.ID] = make(map[uint]bool)
+	}
+	for _, participation := range participations {
+		if participation.RoomID != nil {
+			isTaken[participation.GetRoomID()][participation.SeatNumber] = true
+		}
+	}
+
+	var roomIdx int = 0
+	var seat uint = 1
+	for i := range participations {
+		if participations[i].RoomID != nil {
+			continue
+		}
+		for roomIdx < len(rooms) && (seat > rooms[roomIdx].Capacity || isTaken[rooms[roomIdx].ID][seat]) {
+			if seat > rooms[roomIdx].Capacity {
+				roomIdx++
+				seat = 1
+			} else {
+				seat++
+			}
+		}
+		if roomIdx >= len(rooms) {
+			tx.Rollback()
+			return errVenueCapacityExceeded
+		}
+		errDB = logging.CheckDB(user.RequestID, tx.Model(&participations[i]).Updates(map[string]interface{}{
+			"room_id":     rooms[roomIdx].ID,
+			"seat_number": seat,
+		}))
+		if errDB == nil {
+			participations[i].SetSeat(rooms[roomIdx].ID, seat)
+			errDB = auth.RecordAuditLog(tx, user, auditActionSeatAssign, auditTargetParticipation, participations[i].ID, seatOf(Participation{}), seatOf(participations[i]))
+		}
+		if errDB != nil {
+			tx.Rollback()
+			return errDB
+		}
+		isTaken[rooms[roomIdx].ID][seat] = true
+	}
+	return logging.CheckDB(user.RequestID, tx.Commit())
+}
+
+// ImportSeatAssignment assigns seats of participants on the venue from the given
+// list of assignments. All assignments are validated first, and nothing is saved
+// if there is any invalid row. The error contains the error of each row.
+func ImportSeatAssignment(user auth.User, eventSlug string, venueID uint, seats []SeatAssignmentData) helios.Error {
+	var event Event
+	var venue Venue
+	var errGetEvent helios.Error
+	event, venue, errGetEvent = getEventOfSeatAssigner(user, eventSlug, venueID)
+	if errGetEvent != nil {
+		return errGetEvent
+	}
+
+	var rooms []Room
+	var participations []Participation
+	var errDB helios.Error
+	var roomByName map[string]Room = make(map[string]Room)
+	var participationByUsername map[string]*Participation = make(map[string]*Participation)
+	var isTaken map[uint]map[uint]bool = make(map[uint]map[uint]bool)
+	tx := helios.DB.Begin()
+	if errDB = logging.CheckDB(user.RequestID, lockRooms(tx, venue, &rooms)); errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	if participations, errDB = getSeatedParticipations(tx, user.RequestID, event, venue); errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	for _, room := range rooms {
+		roomByName[room.Name] = room
+		isTaken[room.ID] = make(map[uint]bool)
+	}
+	var seatsBefore map[uint]map[string]interface{} = make(map[uint]map[string]interface{})
+	for i := range participations {
+		participationByUsername[participations[i].User.Username] = &participations[i]
+		seatsBefore[participations[i].ID] = seatOf(participations[i])
+	}
+	// seats of participants that are not imported are kept
+	var isImported map[string]bool = make(map[string]bool)
+	for _, seat := range seats {
+		isImported[seat.UserUsername] = true
+	}
+	for _, participation := range participations {
+		if participation.RoomID != nil && !isImported[participation.User.Username] {
+			isTaken[participation.GetRoomID()][participation.SeatNumber] = true
+		}
+	}
+
+	var isAssigned map[string]bool = make(map[string]bool)
+	var err helios.ErrorForm = helios.NewErrorForm()
+	var errRows helios.ErrorFormFieldArray = make(helios.ErrorFormFieldArray, 0)
+	for _, seat := range seats {
+		var errRow helios.ErrorFormFieldNested = make(helios.ErrorFormFieldNested)
+		var participation *Participation = participationByUsername[seat.UserUsername]
+		var room, roomExists = roomByName[seat.RoomName]
+		if participation == nil {
+			errRow["userUsername"] = helios.ErrorFormFieldAtomic{"Participant is not registered on the venue"}
+		} else if isAssigned[seat.UserUsername] {
+			errRow["userUsername"] = helios.ErrorFormFieldAtomic{"Participant is assigned more than once"}
+		}
+		if !roomExists {
+			errRow["roomName"] = helios.ErrorFormFieldAtomic{"Room doesn't exist on the venue"}
+		} else if seat.SeatNumber == 0 || seat.SeatNumber > room.Capacity {
+			errRow["seatNumber"] = helios.ErrorFormFieldAtomic{fmt.Sprintf("Seat number should be between 1 and %d", room.Capacity)}
+		} else if isTaken[room.ID][seat.SeatNumber] {
+			errRow["seatNumber"] = helios.ErrorFormFieldAtomic{"Seat is assigned more than once"}
+		}
+		isAssigned[seat.UserUsername] = true
+		if !errRow.IsError() {
+			isTaken[room.ID][seat.SeatNumber] = true
+			participation.SetSeat(room.ID, seat.SeatNumber)
+		}
+		errRows = append(errRows, errRow)
+	}
+	err.FieldError["seats"] = errRows
+	if err.IsError() {
+		tx.Rollback()
+		return err
+	}
+
+	// the imported participants may swap their seats, so their seats are
+	// released before any of them is assigned to not conflict on the seat index
+	var importedIDs []uint
+	for _, seat := range seats {
+		importedIDs = append(importedIDs, participationByUsername[seat.UserUsername].ID)
+	}
+	errDB = logging.CheckDB(user.RequestID, tx.Model(&Participation{}).Where("id in (?)", importedIDs).Updates(map[string]interface{}{
+		"room_id":     nil,
+		"seat_number": 0,
+	}))
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	for _, seat := range seats {
+		var participation *Participation = participationByUsername[seat.UserUsername]
+		errDB = logging.CheckDB(user.RequestID, tx.Model(participation).Updates(map[string]interface{}{
+			"room_id":     participation.RoomID,
+			"seat_number": participation.SeatNumber,
+		}))
+		if errDB == nil {
+			errDB = auth.RecordAuditLog(tx, user, auditActionSeatAssign, auditTargetParticipation, participation.ID, seatsBefore[participation.ID], seatOf(*participation))
+		}
+		if errDB != nil {
+			tx.Rollback()
+			return errDB
+		}
+	}
+	return logging.CheckDB(user.RequestID, tx.Commit())
+}
+
+// seatOf returns the seat of the participation to be recorded on the audit log
+func seatOf(participation Participation) map[string]interface{} {
+	return map[string]interface{}{
+		"RoomID":     participation.GetRoomID(),
+		"SeatNumber": participation.SeatNumber,
+	}
+}
+
+// GetSeatingChart returns the rooms of the venue and the participations
+// of participants on the venue, ordered by the username.
+func GetSeatingChart(user auth.User, eventSlug string, venueID uint) (*Venue, []Room, []Participation, helios.Error) {
+	var event Event
+	var venue Venue
+	var errGetEvent helios.Error
+	event, venue, errGetEvent = getEventOfSeatAssigner(user, eventSlug, venueID)
+	if errGetEvent != nil {
+		return nil, nil, nil, errGetEvent
+	}
+
+	var rooms []Room
+	var participations []Participation
+	var errDB helios.Error
+	if errDB = logging.CheckDB(user.RequestID, helios.DB.Where("venue_id = ?", venue.ID).Order("id asc").Find(&rooms)); errDB != nil {
+		return nil, nil, nil, errDB
+	}
+	if participations, errDB = getSeatedParticipations(helios.DB, user.RequestID, event, venue); errDB != nil {
+		return nil, nil, nil, errDB
+	}
+	return &venue, rooms, participations, nil
+}
+
+// PrintSeatingChart renders the seating chart of the venue on the event as A4 PDF
+func PrintSeatingChart(user auth.User, eventSlug string, venueID uint) ([]byte, helios.Error) {
+	var event Event
+	var venue Venue
+	var errGetEvent helios.Error
+	event, venue, errGetEvent = getEventOfSeatAssigner(user, eventSlug, venueID)
+	if errGetEvent != nil {
+		return nil, errGetEvent
+	}
+
+	var rooms []Room
+	var participations []Participation
+	var errDB helios.Error
+	if errDB = logging.CheckDB(user.RequestID, helios.DB.Where("venue_id = ?", venue.ID).Order("id asc").Find(&rooms)); errDB != nil {
+		return nil, errDB
+	}
+	if participations, errDB = getSeatedParticipations(helios.DB, user.RequestID, event, venue); errDB != nil {
+		return nil, errDB
+	}
+	pdf, errRender := renderSeatingChart(event, SerializeSeatingChart(venue, rooms, participations))
+	if errDB = logging.CheckError(user.RequestID, errRender); errDB != nil {
+		return nil, errDB
+	}
+	return pdf, nil
+}
+
+// VerifyParticipation checks if the hashedOnce equal to the participation key
+func VerifyParticipation(user auth.User, eventSlug string, hashedOnce string) helios.Error {
+	var event Event
+	var participation Participation
+	var errGetEvent helios.Error
+	event, errGetEvent = GetEventOfUser(user, eventSlug)
+	if errGetEvent != nil {
+		return errGetEvent
 	}
-
-	if event.ID == 0 {
-		if user.IsAdmin() || user.IsOrganizer() {
-			var err error
-			var prvKey *rsa.PrivateKey
-			var simKeySign []byte
-			prvKey, err = rsa.GenerateKey(rand.Reader, 1024)
-			if err != nil {
-				return helios.ErrInternalServerError
-			}
-			event.SimKey = generateRandomToken(32)
-			event.PrvKey = base64.StdEncoding.EncodeToString(x509.MarshalPKCS1PrivateKey(prvKey))
-			event.PubKey = base64.StdEncoding.EncodeToString(x509.MarshalPKCS1PublicKey(&prvKey.PublicKey))
-			simKeyHashed := sha256.Sum256([]byte(event.SimKey))
-			simKeySign, err = rsa.SignPSS(rand.Reader, prvKey, crypto.SHA256, simKeyHashed[:], nil)
-			if err != nil {
-				return helios.ErrInternalServerError
-			}
-			event.SimKeySign = base64.StdEncoding.EncodeToString(simKeySign)
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("user_id = ?", user.ID).Where("event_id = ?", event.ID).First(&participation)); errDB != nil {
+		return errDB
+	}
+	if user.IsParticipant() && participation.CheckedInAt.IsZero() {
+		return errParticipationNotCheckedIn
+	}
+	hashedTwice := fmt.Sprintf("%x", sha256.Sum256([]byte(hashedOnce)))
+	if participation.KeyHashedTwice == hashedTwice {
+		participation.KeyHashedOnce = hashedOnce
+		tx := helios.DB.Begin()
+		errDB := logging.CheckDB(user.RequestID, tx.Save(&participation))
+		if errDB == nil {
+			errDB = auth.RecordAuditLog(tx, user, auditActionParticipationVerify, auditTargetParticipation, participation.ID, nil, nil)
 		}
-		helios.DB.Omit("last_synchronization").Create(event)
-		if user.IsLocal() {
-			helios.DB.Create(&Participation{
-				User:  &user,
-				Event: event,
-			})
+		if errDB != nil {
+			tx.Rollback()
+			return errDB
 		}
-	} else {
-		helios.DB.Omit("last_synchronization", "key").Save(event)
+		return logging.CheckDB(user.RequestID, tx.Commit())
 	}
+	return errParticipationWrongKey
+}
 
+// checkParticipantCheckedIn returns error if the user is a participant
+// that has not been checked in on the event by the proctor.
+func checkParticipantCheckedIn(user auth.User, event Event) helios.Error {
+	if !user.IsParticipant() {
+		return nil
+	}
+	var participation Participation
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("user_id = ?", user.ID).Where("event_id = ?", event.ID).First(&participation)); errDB != nil {
+		return errDB
+	}
+	if participation.CheckedInAt.IsZero() {
+		return errParticipationNotCheckedIn
+	}
 	return nil
 }
 
-// GetAllParticipationOfUserAndEvent returns all participations of the event.
-func GetAllParticipationOfUserAndEvent(user auth.User, eventSlug string) ([]Participation, helios.Error) {
+// getVenueOfProctor returns the event and the venue of the local user on the event,
+// if the user is permitted to do the action on the venue. errNotAuthorized is
+// returned otherwise.
+func getVenueOfProctor(user auth.User, eventSlug string, action auth.Action, errNotAuthorized helios.Error) (Event, Venue, helios.Error) {
 	var event Event
-	var participations []Participation
+	var venue Venue
+	var participation Participation
 	var errGetEvent helios.Error
-
 	event, errGetEvent = GetEventOfUser(user, eventSlug)
 	if errGetEvent != nil {
-		return nil, errGetEvent
+		return event, venue, errGetEvent
+	}
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Preload("Venue").Where("user_id = ?", user.ID).Where("event_id = ?", event.ID).First(&participation)); errDB != nil {
+		return event, venue, errDB
+	}
+	if participation.Venue == nil || participation.Venue.ID == 0 {
+		if !auth.Can(user, action, auth.Resource{EventID: event.ID}) {
+			return event, venue, errNotAuthorized
+		}
+		return event, venue, errVenueNotFound
+	}
+	if !auth.Can(user, action, auth.Resource{EventID: event.ID, VenueID: participation.Venue.ID}) {
+		return event, venue, errNotAuthorized
+	}
+	return event, *participation.Venue, nil
+}
+
+// CheckInParticipation marks the participant with given username as present
+// on the event. Only proctor of the same venue can check in the participant.
+// The participant is recorded as late if it is checked in after the event starts.
+func CheckInParticipation(user auth.User, eventSlug string, userUsername string, idVerified bool) (*Participation, helios.Error) {
+	var event Event
+	var venue Venue
+	var participation Participation
+	var errGetVenue helios.Error
+	event, venue, errGetVenue = getVenueOfProctor(user, eventSlug, auth.ActionParticipationCheckIn, errCheckInNotAuthorized)
+	if errGetVenue != nil {
+		return nil, errGetVenue
 	}
 
-	helios.DB.
+	errDB := logging.CheckDB(user.RequestID, helios.DB.
+		Select("participations.*").
 		Table("participations").
 		Joins("inner join users on participations.user_id = users.id").
 		Preload("User").
-		Preload("Venue").
-		Where("event_id = ?", event.ID).
-		Where("(users.role < ? or users.id = ?)", user.Role, user.ID).
-		Find(&participations)
+		Where("participations.event_id = ?", event.ID).
+		Where("participations.venue_id = ?", venue.ID).
+		Where("participations.deleted_at is null").
+		Where("users.username = ?", userUsername).
+		Where("users.role = ?", auth.UserRoleParticipant).
+		First(&participation))
+	if errDB != nil {
+		return nil, errDB
+	}
+	if participation.ID == 0 {
+		return nil, errUserNotFound
+	}
+	if !participation.CheckedInAt.IsZero() {
+		return nil, errParticipationAlreadyCheckedIn
+	}
 
-	return participations, nil
+	participation.CheckedInAt = time.Now()
+	participation.IDVerified = idVerified
+	participation.Attendance = AttendancePresent
+	if participation.CheckedInAt.After(event.StartsAt) {
+		participation.Attendance = AttendanceLate
+	}
+	tx := helios.DB.Begin()
+	errDB = logging.CheckDB(user.RequestID, tx.Model(&participation).Updates(map[string]interface{}{
+		"checked_in_at": participation.CheckedInAt,
+		"id_verified":   participation.IDVerified,
+		"attendance":    participation.Attendance,
+	}))
+	if errDB == nil {
+		errDB = auth.RecordAuditLog(tx, user, auditActionParticipationCheckIn, auditTargetParticipation, participation.ID, nil, map[string]interface{}{
+			"Attendance": participation.Attendance,
+			"IDVerified": participation.IDVerified,
+		})
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return nil, errDB
+	}
+	if errDB = logging.CheckDB(user.RequestID, tx.Commit()); errDB != nil {
+		return nil, errDB
+	}
+	return &participation, nil
 }
 
-// UpsertParticipation creates or updates a participation. Only available to
-// user with higher role. If the user is not participate to the event, create new
-// participation on the venue. If it has already existed, update the venue.
-func UpsertParticipation(user auth.User, eventSlug string, userUsername string, participation *Participation) helios.Error {
+// RecordNoShow records all participants on the venue of the proctor that
+// have not been checked in as no-show. It can only be done after the event starts.
+func RecordNoShow(user auth.User, eventSlug string) helios.Error {
 	var event Event
-	var participationUser auth.User
 	var venue Venue
-	var participationSaved Participation
-	var errGetEvent helios.Error
-	event, errGetEvent = GetEventOfUser(user, eventSlug)
-	if errGetEvent != nil {
-		return errGetEvent
+	var errGetVenue helios.Error
+	event, venue, errGetVenue = getVenueOfProctor(user, eventSlug, auth.ActionParticipationCheckIn, errCheckInNotAuthorized)
+	if errGetVenue != nil {
+		return errGetVenue
 	}
-
-	helios.DB.Where("username = ?", userUsername).First(&participationUser)
-	if participationUser.ID == 0 {
-		return errUserNotFound
-	} else if participationUser.Role >= user.Role {
-		return errParticipationChangeNotAuthorized
+	if event.StartsAt.After(time.Now()) {
+		return errEventIsNotYetStarted
 	}
 
-	helios.DB.Where("id = ?", participation.VenueID).First(&venue)
-	if venue.ID == 0 {
-		return errVenueNotFound
+	tx := helios.DB.Begin()
+	var update *gorm.DB = tx.
+		Model(&Participation{}).
+		Where("event_id = ?", event.ID).
+		Where("venue_id = ?", venue.ID).
+		Where("attendance = ?", "").
+		Where("user_id in ?", tx.Table("users").Select("id").Where("role = ?", auth.UserRoleParticipant).SubQuery()).
+		Update("attendance", AttendanceNoShow)
+	errDB := logging.CheckDB(user.RequestID, update)
+	if errDB == nil {
+		errDB = auth.RecordAuditLog(tx, user, auditActionAttendanceNoShow, auditTargetEvent, event.ID, nil, map[string]interface{}{
+			"VenueID":  venue.ID,
+			"Recorded": update.RowsAffected,
+		})
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
 	}
+	return logging.CheckDB(user.RequestID, tx.Commit())
+}
 
-	helios.DB.Where("user_id = ?", participationUser.ID).Where("event_id = ?", event.ID).First(&participationSaved)
-	participation.ID = participationSaved.ID
-	participation.User = &participationUser
-	participation.UserID = participationUser.ID
-	participation.EventID = event.ID
-	participation.Event = &event
-	participation.VenueID = venue.ID
-	participation.Venue = &venue
-	participation.KeyHashedOnce = fmt.Sprintf("%x", sha256.Sum256([]byte(participation.KeyPlain)))
-	participation.KeyHashedTwice = fmt.Sprintf("%x", sha256.Sum256([]byte(participation.KeyHashedOnce)))
-	if participation.ID == 0 {
-		helios.DB.Create(&participation)
-	} else {
-		helios.DB.Save(&participation)
+// GetAttendance returns the participations of participants on the venue
+// of the local user, to be synchronized back to central server.
+func GetAttendance(user auth.User, eventSlug string) ([]Participation, helios.Error) {
+	var event Event
+	var venue Venue
+	var errGetVenue helios.Error
+	event, venue, errGetVenue = getVenueOfProctor(user, eventSlug, auth.ActionAttendanceSynchronize, errAttendanceAccessNotAuthorized)
+	if errGetVenue != nil {
+		return nil, errGetVenue
 	}
-
-	return nil
+	return getSeatedParticipations(helios.DB, user.RequestID, event, venue)
 }
 
-// VerifyParticipation checks if the hashedOnce equal to the participation key
-func VerifyParticipation(user auth.User, eventSlug string, hashedOnce string) helios.Error {
+// PutAttendance saves the attendance sent by local server on central server.
+// usersAttendance is the attendance of participants on the venue of the local
+// user, keyed by the username. Nothing is saved if there is unknown participant.
+func PutAttendance(user auth.User, eventSlug string, usersAttendance map[string]Participation) helios.Error {
 	var event Event
-	var participation Participation
-	var errGetEvent helios.Error
-	event, errGetEvent = GetEventOfUser(user, eventSlug)
-	if errGetEvent != nil {
-		return errGetEvent
+	var venue Venue
+	var errGetVenue helios.Error
+	event, venue, errGetVenue = getVenueOfProctor(user, eventSlug, auth.ActionAttendanceSynchronize, errAttendanceAccessNotAuthorized)
+	if errGetVenue != nil {
+		return errGetVenue
 	}
-	helios.DB.Where("user_id = ?", user.ID).Where("event_id = ?", event.ID).First(&participation)
-	hashedTwice := fmt.Sprintf("%x", sha256.Sum256([]byte(hashedOnce)))
-	if participation.KeyHashedTwice == hashedTwice {
-		participation.KeyHashedOnce = hashedOnce
-		helios.DB.Save(&participation)
-		return nil
+
+	var participations []Participation
+	var errDB helios.Error
+	if participations, errDB = getSeatedParticipations(helios.DB, user.RequestID, event, venue); errDB != nil {
+		return errDB
 	}
-	return errParticipationWrongKey
+	var participationByUsername map[string]Participation = make(map[string]Participation)
+	for _, participation := range participations {
+		participationByUsername[participation.User.Username] = participation
+	}
+
+	var err helios.ErrorForm = helios.NewErrorForm()
+	var errUsers helios.ErrorFormFieldNested = make(helios.ErrorFormFieldNested)
+	for username := range usersAttendance {
+		if _, ok := participationByUsername[username]; !ok {
+			errUsers[username] = helios.ErrorFormFieldAtomic{"Participant is not registered on the venue"}
+		}
+	}
+	if errUsers.IsError() {
+		err.FieldError["usersAttendance"] = errUsers
+		return err
+	}
+
+	tx := helios.DB.Begin()
+	for username, attendance := range usersAttendance {
+		var participation Participation = participationByUsername[username]
+		errDB = logging.CheckDB(user.RequestID, tx.Model(&participation).Updates(map[string]interface{}{
+			"checked_in_at": attendance.CheckedInAt,
+			"id_verified":   attendance.IDVerified,
+			"attendance":    attendance.Attendance,
+		}))
+		if errDB != nil {
+			tx.Rollback()
+			return errDB
+		}
+	}
+	errDB = auth.RecordAuditLog(tx, user, auditActionAttendanceSynchronize, auditTargetEvent, event.ID, nil, map[string]interface{}{
+		"VenueID":  venue.ID,
+		"Received": len(usersAttendance),
+	})
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	return logging.CheckDB(user.RequestID, tx.Commit())
 }
 
 // DeleteParticipation deletes a participation with given id
-// and returns the deleted participation. Only available to
-// user with higher role.
+// and returns the deleted participation. Only available to user
+// permitted to edit participations on the venue, and only for
+// the users of the roles they are permitted to manage.
 func DeleteParticipation(user auth.User, eventSlug string, participationID uint) (*Participation, helios.Error) {
 	var event Event
 	var participation Participation
@@ -259,18 +1878,39 @@ func DeleteParticipation(user auth.User, eventSlug string, participationID uint)
 	if errGetEvent != nil {
 		return nil, errGetEvent
 	}
+	if errState := checkEventState(event, participationEditableStates); errState != nil {
+		return nil, errState
+	}
 
-	helios.DB.Preload("User").Preload("Venue").Where("id = ?", participationID).Where("event_id = ?", event.ID).First(&participation)
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Preload("User").Preload("Venue").Where("id = ?", participationID).Where("event_id = ?", event.ID).First(&participation)); errDB != nil {
+		return nil, errDB
+	}
 	if participation.ID == 0 {
 		return nil, errParticipationNotFound
-	} else if participation.User.Role >= user.Role {
+	} else if !auth.Can(user, auth.UserManageAction(participation.User.Role), auth.Resource{}) {
+		return nil, errParticipationChangeNotAuthorized
+	} else if !auth.Can(user, auth.ActionParticipationEdit, auth.Resource{EventID: event.ID, VenueID: participation.VenueID}) {
 		return nil, errParticipationChangeNotAuthorized
 	}
 
 	tx := helios.DB.Begin()
-	tx.Where("participation_id = ?", participationID).Delete(UserQuestion{})
-	tx.Delete(&participation)
-	tx.Commit()
+	errDB := logging.CheckDB(user.RequestID, tx.Where("participation_id = ?", participationID).Delete(UserQuestion{}))
+	if errDB == nil {
+		errDB = logging.CheckError(user.RequestID, deleteParticipations(tx.Where("id = ?", participation.ID)))
+	}
+	if errDB == nil && participation.User.IsLocal() {
+		errDB = logging.CheckError(user.RequestID, revokeLocalEventRoles(tx, participation.UserID, event.ID, 0))
+	}
+	if errDB == nil {
+		errDB = auth.RecordAuditLog(tx, user, auditActionParticipationDelete, auditTargetParticipation, participation.ID, participation, nil)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return nil, errDB
+	}
+	if errDB = logging.CheckDB(user.RequestID, tx.Commit()); errDB != nil {
+		return nil, errDB
+	}
 	return &participation, nil
 }
 
@@ -287,55 +1927,76 @@ func GetAllQuestionOfUserAndEvent(user auth.User, eventSlug string) ([]Question,
 		return nil, errGetEvent
 	}
 
-	if !user.IsAdmin() && !user.IsOrganizer() && event.StartsAt.After(time.Now()) {
+	var resource auth.Resource = auth.Resource{EventID: event.ID}
+	if !auth.Can(user, auth.ActionQuestionViewBeforeStart, resource) && event.StartsAt.After(time.Now()) {
 		return nil, errEventIsNotYetStarted
 	}
+	if errCheckIn := checkParticipantCheckedIn(user, event); errCheckIn != nil {
+		return nil, errCheckIn
+	}
 
 	// Querying for user questions and user submissions
-	if user.IsAdmin() || user.IsOrganizer() || user.IsLocal() {
-		helios.DB.Where("event_id = ?", event.ID).Order("questions.id asc").Find(&questions)
+	var query *gorm.DB
+	if auth.Can(user, auth.ActionQuestionView, resource) {
+		query = helios.DB.Where("event_id = ?", event.ID).Order("questions.id asc")
 	} else {
-		helios.DB.
+		query = helios.DB.
 			Select("questions.*, user_questions.answer as user_answer").
 			Table("questions").
 			Joins("inner join user_questions on user_questions.question_id = questions.id").
 			Joins("inner join participations on participations.id = user_questions.participation_id").
 			Where("questions.event_id = ?", event.ID).
 			Where("participations.user_id = ?", user.ID).
-			Order("user_questions.ordering asc").
-			Find(&questions)
+			Order("user_questions.ordering asc")
+	}
+	if errDB := logging.CheckDB(user.RequestID, query.Find(&questions)); errDB != nil {
+		return nil, errDB
 	}
 
 	return questions, nil
 }
 
 // UpsertQuestion creates or updates a question. Only available to
-// admin and organizer. Notice that the EventID may be changed, so
-// this function may move a question to other event.
+// user permitted to edit questions of the event. Notice that the EventID
+// may be changed, so this function may move a question to other event.
 // If it is updating, all choices will be deleted then recreated.
 func UpsertQuestion(user auth.User, eventSlug string, question *Question) helios.Error {
-	if !user.IsOrganizer() && !user.IsAdmin() {
-		return errQuestionChangeNotAuthorized
-	}
-
 	var event Event
 	var errGetEvent helios.Error
 	event, errGetEvent = GetEventOfUser(user, eventSlug)
 	if errGetEvent != nil {
 		return errGetEvent
 	}
+	if !auth.Can(user, auth.ActionQuestionEdit, auth.Resource{EventID: event.ID}) {
+		return errQuestionChangeNotAuthorized
+	}
+	if errState := checkEventState(event, questionEditableStates); errState != nil {
+		return errState
+	}
 
+	var questionSaved Question
 	tx := helios.DB.Begin()
 	question.Event = &event
 	// TODO: make sure all choices have the same length
+	var errDB helios.Error
 	if question.ID == 0 {
-		tx.Create(question)
+		errDB = logging.CheckDB(user.RequestID, tx.Create(question))
 	} else {
-		tx.Save(question)
+		errDB = logging.CheckDB(user.RequestID, tx.Where("id = ?", question.ID).First(&questionSaved))
+		if errDB == nil {
+			errDB = logging.CheckDB(user.RequestID, tx.Save(question))
+		}
 	}
-	tx.Commit()
-
-	return nil
+	if errDB == nil && questionSaved.ID == 0 {
+		errDB = auth.RecordAuditLog(tx, user, auditActionQuestionCreate, auditTargetQuestion, question.ID, nil, *question)
+	} else if errDB == nil {
+		errDB = auth.RecordAuditLog(tx, user, auditActionQuestionUpdate, auditTargetQuestion, question.ID, questionSaved, *question)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	return logging.CheckDB(user.RequestID, tx.Commit())
 }
 
 // GetQuestionOfEventAndUser returns a question with given id, but first check
@@ -350,18 +2011,21 @@ func GetQuestionOfEventAndUser(user auth.User, eventSlug string, questionNumber
 		return nil, errGetEvent
 	}
 
-	if !user.IsAdmin() && !user.IsOrganizer() && event.StartsAt.After(time.Now()) {
+	var resource auth.Resource = auth.Resource{EventID: event.ID}
+	if !auth.Can(user, auth.ActionQuestionViewBeforeStart, resource) && event.StartsAt.After(time.Now()) {
 		return nil, errEventIsNotYetStarted
 	}
+	if errCheckIn := checkParticipantCheckedIn(user, event); errCheckIn != nil {
+		return nil, errCheckIn
+	}
 
-	if user.IsAdmin() || user.IsOrganizer() || user.IsLocal() {
-		helios.DB.
+	var query *gorm.DB
+	if auth.Can(user, auth.ActionQuestionView, resource) {
+		query = helios.DB.
 			Where("event_id = ?", event.ID).
-			Order("questions.id asc").
-			Offset(questionNumber - 1).
-			First(&question)
+			Order("questions.id asc")
 	} else {
-		helios.DB.
+		query = helios.DB.
 			Select("questions.*, user_questions.answer as user_answer").
 			Table("questions").
 			Joins("inner join user_questions on user_questions.question_id = questions.id").
@@ -369,9 +2033,10 @@ func GetQuestionOfEventAndUser(user auth.User, eventSlug string, questionNumber
 			Where("questions.event_id = ?", event.ID).
 			Where("participations.user_id = ?", user.ID).
 			Where("participations.event_id = ?", event.ID).
-			Order("user_questions.ordering asc").
-			Offset(questionNumber - 1).
-			First(&question)
+			Order("user_questions.ordering asc")
+	}
+	if errDB := logging.CheckDB(user.RequestID, query.Offset(questionNumber-1).First(&question)); errDB != nil {
+		return nil, errDB
 	}
 	if question.ID == 0 {
 		return nil, errQuestionNotFound
@@ -380,13 +2045,9 @@ func GetQuestionOfEventAndUser(user auth.User, eventSlug string, questionNumber
 }
 
 // DeleteQuestion deletes a question with given id
-// and returns the deleted question. Only organizer and
-// admin that can do deletion
+// and returns the deleted question. Only user permitted to
+// edit questions of the event that can do deletion
 func DeleteQuestion(user auth.User, eventSlug string, questionNumber uint) (*Question, helios.Error) {
-	if !user.IsOrganizer() && !user.IsAdmin() {
-		return nil, errQuestionChangeNotAuthorized
-	}
-
 	var event Event
 	var question Question
 	var errGetEvent helios.Error
@@ -395,25 +2056,46 @@ func DeleteQuestion(user auth.User, eventSlug string, questionNumber uint) (*Que
 	if errGetEvent != nil {
 		return nil, errGetEvent
 	}
+	if !auth.Can(user, auth.ActionQuestionEdit, auth.Resource{EventID: event.ID}) {
+		return nil, errQuestionChangeNotAuthorized
+	}
+	if errState := checkEventState(event, questionEditableStates); errState != nil {
+		return nil, errState
+	}
 
-	helios.DB.
+	errDB := logging.CheckDB(user.RequestID, helios.DB.
 		Where("event_id = ?", event.ID).
 		Order("questions.id asc").
-		Offset(questionNumber - 1).
-		First(&question)
+		Offset(questionNumber-1).
+		First(&question))
+	if errDB != nil {
+		return nil, errDB
+	}
 	if question.ID == 0 {
 		return nil, errQuestionNotFound
 	}
 	tx := helios.DB.Begin()
-	tx.Where("question_id = ?", question.ID).Delete(UserQuestion{})
-	tx.Delete(&question)
-	tx.Commit()
+	errDB = logging.CheckDB(user.RequestID, tx.Where("question_id = ?", question.ID).Delete(UserQuestion{}))
+	if errDB == nil {
+		errDB = logging.CheckDB(user.RequestID, tx.Delete(&question))
+	}
+	if errDB == nil {
+		errDB = auth.RecordAuditLog(tx, user, auditActionQuestionDelete, auditTargetQuestion, question.ID, question, nil)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return nil, errDB
+	}
+	if errDB = logging.CheckDB(user.RequestID, tx.Commit()); errDB != nil {
+		return nil, errDB
+	}
 	return &question, nil
 }
 
-// SubmitSubmission submit a submission from user to a question.
+// SubmitSubmission submit a submission from user to a question. The answers
+// are only accepted while the event is running.
 func SubmitSubmission(user auth.User, eventSlug string, questionNumber uint, answer string) (*Question, helios.Error) {
-	if !user.IsParticipant() {
+	if !auth.Can(user, auth.ActionSubmissionSubmit, auth.Resource{}) {
 		return nil, errSubmissionNotAuthorized
 	}
 
@@ -426,11 +2108,17 @@ func SubmitSubmission(user auth.User, eventSlug string, questionNumber uint, ans
 		return nil, errGetEvent
 	}
 
-	if !user.IsAdmin() && !user.IsOrganizer() && event.StartsAt.After(time.Now()) {
+	if event.StartsAt.After(time.Now()) {
 		return nil, errEventIsNotYetStarted
 	}
+	if errState := checkEventState(event, submittableStates); errState != nil {
+		return nil, errState
+	}
+	if errCheckIn := checkParticipantCheckedIn(user, event); errCheckIn != nil {
+		return nil, errCheckIn
+	}
 
-	helios.DB.
+	errDB := logging.CheckDB(user.RequestID, helios.DB.
 		Select("user_questions.*").
 		Table("user_questions").
 		Preload("Question").
@@ -440,58 +2128,86 @@ func SubmitSubmission(user auth.User, eventSlug string, questionNumber uint, ans
 		Where("participations.user_id = ?", user.ID).
 		Where("participations.event_id = ?", event.ID).
 		Order("user_questions.ordering asc").
-		Offset(questionNumber - 1).
-		First(&userQuestion)
-
+		Offset(questionNumber-1).
+		First(&userQuestion))
+	if errDB != nil {
+		return nil, errDB
+	}
 	if userQuestion.ID == 0 {
 		return nil, errQuestionNotFound
 	}
 
+	var answerBefore string = userQuestion.Answer
 	userQuestion.Answer = answer
 	userQuestion.Question.UserAnswer = answer
-	helios.DB.Save(&userQuestion)
+	tx := helios.DB.Begin()
+	errDB = logging.CheckDB(user.RequestID, tx.Save(&userQuestion))
+	if errDB == nil {
+		errDB = auth.RecordAuditLog(tx, user, auditActionSubmissionSubmit, auditTargetSubmission, userQuestion.ID,
+			map[string]interface{}{"Answer": answerBefore}, map[string]interface{}{"Answer": answer})
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return nil, errDB
+	}
+	if errDB = logging.CheckDB(user.RequestID, tx.Commit()); errDB != nil {
+		return nil, errDB
+	}
+	submissionCounter.Inc(event.Slug)
 	userQuestion.Question.ID = questionNumber
 	return userQuestion.Question, nil
 }
 
-// GetParticipationStatus returns status of all participants
+// GetParticipationStatus returns status of all participants on the venues
+// that the user is permitted to monitor
 func GetParticipationStatus(user auth.User, eventSlug string) ([]ParticipationStatus, helios.Error) {
-	if !user.IsLocal() {
-		return nil, errParticipationStatusAccessNotAuthorized
-	}
 	var event Event
 	var errGetEvent helios.Error
 	event, errGetEvent = GetEventOfUser(user, eventSlug)
 	if errGetEvent != nil {
 		return nil, errGetEvent
 	}
+	var allVenues bool
+	var venueIDs []uint
+	allVenues, venueIDs = auth.GetVenueIDsOfPermission(user, auth.ActionParticipationMonitor, event.ID)
+	if !allVenues && len(venueIDs) == 0 {
+		return nil, errParticipationStatusAccessNotAuthorized
+	}
 
 	var status []ParticipationStatus
-	helios.DB.
-		Select("users.username as user_username, sessions.ip_address, sessions.created_at as login_at, sessions.id as session_id, users.session_locked as user_session_locked").
+	var query = helios.DB.
+		Select("users.username as user_username, sessions.ip_address, sessions.created_at as login_at, sessions.last_seen_at, sessions.id as session_id, participations.attendance as attendance").
 		Table("participations").
 		Joins("left join users on (users.id = participations.user_id and users.deleted_at is null)").
 		Joins("left join sessions on (sessions.user_id = users.id and sessions.deleted_at is null)").
 		Where("event_id = ?", event.ID).
 		Where("users.role = ?", auth.UserRoleParticipant).
-		Where("participations.deleted_at is null").
-		Find(&status)
+		Where("participations.deleted_at is null")
+	if !allVenues {
+		query = query.Where("participations.venue_id in (?)", venueIDs)
+	}
+	if errDB := logging.CheckDB(user.RequestID, query.Find(&status)); errDB != nil {
+		return nil, errDB
+	}
 	return status, nil
 }
 
 // RemoveParticipationSession removes session to force user logout
 func RemoveParticipationSession(user auth.User, eventSlug string, sessionID uint) helios.Error {
-	if !user.IsLocal() {
-		return errParticipationStatusAccessNotAuthorized
-	}
 	var event Event
 	var errGetEvent helios.Error
 	event, errGetEvent = GetEventOfUser(user, eventSlug)
 	if errGetEvent != nil {
 		return errGetEvent
 	}
+	var allVenues bool
+	var venueIDs []uint
+	allVenues, venueIDs = auth.GetVenueIDsOfPermission(user, auth.ActionParticipationMonitor, event.ID)
+	if !allVenues && len(venueIDs) == 0 {
+		return errParticipationStatusAccessNotAuthorized
+	}
 	var session auth.Session
-	helios.DB.
+	var query = helios.DB.
 		Select("sessions.*").
 		Table("participations").
 		Joins("left join users on (users.id = participations.user_id and users.deleted_at is null)").
@@ -500,61 +2216,116 @@ func RemoveParticipationSession(user auth.User, eventSlug string, sessionID uint
 		Where("users.role = ?", auth.UserRoleParticipant).
 		Where("sessions.id = ?", sessionID).
 		Where("sessions.deleted_at is null").
-		Where("participations.deleted_at is null").
-		First(&session)
+		Where("participations.deleted_at is null")
+	if !allVenues {
+		query = query.Where("participations.venue_id in (?)", venueIDs)
+	}
+	if errDB := logging.CheckDB(user.RequestID, query.First(&session)); errDB != nil {
+		return errDB
+	}
 	if session.ID == 0 {
 		return errParticipationStatusNotFound
 	}
-	helios.DB.Delete(auth.Session{}, "id = ?", session.ID)
-	return nil
+	tx := helios.DB.Begin()
+	errDB := logging.CheckDB(user.RequestID, tx.Delete(auth.Session{}, "id = ?", session.ID))
+	if errDB == nil {
+		errDB = auth.RecordAuditLog(tx, user, auditActionSessionRemove, auditTargetSession, session.ID, session, nil)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	return logging.CheckDB(user.RequestID, tx.Commit())
+}
+
+// SynchronizationPayload is the data of the event on the venue that the local
+// server gets from the central server. The participation of each user is
+// keyed by the username, because the users get other IDs on the local server.
+type SynchronizationPayload struct {
+	Event          Event
+	Venue          Venue
+	Rooms          []Room
+	Questions      []Question
+	Users          []auth.User
+	Participations map[string]SynchronizedParticipation
+}
+
+// SynchronizedParticipation is the participation of a user in the synchronization
+// data. RoomName is empty if the seat is not assigned.
+type SynchronizedParticipation struct {
+	KeyHashedTwice string
+	SecretShareY   string
+	RoomName       string
+	SeatNumber     uint
 }
 
-// GetSynchronizationData gets the synchronization data of event.
-// Only local user has the permission
-func GetSynchronizationData(user auth.User, eventSlug string) (*Event, *Venue, []Question, []auth.User, map[string]string, map[string]string, helios.Error) {
-	if !user.IsLocal() {
-		return nil, nil, nil, nil, nil, nil, errSynchronizationNotAuthorized
+// GetSynchronizationData gets the synchronization data of event on
+// the venue of the user. Only user permitted to synchronize has the permission
+func GetSynchronizationData(user auth.User, eventSlug string) (*SynchronizationPayload, helios.Error) {
+	if !auth.Can(user, auth.ActionEventSynchronize, auth.Resource{}) {
+		return nil, errSynchronizationNotAuthorized
 	}
 
 	var participation Participation
-	helios.DB.
+	errDB := logging.CheckDB(user.RequestID, helios.DB.
 		Table("participations").
 		Select("participations.*").
 		Preload("Venue").
 		Joins("inner join events on events.id = participations.event_id").
 		Where("participations.user_id = ?", user.ID).
 		Where("events.slug = ?", eventSlug).
-		First(&participation)
+		First(&participation))
+	if errDB != nil {
+		return nil, errDB
+	}
 	if participation.ID == 0 {
-		return nil, nil, nil, nil, nil, nil, errEventNotFound
+		return nil, errEventNotFound
 	}
 
 	var event Event
+	var rooms []Room
 	var questions []Question
 	var users []auth.User
 	var participations []Participation
-	var usersKey map[string]string
-	var usersY map[string]string
 	var secretShare SecretShare
-	helios.DB.Where("id = ?", participation.EventID).First(&event)
-	helios.DB.Where("event_id = ?", event.ID).Find(&questions)
-	helios.DB.
-		Select("users.*").
-		Joins("inner join participations on participations.user_id = users.id").
-		Where("participations.event_id = ?", event.ID).
-		Where("participations.venue_id = ?", participation.Venue.ID).
-		Find(&users)
-	helios.DB.
-		Select("participations.*").
-		Preload("User").
-		Where("participations.event_id = ?", event.ID).
-		Where("participations.venue_id = ?", participation.Venue.ID).
-		Find(&participations)
-	helios.DB.
-		Where("event_id = ?", event.ID).
-		Where("venue_id = ?", participation.Venue.ID).
-		First(&secretShare)
+	if errDB = logging.CheckDB(user.RequestID, helios.DB.Where("id = ?", participation.EventID).First(&event)); errDB != nil {
+		return nil, errDB
+	}
+	if errState := checkEventState(event, synchronizableStates); errState != nil {
+		return nil, errState
+	}
+	errDB = logging.CheckDB(user.RequestID, helios.DB.Where("venue_id = ?", participation.Venue.ID).Order("id asc").Find(&rooms))
+	if errDB == nil {
+		errDB = logging.CheckDB(user.RequestID, helios.DB.Where("event_id = ?", event.ID).Find(&questions))
+	}
+	if errDB == nil {
+		errDB = logging.CheckDB(user.RequestID, helios.DB.
+			Select("users.*").
+			Joins("inner join participations on participations.user_id = users.id").
+			Where("participations.event_id = ?", event.ID).
+			Where("participations.venue_id = ?", participation.Venue.ID).
+			Find(&users))
+	}
+	if errDB == nil {
+		errDB = logging.CheckDB(user.RequestID, helios.DB.
+			Select("participations.*").
+			Preload("User").
+			Preload("Room").
+			Where("participations.event_id = ?", event.ID).
+			Where("participations.venue_id = ?", participation.Venue.ID).
+			Find(&participations))
+	}
+	if errDB == nil {
+		errDB = logging.CheckDB(user.RequestID, helios.DB.
+			Where("event_id = ?", event.ID).
+			Where("venue_id = ?", participation.Venue.ID).
+			First(&secretShare))
+	}
+	if errDB != nil {
+		return nil, errDB
+	}
 	var polynomCoeffs []big.Int
+	tx := helios.DB.Begin()
 	if secretShare.ID == 0 {
 		// TODO: change to 90%
 		polynomCoeffs = generateNRandomBigInt(len(participations))
@@ -570,7 +2341,10 @@ func GetSynchronizationData(user auth.User, eventSlug string) (*Event, *Venue, [
 			Event:         participation.Event,
 			PolynomCoeffs: polynomCoeffsString,
 		}
-		helios.DB.Create(&secretShare)
+		if errDB = logging.CheckDB(user.RequestID, tx.Create(&secretShare)); errDB != nil {
+			tx.Rollback()
+			return nil, errDB
+		}
 	} else {
 		var coeffs = strings.Split(secretShare.PolynomCoeffs, "|")
 		for _, coeffStr := range coeffs {
@@ -591,48 +2365,112 @@ func GetSynchronizationData(user auth.User, eventSlug string) (*Event, *Venue, [
 		}
 		y = y.Mod(y, PRIME)
 		participations[pI].SecretShareY = y.String()
-		helios.DB.Save(&participations[pI])
+		if errDB = logging.CheckDB(user.RequestID, tx.Save(&participations[pI])); errDB != nil {
+			tx.Rollback()
+			return nil, errDB
+		}
 	}
 
-	err := encryptQuestions(questions, event.SimKey)
-	if err != nil {
-		return nil, nil, nil, nil, nil, nil, helios.ErrInternalServerError
+	if errDB = logging.CheckError(user.RequestID, encryptQuestions(questions, event.SimKey)); errDB != nil {
+		tx.Rollback()
+		return nil, errDB
 	}
 
-	usersKey = make(map[string]string)
-	usersY = make(map[string]string)
+	var synchronizedParticipations map[string]SynchronizedParticipation = make(map[string]SynchronizedParticipation)
 	for _, participation := range participations {
-		usersKey[participation.User.Username] = participation.KeyHashedTwice
-		usersY[participation.User.Username] = participation.SecretShareY
+		var synchronizedParticipation SynchronizedParticipation = SynchronizedParticipation{
+			KeyHashedTwice: participation.KeyHashedTwice,
+			SecretShareY:   participation.SecretShareY,
+		}
+		if participation.Room != nil {
+			synchronizedParticipation.RoomName = participation.Room.Name
+			synchronizedParticipation.SeatNumber = participation.SeatNumber
+		}
+		synchronizedParticipations[participation.User.Username] = synchronizedParticipation
+	}
+	if event.State == EventStatePublished {
+		event.State = EventStateSynced
+		errDB = logging.CheckDB(user.RequestID, tx.Model(&event).Update("state", EventStateSynced))
+		if errDB == nil {
+			errDB = auth.RecordAuditLog(tx, user, auditActionEventTransition, auditTargetEvent, event.ID, map[string]interface{}{"State": EventStatePublished}, map[string]interface{}{"State": EventStateSynced})
+		}
+	}
+	if errDB == nil {
+		errDB = auth.RecordAuditLog(tx, user, auditActionSynchronizationGet, auditTargetEvent, event.ID, nil, map[string]interface{}{
+			"VenueID": participation.Venue.ID,
+		})
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return nil, errDB
+	}
+	if errDB = logging.CheckDB(user.RequestID, tx.Commit()); errDB != nil {
+		return nil, errDB
 	}
 	event.SimKey = ""
-
-	return &event, participation.Venue, questions, users, usersKey, usersY, nil
+	syncOperationCounter.Inc(event.Slug, syncOperationGet)
+
+	return &SynchronizationPayload{
+		Event:          event,
+		Venue:          *participation.Venue,
+		Rooms:          rooms,
+		Questions:      questions,
+		Users:          users,
+		Participations: synchronizedParticipations,
+	}, nil
 }
 
-// PutSynchronizationData puts the synchronization data of event.
-// Only local user has the permission
-func PutSynchronizationData(user auth.User, event Event, venue Venue, questions []Question, users []auth.User, usersKey map[string]string, usersY map[string]string) helios.Error {
-	if !user.IsLocal() {
+// PutSynchronizationData puts the synchronization data of event. The user is
+// assigned as the proctor and venue manager of the venue.
+// Only user permitted to synchronize has the permission
+func PutSynchronizationData(user auth.User, payload SynchronizationPayload) helios.Error {
+	if !auth.Can(user, auth.ActionEventSynchronize, auth.Resource{}) {
 		return errSynchronizationNotAuthorized
 	}
 
+	var event Event = payload.Event
+	var venue Venue = payload.Venue
+	var rooms []Room = payload.Rooms
+	var questions []Question = payload.Questions
+	var users []auth.User = payload.Users
 	var eventSaved Event
 	var userParticipation Participation
+	var roomIDByName map[string]uint = make(map[string]uint)
+
+	// the event that has started on this server can't be overwritten
+	if errDB := logging.CheckDB(user.RequestID, helios.DB.Where("slug = ?", event.Slug).First(&eventSaved)); errDB != nil {
+		return errDB
+	}
+	if eventSaved.ID != 0 && checkEventState(eventSaved, append([]string{EventStateSynced}, participationEditableStates...)) != nil {
+		return errEventStateInvalid
+	}
 
 	tx := helios.DB.Begin()
 	venue.ID = 0
-	tx.Create(&venue)
+	errDB := logging.CheckDB(user.RequestID, tx.Create(&venue))
+	for i := range rooms {
+		if errDB != nil {
+			break
+		}
+		rooms[i].ID = 0
+		rooms[i].VenueID = venue.ID
+		errDB = logging.CheckDB(user.RequestID, tx.Create(&rooms[i]))
+		roomIDByName[rooms[i].Name] = rooms[i].ID
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
 
 	// Update or create event and user participation
-	tx.Where("slug = ?", event.Slug).First(&eventSaved)
 	event.LastSynchronization = time.Now()
+	event.State = EventStateSynced
 	if eventSaved.ID == 0 {
 		event.ID = 0
-		tx.Create(&event)
+		errDB = logging.CheckDB(user.RequestID, tx.Create(&event))
 	} else {
 		event.ID = eventSaved.ID
-		tx.Save(&event)
+		errDB = logging.CheckDB(user.RequestID, tx.Save(&event))
 	}
 
 	userParticipation = Participation{
@@ -640,72 +2478,112 @@ func PutSynchronizationData(user auth.User, event Event, venue Venue, questions
 		VenueID: venue.ID,
 		EventID: event.ID,
 	}
-	tx.Create(&userParticipation)
+	if errDB == nil {
+		errDB = logging.CheckDB(user.RequestID, tx.Create(&userParticipation))
+	}
+	if errDB == nil {
+		errDB = logging.CheckError(user.RequestID, assignLocalEventRoles(tx, user.ID, event.ID, venue.ID))
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
 
 	// update or create user
 	for i := range users {
 		var userSaved auth.User
-		tx.Where("username = ?", users[i].Username).First(&userSaved)
-		if userSaved.ID == 0 {
+		errDB = logging.CheckDB(user.RequestID, tx.Where("username = ?", users[i].Username).First(&userSaved))
+		if errDB == nil && userSaved.ID == 0 {
 			users[i].ID = 0
-			tx.Create(&users[i])
-		} else {
+			errDB = logging.CheckDB(user.RequestID, tx.Create(&users[i]))
+		} else if errDB == nil {
 			users[i].ID = userSaved.ID
-			tx.Save(&users[i])
+			errDB = logging.CheckDB(user.RequestID, tx.Save(&users[i]))
+		}
+		if errDB != nil {
+			tx.Rollback()
+			return errDB
 		}
 	}
 	// reset all questions and particpations
-	tx.Exec(`UPDATE user_questions
-		SET deleted_at = time('now')
-		WHERE id IN (
-			SELECT user_questions.id
-			FROM user_questions
-			INNER JOIN questions ON questions.id = user_questions.question_id
-			WHERE questions.event_id = ?
-		)`, event.ID)
-	tx.Delete(Question{}, "event_id = ?", event.ID)
-	tx.Delete(Participation{}, "event_id = ?", event.ID)
+	errDB = logging.CheckDB(user.RequestID, tx.
+		Where("question_id in (?)", tx.Table("questions").Select("id").Where("event_id = ?", event.ID).SubQuery()).
+		Delete(UserQuestion{}))
+	if errDB == nil {
+		errDB = logging.CheckDB(user.RequestID, tx.Delete(Question{}, "event_id = ?", event.ID))
+	}
+	if errDB == nil {
+		errDB = logging.CheckError(user.RequestID, deleteParticipations(tx.Where("event_id = ?", event.ID)))
+	}
 	// create all questions and participations
 	for i := range questions {
+		if errDB != nil {
+			break
+		}
 		questions[i].ID = 0
 		questions[i].Event = &event
 		questions[i].EventID = event.ID
-		tx.Create(&questions[i])
+		errDB = logging.CheckDB(user.RequestID, tx.Create(&questions[i]))
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
 	}
 	for i := range users {
+		var synchronizedParticipation SynchronizedParticipation = payload.Participations[users[i].Username]
 		var participation Participation = Participation{
 			UserID:         users[i].ID,
 			VenueID:        venue.ID,
 			EventID:        event.ID,
-			KeyHashedTwice: usersKey[users[i].Username],
+			KeyHashedTwice: synchronizedParticipation.KeyHashedTwice,
 			// TODO: if the key is malformed and missing user
-			SecretShareY: usersY[users[i].Username],
+			SecretShareY: synchronizedParticipation.SecretShareY,
 		}
-		tx.Create(&participation)
+		participation.SetSeat(roomIDByName[synchronizedParticipation.RoomName], synchronizedParticipation.SeatNumber)
+		errDB = logging.CheckDB(user.RequestID, tx.Create(&participation))
 		for j := range questions {
+			if errDB != nil {
+				break
+			}
 			// TODO: send userquestion instead of all question
-			tx.Create(&UserQuestion{
+			errDB = logging.CheckDB(user.RequestID, tx.Create(&UserQuestion{
 				ParticipationID: participation.ID,
 				QuestionID:      questions[j].ID,
 				Ordering:        uint((j + 1) * 10),
-			})
+			}))
 		}
+		if errDB != nil {
+			tx.Rollback()
+			return errDB
+		}
+	}
+	errDB = auth.RecordAuditLog(tx, user, auditActionSynchronizationPut, auditTargetEvent, event.ID, nil, map[string]interface{}{
+		"VenueID":   venue.ID,
+		"Questions": len(questions),
+		"Users":     len(users),
+	})
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	if errDB = logging.CheckDB(user.RequestID, tx.Commit()); errDB != nil {
+		return errDB
 	}
-	tx.Commit()
+	syncOperationCounter.Inc(event.Slug, syncOperationPut)
 	return nil
 }
 
 // DecryptEventData decrypts all event data that is encrypted on synchronization data
 func DecryptEventData(user auth.User, eventSlug string, simKey string) helios.Error {
-	if !user.IsLocal() {
-		return errDecryptEventForbidden
-	}
 	var event Event
 	var errGetEvent helios.Error
 	event, errGetEvent = GetEventOfUser(user, eventSlug)
 	if errGetEvent != nil {
 		return errGetEvent
 	}
+	if !auth.Can(user, auth.ActionEventDecrypt, auth.Resource{EventID: event.ID}) {
+		return errDecryptEventForbidden
+	}
 	if !event.DecryptedAt.IsZero() {
 		// Already decrypted
 		return nil
@@ -716,19 +2594,20 @@ func DecryptEventData(user auth.User, eventSlug string, simKey string) helios.Er
 	var pubKey *rsa.PublicKey
 	simKeySign, err = base64.StdEncoding.DecodeString(event.SimKeySign)
 	if err != nil {
-		return helios.ErrInternalServerError
+		return logging.CheckError(user.RequestID, err)
 	}
 	simKeyHashed := sha256.Sum256([]byte(simKey))
 	pubKeyMarshalled, err = base64.StdEncoding.DecodeString(event.PubKey)
 	if err != nil {
-		return helios.ErrInternalServerError
+		return logging.CheckError(user.RequestID, err)
 	}
 	pubKey, err = x509.ParsePKCS1PublicKey(pubKeyMarshalled)
 	if err != nil {
-		return helios.ErrInternalServerError
+		return logging.CheckError(user.RequestID, err)
 	}
 	err = rsa.VerifyPSS(pubKey, crypto.SHA256, simKeyHashed[:], simKeySign, nil)
 	if err != nil {
+		decryptionCounter.Inc(event.Slug, decryptionResultFailure)
 		return errDecryptEventFailed
 	}
 
@@ -736,17 +2615,30 @@ func DecryptEventData(user auth.User, eventSlug string, simKey string) helios.Er
 	tx := helios.DB.Begin()
 	event.DecryptedAt = time.Now()
 	event.SimKey = simKey
-	tx.Save(&event)
-	tx.Where("event_id = ?", event.ID).Find(&questions)
-	err = decryptQuestions(questions, simKey)
-	if err != nil {
-		tx.Rollback()
-		return helios.ErrInternalServerError
+	errDB := logging.CheckDB(user.RequestID, tx.Save(&event))
+	if errDB == nil {
+		errDB = logging.CheckDB(user.RequestID, tx.Where("event_id = ?", event.ID).Find(&questions))
+	}
+	if errDB == nil {
+		errDB = logging.CheckError(user.RequestID, decryptQuestions(questions, simKey))
 	}
 	for _, question := range questions {
-		tx.Save(&question)
+		if errDB != nil {
+			break
+		}
+		errDB = logging.CheckDB(user.RequestID, tx.Save(&question))
+	}
+	if errDB == nil {
+		errDB = auth.RecordAuditLog(tx, user, auditActionEventDecrypt, auditTargetEvent, event.ID, nil, nil)
+	}
+	if errDB != nil {
+		tx.Rollback()
+		return errDB
+	}
+	if errDB = logging.CheckDB(user.RequestID, tx.Commit()); errDB != nil {
+		return errDB
 	}
-	tx.Commit()
+	decryptionCounter.Inc(event.Slug, decryptionResultSuccess)
 	return nil
 }
 
@@ -2,6 +2,7 @@ package exam
 
 import (
 	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"strconv"
@@ -159,17 +160,200 @@ func TestDeleteVenue(t *testing.T) {
 	}
 }
 
+func TestGetAllRoomOfVenue(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var venue1 Venue = VenueFactorySaved(Venue{})
+	var venue2 Venue = VenueFactorySaved(Venue{})
+	RoomFactorySaved(Room{Venue: &venue1})
+	RoomFactorySaved(Room{Venue: &venue1})
+	RoomFactorySaved(Room{Venue: &venue2})
+
+	type getAllRoomOfVenueTestCase struct {
+		user           auth.User
+		venueID        uint
+		expectedLength int
+		expectedError  helios.Error
+	}
+	testCases := []getAllRoomOfVenueTestCase{{
+		user:          auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant}),
+		venueID:       venue1.ID,
+		expectedError: errVenueAccessNotAuthorized,
+	}, {
+		user:          auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal}),
+		venueID:       venue1.ID,
+		expectedError: errVenueAccessNotAuthorized,
+	}, {
+		user:          auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		venueID:       999999,
+		expectedError: errVenueNotFound,
+	}, {
+		user:           auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		venueID:        venue1.ID,
+		expectedLength: 2,
+	}, {
+		user:           auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		venueID:        venue2.ID,
+		expectedLength: 1,
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test GetAllRoomOfVenue testcase: %d", i)
+		var rooms []Room
+		var err helios.Error
+		rooms, err = GetAllRoomOfVenue(testCase.user, testCase.venueID)
+		if testCase.expectedError == nil {
+			assert.Nil(t, err)
+			assert.Equal(t, testCase.expectedLength, len(rooms))
+		} else {
+			assert.Equal(t, testCase.expectedError, err)
+		}
+	}
+}
+
+func TestUpsertRoom(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var venue1 Venue = VenueFactorySaved(Venue{})
+	var venue2 Venue = VenueFactorySaved(Venue{})
+	var room1 Room = RoomFactorySaved(Room{Venue: &venue1, Capacity: 10})
+	ParticipationFactorySaved(Participation{Venue: &venue1, RoomID: &room1.ID, SeatNumber: 5})
+
+	type upsertRoomTestCase struct {
+		user              auth.User
+		venueID           uint
+		room              Room
+		expectedRoomCount int
+		expectedError     helios.Error
+	}
+	testCases := []upsertRoomTestCase{{
+		user:              auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal}),
+		venueID:           venue1.ID,
+		room:              Room{Name: "Room A", Capacity: 20},
+		expectedRoomCount: 1,
+		expectedError:     errVenueAccessNotAuthorized,
+	}, {
+		user:              auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		venueID:           999999,
+		room:              Room{Name: "Room A", Capacity: 20},
+		expectedRoomCount: 1,
+		expectedError:     errVenueNotFound,
+	}, {
+		user:              auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		venueID:           venue1.ID,
+		room:              Room{Name: "Room A", Capacity: 20},
+		expectedRoomCount: 2,
+	}, {
+		user:              auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		venueID:           venue2.ID,
+		room:              Room{ID: room1.ID, Name: "Room B", Capacity: 20},
+		expectedRoomCount: 2,
+		expectedError:     errRoomNotFound,
+	}, {
+		user:              auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		venueID:           venue1.ID,
+		room:              Room{ID: room1.ID, Name: "Room B", Capacity: 4},
+		expectedRoomCount: 2,
+		expectedError:     errRoomCapacityTooSmall,
+	}, {
+		user:              auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		venueID:           venue1.ID,
+		room:              Room{ID: room1.ID, Name: "Room B", Capacity: 5},
+		expectedRoomCount: 2,
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test UpsertRoom testcase: %d", i)
+		var roomCount int
+		var roomSaved Room
+		var err helios.Error
+		err = UpsertRoom(testCase.user, testCase.venueID, &testCase.room)
+		helios.DB.Model(&Room{}).Count(&roomCount)
+		assert.Equal(t, testCase.expectedRoomCount, roomCount)
+		if testCase.expectedError == nil {
+			assert.Nil(t, err)
+			helios.DB.Where("id = ?", testCase.room.ID).First(&roomSaved)
+			assert.Equal(t, testCase.venueID, roomSaved.VenueID)
+			assert.Equal(t, testCase.room.Name, roomSaved.Name)
+			assert.Equal(t, testCase.room.Capacity, roomSaved.Capacity)
+		} else {
+			assert.Equal(t, testCase.expectedError, err)
+		}
+	}
+}
+
+func TestDeleteRoom(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var venue1 Venue = VenueFactorySaved(Venue{})
+	var room1 Room = RoomFactorySaved(Room{Venue: &venue1})
+	var room2 Room = RoomFactorySaved(Room{Venue: &venue1})
+	var room3 Room = RoomFactorySaved(Room{})
+	ParticipationFactorySaved(Participation{Venue: &venue1, RoomID: &room2.ID, SeatNumber: 1})
+
+	type deleteRoomTestCase struct {
+		user              auth.User
+		venueID           uint
+		roomID            uint
+		expectedRoomCount int
+		expectedError     helios.Error
+	}
+	testCases := []deleteRoomTestCase{{
+		user:              auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal}),
+		venueID:           venue1.ID,
+		roomID:            room1.ID,
+		expectedRoomCount: 3,
+		expectedError:     errVenueAccessNotAuthorized,
+	}, {
+		user:              auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		venueID:           venue1.ID,
+		roomID:            room3.ID,
+		expectedRoomCount: 3,
+		expectedError:     errRoomNotFound,
+	}, {
+		user:              auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		venueID:           venue1.ID,
+		roomID:            room2.ID,
+		expectedRoomCount: 3,
+		expectedError:     errRoomCantDeletedParticipationExists,
+	}, {
+		user:              auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		venueID:           venue1.ID,
+		roomID:            room1.ID,
+		expectedRoomCount: 2,
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test DeleteRoom testcase: %d", i)
+		var roomCount int
+		var room *Room
+		var err helios.Error
+		room, err = DeleteRoom(testCase.user, testCase.venueID, testCase.roomID)
+		helios.DB.Model(&Room{}).Count(&roomCount)
+		assert.Equal(t, testCase.expectedRoomCount, roomCount)
+		if testCase.expectedError == nil {
+			assert.Nil(t, err)
+			assert.Equal(t, testCase.roomID, room.ID)
+		} else {
+			assert.Equal(t, testCase.expectedError, err)
+		}
+	}
+}
+
 func TestGetAllEventOfUser(t *testing.T) {
 	helios.App.BeforeTest()
 
 	var userParticipant auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
 	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	var userOrganizer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
 	var event1 Event = EventFactorySaved(Event{StartsAt: time.Now().Add(48 * time.Hour)})
 	var event2 Event = EventFactorySaved(Event{StartsAt: time.Now().Add(24 * time.Hour)})
-	EventFactorySaved(Event{StartsAt: time.Now().Add(72 * time.Hour)})
+	var event3 Event = EventFactorySaved(Event{StartsAt: time.Now().Add(72 * time.Hour)})
 	ParticipationFactorySaved(Participation{Event: &event1, User: &userParticipant})
 	ParticipationFactorySaved(Participation{Event: &event2, User: &userParticipant})
 	ParticipationFactorySaved(Participation{Event: &event1, User: &userLocal})
+	EventRoleFactorySaved(userOrganizer, event3, nil, auth.EventRoleAuthor)
+	EventRoleFactorySaved(userOrganizer, event1, nil, auth.EventRoleReviewer)
 
 	type getAllEventOfUserTestCase struct {
 		user               auth.User
@@ -189,15 +373,21 @@ func TestGetAllEventOfUser(t *testing.T) {
 		expectedLength:     3,
 		expectedFirstTitle: event2.Title,
 	}, {
-		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
-		expectedLength:     3,
-		expectedFirstTitle: event2.Title,
+		user:               userOrganizer,
+		expectedLength:     2,
+		expectedFirstTitle: event1.Title,
+	}, {
+		user:           auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		expectedLength: 0,
 	}}
 	for i, testCase := range testCases {
 		t.Logf("Test GetAllEventOfUser testcase: %d", i)
-		events := GetAllEventOfUser(testCase.user)
+		events, err := GetAllEventOfUser(testCase.user)
+		assert.Nil(t, err)
 		assert.Equal(t, testCase.expectedLength, len(events))
-		assert.Equal(t, testCase.expectedFirstTitle, events[0].Title, "Events received should be ordered by start time")
+		if testCase.expectedLength > 0 {
+			assert.Equal(t, testCase.expectedFirstTitle, events[0].Title, "Events received should be ordered by start time")
+		}
 	}
 }
 
@@ -210,6 +400,7 @@ func TestUpsertEvent(t *testing.T) {
 		expectedError              helios.Error
 		expectedEventCount         int
 		expectedParticipationCount int
+		expectedState              string
 	}
 	testCases := []upsertEventTestCase{{
 		user:                       auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant}),
@@ -222,18 +413,21 @@ func TestUpsertEvent(t *testing.T) {
 		event:                      Event{},
 		expectedEventCount:         2,
 		expectedParticipationCount: 1,
+		expectedState:              EventStateDraft,
 	}, {
 		user:                       auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
 		event:                      EventFactory(Event{}),
 		expectedError:              nil,
 		expectedEventCount:         3,
 		expectedParticipationCount: 1,
+		expectedState:              EventStateDraft,
 	}, {
 		user:                       auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
-		event:                      EventFactorySaved(Event{Title: "New Title"}),
+		event:                      EventFactorySaved(Event{Title: "New Title", State: EventStatePublished}),
 		expectedError:              nil,
 		expectedEventCount:         3,
 		expectedParticipationCount: 1,
+		expectedState:              EventStatePublished,
 	}}
 	for i, testCase := range testCases {
 		var eventCount int
@@ -249,6 +443,14 @@ func TestUpsertEvent(t *testing.T) {
 		if testCase.expectedError == nil {
 			assert.Nil(t, err)
 			assert.Equal(t, testCase.event.Title, eventSaved.Title, "If the event has already existed, it should be updated")
+			assert.Equal(t, testCase.expectedState, eventSaved.State)
+			var auditLog auth.AuditLog
+			helios.DB.Last(&auditLog)
+			assert.Equal(t, testCase.user.ID, auditLog.ActorID)
+			assert.Equal(t, testCase.event.ID, auditLog.TargetID)
+			if eventSaved.SimKey != "" {
+				assert.NotContains(t, auditLog.After, eventSaved.SimKey, "Secret key should not be recorded on audit log")
+			}
 			if !testCase.user.IsLocal() {
 				assert.NotEmpty(t, eventSaved.SimKey)
 				assert.NotEmpty(t, eventSaved.PubKey)
@@ -270,16 +472,624 @@ func TestUpsertEvent(t *testing.T) {
 	}
 }
 
+func TestUpdateEvent(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userAdmin auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	var userReviewer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	var eventDraft Event = EventFactorySaved(Event{})
+	var eventSynced Event = EventFactorySaved(Event{State: EventStateSynced})
+	var eventDecrypted Event = EventFactorySaved(Event{DecryptedAt: time.Now()})
+	helios.DB.Model(&eventDraft).Update("decrypted_at", time.Time{})
+	for _, event := range []Event{eventDraft, eventSynced, eventDecrypted} {
+		EventRoleFactorySaved(userAuthor, event, nil, auth.EventRoleAuthor)
+		EventRoleFactorySaved(userReviewer, event, nil, auth.EventRoleReviewer)
+	}
+
+	type updateEventTestCase struct {
+		user          auth.User
+		eventSlug     string
+		event         Event
+		force         bool
+		expectedError helios.Error
+	}
+	testCases := []updateEventTestCase{{
+		user:          userAuthor,
+		eventSlug:     "random",
+		event:         Event{Slug: "random", Title: "New Title"},
+		expectedError: errEventNotFound,
+	}, {
+		user:          userReviewer,
+		eventSlug:     eventDraft.Slug,
+		event:         Event{Slug: eventDraft.Slug, Title: "New Title"},
+		expectedError: errEventChangeNotAuthorized,
+	}, {
+		user:          userAuthor,
+		eventSlug:     eventDraft.Slug,
+		event:         Event{Slug: eventSynced.Slug, Title: "New Title"},
+		expectedError: errEventSlugTaken,
+	}, {
+		user:          userAuthor,
+		eventSlug:     eventSynced.Slug,
+		event:         Event{Slug: eventSynced.Slug, Title: "New Title"},
+		expectedError: errEventLocked,
+	}, {
+		user:          userAuthor,
+		eventSlug:     eventDecrypted.Slug,
+		event:         Event{Slug: eventDecrypted.Slug, Title: "New Title"},
+		force:         true,
+		expectedError: errEventLocked,
+	}, {
+		user:      userAuthor,
+		eventSlug: eventDraft.Slug,
+		event:     Event{Slug: "new-slug", Title: "New Title"},
+	}, {
+		user:      userAdmin,
+		eventSlug: eventSynced.Slug,
+		event:     Event{Slug: eventSynced.Slug, Title: "New Title"},
+		force:     true,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test UpdateEvent testcase: %d", i)
+		var event Event = testCase.event
+		var err helios.Error = UpdateEvent(testCase.user, testCase.eventSlug, &event, testCase.force)
+		if testCase.expectedError == nil {
+			var eventBefore, eventSaved Event
+			helios.DB.Unscoped().Where("slug = ?", testCase.eventSlug).First(&eventBefore)
+			helios.DB.Where("slug = ?", testCase.event.Slug).First(&eventSaved)
+			assert.Nil(t, err)
+			assert.Equal(t, "New Title", eventSaved.Title)
+			assert.Equal(t, event.ID, eventSaved.ID)
+			assert.Equal(t, event.Slug, eventSaved.Slug)
+			assert.NotEmpty(t, eventSaved.SimKey, "Keys should be kept")
+			assert.NotEmpty(t, eventSaved.PrvKey, "Keys should be kept")
+			assert.NotEmpty(t, eventSaved.State, "State should be kept")
+		} else {
+			var eventSaved Event
+			helios.DB.Where("slug = ?", testCase.eventSlug).First(&eventSaved)
+			assert.Equal(t, testCase.expectedError, err)
+			assert.NotEqual(t, "New Title", eventSaved.Title)
+		}
+	}
+}
+
+func TestDeleteEvent(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userAdmin auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	var venue Venue = VenueFactorySaved(Venue{})
+	var event1 Event = EventFactorySaved(Event{})
+	var event2 Event = EventFactorySaved(Event{})
+	var eventSynced Event = EventFactorySaved(Event{State: EventStateSynced})
+	helios.DB.Model(&event1).Update("decrypted_at", time.Time{})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	EventRoleFactorySaved(userAuthor, eventSynced, nil, auth.EventRoleAuthor)
+	for _, event := range []Event{event1, event2, eventSynced} {
+		var eventOfData Event = event
+		var question Question = QuestionFactorySaved(Question{Event: &eventOfData})
+		var participation Participation = ParticipationFactorySaved(Participation{Event: &eventOfData, Venue: &venue})
+		UserQuestionFactorySaved(UserQuestion{Question: &question, Participation: &participation})
+		helios.DB.Create(&SecretShare{Event: &eventOfData, Venue: &venue, PolynomCoeffs: "1|2"})
+		helios.DB.Create(&auth.APIToken{UserID: userAuthor.ID, TokenHashed: event.Slug, Scopes: []auth.APITokenScope{{EventID: event.ID}}})
+	}
+
+	type deleteEventTestCase struct {
+		user          auth.User
+		eventSlug     string
+		force         bool
+		expectedError helios.Error
+	}
+	testCases := []deleteEventTestCase{{
+		user:          userAuthor,
+		eventSlug:     event2.Slug,
+		expectedError: errEventNotFound,
+	}, {
+		user:          userAuthor,
+		eventSlug:     eventSynced.Slug,
+		force:         true,
+		expectedError: errEventLocked,
+	}, {
+		user:      userAuthor,
+		eventSlug: event1.Slug,
+	}, {
+		user:      userAdmin,
+		eventSlug: eventSynced.Slug,
+		force:     true,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test DeleteEvent testcase: %d", i)
+		var eventSaved Event
+		var questionCount, participationCount, userQuestionCount, secretShareCount, eventRoleCount, apiTokenScopeCount int
+		helios.DB.Unscoped().Where("slug = ?", testCase.eventSlug).First(&eventSaved)
+		event, err := DeleteEvent(testCase.user, testCase.eventSlug, testCase.force)
+		helios.DB.Model(&Question{}).Where("event_id = ?", eventSaved.ID).Count(&questionCount)
+		helios.DB.Model(&Participation{}).Where("event_id = ?", eventSaved.ID).Count(&participationCount)
+		helios.DB.Model(&SecretShare{}).Where("event_id = ?", eventSaved.ID).Count(&secretShareCount)
+		helios.DB.Model(&auth.EventRole{}).Where("event_id = ?", eventSaved.ID).Count(&eventRoleCount)
+		helios.DB.Model(&auth.APITokenScope{}).Where("event_id = ?", eventSaved.ID).Count(&apiTokenScopeCount)
+		helios.DB.
+			Model(&UserQuestion{}).
+			Joins("inner join questions on questions.id = user_questions.question_id").
+			Where("questions.event_id = ?", eventSaved.ID).
+			Count(&userQuestionCount)
+		if testCase.expectedError == nil {
+			var eventCount int
+			helios.DB.Model(&Event{}).Where("id = ?", eventSaved.ID).Count(&eventCount)
+			assert.Nil(t, err)
+			assert.Equal(t, eventSaved.ID, event.ID)
+			assert.Equal(t, 0, eventCount)
+			assert.Equal(t, 0, questionCount)
+			assert.Equal(t, 0, participationCount)
+			assert.Equal(t, 0, secretShareCount)
+			assert.Equal(t, 0, eventRoleCount)
+			assert.Equal(t, 0, apiTokenScopeCount)
+			assert.Equal(t, 0, userQuestionCount)
+		} else {
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, 1, questionCount)
+			assert.Equal(t, 1, participationCount)
+			assert.Equal(t, 1, secretShareCount)
+			assert.Equal(t, 1, apiTokenScopeCount)
+			assert.Equal(t, 1, userQuestionCount)
+		}
+	}
+	var userQuestionCount int
+	helios.DB.Model(&UserQuestion{}).Count(&userQuestionCount)
+	assert.Equal(t, 1, userQuestionCount, "Answers of other event should not be deleted")
+
+	// the slug of the deleted event can be used by a new event
+	var eventRecreated Event = EventFactory(Event{Slug: event1.Slug})
+	eventRecreated.DecryptedAt = time.Time{}
+	assert.Nil(t, UpsertEvent(userAuthor, &eventRecreated))
+	assert.NotEqual(t, event1.ID, eventRecreated.ID)
+	var eventDeleted Event
+	helios.DB.Unscoped().Where("id = ?", event1.ID).First(&eventDeleted)
+	assert.Equal(t, fmt.Sprintf("%s~deleted-%d", event1.Slug, event1.ID), eventDeleted.Slug)
+	var eventGot Event
+	var errGet helios.Error
+	eventGot, errGet = GetEventOfUser(userAuthor, event1.Slug)
+	assert.Nil(t, errGet)
+	assert.Equal(t, eventRecreated.ID, eventGot.ID)
+	_, errDelete := DeleteEvent(userAuthor, event1.Slug, false)
+	assert.Nil(t, errDelete, "Recreated event should be deleted again")
+}
+
+func TestCloneEvent(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	var userReviewer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	var userOrganizer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	var venue Venue = VenueFactorySaved(Venue{})
+	var room Room = RoomFactorySaved(Room{Venue: &venue, Capacity: 10})
+	var source Event = EventFactorySaved(Event{State: EventStateClosed, DecryptedAt: time.Now()})
+	EventRoleFactorySaved(userAuthor, source, nil, auth.EventRoleAuthor)
+	EventRoleFactorySaved(userReviewer, source, nil, auth.EventRoleReviewer)
+	EventRoleFactorySaved(userOrganizer, source, &venue, auth.EventRoleVenueManager)
+	QuestionFactorySaved(Question{Event: &source, Content: "question 1", Choices: "a|b"})
+	QuestionFactorySaved(Question{Event: &source, Content: "question 2", Choices: "c|d"})
+	var participations []Participation = []Participation{
+		ParticipationFactorySaved(Participation{Event: &source, Venue: &venue, RoomID: &room.ID, SeatNumber: 3, KeyPlain: "key1", Attendance: AttendancePresent}),
+		ParticipationFactorySaved(Participation{Event: &source, Venue: &venue, User: &userLocal}),
+	}
+
+	type cloneEventTestCase struct {
+		user                    auth.User
+		eventSlug               string
+		clone                   Event
+		includeParticipations   bool
+		includeVenueAssignments bool
+		expectedQuestionCount   int
+		expectedEventRoleCount  int
+		expectedParticipations  int
+		expectedSeatNumber      uint
+		expectedError           helios.Error
+	}
+	testCases := []cloneEventTestCase{{
+		user:          userAuthor,
+		eventSlug:     "random",
+		clone:         Event{Slug: "clone-1"},
+		expectedError: errEventNotFound,
+	}, {
+		user:          userReviewer,
+		eventSlug:     source.Slug,
+		clone:         Event{Slug: "clone-1"},
+		expectedError: errEventChangeNotAuthorized,
+	}, {
+		user:          userAuthor,
+		eventSlug:     source.Slug,
+		clone:         Event{Slug: source.Slug},
+		expectedError: errEventSlugTaken,
+	}, {
+		user:                   userAuthor,
+		eventSlug:              source.Slug,
+		clone:                  Event{Slug: "clone-1", Title: "Clone 1"},
+		expectedQuestionCount:  2,
+		expectedEventRoleCount: 2,
+	}, {
+		user:                   userAuthor,
+		eventSlug:              source.Slug,
+		clone:                  Event{Slug: "clone-2"},
+		includeParticipations:  true,
+		expectedQuestionCount:  2,
+		expectedEventRoleCount: 4,
+		expectedParticipations: 2,
+	}, {
+		user:                    userAuthor,
+		eventSlug:               source.Slug,
+		clone:                   Event{Slug: "clone-3"},
+		includeParticipations:   true,
+		includeVenueAssignments: true,
+		expectedQuestionCount:   2,
+		expectedEventRoleCount:  5,
+		expectedParticipations:  2,
+		expectedSeatNumber:      3,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test CloneEvent testcase: %d", i)
+		var clone Event = testCase.clone
+		var err helios.Error = CloneEvent(testCase.user, testCase.eventSlug, &clone, testCase.includeParticipations, testCase.includeVenueAssignments)
+		if testCase.expectedError == nil {
+			var cloneSaved Event
+			var questions []Question
+			var participationsCloned []Participation
+			var eventRoleCount int
+			assert.Nil(t, err)
+			helios.DB.Where("slug = ?", testCase.clone.Slug).First(&cloneSaved)
+			helios.DB.Where("event_id = ?", cloneSaved.ID).Order("id asc").Find(&questions)
+			helios.DB.Where("event_id = ?", cloneSaved.ID).Order("id asc").Find(&participationsCloned)
+			helios.DB.Model(&auth.EventRole{}).Where("event_id = ?", cloneSaved.ID).Count(&eventRoleCount)
+			assert.NotZero(t, cloneSaved.ID)
+			assert.Equal(t, EventStateDraft, cloneSaved.State)
+			assert.True(t, cloneSaved.DecryptedAt.IsZero())
+			assert.Equal(t, source.Description, cloneSaved.Description)
+			if testCase.clone.Title == "" {
+				assert.Equal(t, source.Title, cloneSaved.Title)
+			} else {
+				assert.Equal(t, testCase.clone.Title, cloneSaved.Title)
+			}
+			assert.NotEmpty(t, cloneSaved.SimKey)
+			assert.NotEqual(t, source.SimKey, cloneSaved.SimKey, "Keys should never be reused")
+			assert.NotEqual(t, source.PrvKey, cloneSaved.PrvKey, "Keys should never be reused")
+			assert.NotEqual(t, source.PubKey, cloneSaved.PubKey, "Keys should never be reused")
+			assert.NotEqual(t, source.SimKeySign, cloneSaved.SimKeySign, "Keys should never be reused")
+			assert.Equal(t, testCase.expectedQuestionCount, len(questions))
+			assert.Equal(t, "question 1", questions[0].Content)
+			assert.Equal(t, "a|b", questions[0].Choices)
+			assert.Equal(t, testCase.expectedEventRoleCount, eventRoleCount)
+			assert.Equal(t, testCase.expectedParticipations, len(participationsCloned))
+			if testCase.expectedParticipations > 0 {
+				assert.Equal(t, participations[0].UserID, participationsCloned[0].UserID)
+				assert.Equal(t, participations[0].VenueID, participationsCloned[0].VenueID)
+				assert.Equal(t, testCase.expectedSeatNumber, participationsCloned[0].SeatNumber)
+				assert.Empty(t, participationsCloned[0].Attendance)
+				assert.NotEmpty(t, participationsCloned[0].KeyPlain)
+				assert.NotEqual(t, participations[0].KeyPlain, participationsCloned[0].KeyPlain, "Participation key should never be reused")
+				assert.NotEqual(t, participations[0].KeyHashedTwice, participationsCloned[0].KeyHashedTwice, "Participation key should never be reused")
+			}
+		} else {
+			var eventCount int
+			helios.DB.Model(&Event{}).Count(&eventCount)
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, 1, eventCount, "Event should not be created on error")
+		}
+	}
+}
+
+func TestTransitionEvent(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userAdmin auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	var userGrader auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	var userProctor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	var venue Venue = VenueFactorySaved(Venue{})
+	var eventDraft Event = EventFactorySaved(Event{})
+	var eventPublished Event = EventFactorySaved(Event{State: EventStatePublished})
+	var eventSynced Event = EventFactorySaved(Event{State: EventStateSynced})
+	var eventClosed Event = EventFactorySaved(Event{State: EventStateClosed})
+	for _, event := range []Event{eventDraft, eventPublished, eventSynced, eventClosed} {
+		EventRoleFactorySaved(userAuthor, event, nil, auth.EventRoleAuthor)
+		EventRoleFactorySaved(userGrader, event, nil, auth.EventRoleGrader)
+		EventRoleFactorySaved(userProctor, event, &venue, auth.EventRoleProctor)
+	}
+
+	type transitionEventTestCase struct {
+		user          auth.User
+		eventSlug     string
+		state         string
+		expectedState string
+		expectedError helios.Error
+	}
+	testCases := []transitionEventTestCase{{
+		user:          userAdmin,
+		eventSlug:     "random",
+		state:         EventStateReview,
+		expectedError: errEventNotFound,
+	}, {
+		user:          userAuthor,
+		eventSlug:     eventDraft.Slug,
+		state:         EventStatePublished,
+		expectedState: EventStateDraft,
+		expectedError: errEventTransitionInvalid,
+	}, {
+		user:          userAuthor,
+		eventSlug:     eventDraft.Slug,
+		state:         "random",
+		expectedState: EventStateDraft,
+		expectedError: errEventTransitionInvalid,
+	}, {
+		user:          userGrader,
+		eventSlug:     eventDraft.Slug,
+		state:         EventStateReview,
+		expectedState: EventStateDraft,
+		expectedError: errEventTransitionNotAuthorized,
+	}, {
+		user:          userAuthor,
+		eventSlug:     eventDraft.Slug,
+		state:         EventStateReview,
+		expectedState: EventStateReview,
+	}, {
+		user:          userAdmin,
+		eventSlug:     eventPublished.Slug,
+		state:         EventStateSynced,
+		expectedState: EventStatePublished,
+		expectedError: errEventTransitionInvalid,
+	}, {
+		user:          userAuthor,
+		eventSlug:     eventSynced.Slug,
+		state:         EventStateRunning,
+		expectedState: EventStateSynced,
+		expectedError: errEventTransitionNotAuthorized,
+	}, {
+		user:          userProctor,
+		eventSlug:     eventSynced.Slug,
+		state:         EventStateRunning,
+		expectedState: EventStateRunning,
+	}, {
+		user:          userProctor,
+		eventSlug:     eventClosed.Slug,
+		state:         EventStateGraded,
+		expectedState: EventStateClosed,
+		expectedError: errEventTransitionNotAuthorized,
+	}, {
+		user:          userGrader,
+		eventSlug:     eventClosed.Slug,
+		state:         EventStateGraded,
+		expectedState: EventStateGraded,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test TransitionEvent testcase: %d", i)
+		event, err := TransitionEvent(testCase.user, testCase.eventSlug, testCase.state)
+		var eventSaved Event
+		helios.DB.Where("slug = ?", testCase.eventSlug).First(&eventSaved)
+		assert.Equal(t, testCase.expectedState, eventSaved.State)
+		if testCase.expectedError == nil {
+			assert.Nil(t, err)
+			assert.Equal(t, testCase.expectedState, event.State)
+			var auditLog auth.AuditLog
+			helios.DB.Last(&auditLog)
+			assert.Equal(t, "event.transition", auditLog.Action)
+			assert.Equal(t, eventSaved.ID, auditLog.TargetID)
+		} else {
+			assert.Equal(t, testCase.expectedError, err)
+		}
+	}
+}
+func TestGetEventIDBySlug(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var event Event = EventFactorySaved(Event{Slug: "event-1"})
+	assert.Equal(t, event.ID, GetEventIDBySlug("event-1"))
+	assert.Equal(t, uint(0), GetEventIDBySlug("event-2"))
+}
+
+func TestGetAllEventRoleOfEvent(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	var userReviewer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	var event1 Event = EventFactorySaved(Event{})
+	var event2 Event = EventFactorySaved(Event{})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	EventRoleFactorySaved(userReviewer, event1, nil, auth.EventRoleReviewer)
+	EventRoleFactorySaved(userReviewer, event2, nil, auth.EventRoleAuthor)
+
+	type getAllEventRoleOfEventTestCase struct {
+		user                  auth.User
+		eventSlug             string
+		expectedLength        int
+		expectedFirstUsername string
+		expectedError         helios.Error
+	}
+	testCases := []getAllEventRoleOfEventTestCase{{
+		user:                  userAuthor,
+		eventSlug:             event1.Slug,
+		expectedLength:        2,
+		expectedFirstUsername: userAuthor.Username,
+	}, {
+		user:                  auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		eventSlug:             event2.Slug,
+		expectedLength:        1,
+		expectedFirstUsername: userReviewer.Username,
+	}, {
+		user:          userReviewer,
+		eventSlug:     event1.Slug,
+		expectedError: errEventRoleManageNotAuthorized,
+	}, {
+		user:          userAuthor,
+		eventSlug:     event2.Slug,
+		expectedError: errEventNotFound,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test GetAllEventRoleOfEvent testcase: %d", i)
+		eventRoles, err := GetAllEventRoleOfEvent(testCase.user, testCase.eventSlug)
+		if testCase.expectedError == nil {
+			assert.Nil(t, err)
+			assert.Equal(t, testCase.expectedLength, len(eventRoles))
+			if len(eventRoles) > 0 {
+				assert.Equal(t, testCase.expectedFirstUsername, eventRoles[0].User.Username)
+			}
+		} else {
+			assert.Equal(t, testCase.expectedError, err)
+		}
+	}
+}
+
+func TestAssignEventRole(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	var userReviewer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	var userGrader auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	var userParticipant auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
+	var event1 Event = EventFactorySaved(Event{})
+	var venue1 Venue = VenueFactorySaved(Venue{})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	EventRoleFactorySaved(userReviewer, event1, nil, auth.EventRoleReviewer)
+	var eventRoleCountBefore int
+	helios.DB.Model(&auth.EventRole{}).Count(&eventRoleCountBefore)
+
+	type assignEventRoleTestCase struct {
+		user                   auth.User
+		eventSlug              string
+		userUsername           string
+		eventRole              auth.EventRole
+		expectedEventRoleCount int
+		expectedError          helios.Error
+	}
+	testCases := []assignEventRoleTestCase{{
+		user:                   userAuthor,
+		eventSlug:              event1.Slug,
+		userUsername:           userGrader.Username,
+		eventRole:              auth.EventRole{Role: auth.EventRoleGrader},
+		expectedEventRoleCount: eventRoleCountBefore + 1,
+	}, {
+		user:                   userAuthor,
+		eventSlug:              event1.Slug,
+		userUsername:           userGrader.Username,
+		eventRole:              auth.EventRole{Role: auth.EventRoleGrader},
+		expectedEventRoleCount: eventRoleCountBefore + 1,
+	}, {
+		user:                   userAuthor,
+		eventSlug:              event1.Slug,
+		userUsername:           userGrader.Username,
+		eventRole:              auth.EventRole{VenueID: venue1.ID, Role: auth.EventRoleProctor},
+		expectedEventRoleCount: eventRoleCountBefore + 2,
+	}, {
+		user:                   userReviewer,
+		eventSlug:              event1.Slug,
+		userUsername:           userGrader.Username,
+		eventRole:              auth.EventRole{Role: auth.EventRoleAuthor},
+		expectedEventRoleCount: eventRoleCountBefore + 2,
+		expectedError:          errEventRoleManageNotAuthorized,
+	}, {
+		user:                   userAuthor,
+		eventSlug:              event1.Slug,
+		userUsername:           userParticipant.Username,
+		eventRole:              auth.EventRole{Role: auth.EventRoleGrader},
+		expectedEventRoleCount: eventRoleCountBefore + 2,
+		expectedError:          errEventRoleNotAssignable,
+	}, {
+		user:                   userAuthor,
+		eventSlug:              event1.Slug,
+		userUsername:           "random_username",
+		eventRole:              auth.EventRole{Role: auth.EventRoleGrader},
+		expectedEventRoleCount: eventRoleCountBefore + 2,
+		expectedError:          errUserNotFound,
+	}, {
+		user:                   userAuthor,
+		eventSlug:              event1.Slug,
+		userUsername:           userGrader.Username,
+		eventRole:              auth.EventRole{VenueID: 99999, Role: auth.EventRoleProctor},
+		expectedEventRoleCount: eventRoleCountBefore + 2,
+		expectedError:          errVenueNotFound,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test AssignEventRole testcase: %d", i)
+		var eventRoleCount int
+		err := AssignEventRole(testCase.user, testCase.eventSlug, testCase.userUsername, &testCase.eventRole)
+		helios.DB.Model(&auth.EventRole{}).Count(&eventRoleCount)
+		assert.Equal(t, testCase.expectedEventRoleCount, eventRoleCount)
+		if testCase.expectedError == nil {
+			assert.Nil(t, err)
+			assert.NotEqual(t, uint(0), testCase.eventRole.ID)
+			assert.Equal(t, testCase.userUsername, testCase.eventRole.User.Username)
+		} else {
+			assert.Equal(t, testCase.expectedError, err)
+		}
+	}
+	assert.True(t, auth.Can(userGrader, auth.ActionParticipationCheckIn, auth.Resource{EventID: event1.ID, VenueID: venue1.ID}))
+}
+
+func TestRevokeEventRole(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	var userReviewer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	var event1 Event = EventFactorySaved(Event{})
+	var event2 Event = EventFactorySaved(Event{})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	var eventRoleReviewer auth.EventRole = EventRoleFactorySaved(userReviewer, event1, nil, auth.EventRoleReviewer)
+	var eventRoleOtherEvent auth.EventRole = EventRoleFactorySaved(userReviewer, event2, nil, auth.EventRoleAuthor)
+
+	type revokeEventRoleTestCase struct {
+		user          auth.User
+		eventSlug     string
+		eventRoleID   uint
+		expectedError helios.Error
+	}
+	testCases := []revokeEventRoleTestCase{{
+		user:          userReviewer,
+		eventSlug:     event1.Slug,
+		eventRoleID:   eventRoleReviewer.ID,
+		expectedError: errEventRoleManageNotAuthorized,
+	}, {
+		user:          userAuthor,
+		eventSlug:     event1.Slug,
+		eventRoleID:   eventRoleOtherEvent.ID,
+		expectedError: errEventRoleNotFound,
+	}, {
+		user:        userAuthor,
+		eventSlug:   event1.Slug,
+		eventRoleID: eventRoleReviewer.ID,
+	}, {
+		user:          userAuthor,
+		eventSlug:     event1.Slug,
+		eventRoleID:   eventRoleReviewer.ID,
+		expectedError: errEventRoleNotFound,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test RevokeEventRole testcase: %d", i)
+		eventRole, err := RevokeEventRole(testCase.user, testCase.eventSlug, testCase.eventRoleID)
+		if testCase.expectedError == nil {
+			assert.Nil(t, err)
+			assert.Equal(t, testCase.eventRoleID, eventRole.ID)
+			assert.Equal(t, userReviewer.Username, eventRole.User.Username)
+		} else {
+			assert.Equal(t, testCase.expectedError, err)
+		}
+	}
+	assert.False(t, auth.Can(userReviewer, auth.ActionEventView, auth.Resource{EventID: event1.ID}))
+}
+
 func TestGetAllParticipationOfUserAndEvent(t *testing.T) {
 	helios.App.BeforeTest()
 
 	var event1 Event = EventFactorySaved(Event{})
 	var event2 Event = EventFactorySaved(Event{})
+	var venue1 Venue = VenueFactorySaved(Venue{})
 	var userParticipant auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
 	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
-	ParticipationFactorySaved(Participation{Event: &event1, User: &userLocal})
-	ParticipationFactorySaved(Participation{Event: &event1, User: &userParticipant})
+	var userOrganizer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &userLocal})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &userParticipant})
+	ParticipationFactorySaved(Participation{Event: &event1, User: &auth.User{Role: auth.UserRoleParticipant}})
 	ParticipationFactorySaved(Participation{Event: &event2})
+	EventRoleFactorySaved(userOrganizer, event2, nil, auth.EventRoleAuthor)
 
 	type getAllParticipationOfUserAndEventTestCase struct {
 		user           auth.User
@@ -290,15 +1100,19 @@ func TestGetAllParticipationOfUserAndEvent(t *testing.T) {
 	testCases := []getAllParticipationOfUserAndEventTestCase{{
 		user:           auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
 		eventSlug:      event1.Slug,
-		expectedLength: 2, // userParticipant + userLocal
+		expectedLength: 3, // userParticipant + userLocal + participant on other venue
 	}, {
-		user:           auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		user:           userOrganizer,
 		eventSlug:      event2.Slug,
-		expectedLength: 1, // userLocal
+		expectedLength: 1,
+	}, {
+		user:          userOrganizer,
+		eventSlug:     event1.Slug,
+		expectedError: errEventNotFound,
 	}, {
 		user:           userLocal,
 		eventSlug:      event1.Slug,
-		expectedLength: 2, // userParticipant + userLocal
+		expectedLength: 2, // userParticipant + userLocal, only on the venue of userLocal
 	}, {
 		user:          userLocal,
 		eventSlug:     event2.Slug,
@@ -332,7 +1146,15 @@ func TestUpsertParticipation(t *testing.T) {
 	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
 	var event1 Event = EventFactorySaved(Event{})
 	var event2 Event = EventFactorySaved(Event{})
-	ParticipationFactorySaved(Participation{Event: &event1, User: &userLocal})
+	var eventSynced Event = EventFactorySaved(Event{State: EventStateSynced})
+	var room1 Room = RoomFactorySaved(Room{Venue: &venue1, Capacity: 2})
+	var room2 Room = RoomFactorySaved(Room{Venue: &venue2})
+	var userOrganizer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	var userLocal2 auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &userLocal})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &userLocal2})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, RoomID: &room1.ID, SeatNumber: 1})
+	EventRoleFactorySaved(userOrganizer, event1, nil, auth.EventRoleAuthor)
 	var key = "secret_key_per_user_per_event"
 	var keyHashedOnce = "e33a9931ec1ba26e9acd8957b597595ce7e336e4df534ac83bc4102e963c4814"
 	var keyHashedTwice = "cfa42ce14740fb597b001bdc9c6a2569c027f53358f7fd2ebdc80d0888737530"
@@ -345,6 +1167,7 @@ func TestUpsertParticipation(t *testing.T) {
 		userUsername               string
 		participation              Participation
 		expectedParticipationCount int
+		expectedSeatNumber         uint
 		expectedError              helios.Error
 	}
 	testCases := []participationUpsertTestCase{{
@@ -355,12 +1178,19 @@ func TestUpsertParticipation(t *testing.T) {
 		expectedParticipationCount: participationCountBefore,
 		expectedError:              errEventNotFound,
 	}, {
-		user:                       auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		user:                       userOrganizer,
 		eventSlug:                  event1.Slug,
 		userUsername:               "random_username",
 		participation:              Participation{VenueID: venue1.ID, KeyPlain: key},
 		expectedParticipationCount: participationCountBefore,
 		expectedError:              errUserNotFound,
+	}, {
+		user:                       auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		eventSlug:                  eventSynced.Slug,
+		userUsername:               userParticipant.Username,
+		participation:              Participation{VenueID: venue1.ID, KeyPlain: key},
+		expectedParticipationCount: participationCountBefore,
+		expectedError:              errEventStateInvalid,
 	}, {
 		user:                       userLocal,
 		eventSlug:                  event1.Slug,
@@ -382,37 +1212,556 @@ func TestUpsertParticipation(t *testing.T) {
 		participation:              Participation{EventID: event2.ID, UserID: userLocal.ID, VenueID: venue1.ID, KeyPlain: key},
 		expectedParticipationCount: participationCountBefore + 1,
 	}, {
-		user:                       auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
-		eventSlug:                  event1.Slug,
-		userUsername:               userLocal.Username,
-		participation:              Participation{EventID: event2.ID, UserID: userLocal.ID, VenueID: venue2.ID, KeyPlain: key},
-		expectedParticipationCount: participationCountBefore + 1,
+		user:                       userOrganizer,
+		eventSlug:                  event1.Slug,
+		userUsername:               userLocal2.Username,
+		participation:              Participation{EventID: event2.ID, UserID: userLocal2.ID, VenueID: venue2.ID, KeyPlain: key},
+		expectedParticipationCount: participationCountBefore + 1,
+	}, {
+		user:                       userLocal,
+		eventSlug:                  event1.Slug,
+		userUsername:               userParticipant.Username,
+		participation:              Participation{VenueID: venue1.ID, RoomID: &room2.ID, KeyPlain: key},
+		expectedParticipationCount: participationCountBefore + 1,
+		expectedError:              errRoomNotFound,
+	}, {
+		user:                       userLocal,
+		eventSlug:                  event1.Slug,
+		userUsername:               userParticipant.Username,
+		participation:              Participation{VenueID: venue1.ID, RoomID: &room1.ID, SeatNumber: 1, KeyPlain: key},
+		expectedParticipationCount: participationCountBefore + 1,
+		expectedError:              errSeatNotAvailable,
+	}, {
+		user:                       userLocal,
+		eventSlug:                  event1.Slug,
+		userUsername:               userParticipant.Username,
+		participation:              Participation{VenueID: venue1.ID, RoomID: &room1.ID, SeatNumber: 3, KeyPlain: key},
+		expectedParticipationCount: participationCountBefore + 1,
+		expectedError:              errSeatNotAvailable,
+	}, {
+		user:                       userLocal,
+		eventSlug:                  event1.Slug,
+		userUsername:               userParticipant.Username,
+		participation:              Participation{VenueID: venue1.ID, RoomID: &room1.ID, KeyPlain: key},
+		expectedParticipationCount: participationCountBefore + 1,
+		expectedSeatNumber:         2,
+	}, {
+		user:                       userLocal,
+		eventSlug:                  event1.Slug,
+		userUsername:               userParticipant.Username,
+		participation:              Participation{VenueID: venue1.ID, RoomID: &room1.ID, SeatNumber: 2, KeyPlain: key},
+		expectedParticipationCount: participationCountBefore + 1,
+		expectedSeatNumber:         2,
+	}, {
+		user:                       userLocal,
+		eventSlug:                  event1.Slug,
+		userUsername:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant}).Username,
+		participation:              Participation{VenueID: venue1.ID, RoomID: &room1.ID, KeyPlain: key},
+		expectedParticipationCount: participationCountBefore + 1,
+		expectedError:              errRoomFull,
+	}}
+
+	for i, testCase := range testCases {
+		var participationCount int
+		var participationSaved Participation
+		var tempVenueID uint = testCase.participation.VenueID
+		var err helios.Error
+		t.Logf("Test UpsertParticipation testcase: %d", i)
+		err = UpsertParticipation(testCase.user, testCase.eventSlug, testCase.userUsername, &testCase.participation)
+		helios.DB.Model(&Participation{}).Count(&participationCount)
+		helios.DB.Preload("User").Preload("Event").Preload("Venue").Where("id = ?", testCase.participation.ID).First(&participationSaved)
+		assert.Equal(t, testCase.expectedParticipationCount, participationCount)
+		if testCase.expectedError == nil {
+			assert.Nil(t, err)
+			assert.Equal(t, testCase.eventSlug, participationSaved.Event.Slug)
+			assert.Equal(t, testCase.eventSlug, testCase.participation.Event.Slug)
+			assert.Equal(t, testCase.userUsername, participationSaved.User.Username)
+			assert.Equal(t, testCase.userUsername, testCase.participation.User.Username)
+			assert.Equal(t, keyHashedOnce, participationSaved.KeyHashedOnce)
+			assert.Equal(t, keyHashedTwice, participationSaved.KeyHashedTwice)
+			assert.Equal(t, tempVenueID, participationSaved.Venue.ID)
+			assert.Equal(t, tempVenueID, testCase.participation.Venue.ID)
+			assert.Equal(t, testCase.expectedSeatNumber, participationSaved.SeatNumber)
+		} else {
+			assert.Equal(t, testCase.expectedError, err)
+		}
+	}
+	var resourceVenue1 auth.Resource = auth.Resource{EventID: event1.ID, VenueID: venue1.ID}
+	var resourceVenue2 auth.Resource = auth.Resource{EventID: event1.ID, VenueID: venue2.ID}
+	assert.False(t, auth.Can(userLocal2, auth.ActionParticipationCheckIn, resourceVenue1), "Local user should not be proctor of the previous venue")
+	assert.True(t, auth.Can(userLocal2, auth.ActionParticipationCheckIn, resourceVenue2), "Local user should be proctor of the new venue")
+}
+
+func TestAssignSeats(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var venue1 Venue = VenueFactorySaved(Venue{})
+	var venue2 Venue = VenueFactorySaved(Venue{})
+	var event1 Event = EventFactorySaved(Event{})
+	var userLocal1 auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	var userLocal2 auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	var room1 Room = RoomFactorySaved(Room{Venue: &venue1, Capacity: 2})
+	var room2 Room = RoomFactorySaved(Room{Venue: &venue1, Capacity: 2})
+	RoomFactorySaved(Room{Venue: &venue2, Capacity: 1})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &userLocal1})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue2, User: &userLocal2})
+	var userOrganizer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userOrganizer, event1, nil, auth.EventRoleAuthor)
+	var participations []Participation = []Participation{
+		ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &auth.User{Username: "user_a", Role: auth.UserRoleParticipant}}),
+		ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &auth.User{Username: "user_b", Role: auth.UserRoleParticipant}, RoomID: &room1.ID, SeatNumber: 1}),
+		ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &auth.User{Username: "user_c", Role: auth.UserRoleParticipant}}),
+		ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue2, User: &auth.User{Username: "user_d", Role: auth.UserRoleParticipant}}),
+		ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue2, User: &auth.User{Username: "user_e", Role: auth.UserRoleParticipant}}),
+	}
+
+	type assignSeatsTestCase struct {
+		user          auth.User
+		eventSlug     string
+		venueID       uint
+		expectedRooms []uint
+		expectedSeats []uint
+		expectedError helios.Error
+	}
+	testCases := []assignSeatsTestCase{{
+		user:          *participations[0].User,
+		eventSlug:     event1.Slug,
+		venueID:       venue1.ID,
+		expectedRooms: []uint{0, room1.ID, 0, 0, 0},
+		expectedSeats: []uint{0, 1, 0, 0, 0},
+		expectedError: errSeatAssignmentNotAuthorized,
+	}, {
+		user:          userLocal2,
+		eventSlug:     event1.Slug,
+		venueID:       venue1.ID,
+		expectedRooms: []uint{0, room1.ID, 0, 0, 0},
+		expectedSeats: []uint{0, 1, 0, 0, 0},
+		expectedError: errSeatAssignmentNotAuthorized,
+	}, {
+		user:          userLocal2,
+		eventSlug:     event1.Slug,
+		venueID:       venue2.ID,
+		expectedRooms: []uint{0, room1.ID, 0, 0, 0},
+		expectedSeats: []uint{0, 1, 0, 0, 0},
+		expectedError: errVenueCapacityExceeded,
+	}, {
+		user:          userOrganizer,
+		eventSlug:     event1.Slug,
+		venueID:       999999,
+		expectedRooms: []uint{0, room1.ID, 0, 0, 0},
+		expectedSeats: []uint{0, 1, 0, 0, 0},
+		expectedError: errVenueNotFound,
+	}, {
+		user:          userLocal1,
+		eventSlug:     event1.Slug,
+		venueID:       venue1.ID,
+		expectedRooms: []uint{room1.ID, room1.ID, room2.ID, 0, 0},
+		expectedSeats: []uint{2, 1, 1, 0, 0},
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test AssignSeats testcase: %d", i)
+		var err helios.Error
+		err = AssignSeats(testCase.user, testCase.eventSlug, testCase.venueID)
+		if testCase.expectedError == nil {
+			assert.Nil(t, err)
+		} else {
+			assert.Equal(t, testCase.expectedError, err)
+		}
+		for j, participation := range participations {
+			var participationSaved Participation
+			helios.DB.Where("id = ?", participation.ID).First(&participationSaved)
+			assert.Equal(t, testCase.expectedRooms[j], participationSaved.GetRoomID())
+			assert.Equal(t, testCase.expectedSeats[j], participationSaved.SeatNumber)
+		}
+	}
+}
+
+func TestImportParticipants(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var venue1 Venue = VenueFactorySaved(Venue{Name: "Venue A"})
+	var event1 Event = EventFactorySaved(Event{})
+	var userOrganizer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	var userExisting auth.User = auth.UserFactorySaved(auth.User{Username: "existing", Password: "existing-password", Role: auth.UserRoleParticipant})
+	var userLocal auth.User = auth.UserFactorySaved(auth.User{Username: "local", Role: auth.UserRoleLocal})
+	var userVenueManager auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	var participationRegistered Participation = ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &auth.User{Username: "registered", Role: auth.UserRoleParticipant}})
+	ParticipationFactorySaved(Participation{Event: &event1, User: &userVenueManager})
+	var userParticipantAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
+	EventRoleFactorySaved(userOrganizer, event1, nil, auth.EventRoleAuthor)
+	EventRoleFactorySaved(userParticipantAuthor, event1, nil, auth.EventRoleAuthor)
+
+	type importParticipantsTestCase struct {
+		user                        auth.User
+		participants                []ParticipantImportData
+		expectedError               string
+		expectedParticipationsCount int
+	}
+	testCases := []importParticipantsTestCase{{
+		user:                        *participationRegistered.User,
+		participants:                []ParticipantImportData{{Name: "User 1", Username: "user1", VenueName: venue1.Name}},
+		expectedError:               `{"code":"not_authorized_edit_participation","message":"User is not authorized to make changes on participation"}`,
+		expectedParticipationsCount: 2,
+	}, {
+		// editing participations doesn't grant creating the participant users
+		user:                        userParticipantAuthor,
+		participants:                []ParticipantImportData{{Name: "User 1", Username: "user1", VenueName: venue1.Name}},
+		expectedError:               `{"code":"not_authorized_edit_participation","message":"User is not authorized to make changes on participation"}`,
+		expectedParticipationsCount: 2,
+	}, {
+		user:                        userVenueManager,
+		participants:                []ParticipantImportData{{Name: "User 1", Username: "user1", VenueName: venue1.Name}},
+		expectedError:               `{"code":"form_error","message":{"_error":[],"participants":[{"venueName":["You are not allowed to add participant on the venue"]}]}}`,
+		expectedParticipationsCount: 2,
+	}, {
+		user: userOrganizer,
+		participants: []ParticipantImportData{
+			{Name: "User 1", Username: "user1", VenueName: venue1.Name},
+			{Name: "User 1", Username: "user1", VenueName: venue1.Name},
+			{Name: "Local", Username: userLocal.Username, VenueName: venue1.Name},
+			{Name: "Registered", Username: "registered", VenueName: "Venue X"},
+			{Name: "", Username: "user3", VenueName: venue1.Name},
+			{Name: "User 4", Username: "user4", VenueName: venue1.Name, Password: "short"},
+		},
+		expectedError: `{"code":"form_error","message":{"_error":[],"participants":[` +
+			`{},` +
+			`{"username":["Username is imported more than once"]},` +
+			`{"username":["Username is used by non-participant user"]},` +
+			`{"username":["Participant has already been registered on the event"],"venueName":["Venue doesn't exist"]},` +
+			`{"name":["Name can't be empty"]},` +
+			`{"password":["Password should be at least 8 characters","Password should contain a digit"]}` +
+			`]}}`,
+		expectedParticipationsCount: 2,
+	}, {
+		user: userOrganizer,
+		participants: []ParticipantImportData{
+			{Name: "User 1", Username: "user1", VenueName: venue1.Name},
+			{Name: "User 2", Username: "user2", VenueName: venue1.Name, Password: "given-passw0rd"},
+			{Name: "Existing", Username: userExisting.Username, VenueName: venue1.Name, Password: "new-password"},
+		},
+		expectedParticipationsCount: 5,
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test ImportParticipants testcase: %d", i)
+		var participationsCount int
+		var err helios.Error
+		err = ImportParticipants(testCase.user, event1.Slug, testCase.participants)
+		helios.DB.Model(Participation{}).Where("event_id = ?", event1.ID).Count(&participationsCount)
+		assert.Equal(t, testCase.expectedParticipationsCount, participationsCount)
+		if testCase.expectedError == "" {
+			assert.Nil(t, err)
+			for _, participant := range testCase.participants {
+				var participantUser auth.User
+				var participation Participation
+				helios.DB.Where("username = ?", participant.Username).First(&participantUser)
+				helios.DB.Where("user_id = ?", participantUser.ID).Where("event_id = ?", event1.ID).First(&participation)
+				assert.Equal(t, participationKeyLength, len(participant.Key))
+				assert.Equal(t, fmt.Sprintf("%x", sha256.Sum256([]byte(participant.Key))), participation.KeyHashedOnce)
+				if participant.Username == userExisting.Username {
+					assert.Empty(t, participant.Password, "Password of existing user should not be changed")
+					assert.Equal(t, userExisting.Password, participantUser.Password)
+				} else {
+					assert.NotEmpty(t, participant.Password)
+					assert.True(t, participantUser.MustChangePassword, "Imported user should change the given password")
+					_, errLogin := auth.Login(participant.Username, participant.Password, "1.2.3.4")
+					assert.Nil(t, errLogin, "Participant should be able to log in with reported password")
+				}
+			}
+			assert.Equal(t, "given-passw0rd", testCase.participants[1].Password)
+			assert.Equal(t, generatedPasswordLength, len(testCase.participants[0].Password))
+		} else {
+			var errJSON []byte
+			errJSON, _ = json.Marshal(err.GetMessage())
+			assert.Equal(t, testCase.expectedError, string(errJSON))
+		}
+	}
+}
+
+func TestImportSeatAssignment(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var venue1 Venue = VenueFactorySaved(Venue{})
+	var event1 Event = EventFactorySaved(Event{})
+	var room1 Room = RoomFactorySaved(Room{Venue: &venue1, Name: "Room A", Capacity: 2})
+	var room2 Room = RoomFactorySaved(Room{Venue: &venue1, Name: "Room B", Capacity: 2})
+	RoomFactorySaved(Room{Name: "Room C"})
+	var participations []Participation = []Participation{
+		ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &auth.User{Username: "user_a", Role: auth.UserRoleParticipant}}),
+		ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &auth.User{Username: "user_b", Role: auth.UserRoleParticipant}, RoomID: &room1.ID, SeatNumber: 1}),
+		ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &auth.User{Username: "user_c", Role: auth.UserRoleParticipant}}),
+	}
+	ParticipationFactorySaved(Participation{Event: &event1, User: &auth.User{Username: "user_d", Role: auth.UserRoleParticipant}})
+	var userOrganizer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userOrganizer, event1, nil, auth.EventRoleAuthor)
+
+	type importSeatAssignmentTestCase struct {
+		user          auth.User
+		venueID       uint
+		seats         []SeatAssignmentData
+		expectedRooms []uint
+		expectedSeats []uint
+		expectedError string
+	}
+	testCases := []importSeatAssignmentTestCase{{
+		user:          *participations[0].User,
+		venueID:       venue1.ID,
+		seats:         []SeatAssignmentData{{UserUsername: "user_a", RoomName: "Room A", SeatNumber: 2}},
+		expectedRooms: []uint{0, room1.ID, 0},
+		expectedSeats: []uint{0, 1, 0},
+		expectedError: `{"code":"not_authorized_assign_seat","message":"User is not authorized to assign seats on the venue"}`,
+	}, {
+		user:    userOrganizer,
+		venueID: venue1.ID,
+		seats: []SeatAssignmentData{
+			{UserUsername: "user_a", RoomName: "Room A", SeatNumber: 1},
+			{UserUsername: "user_d", RoomName: "Room C", SeatNumber: 1},
+			{UserUsername: "user_c", RoomName: "Room B", SeatNumber: 3},
+			{UserUsername: "user_a", RoomName: "Room B", SeatNumber: 1},
+		},
+		expectedRooms: []uint{0, room1.ID, 0},
+		expectedSeats: []uint{0, 1, 0},
+		expectedError: `{"code":"form_error","message":{"_error":[],"seats":[` +
+			`{"seatNumber":["Seat is assigned more than once"]},` +
+			`{"roomName":["Room doesn't exist on the venue"],"userUsername":["Participant is not registered on the venue"]},` +
+			`{"seatNumber":["Seat number should be between 1 and 2"]},` +
+			`{"userUsername":["Participant is assigned more than once"]}` +
+			`]}}`,
+	}, {
+		user:    userOrganizer,
+		venueID: venue1.ID,
+		seats: []SeatAssignmentData{
+			{UserUsername: "user_a", RoomName: "Room A", SeatNumber: 2},
+			{UserUsername: "user_c", RoomName: "Room B", SeatNumber: 2},
+		},
+		expectedRooms: []uint{room1.ID, room1.ID, room2.ID},
+		expectedSeats: []uint{2, 1, 2},
+	}, {
+		user:    auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		venueID: venue1.ID,
+		seats: []SeatAssignmentData{
+			{UserUsername: "user_a", RoomName: "Room B", SeatNumber: 2},
+			{UserUsername: "user_c", RoomName: "Room A", SeatNumber: 2},
+		},
+		expectedRooms: []uint{room2.ID, room1.ID, room1.ID},
+		expectedSeats: []uint{2, 1, 2},
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test ImportSeatAssignment testcase: %d", i)
+		var err helios.Error
+		err = ImportSeatAssignment(testCase.user, event1.Slug, testCase.venueID, testCase.seats)
+		if testCase.expectedError == "" {
+			assert.Nil(t, err)
+		} else {
+			var errJSON []byte
+			var errMarshalling error
+			assert.NotNil(t, err)
+			errJSON, errMarshalling = json.Marshal(err.GetMessage())
+			assert.Nil(t, errMarshalling)
+			assert.Equal(t, testCase.expectedError, string(errJSON))
+		}
+		for j, participation := range participations {
+			var participationSaved Participation
+			helios.DB.Where("id = ?", participation.ID).First(&participationSaved)
+			assert.Equal(t, testCase.expectedRooms[j], participationSaved.GetRoomID())
+			assert.Equal(t, testCase.expectedSeats[j], participationSaved.SeatNumber)
+		}
+	}
+}
+
+func TestGetSeatingChart(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var venue1 Venue = VenueFactorySaved(Venue{})
+	var event1 Event = EventFactorySaved(Event{})
+	var event2 Event = EventFactorySaved(Event{})
+	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	var room1 Room = RoomFactorySaved(Room{Venue: &venue1})
+	RoomFactorySaved(Room{Venue: &venue1})
+	RoomFactorySaved(Room{})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &userLocal})
+	var participationB Participation = ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &auth.User{Username: "user_b", Role: auth.UserRoleParticipant}})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &auth.User{Username: "user_a", Role: auth.UserRoleParticipant}, RoomID: &room1.ID, SeatNumber: 3})
+	ParticipationFactorySaved(Participation{Event: &event1, User: &auth.User{Role: auth.UserRoleParticipant}})
+	ParticipationFactorySaved(Participation{Event: &event2, Venue: &venue1, User: &auth.User{Role: auth.UserRoleParticipant}})
+
+	type getSeatingChartTestCase struct {
+		user                          auth.User
+		eventSlug                     string
+		expectedRoomLength            int
+		expectedParticipantsUsernames []string
+		expectedError                 helios.Error
+	}
+	testCases := []getSeatingChartTestCase{{
+		user:          *participationB.User,
+		eventSlug:     event1.Slug,
+		expectedError: errSeatAssignmentNotAuthorized,
+	}, {
+		user:          userLocal,
+		eventSlug:     event2.Slug,
+		expectedError: errEventNotFound,
+	}, {
+		user:                          userLocal,
+		eventSlug:                     event1.Slug,
+		expectedRoomLength:            2,
+		expectedParticipantsUsernames: []string{"user_a", "user_b"},
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test GetSeatingChart testcase: %d", i)
+		var venue *Venue
+		var rooms []Room
+		var participations []Participation
+		var err helios.Error
+		venue, rooms, participations, err = GetSeatingChart(testCase.user, testCase.eventSlug, venue1.ID)
+		if testCase.expectedError == nil {
+			assert.Nil(t, err)
+			assert.Equal(t, venue1.ID, venue.ID)
+			assert.Equal(t, testCase.expectedRoomLength, len(rooms))
+			var usernames []string
+			for _, participation := range participations {
+				usernames = append(usernames, participation.User.Username)
+			}
+			assert.Equal(t, testCase.expectedParticipantsUsernames, usernames)
+		} else {
+			assert.Equal(t, testCase.expectedError, err)
+		}
+	}
+}
+
+func TestPrintSeatingChart(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var venue1 Venue = VenueFactorySaved(Venue{})
+	var event1 Event = EventFactorySaved(Event{})
+	var event2 Event = EventFactorySaved(Event{})
+	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	var room1 Room = RoomFactorySaved(Room{Venue: &venue1})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &userLocal})
+	var participationA Participation = ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &auth.User{Role: auth.UserRoleParticipant}, RoomID: &room1.ID, SeatNumber: 1})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &auth.User{Role: auth.UserRoleParticipant}})
+
+	type printSeatingChartTestCase struct {
+		user          auth.User
+		eventSlug     string
+		expectedError helios.Error
+	}
+	testCases := []printSeatingChartTestCase{{
+		user:          *participationA.User,
+		eventSlug:     event1.Slug,
+		expectedError: errSeatAssignmentNotAuthorized,
+	}, {
+		user:          userLocal,
+		eventSlug:     event2.Slug,
+		expectedError: errEventNotFound,
+	}, {
+		user:      userLocal,
+		eventSlug: event1.Slug,
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test PrintSeatingChart testcase: %d", i)
+		pdf, err := PrintSeatingChart(testCase.user, testCase.eventSlug, venue1.ID)
+		if testCase.expectedError == nil {
+			assert.Nil(t, err)
+			assert.Equal(t, "%PDF", string(pdf[:4]))
+		} else {
+			assert.Equal(t, testCase.expectedError, err)
+		}
+	}
+}
+
+func TestGenerateCredentialCards(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var venue1 Venue = VenueFactorySaved(Venue{})
+	var venue2 Venue = VenueFactorySaved(Venue{})
+	var event1 Event = EventFactorySaved(Event{})
+	var userOrganizer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue2, User: &userLocal})
+	var participationA Participation = ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &auth.User{Username: "user_a", Role: auth.UserRoleParticipant}})
+	var participationB Participation = ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &auth.User{Username: "user_b", Role: auth.UserRoleParticipant}})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue2, User: &auth.User{Username: "user_c", Role: auth.UserRoleParticipant}})
+	helios.DB.Model(&participationB).Update("key_plain", "")
+	EventRoleFactorySaved(userOrganizer, event1, nil, auth.EventRoleAuthor)
+
+	type generateCredentialCardsTestCase struct {
+		user                    auth.User
+		venueID                 uint
+		resetPassword           bool
+		reissueKey              bool
+		expectedSkipped         []string
+		expectedPasswordChanged bool
+		expectedError           helios.Error
+	}
+	testCases := []generateCredentialCardsTestCase{{
+		user:          *participationA.User,
+		venueID:       venue1.ID,
+		expectedError: errCredentialCardNotAuthorized,
+	}, {
+		user:          userLocal,
+		venueID:       venue1.ID,
+		expectedError: errCredentialCardNotAuthorized,
+	}, {
+		user:            userOrganizer,
+		venueID:         venue1.ID,
+		expectedSkipped: []string{"user_b"},
+	}, {
+		user:            userOrganizer,
+		venueID:         venue1.ID,
+		expectedSkipped: []string{"user_a", "user_b"},
+	}, {
+		user:                    userOrganizer,
+		venueID:                 venue1.ID,
+		resetPassword:           true,
+		reissueKey:              true,
+		expectedPasswordChanged: true,
 	}}
 
 	for i, testCase := range testCases {
-		var participationCount int
-		var participationSaved Participation
-		var tempVenueID uint = testCase.participation.VenueID
+		t.Logf("Test GenerateCredentialCards testcase: %d", i)
+		var userBefore auth.User
+		var pdf []byte
+		var skipped []string
 		var err helios.Error
-		t.Logf("Test UpsertParticipation testcase: %d", i)
-		err = UpsertParticipation(testCase.user, testCase.eventSlug, testCase.userUsername, &testCase.participation)
-		helios.DB.Model(&Participation{}).Count(&participationCount)
-		helios.DB.Preload("User").Preload("Event").Preload("Venue").Where("id = ?", testCase.participation.ID).First(&participationSaved)
-		assert.Equal(t, testCase.expectedParticipationCount, participationCount)
+		helios.DB.Where("id = ?", participationA.UserID).First(&userBefore)
+		pdf, skipped, err = GenerateCredentialCards(testCase.user, event1.Slug, testCase.venueID, CredentialCardLayoutCard, testCase.resetPassword, testCase.reissueKey)
 		if testCase.expectedError == nil {
 			assert.Nil(t, err)
-			assert.Equal(t, testCase.eventSlug, participationSaved.Event.Slug)
-			assert.Equal(t, testCase.eventSlug, testCase.participation.Event.Slug)
-			assert.Equal(t, testCase.userUsername, participationSaved.User.Username)
-			assert.Equal(t, testCase.userUsername, testCase.participation.User.Username)
-			assert.Equal(t, keyHashedOnce, participationSaved.KeyHashedOnce)
-			assert.Equal(t, keyHashedTwice, participationSaved.KeyHashedTwice)
-			assert.Equal(t, tempVenueID, participationSaved.Venue.ID)
-			assert.Equal(t, tempVenueID, testCase.participation.Venue.ID)
+			assert.Equal(t, "%PDF", string(pdf[:4]))
+			assert.Equal(t, testCase.expectedSkipped, skipped)
+
+			var participations []Participation
+			helios.DB.Where("event_id = ?", event1.ID).Where("venue_id = ?", venue1.ID).Find(&participations)
+			for _, participation := range participations {
+				assert.Equal(t, "", participation.KeyPlain)
+				assert.NotEqual(t, "", participation.KeyHashedTwice)
+			}
+			var userAfter auth.User
+			helios.DB.Where("id = ?", participationA.UserID).First(&userAfter)
+			assert.Equal(t, testCase.expectedPasswordChanged, userBefore.Password != userAfter.Password)
+			assert.Equal(t, testCase.expectedPasswordChanged, userAfter.MustChangePassword)
 		} else {
 			assert.Equal(t, testCase.expectedError, err)
 		}
 	}
+	var participationC Participation
+	helios.DB.Where("venue_id = ?", venue2.ID).Where("user_id <> ?", userLocal.ID).First(&participationC)
+	assert.NotEqual(t, "", participationC.KeyPlain, "Key of other venue should not be wiped")
+
+	// the keys and passwords can't be changed after the event is synchronized
+	for _, state := range []string{EventStateSynced, EventStateRunning} {
+		helios.DB.Model(&event1).Update("state", state)
+		_, _, err := GenerateCredentialCards(userOrganizer, event1.Slug, venue1.ID, CredentialCardLayoutCard, true, false)
+		assert.Equal(t, errEventStateInvalid, err, "Password should not be reset on %s event", state)
+		_, _, err = GenerateCredentialCards(userOrganizer, event1.Slug, venue1.ID, CredentialCardLayoutCard, false, true)
+		assert.Equal(t, errEventStateInvalid, err, "Key should not be reissued on %s event", state)
+		_, _, err = GenerateCredentialCards(userOrganizer, event1.Slug, venue1.ID, CredentialCardLayoutCard, false, false)
+		assert.Nil(t, err, "Cards should be printed on %s event", state)
+	}
 }
 
 func TestVerifyParticipation(t *testing.T) {
@@ -428,8 +1777,10 @@ func TestVerifyParticipation(t *testing.T) {
 	var event1User2KeyHashedOnce = fmt.Sprintf("%x", sha256.Sum256([]byte(event1User2Key)))
 	var event1User1KeyHashedTwice = fmt.Sprintf("%x", sha256.Sum256([]byte(event1User1KeyHashedOnce)))
 	var event1User2KeyHashedTwice = fmt.Sprintf("%x", sha256.Sum256([]byte(event1User2KeyHashedOnce)))
-	var participation1 Participation = ParticipationFactorySaved(Participation{Event: &event1, User: &userParticipant1, KeyPlain: "to_disable_factory", KeyHashedTwice: event1User1KeyHashedTwice})
-	var participation2 Participation = ParticipationFactorySaved(Participation{Event: &event1, User: &userParticipant2, KeyPlain: "to_disable_factory", KeyHashedTwice: event1User2KeyHashedTwice})
+	var userParticipant3 auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
+	var participation1 Participation = ParticipationFactorySaved(Participation{Event: &event1, User: &userParticipant1, CheckedInAt: time.Now(), KeyPlain: "to_disable_factory", KeyHashedTwice: event1User1KeyHashedTwice})
+	var participation2 Participation = ParticipationFactorySaved(Participation{Event: &event1, User: &userParticipant2, CheckedInAt: time.Now(), KeyPlain: "to_disable_factory", KeyHashedTwice: event1User2KeyHashedTwice})
+	var participation3 Participation = ParticipationFactorySaved(Participation{Event: &event1, User: &userParticipant3, KeyPlain: "event_1_user3", KeyHashedTwice: event1User1KeyHashedTwice})
 	type verifyParticipationTestCase struct {
 		user           auth.User
 		eventSlug      string
@@ -466,6 +1817,12 @@ func TestVerifyParticipation(t *testing.T) {
 		keyHashedOnce: "wrong_key",
 		participation: participation2,
 		expectedError: errEventNotFound,
+	}, {
+		user:          userParticipant3,
+		eventSlug:     event1.Slug,
+		keyHashedOnce: event1User1KeyHashedOnce,
+		participation: participation3,
+		expectedError: errParticipationNotCheckedIn,
 	}}
 	for i, testCase := range testCases {
 		t.Logf("Test VerifyParticipation testcase: %d", i)
@@ -482,15 +1839,284 @@ func TestVerifyParticipation(t *testing.T) {
 	}
 }
 
+func TestCheckInParticipation(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var venue1 Venue = VenueFactorySaved(Venue{})
+	var venue2 Venue = VenueFactorySaved(Venue{})
+	var event1 Event = EventFactorySaved(Event{})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	var event2 Event = EventFactorySaved(Event{StartsAt: time.Now().Add(2 * time.Hour)})
+	var userLocal1 auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	var userLocal2 auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &userLocal1})
+	ParticipationFactorySaved(Participation{Event: &event2, Venue: &venue1, User: &userLocal1})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &userLocal2})
+	var participationA Participation = ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &auth.User{Role: auth.UserRoleParticipant}})
+	var participationB Participation = ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue2, User: &auth.User{Role: auth.UserRoleParticipant}})
+	var participationC Participation = ParticipationFactorySaved(Participation{Event: &event2, Venue: &venue1, User: &auth.User{Role: auth.UserRoleParticipant}})
+
+	type checkInParticipationTestCase struct {
+		user               auth.User
+		eventSlug          string
+		userUsername       string
+		idVerified         bool
+		expectedAttendance string
+		expectedError      helios.Error
+	}
+	testCases := []checkInParticipationTestCase{{
+		user:          userAuthor,
+		eventSlug:     event1.Slug,
+		userUsername:  participationA.User.Username,
+		expectedError: errCheckInNotAuthorized,
+	}, {
+		user:          *participationA.User,
+		eventSlug:     event1.Slug,
+		userUsername:  participationA.User.Username,
+		expectedError: errCheckInNotAuthorized,
+	}, {
+		user:          userLocal1,
+		eventSlug:     event1.Slug,
+		userUsername:  participationB.User.Username,
+		expectedError: errUserNotFound,
+	}, {
+		user:          userLocal1,
+		eventSlug:     event1.Slug,
+		userUsername:  userLocal2.Username,
+		expectedError: errUserNotFound,
+	}, {
+		user:          userLocal2,
+		eventSlug:     event2.Slug,
+		userUsername:  participationC.User.Username,
+		expectedError: errEventNotFound,
+	}, {
+		user:               userLocal1,
+		eventSlug:          event1.Slug,
+		userUsername:       participationA.User.Username,
+		idVerified:         true,
+		expectedAttendance: AttendanceLate,
+	}, {
+		user:          userLocal2,
+		eventSlug:     event1.Slug,
+		userUsername:  participationA.User.Username,
+		expectedError: errParticipationAlreadyCheckedIn,
+	}, {
+		user:               userLocal1,
+		eventSlug:          event2.Slug,
+		userUsername:       participationC.User.Username,
+		expectedAttendance: AttendancePresent,
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test CheckInParticipation testcase: %d", i)
+		var participation *Participation
+		var participationSaved Participation
+		var err helios.Error
+		participation, err = CheckInParticipation(testCase.user, testCase.eventSlug, testCase.userUsername, testCase.idVerified)
+		if testCase.expectedError == nil {
+			assert.Nil(t, err)
+			helios.DB.Where("id = ?", participation.ID).First(&participationSaved)
+			assert.Equal(t, testCase.userUsername, participation.User.Username)
+			assert.Equal(t, testCase.expectedAttendance, participationSaved.Attendance)
+			assert.Equal(t, testCase.idVerified, participationSaved.IDVerified)
+			assert.False(t, participationSaved.CheckedInAt.IsZero())
+		} else {
+			assert.Equal(t, testCase.expectedError, err)
+		}
+	}
+}
+
+func TestRecordNoShow(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var venue1 Venue = VenueFactorySaved(Venue{})
+	var venue2 Venue = VenueFactorySaved(Venue{})
+	var event1 Event = EventFactorySaved(Event{})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	var event2 Event = EventFactorySaved(Event{StartsAt: time.Now().Add(2 * time.Hour)})
+	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	var participationLocal Participation = ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &userLocal})
+	ParticipationFactorySaved(Participation{Event: &event2, Venue: &venue1, User: &userLocal})
+	var participations []Participation = []Participation{
+		ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &auth.User{Role: auth.UserRoleParticipant}}),
+		ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &auth.User{Role: auth.UserRoleParticipant}, Attendance: AttendanceLate, CheckedInAt: time.Now()}),
+		ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue2, User: &auth.User{Role: auth.UserRoleParticipant}}),
+		ParticipationFactorySaved(Participation{Event: &event2, Venue: &venue1, User: &auth.User{Role: auth.UserRoleParticipant}}),
+		participationLocal,
+	}
+
+	type recordNoShowTestCase struct {
+		user                auth.User
+		eventSlug           string
+		expectedAttendances []string
+		expectedError       helios.Error
+	}
+	testCases := []recordNoShowTestCase{{
+		user:                userAuthor,
+		eventSlug:           event1.Slug,
+		expectedAttendances: []string{"", AttendanceLate, "", "", ""},
+		expectedError:       errCheckInNotAuthorized,
+	}, {
+		user:                userLocal,
+		eventSlug:           event2.Slug,
+		expectedAttendances: []string{"", AttendanceLate, "", "", ""},
+		expectedError:       errEventIsNotYetStarted,
+	}, {
+		user:                userLocal,
+		eventSlug:           event1.Slug,
+		expectedAttendances: []string{AttendanceNoShow, AttendanceLate, "", "", ""},
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test RecordNoShow testcase: %d", i)
+		var err helios.Error = RecordNoShow(testCase.user, testCase.eventSlug)
+		if testCase.expectedError == nil {
+			assert.Nil(t, err)
+		} else {
+			assert.Equal(t, testCase.expectedError, err)
+		}
+		for j, participation := range participations {
+			var participationSaved Participation
+			helios.DB.Where("id = ?", participation.ID).First(&participationSaved)
+			assert.Equal(t, testCase.expectedAttendances[j], participationSaved.Attendance)
+		}
+	}
+}
+
+func TestGetAttendance(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var venue1 Venue = VenueFactorySaved(Venue{})
+	var venue2 Venue = VenueFactorySaved(Venue{})
+	var event1 Event = EventFactorySaved(Event{})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &userLocal})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &auth.User{Role: auth.UserRoleParticipant}})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &auth.User{Role: auth.UserRoleParticipant}, Attendance: AttendancePresent, CheckedInAt: time.Now()})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue2, User: &auth.User{Role: auth.UserRoleParticipant}})
+
+	type getAttendanceTestCase struct {
+		user           auth.User
+		eventSlug      string
+		expectedLength int
+		expectedError  helios.Error
+	}
+	testCases := []getAttendanceTestCase{{
+		user:          userAuthor,
+		eventSlug:     event1.Slug,
+		expectedError: errAttendanceAccessNotAuthorized,
+	}, {
+		user:          userLocal,
+		eventSlug:     "random",
+		expectedError: errEventNotFound,
+	}, {
+		user:           userLocal,
+		eventSlug:      event1.Slug,
+		expectedLength: 2,
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test GetAttendance testcase: %d", i)
+		var participations []Participation
+		var err helios.Error
+		participations, err = GetAttendance(testCase.user, testCase.eventSlug)
+		if testCase.expectedError == nil {
+			assert.Nil(t, err)
+			assert.Equal(t, testCase.expectedLength, len(participations))
+		} else {
+			assert.Equal(t, testCase.expectedError, err)
+		}
+	}
+}
+
+func TestPutAttendance(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var venue1 Venue = VenueFactorySaved(Venue{})
+	var venue2 Venue = VenueFactorySaved(Venue{})
+	var event1 Event = EventFactorySaved(Event{})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	var checkedInAt time.Time = time.Date(2020, 8, 12, 9, 30, 10, 0, time.UTC)
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &userLocal})
+	var participationA Participation = ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &auth.User{Role: auth.UserRoleParticipant}})
+	var participationB Participation = ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &auth.User{Role: auth.UserRoleParticipant}})
+	var participationC Participation = ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue2, User: &auth.User{Role: auth.UserRoleParticipant}})
+
+	type putAttendanceTestCase struct {
+		user                auth.User
+		usersAttendance     map[string]Participation
+		expectedAttendances []string
+		expectedError       string
+	}
+	testCases := []putAttendanceTestCase{{
+		user: userAuthor,
+		usersAttendance: map[string]Participation{
+			participationA.User.Username: {Attendance: AttendancePresent, CheckedInAt: checkedInAt},
+		},
+		expectedAttendances: []string{"", "", ""},
+		expectedError:       `{"code":"attendance_forbidden","message":"User role doesn't have permission to access attendance"}`,
+	}, {
+		user: userLocal,
+		usersAttendance: map[string]Participation{
+			participationA.User.Username: {Attendance: AttendancePresent, CheckedInAt: checkedInAt},
+			participationC.User.Username: {Attendance: AttendanceLate, CheckedInAt: checkedInAt},
+		},
+		expectedAttendances: []string{"", "", ""},
+		expectedError:       `{"code":"form_error","message":{"_error":[],"usersAttendance":{"` + participationC.User.Username + `":["Participant is not registered on the venue"]}}}`,
+	}, {
+		user: userLocal,
+		usersAttendance: map[string]Participation{
+			participationA.User.Username: {Attendance: AttendancePresent, CheckedInAt: checkedInAt, IDVerified: true},
+			participationB.User.Username: {Attendance: AttendanceNoShow},
+		},
+		expectedAttendances: []string{AttendancePresent, AttendanceNoShow, ""},
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test PutAttendance testcase: %d", i)
+		var err helios.Error = PutAttendance(testCase.user, event1.Slug, testCase.usersAttendance)
+		if testCase.expectedError == "" {
+			assert.Nil(t, err)
+		} else {
+			var errJSON []byte
+			var errMarshalling error
+			assert.NotNil(t, err)
+			errJSON, errMarshalling = json.Marshal(err.GetMessage())
+			assert.Nil(t, errMarshalling)
+			assert.Equal(t, testCase.expectedError, string(errJSON))
+		}
+		for j, participation := range []Participation{participationA, participationB, participationC} {
+			var participationSaved Participation
+			helios.DB.Where("id = ?", participation.ID).First(&participationSaved)
+			assert.Equal(t, testCase.expectedAttendances[j], participationSaved.Attendance)
+			if participationSaved.Attendance == AttendancePresent {
+				assert.True(t, checkedInAt.Equal(participationSaved.CheckedInAt))
+				assert.True(t, participationSaved.IDVerified)
+			}
+		}
+	}
+}
+
 func TestDeleteParticipation(t *testing.T) {
 	helios.App.BeforeTest()
 
 	var userParticipant auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
 	var userLocal1 auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	var userOrganizer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
 	var event1 Event = EventFactorySaved(Event{})
 	var event2 Event = EventFactorySaved(Event{})
-	var participation1 Participation = ParticipationFactorySaved(Participation{Event: &event1, User: &userParticipant})
-	var participation2 Participation = ParticipationFactorySaved(Participation{Event: &event1, User: &userLocal1})
+	var venue Venue = VenueFactorySaved(Venue{})
+	var room Room = RoomFactorySaved(Room{Venue: &venue})
+	var participation1 Participation = ParticipationFactorySaved(Participation{Event: &event1, User: &userParticipant, Venue: &venue, RoomID: &room.ID, SeatNumber: 1})
+	var participation2 Participation = ParticipationFactorySaved(Participation{Event: &event1, User: &userLocal1, Venue: &venue})
+	EventRoleFactorySaved(userOrganizer, event1, nil, auth.EventRoleAuthor)
+	EventRoleFactorySaved(userOrganizer, event2, nil, auth.EventRoleAuthor)
 	UserQuestionFactorySaved(UserQuestion{Participation: &participation1})
 	UserQuestionFactorySaved(UserQuestion{Participation: &participation1})
 	var participationCountBefore, userQuestionCountBefore int
@@ -507,14 +2133,14 @@ func TestDeleteParticipation(t *testing.T) {
 		expectedError              helios.Error
 	}
 	testCases := []deleteParticipationTestCase{{
-		user:                       auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		user:                       userOrganizer,
 		eventSlug:                  "random_slug",
 		participationID:            participation1.ID,
 		expectedParticipationCount: participationCountBefore,
 		expectedUserQuestionCount:  userQuestionCountBefore,
 		expectedError:              errEventNotFound,
 	}, {
-		user:                       auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		user:                       userOrganizer,
 		eventSlug:                  event2.Slug,
 		participationID:            participation1.ID,
 		expectedParticipationCount: participationCountBefore,
@@ -535,7 +2161,7 @@ func TestDeleteParticipation(t *testing.T) {
 		expectedParticipationCount: participationCountBefore - 1,
 		expectedUserQuestionCount:  userQuestionCountBefore - 2,
 	}, {
-		user:                       auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		user:                       userOrganizer,
 		eventSlug:                  event1.Slug,
 		participationID:            participation2.ID,
 		expectedParticipation:      participation2,
@@ -561,6 +2187,13 @@ func TestDeleteParticipation(t *testing.T) {
 			assert.Equal(t, testCase.expectedError, err)
 		}
 	}
+
+	// the seat of the deleted participation is released, and the seat index
+	// refuses the seat that is taken
+	var participationSeated Participation = ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue, RoomID: &room.ID, SeatNumber: 1})
+	assert.NotZero(t, participationSeated.ID, "Seat of deleted participation should be released")
+	var participationConflicting Participation = Participation{EventID: event1.ID, VenueID: venue.ID, RoomID: &room.ID, SeatNumber: 1}
+	assert.NotNil(t, helios.DB.Create(&participationConflicting).Error, "Seat should not be taken twice")
 }
 
 func TestGetAllQuestionOfUserAndEvent(t *testing.T) {
@@ -570,9 +2203,11 @@ func TestGetAllQuestionOfUserAndEvent(t *testing.T) {
 	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
 	var event1 Event = EventFactorySaved(Event{})
 	var event2 Event = EventFactorySaved(Event{StartsAt: time.Now().Add(2 * time.Hour)})
-	var participation1 Participation = ParticipationFactorySaved(Participation{Event: &event1, User: &userParticipant})
+	var userReviewer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userReviewer, event1, nil, auth.EventRoleReviewer)
+	var participation1 Participation = ParticipationFactorySaved(Participation{Event: &event1, User: &userParticipant, CheckedInAt: time.Now()})
 	var participation2 Participation = ParticipationFactorySaved(Participation{Event: &event1, User: &userLocal})
-	ParticipationFactorySaved(Participation{Event: &event2, User: &userParticipant})
+	ParticipationFactorySaved(Participation{Event: &event2, User: &userParticipant, CheckedInAt: time.Now()})
 	ParticipationFactorySaved(Participation{Event: &event2, User: &userLocal})
 	var question1 Question = QuestionFactorySaved(Question{Event: &event1})
 	var question2 Question = QuestionFactorySaved(Question{Event: &event1})
@@ -594,9 +2229,13 @@ func TestGetAllQuestionOfUserAndEvent(t *testing.T) {
 		eventSlug:           event1.Slug,
 		expectedQuestionLen: 4,
 	}, {
-		user:                auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		user:                userReviewer,
 		eventSlug:           event1.Slug,
 		expectedQuestionLen: 4,
+	}, {
+		user:          auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		eventSlug:     event1.Slug,
+		expectedError: errEventNotFound,
 	}, {
 		user:                userLocal,
 		eventSlug:           event1.Slug,
@@ -622,6 +2261,10 @@ func TestGetAllQuestionOfUserAndEvent(t *testing.T) {
 		user:                auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
 		eventSlug:           event2.Slug,
 		expectedQuestionLen: 0,
+	}, {
+		user:          *ParticipationFactorySaved(Participation{Event: &event1}).User,
+		eventSlug:     event1.Slug,
+		expectedError: errParticipationNotCheckedIn,
 	}}
 	for i, testCase := range testCases {
 		t.Logf("Test GetAllQuestionOfUserAndEvent testcase: %d", i)
@@ -648,6 +2291,11 @@ func TestUpsertQuestion(t *testing.T) {
 	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
 	var event1 Event = EventFactorySaved(Event{})
 	var event2 Event = EventFactorySaved(Event{})
+	var eventSynced Event = EventFactorySaved(Event{State: EventStateSynced})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	var userReviewer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	EventRoleFactorySaved(userReviewer, event1, nil, auth.EventRoleReviewer)
 	var participation1 Participation = ParticipationFactorySaved(Participation{Event: &event1, User: &userParticipant})
 	ParticipationFactorySaved(Participation{Event: &event1, User: &userLocal})
 	var question1 Question = QuestionFactorySaved(Question{Event: &event1})
@@ -678,12 +2326,24 @@ func TestUpsertQuestion(t *testing.T) {
 		question:              Question{Content: "Content 2", EventID: event1.ID},
 		expectedQuestionCount: questionCountBefore,
 		expectedError:         errQuestionChangeNotAuthorized,
+	}, {
+		user:                  userReviewer,
+		eventSlug:             event1.Slug,
+		question:              Question{Content: "Content 2", EventID: event1.ID},
+		expectedQuestionCount: questionCountBefore,
+		expectedError:         errQuestionChangeNotAuthorized,
 	}, {
 		user:                  auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
 		eventSlug:             "9999",
 		question:              Question{Content: "Content 3", EventID: event1.ID},
 		expectedQuestionCount: questionCountBefore,
 		expectedError:         errEventNotFound,
+	}, {
+		user:                  auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		eventSlug:             eventSynced.Slug,
+		question:              Question{Content: "Content 3", EventID: eventSynced.ID},
+		expectedQuestionCount: questionCountBefore,
+		expectedError:         errEventStateInvalid,
 	}, {
 		user:                  auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
 		eventSlug:             event1.Slug,
@@ -691,13 +2351,13 @@ func TestUpsertQuestion(t *testing.T) {
 		expectedQuestionCount: questionCountBefore + 1,
 		expectedError:         nil,
 	}, {
-		user:                  auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		user:                  userAuthor,
 		eventSlug:             event1.Slug,
 		question:              Question{ID: question1.ID, Content: "Content 5", EventID: event2.ID},
 		expectedQuestionCount: questionCountBefore + 1,
 		expectedError:         nil,
 	}, {
-		user:      auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		user:      userAuthor,
 		eventSlug: event1.Slug,
 		question: Question{
 			Content: "Content 6",
@@ -707,7 +2367,7 @@ func TestUpsertQuestion(t *testing.T) {
 		expectedQuestionCount: questionCountBefore + 2,
 		expectedError:         nil,
 	}, {
-		user:      auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		user:      userAuthor,
 		eventSlug: event1.Slug,
 		question: Question{
 			ID:      question2.ID,
@@ -744,9 +2404,11 @@ func TestGetQuestionOfEventAndUser(t *testing.T) {
 	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
 	var event1 Event = EventFactorySaved(Event{})
 	var event2 Event = EventFactorySaved(Event{StartsAt: time.Now().Add(2 * time.Hour)})
-	var participation1 Participation = ParticipationFactorySaved(Participation{Event: &event1, User: &userParticipant})
+	var userReviewer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userReviewer, event2, nil, auth.EventRoleReviewer)
+	var participation1 Participation = ParticipationFactorySaved(Participation{Event: &event1, User: &userParticipant, CheckedInAt: time.Now()})
 	var participation2 Participation = ParticipationFactorySaved(Participation{Event: &event1, User: &userLocal})
-	ParticipationFactorySaved(Participation{Event: &event2, User: &userParticipant})
+	ParticipationFactorySaved(Participation{Event: &event2, User: &userParticipant, CheckedInAt: time.Now()})
 	ParticipationFactorySaved(Participation{Event: &event2, User: &userLocal})
 	var question1 Question = QuestionFactorySaved(Question{Event: &event1})
 	var question2 Question = QuestionFactorySaved(Question{Event: &event1})
@@ -770,7 +2432,7 @@ func TestGetQuestionOfEventAndUser(t *testing.T) {
 		questionNumber:          1,
 		expectedQuestionContent: question4.Content,
 	}, {
-		user:                    auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		user:                    userReviewer,
 		eventSlug:               event2.Slug,
 		questionNumber:          1,
 		expectedQuestionContent: question4.Content,
@@ -834,9 +2496,14 @@ func TestDeleteQuestion(t *testing.T) {
 	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
 	var event1 Event = EventFactorySaved(Event{})
 	var event2 Event = EventFactorySaved(Event{})
+	var eventSynced Event = EventFactorySaved(Event{State: EventStateSynced})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
 	var participation1 Participation = ParticipationFactorySaved(Participation{Event: &event1, User: &userParticipant})
+	ParticipationFactorySaved(Participation{Event: &event1, User: &userLocal})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
 	var question1 Question = QuestionFactorySaved(Question{Event: &event1})
 	var question2 Question = QuestionFactorySaved(Question{Event: &event1})
+	QuestionFactorySaved(Question{Event: &eventSynced})
 	UserQuestionFactorySaved(UserQuestion{Participation: &participation1, Question: &question1, Ordering: 20, Answer: "abc"}) // 2
 	UserQuestionFactorySaved(UserQuestion{Participation: &participation1, Question: &question2, Ordering: 10, Answer: "def"}) // 1
 	var questionCountBefore, userQuestionCountBefore int
@@ -853,6 +2520,13 @@ func TestDeleteQuestion(t *testing.T) {
 		expectedError             helios.Error
 	}
 	testCases := []deleteQuestionTestCase{{
+		user:                      auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		eventSlug:                 eventSynced.Slug,
+		questionNumber:            1,
+		expectedQuestionCount:     questionCountBefore,
+		expectedUserQuestionCount: userQuestionCountBefore,
+		expectedError:             errEventStateInvalid,
+	}, {
 		user:                      userParticipant,
 		eventSlug:                 event1.Slug,
 		questionNumber:            1,
@@ -867,7 +2541,7 @@ func TestDeleteQuestion(t *testing.T) {
 		expectedUserQuestionCount: userQuestionCountBefore,
 		expectedError:             errQuestionChangeNotAuthorized,
 	}, {
-		user:                      auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		user:                      userAuthor,
 		eventSlug:                 event1.Slug,
 		questionNumber:            23987,
 		expectedQuestionCount:     questionCountBefore,
@@ -918,15 +2592,18 @@ func TestDeleteQuestion(t *testing.T) {
 func TestSubmitSubmission(t *testing.T) {
 	helios.App.BeforeTest()
 	var userParticipant auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
-	var event1 Event = EventFactorySaved(Event{})
-	var event2 Event = EventFactorySaved(Event{StartsAt: time.Now().Add(2 * time.Hour)})
+	var event1 Event = EventFactorySaved(Event{State: EventStateRunning})
+	var event2 Event = EventFactorySaved(Event{StartsAt: time.Now().Add(2 * time.Hour), State: EventStateRunning})
+	var event3 Event = EventFactorySaved(Event{State: EventStateClosed})
 	var question1 Question = QuestionFactorySaved(Question{Event: &event1})
 	var question2 Question = QuestionFactorySaved(Question{Event: &event1, Choices: "|"})
 	var question3 Question = QuestionFactorySaved(Question{Event: &event1})
 	var question4 Question = QuestionFactorySaved(Question{Event: &event2})
-	var participation1 Participation = ParticipationFactorySaved(Participation{User: &userParticipant, Event: &event1})
-	ParticipationFactorySaved(Participation{Event: &event2, User: &userParticipant})
+	var participation1 Participation = ParticipationFactorySaved(Participation{User: &userParticipant, Event: &event1, CheckedInAt: time.Now()})
+	ParticipationFactorySaved(Participation{Event: &event2, User: &userParticipant, CheckedInAt: time.Now()})
+	var participation3 Participation = ParticipationFactorySaved(Participation{Event: &event3, User: &userParticipant, CheckedInAt: time.Now()})
 	UserQuestionFactorySaved(UserQuestion{Participation: &participation1, Question: &question1, Ordering: 20}) // questionNumber 2
+	UserQuestionFactorySaved(UserQuestion{Participation: &participation3, Ordering: 30})
 	UserQuestionFactorySaved(UserQuestion{Participation: &participation1, Question: &question2, Ordering: 10}) // questionNumber 1
 	type submitSubmissionTestCase struct {
 		user           auth.User
@@ -959,6 +2636,12 @@ func TestSubmitSubmission(t *testing.T) {
 		questionNumber: question4.ID,
 		answer:         "random",
 		expectedError:  errEventIsNotYetStarted,
+	}, {
+		user:           userParticipant,
+		eventSlug:      event3.Slug,
+		questionNumber: 1,
+		answer:         "random",
+		expectedError:  errEventStateInvalid,
 	}, {
 		user:           userParticipant,
 		eventSlug:      event1.Slug,
@@ -1010,6 +2693,11 @@ func TestSubmitSubmission(t *testing.T) {
 			assert.Equal(t, testCase.answer, question.UserAnswer)
 			assert.NotEqual(t, 0, userQuestion.ID)
 			assert.Equal(t, testCase.answer, userQuestion.Answer)
+			var auditLog auth.AuditLog
+			helios.DB.Where("action = ?", auditActionSubmissionSubmit).Order("id desc").First(&auditLog)
+			assert.Equal(t, testCase.user.ID, auditLog.ActorID)
+			assert.Equal(t, userQuestion.ID, auditLog.TargetID)
+			assert.Contains(t, auditLog.After, testCase.answer)
 		} else {
 			assert.Equal(t, testCase.expectedError, errSubmit)
 		}
@@ -1019,15 +2707,18 @@ func TestSubmitSubmission(t *testing.T) {
 func TestGetParticipationStatus(t *testing.T) {
 	helios.App.BeforeTest()
 
-	var user1 auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant, SessionLocked: true})
+	var user1 auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
 	var user2 auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
 	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
 	var event1 Event = EventFactorySaved(Event{})
 	var event2 Event = EventFactorySaved(Event{})
 	var notNilTime time.Time = time.Now()
-	ParticipationFactorySaved(Participation{Event: &event1, User: &userLocal})
-	ParticipationFactorySaved(Participation{Event: &event1, User: &user1})
-	ParticipationFactorySaved(Participation{Event: &event1, User: &user2})
+	var venue Venue = VenueFactorySaved(Venue{})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	ParticipationFactorySaved(Participation{Event: &event1, User: &userLocal, Venue: &venue})
+	ParticipationFactorySaved(Participation{Event: &event1, User: &user1, Venue: &venue})
+	ParticipationFactorySaved(Participation{Event: &event1, User: &user2, Venue: &venue})
 	var session auth.Session = auth.Session{
 		ID:        1,
 		User:      &user2,
@@ -1042,7 +2733,7 @@ func TestGetParticipationStatus(t *testing.T) {
 		expectedError  helios.Error
 	}
 	testCases := []getParticipationStatusTestCase{{
-		user:          auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		user:          userAuthor,
 		eventSlug:     event1.Slug,
 		expectedError: errParticipationStatusAccessNotAuthorized,
 	}, {
@@ -1053,17 +2744,15 @@ func TestGetParticipationStatus(t *testing.T) {
 		user:      userLocal,
 		eventSlug: event1.Slug,
 		expectedStatus: []ParticipationStatus{{
-			UserUsername:      user1.Username,
-			IPAddress:         "",
-			LoginAt:           nil,
-			SessionID:         0,
-			UserSessionLocked: true,
+			UserUsername: user1.Username,
+			IPAddress:    "",
+			LoginAt:      nil,
+			SessionID:    0,
 		}, {
-			UserUsername:      user2.Username,
-			IPAddress:         "192.168.0.2",
-			LoginAt:           &notNilTime,
-			SessionID:         session.ID,
-			UserSessionLocked: false,
+			UserUsername: user2.Username,
+			IPAddress:    "192.168.0.2",
+			LoginAt:      &notNilTime,
+			SessionID:    session.ID,
 		}},
 	}}
 	for i, testCase := range testCases {
@@ -1096,8 +2785,11 @@ func TestRemoveParticipationSession(t *testing.T) {
 	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
 	var event1 Event = EventFactorySaved(Event{})
 	var event2 Event = EventFactorySaved(Event{})
-	ParticipationFactorySaved(Participation{Event: &event1, User: &userLocal})
-	ParticipationFactorySaved(Participation{Event: &event1, User: &user1})
+	var venue Venue = VenueFactorySaved(Venue{})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	ParticipationFactorySaved(Participation{Event: &event1, User: &userLocal, Venue: &venue})
+	ParticipationFactorySaved(Participation{Event: &event1, User: &user1, Venue: &venue})
 	var session auth.Session = auth.Session{
 		User:      &user1,
 		Token:     "abc",
@@ -1111,7 +2803,7 @@ func TestRemoveParticipationSession(t *testing.T) {
 		expectedError helios.Error
 	}
 	testCases := []removeParticipationSessionTestCase{{
-		user:          auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		user:          userAuthor,
 		eventSlug:     event1.Slug,
 		sessionID:     session.ID,
 		expectedError: errParticipationStatusAccessNotAuthorized,
@@ -1155,39 +2847,50 @@ func TestGetSynchronizationData(t *testing.T) {
 
 	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
 	var venue Venue = VenueFactorySaved(Venue{})
-	var event1 Event = EventFactorySaved(Event{SimKey: "1234567890abcdef1234567890abcdef"})
-	var event2 Event = EventFactorySaved(Event{})
+	var event1 Event = EventFactorySaved(Event{SimKey: "1234567890abcdef1234567890abcdef", State: EventStatePublished})
+	var event2 Event = EventFactorySaved(Event{State: EventStatePublished})
+	var eventDraft Event = EventFactorySaved(Event{})
+	var room Room = RoomFactorySaved(Room{Venue: &venue, Name: "room1"})
+	RoomFactorySaved(Room{Venue: &venue})
+	RoomFactorySaved(Room{})
 	QuestionFactorySaved(Question{Event: &event1})
 	QuestionFactorySaved(Question{Event: &event1})
 	QuestionFactorySaved(Question{Event: &event2})
 	participations := []Participation{
 		ParticipationFactorySaved(Participation{Event: &event1, User: &userLocal, Venue: &venue, KeyPlain: "abc", KeyHashedOnce: "1", KeyHashedTwice: "key1"}),
-		ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue, KeyPlain: "def", KeyHashedOnce: "2", KeyHashedTwice: "key2"}),
+		ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue, RoomID: &room.ID, SeatNumber: 4, KeyPlain: "def", KeyHashedOnce: "2", KeyHashedTwice: "key2"}),
 		ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue, KeyPlain: "ghi", KeyHashedOnce: "3", KeyHashedTwice: "key3"}),
 	}
 	ParticipationFactorySaved(Participation{Event: &event1})
 	ParticipationFactorySaved(Participation{Event: &event1})
 	ParticipationFactorySaved(Participation{Event: &event2})
+	ParticipationFactorySaved(Participation{Event: &eventDraft, User: &userLocal, Venue: &venue})
 	helios.DB.Create(&SecretShare{Event: &event1, Venue: &venue, PolynomCoeffs: "1|2"})
-	expectedUsersKey := make(map[string]string)
-	expectedUsersY := make(map[string]string)
+	expectedParticipations := make(map[string]SynchronizedParticipation)
 	for _, participation := range participations {
-		expectedUsersKey[participation.User.Username] = participation.KeyHashedTwice
 		x, _ := strconv.Atoi(participation.KeyHashedOnce)
 		s, _ := new(big.Int).SetString("1234567890abcdef1234567890abcdef", 62)
 		s = s.Add(s, big.NewInt(int64(x+2*x*x)))
 		s = s.Mod(s, PRIME)
-		expectedUsersY[participation.User.Username] = s.String()
+		var expectedParticipation SynchronizedParticipation = SynchronizedParticipation{
+			KeyHashedTwice: participation.KeyHashedTwice,
+			SecretShareY:   s.String(),
+		}
+		if participation.RoomID != nil {
+			expectedParticipation.RoomName = room.Name
+			expectedParticipation.SeatNumber = participation.SeatNumber
+		}
+		expectedParticipations[participation.User.Username] = expectedParticipation
 	}
 	type getSynchronizationDataTestCase struct {
 		user                   auth.User
 		eventSlug              string
 		expectedEvent          Event
 		expectedVenue          Venue
+		expectedRoomLength     int
 		expectedQuestionLength int
 		expectedUserLength     int
-		expectedUsersKey       map[string]string
-		expectedUsersY         map[string]string
+		expectedParticipations map[string]SynchronizedParticipation
 		expectedError          helios.Error
 	}
 	testCases := []getSynchronizationDataTestCase{{
@@ -1210,34 +2913,36 @@ func TestGetSynchronizationData(t *testing.T) {
 		user:          userLocal,
 		eventSlug:     event2.Slug,
 		expectedError: errEventNotFound,
+	}, {
+		user:          userLocal,
+		eventSlug:     eventDraft.Slug,
+		expectedError: errEventStateInvalid,
 	}, {
 		user:                   userLocal,
 		eventSlug:              event1.Slug,
 		expectedEvent:          event1,
 		expectedVenue:          venue,
+		expectedRoomLength:     2,
 		expectedQuestionLength: 2,
 		expectedUserLength:     3,
-		expectedUsersKey:       expectedUsersKey,
-		expectedUsersY:         expectedUsersY,
+		expectedParticipations: expectedParticipations,
 	}}
 	for i, testCase := range testCases {
 		t.Logf("Test GetSynchronizationData testcase: %d", i)
-		var event *Event
-		var venue *Venue
-		var questions []Question
-		var users []auth.User
-		var usersKey map[string]string
-		var usersY map[string]string
+		var payload *SynchronizationPayload
 		var err helios.Error
-		event, venue, questions, users, usersKey, usersY, err = GetSynchronizationData(testCase.user, testCase.eventSlug)
+		payload, err = GetSynchronizationData(testCase.user, testCase.eventSlug)
 		if testCase.expectedError == nil {
 			assert.Nil(t, err)
-			assert.Equal(t, testCase.expectedEvent.Title, event.Title)
-			assert.Equal(t, testCase.expectedVenue.Name, venue.Name)
-			assert.Equal(t, testCase.expectedQuestionLength, len(questions))
-			assert.Equal(t, testCase.expectedUserLength, len(users))
-			assert.Equal(t, testCase.expectedUsersKey, usersKey)
-			assert.Equal(t, testCase.expectedUsersY, usersY)
+			assert.Equal(t, testCase.expectedEvent.Title, payload.Event.Title)
+			assert.Equal(t, testCase.expectedVenue.Name, payload.Venue.Name)
+			assert.Equal(t, testCase.expectedRoomLength, len(payload.Rooms))
+			assert.Equal(t, testCase.expectedQuestionLength, len(payload.Questions))
+			assert.Equal(t, testCase.expectedUserLength, len(payload.Users))
+			assert.Equal(t, testCase.expectedParticipations, payload.Participations)
+			var eventSaved Event
+			helios.DB.Where("slug = ?", testCase.eventSlug).First(&eventSaved)
+			assert.Equal(t, EventStateSynced, eventSaved.State, "Published event should be moved to synced")
 		} else {
 			assert.Equal(t, testCase.expectedError, err)
 		}
@@ -1247,12 +2952,13 @@ func TestGetSynchronizationData(t *testing.T) {
 func TestPutSynchronizationData(t *testing.T) {
 	helios.App.BeforeTest()
 
-	var userCountBefore, eventCountBefore, venueCountBefore, questionCountBefore, participationCountBefore, userQuestionCountBefore int
+	var userCountBefore, eventCountBefore, venueCountBefore, roomCountBefore, questionCountBefore, participationCountBefore, userQuestionCountBefore int
 	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
 	var userAdmin auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin})
 	var userParticipant1 auth.User = auth.UserFactory(auth.User{Role: auth.UserRoleParticipant})
 	var venue Venue = VenueFactorySaved(Venue{})
 	var oldEvent Event = EventFactorySaved(Event{})
+	var runningEvent Event = EventFactorySaved(Event{State: EventStateRunning})
 	var oldQuestions []Question = []Question{
 		QuestionFactorySaved(Question{Event: &oldEvent}),
 		QuestionFactorySaved(Question{Event: &oldEvent}),
@@ -1269,64 +2975,86 @@ func TestPutSynchronizationData(t *testing.T) {
 	helios.DB.Model(&auth.User{}).Count(&userCountBefore)
 	helios.DB.Model(&Event{}).Count(&eventCountBefore)
 	helios.DB.Model(&Venue{}).Count(&venueCountBefore)
+	helios.DB.Model(&Room{}).Count(&roomCountBefore)
 	helios.DB.Model(&Question{}).Count(&questionCountBefore)
 	helios.DB.Model(&Participation{}).Count(&participationCountBefore)
 	helios.DB.Model(&UserQuestion{}).Count(&userQuestionCountBefore)
 	type putSynchronizationDataTestCase struct {
 		user                       auth.User
-		event                      Event
-		venue                      Venue
-		questions                  []Question
-		users                      []auth.User
-		usersKey                   map[string]string
-		usersY                     map[string]string
+		payload                    SynchronizationPayload
 		expectedError              helios.Error
 		expectedEventCount         int
 		expectedVenueCount         int
+		expectedRoomCount          int
+		expectedSeat               map[string]uint
 		expectedUserCount          int
 		expectedQuestionCount      int
 		expectedParticipationCount int
 		expectedUserQuestionCount  int
 	}
 	testCases := []putSynchronizationDataTestCase{{
-		user:                       userAdmin,
-		event:                      EventFactory(Event{}),
-		venue:                      VenueFactory(Venue{}),
-		questions:                  []Question{},
-		users:                      []auth.User{},
-		usersKey:                   make(map[string]string),
-		usersY:                     make(map[string]string),
+		user: userAdmin,
+		payload: SynchronizationPayload{
+			Event: EventFactory(Event{}),
+			Venue: VenueFactory(Venue{}),
+		},
 		expectedError:              errSynchronizationNotAuthorized,
 		expectedUserCount:          userCountBefore,
 		expectedVenueCount:         venueCountBefore,
+		expectedRoomCount:          roomCountBefore,
 		expectedEventCount:         eventCountBefore,
 		expectedQuestionCount:      questionCountBefore,
 		expectedParticipationCount: participationCountBefore,
 		expectedUserQuestionCount:  userQuestionCountBefore,
 	}, {
-		user:                       userLocal,
-		event:                      EventFactory(Event{}),
-		venue:                      VenueFactory(Venue{}),
-		questions:                  []Question{QuestionFactory(Question{})},
-		users:                      []auth.User{auth.UserFactory(auth.User{Username: "user1", Role: auth.UserRoleParticipant})},
-		usersKey:                   map[string]string{"user1": "key_user_1"},
-		usersY:                     map[string]string{"user1": "2"},
+		user: userLocal,
+		payload: SynchronizationPayload{
+			Event: runningEvent,
+			Venue: VenueFactory(Venue{}),
+		},
+		expectedError:              errEventStateInvalid,
+		expectedUserCount:          userCountBefore,
+		expectedVenueCount:         venueCountBefore,
+		expectedRoomCount:          roomCountBefore,
+		expectedEventCount:         eventCountBefore,
+		expectedQuestionCount:      questionCountBefore,
+		expectedParticipationCount: participationCountBefore,
+		expectedUserQuestionCount:  userQuestionCountBefore,
+	}, {
+		user: userLocal,
+		payload: SynchronizationPayload{
+			Event:     EventFactory(Event{}),
+			Venue:     VenueFactory(Venue{}),
+			Questions: []Question{QuestionFactory(Question{})},
+			Rooms:     []Room{{Name: "room1", Capacity: 5}},
+			Users:     []auth.User{auth.UserFactory(auth.User{Username: "user1", Role: auth.UserRoleParticipant})},
+			Participations: map[string]SynchronizedParticipation{
+				"user1": {KeyHashedTwice: "key_user_1", SecretShareY: "2", RoomName: "room1", SeatNumber: 3},
+			},
+		},
 		expectedUserCount:          userCountBefore + 1,
 		expectedEventCount:         eventCountBefore + 1,
 		expectedVenueCount:         venueCountBefore + 1,
+		expectedRoomCount:          roomCountBefore + 1,
+		expectedSeat:               map[string]uint{"user1": 3},
 		expectedQuestionCount:      questionCountBefore + 1,
 		expectedParticipationCount: participationCountBefore + 1,
 		expectedUserQuestionCount:  userQuestionCountBefore + 1,
 	}, {
-		user:                       userLocal,
-		event:                      oldEvent,
-		venue:                      VenueFactory(Venue{}),
-		questions:                  []Question{QuestionFactory(Question{})},
-		users:                      []auth.User{auth.UserFactory(auth.User{Username: "user2", Role: auth.UserRoleParticipant}), userParticipant1},
-		usersKey:                   map[string]string{"user2": "key_user_2"},
-		usersY:                     map[string]string{"user2": "3"},
+		user: userLocal,
+		payload: SynchronizationPayload{
+			Event:     oldEvent,
+			Venue:     VenueFactory(Venue{}),
+			Questions: []Question{QuestionFactory(Question{})},
+			Users:     []auth.User{auth.UserFactory(auth.User{Username: "user2", Role: auth.UserRoleParticipant}), userParticipant1},
+			Participations: map[string]SynchronizedParticipation{
+				"user2": {KeyHashedTwice: "key_user_2", SecretShareY: "3"},
+			},
+		},
 		expectedUserCount:          userCountBefore + 2,
 		expectedVenueCount:         venueCountBefore + 2,
+		expectedRoomCount:          roomCountBefore + 1,
+		expectedSeat:               map[string]uint{"user2": 0},
 		expectedEventCount:         eventCountBefore + 1,
 		expectedQuestionCount:      questionCountBefore + 1 - len(oldQuestions) + 1,
 		expectedParticipationCount: participationCountBefore + 1 - len(oldParticipations) + 2,
@@ -1335,22 +3063,36 @@ func TestPutSynchronizationData(t *testing.T) {
 	for i, testCase := range testCases {
 		t.Logf("Test PutSynchronizationData testcase: %d", i)
 		var err helios.Error
-		var userCount, eventCount, venueCount, questionCount, participationCount, userQuestionCount int
-		err = PutSynchronizationData(testCase.user, testCase.event, testCase.venue, testCase.questions, testCase.users, testCase.usersKey, testCase.usersY)
+		var userCount, eventCount, venueCount, roomCount, questionCount, participationCount, userQuestionCount int
+		err = PutSynchronizationData(testCase.user, testCase.payload)
 		helios.DB.Model(&auth.User{}).Count(&userCount)
 		helios.DB.Model(&Event{}).Count(&eventCount)
 		helios.DB.Model(&Venue{}).Count(&venueCount)
+		helios.DB.Model(&Room{}).Count(&roomCount)
 		helios.DB.Model(&Question{}).Count(&questionCount)
 		helios.DB.Model(&Participation{}).Count(&participationCount)
 		helios.DB.Model(&UserQuestion{}).Count(&userQuestionCount)
 		assert.Equal(t, testCase.expectedUserCount, userCount)
 		assert.Equal(t, testCase.expectedEventCount, eventCount)
 		assert.Equal(t, testCase.expectedVenueCount, venueCount)
+		assert.Equal(t, testCase.expectedRoomCount, roomCount)
 		assert.Equal(t, testCase.expectedQuestionCount, questionCount)
 		assert.Equal(t, testCase.expectedParticipationCount, participationCount)
 		assert.Equal(t, testCase.expectedUserQuestionCount, userQuestionCount)
 		if testCase.expectedError == nil {
 			assert.Nil(t, err)
+			for username, seatNumber := range testCase.expectedSeat {
+				var participationSaved Participation
+				helios.DB.
+					Select("participations.*").
+					Joins("inner join users on participations.user_id = users.id").
+					Where("users.username = ?", username).
+					First(&participationSaved)
+				assert.Equal(t, seatNumber, participationSaved.SeatNumber)
+			}
+			var eventSaved Event
+			helios.DB.Where("slug = ?", testCase.payload.Event.Slug).First(&eventSaved)
+			assert.Equal(t, EventStateSynced, eventSaved.State)
 		} else {
 			assert.Equal(t, testCase.expectedError, err)
 		}
@@ -1362,6 +3104,8 @@ func TestDecryptEventData(t *testing.T) {
 	var event3 Event = EventFactorySaved(Event{})
 	var event2 Event = EventFactorySaved(Event{})
 	var event1 Event = EventFactorySaved(Event{})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
 	var simKey string = event1.SimKey
 	event1.DecryptedAt = time.Time{}
 	event1.SimKey = ""
@@ -1387,7 +3131,7 @@ func TestDecryptEventData(t *testing.T) {
 		expectedError helios.Error
 	}
 	testCases := []decryptEventDataTestCase{{
-		user:          auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		user:          userAuthor,
 		eventSlug:     event1.Slug,
 		simKey:        simKey,
 		expectedError: errDecryptEventForbidden,
@@ -1429,6 +3173,37 @@ func TestDecryptEventData(t *testing.T) {
 			assert.Equal(t, testCase.expectedError, err)
 		}
 	}
+	var decryptAuditLogCount int
+	helios.DB.Model(auth.AuditLog{}).Where("action = ?", auditActionEventDecrypt).Where("target_id = ?", event1.ID).Count(&decryptAuditLogCount)
+	assert.Equal(t, 1, decryptAuditLogCount, "Decryption should be recorded once on audit log")
+}
+
+func TestCheckEventKeys(t *testing.T) {
+	type checkEventKeysTestCase struct {
+		modifyEvent   func(event *Event)
+		expectedError bool
+	}
+	testCases := []checkEventKeysTestCase{
+		checkEventKeysTestCase{modifyEvent: func(event *Event) {}},
+		checkEventKeysTestCase{modifyEvent: func(event *Event) { event.PrvKey = "" }},
+		checkEventKeysTestCase{modifyEvent: func(event *Event) { event.PubKey = "" }, expectedError: true},
+		checkEventKeysTestCase{modifyEvent: func(event *Event) { event.PubKey = "not base64" }, expectedError: true},
+		checkEventKeysTestCase{modifyEvent: func(event *Event) { event.PrvKey = "bm90IGEga2V5" }, expectedError: true},
+		checkEventKeysTestCase{modifyEvent: func(event *Event) { event.PubKey, event.State = "", EventStateArchived }},
+	}
+	for i, testCase := range testCases {
+		t.Logf("Test CheckEventKeys testcase: %d", i)
+		helios.App.BeforeTest()
+		var event Event = EventFactorySaved(Event{})
+		testCase.modifyEvent(&event)
+		helios.DB.Save(&event)
+		err := CheckEventKeys()
+		if testCase.expectedError {
+			assert.NotNil(t, err)
+		} else {
+			assert.Nil(t, err)
+		}
+	}
 }
 
 func TestEncryption(t *testing.T) {
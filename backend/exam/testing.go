@@ -17,6 +17,7 @@ import (
 
 var eventSeq uint = 0
 var venueSeq uint = 0
+var roomSeq uint = 0
 var participationSeq uint = 0
 var questionSeq uint = 0
 var questionChoiceSeq uint = 0
@@ -88,6 +89,41 @@ func VenueFactorySaved(venue Venue) Venue {
 	return venue
 }
 
+// RoomFactory creates a room for testing. The given argument will be
+// completed if the attribute is empty.
+func RoomFactory(room Room) Room {
+	roomSeq = roomSeq + 1
+	if room.Name == "" {
+		room.Name = fmt.Sprintf("Room %d", roomSeq)
+	}
+	if room.Capacity == 0 {
+		room.Capacity = 10
+	}
+	if room.Venue == nil && room.VenueID == 0 {
+		venue := VenueFactory(Venue{})
+		room.Venue = &venue
+	}
+	return room
+}
+
+// RoomFactorySaved do exactly like RoomFactory but the result
+// will be saved to database
+func RoomFactorySaved(room Room) Room {
+	if room.ID == 0 {
+		room = RoomFactory(room)
+		if room.Venue != nil {
+			var venue Venue = VenueFactorySaved(*room.Venue)
+			room.VenueID = venue.ID
+			room.Venue = nil
+			helios.DB.Create(&room)
+			room.Venue = &venue
+		} else {
+			helios.DB.Create(&room)
+		}
+	}
+	return room
+}
+
 // ParticipationFactory creates a participation for testing. The given argument will be
 // completed if the attribute is empty.
 func ParticipationFactory(participation Participation) Participation {
@@ -114,7 +150,8 @@ func ParticipationFactory(participation Participation) Participation {
 }
 
 // ParticipationFactorySaved do exactly like ParticipationFactory but the result
-// will be saved to database
+// will be saved to database. Like UpsertParticipation, local user is assigned
+// as the proctor and venue manager of the venue.
 func ParticipationFactorySaved(participation Participation) Participation {
 	if participation.ID == 0 {
 		participation = ParticipationFactory(participation)
@@ -128,6 +165,9 @@ func ParticipationFactorySaved(participation Participation) Participation {
 		participation.Event = nil
 		participation.User = nil
 		helios.DB.Create(&participation)
+		if user.IsLocal() && venue.ID != 0 {
+			assignLocalEventRoles(helios.DB, user.ID, event.ID, venue.ID)
+		}
 		participation.Venue = &venue
 		participation.Event = &event
 		participation.User = &user
@@ -135,6 +175,17 @@ func ParticipationFactorySaved(participation Participation) Participation {
 	return participation
 }
 
+// EventRoleFactorySaved assigns the user to the role on the event for testing.
+// The assignment is on all venues if the venue is nil.
+func EventRoleFactorySaved(user auth.User, event Event, venue *Venue, role string) auth.EventRole {
+	var eventRole auth.EventRole = auth.EventRole{UserID: user.ID, EventID: event.ID, Role: role}
+	if venue != nil {
+		eventRole.VenueID = venue.ID
+	}
+	helios.DB.Create(&eventRole)
+	return eventRole
+}
+
 // QuestionFactory creates a question for testing. The given argument will be
 // completed if the attribute is empty.
 func QuestionFactory(question Question) Question {
@@ -1,6 +1,7 @@
 package exam
 
 import (
+	cryptorand "crypto/rand"
 	"math/big"
 	"math/rand"
 	"strings"
@@ -8,10 +9,11 @@ import (
 )
 
 const (
-	tokenBytes   = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	tokenIdxBits = 6                   // 6 bits to represent a token index
-	tokenIdxMask = 1<<tokenIdxBits - 1 // All 1-bits, as many as tokenIdxBits
-	tokenIdxMax  = 63 / tokenIdxBits   // # of token indices fitting in 63 bits
+	tokenBytes    = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	passwordBytes = "abcdefghjkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789" // without look-alike characters
+	tokenIdxBits  = 6                                                         // 6 bits to represent a token index
+	tokenIdxMask  = 1<<tokenIdxBits - 1                                       // All 1-bits, as many as tokenIdxBits
+	tokenIdxMax   = 63 / tokenIdxBits                                         // # of token indices fitting in 63 bits
 )
 
 var randomSource = rand.NewSource(time.Now().UnixNano())
@@ -37,6 +39,22 @@ func generateRandomToken(tokenLength int) string {
 	return sb.String()
 }
 
+// generateSecureToken generates token of given length from the alphabet
+// using cryptographically secure random source
+func generateSecureToken(tokenLength int, alphabet string) (string, error) {
+	sb := strings.Builder{}
+	sb.Grow(tokenLength)
+	var alphabetLength *big.Int = big.NewInt(int64(len(alphabet)))
+	for i := 0; i < tokenLength; i++ {
+		idx, err := cryptorand.Int(cryptorand.Reader, alphabetLength)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(alphabet[idx.Int64()])
+	}
+	return sb.String(), nil
+}
+
 func generateNRandomBigInt(n int) []big.Int {
 	var primelength uint = 256
 	var twoPower *big.Int = new(big.Int).Lsh(big.NewInt(1), primelength)
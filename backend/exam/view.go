@@ -80,6 +80,141 @@ func VenueDeleteView(req helios.Request) {
 	req.SendJSON(serializedVenue, http.StatusOK)
 }
 
+// RoomListView send list of rooms of the venue
+func RoomListView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	venueID, errParseVenueID := req.GetURLParamUint("venueID")
+	if errParseVenueID != nil {
+		req.SendJSON(errVenueNotFound.GetMessage(), errVenueNotFound.GetStatusCode())
+		return
+	}
+
+	var rooms []Room
+	var err helios.Error
+	rooms, err = GetAllRoomOfVenue(user, venueID)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+
+	serializedRooms := make([]RoomData, 0)
+	for _, room := range rooms {
+		serializedRooms = append(serializedRooms, SerializeRoom(room))
+	}
+	req.SendJSON(serializedRooms, http.StatusOK)
+}
+
+// RoomCreateView creates the room on the venue
+func RoomCreateView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	venueID, errParseVenueID := req.GetURLParamUint("venueID")
+	if errParseVenueID != nil {
+		req.SendJSON(errVenueNotFound.GetMessage(), errVenueNotFound.GetStatusCode())
+		return
+	}
+
+	var roomData RoomData
+	var room Room
+	var err helios.Error
+	err = req.DeserializeRequestData(&roomData)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	err = DeserializeRoom(roomData, &room)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	room.ID = 0
+	err = UpsertRoom(user, venueID, &room)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeRoom(room), http.StatusCreated)
+}
+
+// RoomUpdateView updates the room of the venue
+func RoomUpdateView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	venueID, errParseVenueID := req.GetURLParamUint("venueID")
+	if errParseVenueID != nil {
+		req.SendJSON(errVenueNotFound.GetMessage(), errVenueNotFound.GetStatusCode())
+		return
+	}
+	roomID, errParseRoomID := req.GetURLParamUint("roomID")
+	if errParseRoomID != nil {
+		req.SendJSON(errRoomNotFound.GetMessage(), errRoomNotFound.GetStatusCode())
+		return
+	}
+
+	var roomData RoomData
+	var room Room
+	var err helios.Error
+	err = req.DeserializeRequestData(&roomData)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	err = DeserializeRoom(roomData, &room)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	room.ID = roomID
+	err = UpsertRoom(user, venueID, &room)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeRoom(room), http.StatusOK)
+}
+
+// RoomDeleteView deletes the room of the venue
+func RoomDeleteView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	venueID, errParseVenueID := req.GetURLParamUint("venueID")
+	if errParseVenueID != nil {
+		req.SendJSON(errVenueNotFound.GetMessage(), errVenueNotFound.GetStatusCode())
+		return
+	}
+	roomID, errParseRoomID := req.GetURLParamUint("roomID")
+	if errParseRoomID != nil {
+		req.SendJSON(errRoomNotFound.GetMessage(), errRoomNotFound.GetStatusCode())
+		return
+	}
+
+	var room *Room
+	var err helios.Error
+	room, err = DeleteRoom(user, venueID, roomID)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeRoom(*room), http.StatusOK)
+}
+
 // EventListView send list of questions
 func EventListView(req helios.Request) {
 	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
@@ -88,7 +223,11 @@ func EventListView(req helios.Request) {
 		return
 	}
 
-	var events []Event = GetAllEventOfUser(user)
+	events, err := GetAllEventOfUser(user)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
 	serializedEvents := make([]EventData, 0)
 	for _, event := range events {
 		serializedEvents = append(serializedEvents, SerializeEvent(event))
@@ -122,6 +261,86 @@ func EventCreateView(req helios.Request) {
 	req.SendJSON(SerializeEvent(event), http.StatusCreated)
 }
 
+// EventRoleListView send list of role assignments on the event
+func EventRoleListView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var eventSlug string = req.GetURLParam("eventSlug")
+	var eventRoles []auth.EventRole
+	var err helios.Error
+	eventRoles, err = GetAllEventRoleOfEvent(user, eventSlug)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+
+	serializedEventRoles := make([]EventRoleData, 0)
+	for _, eventRole := range eventRoles {
+		serializedEventRoles = append(serializedEventRoles, SerializeEventRole(eventRole))
+	}
+	req.SendJSON(serializedEventRoles, http.StatusOK)
+}
+
+// EventRoleCreateView assigns the user to the role on the event
+func EventRoleCreateView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var eventSlug string = req.GetURLParam("eventSlug")
+	var eventRoleData EventRoleData
+	var eventRole auth.EventRole
+	var err helios.Error
+	err = req.DeserializeRequestData(&eventRoleData)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	err = DeserializeEventRole(eventRoleData, &eventRole)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	eventRole.ID = 0
+	err = AssignEventRole(user, eventSlug, eventRoleData.UserUsername, &eventRole)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeEventRole(eventRole), http.StatusCreated)
+}
+
+// EventRoleDeleteView revokes the role assignment on the event
+func EventRoleDeleteView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var eventSlug string = req.GetURLParam("eventSlug")
+	eventRoleID, errParseEventRoleID := req.GetURLParamUint("eventRoleID")
+	if errParseEventRoleID != nil {
+		req.SendJSON(errEventRoleNotFound.GetMessage(), errEventRoleNotFound.GetStatusCode())
+		return
+	}
+
+	var eventRole *auth.EventRole
+	var err helios.Error
+	eventRole, err = RevokeEventRole(user, eventSlug, eventRoleID)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeEventRole(*eventRole), http.StatusOK)
+}
+
 // ParticipationListView send list of participations
 func ParticipationListView(req helios.Request) {
 	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
@@ -179,6 +398,303 @@ func ParticipationCreateView(req helios.Request) {
 	req.SendJSON(SerializeParticipation(participation), http.StatusOK)
 }
 
+// ParticipantImportView creates participants and their participations on
+// the event from CSV, then sends the imported participants with their
+// generated passwords and keys
+func ParticipantImportView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var eventSlug string = req.GetURLParam("eventSlug")
+	var participantImportRequest ParticipantImportRequest
+	var participants []ParticipantImportData
+	var err helios.Error
+	err = req.DeserializeRequestData(&participantImportRequest)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	err = DeserializeParticipantImportRequest(participantImportRequest, &participants)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+
+	err = ImportParticipants(user, eventSlug, participants)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(participants, http.StatusCreated)
+}
+
+// CredentialCardView generates the credential cards of participants on the venue
+// and sends the PDF, encoded in base64
+func CredentialCardView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var eventSlug string = req.GetURLParam("eventSlug")
+	venueID, errParseVenueID := req.GetURLParamUint("venueID")
+	if errParseVenueID != nil {
+		req.SendJSON(errVenueNotFound.GetMessage(), errVenueNotFound.GetStatusCode())
+		return
+	}
+
+	var credentialCardRequest CredentialCardRequest
+	var layout string
+	var pdf []byte
+	var skipped []string
+	var err helios.Error
+	err = req.DeserializeRequestData(&credentialCardRequest)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	err = DeserializeCredentialCardRequest(credentialCardRequest, &layout)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+
+	pdf, skipped, err = GenerateCredentialCards(user, eventSlug, venueID, layout, credentialCardRequest.ResetPassword, credentialCardRequest.ReissueKey)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeCredentialCard(pdf, skipped), http.StatusOK)
+}
+
+// SeatingChartView sends the seating chart of the venue on the event
+func SeatingChartView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var eventSlug string = req.GetURLParam("eventSlug")
+	venueID, errParseVenueID := req.GetURLParamUint("venueID")
+	if errParseVenueID != nil {
+		req.SendJSON(errVenueNotFound.GetMessage(), errVenueNotFound.GetStatusCode())
+		return
+	}
+
+	var venue *Venue
+	var rooms []Room
+	var participations []Participation
+	var err helios.Error
+	venue, rooms, participations, err = GetSeatingChart(user, eventSlug, venueID)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeSeatingChart(*venue, rooms, participations), http.StatusOK)
+}
+
+// SeatingChartPrintView sends the printable seating chart of the venue on the event
+func SeatingChartPrintView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var eventSlug string = req.GetURLParam("eventSlug")
+	venueID, errParseVenueID := req.GetURLParamUint("venueID")
+	if errParseVenueID != nil {
+		req.SendJSON(errVenueNotFound.GetMessage(), errVenueNotFound.GetStatusCode())
+		return
+	}
+
+	var pdf []byte
+	var err helios.Error
+	pdf, err = PrintSeatingChart(user, eventSlug, venueID)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeSeatingChartPrint(pdf), http.StatusOK)
+}
+
+// SeatAssignView automatically assigns seats to participants on the venue,
+// then sends the seating chart
+func SeatAssignView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var eventSlug string = req.GetURLParam("eventSlug")
+	venueID, errParseVenueID := req.GetURLParamUint("venueID")
+	if errParseVenueID != nil {
+		req.SendJSON(errVenueNotFound.GetMessage(), errVenueNotFound.GetStatusCode())
+		return
+	}
+
+	var venue *Venue
+	var rooms []Room
+	var participations []Participation
+	var err helios.Error
+	err = AssignSeats(user, eventSlug, venueID)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	venue, rooms, participations, err = GetSeatingChart(user, eventSlug, venueID)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeSeatingChart(*venue, rooms, participations), http.StatusOK)
+}
+
+// SeatImportView assigns seats to participants on the venue from CSV,
+// then sends the seating chart
+func SeatImportView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var eventSlug string = req.GetURLParam("eventSlug")
+	venueID, errParseVenueID := req.GetURLParamUint("venueID")
+	if errParseVenueID != nil {
+		req.SendJSON(errVenueNotFound.GetMessage(), errVenueNotFound.GetStatusCode())
+		return
+	}
+
+	var seatImportRequest SeatImportRequest
+	var seats []SeatAssignmentData
+	var err helios.Error
+	err = req.DeserializeRequestData(&seatImportRequest)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	err = DeserializeSeatImportRequest(seatImportRequest, &seats)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+
+	var venue *Venue
+	var rooms []Room
+	var participations []Participation
+	err = ImportSeatAssignment(user, eventSlug, venueID, seats)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	venue, rooms, participations, err = GetSeatingChart(user, eventSlug, venueID)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeSeatingChart(*venue, rooms, participations), http.StatusOK)
+}
+
+// CheckInView checks in a participant on the venue of the proctor
+func CheckInView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var eventSlug string = req.GetURLParam("eventSlug")
+	var checkInRequest CheckInRequest
+	var participation *Participation
+	var err helios.Error
+	err = req.DeserializeRequestData(&checkInRequest)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+
+	participation, err = CheckInParticipation(user, eventSlug, checkInRequest.UserUsername, checkInRequest.IDVerified)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeAttendance(*participation), http.StatusOK)
+}
+
+// AttendanceNoShowView records participants that are not checked in as no-show
+func AttendanceNoShowView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var eventSlug string = req.GetURLParam("eventSlug")
+	var err helios.Error = RecordNoShow(user, eventSlug)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON("OK", http.StatusOK)
+}
+
+// GetAttendanceView sends the attendance of participants on the venue of the proctor
+func GetAttendanceView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var eventSlug string = req.GetURLParam("eventSlug")
+	var participations []Participation
+	var err helios.Error
+	participations, err = GetAttendance(user, eventSlug)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeAttendanceSynchronizationData(participations), http.StatusOK)
+}
+
+// PutAttendanceView saves the attendance sent back by local server
+func PutAttendanceView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var eventSlug string = req.GetURLParam("eventSlug")
+	var attendanceSynchronizationData AttendanceSynchronizationData
+	var usersAttendance map[string]Participation
+	var err helios.Error
+	err = req.DeserializeRequestData(&attendanceSynchronizationData)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	err = DeserializeAttendanceSynchronizationData(attendanceSynchronizationData, &usersAttendance)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+
+	err = PutAttendance(user, eventSlug, usersAttendance)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON("OK", http.StatusOK)
+}
+
 // ParticipationVerifyView used to submit hashed once participation key
 func ParticipationVerifyView(req helios.Request) {
 	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
@@ -427,19 +943,14 @@ func GetSynchronizationDataView(req helios.Request) {
 		return
 	}
 	var eventSlug string = req.GetURLParam("eventSlug")
-	var event *Event
-	var venue *Venue
-	var questions []Question
-	var users []auth.User
-	var usersKey map[string]string
-	var usersY map[string]string
+	var payload *SynchronizationPayload
 	var err helios.Error
 
-	event, venue, questions, users, usersKey, usersY, err = GetSynchronizationData(user, eventSlug)
+	payload, err = GetSynchronizationData(user, eventSlug)
 	if err != nil {
 		req.SendJSON(err.GetMessage(), err.GetStatusCode())
 	} else {
-		var synchronizationData SynchronizationData = SerializeSynchronizationData(*event, *venue, questions, users, usersKey, usersY)
+		var synchronizationData SynchronizationData = SerializeSynchronizationData(*payload)
 		req.SendJSON(synchronizationData, http.StatusOK)
 	}
 }
@@ -459,21 +970,16 @@ func PutSynchronizationDataView(req helios.Request) {
 		return
 	}
 
-	var event Event
-	var venue Venue
-	var questions []Question
-	var users []auth.User
-	var usersKey map[string]string
-	var usersY map[string]string
+	var payload SynchronizationPayload
 	var err helios.Error
 
-	err = DeserializeSynchronizationData(synchronizationData, &event, &venue, &questions, &users, &usersKey, &usersY)
+	err = DeserializeSynchronizationData(synchronizationData, &payload)
 	if err != nil {
 		req.SendJSON(err.GetMessage(), err.GetStatusCode())
 		return
 	}
 
-	err = PutSynchronizationData(user, event, venue, questions, users, usersKey, usersY)
+	err = PutSynchronizationData(user, payload)
 	if err != nil {
 		req.SendJSON(err.GetMessage(), err.GetStatusCode())
 	} else {
@@ -481,6 +987,134 @@ func PutSynchronizationDataView(req helios.Request) {
 	}
 }
 
+// EventDetailView shows the event with the slug
+func EventDetailView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var eventSlug string = req.GetURLParam("eventSlug")
+	var event Event
+	var err helios.Error
+	event, err = GetEventOfUser(user, eventSlug)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeEvent(event), http.StatusOK)
+}
+
+// EventUpdateView updates the event with the slug
+func EventUpdateView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var eventSlug string = req.GetURLParam("eventSlug")
+	var updateRequest EventUpdateRequest
+	var event Event
+	var err helios.Error
+	err = req.DeserializeRequestData(&updateRequest)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	err = DeserializeEvent(updateRequest.EventData, &event)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	err = UpdateEvent(user, eventSlug, &event, updateRequest.Force)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeEvent(event), http.StatusOK)
+}
+
+// EventDeleteView deletes the event with the slug
+func EventDeleteView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var eventSlug string = req.GetURLParam("eventSlug")
+	var deleteRequest EventDeleteRequest
+	// the request data is optional, the deletion is not forced without it
+	req.DeserializeRequestData(&deleteRequest)
+
+	var event *Event
+	var err helios.Error
+	event, err = DeleteEvent(user, eventSlug, deleteRequest.Force)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeEvent(*event), http.StatusOK)
+}
+
+// EventCloneView clones the event with the new slug and time
+func EventCloneView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var eventSlug string = req.GetURLParam("eventSlug")
+	var cloneRequest EventCloneRequest
+	var clone Event
+	var err helios.Error
+	err = req.DeserializeRequestData(&cloneRequest)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	err = DeserializeEventClone(cloneRequest, &clone)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	err = CloneEvent(user, eventSlug, &clone, cloneRequest.IncludeParticipations, cloneRequest.IncludeVenueAssignments)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeEvent(clone), http.StatusCreated)
+}
+
+// EventTransitionView moves the event to the requested state
+func EventTransitionView(req helios.Request) {
+	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
+	if !ok {
+		req.SendJSON(helios.ErrInternalServerError.GetMessage(), helios.ErrInternalServerError.GetStatusCode())
+		return
+	}
+
+	var eventSlug string = req.GetURLParam("eventSlug")
+	var transitionRequest EventTransitionRequest
+	var errDeserialization helios.Error = req.DeserializeRequestData(&transitionRequest)
+	if errDeserialization != nil {
+		req.SendJSON(errDeserialization.GetMessage(), errDeserialization.GetStatusCode())
+		return
+	}
+
+	var event *Event
+	var err helios.Error
+	event, err = TransitionEvent(user, eventSlug, transitionRequest.State)
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeEvent(*event), http.StatusOK)
+}
+
 // DecryptEventDataView decrypts all the event data using the key
 func DecryptEventDataView(req helios.Request) {
 	user, ok := req.GetContextData(auth.UserContextKey).(auth.User)
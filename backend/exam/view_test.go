@@ -2,6 +2,7 @@ package exam
 
 import (
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -165,6 +166,283 @@ func TestVenueDeleteView(t *testing.T) {
 	}
 }
 
+func TestRoomListView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var venue1 Venue = VenueFactorySaved(Venue{})
+	RoomFactorySaved(Room{Venue: &venue1})
+
+	type roomListViewTestCase struct {
+		user               interface{}
+		venueID            string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []roomListViewTestCase{{
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		expectedStatusCode: http.StatusOK,
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant}),
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		expectedStatusCode: http.StatusForbidden,
+		expectedErrorCode:  errVenueAccessNotAuthorized.Code,
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		venueID:            "bad_venue_id",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errVenueNotFound.Code,
+	}, {
+		user:               "bad_user",
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		expectedStatusCode: http.StatusInternalServerError,
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test RoomListView testcase: %d", i)
+		req := helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["venueID"] = testCase.venueID
+
+		RoomListView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			var errUnmarshalling error
+			errUnmarshalling = json.Unmarshal(req.JSONResponse, &err)
+			assert.Nil(t, errUnmarshalling)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestRoomCreateView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var venue1 Venue = VenueFactorySaved(Venue{})
+
+	type roomCreateViewTestCase struct {
+		user               interface{}
+		venueID            string
+		requestData        string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []roomCreateViewTestCase{{
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		requestData:        `{"name":"Room A","capacity":20}`,
+		expectedStatusCode: http.StatusCreated,
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		requestData:        `{"name":"Room A"}`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  "form_error",
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		requestData:        "bad_format",
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  helios.ErrJSONParseFailed.Code,
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal}),
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		requestData:        `{"name":"Room A","capacity":20}`,
+		expectedStatusCode: http.StatusForbidden,
+		expectedErrorCode:  errVenueAccessNotAuthorized.Code,
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		venueID:            "bad_venue_id",
+		requestData:        `{"name":"Room A","capacity":20}`,
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errVenueNotFound.Code,
+	}, {
+		user:               "bad_user",
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		requestData:        `{"name":"Room A","capacity":20}`,
+		expectedStatusCode: http.StatusInternalServerError,
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test RoomCreateView testcase: %d", i)
+		req := helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["venueID"] = testCase.venueID
+		req.RequestData = testCase.requestData
+
+		RoomCreateView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			var errUnmarshalling error
+			errUnmarshalling = json.Unmarshal(req.JSONResponse, &err)
+			assert.Nil(t, errUnmarshalling)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestRoomUpdateView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var venue1 Venue = VenueFactorySaved(Venue{})
+	var room1 Room = RoomFactorySaved(Room{Venue: &venue1, Capacity: 10})
+	ParticipationFactorySaved(Participation{Venue: &venue1, RoomID: &room1.ID, SeatNumber: 1})
+	ParticipationFactorySaved(Participation{Venue: &venue1, RoomID: &room1.ID, SeatNumber: 2})
+	var room2 Room = RoomFactorySaved(Room{})
+
+	type roomUpdateViewTestCase struct {
+		user               interface{}
+		venueID            string
+		roomID             string
+		requestData        string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []roomUpdateViewTestCase{{
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		roomID:             strconv.Itoa(int(room1.ID)),
+		requestData:        `{"name":"Room B","capacity":20}`,
+		expectedStatusCode: http.StatusOK,
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		roomID:             strconv.Itoa(int(room1.ID)),
+		requestData:        `{"name":"Room B","capacity":1}`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  errRoomCapacityTooSmall.Code,
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		roomID:             strconv.Itoa(int(room2.ID)),
+		requestData:        `{"name":"Room B","capacity":20}`,
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errRoomNotFound.Code,
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		roomID:             strconv.Itoa(int(room1.ID)),
+		requestData:        "bad_format",
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  helios.ErrJSONParseFailed.Code,
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		roomID:             "bad_room_id",
+		requestData:        `{"name":"Room B","capacity":20}`,
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errRoomNotFound.Code,
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		venueID:            "bad_venue_id",
+		roomID:             strconv.Itoa(int(room1.ID)),
+		requestData:        `{"name":"Room B","capacity":20}`,
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errVenueNotFound.Code,
+	}, {
+		user:               "bad_user",
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		roomID:             strconv.Itoa(int(room1.ID)),
+		requestData:        `{"name":"Room B","capacity":20}`,
+		expectedStatusCode: http.StatusInternalServerError,
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test RoomUpdateView testcase: %d", i)
+		req := helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["venueID"] = testCase.venueID
+		req.URLParam["roomID"] = testCase.roomID
+		req.RequestData = testCase.requestData
+
+		RoomUpdateView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			var errUnmarshalling error
+			errUnmarshalling = json.Unmarshal(req.JSONResponse, &err)
+			assert.Nil(t, errUnmarshalling)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestRoomDeleteView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var venue1 Venue = VenueFactorySaved(Venue{})
+	var room1 Room = RoomFactorySaved(Room{Venue: &venue1})
+	var room2 Room = RoomFactorySaved(Room{Venue: &venue1})
+	ParticipationFactorySaved(Participation{Venue: &venue1, RoomID: &room2.ID, SeatNumber: 1})
+
+	type roomDeleteViewTestCase struct {
+		user               interface{}
+		venueID            string
+		roomID             string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []roomDeleteViewTestCase{{
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal}),
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		roomID:             strconv.Itoa(int(room1.ID)),
+		expectedStatusCode: http.StatusForbidden,
+		expectedErrorCode:  errVenueAccessNotAuthorized.Code,
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		venueID:            "bad_venue_id",
+		roomID:             strconv.Itoa(int(room1.ID)),
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errVenueNotFound.Code,
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		roomID:             "bad_room_id",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errRoomNotFound.Code,
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		roomID:             strconv.Itoa(int(room2.ID)),
+		expectedStatusCode: errRoomCantDeletedParticipationExists.StatusCode,
+		expectedErrorCode:  errRoomCantDeletedParticipationExists.Code,
+	}, {
+		user:               "bad_user",
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		roomID:             strconv.Itoa(int(room1.ID)),
+		expectedStatusCode: http.StatusInternalServerError,
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		roomID:             strconv.Itoa(int(room1.ID)),
+		expectedStatusCode: http.StatusOK,
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test RoomDeleteView testcase: %d", i)
+		req := helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["venueID"] = testCase.venueID
+		req.URLParam["roomID"] = testCase.roomID
+
+		RoomDeleteView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			var errUnmarshalling error
+			errUnmarshalling = json.Unmarshal(req.JSONResponse, &err)
+			assert.Nil(t, errUnmarshalling)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
 func TestEventListView(t *testing.T) {
 	helios.App.BeforeTest()
 
@@ -253,24 +531,24 @@ func TestEventCreateView(t *testing.T) {
 	}
 }
 
-func TestParticipationListView(t *testing.T) {
+func TestEventRoleListView(t *testing.T) {
 	helios.App.BeforeTest()
 
 	var event1 Event = EventFactorySaved(Event{})
-	ParticipationFactorySaved(Participation{Event: &event1})
-	ParticipationFactorySaved(Participation{Event: &event1})
-	type questionListTestCase struct {
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	type eventRoleListViewTestCase struct {
 		user               interface{}
 		eventSlug          string
 		expectedStatusCode int
 		expectedErrorCode  string
 	}
-	testCases := []questionListTestCase{{
-		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+	testCases := []eventRoleListViewTestCase{{
+		user:               userAuthor,
 		eventSlug:          event1.Slug,
 		expectedStatusCode: http.StatusOK,
 	}, {
-		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		user:               userAuthor,
 		eventSlug:          "random",
 		expectedStatusCode: http.StatusNotFound,
 		expectedErrorCode:  errEventNotFound.Code,
@@ -281,12 +559,12 @@ func TestParticipationListView(t *testing.T) {
 		expectedErrorCode:  helios.ErrInternalServerError.Code,
 	}}
 	for i, testCase := range testCases {
-		t.Logf("Test ParticipationListView testcase: %d", i)
+		t.Logf("Test EventRoleListView testcase: %d", i)
 		req := helios.NewMockRequest()
 		req.SetContextData(auth.UserContextKey, testCase.user)
 		req.URLParam["eventSlug"] = testCase.eventSlug
 
-		ParticipationListView(&req)
+		EventRoleListView(&req)
 
 		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
 		if testCase.expectedErrorCode != "" {
@@ -297,38 +575,204 @@ func TestParticipationListView(t *testing.T) {
 	}
 }
 
-func TestParticipationCreateView(t *testing.T) {
+func TestEventRoleCreateView(t *testing.T) {
 	helios.App.BeforeTest()
 
-	var participationCountBefore int
-	auth.UserFactorySaved(auth.User{Username: "participant", Role: auth.UserRoleParticipant})
-	var userLocal auth.User = auth.UserFactorySaved(auth.User{Username: "local", Role: auth.UserRoleLocal})
 	var event1 Event = EventFactorySaved(Event{})
-	var venue1 Venue = VenueFactorySaved(Venue{})
-	var venue2 Venue = VenueFactorySaved(Venue{})
-	ParticipationFactorySaved(Participation{EventID: event1.ID, Event: &event1, UserID: userLocal.ID, User: &userLocal})
-	helios.DB.Model(Participation{}).Count(&participationCountBefore)
-
-	type participationCreateTestCase struct {
-		user                       interface{}
-		eventSlug                  string
-		requestData                string
-		expectedParticipationCount int
-		expectedStatusCode         int
-		expectedErrorCode          string
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	auth.UserFactorySaved(auth.User{Username: "grader", Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	type eventRoleCreateViewTestCase struct {
+		user               interface{}
+		eventSlug          string
+		requestData        string
+		expectedStatusCode int
+		expectedErrorCode  string
 	}
-
-	testCases := []participationCreateTestCase{{
-		user:                       userLocal,
-		eventSlug:                  event1.Slug,
-		requestData:                fmt.Sprintf(`{"id":1,"userUsername":"participant","venueId":%d,"key":"abcdefghijklmnopabcdefghijklmnop"}`, venue1.ID),
-		expectedParticipationCount: participationCountBefore + 1,
-		expectedStatusCode:         http.StatusOK,
+	testCases := []eventRoleCreateViewTestCase{{
+		user:               userAuthor,
+		eventSlug:          event1.Slug,
+		requestData:        `{"userUsername":"grader","role":"grader"}`,
+		expectedStatusCode: http.StatusCreated,
 	}, {
-		user:                       auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
-		eventSlug:                  "abcdef",
-		requestData:                fmt.Sprintf(`{"id":1,"userUsername":"participant","venueId":%d,"key":"abcdefghijklmnopabcdefghijklmnop"}`, venue1.ID),
-		expectedParticipationCount: participationCountBefore + 1,
+		user:               userAuthor,
+		eventSlug:          event1.Slug,
+		requestData:        `{"userUsername":"grader","role":"admin"}`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  "form_error",
+	}, {
+		user:               userAuthor,
+		eventSlug:          event1.Slug,
+		requestData:        "bad_format",
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  helios.ErrJSONParseFailed.Code,
+	}, {
+		user:               userAuthor,
+		eventSlug:          "random",
+		requestData:        `{"userUsername":"grader","role":"grader"}`,
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errEventNotFound.Code,
+	}, {
+		user:               "bad_user",
+		eventSlug:          event1.Slug,
+		requestData:        `{"userUsername":"grader","role":"grader"}`,
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedErrorCode:  helios.ErrInternalServerError.Code,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test EventRoleCreateView testcase: %d", i)
+		req := helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["eventSlug"] = testCase.eventSlug
+		req.RequestData = testCase.requestData
+
+		EventRoleCreateView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestEventRoleDeleteView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var event1 Event = EventFactorySaved(Event{})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	var eventRoleReviewer auth.EventRole = EventRoleFactorySaved(auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}), event1, nil, auth.EventRoleReviewer)
+	type eventRoleDeleteViewTestCase struct {
+		user               interface{}
+		eventSlug          string
+		eventRoleID        string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []eventRoleDeleteViewTestCase{{
+		user:               userAuthor,
+		eventSlug:          event1.Slug,
+		eventRoleID:        strconv.Itoa(int(eventRoleReviewer.ID)),
+		expectedStatusCode: http.StatusOK,
+	}, {
+		user:               userAuthor,
+		eventSlug:          event1.Slug,
+		eventRoleID:        strconv.Itoa(int(eventRoleReviewer.ID)),
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errEventRoleNotFound.Code,
+	}, {
+		user:               userAuthor,
+		eventSlug:          event1.Slug,
+		eventRoleID:        "bad_id",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errEventRoleNotFound.Code,
+	}, {
+		user:               "bad_user",
+		eventSlug:          event1.Slug,
+		eventRoleID:        strconv.Itoa(int(eventRoleReviewer.ID)),
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedErrorCode:  helios.ErrInternalServerError.Code,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test EventRoleDeleteView testcase: %d", i)
+		req := helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["eventSlug"] = testCase.eventSlug
+		req.URLParam["eventRoleID"] = testCase.eventRoleID
+
+		EventRoleDeleteView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestParticipationListView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var event1 Event = EventFactorySaved(Event{})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	ParticipationFactorySaved(Participation{Event: &event1})
+	ParticipationFactorySaved(Participation{Event: &event1})
+	type questionListTestCase struct {
+		user               interface{}
+		eventSlug          string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []questionListTestCase{{
+		user:               userAuthor,
+		eventSlug:          event1.Slug,
+		expectedStatusCode: http.StatusOK,
+	}, {
+		user:               userAuthor,
+		eventSlug:          "random",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errEventNotFound.Code,
+	}, {
+		user:               "bad_user",
+		eventSlug:          event1.Slug,
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedErrorCode:  helios.ErrInternalServerError.Code,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test ParticipationListView testcase: %d", i)
+		req := helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["eventSlug"] = testCase.eventSlug
+
+		ParticipationListView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestParticipationCreateView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var participationCountBefore int
+	auth.UserFactorySaved(auth.User{Username: "participant", Role: auth.UserRoleParticipant})
+	var userLocal auth.User = auth.UserFactorySaved(auth.User{Username: "local", Role: auth.UserRoleLocal})
+	var event1 Event = EventFactorySaved(Event{})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	var venue1 Venue = VenueFactorySaved(Venue{})
+	var venue2 Venue = VenueFactorySaved(Venue{})
+	ParticipationFactorySaved(Participation{Event: &event1, User: &userLocal, Venue: &venue1})
+	helios.DB.Model(Participation{}).Count(&participationCountBefore)
+
+	type participationCreateTestCase struct {
+		user                       interface{}
+		eventSlug                  string
+		requestData                string
+		expectedParticipationCount int
+		expectedStatusCode         int
+		expectedErrorCode          string
+	}
+
+	testCases := []participationCreateTestCase{{
+		user:                       userLocal,
+		eventSlug:                  event1.Slug,
+		requestData:                fmt.Sprintf(`{"id":1,"userUsername":"participant","venueId":%d,"key":"abcdefghijklmnopabcdefghijklmnop"}`, venue1.ID),
+		expectedParticipationCount: participationCountBefore + 1,
+		expectedStatusCode:         http.StatusOK,
+	}, {
+		user:                       userAuthor,
+		eventSlug:                  "abcdef",
+		requestData:                fmt.Sprintf(`{"id":1,"userUsername":"participant","venueId":%d,"key":"abcdefghijklmnopabcdefghijklmnop"}`, venue1.ID),
+		expectedParticipationCount: participationCountBefore + 1,
 		expectedStatusCode:         http.StatusNotFound,
 		expectedErrorCode:          errEventNotFound.Code,
 	}, {
@@ -339,20 +783,20 @@ func TestParticipationCreateView(t *testing.T) {
 		expectedStatusCode:         http.StatusForbidden,
 		expectedErrorCode:          errParticipationChangeNotAuthorized.Code,
 	}, {
-		user:                       auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		user:                       userAuthor,
 		eventSlug:                  event1.Slug,
 		requestData:                `bad_format`,
 		expectedParticipationCount: participationCountBefore + 1,
 		expectedStatusCode:         http.StatusBadRequest,
 	}, {
-		user:                       auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		user:                       userAuthor,
 		eventSlug:                  event1.Slug,
 		requestData:                `{}`,
 		expectedParticipationCount: participationCountBefore + 1,
 		expectedStatusCode:         http.StatusBadRequest,
 		expectedErrorCode:          "form_error",
 	}, {
-		user:                       auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		user:                       userAuthor,
 		eventSlug:                  event1.Slug,
 		requestData:                fmt.Sprintf(`{"id":1,"userUsername":"participant","venueId":%d,"key":"abcdefghijklmnopabcdefghijklmnop"}`, venue2.ID),
 		expectedParticipationCount: participationCountBefore + 1,
@@ -390,56 +834,55 @@ func TestParticipationCreateView(t *testing.T) {
 	}
 }
 
-func TestParticipationVerifyView(t *testing.T) {
+func TestSeatingChartView(t *testing.T) {
 	helios.App.BeforeTest()
 
-	var userParticipant1 auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
+	var venue1 Venue = VenueFactorySaved(Venue{})
 	var event1 Event = EventFactorySaved(Event{})
-	var event1User1Key = "event_1_user1"
-	var keyHashedOnce = fmt.Sprintf("%x", sha256.Sum256([]byte(event1User1Key)))
-	var keyHashedTwice = fmt.Sprintf("%x", sha256.Sum256([]byte(keyHashedOnce)))
-	ParticipationFactorySaved(Participation{Event: &event1, User: &userParticipant1, KeyPlain: event1User1Key, KeyHashedTwice: keyHashedTwice})
-	type participationVerifyTestCase struct {
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	var room1 Room = RoomFactorySaved(Room{Venue: &venue1})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, RoomID: &room1.ID, SeatNumber: 1})
+
+	type seatingChartViewTestCase struct {
 		user               interface{}
 		eventSlug          string
-		requestData        string
+		venueID            string
 		expectedStatusCode int
 		expectedErrorCode  string
 	}
-
-	testCases := []participationVerifyTestCase{{
-		user:               userParticipant1,
+	testCases := []seatingChartViewTestCase{{
+		user:               userAuthor,
 		eventSlug:          event1.Slug,
-		requestData:        fmt.Sprintf(`{"key":"%s"}`, keyHashedOnce),
+		venueID:            strconv.Itoa(int(venue1.ID)),
 		expectedStatusCode: http.StatusOK,
 	}, {
-		user:               userParticipant1,
+		user:               *ParticipationFactorySaved(Participation{Event: &event1}).User,
 		eventSlug:          event1.Slug,
-		requestData:        fmt.Sprintf(`{"key":"%s"}`, keyHashedTwice),
-		expectedStatusCode: http.StatusBadRequest,
-		expectedErrorCode:  errParticipationWrongKey.Code,
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		expectedStatusCode: http.StatusForbidden,
+		expectedErrorCode:  errSeatAssignmentNotAuthorized.Code,
 	}, {
-		user:               "bad_user",
+		user:               userAuthor,
 		eventSlug:          event1.Slug,
-		requestData:        fmt.Sprintf(`{"key":"%s"}`, keyHashedOnce),
-		expectedStatusCode: http.StatusInternalServerError,
-		expectedErrorCode:  helios.ErrInternalServerError.Code,
+		venueID:            "bad_venue_id",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errVenueNotFound.Code,
 	}, {
-		user:               userParticipant1,
+		user:               "bad_user",
 		eventSlug:          event1.Slug,
-		requestData:        "bad_format",
-		expectedStatusCode: http.StatusBadRequest,
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		expectedStatusCode: http.StatusInternalServerError,
 	}}
 
 	for i, testCase := range testCases {
-		t.Logf("Test ParticipationVerifyView testcase: %d", i)
-		var req helios.MockRequest
-		req = helios.NewMockRequest()
+		t.Logf("Test SeatingChartView testcase: %d", i)
+		req := helios.NewMockRequest()
 		req.SetContextData(auth.UserContextKey, testCase.user)
 		req.URLParam["eventSlug"] = testCase.eventSlug
-		req.RequestData = testCase.requestData
+		req.URLParam["venueID"] = testCase.venueID
 
-		ParticipationVerifyView(&req)
+		SeatingChartView(&req)
 
 		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
 		if testCase.expectedErrorCode != "" {
@@ -452,54 +895,55 @@ func TestParticipationVerifyView(t *testing.T) {
 	}
 }
 
-func TestParticipationDeleteView(t *testing.T) {
+func TestSeatingChartPrintView(t *testing.T) {
 	helios.App.BeforeTest()
 
-	var userParticipant auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
-	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	var venue1 Venue = VenueFactorySaved(Venue{})
 	var event1 Event = EventFactorySaved(Event{})
-	var participation1 Participation = ParticipationFactorySaved(Participation{User: &userParticipant, Event: &event1})
-	var participation2 Participation = ParticipationFactorySaved(Participation{User: &userLocal, Event: &event1})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	var room1 Room = RoomFactorySaved(Room{Venue: &venue1})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, RoomID: &room1.ID, SeatNumber: 1})
 
-	type questionDeleteTestCase struct {
+	type seatingChartPrintViewTestCase struct {
 		user               interface{}
 		eventSlug          string
-		participationID    string
+		venueID            string
 		expectedStatusCode int
 		expectedErrorCode  string
 	}
-	testCases := []questionDeleteTestCase{{
-		user:               "bad_user",
+	testCases := []seatingChartPrintViewTestCase{{
+		user:               userAuthor,
 		eventSlug:          event1.Slug,
-		participationID:    strconv.Itoa(int(participation1.ID)),
-		expectedStatusCode: http.StatusInternalServerError,
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		expectedStatusCode: http.StatusOK,
 	}, {
-		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
-		eventSlug:          "slug",
-		participationID:    "malformed",
-		expectedStatusCode: http.StatusNotFound,
-		expectedErrorCode:  errParticipationNotFound.Code,
+		user:               *ParticipationFactorySaved(Participation{Event: &event1}).User,
+		eventSlug:          event1.Slug,
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		expectedStatusCode: http.StatusForbidden,
+		expectedErrorCode:  errSeatAssignmentNotAuthorized.Code,
 	}, {
-		user:               userLocal,
+		user:               userAuthor,
 		eventSlug:          event1.Slug,
-		participationID:    strconv.Itoa(int(participation2.ID)),
-		expectedStatusCode: errParticipationChangeNotAuthorized.StatusCode,
-		expectedErrorCode:  errParticipationChangeNotAuthorized.Code,
+		venueID:            "bad_venue_id",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errVenueNotFound.Code,
 	}, {
-		user:               userLocal,
+		user:               "bad_user",
 		eventSlug:          event1.Slug,
-		participationID:    strconv.Itoa(int(participation1.ID)),
-		expectedStatusCode: http.StatusOK,
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		expectedStatusCode: http.StatusInternalServerError,
 	}}
 
 	for i, testCase := range testCases {
-		t.Logf("Test ParticipationDelete testcase: %d", i)
+		t.Logf("Test SeatingChartPrintView testcase: %d", i)
 		req := helios.NewMockRequest()
 		req.SetContextData(auth.UserContextKey, testCase.user)
 		req.URLParam["eventSlug"] = testCase.eventSlug
-		req.URLParam["participationID"] = testCase.participationID
+		req.URLParam["venueID"] = testCase.venueID
 
-		ParticipationDeleteView(&req)
+		SeatingChartPrintView(&req)
 
 		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
 		if testCase.expectedErrorCode != "" {
@@ -508,254 +952,288 @@ func TestParticipationDeleteView(t *testing.T) {
 			errUnmarshalling = json.Unmarshal(req.JSONResponse, &err)
 			assert.Nil(t, errUnmarshalling)
 			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		} else if testCase.expectedStatusCode == http.StatusOK {
+			var chart SeatingChartPrintData
+			assert.Nil(t, json.Unmarshal(req.JSONResponse, &chart))
+			pdf, errDecode := base64.StdEncoding.DecodeString(chart.PDF)
+			assert.Nil(t, errDecode)
+			assert.Equal(t, "%PDF", string(pdf[:4]))
 		}
 	}
 }
 
-func TestParticipationStatusListView(t *testing.T) {
+func TestSeatAssignView(t *testing.T) {
 	helios.App.BeforeTest()
 
-	var user1 auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
-	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	var venue1 Venue = VenueFactorySaved(Venue{})
+	var venue2 Venue = VenueFactorySaved(Venue{})
 	var event1 Event = EventFactorySaved(Event{})
-	var event2 Event = EventFactorySaved(Event{})
-	ParticipationFactorySaved(Participation{Event: &event1, User: &userLocal})
-	ParticipationFactorySaved(Participation{Event: &event1, User: &user1})
-	var session auth.Session = auth.Session{
-		ID:        1,
-		User:      &user1,
-		Token:     "abc",
-		IPAddress: "192.168.0.2",
-	}
-	helios.DB.Create(&session)
-	type participationStatusListViewTestCase struct {
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	RoomFactorySaved(Room{Venue: &venue1})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &auth.User{Role: auth.UserRoleParticipant}})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue2, User: &auth.User{Role: auth.UserRoleParticipant}})
+
+	type seatAssignViewTestCase struct {
 		user               interface{}
-		eventSlug          string
+		venueID            string
 		expectedStatusCode int
 		expectedErrorCode  string
 	}
-	testCases := []participationStatusListViewTestCase{{
-		user:               userLocal,
-		eventSlug:          event1.Slug,
+	testCases := []seatAssignViewTestCase{{
+		user:               userAuthor,
+		venueID:            strconv.Itoa(int(venue1.ID)),
 		expectedStatusCode: http.StatusOK,
 	}, {
-		user:               userLocal,
-		eventSlug:          event2.Slug,
-		expectedStatusCode: errEventNotFound.StatusCode,
-		expectedErrorCode:  errEventNotFound.Code,
+		user:               userAuthor,
+		venueID:            strconv.Itoa(int(venue2.ID)),
+		expectedStatusCode: errVenueCapacityExceeded.StatusCode,
+		expectedErrorCode:  errVenueCapacityExceeded.Code,
 	}, {
-		user:               "bad_user",
-		eventSlug:          event2.Slug,
-		expectedStatusCode: http.StatusInternalServerError,
-		expectedErrorCode:  helios.ErrInternalServerError.Code,
-	}}
-	for i, testCase := range testCases {
-		t.Logf("Test ParticipationStatusListView testcase: %d", i)
+		user:               *ParticipationFactorySaved(Participation{Event: &event1}).User,
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		expectedStatusCode: http.StatusForbidden,
+		expectedErrorCode:  errSeatAssignmentNotAuthorized.Code,
+	}, {
+		user:               userAuthor,
+		venueID:            "bad_venue_id",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errVenueNotFound.Code,
+	}, {
+		user:               "bad_user",
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		expectedStatusCode: http.StatusInternalServerError,
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test SeatAssignView testcase: %d", i)
 		req := helios.NewMockRequest()
 		req.SetContextData(auth.UserContextKey, testCase.user)
-		req.URLParam["eventSlug"] = testCase.eventSlug
+		req.URLParam["eventSlug"] = event1.Slug
+		req.URLParam["venueID"] = testCase.venueID
 
-		ParticipationStatusListView(&req)
+		SeatAssignView(&req)
 
 		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
 		if testCase.expectedErrorCode != "" {
 			var err map[string]interface{}
-			json.Unmarshal(req.JSONResponse, &err)
+			var errUnmarshalling error
+			errUnmarshalling = json.Unmarshal(req.JSONResponse, &err)
+			assert.Nil(t, errUnmarshalling)
 			assert.Equal(t, testCase.expectedErrorCode, err["code"])
 		}
 	}
 }
 
-func TestParticipationStatusDeleteView(t *testing.T) {
+func TestParticipantImportView(t *testing.T) {
 	helios.App.BeforeTest()
 
-	var user1 auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
-	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
-	var event1 Event = EventFactorySaved(Event{})
-	ParticipationFactorySaved(Participation{Event: &event1, User: &userLocal})
-	ParticipationFactorySaved(Participation{Event: &event1, User: &user1})
-	var session auth.Session = auth.Session{
-		ID:        1,
-		User:      &user1,
-		Token:     "abc",
-		IPAddress: "192.168.0.2",
-	}
-	helios.DB.Create(&session)
-	type participationStatusDeleteViewTestCase struct {
+	var venue Venue = VenueFactorySaved(Venue{Name: "Venue A"})
+	var event Event = EventFactorySaved(Event{})
+	var userOrganizer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userOrganizer, event, nil, auth.EventRoleAuthor)
+
+	type participantImportViewTestCase struct {
 		user               interface{}
-		eventSlug          string
-		sessionID          string
+		requestData        string
 		expectedStatusCode int
 		expectedErrorCode  string
 	}
-	testCases := []participationStatusDeleteViewTestCase{{
-		user:               userLocal,
-		eventSlug:          event1.Slug,
-		sessionID:          strconv.Itoa(int(session.ID)),
-		expectedStatusCode: http.StatusOK,
+	testCases := []participantImportViewTestCase{{
+		user:               userOrganizer,
+		requestData:        `{"csv":"User 1,user1,` + venue.Name + `"}`,
+		expectedStatusCode: http.StatusCreated,
 	}, {
-		user:               userLocal,
-		eventSlug:          event1.Slug,
-		sessionID:          strconv.Itoa(int(session.ID)),
-		expectedStatusCode: errParticipationStatusNotFound.StatusCode,
-		expectedErrorCode:  errParticipationStatusNotFound.Code,
+		user:               userOrganizer,
+		requestData:        `{"csv":"User 2,user2,Venue X"}`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  "form_error",
+	}, {
+		user:               userOrganizer,
+		requestData:        `{"csv":""}`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  "form_error",
+	}, {
+		user:               userOrganizer,
+		requestData:        `bad_request_data`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  helios.ErrJSONParseFailed.Code,
 	}, {
 		user:               "bad_user",
-		eventSlug:          event1.Slug,
-		sessionID:          strconv.Itoa(int(session.ID)),
+		requestData:        `{"csv":"User 1,user1,Venue A"}`,
 		expectedStatusCode: http.StatusInternalServerError,
 		expectedErrorCode:  helios.ErrInternalServerError.Code,
-	}, {
-		user:               userLocal,
-		eventSlug:          event1.Slug,
-		sessionID:          "bad_format",
-		expectedStatusCode: errParticipationStatusNotFound.StatusCode,
-		expectedErrorCode:  errParticipationStatusNotFound.Code,
 	}}
 	for i, testCase := range testCases {
-		t.Logf("Test ParticipationStatusDeleteView testcase: %d", i)
-		req := helios.NewMockRequest()
+		t.Logf("Test ParticipantImportView testcase: %d", i)
+		var req helios.MockRequest = helios.NewMockRequest()
 		req.SetContextData(auth.UserContextKey, testCase.user)
-		req.URLParam["eventSlug"] = testCase.eventSlug
-		req.URLParam["sessionID"] = testCase.sessionID
+		req.URLParam["eventSlug"] = event.Slug
+		req.RequestData = testCase.requestData
 
-		ParticipationStatusDeleteView(&req)
+		ParticipantImportView(&req)
 
 		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
 		if testCase.expectedErrorCode != "" {
 			var err map[string]interface{}
 			json.Unmarshal(req.JSONResponse, &err)
 			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		} else {
+			var participantsData []ParticipantImportData
+			json.Unmarshal(req.JSONResponse, &participantsData)
+			if assert.Equal(t, 1, len(participantsData)) {
+				assert.NotEmpty(t, participantsData[0].Password)
+				assert.NotEmpty(t, participantsData[0].Key)
+			}
 		}
 	}
 }
 
-func TestQuestionListView(t *testing.T) {
+func TestCredentialCardView(t *testing.T) {
 	helios.App.BeforeTest()
 
+	var venue1 Venue = VenueFactorySaved(Venue{})
 	var event1 Event = EventFactorySaved(Event{})
-	QuestionFactorySaved(Question{Event: &event1})
-	QuestionFactorySaved(Question{Event: &event1})
-	type questionListTestCase struct {
+	var userOrganizer auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userOrganizer, event1, nil, auth.EventRoleAuthor)
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &auth.User{Role: auth.UserRoleParticipant}})
+
+	type credentialCardViewTestCase struct {
 		user               interface{}
-		eventSlug          string
+		venueID            string
+		requestData        string
 		expectedStatusCode int
 		expectedErrorCode  string
 	}
-	testCases := []questionListTestCase{{
-		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
-		eventSlug:          event1.Slug,
+	testCases := []credentialCardViewTestCase{{
+		user:               userOrganizer,
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		requestData:        `{"layout":"card","resetPassword":true}`,
 		expectedStatusCode: http.StatusOK,
 	}, {
-		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
-		eventSlug:          "random",
+		user:               userOrganizer,
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		requestData:        `{"layout":"poster"}`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  "form_error",
+	}, {
+		user:               *ParticipationFactorySaved(Participation{Event: &event1}).User,
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		requestData:        `{}`,
+		expectedStatusCode: http.StatusForbidden,
+		expectedErrorCode:  errCredentialCardNotAuthorized.Code,
+	}, {
+		user:               userOrganizer,
+		venueID:            "bad_venue_id",
+		requestData:        `{}`,
 		expectedStatusCode: http.StatusNotFound,
-		expectedErrorCode:  errEventNotFound.Code,
+		expectedErrorCode:  errVenueNotFound.Code,
+	}, {
+		user:               userOrganizer,
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		requestData:        `bad_request_data`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  helios.ErrJSONParseFailed.Code,
 	}, {
 		user:               "bad_user",
-		eventSlug:          event1.Slug,
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		requestData:        `{}`,
 		expectedStatusCode: http.StatusInternalServerError,
 		expectedErrorCode:  helios.ErrInternalServerError.Code,
 	}}
 	for i, testCase := range testCases {
-		t.Logf("Test QuestionListView testcase: %d", i)
-		req := helios.NewMockRequest()
+		t.Logf("Test CredentialCardView testcase: %d", i)
+		var req helios.MockRequest = helios.NewMockRequest()
 		req.SetContextData(auth.UserContextKey, testCase.user)
-		req.URLParam["eventSlug"] = testCase.eventSlug
+		req.URLParam["eventSlug"] = event1.Slug
+		req.URLParam["venueID"] = testCase.venueID
+		req.RequestData = testCase.requestData
 
-		QuestionListView(&req)
+		CredentialCardView(&req)
 
 		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
 		if testCase.expectedErrorCode != "" {
 			var err map[string]interface{}
 			json.Unmarshal(req.JSONResponse, &err)
 			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		} else {
+			var credentialCardData CredentialCardData
+			json.Unmarshal(req.JSONResponse, &credentialCardData)
+			assert.NotEmpty(t, credentialCardData.PDF)
+			assert.Equal(t, []string{}, credentialCardData.Skipped)
 		}
 	}
 }
 
-func TestQuestionCreateView(t *testing.T) {
+func TestSeatImportView(t *testing.T) {
 	helios.App.BeforeTest()
 
+	var venue1 Venue = VenueFactorySaved(Venue{})
 	var event1 Event = EventFactorySaved(Event{})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	RoomFactorySaved(Room{Venue: &venue1, Name: "Room A"})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &auth.User{Username: "user_a", Role: auth.UserRoleParticipant}})
 
-	type questionCreateTestCase struct {
-		user                  interface{}
-		eventSlug             string
-		requestData           string
-		expectedQuestionCount int
-		expectedStatusCode    int
-		expectedErrorCode     string
+	type seatImportViewTestCase struct {
+		user               interface{}
+		venueID            string
+		requestData        string
+		expectedStatusCode int
+		expectedErrorCode  string
 	}
-
-	testCases := []questionCreateTestCase{{
-		user:                  auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
-		eventSlug:             event1.Slug,
-		requestData:           `{"id":1,"content":"content1","choices":[],"answer":"abc","eventId":2}`,
-		expectedQuestionCount: 1,
-		expectedStatusCode:    http.StatusCreated,
-	}, {
-		user:                  auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
-		eventSlug:             "abcdef",
-		requestData:           `{"id":1,"content":"content2","choices":[],"answer":"abc","eventId":2}`,
-		expectedQuestionCount: 1,
-		expectedStatusCode:    http.StatusNotFound,
-		expectedErrorCode:     errEventNotFound.Code,
+	testCases := []seatImportViewTestCase{{
+		user:               userAuthor,
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		requestData:        `{"csv":"user_a,Room A,3"}`,
+		expectedStatusCode: http.StatusOK,
 	}, {
-		user:                  auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant}),
-		eventSlug:             event1.Slug,
-		requestData:           `{"id":1,"content":"content3","choices":[],"answer":"abc","eventId":2}`,
-		expectedQuestionCount: 1,
-		expectedStatusCode:    http.StatusForbidden,
-		expectedErrorCode:     errQuestionChangeNotAuthorized.Code,
+		user:               userAuthor,
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		requestData:        `{"csv":"user_b,Room A,3"}`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  "form_error",
 	}, {
-		user:                  auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal}),
-		eventSlug:             event1.Slug,
-		requestData:           `{"id":1,"content":"content3","choices":[],"answer":"abc","eventId":2}`,
-		expectedQuestionCount: 1,
-		expectedStatusCode:    http.StatusForbidden,
-		expectedErrorCode:     errQuestionChangeNotAuthorized.Code,
+		user:               userAuthor,
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		requestData:        `{"csv":""}`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  "form_error",
 	}, {
-		user:                  auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
-		eventSlug:             event1.Slug,
-		requestData:           `bad_format`,
-		expectedQuestionCount: 1,
-		expectedStatusCode:    http.StatusBadRequest,
+		user:               userAuthor,
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		requestData:        "bad_format",
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  helios.ErrJSONParseFailed.Code,
 	}, {
-		user:                  auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
-		eventSlug:             event1.Slug,
-		requestData:           `{"choices":["content5_1","content5_2"],"answer":"abc","eventId":2}`,
-		expectedQuestionCount: 1,
-		expectedStatusCode:    http.StatusBadRequest,
-		expectedErrorCode:     "form_error",
+		user:               *ParticipationFactorySaved(Participation{Event: &event1}).User,
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		requestData:        `{"csv":"user_a,Room A,3"}`,
+		expectedStatusCode: http.StatusForbidden,
+		expectedErrorCode:  errSeatAssignmentNotAuthorized.Code,
 	}, {
-		user:                  auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
-		eventSlug:             event1.Slug,
-		requestData:           `{"content":"content5","choices":["content5_1","content5_2"],"answer":"abc","eventId":2}`,
-		expectedQuestionCount: 2,
-		expectedStatusCode:    http.StatusCreated,
+		user:               userAuthor,
+		venueID:            "bad_venue_id",
+		requestData:        `{"csv":"user_a,Room A,3"}`,
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errVenueNotFound.Code,
 	}, {
-		user:                  "bad_user",
-		eventSlug:             event1.Slug,
-		requestData:           `{"content":"content6","choices":[],"answer":"abc","eventId":2}`,
-		expectedQuestionCount: 2,
-		expectedStatusCode:    http.StatusInternalServerError,
-		expectedErrorCode:     helios.ErrInternalServerError.Code,
+		user:               "bad_user",
+		venueID:            strconv.Itoa(int(venue1.ID)),
+		requestData:        `{"csv":"user_a,Room A,3"}`,
+		expectedStatusCode: http.StatusInternalServerError,
 	}}
 
 	for i, testCase := range testCases {
-		t.Logf("Test QuestionCreate testcase: %d", i)
-		var questionCount int
-		var req helios.MockRequest
-		req = helios.NewMockRequest()
+		t.Logf("Test SeatImportView testcase: %d", i)
+		req := helios.NewMockRequest()
 		req.SetContextData(auth.UserContextKey, testCase.user)
-		req.URLParam["eventSlug"] = testCase.eventSlug
+		req.URLParam["eventSlug"] = event1.Slug
+		req.URLParam["venueID"] = testCase.venueID
 		req.RequestData = testCase.requestData
 
-		QuestionCreateView(&req)
+		SeatImportView(&req)
 
-		helios.DB.Model(Question{}).Count(&questionCount)
-		assert.Equal(t, testCase.expectedQuestionCount, questionCount)
 		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
 		if testCase.expectedErrorCode != "" {
 			var err map[string]interface{}
@@ -767,363 +1245,1253 @@ func TestQuestionCreateView(t *testing.T) {
 	}
 }
 
-func TestQuestionDetailView(t *testing.T) {
+func TestParticipationVerifyView(t *testing.T) {
 	helios.App.BeforeTest()
 
-	var userParticipant auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
+	var userParticipant1 auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
 	var event1 Event = EventFactorySaved(Event{})
-	var question1 Question = QuestionFactorySaved(Question{Event: &event1})
-	QuestionFactorySaved(Question{Event: &event1})
-	var participation1 Participation = ParticipationFactorySaved(Participation{User: &userParticipant, Event: &event1})
-	UserQuestionFactorySaved(UserQuestion{Participation: &participation1, Question: &question1})
-	type questionDetailTestCase struct {
+	var event1User1Key = "event_1_user1"
+	var keyHashedOnce = fmt.Sprintf("%x", sha256.Sum256([]byte(event1User1Key)))
+	var keyHashedTwice = fmt.Sprintf("%x", sha256.Sum256([]byte(keyHashedOnce)))
+	ParticipationFactorySaved(Participation{Event: &event1, User: &userParticipant1, CheckedInAt: time.Now(), KeyPlain: event1User1Key, KeyHashedTwice: keyHashedTwice})
+	type participationVerifyTestCase struct {
 		user               interface{}
 		eventSlug          string
-		questionNumber     string
+		requestData        string
 		expectedStatusCode int
 		expectedErrorCode  string
 	}
-	testCases := []questionDetailTestCase{{
-		user:               userParticipant,
+
+	testCases := []participationVerifyTestCase{{
+		user:               userParticipant1,
 		eventSlug:          event1.Slug,
-		questionNumber:     "1",
+		requestData:        fmt.Sprintf(`{"key":"%s"}`, keyHashedOnce),
 		expectedStatusCode: http.StatusOK,
 	}, {
-		user:               userParticipant,
+		user:               userParticipant1,
 		eventSlug:          event1.Slug,
-		questionNumber:     "2",
-		expectedStatusCode: http.StatusNotFound,
-		expectedErrorCode:  errQuestionNotFound.Code,
+		requestData:        fmt.Sprintf(`{"key":"%s"}`, keyHashedTwice),
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  errParticipationWrongKey.Code,
 	}, {
-		user:               userParticipant,
+		user:               "bad_user",
 		eventSlug:          event1.Slug,
-		questionNumber:     "bad_question_id",
-		expectedStatusCode: http.StatusNotFound,
-		expectedErrorCode:  errQuestionNotFound.Code,
+		requestData:        fmt.Sprintf(`{"key":"%s"}`, keyHashedOnce),
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedErrorCode:  helios.ErrInternalServerError.Code,
 	}, {
-		user:               userParticipant,
+		user:               userParticipant1,
+		eventSlug:          event1.Slug,
+		requestData:        "bad_format",
+		expectedStatusCode: http.StatusBadRequest,
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test ParticipationVerifyView testcase: %d", i)
+		var req helios.MockRequest
+		req = helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["eventSlug"] = testCase.eventSlug
+		req.RequestData = testCase.requestData
+
+		ParticipationVerifyView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			var errUnmarshalling error
+			errUnmarshalling = json.Unmarshal(req.JSONResponse, &err)
+			assert.Nil(t, errUnmarshalling)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestCheckInView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var venue1 Venue = VenueFactorySaved(Venue{})
+	var event1 Event = EventFactorySaved(Event{})
+	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &userLocal})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: &venue1, User: &auth.User{Username: "user_a", Role: auth.UserRoleParticipant}})
+
+	type checkInViewTestCase struct {
+		user               interface{}
+		requestData        string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []checkInViewTestCase{{
+		user:               userLocal,
+		requestData:        `{"userUsername":"user_a","idVerified":true}`,
+		expectedStatusCode: http.StatusOK,
+	}, {
+		user:               userLocal,
+		requestData:        `{"userUsername":"user_a"}`,
+		expectedStatusCode: errParticipationAlreadyCheckedIn.StatusCode,
+		expectedErrorCode:  errParticipationAlreadyCheckedIn.Code,
+	}, {
+		user:               userAuthor,
+		requestData:        `{"userUsername":"user_a"}`,
+		expectedStatusCode: http.StatusForbidden,
+		expectedErrorCode:  errCheckInNotAuthorized.Code,
+	}, {
+		user:               userLocal,
+		requestData:        "bad_format",
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  helios.ErrJSONParseFailed.Code,
+	}, {
+		user:               "bad_user",
+		requestData:        `{"userUsername":"user_a"}`,
+		expectedStatusCode: http.StatusInternalServerError,
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test CheckInView testcase: %d", i)
+		req := helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["eventSlug"] = event1.Slug
+		req.RequestData = testCase.requestData
+
+		CheckInView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			var errUnmarshalling error
+			errUnmarshalling = json.Unmarshal(req.JSONResponse, &err)
+			assert.Nil(t, errUnmarshalling)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestAttendanceNoShowView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var event1 Event = EventFactorySaved(Event{})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	ParticipationFactorySaved(Participation{Event: &event1, User: &userLocal})
+
+	type attendanceNoShowViewTestCase struct {
+		user               interface{}
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []attendanceNoShowViewTestCase{{
+		user:               userLocal,
+		expectedStatusCode: http.StatusOK,
+	}, {
+		user:               userAuthor,
+		expectedStatusCode: http.StatusForbidden,
+		expectedErrorCode:  errCheckInNotAuthorized.Code,
+	}, {
+		user:               "bad_user",
+		expectedStatusCode: http.StatusInternalServerError,
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test AttendanceNoShowView testcase: %d", i)
+		req := helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["eventSlug"] = event1.Slug
+
+		AttendanceNoShowView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			var errUnmarshalling error
+			errUnmarshalling = json.Unmarshal(req.JSONResponse, &err)
+			assert.Nil(t, errUnmarshalling)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestParticipationDeleteView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userParticipant auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
+	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	var event1 Event = EventFactorySaved(Event{})
+	var venue1 Venue = VenueFactorySaved(Venue{})
+	var participation1 Participation = ParticipationFactorySaved(Participation{User: &userParticipant, Event: &event1, Venue: &venue1, CheckedInAt: time.Now()})
+	var participation2 Participation = ParticipationFactorySaved(Participation{User: &userLocal, Event: &event1, Venue: &venue1})
+
+	type questionDeleteTestCase struct {
+		user               interface{}
+		eventSlug          string
+		participationID    string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []questionDeleteTestCase{{
+		user:               "bad_user",
+		eventSlug:          event1.Slug,
+		participationID:    strconv.Itoa(int(participation1.ID)),
+		expectedStatusCode: http.StatusInternalServerError,
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		eventSlug:          "slug",
+		participationID:    "malformed",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errParticipationNotFound.Code,
+	}, {
+		user:               userLocal,
+		eventSlug:          event1.Slug,
+		participationID:    strconv.Itoa(int(participation2.ID)),
+		expectedStatusCode: errParticipationChangeNotAuthorized.StatusCode,
+		expectedErrorCode:  errParticipationChangeNotAuthorized.Code,
+	}, {
+		user:               userLocal,
+		eventSlug:          event1.Slug,
+		participationID:    strconv.Itoa(int(participation1.ID)),
+		expectedStatusCode: http.StatusOK,
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test ParticipationDelete testcase: %d", i)
+		req := helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["eventSlug"] = testCase.eventSlug
+		req.URLParam["participationID"] = testCase.participationID
+
+		ParticipationDeleteView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			var errUnmarshalling error
+			errUnmarshalling = json.Unmarshal(req.JSONResponse, &err)
+			assert.Nil(t, errUnmarshalling)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestParticipationStatusListView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user1 auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
+	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	var event1 Event = EventFactorySaved(Event{})
+	var event2 Event = EventFactorySaved(Event{})
+	ParticipationFactorySaved(Participation{Event: &event1, User: &userLocal})
+	ParticipationFactorySaved(Participation{Event: &event1, User: &user1})
+	var session auth.Session = auth.Session{
+		ID:        1,
+		User:      &user1,
+		Token:     "abc",
+		IPAddress: "192.168.0.2",
+	}
+	helios.DB.Create(&session)
+	type participationStatusListViewTestCase struct {
+		user               interface{}
+		eventSlug          string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []participationStatusListViewTestCase{{
+		user:               userLocal,
+		eventSlug:          event1.Slug,
+		expectedStatusCode: http.StatusOK,
+	}, {
+		user:               userLocal,
+		eventSlug:          event2.Slug,
+		expectedStatusCode: errEventNotFound.StatusCode,
+		expectedErrorCode:  errEventNotFound.Code,
+	}, {
+		user:               "bad_user",
+		eventSlug:          event2.Slug,
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedErrorCode:  helios.ErrInternalServerError.Code,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test ParticipationStatusListView testcase: %d", i)
+		req := helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["eventSlug"] = testCase.eventSlug
+
+		ParticipationStatusListView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestParticipationStatusDeleteView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var user1 auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
+	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	var event1 Event = EventFactorySaved(Event{})
+	var venue1 Venue = VenueFactorySaved(Venue{})
+	ParticipationFactorySaved(Participation{Event: &event1, User: &userLocal, Venue: &venue1})
+	ParticipationFactorySaved(Participation{Event: &event1, User: &user1, Venue: &venue1})
+	var session auth.Session = auth.Session{
+		ID:        1,
+		User:      &user1,
+		Token:     "abc",
+		IPAddress: "192.168.0.2",
+	}
+	helios.DB.Create(&session)
+	type participationStatusDeleteViewTestCase struct {
+		user               interface{}
+		eventSlug          string
+		sessionID          string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []participationStatusDeleteViewTestCase{{
+		user:               userLocal,
+		eventSlug:          event1.Slug,
+		sessionID:          strconv.Itoa(int(session.ID)),
+		expectedStatusCode: http.StatusOK,
+	}, {
+		user:               userLocal,
+		eventSlug:          event1.Slug,
+		sessionID:          strconv.Itoa(int(session.ID)),
+		expectedStatusCode: errParticipationStatusNotFound.StatusCode,
+		expectedErrorCode:  errParticipationStatusNotFound.Code,
+	}, {
+		user:               "bad_user",
+		eventSlug:          event1.Slug,
+		sessionID:          strconv.Itoa(int(session.ID)),
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedErrorCode:  helios.ErrInternalServerError.Code,
+	}, {
+		user:               userLocal,
+		eventSlug:          event1.Slug,
+		sessionID:          "bad_format",
+		expectedStatusCode: errParticipationStatusNotFound.StatusCode,
+		expectedErrorCode:  errParticipationStatusNotFound.Code,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test ParticipationStatusDeleteView testcase: %d", i)
+		req := helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["eventSlug"] = testCase.eventSlug
+		req.URLParam["sessionID"] = testCase.sessionID
+
+		ParticipationStatusDeleteView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestQuestionListView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var event1 Event = EventFactorySaved(Event{})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	QuestionFactorySaved(Question{Event: &event1})
+	QuestionFactorySaved(Question{Event: &event1})
+	type questionListTestCase struct {
+		user               interface{}
+		eventSlug          string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []questionListTestCase{{
+		user:               userAuthor,
+		eventSlug:          event1.Slug,
+		expectedStatusCode: http.StatusOK,
+	}, {
+		user:               userAuthor,
+		eventSlug:          "random",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errEventNotFound.Code,
+	}, {
+		user:               "bad_user",
+		eventSlug:          event1.Slug,
+		expectedStatusCode: http.StatusInternalServerError,
+		expectedErrorCode:  helios.ErrInternalServerError.Code,
+	}}
+	for i, testCase := range testCases {
+		t.Logf("Test QuestionListView testcase: %d", i)
+		req := helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["eventSlug"] = testCase.eventSlug
+
+		QuestionListView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestQuestionCreateView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var event1 Event = EventFactorySaved(Event{})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+
+	type questionCreateTestCase struct {
+		user                  interface{}
+		eventSlug             string
+		requestData           string
+		expectedQuestionCount int
+		expectedStatusCode    int
+		expectedErrorCode     string
+	}
+
+	testCases := []questionCreateTestCase{{
+		user:                  userAuthor,
+		eventSlug:             event1.Slug,
+		requestData:           `{"id":1,"content":"content1","choices":[],"answer":"abc","eventId":2}`,
+		expectedQuestionCount: 1,
+		expectedStatusCode:    http.StatusCreated,
+	}, {
+		user:                  userAuthor,
+		eventSlug:             "abcdef",
+		requestData:           `{"id":1,"content":"content2","choices":[],"answer":"abc","eventId":2}`,
+		expectedQuestionCount: 1,
+		expectedStatusCode:    http.StatusNotFound,
+		expectedErrorCode:     errEventNotFound.Code,
+	}, {
+		user:                  *ParticipationFactorySaved(Participation{Event: &event1}).User,
+		eventSlug:             event1.Slug,
+		requestData:           `{"id":1,"content":"content3","choices":[],"answer":"abc","eventId":2}`,
+		expectedQuestionCount: 1,
+		expectedStatusCode:    http.StatusForbidden,
+		expectedErrorCode:     errQuestionChangeNotAuthorized.Code,
+	}, {
+		user:                  *ParticipationFactorySaved(Participation{Event: &event1, User: &auth.User{Role: auth.UserRoleLocal}}).User,
+		eventSlug:             event1.Slug,
+		requestData:           `{"id":1,"content":"content3","choices":[],"answer":"abc","eventId":2}`,
+		expectedQuestionCount: 1,
+		expectedStatusCode:    http.StatusForbidden,
+		expectedErrorCode:     errQuestionChangeNotAuthorized.Code,
+	}, {
+		user:                  userAuthor,
+		eventSlug:             event1.Slug,
+		requestData:           `bad_format`,
+		expectedQuestionCount: 1,
+		expectedStatusCode:    http.StatusBadRequest,
+	}, {
+		user:                  userAuthor,
+		eventSlug:             event1.Slug,
+		requestData:           `{"choices":["content5_1","content5_2"],"answer":"abc","eventId":2}`,
+		expectedQuestionCount: 1,
+		expectedStatusCode:    http.StatusBadRequest,
+		expectedErrorCode:     "form_error",
+	}, {
+		user:                  userAuthor,
+		eventSlug:             event1.Slug,
+		requestData:           `{"content":"content5","choices":["content5_1","content5_2"],"answer":"abc","eventId":2}`,
+		expectedQuestionCount: 2,
+		expectedStatusCode:    http.StatusCreated,
+	}, {
+		user:                  "bad_user",
+		eventSlug:             event1.Slug,
+		requestData:           `{"content":"content6","choices":[],"answer":"abc","eventId":2}`,
+		expectedQuestionCount: 2,
+		expectedStatusCode:    http.StatusInternalServerError,
+		expectedErrorCode:     helios.ErrInternalServerError.Code,
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test QuestionCreate testcase: %d", i)
+		var questionCount int
+		var req helios.MockRequest
+		req = helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["eventSlug"] = testCase.eventSlug
+		req.RequestData = testCase.requestData
+
+		QuestionCreateView(&req)
+
+		helios.DB.Model(Question{}).Count(&questionCount)
+		assert.Equal(t, testCase.expectedQuestionCount, questionCount)
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			var errUnmarshalling error
+			errUnmarshalling = json.Unmarshal(req.JSONResponse, &err)
+			assert.Nil(t, errUnmarshalling)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestQuestionDetailView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userParticipant auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
+	var event1 Event = EventFactorySaved(Event{})
+	var question1 Question = QuestionFactorySaved(Question{Event: &event1})
+	QuestionFactorySaved(Question{Event: &event1})
+	var participation1 Participation = ParticipationFactorySaved(Participation{User: &userParticipant, Event: &event1, CheckedInAt: time.Now()})
+	UserQuestionFactorySaved(UserQuestion{Participation: &participation1, Question: &question1})
+	type questionDetailTestCase struct {
+		user               interface{}
+		eventSlug          string
+		questionNumber     string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []questionDetailTestCase{{
+		user:               userParticipant,
+		eventSlug:          event1.Slug,
+		questionNumber:     "1",
+		expectedStatusCode: http.StatusOK,
+	}, {
+		user:               userParticipant,
+		eventSlug:          event1.Slug,
+		questionNumber:     "2",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errQuestionNotFound.Code,
+	}, {
+		user:               userParticipant,
+		eventSlug:          event1.Slug,
+		questionNumber:     "bad_question_id",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errQuestionNotFound.Code,
+	}, {
+		user:               userParticipant,
+		eventSlug:          event1.Slug,
+		questionNumber:     "879654",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errQuestionNotFound.Code,
+	}, {
+		user:               userParticipant,
+		eventSlug:          "random",
+		questionNumber:     "malformed",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errQuestionNotFound.Code,
+	}, {
+		user:               "bad_user",
+		eventSlug:          event1.Slug,
+		questionNumber:     "1",
+		expectedStatusCode: http.StatusInternalServerError,
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test QuestionDetailView testcase: %d", i)
+		var req helios.MockRequest
+		req = helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["eventSlug"] = testCase.eventSlug
+		req.URLParam["questionNumber"] = testCase.questionNumber
+
+		QuestionDetailView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestQuestionDeleteView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userParticipant auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
+	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	var event1 Event = EventFactorySaved(Event{})
+	var question1 Question = QuestionFactorySaved(Question{Event: &event1})
+	var participation1 Participation = ParticipationFactorySaved(Participation{User: &userParticipant, Event: &event1, CheckedInAt: time.Now()})
+	ParticipationFactorySaved(Participation{User: &userLocal, Event: &event1})
+	UserQuestionFactorySaved(UserQuestion{Participation: &participation1, Question: &question1})
+
+	type questionDeleteTestCase struct {
+		user               interface{}
+		eventSlug          string
+		questionNumber     string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []questionDeleteTestCase{{
+		user:               userParticipant,
+		eventSlug:          event1.Slug,
+		questionNumber:     "1",
+		expectedStatusCode: http.StatusForbidden,
+		expectedErrorCode:  errQuestionChangeNotAuthorized.Code,
+	}, {
+		user:               userLocal,
+		eventSlug:          event1.Slug,
+		questionNumber:     "1",
+		expectedStatusCode: http.StatusForbidden,
+		expectedErrorCode:  errQuestionChangeNotAuthorized.Code,
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		eventSlug:          event1.Slug,
+		questionNumber:     "bad_question_id",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errQuestionNotFound.Code,
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		eventSlug:          event1.Slug,
+		questionNumber:     "879654",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errQuestionNotFound.Code,
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		eventSlug:          "4567890",
+		questionNumber:     "malformed",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errQuestionNotFound.Code,
+	}, {
+		user:               "bad_user",
+		eventSlug:          event1.Slug,
+		questionNumber:     "1",
+		expectedStatusCode: http.StatusInternalServerError,
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		eventSlug:          event1.Slug,
+		questionNumber:     "1",
+		expectedStatusCode: http.StatusOK,
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		eventSlug:          event1.Slug,
+		questionNumber:     "1",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errQuestionNotFound.Code,
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test QuestionDeleteView testcase: %d", i)
+		req := helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["eventSlug"] = testCase.eventSlug
+		req.URLParam["questionNumber"] = testCase.questionNumber
+
+		QuestionDeleteView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			var errUnmarshalling error
+			errUnmarshalling = json.Unmarshal(req.JSONResponse, &err)
+			assert.Nil(t, errUnmarshalling)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestSubmissionCreateView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var event1 Event = EventFactorySaved(Event{State: EventStateRunning})
+	var participation Participation = ParticipationFactory(Participation{Event: &event1, CheckedInAt: time.Now()})
+	var userQuestion UserQuestion = UserQuestionFactorySaved(UserQuestion{Participation: &participation})
+	var userParticipant auth.User = *userQuestion.Participation.User
+	var question1 Question = *userQuestion.Question
+	type submissionCreateTestCase struct {
+		user               interface{}
+		eventSlug          string
+		questionNumber     string
+		requestData        string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []submissionCreateTestCase{{
+		user:               userParticipant,
+		eventSlug:          event1.Slug,
+		questionNumber:     "1",
+		requestData:        fmt.Sprintf(`{"answer":"%s"}`, strings.Split(question1.Choices, "|")[0]),
+		expectedStatusCode: http.StatusCreated,
+	}, {
+		user:               userParticipant,
+		eventSlug:          "random",
+		questionNumber:     "malformed",
+		requestData:        `{"answer":"answer2"}`,
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errQuestionNotFound.Code,
+	}, {
+		user:               userParticipant,
+		eventSlug:          event1.Slug,
+		questionNumber:     "malformed",
+		requestData:        `{"answer":"answer4"}`,
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errQuestionNotFound.Code,
+	}, {
+		user:               userParticipant,
+		eventSlug:          event1.Slug,
+		questionNumber:     "876789",
+		requestData:        `{"answer":"answer5"}`,
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errQuestionNotFound.Code,
+	}, {
+		user:               userParticipant,
+		eventSlug:          event1.Slug,
+		questionNumber:     "1",
+		requestData:        `malformed`,
+		expectedStatusCode: http.StatusBadRequest,
+	}, {
+		user:               "bad_user",
+		eventSlug:          event1.Slug,
+		questionNumber:     "1",
+		requestData:        `{"answer":"answer7"}`,
+		expectedStatusCode: http.StatusInternalServerError,
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test SubmissionCreateView testcase: %d", i)
+		var req helios.MockRequest = helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["eventSlug"] = testCase.eventSlug
+		req.URLParam["questionNumber"] = testCase.questionNumber
+		req.RequestData = testCase.requestData
+
+		SubmissionCreateView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			var errUnmarshalling error
+			errUnmarshalling = json.Unmarshal(req.JSONResponse, &err)
+			assert.Nil(t, errUnmarshalling)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestGetSynchronizationDataView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	var userParticipant auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
+	var event1 Event = EventFactorySaved(Event{State: EventStatePublished})
+	var event2 Event = EventFactorySaved(Event{})
+	ParticipationFactorySaved(Participation{User: &userLocal, Event: &event1})
+	ParticipationFactorySaved(Participation{User: &userParticipant, Event: &event1, CheckedInAt: time.Now()})
+	QuestionFactorySaved(Question{Event: &event1})
+	type synchronizationDataViewTestCase struct {
+		user               interface{}
+		eventSlug          string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []synchronizationDataViewTestCase{{
+		user:               userLocal,
+		eventSlug:          event1.Slug,
+		expectedStatusCode: http.StatusOK,
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		eventSlug:          event1.Slug,
+		expectedStatusCode: http.StatusForbidden,
+		expectedErrorCode:  errSynchronizationNotAuthorized.Code,
+	}, {
+		user:               userLocal,
+		eventSlug:          event2.Slug,
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errEventNotFound.Code,
+	}, {
+		user:               userLocal,
+		eventSlug:          "random",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errEventNotFound.Code,
+	}, {
+		user:               "bad_user",
+		eventSlug:          event1.Slug,
+		expectedStatusCode: http.StatusInternalServerError,
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test GetSynchronizationDataView testcase: %d", i)
+		var req helios.MockRequest
+		req = helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["eventSlug"] = testCase.eventSlug
+
+		GetSynchronizationDataView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			json.Unmarshal(req.JSONResponse, &err)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestPutSynchronizationDataView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	type putSynchronizationDataViewTestCase struct {
+		user               interface{}
+		requestData        string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []putSynchronizationDataViewTestCase{{
+		user: auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal}),
+		requestData: `{` +
+			`"event":{"id":3,"slug":"math-final-exam","title":"Math Final Exam","description":"desc","startsAt":"2020-08-12T09:30:10+07:00","endsAt":"2020-08-12T11:30:10+07:00"},` +
+			`"venue":{"id":10,"name":"venue1"},` +
+			`"rooms":[{"id":2,"name":"room1","capacity":20}],` +
+			`"questions":[{"id":2,"content":"Question Content","choices":["a","b","c"],"answer":"answer2"},{"id":0,"content":"a","choices":[],"answer":""}],` +
+			`"users":[{"name":"abc","username":"def","role":"admin"}],` +
+			`"usersRoom":{"def":"room1"},` +
+			`"usersSeat":{"def":1}` +
+			`}`,
+		expectedStatusCode: http.StatusCreated,
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal}),
+		requestData:        `{}`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  "form_error",
+	}, {
+		user: auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
+		requestData: `{` +
+			`"event":{"id":3,"slug":"math-final-exam","title":"Math Final Exam","description":"desc","startsAt":"2020-08-12T09:30:10+07:00","endsAt":"2020-08-12T11:30:10+07:00"},` +
+			`"venue":{"id":10,"name":"venue1"},` +
+			`"questions":[{"id":2,"content":"Question Content","choices":["a","b","c"],"answer":"answer2"},{"id":0,"content":"a","choices":[],"answer":""}],` +
+			`"users":[{"name":"abc","username":"def","role":"admin"}]` +
+			`}`,
+		expectedStatusCode: http.StatusForbidden,
+		expectedErrorCode:  errSynchronizationNotAuthorized.Code,
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal}),
+		requestData:        "bad_format",
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  helios.ErrJSONParseFailed.Code,
+	}, {
+		user: "bad_user",
+		requestData: `{` +
+			`"event":{"id":3,"slug":"math-final-exam","title":"Math Final Exam","description":"desc","startsAt":"2020-08-12T09:30:10+07:00","endsAt":"2020-08-12T11:30:10+07:00"},` +
+			`"venue":{"id":10,"name":"venue1"},` +
+			`"questions":[{"id":2,"content":"Question Content","choices":["a","b","c"],"answer":"answer2"},{"id":0,"content":"a","choices":[],"answer":""}],` +
+			`"users":[{"name":"abc","username":"def","role":"admin"}]` +
+			`}`,
+		expectedStatusCode: http.StatusInternalServerError,
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test PutSynchronizationDataView testcase: %d", i)
+		var req helios.MockRequest = helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.RequestData = testCase.requestData
+
+		PutSynchronizationDataView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			var errUnmarshalling error
+			errUnmarshalling = json.Unmarshal(req.JSONResponse, &err)
+			assert.Nil(t, errUnmarshalling)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestGetAttendanceView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var event1 Event = EventFactorySaved(Event{})
+	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	var participationLocal Participation = ParticipationFactorySaved(Participation{Event: &event1, User: &userLocal})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: participationLocal.Venue, User: &auth.User{Role: auth.UserRoleParticipant}})
+
+	type getAttendanceViewTestCase struct {
+		user               interface{}
+		eventSlug          string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []getAttendanceViewTestCase{{
+		user:               userLocal,
+		eventSlug:          event1.Slug,
+		expectedStatusCode: http.StatusOK,
+	}, {
+		user:               userLocal,
+		eventSlug:          "random",
+		expectedStatusCode: http.StatusNotFound,
+		expectedErrorCode:  errEventNotFound.Code,
+	}, {
+		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		eventSlug:          event1.Slug,
+		expectedStatusCode: http.StatusForbidden,
+		expectedErrorCode:  errAttendanceAccessNotAuthorized.Code,
+	}, {
+		user:               "bad_user",
+		eventSlug:          event1.Slug,
+		expectedStatusCode: http.StatusInternalServerError,
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test GetAttendanceView testcase: %d", i)
+		req := helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["eventSlug"] = testCase.eventSlug
+
+		GetAttendanceView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			var errUnmarshalling error
+			errUnmarshalling = json.Unmarshal(req.JSONResponse, &err)
+			assert.Nil(t, errUnmarshalling)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestPutAttendanceView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var event1 Event = EventFactorySaved(Event{})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
+	var participationLocal Participation = ParticipationFactorySaved(Participation{Event: &event1, User: &userLocal})
+	ParticipationFactorySaved(Participation{Event: &event1, Venue: participationLocal.Venue, User: &auth.User{Username: "user_a", Role: auth.UserRoleParticipant}})
+
+	type putAttendanceViewTestCase struct {
+		user               interface{}
+		requestData        string
+		expectedStatusCode int
+		expectedErrorCode  string
+	}
+	testCases := []putAttendanceViewTestCase{{
+		user:               userLocal,
+		requestData:        `{"attendances":[{"userUsername":"user_a","attendance":"late","checkedInAt":"2020-08-12T09:30:10+07:00"}]}`,
+		expectedStatusCode: http.StatusOK,
+	}, {
+		user:               userLocal,
+		requestData:        `{"attendances":[{"userUsername":"user_b","attendance":"no_show"}]}`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  "form_error",
+	}, {
+		user:               userLocal,
+		requestData:        `{"attendances":[{"userUsername":"user_a","attendance":"absent"}]}`,
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  "form_error",
+	}, {
+		user:               userLocal,
+		requestData:        "bad_format",
+		expectedStatusCode: http.StatusBadRequest,
+		expectedErrorCode:  helios.ErrJSONParseFailed.Code,
+	}, {
+		user:               userAuthor,
+		requestData:        `{"attendances":[]}`,
+		expectedStatusCode: http.StatusForbidden,
+		expectedErrorCode:  errAttendanceAccessNotAuthorized.Code,
+	}, {
+		user:               "bad_user",
+		requestData:        `{"attendances":[]}`,
+		expectedStatusCode: http.StatusInternalServerError,
+	}}
+
+	for i, testCase := range testCases {
+		t.Logf("Test PutAttendanceView testcase: %d", i)
+		req := helios.NewMockRequest()
+		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.URLParam["eventSlug"] = event1.Slug
+		req.RequestData = testCase.requestData
+
+		PutAttendanceView(&req)
+
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedErrorCode != "" {
+			var err map[string]interface{}
+			var errUnmarshalling error
+			errUnmarshalling = json.Unmarshal(req.JSONResponse, &err)
+			assert.Nil(t, errUnmarshalling)
+			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		}
+	}
+}
+
+func TestEventDetailView(t *testing.T) {
+	helios.App.BeforeTest()
+
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	var event1 Event = EventFactorySaved(Event{})
+	var event2 Event = EventFactorySaved(Event{})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+
+	type eventDetailViewTestCase struct {
+		user               interface{}
+		eventSlug          string
+		expectedStatusCode int
+	}
+	testCases := []eventDetailViewTestCase{{
+		user:               userAuthor,
 		eventSlug:          event1.Slug,
-		questionNumber:     "879654",
-		expectedStatusCode: http.StatusNotFound,
-		expectedErrorCode:  errQuestionNotFound.Code,
+		expectedStatusCode: http.StatusOK,
 	}, {
-		user:               userParticipant,
-		eventSlug:          "random",
-		questionNumber:     "malformed",
+		user:               userAuthor,
+		eventSlug:          event2.Slug,
 		expectedStatusCode: http.StatusNotFound,
-		expectedErrorCode:  errQuestionNotFound.Code,
 	}, {
 		user:               "bad_user",
 		eventSlug:          event1.Slug,
-		questionNumber:     "1",
 		expectedStatusCode: http.StatusInternalServerError,
 	}}
 
 	for i, testCase := range testCases {
-		t.Logf("Test QuestionDetailView testcase: %d", i)
-		var req helios.MockRequest
-		req = helios.NewMockRequest()
+		t.Logf("Test EventDetailView testcase: %d", i)
+		var req helios.MockRequest = helios.NewMockRequest()
 		req.SetContextData(auth.UserContextKey, testCase.user)
 		req.URLParam["eventSlug"] = testCase.eventSlug
-		req.URLParam["questionNumber"] = testCase.questionNumber
 
-		QuestionDetailView(&req)
+		EventDetailView(&req)
 
-		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
-		if testCase.expectedErrorCode != "" {
-			var err map[string]interface{}
-			json.Unmarshal(req.JSONResponse, &err)
-			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode, req.JSONResponse)
+		if testCase.expectedStatusCode == http.StatusOK {
+			var eventData EventData
+			json.Unmarshal(req.JSONResponse, &eventData)
+			assert.Equal(t, testCase.eventSlug, eventData.Slug)
 		}
 	}
 }
 
-func TestQuestionDeleteView(t *testing.T) {
+func TestEventUpdateView(t *testing.T) {
 	helios.App.BeforeTest()
 
-	var userParticipant auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
-	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
-	var event1 Event = EventFactorySaved(Event{})
-	var question1 Question = QuestionFactorySaved(Question{Event: &event1})
-	var participation1 Participation = ParticipationFactorySaved(Participation{User: &userParticipant, Event: &event1})
-	ParticipationFactorySaved(Participation{User: &userLocal, Event: &event1})
-	UserQuestionFactorySaved(UserQuestion{Participation: &participation1, Question: &question1})
+	var userAdmin auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	var event1 Event = EventFactorySaved(Event{Slug: "event-1"})
+	var eventSynced Event = EventFactorySaved(Event{Slug: "event-synced", State: EventStateSynced})
+	helios.DB.Model(&event1).Update("decrypted_at", time.Time{})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	EventRoleFactorySaved(userAuthor, eventSynced, nil, auth.EventRoleAuthor)
 
-	type questionDeleteTestCase struct {
+	type eventUpdateViewTestCase struct {
 		user               interface{}
 		eventSlug          string
-		questionNumber     string
+		requestData        string
 		expectedStatusCode int
-		expectedErrorCode  string
 	}
-	testCases := []questionDeleteTestCase{{
-		user:               userParticipant,
+	testCases := []eventUpdateViewTestCase{{
+		user:               userAuthor,
 		eventSlug:          event1.Slug,
-		questionNumber:     "1",
-		expectedStatusCode: http.StatusForbidden,
-		expectedErrorCode:  errQuestionChangeNotAuthorized.Code,
+		requestData:        `{"slug":"event-1","title":"New Title","startsAt":"2021-08-12T09:30:10+07:00","endsAt":"2021-08-12T11:30:10+07:00"}`,
+		expectedStatusCode: http.StatusOK,
 	}, {
-		user:               userLocal,
-		eventSlug:          event1.Slug,
-		questionNumber:     "1",
-		expectedStatusCode: http.StatusForbidden,
-		expectedErrorCode:  errQuestionChangeNotAuthorized.Code,
+		user:               userAuthor,
+		eventSlug:          eventSynced.Slug,
+		requestData:        `{"slug":"event-synced","title":"New Title","startsAt":"2021-08-12T09:30:10+07:00","endsAt":"2021-08-12T11:30:10+07:00","force":true}`,
+		expectedStatusCode: http.StatusBadRequest,
 	}, {
-		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
-		eventSlug:          event1.Slug,
-		questionNumber:     "bad_question_id",
-		expectedStatusCode: http.StatusNotFound,
-		expectedErrorCode:  errQuestionNotFound.Code,
+		user:               userAdmin,
+		eventSlug:          eventSynced.Slug,
+		requestData:        `{"slug":"event-synced","title":"New Title","startsAt":"2021-08-12T09:30:10+07:00","endsAt":"2021-08-12T11:30:10+07:00","force":true}`,
+		expectedStatusCode: http.StatusOK,
 	}, {
-		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		user:               userAuthor,
 		eventSlug:          event1.Slug,
-		questionNumber:     "879654",
-		expectedStatusCode: http.StatusNotFound,
-		expectedErrorCode:  errQuestionNotFound.Code,
-	}, {
-		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
-		eventSlug:          "4567890",
-		questionNumber:     "malformed",
-		expectedStatusCode: http.StatusNotFound,
-		expectedErrorCode:  errQuestionNotFound.Code,
+		requestData:        `{"slug":"event-1"}`,
+		expectedStatusCode: http.StatusBadRequest,
 	}, {
 		user:               "bad_user",
 		eventSlug:          event1.Slug,
-		questionNumber:     "1",
+		requestData:        `{"slug":"event-1","title":"New Title","startsAt":"2021-08-12T09:30:10+07:00","endsAt":"2021-08-12T11:30:10+07:00"}`,
 		expectedStatusCode: http.StatusInternalServerError,
 	}, {
-		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
-		eventSlug:          event1.Slug,
-		questionNumber:     "1",
-		expectedStatusCode: http.StatusOK,
-	}, {
-		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		user:               userAuthor,
 		eventSlug:          event1.Slug,
-		questionNumber:     "1",
-		expectedStatusCode: http.StatusNotFound,
-		expectedErrorCode:  errQuestionNotFound.Code,
+		requestData:        "bad_format",
+		expectedStatusCode: http.StatusBadRequest,
 	}}
 
 	for i, testCase := range testCases {
-		t.Logf("Test QuestionDeleteView testcase: %d", i)
-		req := helios.NewMockRequest()
+		t.Logf("Test EventUpdateView testcase: %d", i)
+		var req helios.MockRequest = helios.NewMockRequest()
 		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.RequestData = testCase.requestData
 		req.URLParam["eventSlug"] = testCase.eventSlug
-		req.URLParam["questionNumber"] = testCase.questionNumber
 
-		QuestionDeleteView(&req)
+		EventUpdateView(&req)
 
-		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
-		if testCase.expectedErrorCode != "" {
-			var err map[string]interface{}
-			var errUnmarshalling error
-			errUnmarshalling = json.Unmarshal(req.JSONResponse, &err)
-			assert.Nil(t, errUnmarshalling)
-			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode, req.JSONResponse)
+		if testCase.expectedStatusCode == http.StatusOK {
+			var eventData EventData
+			json.Unmarshal(req.JSONResponse, &eventData)
+			assert.Equal(t, "New Title", eventData.Title)
 		}
 	}
 }
 
-func TestSubmissionCreateView(t *testing.T) {
+func TestEventDeleteView(t *testing.T) {
 	helios.App.BeforeTest()
 
-	var userQuestion UserQuestion = UserQuestionFactorySaved(UserQuestion{})
-	var userParticipant auth.User = *userQuestion.Participation.User
-	var event1 Event = *userQuestion.Question.Event
-	var question1 Question = *userQuestion.Question
-	type submissionCreateTestCase struct {
+	var userAdmin auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	var event1 Event = EventFactorySaved(Event{})
+	var eventSynced Event = EventFactorySaved(Event{State: EventStateSynced})
+	helios.DB.Model(&event1).Update("decrypted_at", time.Time{})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+	EventRoleFactorySaved(userAuthor, eventSynced, nil, auth.EventRoleAuthor)
+
+	type eventDeleteViewTestCase struct {
 		user               interface{}
 		eventSlug          string
-		questionNumber     string
 		requestData        string
 		expectedStatusCode int
-		expectedErrorCode  string
 	}
-	testCases := []submissionCreateTestCase{{
-		user:               userParticipant,
-		eventSlug:          event1.Slug,
-		questionNumber:     "1",
-		requestData:        fmt.Sprintf(`{"answer":"%s"}`, strings.Split(question1.Choices, "|")[0]),
-		expectedStatusCode: http.StatusCreated,
+	testCases := []eventDeleteViewTestCase{{
+		user:               userAuthor,
+		eventSlug:          eventSynced.Slug,
+		requestData:        `{"force":true}`,
+		expectedStatusCode: http.StatusBadRequest,
 	}, {
-		user:               userParticipant,
-		eventSlug:          "random",
-		questionNumber:     "malformed",
-		requestData:        `{"answer":"answer2"}`,
-		expectedStatusCode: http.StatusNotFound,
-		expectedErrorCode:  errQuestionNotFound.Code,
+		user:               userAdmin,
+		eventSlug:          eventSynced.Slug,
+		expectedStatusCode: http.StatusBadRequest,
 	}, {
-		user:               userParticipant,
-		eventSlug:          event1.Slug,
-		questionNumber:     "malformed",
-		requestData:        `{"answer":"answer4"}`,
-		expectedStatusCode: http.StatusNotFound,
-		expectedErrorCode:  errQuestionNotFound.Code,
+		user:               userAdmin,
+		eventSlug:          eventSynced.Slug,
+		requestData:        `{"force":true}`,
+		expectedStatusCode: http.StatusOK,
 	}, {
-		user:               userParticipant,
+		user:               userAuthor,
 		eventSlug:          event1.Slug,
-		questionNumber:     "876789",
-		requestData:        `{"answer":"answer5"}`,
-		expectedStatusCode: http.StatusNotFound,
-		expectedErrorCode:  errQuestionNotFound.Code,
+		expectedStatusCode: http.StatusOK,
 	}, {
-		user:               userParticipant,
+		user:               userAuthor,
 		eventSlug:          event1.Slug,
-		questionNumber:     "1",
-		requestData:        `malformed`,
-		expectedStatusCode: http.StatusBadRequest,
+		expectedStatusCode: http.StatusNotFound,
 	}, {
 		user:               "bad_user",
 		eventSlug:          event1.Slug,
-		questionNumber:     "1",
-		requestData:        `{"answer":"answer7"}`,
 		expectedStatusCode: http.StatusInternalServerError,
 	}}
 
 	for i, testCase := range testCases {
-		t.Logf("Test SubmissionCreateView testcase: %d", i)
+		t.Logf("Test EventDeleteView testcase: %d", i)
 		var req helios.MockRequest = helios.NewMockRequest()
 		req.SetContextData(auth.UserContextKey, testCase.user)
-		req.URLParam["eventSlug"] = testCase.eventSlug
-		req.URLParam["questionNumber"] = testCase.questionNumber
 		req.RequestData = testCase.requestData
+		req.URLParam["eventSlug"] = testCase.eventSlug
 
-		SubmissionCreateView(&req)
+		EventDeleteView(&req)
 
-		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
-		if testCase.expectedErrorCode != "" {
-			var err map[string]interface{}
-			var errUnmarshalling error
-			errUnmarshalling = json.Unmarshal(req.JSONResponse, &err)
-			assert.Nil(t, errUnmarshalling)
-			assert.Equal(t, testCase.expectedErrorCode, err["code"])
-		}
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode, req.JSONResponse)
 	}
 }
 
-func TestGetSynchronizationDataView(t *testing.T) {
+func TestEventCloneView(t *testing.T) {
 	helios.App.BeforeTest()
 
-	var userLocal auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal})
-	var userParticipant auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleParticipant})
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
 	var event1 Event = EventFactorySaved(Event{})
-	var event2 Event = EventFactorySaved(Event{})
-	ParticipationFactorySaved(Participation{User: &userLocal, Event: &event1})
-	ParticipationFactorySaved(Participation{User: &userParticipant, Event: &event1})
-	QuestionFactorySaved(Question{Event: &event1})
-	type synchronizationDataViewTestCase struct {
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+
+	type eventCloneViewTestCase struct {
 		user               interface{}
 		eventSlug          string
+		requestData        string
 		expectedStatusCode int
-		expectedErrorCode  string
 	}
-	testCases := []synchronizationDataViewTestCase{{
-		user:               userLocal,
+	testCases := []eventCloneViewTestCase{{
+		user:               userAuthor,
 		eventSlug:          event1.Slug,
-		expectedStatusCode: http.StatusOK,
+		requestData:        `{"slug":"clone-1","startsAt":"2021-08-12T09:30:10+07:00","endsAt":"2021-08-12T11:30:10+07:00"}`,
+		expectedStatusCode: http.StatusCreated,
 	}, {
-		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleAdmin}),
+		user:               userAuthor,
 		eventSlug:          event1.Slug,
-		expectedStatusCode: http.StatusForbidden,
-		expectedErrorCode:  errSynchronizationNotAuthorized.Code,
-	}, {
-		user:               userLocal,
-		eventSlug:          event2.Slug,
-		expectedStatusCode: http.StatusNotFound,
-		expectedErrorCode:  errEventNotFound.Code,
+		requestData:        `{"slug":"clone-1","startsAt":"2021-08-12T09:30:10+07:00","endsAt":"2021-08-12T11:30:10+07:00"}`,
+		expectedStatusCode: http.StatusBadRequest,
 	}, {
-		user:               userLocal,
-		eventSlug:          "random",
-		expectedStatusCode: http.StatusNotFound,
-		expectedErrorCode:  errEventNotFound.Code,
+		user:               userAuthor,
+		eventSlug:          event1.Slug,
+		requestData:        `{"slug":"clone-2"}`,
+		expectedStatusCode: http.StatusBadRequest,
 	}, {
 		user:               "bad_user",
 		eventSlug:          event1.Slug,
+		requestData:        `{"slug":"clone-3","startsAt":"2021-08-12T09:30:10+07:00","endsAt":"2021-08-12T11:30:10+07:00"}`,
 		expectedStatusCode: http.StatusInternalServerError,
+	}, {
+		user:               userAuthor,
+		eventSlug:          event1.Slug,
+		requestData:        "bad_format",
+		expectedStatusCode: http.StatusBadRequest,
 	}}
 
 	for i, testCase := range testCases {
-		t.Logf("Test GetSynchronizationDataView testcase: %d", i)
-		var req helios.MockRequest
-		req = helios.NewMockRequest()
+		t.Logf("Test EventCloneView testcase: %d", i)
+		var req helios.MockRequest = helios.NewMockRequest()
 		req.SetContextData(auth.UserContextKey, testCase.user)
+		req.RequestData = testCase.requestData
 		req.URLParam["eventSlug"] = testCase.eventSlug
 
-		GetSynchronizationDataView(&req)
+		EventCloneView(&req)
 
-		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
-		if testCase.expectedErrorCode != "" {
-			var err map[string]interface{}
-			json.Unmarshal(req.JSONResponse, &err)
-			assert.Equal(t, testCase.expectedErrorCode, err["code"])
-		}
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode, req.JSONResponse)
 	}
 }
 
-func TestPutSynchronizationDataView(t *testing.T) {
+func TestEventTransitionView(t *testing.T) {
 	helios.App.BeforeTest()
 
-	type putSynchronizationDataViewTestCase struct {
+	var userAuthor auth.User = auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer})
+	var event1 Event = EventFactorySaved(Event{})
+	EventRoleFactorySaved(userAuthor, event1, nil, auth.EventRoleAuthor)
+
+	type eventTransitionViewTestCase struct {
 		user               interface{}
+		eventSlug          string
 		requestData        string
 		expectedStatusCode int
-		expectedErrorCode  string
+		expectedState      string
 	}
-	testCases := []putSynchronizationDataViewTestCase{{
-		user: auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal}),
-		requestData: `{` +
-			`"event":{"id":3,"slug":"math-final-exam","title":"Math Final Exam","description":"desc","startsAt":"2020-08-12T09:30:10+07:00","endsAt":"2020-08-12T11:30:10+07:00"},` +
-			`"venue":{"id":10,"name":"venue1"},` +
-			`"questions":[{"id":2,"content":"Question Content","choices":["a","b","c"],"answer":"answer2"},{"id":0,"content":"a","choices":[],"answer":""}],` +
-			`"users":[{"name":"abc","username":"def","role":"admin"}]` +
-			`}`,
-		expectedStatusCode: http.StatusCreated,
-	}, {
-		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal}),
-		requestData:        `{}`,
+	testCases := []eventTransitionViewTestCase{{
+		user:               userAuthor,
+		eventSlug:          event1.Slug,
+		requestData:        `{"state":"running"}`,
 		expectedStatusCode: http.StatusBadRequest,
-		expectedErrorCode:  "form_error",
 	}, {
-		user: auth.UserFactorySaved(auth.User{Role: auth.UserRoleOrganizer}),
-		requestData: `{` +
-			`"event":{"id":3,"slug":"math-final-exam","title":"Math Final Exam","description":"desc","startsAt":"2020-08-12T09:30:10+07:00","endsAt":"2020-08-12T11:30:10+07:00"},` +
-			`"venue":{"id":10,"name":"venue1"},` +
-			`"questions":[{"id":2,"content":"Question Content","choices":["a","b","c"],"answer":"answer2"},{"id":0,"content":"a","choices":[],"answer":""}],` +
-			`"users":[{"name":"abc","username":"def","role":"admin"}]` +
-			`}`,
-		expectedStatusCode: http.StatusForbidden,
-		expectedErrorCode:  errSynchronizationNotAuthorized.Code,
+		user:               userAuthor,
+		eventSlug:          event1.Slug,
+		requestData:        `{"state":"review"}`,
+		expectedStatusCode: http.StatusOK,
+		expectedState:      EventStateReview,
 	}, {
-		user:               auth.UserFactorySaved(auth.User{Role: auth.UserRoleLocal}),
+		user:               "bad_user",
+		eventSlug:          event1.Slug,
+		requestData:        `{"state":"draft"}`,
+		expectedStatusCode: http.StatusInternalServerError,
+	}, {
+		user:               userAuthor,
+		eventSlug:          event1.Slug,
 		requestData:        "bad_format",
 		expectedStatusCode: http.StatusBadRequest,
-		expectedErrorCode:  helios.ErrJSONParseFailed.Code,
-	}, {
-		user: "bad_user",
-		requestData: `{` +
-			`"event":{"id":3,"slug":"math-final-exam","title":"Math Final Exam","description":"desc","startsAt":"2020-08-12T09:30:10+07:00","endsAt":"2020-08-12T11:30:10+07:00"},` +
-			`"venue":{"id":10,"name":"venue1"},` +
-			`"questions":[{"id":2,"content":"Question Content","choices":["a","b","c"],"answer":"answer2"},{"id":0,"content":"a","choices":[],"answer":""}],` +
-			`"users":[{"name":"abc","username":"def","role":"admin"}]` +
-			`}`,
-		expectedStatusCode: http.StatusInternalServerError,
 	}}
 
 	for i, testCase := range testCases {
-		t.Logf("Test PutSynchronizationDataView testcase: %d", i)
+		t.Logf("Test EventTransitionView testcase: %d", i)
 		var req helios.MockRequest = helios.NewMockRequest()
 		req.SetContextData(auth.UserContextKey, testCase.user)
 		req.RequestData = testCase.requestData
+		req.URLParam["eventSlug"] = testCase.eventSlug
 
-		PutSynchronizationDataView(&req)
+		EventTransitionView(&req)
 
-		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
-		if testCase.expectedErrorCode != "" {
-			var err map[string]interface{}
-			var errUnmarshalling error
-			errUnmarshalling = json.Unmarshal(req.JSONResponse, &err)
-			assert.Nil(t, errUnmarshalling)
-			assert.Equal(t, testCase.expectedErrorCode, err["code"])
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode, req.JSONResponse)
+		if testCase.expectedState != "" {
+			var eventData EventData
+			json.Unmarshal(req.JSONResponse, &eventData)
+			assert.Equal(t, testCase.expectedState, eventData.State)
 		}
 	}
 }
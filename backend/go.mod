@@ -3,10 +3,13 @@ module github.com/yonasadiel/charon/backend
 go 1.13
 
 require (
+	github.com/go-sql-driver/mysql v1.4.1
 	github.com/gorilla/mux v1.7.4
 	github.com/jinzhu/gorm v1.9.12
 	github.com/joho/godotenv v1.3.0
+	github.com/jung-kurt/gofpdf v1.16.2
 	github.com/mattn/go-sqlite3 v2.0.3+incompatible // indirect
+	github.com/skip2/go-qrcode v0.0.0-20191027152451-9434209cb086
 	github.com/stretchr/testify v1.5.1
 	github.com/yonasadiel/helios v0.0.0-20200417082030-e0b2775e2f43
 	golang.org/x/crypto v0.0.0-20200414173820-0848c9571904
@@ -0,0 +1,70 @@
+// Package health serves the liveness and readiness probes of the servers
+package health
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/yonasadiel/helios"
+
+	"github.com/yonasadiel/charon/backend/migration"
+)
+
+const (
+	statusOK       = "ok"
+	statusNotReady = "not ready"
+)
+
+// Check is a readiness check. Run returns error if the server can't serve
+// the requests.
+type Check struct {
+	Name string
+	Run  func() error
+}
+
+// StatusData is JSON representation of the result of the probe
+type StatusData struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// LiveView responds OK as long as the server is able to handle requests
+func LiveView(req helios.Request) {
+	req.SendJSON(StatusData{Status: statusOK}, http.StatusOK)
+}
+
+// CreateReadyView returns the view that runs all checks, and responds
+// 503 Service Unavailable if any of them fails
+func CreateReadyView(checks []Check) helios.HTTPHandler {
+	return func(req helios.Request) {
+		var statusData StatusData = StatusData{Status: statusOK, Checks: make(map[string]string)}
+		var statusCode int = http.StatusOK
+		for _, check := range checks {
+			if err := check.Run(); err != nil {
+				statusData.Status = statusNotReady
+				statusData.Checks[check.Name] = err.Error()
+				statusCode = http.StatusServiceUnavailable
+			} else {
+				statusData.Checks[check.Name] = statusOK
+			}
+		}
+		req.SendJSON(statusData, statusCode)
+	}
+}
+
+// DatabaseCheck checks that the database is reachable
+func DatabaseCheck() Check {
+	return Check{Name: "database", Run: func() error {
+		if helios.DB == nil {
+			return fmt.Errorf("database is not opened")
+		}
+		return helios.DB.DB().Ping()
+	}}
+}
+
+// MigrationCheck checks that the database is on the schema of the migrations
+func MigrationCheck(migrations []migration.Migration) Check {
+	return Check{Name: "migrations", Run: func() error {
+		return migration.Check(helios.DB, migrations)
+	}}
+}
@@ -0,0 +1,70 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/yonasadiel/helios"
+
+	"github.com/yonasadiel/charon/backend/migration"
+)
+
+func TestLiveView(t *testing.T) {
+	req := helios.NewMockRequest()
+	LiveView(&req)
+	assert.Equal(t, http.StatusOK, req.StatusCode)
+}
+
+func TestReadyView(t *testing.T) {
+	helios.App.BeforeTest()
+	var migrations []migration.Migration = []migration.Migration{
+		migration.Migration{Version: 1, Name: "noop", Up: func(db *gorm.DB) error { return nil }},
+	}
+	var failingCheck Check = Check{Name: "failing", Run: func() error { return fmt.Errorf("check failed") }}
+
+	type readyViewTestCase struct {
+		checks             []Check
+		migrate            bool
+		expectedStatusCode int
+		expectedChecks     map[string]string
+	}
+	testCases := []readyViewTestCase{
+		readyViewTestCase{
+			checks:             []Check{DatabaseCheck()},
+			expectedStatusCode: http.StatusOK,
+			expectedChecks:     map[string]string{"database": statusOK},
+		},
+		readyViewTestCase{
+			checks:             []Check{DatabaseCheck(), MigrationCheck(migrations)},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedChecks:     map[string]string{"database": statusOK, "migrations": "database schema is not up to date, 1 migration(s) pending, run the migrate up command first"},
+		},
+		readyViewTestCase{
+			checks:             []Check{DatabaseCheck(), MigrationCheck(migrations)},
+			migrate:            true,
+			expectedStatusCode: http.StatusOK,
+			expectedChecks:     map[string]string{"database": statusOK, "migrations": statusOK},
+		},
+		readyViewTestCase{
+			checks:             []Check{DatabaseCheck(), failingCheck},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedChecks:     map[string]string{"database": statusOK, "failing": "check failed"},
+		},
+	}
+	for i, testCase := range testCases {
+		t.Logf("Test ReadyView testcase: %d", i)
+		if testCase.migrate {
+			assert.Nil(t, migration.Up(helios.DB, migrations))
+		}
+		req := helios.NewMockRequest()
+		CreateReadyView(testCase.checks)(&req)
+		var statusData StatusData
+		json.Unmarshal(req.JSONResponse, &statusData)
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		assert.Equal(t, testCase.expectedChecks, statusData.Checks)
+	}
+}
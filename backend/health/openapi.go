@@ -0,0 +1,11 @@
+package health
+
+import (
+	"github.com/yonasadiel/charon/backend/openapi"
+)
+
+// Operations describes the probes for the OpenAPI document
+var Operations = openapi.Operations{
+	"GET /healthz": {ID: "Live", Summary: "Check that the server is alive", Response: StatusData{}},
+	"GET /readyz":  {ID: "Ready", Summary: "Check that the server is ready, 503 if any check fails", Response: StatusData{}},
+}
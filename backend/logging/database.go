@@ -0,0 +1,80 @@
+package logging
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/yonasadiel/helios"
+)
+
+// CheckDB logs the error of the database operation made on the request, and
+// returns helios.ErrInternalServerError to be sent instead of pretending
+// success. It returns nil if the operation succeeds or only finds no record,
+// since the callers check the missing record from its zero ID. The function
+// that made the operation is logged to locate the failure.
+func CheckDB(requestID string, db *gorm.DB) helios.Error {
+	return check(requestID, db.Error, "database operation failed")
+}
+
+// CheckError is CheckDB for the error returned by the function that does
+// the database operations, or any other failure of the request
+func CheckError(requestID string, err error) helios.Error {
+	return check(requestID, err, "request failed")
+}
+
+func check(requestID string, err error, msg string) helios.Error {
+	if err == nil || gorm.IsRecordNotFoundError(err) {
+		return nil
+	}
+	// skip check and CheckDB or CheckError
+	var fields Fields = Fields{"error": err.Error(), "caller": caller(3)}
+	if requestID != "" {
+		fields["request_id"] = requestID
+	}
+	Error(msg, fields)
+	return helios.ErrInternalServerError
+}
+
+// caller returns the function and the line of the caller, skip is the
+// number of frames to skip like runtime.Caller
+func caller(skip int) string {
+	pc, file, line, ok := runtime.Caller(skip)
+	if !ok {
+		return "unknown"
+	}
+	var function string = "unknown"
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		// strip the import path, github.com/.../backend/exam.SubmitSubmission
+		// becomes exam.SubmitSubmission
+		function = fn.Name()[strings.LastIndex(fn.Name(), "/")+1:]
+	}
+	return fmt.Sprintf("%s (%s:%d)", function, filepath.Base(file), line)
+}
+
+// GormLogger writes the SQL statements of gorm as debug logs. It is used
+// with the gorm detailed log mode on debug level, otherwise gorm logs
+// nothing and the errors are logged by CheckDB.
+type GormLogger struct{}
+
+// Print implements the logger of gorm
+func (GormLogger) Print(values ...interface{}) {
+	if len(values) < 2 {
+		return
+	}
+	var fields Fields = Fields{"source": values[1]}
+	if values[0] == "sql" && len(values) >= 6 {
+		if duration, ok := values[2].(time.Duration); ok {
+			fields["duration_ms"] = float64(duration) / float64(time.Millisecond)
+		}
+		fields["sql"] = values[3]
+		fields["rows"] = values[5]
+		Debug("sql", fields)
+		return
+	}
+	fields["message"] = fmt.Sprint(values[2:]...)
+	Debug("gorm", fields)
+}
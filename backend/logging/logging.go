@@ -0,0 +1,105 @@
+// Package logging writes structured logs of the servers, one JSON object per
+// line, so they can be collected and queried by the log aggregator.
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// LevelDebug logs every SQL statement in addition to the info logs
+	LevelDebug = "debug"
+	// LevelInfo logs the requests and the lifecycle of the server
+	LevelInfo = "info"
+	// LevelWarn logs the unexpected conditions that are handled
+	LevelWarn = "warn"
+	// LevelError logs the failures only
+	LevelError = "error"
+)
+
+// levelRanks orders the levels from the most verbose
+var levelRanks = map[string]int{LevelDebug: 0, LevelInfo: 1, LevelWarn: 2, LevelError: 3}
+
+// Fields are the attributes of a log entry
+type Fields map[string]interface{}
+
+var logger = struct {
+	mutex    sync.Mutex
+	writer   io.Writer
+	minLevel int
+}{writer: os.Stderr, minLevel: levelRanks[LevelInfo]}
+
+// SetOutput sets the writer of the logs, os.Stderr by default
+func SetOutput(writer io.Writer) {
+	logger.mutex.Lock()
+	logger.writer = writer
+	logger.mutex.Unlock()
+}
+
+// SetLevel sets the minimum level of the logs that are written
+func SetLevel(level string) error {
+	rank, ok := levelRanks[level]
+	if !ok {
+		return fmt.Errorf("unknown log level %q", level)
+	}
+	logger.mutex.Lock()
+	logger.minLevel = rank
+	logger.mutex.Unlock()
+	return nil
+}
+
+// IsEnabled returns true if the logs of the level are written
+func IsEnabled(level string) bool {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	return levelRanks[level] >= logger.minLevel
+}
+
+// Debug writes the log entry on debug level
+func Debug(msg string, fields Fields) {
+	write(LevelDebug, msg, fields)
+}
+
+// Info writes the log entry on info level
+func Info(msg string, fields Fields) {
+	write(LevelInfo, msg, fields)
+}
+
+// Warn writes the log entry on warn level
+func Warn(msg string, fields Fields) {
+	write(LevelWarn, msg, fields)
+}
+
+// Error writes the log entry on error level
+func Error(msg string, fields Fields) {
+	write(LevelError, msg, fields)
+}
+
+func write(level string, msg string, fields Fields) {
+	if !IsEnabled(level) {
+		return
+	}
+	var entry map[string]interface{} = make(map[string]interface{}, len(fields)+3)
+	for key, value := range fields {
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+		entry[key] = value
+	}
+	entry["time"] = time.Now().UTC().Format(time.RFC3339Nano)
+	entry["level"] = level
+	entry["msg"] = msg
+	line, err := json.Marshal(entry)
+	if err != nil {
+		line, _ = json.Marshal(map[string]interface{}{"time": entry["time"], "level": level, "msg": msg, "error": err.Error()})
+	}
+
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	logger.writer.Write(append(line, '\n'))
+}
@@ -0,0 +1,154 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/yonasadiel/helios"
+)
+
+// captureLogs writes the logs to the returned buffer until resetLogs is called
+func captureLogs() *bytes.Buffer {
+	var output bytes.Buffer
+	SetOutput(&output)
+	return &output
+}
+
+func resetLogs() {
+	SetOutput(os.Stderr)
+	SetLevel(LevelInfo)
+}
+
+// parseLogs returns the JSON objects of each line of the logs
+func parseLogs(t *testing.T, output *bytes.Buffer) []map[string]interface{} {
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		assert.Nil(t, json.Unmarshal([]byte(line), &entry), line)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestWrite(t *testing.T) {
+	defer resetLogs()
+	var output *bytes.Buffer = captureLogs()
+	assert.NotNil(t, SetLevel("verbose"))
+	assert.Nil(t, SetLevel(LevelWarn))
+	assert.False(t, IsEnabled(LevelInfo))
+	assert.True(t, IsEnabled(LevelError))
+
+	Debug("debug message", nil)
+	Info("info message", nil)
+	Warn("warn message", Fields{"count": 2})
+	Error("error message", Fields{"error": errors.New("disk is full"), "msg": "overwritten"})
+
+	var entries []map[string]interface{} = parseLogs(t, output)
+	assert.Equal(t, 2, len(entries))
+	assert.Equal(t, "warn", entries[0]["level"])
+	assert.Equal(t, "warn message", entries[0]["msg"])
+	assert.Equal(t, float64(2), entries[0]["count"])
+	assert.NotEmpty(t, entries[0]["time"])
+	assert.Equal(t, "error", entries[1]["level"])
+	assert.Equal(t, "error message", entries[1]["msg"])
+	assert.Equal(t, "disk is full", entries[1]["error"])
+}
+
+func TestCheckDB(t *testing.T) {
+	defer resetLogs()
+	type checkDBTestCase struct {
+		err         error
+		requestID   string
+		expectedErr helios.Error
+	}
+	testCases := []checkDBTestCase{
+		{err: nil, requestID: "abc", expectedErr: nil},
+		{err: gorm.ErrRecordNotFound, requestID: "abc", expectedErr: nil},
+		{err: errors.New("database is locked"), requestID: "abc", expectedErr: helios.ErrInternalServerError},
+		{err: errors.New("database is locked"), requestID: "", expectedErr: helios.ErrInternalServerError},
+	}
+	for i, testCase := range testCases {
+		t.Logf("Test CheckDB testcase: %d", i)
+		var output *bytes.Buffer = captureLogs()
+		var errDB helios.Error = CheckDB(testCase.requestID, &gorm.DB{Error: testCase.err})
+		assert.Equal(t, testCase.expectedErr, errDB)
+		var entries []map[string]interface{} = parseLogs(t, output)
+		if testCase.expectedErr == nil {
+			assert.Equal(t, 0, len(entries))
+			continue
+		}
+		assert.Equal(t, 1, len(entries))
+		assert.Equal(t, "error", entries[0]["level"])
+		assert.Equal(t, "database operation failed", entries[0]["msg"])
+		assert.Equal(t, testCase.err.Error(), entries[0]["error"])
+		assert.Contains(t, entries[0]["caller"], "logging.TestCheckDB (logging_test.go:")
+		if testCase.requestID == "" {
+			assert.NotContains(t, entries[0], "request_id")
+		} else {
+			assert.Equal(t, testCase.requestID, entries[0]["request_id"])
+		}
+	}
+
+	var output *bytes.Buffer = captureLogs()
+	assert.Equal(t, helios.ErrInternalServerError, CheckError("abc", errors.New("failed to generate key")))
+	assert.Nil(t, CheckError("abc", nil))
+	var entries []map[string]interface{} = parseLogs(t, output)
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, "request failed", entries[0]["msg"])
+}
+
+func TestMiddleware(t *testing.T) {
+	defer resetLogs()
+	var output *bytes.Buffer = captureLogs()
+	var handledRequestID string
+	router := mux.NewRouter()
+	router.HandleFunc("/exam/{eventSlug}/", func(w http.ResponseWriter, r *http.Request) {
+		handledRequestID = r.Header.Get(RequestIDHeader)
+		SetUserID(handledRequestID, 7)
+		w.WriteHeader(http.StatusCreated)
+	})
+	router.HandleFunc("/fail/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	router.Use(Middleware)
+
+	req := httptest.NewRequest("POST", "/exam/event-1/", nil)
+	req.Header.Set(RequestIDHeader, "forged-id")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Len(t, handledRequestID, 32)
+	assert.NotEqual(t, "forged-id", handledRequestID)
+	assert.Equal(t, handledRequestID, rec.Header().Get(RequestIDHeader))
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/fail/", nil))
+	SetUserID("unknown-request", 8)
+
+	var entries []map[string]interface{} = parseLogs(t, output)
+	assert.Equal(t, 2, len(entries))
+	assert.Equal(t, "info", entries[0]["level"])
+	assert.Equal(t, "request", entries[0]["msg"])
+	assert.Equal(t, handledRequestID, entries[0]["request_id"])
+	assert.Equal(t, "POST", entries[0]["method"])
+	assert.Equal(t, "/exam/{eventSlug}/", entries[0]["route"])
+	assert.Equal(t, "/exam/event-1/", entries[0]["path"])
+	assert.Equal(t, float64(http.StatusCreated), entries[0]["status"])
+	assert.Equal(t, float64(7), entries[0]["user_id"])
+	assert.Contains(t, entries[0], "duration_ms")
+	assert.Equal(t, "error", entries[1]["level"])
+	assert.Equal(t, float64(http.StatusInternalServerError), entries[1]["status"])
+	assert.NotContains(t, entries[1], "user_id")
+	assert.Equal(t, 0, len(inFlightUserIDs.userIDs))
+}
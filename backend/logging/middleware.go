@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// RequestIDHeader is the header of the request ID. It is set on the request
+// for the handlers and on the response for the clients to report.
+const RequestIDHeader = "X-Request-ID"
+
+// inFlightUserIDs are the users of the requests being handled, by request ID.
+// The user is known by the auth middleware inside the handler, while the
+// request is logged by Middleware outside of it.
+var inFlightUserIDs = struct {
+	mutex   sync.Mutex
+	userIDs map[string]uint
+}{userIDs: make(map[string]uint)}
+
+// SetUserID records the user of the request, to be logged when the request
+// is done. It does nothing if the request is not handled by Middleware.
+func SetUserID(requestID string, userID uint) {
+	inFlightUserIDs.mutex.Lock()
+	defer inFlightUserIDs.mutex.Unlock()
+	if _, ok := inFlightUserIDs.userIDs[requestID]; ok {
+		inFlightUserIDs.userIDs[requestID] = userID
+	}
+}
+
+// statusRecorder keeps the status code written to the response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (recorder *statusRecorder) WriteHeader(status int) {
+	recorder.status = status
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+// Middleware is the router middleware that assigns a new ID to the request
+// and logs the request when it is done, with its route, status, duration,
+// and user. The request ID given by the client is replaced, so the ID is
+// unique and can't be used to forge the logs of other requests.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requestID string = newRequestID()
+		r.Header.Set(RequestIDHeader, requestID)
+		w.Header().Set(RequestIDHeader, requestID)
+		inFlightUserIDs.mutex.Lock()
+		inFlightUserIDs.userIDs[requestID] = 0
+		inFlightUserIDs.mutex.Unlock()
+
+		var recorder *statusRecorder = &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		var start time.Time = time.Now()
+		next.ServeHTTP(recorder, r)
+		var duration time.Duration = time.Since(start)
+
+		inFlightUserIDs.mutex.Lock()
+		var userID uint = inFlightUserIDs.userIDs[requestID]
+		delete(inFlightUserIDs.userIDs, requestID)
+		inFlightUserIDs.mutex.Unlock()
+
+		var fields Fields = Fields{
+			"request_id":  requestID,
+			"method":      r.Method,
+			"route":       routeTemplate(r),
+			"path":        r.URL.Path,
+			"status":      recorder.status,
+			"duration_ms": float64(duration) / float64(time.Millisecond),
+		}
+		if userID != 0 {
+			fields["user_id"] = userID
+		}
+		if recorder.status >= http.StatusInternalServerError {
+			Error("request", fields)
+		} else {
+			Info("request", fields)
+		}
+	})
+}
+
+func routeTemplate(r *http.Request) string {
+	if currentRoute := mux.CurrentRoute(r); currentRoute != nil {
+		if template, err := currentRoute.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return "unknown"
+}
+
+func newRequestID() string {
+	var id []byte = make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
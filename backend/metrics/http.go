@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+var httpRequests = NewCounterVec(
+	"charon_http_requests_total",
+	"Number of HTTP requests by route, method, and status code.",
+	"route", "method", "status",
+)
+
+var httpRequestDuration = NewHistogramVec(
+	"charon_http_request_duration_seconds",
+	"Latency of HTTP requests by route and method.",
+	DefaultBuckets,
+	"route", "method",
+)
+
+// statusRecorder keeps the status code written to the response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (recorder *statusRecorder) WriteHeader(status int) {
+	recorder.status = status
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+// RouteMiddleware is the router middleware that records the count and the
+// latency of the requests. The requests are labeled by the path template of
+// the matched route, so the path parameters like event slug don't create
+// a series for every value.
+func RouteMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var route string = "unknown"
+		if currentRoute := mux.CurrentRoute(r); currentRoute != nil {
+			if template, err := currentRoute.GetPathTemplate(); err == nil {
+				route = template
+			}
+		}
+		var recorder *statusRecorder = &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		var start time.Time = time.Now()
+		next.ServeHTTP(recorder, r)
+		httpRequests.Inc(route, r.Method, strconv.Itoa(recorder.status))
+		httpRequestDuration.Observe(time.Since(start).Seconds(), route, r.Method)
+	})
+}
@@ -0,0 +1,275 @@
+// Package metrics keeps the counters and histograms of the server and exposes
+// them in the Prometheus text format. It only implements the small subset of
+// the format that the server needs, so no client library is required.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the upper bounds of the histogram buckets, in seconds,
+// suitable for the latency of HTTP requests
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// labelSeparator joins the label values into the key of a series. It can't
+// appear in valid UTF-8 label values.
+const labelSeparator = "\xff"
+
+// Sample is a value of a gauge collected when the metrics are scraped
+type Sample struct {
+	LabelValues []string
+	Value       float64
+}
+
+type metric interface {
+	getName() string
+	write(w io.Writer)
+}
+
+var registry = struct {
+	mutex   sync.Mutex
+	metrics map[string]metric
+}{metrics: make(map[string]metric)}
+
+func register(m metric) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	if _, exists := registry.metrics[m.getName()]; exists {
+		panic(fmt.Sprintf("metric %s is registered twice", m.getName()))
+	}
+	registry.metrics[m.getName()] = m
+}
+
+type desc struct {
+	name       string
+	help       string
+	metricType string
+	labelNames []string
+}
+
+func (d desc) getName() string {
+	return d.name
+}
+
+func (d desc) key(labelValues []string) string {
+	if len(labelValues) != len(d.labelNames) {
+		panic(fmt.Sprintf("metric %s has %d labels, got %d values", d.name, len(d.labelNames), len(labelValues)))
+	}
+	return strings.Join(labelValues, labelSeparator)
+}
+
+func (d desc) writeHeader(w io.Writer) {
+	var help string = strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(d.help)
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", d.name, help, d.name, d.metricType)
+}
+
+// formatLabels formats the labels of the key, with the extra label appended
+// if it is not empty
+func (d desc) formatLabels(key string, extra string) string {
+	var labels []string
+	if len(d.labelNames) > 0 {
+		for i, value := range strings.Split(key, labelSeparator) {
+			var escaped string = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+			labels = append(labels, fmt.Sprintf(`%s="%s"`, d.labelNames[i], escaped))
+		}
+	}
+	if extra != "" {
+		labels = append(labels, extra)
+	}
+	if len(labels) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(labels, ",") + "}"
+}
+
+// CounterVec is a counter partitioned by the label values
+type CounterVec struct {
+	desc
+	mutex  sync.Mutex
+	values map[string]float64
+}
+
+// NewCounterVec creates and registers a counter
+func NewCounterVec(name string, help string, labelNames ...string) *CounterVec {
+	var counter *CounterVec = &CounterVec{
+		desc:   desc{name: name, help: help, metricType: "counter", labelNames: labelNames},
+		values: make(map[string]float64),
+	}
+	register(counter)
+	return counter
+}
+
+// Inc increments the counter of the label values by one
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds the value to the counter of the label values. The value can't be
+// negative.
+func (c *CounterVec) Add(value float64, labelValues ...string) {
+	if value < 0 {
+		panic(fmt.Sprintf("counter %s can't be decreased", c.name))
+	}
+	var key string = c.key(labelValues)
+	c.mutex.Lock()
+	c.values[key] += value
+	c.mutex.Unlock()
+}
+
+func (c *CounterVec) write(w io.Writer) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.writeHeader(w)
+	for _, key := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, c.formatLabels(key, ""), formatValue(c.values[key]))
+	}
+}
+
+type histogram struct {
+	bucketCounts []uint64
+	sum          float64
+	count        uint64
+}
+
+// HistogramVec is a histogram partitioned by the label values
+type HistogramVec struct {
+	desc
+	buckets []float64
+	mutex   sync.Mutex
+	values  map[string]*histogram
+}
+
+// NewHistogramVec creates and registers a histogram with the upper bounds of
+// the buckets sorted ascending
+func NewHistogramVec(name string, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if !sort.Float64sAreSorted(buckets) {
+		panic(fmt.Sprintf("buckets of histogram %s are not sorted", name))
+	}
+	var histogramVec *HistogramVec = &HistogramVec{
+		desc:    desc{name: name, help: help, metricType: "histogram", labelNames: labelNames},
+		buckets: buckets,
+		values:  make(map[string]*histogram),
+	}
+	register(histogramVec)
+	return histogramVec
+}
+
+// Observe adds the value to the histogram of the label values
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	var key string = h.key(labelValues)
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	var values *histogram = h.values[key]
+	if values == nil {
+		values = &histogram{bucketCounts: make([]uint64, len(h.buckets))}
+		h.values[key] = values
+	}
+	for i, upperBound := range h.buckets {
+		if value <= upperBound {
+			values.bucketCounts[i]++
+		}
+	}
+	values.sum += value
+	values.count++
+}
+
+func (h *HistogramVec) write(w io.Writer) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.writeHeader(w)
+	var keys []string = make([]string, 0, len(h.values))
+	for key := range h.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		var values *histogram = h.values[key]
+		for i, upperBound := range h.buckets {
+			var le string = fmt.Sprintf(`le="%s"`, formatValue(upperBound))
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.formatLabels(key, le), values.bucketCounts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.formatLabels(key, `le="+Inf"`), values.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.formatLabels(key, ""), formatValue(values.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.formatLabels(key, ""), values.count)
+	}
+}
+
+// gaugeFunc is a gauge whose samples are collected on every scrape
+type gaugeFunc struct {
+	desc
+	collect func() []Sample
+}
+
+// NewGaugeFunc registers a gauge whose samples are collected by the function
+// every time the metrics are scraped
+func NewGaugeFunc(name string, help string, labelNames []string, collect func() []Sample) {
+	register(&gaugeFunc{
+		desc:    desc{name: name, help: help, metricType: "gauge", labelNames: labelNames},
+		collect: collect,
+	})
+}
+
+func (g *gaugeFunc) write(w io.Writer) {
+	var values map[string]float64 = make(map[string]float64)
+	for _, sample := range g.collect() {
+		values[g.key(sample.LabelValues)] = sample.Value
+	}
+	g.writeHeader(w)
+	for _, key := range sortedKeys(values) {
+		fmt.Fprintf(w, "%s%s %s\n", g.name, g.formatLabels(key, ""), formatValue(values[key]))
+	}
+}
+
+// Write writes all registered metrics in the Prometheus text format, ordered
+// by the metric name
+func Write(w io.Writer) error {
+	registry.mutex.Lock()
+	var metrics []metric = make([]metric, 0, len(registry.metrics))
+	for _, m := range registry.metrics {
+		metrics = append(metrics, m)
+	}
+	registry.mutex.Unlock()
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].getName() < metrics[j].getName() })
+
+	var buffered *bufio.Writer = bufio.NewWriter(w)
+	for _, m := range metrics {
+		m.write(buffered)
+	}
+	return buffered.Flush()
+}
+
+// Handler serves the metrics to the Prometheus scraper
+func Handler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	Write(w)
+}
+
+func sortedKeys(values map[string]float64) []string {
+	var keys []string = make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatValue(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	if math.IsInf(value, -1) {
+		return "-Inf"
+	}
+	if math.IsNaN(value) {
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
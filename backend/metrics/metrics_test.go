@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounterVec(t *testing.T) {
+	var counter *CounterVec = NewCounterVec("test_counter_total", "Counter\\of test.", "event", "result")
+	counter.Inc("event-1", "success")
+	counter.Add(2, "event-1", "success")
+	counter.Inc("event-\"2\"", "failure")
+	assert.Panics(t, func() { counter.Inc("event-1") })
+	assert.Panics(t, func() { counter.Add(-1, "event-1", "success") })
+	assert.Panics(t, func() { NewCounterVec("test_counter_total", "Duplicated counter.") })
+
+	var output bytes.Buffer
+	counter.write(&output)
+	assert.Equal(t, "# HELP test_counter_total Counter\\\\of test.\n"+
+		"# TYPE test_counter_total counter\n"+
+		"test_counter_total{event=\"event-\\\"2\\\"\",result=\"failure\"} 1\n"+
+		"test_counter_total{event=\"event-1\",result=\"success\"} 3\n", output.String())
+}
+
+func TestHistogramVec(t *testing.T) {
+	var histogram *HistogramVec = NewHistogramVec("test_duration_seconds", "Histogram of test.", []float64{0.1, 1}, "route")
+	histogram.Observe(0.05, "/exam/")
+	histogram.Observe(0.5, "/exam/")
+	histogram.Observe(2, "/exam/")
+	assert.Panics(t, func() { NewHistogramVec("test_unsorted_seconds", "Unsorted histogram.", []float64{1, 0.1}) })
+
+	var output bytes.Buffer
+	histogram.write(&output)
+	assert.Equal(t, "# HELP test_duration_seconds Histogram of test.\n"+
+		"# TYPE test_duration_seconds histogram\n"+
+		"test_duration_seconds_bucket{route=\"/exam/\",le=\"0.1\"} 1\n"+
+		"test_duration_seconds_bucket{route=\"/exam/\",le=\"1\"} 2\n"+
+		"test_duration_seconds_bucket{route=\"/exam/\",le=\"+Inf\"} 3\n"+
+		"test_duration_seconds_sum{route=\"/exam/\"} 2.55\n"+
+		"test_duration_seconds_count{route=\"/exam/\"} 3\n", output.String())
+}
+
+func TestGaugeFunc(t *testing.T) {
+	var value float64 = 1
+	NewGaugeFunc("test_gauge", "Gauge of test.", nil, func() []Sample {
+		return []Sample{{Value: value}}
+	})
+	value = 5
+
+	var output bytes.Buffer
+	assert.Nil(t, Write(&output))
+	assert.Contains(t, output.String(), "# TYPE test_gauge gauge\ntest_gauge 5\n")
+}
+
+func TestRouteMiddleware(t *testing.T) {
+	var router *mux.Router = mux.NewRouter()
+	router.HandleFunc("/exam/{eventSlug}/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}).Methods(http.MethodGet)
+	router.HandleFunc("/metrics", Handler).Methods(http.MethodGet)
+	router.Use(RouteMiddleware)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/exam/event-1/", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/exam/event-2/", nil))
+	var response *httptest.ResponseRecorder = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.True(t, strings.HasPrefix(response.Header().Get("Content-Type"), "text/plain; version=0.0.4"))
+	assert.Contains(t, response.Body.String(), "charon_http_requests_total{route=\"/exam/{eventSlug}/\",method=\"GET\",status=\"404\"} 2\n")
+	assert.Contains(t, response.Body.String(), "charon_http_request_duration_seconds_count{route=\"/exam/{eventSlug}/\",method=\"GET\"} 2\n")
+	assert.NotContains(t, response.Body.String(), "/exam/event-1/")
+}
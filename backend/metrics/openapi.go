@@ -0,0 +1,10 @@
+package metrics
+
+import (
+	"github.com/yonasadiel/charon/backend/openapi"
+)
+
+// Operations describes the metrics endpoint for the OpenAPI document
+var Operations = openapi.Operations{
+	"GET /metrics": {ID: "Metrics", Summary: "Get the metrics in Prometheus text format", Response: "", ContentType: "text/plain"},
+}
@@ -0,0 +1,322 @@
+package migration
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Migration is a versioned change of the database schema. Up applies the
+// change and Down reverts it, both are run in a transaction. Once released,
+// a migration must not be changed, the schema is changed by adding a new
+// migration with higher version instead. The first migration of each package
+// creates its tables with CreateTables, so the databases that are created
+// before the migrations are versioned can be adopted by migrate up.
+type Migration struct {
+	// Version orders the migrations, it is the date of the migration
+	// followed by two digits sequence, e.g. 2026101901
+	Version uint
+	Name    string
+	Up      func(db *gorm.DB) error
+	Down    func(db *gorm.DB) error
+}
+
+// SchemaMigration is the record of migration that has been applied
+// to the database
+type SchemaMigration struct {
+	Version   uint `gorm:"primary_key;auto_increment:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+// Status is the state of a migration in the database. Unknown is true
+// if the migration is applied to the database but it is not known by
+// this server, i.e. the database is migrated by a newer server.
+type Status struct {
+	Version   uint
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	Unknown   bool
+}
+
+// Table is a table created by CreateTables. Model is a struct local to the
+// migration instead of the model of the package, so the table created by the
+// migration doesn't change when the model is changed later.
+type Table struct {
+	Name  string
+	Model interface{}
+}
+
+// CreateTables creates the tables with AutoMigrate of their models. The table
+// that already exists is adopted, only its missing columns and indexes are
+// added.
+func CreateTables(db *gorm.DB, tables []Table) error {
+	for _, table := range tables {
+		if err := db.Table(table.Name).AutoMigrate(table.Model).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DropTables drops the tables in the reverse order of their creation
+func DropTables(db *gorm.DB, tables []Table) error {
+	for i := len(tables) - 1; i >= 0; i-- {
+		if err := db.DropTableIfExists(tables[i].Name).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate returns error if the version of a migration is zero or is used by
+// another migration, so the migrations can't be ordered nor recorded
+func Validate(migrations []Migration) error {
+	var isUsed map[uint]bool = make(map[uint]bool)
+	for _, migration := range migrations {
+		if migration.Version == 0 {
+			return fmt.Errorf("migration %q has no version", migration.Name)
+		}
+		if isUsed[migration.Version] {
+			return fmt.Errorf("migration version %d (%s) is used by another migration", migration.Version, migration.Name)
+		}
+		isUsed[migration.Version] = true
+	}
+	return nil
+}
+
+// Collect returns the migrations of all packages sorted by version
+func Collect(migrationLists ...[]Migration) []Migration {
+	var migrations []Migration = make([]Migration, 0)
+	for _, migrationList := range migrationLists {
+		migrations = append(migrations, migrationList...)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations
+}
+
+// GetStatus returns the status of all known migrations, followed by the
+// unknown migrations that are applied to the database. It returns error if the
+// migrations are not valid, see Validate.
+func GetStatus(db *gorm.DB, migrations []Migration) ([]Status, error) {
+	if err := Validate(migrations); err != nil {
+		return nil, err
+	}
+	applied, err := getApplied(db)
+	if err != nil {
+		return nil, err
+	}
+	var statuses []Status = make([]Status, 0)
+	var isKnown map[uint]bool = make(map[uint]bool)
+	for _, migration := range migrations {
+		var status Status = Status{Version: migration.Version, Name: migration.Name}
+		if schemaMigration, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = schemaMigration.AppliedAt
+		}
+		isKnown[migration.Version] = true
+		statuses = append(statuses, status)
+	}
+	var unknownStatuses []Status = make([]Status, 0)
+	for version, schemaMigration := range applied {
+		if !isKnown[version] {
+			unknownStatuses = append(unknownStatuses, Status{
+				Version:   version,
+				Name:      schemaMigration.Name,
+				Applied:   true,
+				AppliedAt: schemaMigration.AppliedAt,
+				Unknown:   true,
+			})
+		}
+	}
+	sort.Slice(unknownStatuses, func(i, j int) bool { return unknownStatuses[i].Version < unknownStatuses[j].Version })
+	return append(statuses, unknownStatuses...), nil
+}
+
+// Check returns error if the database is not on the schema of the
+// migrations, i.e. it has unknown migration applied or it has pending
+// migration. The server must not be run against such database.
+func Check(db *gorm.DB, migrations []Migration) error {
+	statuses, err := GetStatus(db, migrations)
+	if err != nil {
+		return err
+	}
+	var pending int = 0
+	for _, status := range statuses {
+		if status.Unknown {
+			return errUnknownVersion(status)
+		}
+		if !status.Applied {
+			pending++
+		}
+	}
+	if pending > 0 {
+		return fmt.Errorf("database schema is not up to date, %d migration(s) pending, run the migrate up command first", pending)
+	}
+	return nil
+}
+
+// Up applies all pending migrations
+func Up(db *gorm.DB, migrations []Migration) error {
+	if len(migrations) == 0 {
+		return nil
+	}
+	return To(db, migrations, migrations[len(migrations)-1].Version)
+}
+
+// Down reverts the last applied migration
+func Down(db *gorm.DB, migrations []Migration) error {
+	applied, err := getApplied(db)
+	if err != nil {
+		return err
+	}
+	for i := len(migrations) - 1; i >= 0; i-- {
+		if _, ok := applied[migrations[i].Version]; ok {
+			var target uint = 0
+			if i > 0 {
+				target = migrations[i-1].Version
+			}
+			return To(db, migrations, target)
+		}
+	}
+	return nil
+}
+
+// To migrates the database to the version, i.e. applies the pending
+// migrations up to the version and reverts the applied migrations after
+// the version. Version 0 reverts all migrations.
+func To(db *gorm.DB, migrations []Migration, version uint) error {
+	var isKnownVersion bool = version == 0
+	for _, migration := range migrations {
+		isKnownVersion = isKnownVersion || migration.Version == version
+	}
+	if !isKnownVersion {
+		return fmt.Errorf("migration version %d is unknown", version)
+	}
+	statuses, err := GetStatus(db, migrations)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		if status.Unknown {
+			return errUnknownVersion(status)
+		}
+	}
+	applied, err := getApplied(db)
+	if err != nil {
+		return err
+	}
+	for i := len(migrations) - 1; i >= 0; i-- {
+		if _, ok := applied[migrations[i].Version]; ok && migrations[i].Version > version {
+			if err := run(db, migrations[i], false); err != nil {
+				return err
+			}
+		}
+	}
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; !ok && migration.Version <= version {
+			if err := run(db, migration, true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RunCommand runs the migrate subcommand: status, up, down, or to <version>,
+// and writes the result to out
+func RunCommand(db *gorm.DB, migrations []Migration, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate status | up | down | to <version>")
+	}
+	var err error
+	switch args[0] {
+	case "status":
+	case "up":
+		err = Up(db, migrations)
+	case "down":
+		err = Down(db, migrations)
+	case "to":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: migrate to <version>")
+		}
+		version, errParse := strconv.ParseUint(args[1], 10, 32)
+		if errParse != nil {
+			return fmt.Errorf("migration version should be a number, got %q", args[1])
+		}
+		err = To(db, migrations, uint(version))
+	default:
+		return fmt.Errorf("unknown migrate command %q, usage: migrate status | up | down | to <version>", args[0])
+	}
+	if err != nil {
+		return err
+	}
+	statuses, err := GetStatus(db, migrations)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		var state string = "pending"
+		if status.Unknown {
+			state = "unknown"
+		} else if status.Applied {
+			state = "applied " + status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(out, "%d %-40s %s\n", status.Version, status.Name, state)
+	}
+	return nil
+}
+
+// run applies or reverts the migration in a transaction, and records it
+func run(db *gorm.DB, migration Migration, up bool) error {
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	var err error
+	if up {
+		err = migration.Up(tx)
+		if err == nil {
+			err = tx.Create(&SchemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error
+		}
+	} else {
+		err = migration.Down(tx)
+		if err == nil {
+			err = tx.Delete(&SchemaMigration{}, "version = ?", migration.Version).Error
+		}
+	}
+	if err != nil {
+		tx.Rollback()
+		if up {
+			return fmt.Errorf("failed to apply migration %d (%s): %v", migration.Version, migration.Name, err)
+		}
+		return fmt.Errorf("failed to revert migration %d (%s): %v", migration.Version, migration.Name, err)
+	}
+	return tx.Commit().Error
+}
+
+// errUnknownVersion returns the error of unknown migration applied to the database
+func errUnknownVersion(status Status) error {
+	return fmt.Errorf("database schema version %d (%s) is unknown, the database is migrated by a newer server", status.Version, status.Name)
+}
+
+// getApplied returns the applied migrations by version
+func getApplied(db *gorm.DB) (map[uint]SchemaMigration, error) {
+	if err := db.AutoMigrate(&SchemaMigration{}).Error; err != nil {
+		return nil, fmt.Errorf("failed to create schema migrations table: %v", err)
+	}
+	var schemaMigrations []SchemaMigration
+	if err := db.Find(&schemaMigrations).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema migrations: %v", err)
+	}
+	var applied map[uint]SchemaMigration = make(map[uint]SchemaMigration)
+	for _, schemaMigration := range schemaMigrations {
+		applied[schemaMigration.Version] = schemaMigration
+	}
+	return applied, nil
+}
@@ -0,0 +1,169 @@
+package migration
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite" // use sqlite dialect
+	"github.com/stretchr/testify/assert"
+)
+
+type tableA struct {
+	ID uint `gorm:"primary_key"`
+}
+
+type tableB struct {
+	ID uint `gorm:"primary_key"`
+}
+
+var testMigrations = []Migration{
+	{
+		Version: 2,
+		Name:    "create table b",
+		Up:      func(db *gorm.DB) error { return db.CreateTable(&tableB{}).Error },
+		Down:    func(db *gorm.DB) error { return db.DropTable(&tableB{}).Error },
+	},
+	{
+		Version: 1,
+		Name:    "create table a",
+		Up:      func(db *gorm.DB) error { return db.CreateTable(&tableA{}).Error },
+		Down:    func(db *gorm.DB) error { return db.DropTable(&tableA{}).Error },
+	},
+}
+
+func openTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	assert.Nil(t, err)
+	return db
+}
+
+func TestCollect(t *testing.T) {
+	var migrations []Migration = Collect(testMigrations[:1], testMigrations[1:])
+	assert.Equal(t, 2, len(migrations))
+	assert.Equal(t, uint(1), migrations[0].Version)
+	assert.Equal(t, uint(2), migrations[1].Version)
+}
+
+func TestValidate(t *testing.T) {
+	assert.Nil(t, Validate(Collect(testMigrations)))
+	assert.NotNil(t, Validate(Collect(testMigrations, testMigrations[:1])))
+	assert.NotNil(t, Validate([]Migration{{Name: "no version"}}))
+
+	var db *gorm.DB = openTestDB(t)
+	defer db.Close()
+	assert.NotNil(t, Up(db, Collect(testMigrations, testMigrations[:1])))
+	assert.False(t, db.HasTable(&tableA{}))
+}
+
+func TestCreateTables(t *testing.T) {
+	var db *gorm.DB = openTestDB(t)
+	defer db.Close()
+	type tableAWithName struct {
+		ID   uint `gorm:"primary_key"`
+		Name string
+	}
+	var tables []Table = []Table{{Name: "table_as", Model: &tableA{}}, {Name: "table_bs", Model: &tableB{}}}
+
+	assert.Nil(t, CreateTables(db, tables))
+	assert.True(t, db.HasTable("table_as"))
+	assert.True(t, db.HasTable("table_bs"))
+	assert.Nil(t, CreateTables(db, []Table{{Name: "table_as", Model: &tableAWithName{}}}))
+	assert.True(t, db.Dialect().HasColumn("table_as", "name"), "Existing table should be adopted")
+	assert.Nil(t, DropTables(db, tables))
+	assert.False(t, db.HasTable("table_as"))
+	assert.False(t, db.HasTable("table_bs"))
+}
+
+func TestMigrate(t *testing.T) {
+	var db *gorm.DB = openTestDB(t)
+	defer db.Close()
+	var migrations []Migration = Collect(testMigrations)
+
+	assert.NotNil(t, Check(db, migrations))
+	assert.Nil(t, Up(db, migrations))
+	assert.Nil(t, Check(db, migrations))
+	assert.True(t, db.HasTable(&tableA{}))
+	assert.True(t, db.HasTable(&tableB{}))
+	statuses, err := GetStatus(db, migrations)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(statuses))
+	assert.True(t, statuses[0].Applied)
+	assert.True(t, statuses[1].Applied)
+
+	assert.Nil(t, Down(db, migrations))
+	assert.True(t, db.HasTable(&tableA{}))
+	assert.False(t, db.HasTable(&tableB{}))
+	assert.NotNil(t, Check(db, migrations))
+
+	assert.Nil(t, To(db, migrations, 0))
+	assert.False(t, db.HasTable(&tableA{}))
+	assert.Nil(t, Down(db, migrations))
+	assert.NotNil(t, To(db, migrations, 3))
+
+	assert.Nil(t, To(db, migrations, 2))
+	assert.True(t, db.HasTable(&tableB{}))
+
+	// the database is migrated by a newer server
+	assert.Nil(t, db.Create(&SchemaMigration{Version: 3, Name: "create table c"}).Error)
+	statuses, err = GetStatus(db, migrations)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(statuses))
+	assert.True(t, statuses[2].Unknown)
+	assert.NotNil(t, Check(db, migrations))
+	assert.NotNil(t, Up(db, migrations))
+}
+
+func TestMigrateFailed(t *testing.T) {
+	var db *gorm.DB = openTestDB(t)
+	defer db.Close()
+	var migrations []Migration = Collect(testMigrations, []Migration{{
+		Version: 3,
+		Name:    "broken",
+		Up:      func(db *gorm.DB) error { return errors.New("broken") },
+		Down:    func(db *gorm.DB) error { return nil },
+	}})
+
+	assert.NotNil(t, Up(db, migrations))
+	statuses, err := GetStatus(db, migrations)
+	assert.Nil(t, err)
+	assert.True(t, statuses[0].Applied)
+	assert.True(t, statuses[1].Applied)
+	assert.False(t, statuses[2].Applied)
+}
+
+func TestRunCommand(t *testing.T) {
+	type runCommandTestCase struct {
+		args            []string
+		expectedError   bool
+		expectedApplied int
+	}
+	testCases := []runCommandTestCase{
+		runCommandTestCase{args: []string{}, expectedError: true},
+		runCommandTestCase{args: []string{"sideways"}, expectedError: true},
+		runCommandTestCase{args: []string{"to"}, expectedError: true},
+		runCommandTestCase{args: []string{"to", "abc"}, expectedError: true},
+		runCommandTestCase{args: []string{"status"}, expectedApplied: 0},
+		runCommandTestCase{args: []string{"up"}, expectedApplied: 2},
+		runCommandTestCase{args: []string{"down"}, expectedApplied: 1},
+		runCommandTestCase{args: []string{"to", "0"}, expectedApplied: 0},
+		runCommandTestCase{args: []string{"to", "1"}, expectedApplied: 1},
+	}
+
+	var db *gorm.DB = openTestDB(t)
+	defer db.Close()
+	var migrations []Migration = Collect(testMigrations)
+	for i, testCase := range testCases {
+		t.Logf("Test RunCommand testcase: %d", i)
+		var out bytes.Buffer
+		err := RunCommand(db, migrations, testCase.args, &out)
+		if testCase.expectedError {
+			assert.NotNil(t, err)
+		} else {
+			assert.Nil(t, err)
+			assert.Equal(t, testCase.expectedApplied, bytes.Count(out.Bytes(), []byte("applied")))
+			assert.Equal(t, 2, bytes.Count(out.Bytes(), []byte("\n")))
+		}
+	}
+}
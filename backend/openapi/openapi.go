@@ -0,0 +1,250 @@
+// Package openapi generates the OpenAPI 3 document of the API from the routes
+// of the router and the Go types of the request and response data, so the
+// clients written against the API can be checked and generated from it. Each
+// package describes the operations of its views, and the document only
+// contains the routes that are described.
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+)
+
+// Version is the version of OpenAPI specification of the document
+const Version = "3.0.3"
+
+// Path is the path the document is served on
+const Path = "/openapi.json"
+
+// The security schemes that can be accepted by the operations
+const (
+	// SecuritySession is the cookie session of the logged in user
+	SecuritySession = "session"
+	// SecurityAPIToken is the API token of the local server on the
+	// Authorization header ("Bearer <token>")
+	SecurityAPIToken = "apiToken"
+	// SecurityReplicationToken is the shared secret between the primary and
+	// the standby on the Authorization header ("Bearer <token>")
+	SecurityReplicationToken = "replicationToken"
+)
+
+// OK is the response of the views that only send "OK"
+const OK = "OK"
+
+// Operation describes the operation of a route
+type Operation struct {
+	// ID identifies the operation in the generated clients, e.g. "EventList"
+	ID string
+	// Summary is the short description of the operation
+	Summary string
+	// Security is the security schemes of which any is accepted, empty for
+	// the public operation
+	Security []string
+	// Request is a value of the type of request body, nil if there is no
+	// request body
+	Request interface{}
+	// RequestOptional is true if the request body may be omitted
+	RequestOptional bool
+	// StatusCode is the status code of the successful response, zero for
+	// http.StatusOK
+	StatusCode int
+	// Response is a value of the type of successful response body, nil if
+	// the body is empty
+	Response interface{}
+	// ContentType is the media type of the successful response, empty for
+	// JSON
+	ContentType string
+}
+
+// Operations is the operations keyed by the method and the path template of
+// the route, e.g. "GET /exam/{eventSlug}/"
+type Operations map[string]Operation
+
+// Merge returns all of the operations, the latter overrides the former for
+// the same route
+func Merge(operations ...Operations) Operations {
+	return Prefix("", operations...)
+}
+
+// Prefix returns all of the operations with the prefix added to their paths,
+// for the routes of a subrouter, e.g. "/api"
+func Prefix(prefix string, operations ...Operations) Operations {
+	var merged Operations = make(Operations)
+	for _, ops := range operations {
+		for key, operation := range ops {
+			var method, path string = splitKey(key)
+			merged[method+" "+prefix+path] = operation
+		}
+	}
+	return merged
+}
+
+func splitKey(key string) (method string, path string) {
+	var separator int = strings.Index(key, " ")
+	if separator < 0 {
+		return key, ""
+	}
+	return key[:separator], key[separator+1:]
+}
+
+// DocumentOperations describes the route of the document
+var DocumentOperations = Operations{
+	"GET " + Path: {ID: "OpenAPIDocument", Summary: "Get the OpenAPI document of the API", Response: map[string]interface{}{}},
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// Build returns the document of the routes of the router that are described
+// by the operations. The routes that aren't described are left out.
+func Build(router *mux.Router, info Info, operations Operations) *Document {
+	var document *Document = &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			Schemas:         make(map[string]*Schema),
+			SecuritySchemes: make(map[string]SecurityScheme),
+		},
+	}
+	var generator *schemaGenerator = newSchemaGenerator(document.Components.Schemas)
+	document.Components.Schemas[errorSchemaName] = errorSchema
+	document.Components.Schemas[formErrorSchemaName] = formErrorSchema
+
+	router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		pathTemplate, errPath := route.GetPathTemplate()
+		methods, errMethods := route.GetMethods()
+		if errPath != nil || errMethods != nil {
+			// a subrouter or a route without path or method is not an operation
+			return nil
+		}
+		for _, method := range methods {
+			operation, ok := operations[method+" "+pathTemplate]
+			if !ok {
+				continue
+			}
+			if document.Paths[pathTemplate] == nil {
+				document.Paths[pathTemplate] = make(PathItem)
+			}
+			document.Paths[pathTemplate][strings.ToLower(method)] = buildOperation(generator, pathTemplate, operation)
+			for _, security := range operation.Security {
+				document.Components.SecuritySchemes[security] = securitySchemeOf(security)
+			}
+		}
+		return nil
+	})
+	return document
+}
+
+func buildOperation(generator *schemaGenerator, pathTemplate string, operation Operation) *OperationObject {
+	var operationObject *OperationObject = &OperationObject{
+		OperationID: operation.ID,
+		Summary:     operation.Summary,
+		Responses:   make(map[string]*Response),
+	}
+	for _, match := range pathParamPattern.FindAllStringSubmatch(pathTemplate, -1) {
+		var schema *Schema = &Schema{Type: "string"}
+		if strings.HasSuffix(match[1], "ID") || strings.HasSuffix(match[1], "Number") {
+			schema = &Schema{Type: "integer", Minimum: new(float64)}
+		}
+		operationObject.Parameters = append(operationObject.Parameters, Parameter{Name: match[1], In: "path", Required: true, Schema: schema})
+	}
+	for _, security := range operation.Security {
+		operationObject.Security = append(operationObject.Security, map[string][]string{security: []string{}})
+	}
+
+	var statusCode int = operation.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	var response *Response = &Response{Description: http.StatusText(statusCode)}
+	if operation.Response != nil {
+		var contentType string = operation.ContentType
+		if contentType == "" {
+			contentType = contentTypeJSON
+		}
+		response.Content = map[string]MediaType{contentType: MediaType{Schema: generator.schemaOf(reflect.TypeOf(operation.Response))}}
+	}
+	operationObject.Responses[statusCodeKey(statusCode)] = response
+
+	if operation.Request != nil {
+		operationObject.RequestBody = &RequestBody{
+			Required: !operation.RequestOptional,
+			Content:  map[string]MediaType{contentTypeJSON: MediaType{Schema: generator.schemaOf(reflect.TypeOf(operation.Request))}},
+		}
+		operationObject.Responses[statusCodeKey(http.StatusBadRequest)] = &Response{
+			Description: "The request is invalid",
+			Content: map[string]MediaType{contentTypeJSON: MediaType{Schema: &Schema{
+				OneOf: []*Schema{schemaRef(formErrorSchemaName), schemaRef(errorSchemaName)},
+			}}},
+		}
+	}
+	operationObject.Responses["default"] = &Response{
+		Description: "The request fails",
+		Content:     map[string]MediaType{contentTypeJSON: MediaType{Schema: schemaRef(errorSchemaName)}},
+	}
+	return operationObject
+}
+
+func securitySchemeOf(security string) SecurityScheme {
+	switch security {
+	case SecuritySession:
+		// the cookie name of helios session
+		var name string = os.Getenv("SESSION_NAME")
+		if name == "" {
+			name = "session"
+		}
+		return SecurityScheme{Type: "apiKey", In: "cookie", Name: name}
+	default:
+		return SecurityScheme{Type: "http", Scheme: "bearer"}
+	}
+}
+
+// Handler returns the handler that sends the document of the routes of the
+// router described by the operations. The document is built on the first
+// request, after all of the routes are added.
+func Handler(router *mux.Router, info Info, operations Operations) http.HandlerFunc {
+	var once sync.Once
+	var document []byte
+	var err error
+	return func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() {
+			document, err = json.Marshal(Build(router, info, operations))
+		})
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.Write(document)
+	}
+}
+
+// Routes returns the method and the path template of each route of the
+// router, sorted, e.g. "GET /exam/{eventSlug}/". The OPTIONS and HEAD routes
+// are left out, since they only accompany the other routes.
+func Routes(router *mux.Router) []string {
+	var routes []string
+	router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		pathTemplate, errPath := route.GetPathTemplate()
+		methods, errMethods := route.GetMethods()
+		if errPath != nil || errMethods != nil {
+			return nil
+		}
+		for _, method := range methods {
+			if method != http.MethodOptions && method != http.MethodHead {
+				routes = append(routes, method+" "+pathTemplate)
+			}
+		}
+		return nil
+	})
+	sort.Strings(routes)
+	return routes
+}
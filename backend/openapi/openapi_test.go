@@ -0,0 +1,144 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type testTimestamps struct {
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type testNodeData struct {
+	testTimestamps
+	ID       uint              `json:"id"`
+	Name     string            `json:"name,omitempty"`
+	Weight   float64           `json:"weight"`
+	Parent   *testNodeData     `json:"parent"`
+	Children []testNodeData    `json:"children"`
+	Labels   map[string]string `json:"labels"`
+	Content  []byte            `json:"content"`
+	Extra    interface{}       `json:"extra"`
+	Secret   string            `json:"-"`
+	Untagged bool
+	internal int
+}
+
+type testCreateRequest struct {
+	Name string `json:"name"`
+}
+
+func TestPrefix(t *testing.T) {
+	var operations Operations = Merge(
+		Operations{"GET /healthz": {ID: "Live"}, "GET /metrics": {ID: "Metrics"}},
+		Prefix("/api", Operations{"GET /node/": {ID: "NodeList"}}, Operations{"GET /node/": {ID: "NodeListOverridden"}}),
+	)
+	assert.Equal(t, Operations{
+		"GET /healthz":   {ID: "Live"},
+		"GET /metrics":   {ID: "Metrics"},
+		"GET /api/node/": {ID: "NodeListOverridden"},
+	}, operations)
+}
+
+func TestBuild(t *testing.T) {
+	defer os.Setenv("SESSION_NAME", os.Getenv("SESSION_NAME"))
+	os.Setenv("SESSION_NAME", "charon_session")
+	var view http.HandlerFunc = func(w http.ResponseWriter, r *http.Request) {}
+	router := mux.NewRouter()
+	router.HandleFunc("/healthz", view).Methods(http.MethodGet)
+	api := router.PathPrefix("/api").Subrouter()
+	api.HandleFunc("/node/", view).Methods(http.MethodGet)
+	api.HandleFunc("/node/", view).Methods(http.MethodPost)
+	api.HandleFunc("/node/", view).Methods(http.MethodOptions)
+	api.HandleFunc("/node/{nodeID}/", view).Methods(http.MethodDelete)
+	api.HandleFunc("/undocumented/", view).Methods(http.MethodGet)
+
+	assert.Equal(t, []string{"DELETE /api/node/{nodeID}/", "GET /api/node/", "GET /api/undocumented/", "GET /healthz", "POST /api/node/"}, Routes(router))
+
+	var document *Document = Build(router, Info{Title: "Test API", Version: "1.0.0"}, Merge(
+		Operations{"GET /healthz": {ID: "Live", Response: map[string]string{}}},
+		Prefix("/api", Operations{
+			"GET /node/":             {ID: "NodeList", Security: []string{SecuritySession}, Response: []testNodeData{}},
+			"POST /node/":            {ID: "NodeCreate", Security: []string{SecuritySession, SecurityAPIToken}, Request: testCreateRequest{}, StatusCode: http.StatusCreated, Response: testNodeData{}},
+			"DELETE /node/{nodeID}/": {ID: "NodeDelete", Summary: "Delete the node", Security: []string{SecuritySession}, Response: OK},
+			"GET /missing/":          {ID: "Missing"},
+		}),
+	))
+	assert.Equal(t, Version, document.OpenAPI)
+	assert.Equal(t, Info{Title: "Test API", Version: "1.0.0"}, document.Info)
+
+	// only the described routes are in the document
+	assert.Equal(t, 3, len(document.Paths))
+	assert.Equal(t, 2, len(document.Paths["/api/node/"]))
+	assert.NotContains(t, document.Paths, "/api/undocumented/")
+	assert.NotContains(t, document.Paths, "/api/missing/")
+
+	var nodeList *OperationObject = document.Paths["/api/node/"]["get"]
+	assert.Equal(t, "NodeList", nodeList.OperationID)
+	assert.Nil(t, nodeList.RequestBody)
+	assert.Equal(t, []map[string][]string{{SecuritySession: {}}}, nodeList.Security)
+	assert.Equal(t, &Schema{Type: "array", Items: schemaRef("testNodeData")}, nodeList.Responses["200"].Content[contentTypeJSON].Schema)
+	assert.Equal(t, schemaRef(errorSchemaName), nodeList.Responses["default"].Content[contentTypeJSON].Schema)
+	assert.NotContains(t, nodeList.Responses, "400")
+
+	var nodeCreate *OperationObject = document.Paths["/api/node/"]["post"]
+	assert.True(t, nodeCreate.RequestBody.Required)
+	assert.Equal(t, schemaRef("testCreateRequest"), nodeCreate.RequestBody.Content[contentTypeJSON].Schema)
+	assert.Equal(t, schemaRef("testNodeData"), nodeCreate.Responses["201"].Content[contentTypeJSON].Schema)
+	assert.Equal(t, []*Schema{schemaRef(formErrorSchemaName), schemaRef(errorSchemaName)}, nodeCreate.Responses["400"].Content[contentTypeJSON].Schema.OneOf)
+
+	var nodeDelete *OperationObject = document.Paths["/api/node/{nodeID}/"]["delete"]
+	assert.Equal(t, "Delete the node", nodeDelete.Summary)
+	assert.Equal(t, []Parameter{{Name: "nodeID", In: "path", Required: true, Schema: &Schema{Type: "integer", Minimum: new(float64)}}}, nodeDelete.Parameters)
+	assert.Equal(t, &Schema{Type: "string"}, nodeDelete.Responses["200"].Content[contentTypeJSON].Schema)
+
+	assert.Equal(t, map[string]SecurityScheme{
+		SecuritySession:  {Type: "apiKey", In: "cookie", Name: "charon_session"},
+		SecurityAPIToken: {Type: "http", Scheme: "bearer"},
+	}, document.Components.SecuritySchemes)
+
+	var minimum *float64 = new(float64)
+	assert.Equal(t, &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"createdAt": {Type: "string", Format: "date-time"},
+			"id":        {Type: "integer", Minimum: minimum},
+			"name":      {Type: "string"},
+			"weight":    {Type: "number"},
+			"parent":    {AllOf: []*Schema{schemaRef("testNodeData")}, Nullable: true},
+			"children":  {Type: "array", Items: schemaRef("testNodeData")},
+			"labels":    {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+			"content":   {Type: "string", Format: "byte"},
+			"extra":     {},
+			"Untagged":  {Type: "boolean"},
+		},
+		Required: []string{"createdAt", "id", "weight", "parent", "children", "labels", "content", "extra", "Untagged"},
+	}, document.Components.Schemas["testNodeData"])
+	assert.Contains(t, document.Components.Schemas, errorSchemaName)
+	assert.Contains(t, document.Components.Schemas, formErrorSchemaName)
+}
+
+func TestHandler(t *testing.T) {
+	router := mux.NewRouter()
+	var operations Operations = Merge(DocumentOperations, Operations{"GET /healthz": {ID: "Live"}})
+	router.HandleFunc(Path, Handler(router, Info{Title: "Test API", Version: "1.0.0"}, operations)).Methods(http.MethodGet)
+	// the route added after the handler is in the document too
+	router.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {}).Methods(http.MethodGet)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, contentTypeJSON, rec.Header().Get("Content-Type"))
+	var document map[string]interface{}
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &document))
+	assert.Equal(t, Version, document["openapi"])
+	assert.Contains(t, document["paths"], Path)
+	assert.Contains(t, document["paths"], "/healthz")
+}
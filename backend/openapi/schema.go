@@ -0,0 +1,167 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	errorSchemaName     = "Error"
+	formErrorSchemaName = "FormError"
+)
+
+// errorSchema is the body of helios.ErrorAPI
+var errorSchema = &Schema{
+	Type: "object",
+	Properties: map[string]*Schema{
+		"code":    &Schema{Type: "string"},
+		"message": &Schema{Type: "string"},
+	},
+	Required: []string{"code", "message"},
+}
+
+// formErrorSchema is the body of helios.ErrorForm, the message has the
+// errors of each field, which is a list of errors, a list of the errors of
+// each item, or the errors of the nested fields, and the errors that aren't
+// of any field on "_error"
+var formErrorSchema = &Schema{
+	Type: "object",
+	Properties: map[string]*Schema{
+		"code": &Schema{Type: "string"},
+		"message": &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"_error": &Schema{Type: "array", Items: &Schema{Type: "string"}},
+			},
+			AdditionalProperties: &Schema{},
+		},
+	},
+	Required: []string{"code", "message"},
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+var rawMessageType = reflect.TypeOf(json.RawMessage{})
+
+func schemaRef(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// schemaGenerator generates the schemas of Go types like encoding/json
+// marshals them. The named structs are added to the component schemas and
+// referred.
+type schemaGenerator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newSchemaGenerator(schemas map[string]*Schema) *schemaGenerator {
+	return &schemaGenerator{schemas: schemas, names: make(map[reflect.Type]string)}
+}
+
+func (generator *schemaGenerator) schemaOf(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Minimum: new(float64)}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Ptr:
+		var schema *Schema = generator.schemaOf(t.Elem())
+		if schema.Ref != "" {
+			// the siblings of $ref are ignored
+			return &Schema{AllOf: []*Schema{schema}, Nullable: true}
+		}
+		schema.Nullable = true
+		return schema
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// []byte is encoded as base64 string
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: generator.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: generator.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return generator.structSchema(t)
+		}
+		return schemaRef(generator.componentOf(t))
+	}
+	// interface and the others can be any value
+	return &Schema{}
+}
+
+// componentOf adds the schema of the named struct to the components if it
+// isn't added yet, and returns its name
+func (generator *schemaGenerator) componentOf(t reflect.Type) string {
+	if name, ok := generator.names[t]; ok {
+		return name
+	}
+	var name string = t.Name()
+	if _, taken := generator.schemas[name]; taken {
+		// another package has the struct of the same name
+		name = path.Base(t.PkgPath()) + "." + t.Name()
+	}
+	generator.names[t] = name
+	// the name is reserved before the fields are generated, for the struct
+	// that refers itself
+	generator.schemas[name] = &Schema{}
+	*generator.schemas[name] = *generator.structSchema(t)
+	return name
+}
+
+func (generator *schemaGenerator) structSchema(t reflect.Type) *Schema {
+	var schema *Schema = &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	generator.addFields(schema, t)
+	return schema
+}
+
+// addFields adds the fields of the struct to the properties of the schema.
+// The fields of the embedded struct without JSON name are added as the
+// fields of the struct, like encoding/json.
+func (generator *schemaGenerator) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		var field reflect.StructField = t.Field(i)
+		var tag string = field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		var name string = strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" {
+			var embedded reflect.Type = field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				generator.addFields(schema, embedded)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			// unexported
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = generator.schemaOf(field.Type)
+		if !strings.Contains(tag, ",omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package replication
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// encodedVar is a statement argument with its type, so it is applied with the
+// same type on the standby
+type encodedVar struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// notifier wakes up the requests that are waiting for new entries
+type notifier struct {
+	mutex   sync.Mutex
+	channel chan struct{}
+}
+
+var entryNotifier = &notifier{channel: make(chan struct{})}
+
+// EnableCapture records every create, update and delete statement on the
+// database as replication entry, in the same transaction as the statement.
+// If the entry can't be recorded, the statement is rolled back, so the
+// standby never misses a write. The standby reads the entries in the order
+// of ID, which is the commit order only on sqlite3, where the transactions
+// are begun immediate and never run side by side. On postgres and mysql, an
+// entry could be committed after the standby has read the entries of higher
+// ID, and be skipped, so the capture is refused.
+func EnableCapture(db *gorm.DB) error {
+	if driver := db.Dialect().GetName(); driver != "sqlite3" {
+		return fmt.Errorf("replication only supports sqlite3 database, got %s", driver)
+	}
+	if db.Callback().Create().Get("replication:capture") == nil {
+		db.Callback().Create().After("gorm:create").Register("replication:capture", captureStatement)
+		db.Callback().Update().After("gorm:update").Register("replication:capture", captureStatement)
+		db.Callback().Delete().After("gorm:delete").Register("replication:capture", captureStatement)
+	}
+	return nil
+}
+
+func captureStatement(scope *gorm.Scope) {
+	if scope.HasError() || scope.SQL == "" || scope.DB().RowsAffected == 0 {
+		return
+	}
+	if tableName := scope.TableName(); tableName == (Entry{}).TableName() || tableName == (State{}).TableName() {
+		return
+	}
+	vars, err := encodeVars(scope.SQLVars)
+	if err != nil {
+		scope.Err(fmt.Errorf("failed to record replication entry: %v", err))
+		return
+	}
+	var entry Entry = Entry{Statement: scope.SQL, Vars: vars}
+	if err = scope.NewDB().Create(&entry).Error; err != nil {
+		scope.Err(fmt.Errorf("failed to record replication entry: %v", err))
+		return
+	}
+	entryNotifier.notify()
+}
+
+func encodeVars(vars []interface{}) (string, error) {
+	var encodedVars []encodedVar = make([]encodedVar, 0)
+	for _, v := range vars {
+		value, err := driver.DefaultParameterConverter.ConvertValue(v)
+		if err != nil {
+			return "", err
+		}
+		switch value := value.(type) {
+		case nil:
+			encodedVars = append(encodedVars, encodedVar{Type: "null"})
+		case int64:
+			encodedVars = append(encodedVars, encodedVar{Type: "int", Value: strconv.FormatInt(value, 10)})
+		case float64:
+			encodedVars = append(encodedVars, encodedVar{Type: "float", Value: strconv.FormatFloat(value, 'g', -1, 64)})
+		case bool:
+			encodedVars = append(encodedVars, encodedVar{Type: "bool", Value: strconv.FormatBool(value)})
+		case []byte:
+			encodedVars = append(encodedVars, encodedVar{Type: "bytes", Value: base64.StdEncoding.EncodeToString(value)})
+		case string:
+			encodedVars = append(encodedVars, encodedVar{Type: "string", Value: value})
+		case time.Time:
+			encodedVars = append(encodedVars, encodedVar{Type: "time", Value: value.Format(time.RFC3339Nano)})
+		default:
+			return "", fmt.Errorf("unsupported statement argument %T", value)
+		}
+	}
+	encoded, err := json.Marshal(encodedVars)
+	return string(encoded), err
+}
+
+func decodeVars(encoded string) ([]interface{}, error) {
+	var encodedVars []encodedVar
+	if err := json.Unmarshal([]byte(encoded), &encodedVars); err != nil {
+		return nil, err
+	}
+	var vars []interface{} = make([]interface{}, 0)
+	for _, encodedVar := range encodedVars {
+		var value interface{}
+		var err error
+		switch encodedVar.Type {
+		case "null":
+			value = nil
+		case "int":
+			value, err = strconv.ParseInt(encodedVar.Value, 10, 64)
+		case "float":
+			value, err = strconv.ParseFloat(encodedVar.Value, 64)
+		case "bool":
+			value, err = strconv.ParseBool(encodedVar.Value)
+		case "bytes":
+			value, err = base64.StdEncoding.DecodeString(encodedVar.Value)
+		case "string":
+			value = encodedVar.Value
+		case "time":
+			value, err = time.Parse(time.RFC3339Nano, encodedVar.Value)
+		default:
+			err = fmt.Errorf("unknown statement argument type %s", encodedVar.Type)
+		}
+		if err != nil {
+			return nil, err
+		}
+		vars = append(vars, value)
+	}
+	return vars, nil
+}
+
+// wait returns the channel that is closed on the next notification
+func (n *notifier) wait() <-chan struct{} {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.channel
+}
+
+func (n *notifier) notify() {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	close(n.channel)
+	n.channel = make(chan struct{})
+}
@@ -0,0 +1,67 @@
+package replication
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client fetches the replication entries and snapshot from the primary
+type Client struct {
+	// PrimaryURL is the base URL of the primary, e.g. http://10.0.0.2:8100
+	PrimaryURL string
+	Token      string
+	HTTPClient *http.Client
+}
+
+// NewClient returns the client of the primary
+func NewClient(primaryURL string, token string) *Client {
+	return &Client{
+		PrimaryURL: strings.TrimSuffix(primaryURL, "/"),
+		Token:      token,
+		HTTPClient: &http.Client{Timeout: entryWaitTimeout + 30*time.Second},
+	}
+}
+
+// GetEntries returns the entries of the primary after the ID
+func (client *Client) GetEntries(afterID uint) ([]EntryData, error) {
+	var entries []EntryData
+	err := client.get(fmt.Sprintf("/api/replication/entries/%d/", afterID), &entries)
+	return entries, err
+}
+
+// GetSnapshot returns the backup snapshot of the primary database
+func (client *Client) GetSnapshot() ([]byte, error) {
+	var snapshotData SnapshotData
+	if err := client.get("/api/replication/snapshot/", &snapshotData); err != nil {
+		return nil, err
+	}
+	snapshot, err := base64.StdEncoding.DecodeString(snapshotData.Snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot of primary is corrupted: %v", err)
+	}
+	return snapshot, nil
+}
+
+func (client *Client) get(path string, data interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, client.PrimaryURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+client.Token)
+	res, err := client.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("primary is unreachable: %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("primary responded %s to %s", res.Status, path)
+	}
+	if err = json.NewDecoder(res.Body).Decode(data); err != nil {
+		return fmt.Errorf("invalid response of primary to %s: %v", path, err)
+	}
+	return nil
+}
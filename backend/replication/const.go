@@ -0,0 +1,59 @@
+package replication
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/yonasadiel/helios"
+)
+
+const (
+	// RolePrimary is the role of server that serves the participants and
+	// records every write for the standby
+	RolePrimary = "primary"
+	// RoleStandby is the role of server that applies the writes of the primary,
+	// and doesn't serve the participants until it is promoted
+	RoleStandby = "standby"
+
+	// entryListLimit is the maximum number of entries sent to the standby at once
+	entryListLimit = 500
+	// entryWaitTimeout is the maximum time the primary holds the entries request
+	// while waiting for new entries
+	entryWaitTimeout = 5 * time.Second
+	// entryPollInterval is the interval of checking new entries while waiting,
+	// in case the notification comes before the write is committed
+	entryPollInterval = 200 * time.Millisecond
+	// standbyRetryInterval is the waiting time of standby before fetching the
+	// entries again after the primary is unreachable
+	standbyRetryInterval = 2 * time.Second
+	// stateID is the ID of the only row of replication state
+	stateID = 1
+)
+
+// Token is the shared secret between the primary and the standby. The
+// replication endpoints are disabled if it is empty.
+var Token string
+
+var errReplicationDisabled = helios.ErrorAPI{
+	StatusCode: http.StatusNotFound,
+	Code:       "replication_disabled",
+	Message:    "Replication is not enabled on this server",
+}
+
+var errReplicationTokenInvalid = helios.ErrorAPI{
+	StatusCode: http.StatusUnauthorized,
+	Code:       "replication_token_invalid",
+	Message:    "The replication token is invalid",
+}
+
+var errEntryIDInvalid = helios.ErrorAPI{
+	StatusCode: http.StatusBadRequest,
+	Code:       "replication_entry_id_invalid",
+	Message:    "The replication entry ID should be a number",
+}
+
+var errReplicationSnapshotFailed = helios.ErrorAPI{
+	StatusCode: http.StatusInternalServerError,
+	Code:       "replication_snapshot_failed",
+	Message:    "Failed to take the database snapshot",
+}
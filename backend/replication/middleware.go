@@ -0,0 +1,26 @@
+package replication
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/yonasadiel/helios"
+)
+
+// TokenMiddleware only allows the request with the replication token,
+// i.e. from the standby
+func TokenMiddleware(f helios.HTTPHandler) helios.HTTPHandler {
+	return func(req helios.Request) {
+		if Token == "" {
+			req.SendJSON(errReplicationDisabled.GetMessage(), errReplicationDisabled.GetStatusCode())
+			return
+		}
+		var authorization string = req.GetHeader("Authorization")
+		var token string = strings.TrimSpace(strings.TrimPrefix(authorization, "Bearer "))
+		if !strings.HasPrefix(authorization, "Bearer ") || subtle.ConstantTimeCompare([]byte(token), []byte(Token)) != 1 {
+			req.SendJSON(errReplicationTokenInvalid.GetMessage(), errReplicationTokenInvalid.GetStatusCode())
+			return
+		}
+		f(req)
+	}
+}
@@ -0,0 +1,41 @@
+package replication
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/yonasadiel/charon/backend/migration"
+)
+
+// Migrations is the schema migrations of replication models
+var Migrations = []migration.Migration{
+	{
+		Version: 2026101904,
+		Name:    "create replication tables",
+		Up: func(db *gorm.DB) error {
+			return migration.CreateTables(db, initialTables)
+		},
+		Down: func(db *gorm.DB) error {
+			return migration.DropTables(db, initialTables)
+		},
+	},
+}
+
+// initialTables is the replication tables created by the first migration. The
+// tables are kept apart from the models, so the migration doesn't change with
+// them.
+var initialTables = []migration.Table{
+	{Name: "replication_entries", Model: &struct {
+		ID        uint   `gorm:"primary_key"`
+		Statement string `gorm:"type:text"`
+		Vars      string `gorm:"type:text"`
+		CreatedAt time.Time
+	}{}},
+	{Name: "replication_states", Model: &struct {
+		ID            uint   `gorm:"primary_key"`
+		Role          string `gorm:"size:16"`
+		LastAppliedID uint
+		UpdatedAt     time.Time
+	}{}},
+}
@@ -0,0 +1,108 @@
+package replication
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/yonasadiel/helios"
+
+	"github.com/yonasadiel/charon/backend/migration"
+)
+
+// columnLength matches the length of column type, e.g. (256) of varchar(256)
+var columnLength = regexp.MustCompile(`\(\d+\)`)
+
+// dumpSchema returns the columns and the indexes of the tables of sqlite3
+// database as text by table name, for comparing schemas. The length of the
+// column types is left out, sqlite3 doesn't enforce it so the migrations
+// don't change it on sqlite3.
+func dumpSchema(t *testing.T, db *gorm.DB) map[string]string {
+	var tables []string
+	err := db.Table("sqlite_master").
+		Where("type = ?", "table").
+		Where("name NOT LIKE ?", "sqlite_%").
+		Where("name <> ?", "schema_migrations").
+		Pluck("name", &tables).Error
+	assert.Nil(t, err)
+	var schema map[string]string = make(map[string]string)
+	for _, table := range tables {
+		var dump []string
+		rows, err := db.Raw(fmt.Sprintf("PRAGMA table_info(%q)", table)).Rows()
+		assert.Nil(t, err)
+		for rows.Next() {
+			var cid, notNull, primaryKey int
+			var name, typ string
+			var defaultValue sql.NullString
+			assert.Nil(t, rows.Scan(&cid, &name, &typ, &notNull, &defaultValue, &primaryKey))
+			dump = append(dump, fmt.Sprintf("column %s %s notnull=%d default=%s pk=%d",
+				name, columnLength.ReplaceAllString(typ, ""), notNull, defaultValue.String, primaryKey))
+		}
+		rows.Close()
+		rows, err = db.Raw(fmt.Sprintf("PRAGMA index_list(%q)", table)).Rows()
+		assert.Nil(t, err)
+		var indexNames, indexes []string
+		for rows.Next() {
+			var seq, unique, partial int
+			var name, origin string
+			assert.Nil(t, rows.Scan(&seq, &name, &unique, &origin, &partial))
+			indexNames = append(indexNames, name)
+			if origin == "c" {
+				indexes = append(indexes, fmt.Sprintf("index %s unique=%d", name, unique))
+			} else {
+				// the indexes of unique and primary key constraints are named
+				// by their order in the table, so they are told by columns
+				indexes = append(indexes, fmt.Sprintf("index %s unique=%d", origin, unique))
+			}
+		}
+		rows.Close()
+		for i, indexName := range indexNames {
+			var columns []string
+			rows, err = db.Raw(fmt.Sprintf("PRAGMA index_info(%q)", indexName)).Rows()
+			assert.Nil(t, err)
+			for rows.Next() {
+				var seqNo, cid int
+				var name string
+				assert.Nil(t, rows.Scan(&seqNo, &cid, &name))
+				columns = append(columns, name)
+			}
+			rows.Close()
+			dump = append(dump, fmt.Sprintf("%s on %s", indexes[i], strings.Join(columns, ",")))
+		}
+		sort.Strings(dump)
+		schema[table] = strings.Join(dump, "\n")
+	}
+	return schema
+}
+
+func TestMigrationsMatchModels(t *testing.T) {
+	migrated, err := gorm.Open("sqlite3", ":memory:")
+	assert.Nil(t, err)
+	defer migrated.Close()
+	assert.Nil(t, migration.Up(migrated, testMigrations))
+
+	// the tests create the tables by the models registered to helios
+	models, err := gorm.Open("sqlite3", ":memory:")
+	assert.Nil(t, err)
+	defer models.Close()
+	var db *gorm.DB = helios.DB
+	helios.DB = models
+	helios.App.Migrate()
+	helios.DB = db
+
+	var migratedSchema map[string]string = dumpSchema(t, migrated)
+	var modelsSchema map[string]string = dumpSchema(t, models)
+	assert.NotEmpty(t, modelsSchema)
+	for table, tableSchema := range modelsSchema {
+		assert.Equal(t, tableSchema, migratedSchema[table], "Migrations should create table %s of the models", table)
+	}
+	for table := range migratedSchema {
+		_, ok := modelsSchema[table]
+		assert.True(t, ok, "Table %s of the migrations should be registered to helios", table)
+	}
+}
@@ -0,0 +1,41 @@
+package replication
+
+import (
+	"time"
+
+	"github.com/yonasadiel/helios"
+)
+
+// Entry is a write statement on the primary, to be applied on the standby
+// in the order of ID. Vars is the JSON encoded statement arguments.
+type Entry struct {
+	ID        uint   `gorm:"primary_key"`
+	Statement string `gorm:"type:text"`
+	Vars      string `gorm:"type:text"`
+	CreatedAt time.Time
+}
+
+// State is the replication state of the server. There is only one row.
+// LastAppliedID is the ID of the last entry of the primary that has been
+// applied, only used on the standby.
+type State struct {
+	ID            uint   `gorm:"primary_key"`
+	Role          string `gorm:"size:16"`
+	LastAppliedID uint
+	UpdatedAt     time.Time
+}
+
+// TableName returns the table name of replication entries
+func (Entry) TableName() string {
+	return "replication_entries"
+}
+
+// TableName returns the table name of replication state
+func (State) TableName() string {
+	return "replication_states"
+}
+
+func init() {
+	helios.App.RegisterModel(Entry{})
+	helios.App.RegisterModel(State{})
+}
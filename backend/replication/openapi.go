@@ -0,0 +1,13 @@
+package replication
+
+import (
+	"github.com/yonasadiel/charon/backend/openapi"
+)
+
+var replicationSecurity = []string{openapi.SecurityReplicationToken}
+
+// Operations describes the views of replication for the OpenAPI document
+var Operations = openapi.Operations{
+	"GET /replication/entries/{afterID}/": {ID: "ReplicationEntryList", Summary: "List the replication entries after the ID, waiting for a new one if there is none", Security: replicationSecurity, Response: []EntryData{}},
+	"GET /replication/snapshot/":          {ID: "ReplicationSnapshot", Summary: "Get the snapshot of the database for a new standby", Security: replicationSecurity, Response: SnapshotData{}},
+}
@@ -0,0 +1,31 @@
+package replication
+
+import (
+	"encoding/base64"
+)
+
+// EntryData is JSON representation of replication entry
+type EntryData struct {
+	ID        uint   `json:"id"`
+	Statement string `json:"statement"`
+	Vars      string `json:"vars"`
+}
+
+// SnapshotData is JSON representation of database snapshot
+type SnapshotData struct {
+	Snapshot string `json:"snapshot"`
+}
+
+// SerializeEntry converts Entry object to EntryData
+func SerializeEntry(entry Entry) EntryData {
+	return EntryData{
+		ID:        entry.ID,
+		Statement: entry.Statement,
+		Vars:      entry.Vars,
+	}
+}
+
+// SerializeSnapshot converts the snapshot to SnapshotData
+func SerializeSnapshot(snapshot []byte) SnapshotData {
+	return SnapshotData{Snapshot: base64.StdEncoding.EncodeToString(snapshot)}
+}
@@ -0,0 +1,131 @@
+package replication
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/yonasadiel/helios"
+
+	"github.com/yonasadiel/charon/backend/backup"
+	"github.com/yonasadiel/charon/backend/logging"
+)
+
+// GetEntries returns the entries after the ID. If there is none, it waits
+// for new entries until the wait duration passes. The standby asks for the
+// entries after the last one it has applied, so the entries before it are
+// pruned.
+func GetEntries(requestID string, afterID uint, wait time.Duration) ([]Entry, helios.Error) {
+	if errDB := pruneEntries(helios.DB, requestID, afterID); errDB != nil {
+		return nil, errDB
+	}
+	var deadline time.Time = time.Now().Add(wait)
+	for {
+		var notified <-chan struct{} = entryNotifier.wait()
+		var entries []Entry
+		if errDB := logging.CheckDB(requestID, helios.DB.Where("id > ?", afterID).Order("id asc").Limit(entryListLimit).Find(&entries)); errDB != nil {
+			return nil, errDB
+		}
+		if len(entries) > 0 || !time.Now().Before(deadline) {
+			return entries, nil
+		}
+		select {
+		case <-notified:
+		case <-time.After(entryPollInterval):
+		}
+	}
+}
+
+// pruneEntries deletes the entries that have been applied by the standby,
+// except the last one, so the snapshot for a new standby still tells the
+// entry to continue from
+func pruneEntries(db *gorm.DB, requestID string, appliedID uint) helios.Error {
+	return logging.CheckDB(requestID, db.Where("id < ?", appliedID).Delete(&Entry{}))
+}
+
+// GetSnapshot returns the backup snapshot of the database. The snapshot
+// includes the replication entries, so the standby continues from the last
+// entry in the snapshot.
+func GetSnapshot() ([]byte, helios.Error) {
+	file, err := ioutil.TempFile("", "charon-replication-*.backup")
+	if err != nil {
+		return nil, errReplicationSnapshotFailed
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+	if err = backup.Create(helios.DB, helios.DB.Dialect().GetName(), "", nil, file.Name()); err != nil {
+		return nil, errReplicationSnapshotFailed
+	}
+	snapshot, err := ioutil.ReadFile(file.Name())
+	if err != nil {
+		return nil, errReplicationSnapshotFailed
+	}
+	return snapshot, nil
+}
+
+// GetState returns the replication state of the database. The role is empty
+// if the replication has never been enabled.
+func GetState(db *gorm.DB) (State, error) {
+	var state State
+	if err := db.Where("id = ?", stateID).FirstOrInit(&state).Error; err != nil {
+		return state, fmt.Errorf("failed to read replication state: %v", err)
+	}
+	state.ID = stateID
+	return state, nil
+}
+
+// SetRole saves the role of the server. The standby starts applying the
+// entries after the ID.
+func SetRole(db *gorm.DB, role string, lastAppliedID uint) error {
+	state, err := GetState(db)
+	if err != nil {
+		return err
+	}
+	state.Role = role
+	state.LastAppliedID = lastAppliedID
+	if err = db.Save(&state).Error; err != nil {
+		return fmt.Errorf("failed to save replication state: %v", err)
+	}
+	return nil
+}
+
+// Promote makes the standby database the primary. The running standby stops
+// applying the entries and starts serving the participants.
+func Promote(db *gorm.DB) error {
+	state, err := GetState(db)
+	if err != nil {
+		return err
+	}
+	if state.Role != RoleStandby {
+		return fmt.Errorf("only standby can be promoted, the database role is %q", state.Role)
+	}
+	return SetRole(db, RolePrimary, state.LastAppliedID)
+}
+
+// applyEntries applies the entries of the primary and moves the last applied
+// ID in a transaction, so each entry is applied exactly once
+func applyEntries(db *gorm.DB, entries []EntryData) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	tx := db.Begin()
+	for _, entry := range entries {
+		vars, err := decodeVars(entry.Vars)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("replication entry %d is corrupted: %v", entry.ID, err)
+		}
+		if err = tx.Exec(entry.Statement, vars...).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to apply replication entry %d: %v", entry.ID, err)
+		}
+	}
+	err := tx.Model(&State{}).Where("id = ?", stateID).Update("last_applied_id", entries[len(entries)-1].ID).Error
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to save replication state: %v", err)
+	}
+	return tx.Commit().Error
+}
@@ -0,0 +1,141 @@
+package replication
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/yonasadiel/helios"
+
+	"github.com/yonasadiel/charon/backend/announcement"
+	"github.com/yonasadiel/charon/backend/auth"
+	"github.com/yonasadiel/charon/backend/backup"
+	"github.com/yonasadiel/charon/backend/exam"
+	"github.com/yonasadiel/charon/backend/migration"
+)
+
+var testMigrations = migration.Collect(auth.Migrations, exam.Migrations, announcement.Migrations, Migrations)
+
+// dumpTable returns all rows of the table as text, for comparing databases
+func dumpTable(db *gorm.DB, table string) string {
+	rows, err := db.Raw(fmt.Sprintf("SELECT * FROM %s ORDER BY id", table)).Rows()
+	if err != nil {
+		return err.Error()
+	}
+	defer rows.Close()
+	columns, _ := rows.Columns()
+	var dump []string
+	for rows.Next() {
+		var values []interface{} = make([]interface{}, len(columns))
+		var pointers []interface{} = make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		rows.Scan(pointers...)
+		dump = append(dump, fmt.Sprintf("%v", values))
+	}
+	return strings.Join(dump, "\n")
+}
+
+func TestReplicate(t *testing.T) {
+	helios.App.BeforeTest()
+	if helios.DB.Dialect().GetName() != "sqlite3" {
+		assert.NotNil(t, EnableCapture(helios.DB), "Capture should be refused on non-sqlite3 database")
+		t.Skip("replication only supports sqlite3 database")
+	}
+	assert.Nil(t, migration.Up(helios.DB, testMigrations))
+	dir, err := ioutil.TempDir("", "charon-replication")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	var participation exam.Participation = exam.ParticipationFactorySaved(exam.Participation{User: &auth.User{Password: "replicated-password"}})
+	snapshot, errSnapshot := GetSnapshot()
+	assert.Nil(t, errSnapshot)
+	var snapshotFile string = filepath.Join(dir, "snapshot.backup")
+	var standbyFile string = filepath.Join(dir, "standby.sqlite3")
+	assert.Nil(t, ioutil.WriteFile(snapshotFile, snapshot, 0600))
+	_, _, err = backup.Restore(snapshotFile, "sqlite3", standbyFile, nil, testMigrations)
+	assert.Nil(t, err)
+	standbyDB, err := gorm.Open("sqlite3", standbyFile)
+	assert.Nil(t, err)
+	defer standbyDB.Close()
+	assert.Nil(t, SetRole(standbyDB, RoleStandby, 0))
+
+	assert.Nil(t, EnableCapture(helios.DB))
+	var userQuestion exam.UserQuestion = exam.UserQuestionFactorySaved(exam.UserQuestion{Participation: &participation})
+	helios.DB.Model(&userQuestion).Update("answer", "changed answer")
+	helios.DB.Model(&exam.UserQuestion{}).Where("participation_id = ?", participation.ID).Update("ordering", 7)
+	session, errLogin := auth.Login(participation.User.Username, "replicated-password", "10.0.0.1")
+	assert.Nil(t, errLogin)
+	auth.Logout(*session)
+	helios.DB.Create(&auth.Session{UserID: participation.User.ID, Token: "replicated-session", LastSeenAt: time.Now()})
+	helios.DB.Delete(&exam.Question{}, "id = ?", userQuestion.QuestionID)
+	tx := helios.DB.Begin()
+	tx.Create(&auth.Session{UserID: participation.User.ID, Token: "rolled-back-session"})
+	tx.Rollback()
+
+	entries, errGetEntries := GetEntries("", 0, 0)
+	assert.Nil(t, errGetEntries)
+	assert.True(t, len(entries) > 0)
+	var serializedEntries []EntryData
+	for _, entry := range entries {
+		serializedEntries = append(serializedEntries, SerializeEntry(entry))
+	}
+	assert.Nil(t, applyEntries(standbyDB, serializedEntries))
+	for _, table := range []string{"users", "sessions", "participations", "questions", "user_questions"} {
+		assert.Equal(t, dumpTable(helios.DB, table), dumpTable(standbyDB, table), table)
+	}
+	var standbyUserQuestion exam.UserQuestion
+	standbyDB.Where("id = ?", userQuestion.ID).First(&standbyUserQuestion)
+	assert.Equal(t, "changed answer", standbyUserQuestion.Answer)
+	assert.Equal(t, uint(7), standbyUserQuestion.Ordering)
+	var sessionCount, rolledBackSessionCount int
+	standbyDB.Model(&auth.Session{}).Where("token = ?", "replicated-session").Count(&sessionCount)
+	standbyDB.Model(&auth.Session{}).Where("token = ?", "rolled-back-session").Count(&rolledBackSessionCount)
+	assert.Equal(t, 1, sessionCount)
+	assert.Equal(t, 0, rolledBackSessionCount)
+	state, err := GetState(standbyDB)
+	assert.Nil(t, err)
+	assert.Equal(t, entries[len(entries)-1].ID, state.LastAppliedID)
+	newEntries, errGetNewEntries := GetEntries("", state.LastAppliedID, 0)
+	assert.Nil(t, errGetNewEntries)
+	assert.Equal(t, 0, len(newEntries))
+	var remainingEntries []Entry
+	helios.DB.Order("id asc").Find(&remainingEntries)
+	if assert.Equal(t, 1, len(remainingEntries), "Applied entries should be pruned except the last one") {
+		assert.Equal(t, state.LastAppliedID, remainingEntries[0].ID)
+	}
+
+	assert.NotNil(t, applyEntries(standbyDB, []EntryData{{ID: state.LastAppliedID + 1, Statement: "UPDATE unknown_table SET x = 1", Vars: "[]"}}))
+	state, _ = GetState(standbyDB)
+	assert.Equal(t, entries[len(entries)-1].ID, state.LastAppliedID)
+
+	assert.Nil(t, Promote(standbyDB))
+	state, _ = GetState(standbyDB)
+	assert.Equal(t, RolePrimary, state.Role)
+	assert.NotNil(t, Promote(standbyDB))
+}
+
+func TestEncodeVars(t *testing.T) {
+	var now time.Time = time.Now()
+	var deletedAt *time.Time = &now
+	var vars []interface{} = []interface{}{nil, uint(3), -4, 1.5, true, []byte("bytes"), "string", now, deletedAt}
+	encoded, err := encodeVars(vars)
+	assert.Nil(t, err)
+	decoded, err := decodeVars(encoded)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{nil, int64(3), int64(-4), 1.5, true, []byte("bytes"), "string"}, decoded[:7])
+	assert.True(t, now.Equal(decoded[7].(time.Time)))
+	assert.True(t, now.Equal(decoded[8].(time.Time)))
+
+	_, err = encodeVars([]interface{}{struct{}{}})
+	assert.NotNil(t, err)
+	_, err = decodeVars(`[{"type":"complex","value":"1i"}]`)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,95 @@
+package replication
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/yonasadiel/charon/backend/backup"
+	"github.com/yonasadiel/charon/backend/logging"
+	"github.com/yonasadiel/charon/backend/migration"
+)
+
+// Bootstrap creates the standby sqlite3 database file from the snapshot of
+// the primary. The standby continues from the last entry in the snapshot.
+func Bootstrap(client *Client, databaseFile string, migrations []migration.Migration) error {
+	snapshot, err := client.GetSnapshot()
+	if err != nil {
+		return err
+	}
+	file, err := ioutil.TempFile("", "charon-replication-*.backup")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	_, err = file.Write(snapshot)
+	file.Close()
+	if err != nil {
+		return err
+	}
+	if _, _, err = backup.Restore(file.Name(), "sqlite3", databaseFile, nil, migrations); err != nil {
+		return err
+	}
+
+	db, err := gorm.Open("sqlite3", databaseFile)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	var lastEntry Entry
+	db.Order("id desc").First(&lastEntry)
+	return SetRole(db, RoleStandby, lastEntry.ID)
+}
+
+// RunStandby applies the entries of the primary to the database until the
+// database is promoted or the stop channel is closed. The primary being
+// unreachable is retried, but an entry that fails to apply stops the standby,
+// because the databases have diverged.
+//
+// The sessions are replicated, so the participants stay logged in after the
+// failover as long as both servers share HELIOS_SECRET and SESSION_NAME and
+// the promoted server is reachable at the same address.
+func RunStandby(db *gorm.DB, client *Client, stop <-chan struct{}) error {
+	var isPrimaryUnreachable bool = false
+	for {
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+		state, err := GetState(db)
+		if err != nil {
+			return err
+		}
+		if state.Role == RolePrimary {
+			return nil
+		}
+		if state.Role != RoleStandby {
+			return fmt.Errorf("database is not a standby, its role is %q", state.Role)
+		}
+
+		entries, err := client.GetEntries(state.LastAppliedID)
+		if err != nil {
+			if !isPrimaryUnreachable {
+				logging.Warn("standby failed to fetch replication entries", logging.Fields{"error": err})
+			}
+			isPrimaryUnreachable = true
+			select {
+			case <-stop:
+				return nil
+			case <-time.After(standbyRetryInterval):
+			}
+			continue
+		}
+		if isPrimaryUnreachable {
+			logging.Info("standby is connected to the primary again", nil)
+		}
+		isPrimaryUnreachable = false
+		if err = applyEntries(db, entries); err != nil {
+			return err
+		}
+	}
+}
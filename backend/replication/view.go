@@ -0,0 +1,40 @@
+package replication
+
+import (
+	"net/http"
+
+	"github.com/yonasadiel/helios"
+
+	"github.com/yonasadiel/charon/backend/logging"
+)
+
+// EntryListView sends the replication entries after the given ID, waiting
+// for new entries if there is none
+func EntryListView(req helios.Request) {
+	afterID, errParseAfterID := req.GetURLParamUint("afterID")
+	if errParseAfterID != nil {
+		req.SendJSON(errEntryIDInvalid.GetMessage(), errEntryIDInvalid.GetStatusCode())
+		return
+	}
+
+	entries, errGetEntries := GetEntries(req.GetHeader(logging.RequestIDHeader), afterID, entryWaitTimeout)
+	if errGetEntries != nil {
+		req.SendJSON(errGetEntries.GetMessage(), errGetEntries.GetStatusCode())
+		return
+	}
+	var serializedEntries []EntryData = make([]EntryData, 0)
+	for _, entry := range entries {
+		serializedEntries = append(serializedEntries, SerializeEntry(entry))
+	}
+	req.SendJSON(serializedEntries, http.StatusOK)
+}
+
+// SnapshotView sends the snapshot of the database for the new standby
+func SnapshotView(req helios.Request) {
+	snapshot, err := GetSnapshot()
+	if err != nil {
+		req.SendJSON(err.GetMessage(), err.GetStatusCode())
+		return
+	}
+	req.SendJSON(SerializeSnapshot(snapshot), http.StatusOK)
+}
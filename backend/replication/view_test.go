@@ -0,0 +1,84 @@
+package replication
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yonasadiel/helios"
+)
+
+func TestTokenMiddleware(t *testing.T) {
+	defer func() { Token = "" }()
+	var blankHandler = func(req helios.Request) {
+		req.SendJSON("OK", http.StatusOK)
+	}
+	var wrappedHandler helios.HTTPHandler = TokenMiddleware(blankHandler)
+	type tokenMiddlewareTestCase struct {
+		token              string
+		authorization      string
+		expectedStatusCode int
+	}
+	testCases := []tokenMiddlewareTestCase{
+		tokenMiddlewareTestCase{token: "", authorization: "Bearer ", expectedStatusCode: errReplicationDisabled.StatusCode},
+		tokenMiddlewareTestCase{token: "secret", authorization: "", expectedStatusCode: errReplicationTokenInvalid.StatusCode},
+		tokenMiddlewareTestCase{token: "secret", authorization: "secret", expectedStatusCode: errReplicationTokenInvalid.StatusCode},
+		tokenMiddlewareTestCase{token: "secret", authorization: "Bearer wrong", expectedStatusCode: errReplicationTokenInvalid.StatusCode},
+		tokenMiddlewareTestCase{token: "secret", authorization: "Bearer secret", expectedStatusCode: http.StatusOK},
+	}
+	for i, testCase := range testCases {
+		t.Logf("Test TokenMiddleware testcase: %d", i)
+		Token = testCase.token
+		req := helios.NewMockRequest()
+		req.RequestHeader["authorization"] = testCase.authorization
+		wrappedHandler(&req)
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+	}
+}
+
+func TestEntryListView(t *testing.T) {
+	helios.App.BeforeTest()
+	var firstEntry Entry = Entry{Statement: "DELETE FROM users WHERE id = ?", Vars: `[{"type":"int","value":"1"}]`}
+	var lastEntry Entry = Entry{Statement: "DELETE FROM users WHERE id = ?", Vars: `[{"type":"int","value":"2"}]`}
+	helios.DB.Create(&firstEntry)
+	helios.DB.Create(&lastEntry)
+
+	type entryListTestCase struct {
+		afterID            string
+		expectedStatusCode int
+		expectedCount      int
+	}
+	testCases := []entryListTestCase{
+		entryListTestCase{afterID: "abc", expectedStatusCode: errEntryIDInvalid.StatusCode},
+		entryListTestCase{afterID: "0", expectedStatusCode: http.StatusOK, expectedCount: 2},
+		entryListTestCase{afterID: fmt.Sprintf("%d", firstEntry.ID), expectedStatusCode: http.StatusOK, expectedCount: 1},
+	}
+	for i, testCase := range testCases {
+		t.Logf("Test EntryListView testcase: %d", i)
+		req := helios.NewMockRequest()
+		req.URLParam["afterID"] = testCase.afterID
+		EntryListView(&req)
+		assert.Equal(t, testCase.expectedStatusCode, req.StatusCode)
+		if testCase.expectedStatusCode == http.StatusOK {
+			var entries []EntryData
+			json.Unmarshal(req.JSONResponse, &entries)
+			assert.Equal(t, testCase.expectedCount, len(entries))
+			assert.Equal(t, lastEntry.ID, entries[len(entries)-1].ID)
+		}
+	}
+}
+
+func TestSnapshotView(t *testing.T) {
+	helios.App.BeforeTest()
+	req := helios.NewMockRequest()
+	SnapshotView(&req)
+	assert.Equal(t, http.StatusOK, req.StatusCode)
+	var snapshotData SnapshotData
+	json.Unmarshal(req.JSONResponse, &snapshotData)
+	snapshot, err := base64.StdEncoding.DecodeString(snapshotData.Snapshot)
+	assert.Nil(t, err)
+	assert.Contains(t, string(snapshot), "CHARON-BACKUP")
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// forwardedHeaders are the headers that carry the client IP through the
+// reverse proxies, read by helios.Request.ClientIP
+var forwardedHeaders = []string{"X-Forwarded-For", "X-Real-Ip"}
+
+// TrustedProxyHandler resolves the client IP before the request reaches the
+// handler, so the client can't pick the IP used for the login throttle and
+// the sessions by sending X-Forwarded-For. The forwarded headers are removed
+// unless the request comes from one of the trusted proxies. Otherwise
+// X-Forwarded-For is walked from the nearest hop, skipping the trusted
+// proxies, and replaced with the first address that isn't trusted.
+func TrustedProxyHandler(trustedProxies []*net.IPNet, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var remoteIP string = r.RemoteAddr
+		if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+			remoteIP = host
+		}
+		if !isTrustedProxy(trustedProxies, remoteIP) {
+			for _, header := range forwardedHeaders {
+				r.Header.Del(header)
+			}
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		var hops []string
+		for _, value := range r.Header[http.CanonicalHeaderKey("X-Forwarded-For")] {
+			for _, hop := range strings.Split(value, ",") {
+				if hop = strings.TrimSpace(hop); hop != "" {
+					hops = append(hops, hop)
+				}
+			}
+		}
+		var clientIP string = remoteIP
+		if len(hops) == 0 && net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-Ip"))) != nil {
+			clientIP = strings.TrimSpace(r.Header.Get("X-Real-Ip"))
+		}
+		for i := len(hops) - 1; i >= 0; i-- {
+			if net.ParseIP(hops[i]) == nil {
+				break
+			}
+			clientIP = hops[i]
+			if !isTrustedProxy(trustedProxies, hops[i]) {
+				break
+			}
+		}
+		r.Header.Del("X-Real-Ip")
+		r.Header.Set("X-Forwarded-For", clientIP)
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func isTrustedProxy(trustedProxies []*net.IPNet, address string) bool {
+	var ip net.IP = net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	for _, network := range trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
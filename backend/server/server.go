@@ -0,0 +1,141 @@
+// Package server runs the HTTP server until the process is asked to shut
+// down, then drains the requests in flight and stops the background jobs, so
+// a restart during the exam doesn't cut off a submission halfway.
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/yonasadiel/charon/backend/config"
+	"github.com/yonasadiel/charon/backend/logging"
+)
+
+// ShutdownSignals are the signals that shut the server down gracefully
+var ShutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// NotifyShutdown returns a channel that is closed when the process receives
+// one of ShutdownSignals. The second signal exits immediately, to stop the
+// server that is stuck on shutdown.
+func NotifyShutdown() <-chan struct{} {
+	var signals chan os.Signal = make(chan os.Signal, 1)
+	var shutdown chan struct{} = make(chan struct{})
+	signal.Notify(signals, ShutdownSignals...)
+	go func() {
+		var received os.Signal = <-signals
+		logging.Info("shutting down", logging.Fields{"signal": received.String()})
+		close(shutdown)
+		received = <-signals
+		logging.Error("exiting without finishing the shutdown", logging.Fields{"signal": received.String()})
+		os.Exit(1)
+	}()
+	return shutdown
+}
+
+// Job is a background job that runs alongside the server
+type Job struct {
+	// Name identifies the job in the logs
+	Name string
+	// Stop stops the job, and returns after the job has stopped
+	Stop func()
+}
+
+// Server is the HTTP server with the background jobs that are stopped on
+// shutdown
+type Server struct {
+	httpServer      *http.Server
+	tlsCertFile     string
+	tlsKeyFile      string
+	shutdownTimeout time.Duration
+	jobs            []Job
+}
+
+// New returns the server of the handler, listening on the address with the
+// TLS files and the shutdown timeout of the configuration. The client IP of
+// the requests is resolved with the trusted proxies of the configuration.
+func New(cfg config.Config, handler http.Handler) *Server {
+	// the trusted proxies have been validated on loading the configuration
+	trustedProxies, _ := config.ParseTrustedProxies(cfg.TrustedProxies)
+	return &Server{
+		httpServer:      &http.Server{Addr: cfg.ListenAddress, Handler: TrustedProxyHandler(trustedProxies, handler)},
+		tlsCertFile:     cfg.TLSCertFile,
+		tlsKeyFile:      cfg.TLSKeyFile,
+		shutdownTimeout: cfg.ShutdownTimeout,
+	}
+}
+
+// AddJob adds the background job that is stopped on shutdown. The jobs are
+// stopped in the reverse order of adding like deferred calls, so the job
+// added first, e.g. closing the database, is stopped last.
+func (server *Server) AddJob(name string, stop func()) {
+	server.jobs = append(server.jobs, Job{Name: name, Stop: stop})
+}
+
+// Run serves the requests until shutdown is closed. Then it stops accepting
+// new requests, waits for the requests in flight until the shutdown timeout
+// passes, cuts off the remaining ones, and stops the jobs. The jobs are also
+// stopped if the server fails, and the failure is returned.
+func (server *Server) Run(shutdown <-chan struct{}) error {
+	listener, err := net.Listen("tcp", server.httpServer.Addr)
+	if err != nil {
+		server.stopJobs()
+		return err
+	}
+	return server.serve(listener, shutdown)
+}
+
+func (server *Server) serve(listener net.Listener, shutdown <-chan struct{}) error {
+	var served chan error = make(chan error, 1)
+	go func() {
+		if server.tlsCertFile != "" {
+			served <- server.httpServer.ServeTLS(listener, server.tlsCertFile, server.tlsKeyFile)
+		} else {
+			served <- server.httpServer.Serve(listener)
+		}
+	}()
+	logging.Info("server started", logging.Fields{"address": listener.Addr().String(), "tls": server.tlsCertFile != ""})
+
+	var err error
+	select {
+	case err = <-served:
+	case <-shutdown:
+		server.drain()
+		// Serve returns http.ErrServerClosed as soon as the shutdown begins
+		<-served
+	}
+	server.stopJobs()
+	if err == nil {
+		logging.Info("server stopped", nil)
+	}
+	return err
+}
+
+// drain stops accepting new requests and waits for the requests in flight
+// until the shutdown timeout passes, then closes the remaining connections
+func (server *Server) drain() {
+	ctx, cancel := context.WithTimeout(context.Background(), server.shutdownTimeout)
+	defer cancel()
+	var start time.Time = time.Now()
+	if err := server.httpServer.Shutdown(ctx); err != nil {
+		server.httpServer.Close()
+		logging.Warn("requests in flight are cut off after the shutdown timeout", logging.Fields{
+			"timeout_ms": float64(server.shutdownTimeout) / float64(time.Millisecond),
+		})
+		return
+	}
+	logging.Info("requests in flight are drained", logging.Fields{
+		"duration_ms": float64(time.Since(start)) / float64(time.Millisecond),
+	})
+}
+
+func (server *Server) stopJobs() {
+	for i := len(server.jobs) - 1; i >= 0; i-- {
+		server.jobs[i].Stop()
+		logging.Info("background job stopped", logging.Fields{"job": server.jobs[i].Name})
+	}
+}
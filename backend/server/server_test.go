@@ -0,0 +1,130 @@
+package server
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yonasadiel/charon/backend/config"
+)
+
+func TestServe(t *testing.T) {
+	type serveTestCase struct {
+		shutdownTimeout    time.Duration
+		handlerDuration    time.Duration
+		expectedStatusCode int
+	}
+	testCases := []serveTestCase{
+		// the request in flight is drained
+		serveTestCase{shutdownTimeout: time.Second, handlerDuration: 100 * time.Millisecond, expectedStatusCode: http.StatusOK},
+		// the request in flight is cut off after the timeout
+		serveTestCase{shutdownTimeout: 50 * time.Millisecond, handlerDuration: time.Second, expectedStatusCode: 0},
+	}
+
+	for i, testCase := range testCases {
+		t.Logf("Test Serve testcase: %d", i)
+		var started chan struct{} = make(chan struct{})
+		var handler http.HandlerFunc = func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			time.Sleep(testCase.handlerDuration)
+			w.Write([]byte("submitted"))
+		}
+		var stoppedJobs []string
+		var server *Server = New(config.Config{ShutdownTimeout: testCase.shutdownTimeout}, handler)
+		server.AddJob("database", func() { stoppedJobs = append(stoppedJobs, "database") })
+		server.AddJob("backup", func() { stoppedJobs = append(stoppedJobs, "backup") })
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.Nil(t, err)
+		var address string = "http://" + listener.Addr().String()
+		var shutdown chan struct{} = make(chan struct{})
+		var served chan error = make(chan error, 1)
+		go func() { served <- server.serve(listener, shutdown) }()
+
+		var statusCode chan int = make(chan int, 1)
+		go func() {
+			res, err := http.Get(address + "/submit/")
+			if err != nil {
+				statusCode <- 0
+				return
+			}
+			defer res.Body.Close()
+			if _, err = ioutil.ReadAll(res.Body); err != nil {
+				statusCode <- 0
+				return
+			}
+			statusCode <- res.StatusCode
+		}()
+		<-started
+		close(shutdown)
+
+		assert.Nil(t, <-served)
+		assert.Equal(t, testCase.expectedStatusCode, <-statusCode)
+		assert.Equal(t, []string{"backup", "database"}, stoppedJobs)
+		_, err = http.Get(address + "/submit/")
+		assert.NotNil(t, err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	// the address is in use, so the server fails and the jobs are stopped
+	var stoppedJobs []string
+	var server *Server = New(config.Config{ListenAddress: listener.Addr().String(), ShutdownTimeout: time.Second}, http.NotFoundHandler())
+	server.AddJob("database", func() { stoppedJobs = append(stoppedJobs, "database") })
+	assert.NotNil(t, server.Run(make(chan struct{})))
+	assert.Equal(t, []string{"database"}, stoppedJobs)
+}
+
+func TestTrustedProxyHandler(t *testing.T) {
+	trustedProxies, err := config.ParseTrustedProxies([]string{"10.0.0.1", "192.168.0.0/16"})
+	assert.Nil(t, err)
+
+	type trustedProxyHandlerTestCase struct {
+		remoteAddr       string
+		forwardedFor     string
+		realIP           string
+		expectedClientIP string
+	}
+	testCases := []trustedProxyHandlerTestCase{
+		// the headers of untrusted client are ignored
+		trustedProxyHandlerTestCase{remoteAddr: "1.2.3.4:5000", forwardedFor: "5.6.7.8", realIP: "5.6.7.8", expectedClientIP: ""},
+		trustedProxyHandlerTestCase{remoteAddr: "10.0.0.1:5000", forwardedFor: "1.2.3.4", expectedClientIP: "1.2.3.4"},
+		// the spoofed hop before the proxy chain is skipped
+		trustedProxyHandlerTestCase{remoteAddr: "10.0.0.1:5000", forwardedFor: "5.6.7.8, 1.2.3.4, 192.168.1.1", expectedClientIP: "1.2.3.4"},
+		trustedProxyHandlerTestCase{remoteAddr: "10.0.0.1:5000", forwardedFor: "192.168.1.2, 192.168.1.1", expectedClientIP: "192.168.1.2"},
+		trustedProxyHandlerTestCase{remoteAddr: "10.0.0.1:5000", forwardedFor: "unknown, 192.168.1.1", expectedClientIP: "192.168.1.1"},
+		trustedProxyHandlerTestCase{remoteAddr: "10.0.0.1:5000", realIP: "1.2.3.4", expectedClientIP: "1.2.3.4"},
+		trustedProxyHandlerTestCase{remoteAddr: "10.0.0.1:5000", expectedClientIP: "10.0.0.1"},
+	}
+
+	for i, testCase := range testCases {
+		t.Logf("Test TrustedProxyHandler testcase: %d", i)
+		var forwardedFor, realIP string
+		var handler http.HandlerFunc = func(w http.ResponseWriter, r *http.Request) {
+			forwardedFor = r.Header.Get("X-Forwarded-For")
+			realIP = r.Header.Get("X-Real-Ip")
+		}
+		request, _ := http.NewRequest(http.MethodPost, "/api/auth/login/", nil)
+		request.RemoteAddr = testCase.remoteAddr
+		if testCase.forwardedFor != "" {
+			request.Header.Set("X-Forwarded-For", testCase.forwardedFor)
+		}
+		if testCase.realIP != "" {
+			request.Header.Set("X-Real-Ip", testCase.realIP)
+		}
+
+		TrustedProxyHandler(trustedProxies, handler).ServeHTTP(httptest.NewRecorder(), request)
+
+		assert.Equal(t, testCase.expectedClientIP, forwardedFor)
+		assert.Equal(t, "", realIP)
+	}
+}
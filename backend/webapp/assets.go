@@ -0,0 +1,72 @@
+package webapp
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"os"
+	"path"
+	"time"
+)
+
+// Assets is the compiled frontend that is embedded in the binary built with
+// the embedfrontend tag, after running go generate on this package. It is
+// nil otherwise, and the frontend is served separately.
+var Assets http.FileSystem
+
+// memoryFileSystem is the files whose contents are kept in memory, keyed by
+// the absolute path, e.g. "/index.html"
+type memoryFileSystem struct {
+	files   map[string]string
+	modTime time.Time
+}
+
+// newMemoryFileSystem returns the file system of the files, which are
+// modified at the time the frontend is built
+func newMemoryFileSystem(modTime time.Time, files map[string]string) http.FileSystem {
+	return &memoryFileSystem{files: files, modTime: modTime}
+}
+
+// Open implements http.FileSystem. Only the files can be opened, not the
+// directories.
+func (fs *memoryFileSystem) Open(name string) (http.File, error) {
+	name = path.Clean("/" + name)
+	content, ok := fs.files[name]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return &memoryFile{
+		Reader: bytes.NewReader([]byte(content)),
+		info:   memoryFileInfo{name: path.Base(name), size: int64(len(content)), modTime: fs.modTime},
+	}, nil
+}
+
+type memoryFile struct {
+	*bytes.Reader
+	info memoryFileInfo
+}
+
+func (file *memoryFile) Close() error {
+	return nil
+}
+
+func (file *memoryFile) Readdir(count int) ([]os.FileInfo, error) {
+	return nil, errors.New("not a directory")
+}
+
+func (file *memoryFile) Stat() (os.FileInfo, error) {
+	return file.info, nil
+}
+
+type memoryFileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+func (info memoryFileInfo) Name() string       { return info.name }
+func (info memoryFileInfo) Size() int64        { return info.size }
+func (info memoryFileInfo) Mode() os.FileMode  { return 0444 }
+func (info memoryFileInfo) ModTime() time.Time { return info.modTime }
+func (info memoryFileInfo) IsDir() bool        { return false }
+func (info memoryFileInfo) Sys() interface{}   { return nil }
@@ -0,0 +1,103 @@
+// Package webapp serves the compiled frontend from the server binary, so a
+// venue only needs the binary to run the exam. The frontend is a single page
+// application, so the paths that aren't files are served with index.html and
+// routed by the frontend.
+package webapp
+
+//go:generate go run ../cmd/embedfrontend ../../frontend/build assets_embed.go
+
+import (
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// APIPrefix is the path prefix of the API, the other paths are the frontend
+const APIPrefix = "/api"
+
+// indexFile is the page of the frontend that routes the paths without file
+const indexFile = "/index.html"
+
+// staticDir is the directory of the assets whose names contain the hash of
+// their content, so they never change and are cached forever
+const staticDir = "/static/"
+
+// Cache-Control of the hashed assets and of the other files, which are
+// revalidated on every request so a new frontend is loaded after upgrade
+const (
+	cacheForever    = "public, max-age=31536000, immutable"
+	cacheRevalidate = "no-cache"
+)
+
+// Mount serves the assets on the paths of the router that are outside of
+// APIPrefix. It does nothing if assets is nil, i.e. the frontend isn't
+// embedded. It should be called after the API routes are added, since the
+// assets match every path.
+func Mount(router *mux.Router, assets http.FileSystem) {
+	if assets == nil {
+		return
+	}
+	router.PathPrefix("/").
+		Methods(http.MethodGet, http.MethodHead).
+		MatcherFunc(func(req *http.Request, match *mux.RouteMatch) bool { return !isAPIPath(req.URL.Path) }).
+		Handler(Handler(assets))
+}
+
+// Handler returns the handler that serves the file of the path from assets.
+// If the file doesn't exist, index.html is served instead, unless the path
+// has an extension, e.g. a missing script, which is not found.
+func Handler(assets http.FileSystem) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if isAPIPath(req.URL.Path) {
+			http.NotFound(w, req)
+			return
+		}
+		var name string = path.Clean("/" + req.URL.Path)
+		file, info, err := open(assets, name)
+		if os.IsNotExist(err) && path.Ext(name) == "" {
+			name = indexFile
+			file, info, err = open(assets, name)
+		}
+		if os.IsNotExist(err) {
+			http.NotFound(w, req)
+			return
+		}
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		defer file.Close()
+
+		if strings.HasPrefix(name, staticDir) {
+			w.Header().Set("Cache-Control", cacheForever)
+		} else {
+			w.Header().Set("Cache-Control", cacheRevalidate)
+		}
+		http.ServeContent(w, req, name, info.ModTime(), file)
+	})
+}
+
+// open returns the file of the name, a directory is not exist since the
+// directories aren't served
+func open(assets http.FileSystem, name string) (http.File, os.FileInfo, error) {
+	file, err := assets.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := file.Stat()
+	if err == nil && info.IsDir() {
+		err = os.ErrNotExist
+	}
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	return file, info, nil
+}
+
+func isAPIPath(urlPath string) bool {
+	return urlPath == APIPrefix || strings.HasPrefix(urlPath, APIPrefix+"/")
+}
@@ -0,0 +1,106 @@
+package webapp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func createAssets() http.FileSystem {
+	return newMemoryFileSystem(time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), map[string]string{
+		"/index.html":               "<html>charon</html>",
+		"/favicon.ico":              "icon",
+		"/static/js/main.1a2b.js":   "console.log('charon')",
+		"/static/css/main.3c4d.css": "body {}",
+	})
+}
+
+func TestHandler(t *testing.T) {
+	type handlerTestCase struct {
+		method               string
+		path                 string
+		expectedStatusCode   int
+		expectedBody         string
+		expectedCacheControl string
+		expectedContentType  string
+	}
+	testCases := []handlerTestCase{
+		handlerTestCase{method: http.MethodGet, path: "/", expectedStatusCode: http.StatusOK, expectedBody: "<html>charon</html>", expectedCacheControl: cacheRevalidate, expectedContentType: "text/html; charset=utf-8"},
+		handlerTestCase{method: http.MethodGet, path: "/index.html", expectedStatusCode: http.StatusOK, expectedBody: "<html>charon</html>", expectedCacheControl: cacheRevalidate, expectedContentType: "text/html; charset=utf-8"},
+		handlerTestCase{method: http.MethodGet, path: "/exam/event-1/question/2", expectedStatusCode: http.StatusOK, expectedBody: "<html>charon</html>", expectedCacheControl: cacheRevalidate, expectedContentType: "text/html; charset=utf-8"},
+		handlerTestCase{method: http.MethodGet, path: "/static/", expectedStatusCode: http.StatusOK, expectedBody: "<html>charon</html>", expectedCacheControl: cacheRevalidate, expectedContentType: "text/html; charset=utf-8"},
+		handlerTestCase{method: http.MethodGet, path: "/favicon.ico", expectedStatusCode: http.StatusOK, expectedBody: "icon", expectedCacheControl: cacheRevalidate},
+		handlerTestCase{method: http.MethodGet, path: "/static/js/main.1a2b.js", expectedStatusCode: http.StatusOK, expectedBody: "console.log('charon')", expectedCacheControl: cacheForever, expectedContentType: "javascript"},
+		handlerTestCase{method: http.MethodGet, path: "/static/css/main.3c4d.css", expectedStatusCode: http.StatusOK, expectedBody: "body {}", expectedCacheControl: cacheForever, expectedContentType: "text/css; charset=utf-8"},
+		handlerTestCase{method: http.MethodHead, path: "/static/js/main.1a2b.js", expectedStatusCode: http.StatusOK, expectedBody: "", expectedCacheControl: cacheForever},
+		handlerTestCase{method: http.MethodGet, path: "/static/js/main.0000.js", expectedStatusCode: http.StatusNotFound},
+		handlerTestCase{method: http.MethodGet, path: "/api/exam/", expectedStatusCode: http.StatusNotFound},
+		handlerTestCase{method: http.MethodGet, path: "/api", expectedStatusCode: http.StatusNotFound},
+	}
+
+	var handler http.Handler = Handler(createAssets())
+	for i, testCase := range testCases {
+		t.Logf("Test Handler testcase: %d", i)
+		req := httptest.NewRequest(testCase.method, testCase.path, nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, testCase.expectedStatusCode, rec.Code)
+		if testCase.expectedStatusCode == http.StatusOK {
+			assert.Equal(t, testCase.expectedBody, rec.Body.String())
+			assert.Equal(t, testCase.expectedCacheControl, rec.Header().Get("Cache-Control"))
+			assert.Equal(t, "Sun, 01 Mar 2020 00:00:00 GMT", rec.Header().Get("Last-Modified"))
+		}
+		if testCase.expectedContentType != "" {
+			assert.Contains(t, rec.Header().Get("Content-Type"), testCase.expectedContentType)
+		}
+	}
+
+	// the unchanged file is revalidated without sending the content
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-Modified-Since", "Sun, 01 Mar 2020 00:00:00 GMT")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+}
+
+func TestMount(t *testing.T) {
+	type mountTestCase struct {
+		method             string
+		path               string
+		expectedStatusCode int
+		expectedBody       string
+	}
+	testCases := []mountTestCase{
+		mountTestCase{method: http.MethodGet, path: "/api/exam/", expectedStatusCode: http.StatusOK, expectedBody: "[]"},
+		mountTestCase{method: http.MethodPost, path: "/api/exam/", expectedStatusCode: http.StatusMethodNotAllowed},
+		mountTestCase{method: http.MethodGet, path: "/api/unknown/", expectedStatusCode: http.StatusNotFound},
+		mountTestCase{method: http.MethodGet, path: "/exam/", expectedStatusCode: http.StatusOK, expectedBody: "<html>charon</html>"},
+		mountTestCase{method: http.MethodGet, path: "/static/js/main.1a2b.js", expectedStatusCode: http.StatusOK, expectedBody: "console.log('charon')"},
+		mountTestCase{method: http.MethodPost, path: "/exam/", expectedStatusCode: http.StatusMethodNotAllowed},
+	}
+
+	router := mux.NewRouter()
+	api := router.PathPrefix(APIPrefix).Subrouter()
+	api.HandleFunc("/exam/", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("[]")) }).Methods(http.MethodGet)
+	Mount(router, createAssets())
+	for i, testCase := range testCases {
+		t.Logf("Test Mount testcase: %d", i)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(testCase.method, testCase.path, nil))
+		assert.Equal(t, testCase.expectedStatusCode, rec.Code)
+		if testCase.expectedBody != "" {
+			assert.Equal(t, testCase.expectedBody, rec.Body.String())
+		}
+	}
+
+	// without the embedded frontend, only the API is served
+	router = mux.NewRouter()
+	Mount(router, nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/exam/", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
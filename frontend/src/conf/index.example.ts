@@ -5,6 +5,8 @@ import { AppConfig } from './types';
 
 export default {
   appName: process.env['APP_NAME'],
-  charonApiUrl: process.env['CHARON_API_URL'], // don't use trailing slash
+  // don't use trailing slash, the API is under /api of the server, e.g. http://localhost:8200/api,
+  // or just /api if the frontend is embedded in the server
+  charonApiUrl: process.env['CHARON_API_URL'],
   tinyMCEApiKey: 'lugruemgf9a7cb78atgaikhkaish8da7itsdaiusdausdhhy',
 } as AppConfig;
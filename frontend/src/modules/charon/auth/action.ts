@@ -61,28 +61,3 @@ export function createUser(user: User): AppThunk<Promise<void>> {
       });
   };
 };
-
-export function lockUser(username: string): AppThunk<Promise<void>> {
-  return async function (_dispatch, _getState, { charonAuthApi }) {
-    return charonAuthApi.lockUser(username)
-      .then(() => {
-        return;
-      })
-      .catch((err: AxiosError) => {
-        throw new CharonFormError(err);
-      });
-  };
-};
-
-export function unlockUser(username: string): AppThunk<Promise<void>> {
-  return async function (_dispatch, _getState, { charonAuthApi }) {
-    return charonAuthApi.unlockUser(username)
-      .then(() => {
-        return;
-      })
-      .catch((err: AxiosError) => {
-        throw new CharonFormError(err);
-      });
-  };
-};
-
@@ -22,8 +22,6 @@ export interface CharonAuthApi {
 
   getUsers: () => Promise<AxiosResponse<User[]>>;
   createUser: (user: User) => Promise<AxiosResponse<void>>;
-  lockUser: (username: string) => Promise<AxiosResponse<void>>;
-  unlockUser: (username: string) => Promise<AxiosResponse<void>>;
 }
 
 export default {
@@ -32,6 +30,4 @@ export default {
 
   getUsers: () => http.get(`/auth/user/`),
   createUser: (user: User) => http.post(`/auth/user/`, user),
-  lockUser: (username: string) => http.post(`/auth/user/${username}/lock/`),
-  unlockUser: (username: string) => http.post(`/auth/user/${username}/unlock/`),
 }
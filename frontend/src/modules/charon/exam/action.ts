@@ -158,6 +158,7 @@ export function getParticipationStatus(eventSlug: string): AppThunk<Promise<void
         const participationStatus: ParticipationStatus[] = res.data;
         participationStatus.forEach((status) => {
           status.loginAt = new Date(status.loginAt);
+          status.lastSeenAt = !!status.lastSeenAt ? new Date(status.lastSeenAt) : null;
         });
         dispatch(putParticipationStatus(eventSlug, participationStatus));
       })
@@ -47,7 +47,7 @@ export interface ParticipationStatus {
   ipAddress: string;
   loginAt: Date;
   sessionId: number;
-  userSessionLocked: boolean;
+  lastSeenAt: Date | null;
 };
 
 export interface CharonExamApi {
@@ -21,8 +21,6 @@ interface ParticipationStatusPageProps extends RouteComponentProps<{ eventSlug:
 };
 
 interface ConnectedParticipationStatusPageProps extends ParticipationStatusPageProps {
-  lockUser: (username: string) => Promise<void>;
-  unlockUser: (username: string) => Promise<void>;
   getParticipationStatus: (eventSlug: string) => Promise<void>;
   deleteParticipationStatus: (eventSlug: string, sessionId: number) => Promise<void>;
   getUsers: () => Promise<void>,
@@ -33,8 +31,6 @@ interface ConnectedParticipationStatusPageProps extends ParticipationStatusPageP
 
 const ParticipationStatusPage = (props: ConnectedParticipationStatusPageProps) => {
   const {
-    lockUser,
-    unlockUser,
     getParticipationStatus,
     deleteParticipationStatus,
     getUsers,
@@ -46,7 +42,7 @@ const ParticipationStatusPage = (props: ConnectedParticipationStatusPageProps) =
 
   React.useEffect(() => { if (!participationStatus) getParticipationStatus(eventSlug); }, [getParticipationStatus, eventSlug, participationStatus]);
   React.useEffect(() => { if (!users) getUsers(); }, [getUsers, users]);
-  const [isLocking, setIsLocking] = React.useState(false);
+  const [isDeleting, setIsDeleting] = React.useState(false);
 
   if (!participationStatus) {
     return <ParticipationStatusLoadingPage />;
@@ -56,29 +52,11 @@ const ParticipationStatusPage = (props: ConnectedParticipationStatusPageProps) =
     return <Redirect to={ROUTE_LOGIN} />;
   }
   const usersByUsername = keyBy(users, 'username');
-  const handleLockUser = (username: string) => {
-    return () => {
-      setIsLocking(true);
-      lockUser(username).then(() => {
-        setIsLocking(false);
-        getParticipationStatus(eventSlug);
-      });
-    };
-  }
-  const handleUnlockUser = (username: string) => {
-    return () => {
-      setIsLocking(true);
-      unlockUser(username).then(() => {
-        setIsLocking(false);
-        getParticipationStatus(eventSlug);
-      });
-    };
-  };
   const handleDeleteSession = (sessionId: number) => {
     return () => {
-      setIsLocking(true);
+      setIsDeleting(true);
       deleteParticipationStatus(eventSlug, sessionId).then(() => {
-        setIsLocking(false);
+        setIsDeleting(false);
         getParticipationStatus(eventSlug);
       });
     };
@@ -93,22 +71,16 @@ const ParticipationStatusPage = (props: ConnectedParticipationStatusPageProps) =
           <div className="user">Nama</div>
           <div className="ip-address">Alamat IP</div>
           <div className="login-at">Waktu Login</div>
-          <div className="lock-button">Kunci Sesi</div>
+          <div className="last-seen-at">Aktivitas Terakhir</div>
           <div className="delete"></div>
         </div>
-        {participationStatus?.map(({ userUsername, ipAddress, loginAt, sessionId, userSessionLocked }) => (
+        {participationStatus?.map(({ userUsername, ipAddress, loginAt, sessionId, lastSeenAt }) => (
           <div className="participation-status" key={sessionId}>
             <div className="user">{!!usersByUsername[userUsername] ? usersByUsername[userUsername].name : <span className="skeleton">Nama peserta</span>}</div>
             <div className="ip-address">{ipAddress}</div>
             <div className="login-at">{!!loginAt ? loginAt.toLocaleTimeString() : '-'}</div>
-            <div className="lock-button">
-              {isLocking
-                ? <Button><LoadingCircle /></Button>
-                : userSessionLocked
-                  ? <Button disabled={isLocking} onClick={handleUnlockUser(userUsername)}><i className="fas fa-lock-open"/><span>Unlock</span></Button>
-                  : <Button disabled={isLocking} onClick={handleLockUser(userUsername)}><i className="fas fa-lock"/><span>Lock</span></Button>}
-            </div>
-            <div className="delete"><Button className="delete-button" disabled={isLocking} onClick={handleDeleteSession(sessionId)}><i className="fas fa-trash"/></Button></div>
+            <div className="last-seen-at">{!!lastSeenAt ? lastSeenAt.toLocaleTimeString() : '-'}</div>
+            <div className="delete"><Button className="delete-button" disabled={isDeleting} onClick={handleDeleteSession(sessionId)}>{isDeleting ? <LoadingCircle /> : <i className="fas fa-trash"/>}</Button></div>
           </div>
         ))}
       </div>
@@ -123,8 +95,6 @@ const mapStateToProps = (state: AppState, props: RouteComponentProps<{ eventSlug
 });
 
 const mapDispatchToProps = {
-  lockUser: charonAuthActions.lockUser,
-  unlockUser: charonAuthActions.unlockUser,
   getParticipationStatus: charonExamActions.getParticipationStatus,
   deleteParticipationStatus: charonExamActions.deleteParticipationStatus,
   getUsers: charonAuthActions.getUsers,